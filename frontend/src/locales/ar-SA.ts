@@ -1055,6 +1055,15 @@ export default {
       stop: 'إيقاف',
       copy: 'نسخ',
       copyFailed: 'فشل النسخ',
+      rateUp: 'إجابة جيدة',
+      rateDown: 'إجابة سيئة',
+      rateFailed: 'فشل حفظ التقييم',
+      contextReset: 'مسح السياق (مع الاحتفاظ بالسجل)',
+      contextResetUndo: 'استعادة السياق السابق',
+      contextCleared: 'تم مسح السياق، تبقى الرسائل السابقة ظاهرة لكنها لم تعد تُرسل إلى النموذج',
+      contextRestored: 'تمت استعادة السياق السابق',
+      contextResetFailed: 'فشل تحديث السياق',
+      contextResetDivider: 'تم مسح السياق — لا يرى النموذج الرسائل أعلاه',
       edit: 'تعديل',
       resend: 'إعادة الإرسال',
       error: 'حدث خطأ',
@@ -1066,6 +1075,8 @@ export default {
       thinkingInProgress: 'جار التفكير...',
       thinkingOn: 'تم تفعيل وضع التفكير',
       thinkingOff: 'تم تعطيل وضع التفكير',
+      showThinkingOn: 'عرض خطوات التفكير',
+      showThinkingOff: 'إخفاء خطوات التفكير (مع الاستمرار في حفظها)',
       toolCalling: 'جاري التنفيذ',
       toolCompleted: 'مكتمل',
       toolError: 'فشل',
@@ -1463,6 +1474,8 @@ export default {
       menu: {
         rename: 'إعادة تسمية',
         relearn: 'إعادة التعلم',
+        retryEmbedding: 'إعادة محاولة التضمين',
+        cancelProcessing: 'إيقاف التعلم',
         delete: 'حذف',
       },
       upload: {
@@ -1470,6 +1483,7 @@ export default {
         failed: 'فشل رفع الوثائق',
         count: 'تم رفع {count} وثيقة',
         uploading: 'جار الرفع {done}/{total}',
+        reused: 'أعاد {count} من المستندات استخدام نتائج تعلم موجودة',
       },
       drop: {
         hint: 'اسحب الملفات هنا للرفع',
@@ -1489,6 +1503,21 @@ export default {
         successBatch: 'بدأت إعادة التعلم لـ {count} مستندات',
         failed: 'فشل إعادة التعلم',
       },
+      crossLibraryDuplicate: {
+        title: 'تم تعلمه بالفعل في قاعدة معرفة أخرى',
+        desc: 'تم تعلم “{name}” بالفعل في “{library}”. هل تريد إعادة استخدام المحتوى المحلل والمتجهات بدلاً من تعلمه مرة أخرى؟',
+        descBatch: 'تم تعلم {count} من الملفات بالفعل في قواعد معرفة أخرى. هل تريد إعادة استخدام المحتوى المحلل والمتجهات بدلاً من تعلمها مرة أخرى؟',
+        reuse: 'إعادة الاستخدام',
+        relearn: 'التعلم مجددًا',
+      },
+      retryEmbedding: {
+        success: 'بدأت إعادة محاولة التضمين',
+        failed: 'فشلت إعادة محاولة التضمين',
+      },
+      cancelProcessing: {
+        success: 'تم إيقاف التعلم',
+        failed: 'فشل إيقاف التعلم',
+      },
       delete: {
         title: 'تأكيد الحذف',
         desc: 'هل أنت متأكد من حذف ',
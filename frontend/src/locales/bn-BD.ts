@@ -1056,6 +1056,16 @@ export default {
       stop: 'থামান',
       copy: 'কপি',
       copyFailed: 'কপি ব্যর্থ',
+      rateUp: 'ভালো উত্তর',
+      rateDown: 'খারাপ উত্তর',
+      rateFailed: 'রেটিং সংরক্ষণ করতে ব্যর্থ',
+      contextReset: 'কনটেক্সট মুছুন (ইতিহাস রাখুন)',
+      contextResetUndo: 'আগের কনটেক্সট ফিরিয়ে আনুন',
+      contextCleared:
+        'কনটেক্সট মুছে ফেলা হয়েছে, আগের বার্তাগুলো দেখা যাবে কিন্তু আর মডেলে পাঠানো হবে না',
+      contextRestored: 'আগের কনটেক্সট ফিরিয়ে আনা হয়েছে',
+      contextResetFailed: 'কনটেক্সট আপডেট করতে ব্যর্থ',
+      contextResetDivider: 'কনটেক্সট মুছে ফেলা হয়েছে — মডেল উপরের বার্তাগুলো দেখে না',
       edit: 'সম্পাদনা',
       resend: 'পুনরায় পাঠান',
       error: 'একটি ত্রুটি হয়েছে',
@@ -1067,6 +1077,8 @@ export default {
       thinkingInProgress: 'চিন্তা করছি...',
       thinkingOn: 'চিন্তা মোড চালু',
       thinkingOff: 'চিন্তা মোড বন্ধ',
+      showThinkingOn: 'চিন্তার ধাপ দেখানো হচ্ছে',
+      showThinkingOff: 'চিন্তার ধাপ লুকানো (তবুও সংরক্ষিত)',
       toolCalling: 'চলছে',
       toolCompleted: 'সম্পন্ন',
       toolError: 'ব্যর্থ',
@@ -1463,6 +1475,8 @@ export default {
       menu: {
         rename: 'নাম পরিবর্তন',
         relearn: 'পুনরায় শিখুন',
+        retryEmbedding: 'এমবেডিং পুনরায় চেষ্টা',
+        cancelProcessing: 'শেখা বন্ধ করুন',
         delete: 'মুছুন',
       },
       upload: {
@@ -1470,6 +1484,7 @@ export default {
         failed: 'ডকুমেন্ট আপলোড ব্যর্থ',
         count: '{count}টি ডকুমেন্ট আপলোড হয়েছে',
         uploading: 'আপলোড হচ্ছে {done}/{total}',
+        reused: '{count}টি ডকুমেন্ট বিদ্যমান শেখার ফলাফল পুনরায় ব্যবহার করেছে',
       },
       drop: {
         hint: 'আপলোড করতে ফাইল এখানে ড্র্যাগ করুন',
@@ -1489,6 +1504,21 @@ export default {
         successBatch: '{count}টি ডকুমেন্টের জন্য পুনরায় শেখা শুরু হয়েছে',
         failed: 'পুনরায় শেখা ব্যর্থ',
       },
+      crossLibraryDuplicate: {
+        title: 'অন্য নলেজ বেসে ইতিমধ্যে শেখা হয়েছে',
+        desc: '“{name}” ইতিমধ্যে “{library}”-এ শেখা হয়েছে। আবার শেখার বদলে এর পার্স করা কনটেন্ট ও ভেক্টর পুনরায় ব্যবহার করবেন?',
+        descBatch: '{count}টি ফাইল ইতিমধ্যে অন্য নলেজ বেসে শেখা হয়েছে। আবার শেখার বদলে এগুলোর পার্স করা কনটেন্ট ও ভেক্টর পুনরায় ব্যবহার করবেন?',
+        reuse: 'পুনরায় ব্যবহার',
+        relearn: 'আবার শিখুন',
+      },
+      retryEmbedding: {
+        success: 'এমবেডিং পুনরায় চেষ্টা শুরু হয়েছে',
+        failed: 'এমবেডিং পুনরায় চেষ্টা ব্যর্থ হয়েছে',
+      },
+      cancelProcessing: {
+        success: 'শেখা বন্ধ করা হয়েছে',
+        failed: 'শেখা বন্ধ করতে ব্যর্থ',
+      },
       delete: {
         title: 'মুছতে নিশ্চিত করুন',
         desc: '',
@@ -1053,6 +1053,16 @@ export default {
       stop: 'Stoppen',
       copy: 'Kopieren',
       copyFailed: 'Kopieren fehlgeschlagen',
+      rateUp: 'Gute Antwort',
+      rateDown: 'Schlechte Antwort',
+      rateFailed: 'Bewertung konnte nicht gespeichert werden',
+      contextReset: 'Kontext leeren (Verlauf behalten)',
+      contextResetUndo: 'Vorherigen Kontext wiederherstellen',
+      contextCleared:
+        'Kontext geleert – frühere Nachrichten bleiben sichtbar, werden aber nicht mehr an das Modell gesendet',
+      contextRestored: 'Vorheriger Kontext wiederhergestellt',
+      contextResetFailed: 'Kontext konnte nicht aktualisiert werden',
+      contextResetDivider: 'Kontext geleert – das Modell sieht die Nachrichten oben nicht',
       edit: 'Bearbeiten',
       resend: 'Erneut senden',
       error: 'Ein Fehler ist aufgetreten',
@@ -1064,6 +1074,8 @@ export default {
       thinkingInProgress: 'Denke nach...',
       thinkingOn: 'Denkmodus aktiviert',
       thinkingOff: 'Denkmodus deaktiviert',
+      showThinkingOn: 'Denkprozess wird angezeigt',
+      showThinkingOff: 'Denkprozess ausgeblendet (wird weiterhin gespeichert)',
       toolCalling: 'Läuft',
       toolCompleted: 'Abgeschlossen',
       toolError: 'Fehlgeschlagen',
@@ -1460,6 +1472,8 @@ export default {
       menu: {
         rename: 'Umbenennen',
         relearn: 'Neu lernen',
+        retryEmbedding: 'Vektorisierung wiederholen',
+        cancelProcessing: 'Lernen stoppen',
         delete: 'Löschen',
       },
       upload: {
@@ -1467,6 +1481,7 @@ export default {
         failed: 'Dokumente konnten nicht hochgeladen werden',
         count: '{count} Dokument(e) hochgeladen',
         uploading: 'Hochladen {done}/{total}',
+        reused: '{count} Dokumente haben vorhandene Lernergebnisse wiederverwendet',
       },
       drop: {
         hint: 'Dateien hierher ziehen zum Hochladen',
@@ -1486,6 +1501,21 @@ export default {
         successBatch: 'Neu Lernen für {count} Dokumente gestartet',
         failed: 'Neu Lernen fehlgeschlagen',
       },
+      crossLibraryDuplicate: {
+        title: 'Bereits in einer anderen Wissensdatenbank gelernt',
+        desc: '„{name}“ wurde bereits in „{library}“ gelernt. Die analysierten Inhalte und Vektoren wiederverwenden, statt neu zu lernen?',
+        descBatch: '{count} Dateien wurden bereits in anderen Wissensdatenbanken gelernt. Die analysierten Inhalte und Vektoren wiederverwenden, statt neu zu lernen?',
+        reuse: 'Wiederverwenden',
+        relearn: 'Neu lernen',
+      },
+      retryEmbedding: {
+        success: 'Vektorisierung wird wiederholt',
+        failed: 'Vektorisierung konnte nicht wiederholt werden',
+      },
+      cancelProcessing: {
+        success: 'Lernen gestoppt',
+        failed: 'Lernen konnte nicht gestoppt werden',
+      },
       delete: {
         title: 'Löschen bestätigen',
         desc: 'Dokument "{name}" löschen? Diese Aktion kann nicht rückgängig gemacht werden.',
@@ -1146,6 +1146,16 @@ export default {
       stop: 'Stop',
       copy: 'Copy',
       copyFailed: 'Failed to copy',
+      rateUp: 'Good answer',
+      rateDown: 'Bad answer',
+      rateFailed: 'Failed to save rating',
+      contextReset: 'Clear context (keep history)',
+      contextResetUndo: 'Restore previous context',
+      contextCleared:
+        'Context cleared — earlier messages stay visible but are no longer sent to the model',
+      contextRestored: 'Previous context restored',
+      contextResetFailed: 'Failed to update context',
+      contextResetDivider: 'Context cleared — the model does not see messages above',
       edit: 'Edit',
       resend: 'Resend',
       error: 'An error occurred',
@@ -1157,6 +1167,8 @@ export default {
       thinkingInProgress: 'Thinking...',
       thinkingOn: 'Thinking mode enabled',
       thinkingOff: 'Thinking mode disabled',
+      showThinkingOn: 'Reasoning shown',
+      showThinkingOff: 'Reasoning hidden (still captured)',
       toolCalling: 'Calling',
       toolCompleted: 'Completed',
       toolError: 'Failed',
@@ -1553,6 +1565,8 @@ export default {
       menu: {
         rename: 'Rename',
         relearn: 'Relearn',
+        retryEmbedding: 'Retry embedding',
+        cancelProcessing: 'Stop learning',
         delete: 'Delete',
       },
       upload: {
@@ -1560,6 +1574,7 @@ export default {
         failed: 'Failed to upload documents',
         count: '{count} documents uploaded',
         uploading: 'Uploading {done}/{total}',
+        reused: '{count} documents reused existing learning results',
       },
       drop: {
         hint: 'Drop files here to upload',
@@ -1579,6 +1594,21 @@ export default {
         successBatch: 'Relearning started for {count} documents',
         failed: 'Failed to relearn',
       },
+      crossLibraryDuplicate: {
+        title: 'Already learned in another knowledge base',
+        desc: '“{name}” has already been learned in “{library}”. Reuse its parsed content and vectors instead of learning it again?',
+        descBatch: '{count} files have already been learned in other knowledge bases. Reuse their parsed content and vectors instead of learning them again?',
+        reuse: 'Reuse',
+        relearn: 'Learn again',
+      },
+      retryEmbedding: {
+        success: 'Embedding retry started',
+        failed: 'Failed to retry embedding',
+      },
+      cancelProcessing: {
+        success: 'Learning stopped',
+        failed: 'Failed to stop learning',
+      },
       delete: {
         title: 'Confirm Delete',
         desc: 'Are you sure you want to delete ',
@@ -1053,6 +1053,16 @@ export default {
       stop: 'Detener',
       copy: 'Copiar',
       copyFailed: 'Error al copiar',
+      rateUp: 'Buena respuesta',
+      rateDown: 'Mala respuesta',
+      rateFailed: 'No se pudo guardar la valoración',
+      contextReset: 'Borrar contexto (mantener historial)',
+      contextResetUndo: 'Restaurar contexto anterior',
+      contextCleared:
+        'Contexto borrado: los mensajes anteriores siguen visibles pero ya no se envían al modelo',
+      contextRestored: 'Contexto anterior restaurado',
+      contextResetFailed: 'No se pudo actualizar el contexto',
+      contextResetDivider: 'Contexto borrado: el modelo no ve los mensajes de arriba',
       edit: 'Editar',
       resend: 'Reenviar',
       error: 'Ocurrió un error',
@@ -1064,6 +1074,8 @@ export default {
       thinkingInProgress: 'Pensando...',
       thinkingOn: 'Modo de pensamiento habilitado',
       thinkingOff: 'Modo de pensamiento deshabilitado',
+      showThinkingOn: 'Razonamiento visible',
+      showThinkingOff: 'Razonamiento oculto (se sigue guardando)',
       toolCalling: 'Ejecutando',
       toolCompleted: 'Completado',
       toolError: 'Fallido',
@@ -1461,6 +1473,8 @@ export default {
       menu: {
         rename: 'Renombrar',
         relearn: 'Re-aprender',
+        retryEmbedding: 'Reintentar vectorización',
+        cancelProcessing: 'Detener aprendizaje',
         delete: 'Eliminar',
       },
       upload: {
@@ -1468,6 +1482,7 @@ export default {
         failed: 'Error al subir documentos',
         count: '{count} documento(s) subido(s)',
         uploading: 'Subiendo {done}/{total}',
+        reused: '{count} documentos reutilizaron resultados de aprendizaje existentes',
       },
       drop: {
         hint: 'Arrastra archivos aquí para subir',
@@ -1487,6 +1502,21 @@ export default {
         successBatch: 'Reaprendizaje iniciado para {count} documentos',
         failed: 'Error al re-aprender',
       },
+      crossLibraryDuplicate: {
+        title: 'Ya aprendido en otra base de conocimiento',
+        desc: '“{name}” ya se aprendió en “{library}”. ¿Reutilizar su contenido analizado y sus vectores en lugar de aprenderlo de nuevo?',
+        descBatch: '{count} archivos ya se aprendieron en otras bases de conocimiento. ¿Reutilizar su contenido analizado y sus vectores en lugar de aprenderlos de nuevo?',
+        reuse: 'Reutilizar',
+        relearn: 'Aprender de nuevo',
+      },
+      retryEmbedding: {
+        success: 'Reintento de vectorización iniciado',
+        failed: 'No se pudo reintentar la vectorización',
+      },
+      cancelProcessing: {
+        success: 'Aprendizaje detenido',
+        failed: 'No se pudo detener el aprendizaje',
+      },
       delete: {
         title: 'Confirmar eliminación',
         desc: '¿Estás seguro de eliminar ',
@@ -1161,6 +1161,16 @@ export default {
       stop: 'Arrêter',
       copy: 'Copier',
       copyFailed: 'Échec de la copie',
+      rateUp: 'Bonne réponse',
+      rateDown: 'Mauvaise réponse',
+      rateFailed: 'Échec de l’enregistrement de la note',
+      contextReset: 'Effacer le contexte (garder l’historique)',
+      contextResetUndo: 'Restaurer le contexte précédent',
+      contextCleared:
+        'Contexte effacé : les messages précédents restent visibles mais ne sont plus envoyés au modèle',
+      contextRestored: 'Contexte précédent restauré',
+      contextResetFailed: 'Échec de la mise à jour du contexte',
+      contextResetDivider: 'Contexte effacé : le modèle ne voit pas les messages ci-dessus',
       edit: 'Modifier',
       resend: 'Renvoyer',
       error: 'Une erreur s',
@@ -1172,6 +1182,8 @@ export default {
       thinkingInProgress: 'Réflexion...',
       thinkingOn: 'Mode de réflexion activé',
       thinkingOff: 'Mode de réflexion désactivé',
+      showThinkingOn: 'Raisonnement affiché',
+      showThinkingOff: 'Raisonnement masqué (toujours enregistré)',
       toolCalling: 'En cours',
       toolCompleted: 'Terminé',
       toolError: 'Échoué',
@@ -1573,6 +1585,8 @@ export default {
       menu: {
         rename: 'Renommer',
         relearn: 'Réapprendre',
+        retryEmbedding: 'Relancer la vectorisation',
+        cancelProcessing: 'Arrêter l’apprentissage',
         delete: 'Supprimer',
       },
       upload: {
@@ -1580,6 +1594,7 @@ export default {
         failed: 'Échec du téléchargement des documents',
         count: '{count} document(s) téléchargé(s)',
         uploading: 'Téléchargement {done}/{total}',
+        reused: '{count} documents ont réutilisé des résultats d’apprentissage existants',
       },
       drop: {
         hint: 'Déposez les fichiers ici pour télécharger',
@@ -1599,6 +1614,21 @@ export default {
         successBatch: 'Réapprentissage démarré pour {count} documents',
         failed: 'Échec du réapprentissage',
       },
+      crossLibraryDuplicate: {
+        title: 'Déjà appris dans une autre base de connaissances',
+        desc: '« {name} » a déjà été appris dans « {library} ». Réutiliser son contenu analysé et ses vecteurs au lieu de l’apprendre à nouveau ?',
+        descBatch: '{count} fichiers ont déjà été appris dans d’autres bases de connaissances. Réutiliser leur contenu analysé et leurs vecteurs au lieu de les apprendre à nouveau ?',
+        reuse: 'Réutiliser',
+        relearn: 'Réapprendre',
+      },
+      retryEmbedding: {
+        success: 'Vectorisation relancée',
+        failed: 'Échec de la relance de la vectorisation',
+      },
+      cancelProcessing: {
+        success: 'Apprentissage arrêté',
+        failed: 'Échec de l’arrêt de l’apprentissage',
+      },
       delete: {
         title: 'Confirmer la suppression',
         desc: 'Êtes-vous sûr de vouloir supprimer ',
@@ -1073,6 +1073,16 @@ export default {
       stop: 'रोकें',
       copy: 'कॉपी करें',
       copyFailed: 'कॉपी करने में विफल',
+      rateUp: 'अच्छा उत्तर',
+      rateDown: 'खराब उत्तर',
+      rateFailed: 'रेटिंग सहेजने में विफल',
+      contextReset: 'संदर्भ साफ़ करें (इतिहास रखें)',
+      contextResetUndo: 'पिछला संदर्भ पुनर्स्थापित करें',
+      contextCleared:
+        'संदर्भ साफ़ किया गया, पिछले संदेश दिखते रहेंगे लेकिन अब मॉडल को नहीं भेजे जाएंगे',
+      contextRestored: 'पिछला संदर्भ पुनर्स्थापित किया गया',
+      contextResetFailed: 'संदर्भ अपडेट करने में विफल',
+      contextResetDivider: 'संदर्भ साफ़ किया गया — मॉडल ऊपर के संदेश नहीं देखता',
       edit: 'संपादित करें',
       resend: 'पुनः भेजें',
       error: 'एक त्रुटि हुई',
@@ -1084,6 +1094,8 @@ export default {
       thinkingInProgress: 'सोच रहा है...',
       thinkingOn: 'सोच मोड सक्षम',
       thinkingOff: 'सोच मोड अक्षम',
+      showThinkingOn: 'तर्क दिखाया जा रहा है',
+      showThinkingOff: 'तर्क छिपा है (फिर भी सहेजा जाता है)',
       toolCalling: 'कॉल कर रहा है',
       toolCompleted: 'पूर्ण',
       toolError: 'विफल',
@@ -1480,6 +1492,8 @@ export default {
       menu: {
         rename: 'नाम बदलें',
         relearn: 'फिर से सीखें',
+        retryEmbedding: 'एम्बेडिंग पुनः प्रयास करें',
+        cancelProcessing: 'सीखना रोकें',
         delete: 'हटाएं',
       },
       upload: {
@@ -1487,6 +1501,7 @@ export default {
         failed: 'डॉक्यूमेंट अपलोड करने में विफल',
         count: '{count} डॉक्यूमेंट अपलोड हुए',
         uploading: 'अपलोड हो रहा है {done}/{total}',
+        reused: '{count} दस्तावेज़ों ने मौजूदा लर्निंग परिणामों का पुनः उपयोग किया',
       },
       drop: {
         hint: 'अपलोड करने के लिए फाइलें यहां छोड़ें',
@@ -1506,6 +1521,21 @@ export default {
         successBatch: '{count} दस्तावेज़ों के लिए फिर से सीखना शुरू हुआ',
         failed: 'फिर से सीखने में विफल',
       },
+      crossLibraryDuplicate: {
+        title: 'किसी अन्य नॉलेज बेस में पहले ही सीखा जा चुका है',
+        desc: '“{name}” को “{library}” में पहले ही सीखा जा चुका है। फिर से सीखने के बजाय इसकी पार्स की गई सामग्री और वेक्टर का पुनः उपयोग करें?',
+        descBatch: '{count} फ़ाइलें अन्य नॉलेज बेस में पहले ही सीखी जा चुकी हैं। फिर से सीखने के बजाय उनकी पार्स की गई सामग्री और वेक्टर का पुनः उपयोग करें?',
+        reuse: 'पुनः उपयोग करें',
+        relearn: 'फिर से सीखें',
+      },
+      retryEmbedding: {
+        success: 'एम्बेडिंग पुनः प्रयास शुरू हुआ',
+        failed: 'एम्बेडिंग पुनः प्रयास विफल रहा',
+      },
+      cancelProcessing: {
+        success: 'सीखना रोक दिया गया',
+        failed: 'सीखना रोकने में विफल',
+      },
       delete: {
         title: 'हटाने की पुष्टि करें',
         desc: 'क्या आप ',
@@ -1054,6 +1054,16 @@ export default {
       stop: 'Ferma',
       copy: 'Copia',
       copyFailed: 'Copia fallita',
+      rateUp: 'Risposta buona',
+      rateDown: 'Risposta scarsa',
+      rateFailed: 'Impossibile salvare la valutazione',
+      contextReset: 'Cancella contesto (mantieni cronologia)',
+      contextResetUndo: 'Ripristina contesto precedente',
+      contextCleared:
+        'Contesto cancellato: i messaggi precedenti restano visibili ma non vengono più inviati al modello',
+      contextRestored: 'Contesto precedente ripristinato',
+      contextResetFailed: 'Impossibile aggiornare il contesto',
+      contextResetDivider: 'Contesto cancellato: il modello non vede i messaggi sopra',
       edit: 'Modifica',
       resend: 'Reinvia',
       error: 'Si è verificato un errore durante la generazione',
@@ -1065,6 +1075,8 @@ export default {
       thinkingInProgress: 'Riflettendo...',
       thinkingOn: 'Modalità ragionamento attivata',
       thinkingOff: 'Modalità ragionamento disattivata',
+      showThinkingOn: 'Ragionamento visibile',
+      showThinkingOff: 'Ragionamento nascosto (viene comunque salvato)',
       toolCalling: 'Esecuzione',
       toolCompleted: 'Completato',
       toolError: 'Esecuzione fallita',
@@ -1462,6 +1474,8 @@ export default {
       menu: {
         rename: 'Rinomina',
         relearn: 'Riapprendi',
+        retryEmbedding: 'Riprova vettorizzazione',
+        cancelProcessing: 'Interrompi apprendimento',
         delete: 'Elimina',
       },
       upload: {
@@ -1469,6 +1483,7 @@ export default {
         failed: 'Caricamento documento fallito',
         count: '{count} documento(i) caricato(i)',
         uploading: 'Caricamento {done}/{total}',
+        reused: '{count} documenti hanno riutilizzato risultati di apprendimento esistenti',
       },
       drop: {
         hint: 'Trascina file qui per caricare',
@@ -1488,6 +1503,21 @@ export default {
         successBatch: 'Riapprendimento avviato per {count} documenti',
         failed: 'Riapprendimento fallito',
       },
+      crossLibraryDuplicate: {
+        title: 'Già appreso in un’altra base di conoscenza',
+        desc: '“{name}” è già stato appreso in “{library}”. Riutilizzare il contenuto analizzato e i vettori invece di apprenderlo di nuovo?',
+        descBatch: '{count} file sono già stati appresi in altre basi di conoscenza. Riutilizzare il contenuto analizzato e i vettori invece di apprenderli di nuovo?',
+        reuse: 'Riutilizza',
+        relearn: 'Apprendi di nuovo',
+      },
+      retryEmbedding: {
+        success: 'Nuovo tentativo di vettorizzazione avviato',
+        failed: 'Impossibile ritentare la vettorizzazione',
+      },
+      cancelProcessing: {
+        success: 'Apprendimento interrotto',
+        failed: 'Impossibile interrompere l’apprendimento',
+      },
       delete: {
         title: 'Conferma eliminazione',
         desc: 'Sei sicuro di voler eliminare il documento ',
@@ -1055,6 +1055,15 @@ export default {
       stop: '停止',
       copy: 'コピー',
       copyFailed: 'コピーに失敗しました',
+      rateUp: '良い回答',
+      rateDown: '良くない回答',
+      rateFailed: '評価の保存に失敗しました',
+      contextReset: 'コンテキストをクリア（履歴は保持）',
+      contextResetUndo: '以前のコンテキストに戻す',
+      contextCleared: 'コンテキストをクリアしました。以前のメッセージは表示されたままですが、モデルには送信されません',
+      contextRestored: '以前のコンテキストに戻しました',
+      contextResetFailed: 'コンテキストの更新に失敗しました',
+      contextResetDivider: 'コンテキストをクリアしました — モデルは上のメッセージを参照しません',
       edit: '編集',
       resend: '再送信',
       error: 'エラーが発生しました',
@@ -1066,6 +1075,8 @@ export default {
       thinkingInProgress: '考え中...',
       thinkingOn: '思考モードを有効化しました',
       thinkingOff: '思考モードを無効化しました',
+      showThinkingOn: '思考過程を表示',
+      showThinkingOff: '思考過程を非表示（記録は継続）',
       toolCalling: '実行中',
       toolCompleted: '完了',
       toolError: '失敗',
@@ -1462,6 +1473,8 @@ export default {
       menu: {
         rename: '名前を変更',
         relearn: '再学習',
+        retryEmbedding: 'ベクトル化を再試行',
+        cancelProcessing: '学習を停止',
         delete: '削除',
       },
       upload: {
@@ -1469,6 +1482,7 @@ export default {
         failed: 'ドキュメントのアップロードに失敗しました',
         count: '{count} ドキュメントがアップロードされました',
         uploading: '{done}/{total} 件をアップロード中',
+        reused: '{count} 件のドキュメントで既存の学習結果を再利用しました',
       },
       drop: {
         hint: 'ここにファイルをドロップしてアップロード',
@@ -1488,6 +1502,21 @@ export default {
         successBatch: '{count} 件のドキュメントの再学習を開始しました',
         failed: '再学習に失敗しました',
       },
+      crossLibraryDuplicate: {
+        title: '他のナレッジベースで学習済みです',
+        desc: '「{name}」はナレッジベース「{library}」で学習済みです。再学習せずに、その分割内容とベクトルを再利用しますか？',
+        descBatch: '{count} 件のファイルは他のナレッジベースで学習済みです。再学習せずに、その分割内容とベクトルを再利用しますか？',
+        reuse: '再利用',
+        relearn: '再学習',
+      },
+      retryEmbedding: {
+        success: 'ベクトル化の再試行を開始しました',
+        failed: 'ベクトル化の再試行に失敗しました',
+      },
+      cancelProcessing: {
+        success: '学習を停止しました',
+        failed: '学習の停止に失敗しました',
+      },
       delete: {
         title: '削除の確認',
         desc: '削除してもよろしいですか ',
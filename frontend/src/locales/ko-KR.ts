@@ -1098,6 +1098,15 @@ export default {
       stop: '중지',
       copy: '복사',
       copyFailed: '복사 실패',
+      rateUp: '좋은 답변',
+      rateDown: '좋지 않은 답변',
+      rateFailed: '평가 저장에 실패했습니다',
+      contextReset: '컨텍스트 지우기(기록 유지)',
+      contextResetUndo: '이전 컨텍스트 복원',
+      contextCleared: '컨텍스트를 지웠습니다. 이전 메시지는 표시되지만 더 이상 모델에 전송되지 않습니다',
+      contextRestored: '이전 컨텍스트를 복원했습니다',
+      contextResetFailed: '컨텍스트 업데이트에 실패했습니다',
+      contextResetDivider: '컨텍스트 지움 — 모델은 위의 메시지를 보지 않습니다',
       edit: '편집',
       resend: '재전송',
       error: '오류가 발생했습니다',
@@ -1109,6 +1118,8 @@ export default {
       thinkingInProgress: '생각 중...',
       thinkingOn: '생각 모드 활성화됨',
       thinkingOff: '생각 모드 비활성화됨',
+      showThinkingOn: '추론 과정 표시',
+      showThinkingOff: '추론 과정 숨김 (기록은 유지)',
       toolCalling: '실행 중',
       toolCompleted: '완료',
       toolError: '실패',
@@ -1540,6 +1551,8 @@ export default {
       menu: {
         rename: '이름 바꾸기',
         relearn: '다시 학습',
+        retryEmbedding: '임베딩 재시도',
+        cancelProcessing: '학습 중지',
         delete: '삭제',
       },
       upload: {
@@ -1547,6 +1560,7 @@ export default {
         failed: '문서 업로드 실패',
         count: '문서 {count}개 업로드됨',
         uploading: '업로드 중 {done}/{total}',
+        reused: '{count}개 문서가 기존 학습 결과를 재사용했습니다',
       },
       drop: {
         hint: '여기에 파일을 드롭해 업로드하세요',
@@ -1566,6 +1580,21 @@ export default {
         successBatch: '{count}개 문서 다시 학습을 시작했습니다',
         failed: '다시 학습하지 못했습니다',
       },
+      crossLibraryDuplicate: {
+        title: '다른 지식 베이스에서 이미 학습됨',
+        desc: '“{name}”은(는) 지식 베이스 “{library}”에서 이미 학습되었습니다. 다시 학습하지 않고 분할 내용과 벡터를 재사용할까요?',
+        descBatch: '{count}개 파일이 다른 지식 베이스에서 이미 학습되었습니다. 다시 학습하지 않고 분할 내용과 벡터를 재사용할까요?',
+        reuse: '재사용',
+        relearn: '다시 학습',
+      },
+      retryEmbedding: {
+        success: '임베딩 재시도를 시작했습니다',
+        failed: '임베딩 재시도에 실패했습니다',
+      },
+      cancelProcessing: {
+        success: '학습을 중지했습니다',
+        failed: '학습 중지 실패',
+      },
       delete: {
         title: '삭제 확인',
         desc: '이 문서를 삭제하시겠습니까? 이 작업은 취소할 수 없습니다.',
@@ -1053,6 +1053,16 @@ export default {
       stop: 'Parar',
       copy: 'Copiar',
       copyFailed: 'Falha ao copiar',
+      rateUp: 'Boa resposta',
+      rateDown: 'Resposta ruim',
+      rateFailed: 'Falha ao salvar a avaliação',
+      contextReset: 'Limpar contexto (manter histórico)',
+      contextResetUndo: 'Restaurar contexto anterior',
+      contextCleared:
+        'Contexto limpo: as mensagens anteriores continuam visíveis, mas não são mais enviadas ao modelo',
+      contextRestored: 'Contexto anterior restaurado',
+      contextResetFailed: 'Falha ao atualizar o contexto',
+      contextResetDivider: 'Contexto limpo — o modelo não vê as mensagens acima',
       edit: 'Editar',
       resend: 'Reenviar',
       error: 'Ocorreu um erro',
@@ -1064,6 +1074,8 @@ export default {
       thinkingInProgress: 'Pensando...',
       thinkingOn: 'Modo de pensamento ativado',
       thinkingOff: 'Modo de pensamento desativado',
+      showThinkingOn: 'Raciocínio visível',
+      showThinkingOff: 'Raciocínio oculto (continua sendo salvo)',
       toolCalling: 'Executando',
       toolCompleted: 'Concluído',
       toolError: 'Falhou',
@@ -1461,6 +1473,8 @@ export default {
       menu: {
         rename: 'Renomear',
         relearn: 'Reaprender',
+        retryEmbedding: 'Tentar vetorização novamente',
+        cancelProcessing: 'Parar aprendizado',
         delete: 'Excluir',
       },
       upload: {
@@ -1468,6 +1482,7 @@ export default {
         failed: 'Falha ao carregar documentos',
         count: '{count} documento(s) carregado(s)',
         uploading: 'Carregando {done}/{total}',
+        reused: '{count} documentos reutilizaram resultados de aprendizado existentes',
       },
       drop: {
         hint: 'Arraste arquivos aqui para carregar',
@@ -1487,6 +1502,21 @@ export default {
         successBatch: 'Reaprendizado iniciado para {count} documentos',
         failed: 'Falha ao reaprender',
       },
+      crossLibraryDuplicate: {
+        title: 'Já aprendido em outra base de conhecimento',
+        desc: '“{name}” já foi aprendido em “{library}”. Reutilizar o conteúdo analisado e os vetores em vez de aprender novamente?',
+        descBatch: '{count} arquivos já foram aprendidos em outras bases de conhecimento. Reutilizar o conteúdo analisado e os vetores em vez de aprender novamente?',
+        reuse: 'Reutilizar',
+        relearn: 'Aprender novamente',
+      },
+      retryEmbedding: {
+        success: 'Nova tentativa de vetorização iniciada',
+        failed: 'Falha ao tentar a vetorização novamente',
+      },
+      cancelProcessing: {
+        success: 'Aprendizado interrompido',
+        failed: 'Falha ao parar o aprendizado',
+      },
       delete: {
         title: 'Confirmar Exclusão',
         desc: 'Tem certeza que deseja excluir ',
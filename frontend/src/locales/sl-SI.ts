@@ -1056,6 +1056,16 @@ export default {
       stop: 'Ustavi',
       copy: 'Kopiraj',
       copyFailed: 'Kopiranje ni uspelo',
+      rateUp: 'Dober odgovor',
+      rateDown: 'Slab odgovor',
+      rateFailed: 'Ocene ni bilo mogoče shraniti',
+      contextReset: 'Počisti kontekst (ohrani zgodovino)',
+      contextResetUndo: 'Obnovi prejšnji kontekst',
+      contextCleared:
+        'Kontekst je počiščen – prejšnja sporočila ostanejo vidna, vendar se ne pošiljajo več modelu',
+      contextRestored: 'Prejšnji kontekst je obnovljen',
+      contextResetFailed: 'Posodobitev konteksta ni uspela',
+      contextResetDivider: 'Kontekst je počiščen – model ne vidi zgornjih sporočil',
       edit: 'Uredi',
       resend: 'Pošlji znova',
       error: 'Prišlo je do napake',
@@ -1067,6 +1077,8 @@ export default {
       thinkingInProgress: 'Razmišljanje...',
       thinkingOn: 'Način razmišljanja omogočen',
       thinkingOff: 'Način razmišljanja onemogočen',
+      showThinkingOn: 'Razmišljanje je prikazano',
+      showThinkingOff: 'Razmišljanje je skrito (še vedno se shrani)',
       toolCalling: 'Izvajanje',
       toolCompleted: 'Dokončano',
       toolError: 'Ni uspelo',
@@ -1463,6 +1475,8 @@ export default {
       menu: {
         rename: 'Preimenuj',
         relearn: 'Znova nauči',
+        retryEmbedding: 'Ponovi vektorizacijo',
+        cancelProcessing: 'Ustavi učenje',
         delete: 'Izbriši',
       },
       upload: {
@@ -1470,6 +1484,7 @@ export default {
         failed: 'Nalaganje dokumentov ni uspelo',
         count: '{count} dokument(ov) naloženih',
         uploading: 'Nalaganje {done}/{total}',
+        reused: '{count} dokumentov je ponovno uporabilo obstoječe rezultate učenja',
       },
       drop: {
         hint: 'Sem povlecite datoteke za naložitev',
@@ -1489,6 +1504,21 @@ export default {
         successBatch: 'Ponovno učenje se je začelo za {count} dokumentov',
         failed: 'Ponovno učenje ni uspelo',
       },
+      crossLibraryDuplicate: {
+        title: 'Že naučeno v drugi bazi znanja',
+        desc: '»{name}« je bil že naučen v »{library}«. Želite ponovno uporabiti razčlenjeno vsebino in vektorje namesto ponovnega učenja?',
+        descBatch: '{count} datotek je bilo že naučenih v drugih bazah znanja. Želite ponovno uporabiti razčlenjeno vsebino in vektorje namesto ponovnega učenja?',
+        reuse: 'Uporabi znova',
+        relearn: 'Nauči znova',
+      },
+      retryEmbedding: {
+        success: 'Ponovna vektorizacija se je začela',
+        failed: 'Ponovna vektorizacija ni uspela',
+      },
+      cancelProcessing: {
+        success: 'Učenje ustavljeno',
+        failed: 'Učenja ni bilo mogoče ustaviti',
+      },
       delete: {
         title: 'Potrdite brisanje',
         desc: 'Izbrišem ',
@@ -1054,6 +1054,16 @@ export default {
       stop: 'Durdur',
       copy: 'Kopyala',
       copyFailed: 'Kopyalama başarısız',
+      rateUp: 'İyi yanıt',
+      rateDown: 'Kötü yanıt',
+      rateFailed: 'Değerlendirme kaydedilemedi',
+      contextReset: 'Bağlamı temizle (geçmişi koru)',
+      contextResetUndo: 'Önceki bağlamı geri yükle',
+      contextCleared:
+        'Bağlam temizlendi; önceki mesajlar görünür kalır ancak artık modele gönderilmez',
+      contextRestored: 'Önceki bağlam geri yüklendi',
+      contextResetFailed: 'Bağlam güncellenemedi',
+      contextResetDivider: 'Bağlam temizlendi — model yukarıdaki mesajları görmez',
       edit: 'Düzenle',
       resend: 'Yeniden gönder',
       error: 'Bir hata oluştu',
@@ -1065,6 +1075,8 @@ export default {
       thinkingInProgress: 'Düşünüyor...',
       thinkingOn: 'Düşünme modu etkinleştirildi',
       thinkingOff: 'Düşünme modu devre dışı bırakıldı',
+      showThinkingOn: 'Akıl yürütme gösteriliyor',
+      showThinkingOff: 'Akıl yürütme gizli (yine de kaydedilir)',
       toolCalling: 'Çalışıyor',
       toolCompleted: 'Tamamlandı',
       toolError: 'Başarısız',
@@ -1461,6 +1473,8 @@ export default {
       menu: {
         rename: 'Yeniden adlandır',
         relearn: 'Yeniden öğren',
+        retryEmbedding: 'Vektörleştirmeyi yeniden dene',
+        cancelProcessing: 'Öğrenmeyi durdur',
         delete: 'Sil',
       },
       upload: {
@@ -1468,6 +1482,7 @@ export default {
         failed: 'Belge(ler) yüklenemedi',
         count: '{count} belge(ler) yüklendi',
         uploading: 'Yükleniyor {done}/{total}',
+        reused: '{count} belge mevcut öğrenme sonuçlarını yeniden kullandı',
       },
       drop: {
         hint: 'Yüklemek için dosyaları buraya sürükleyin',
@@ -1487,6 +1502,21 @@ export default {
         successBatch: '{count} belge için yeniden öğrenme başlatıldı',
         failed: 'Yeniden öğrenme başarısız',
       },
+      crossLibraryDuplicate: {
+        title: 'Başka bir bilgi tabanında zaten öğrenildi',
+        desc: '“{name}” zaten “{library}” içinde öğrenildi. Yeniden öğrenmek yerine ayrıştırılmış içeriği ve vektörleri yeniden kullanılsın mı?',
+        descBatch: '{count} dosya diğer bilgi tabanlarında zaten öğrenildi. Yeniden öğrenmek yerine ayrıştırılmış içeriği ve vektörleri yeniden kullanılsın mı?',
+        reuse: 'Yeniden kullan',
+        relearn: 'Yeniden öğren',
+      },
+      retryEmbedding: {
+        success: 'Vektörleştirme yeniden başlatıldı',
+        failed: 'Vektörleştirme yeniden denenemedi',
+      },
+      cancelProcessing: {
+        success: 'Öğrenme durduruldu',
+        failed: 'Öğrenme durdurulamadı',
+      },
       delete: {
         title: 'Silmeyi onayla',
         desc: '',
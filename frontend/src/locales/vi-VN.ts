@@ -1056,6 +1056,16 @@ export default {
       stop: 'Dừng',
       copy: 'Sao chép',
       copyFailed: 'Sao chép thất bại',
+      rateUp: 'Câu trả lời tốt',
+      rateDown: 'Câu trả lời chưa tốt',
+      rateFailed: 'Không thể lưu đánh giá',
+      contextReset: 'Xóa ngữ cảnh (giữ lịch sử)',
+      contextResetUndo: 'Khôi phục ngữ cảnh trước',
+      contextCleared:
+        'Đã xóa ngữ cảnh, các tin nhắn trước vẫn hiển thị nhưng không còn được gửi cho mô hình',
+      contextRestored: 'Đã khôi phục ngữ cảnh trước',
+      contextResetFailed: 'Không thể cập nhật ngữ cảnh',
+      contextResetDivider: 'Đã xóa ngữ cảnh — mô hình không thấy các tin nhắn phía trên',
       edit: 'Chỉnh sửa',
       resend: 'Gửi lại',
       error: 'Đã xảy ra lỗi',
@@ -1067,6 +1077,8 @@ export default {
       thinkingInProgress: 'Đang suy nghĩ...',
       thinkingOn: 'Chế độ suy nghĩ đã bật',
       thinkingOff: 'Chế độ suy nghĩ đã tắt',
+      showThinkingOn: 'Hiển thị quá trình suy luận',
+      showThinkingOff: 'Ẩn quá trình suy luận (vẫn được lưu)',
       toolCalling: 'Đang thực thi',
       toolCompleted: 'Hoàn thành',
       toolError: 'Thất bại',
@@ -1463,6 +1475,8 @@ export default {
       menu: {
         rename: 'Đổi tên',
         relearn: 'Học lại',
+        retryEmbedding: 'Thử lại vector hóa',
+        cancelProcessing: 'Dừng học',
         delete: 'Xóa',
       },
       upload: {
@@ -1470,6 +1484,7 @@ export default {
         failed: 'Tải tài liệu thất bại',
         count: 'Đã tải {count} tài liệu',
         uploading: 'Đang tải {done}/{total}',
+        reused: '{count} tài liệu đã dùng lại kết quả học sẵn có',
       },
       drop: {
         hint: 'Kéo tệp vào đây để tải lên',
@@ -1489,6 +1504,21 @@ export default {
         successBatch: 'Đã bắt đầu học lại {count} tài liệu',
         failed: 'Học lại thất bại',
       },
+      crossLibraryDuplicate: {
+        title: 'Đã được học trong cơ sở tri thức khác',
+        desc: '“{name}” đã được học trong “{library}”. Dùng lại nội dung đã phân tích và vector thay vì học lại?',
+        descBatch: '{count} tệp đã được học trong các cơ sở tri thức khác. Dùng lại nội dung đã phân tích và vector thay vì học lại?',
+        reuse: 'Dùng lại',
+        relearn: 'Học lại',
+      },
+      retryEmbedding: {
+        success: 'Đã bắt đầu vector hóa lại',
+        failed: 'Không thể thử lại vector hóa',
+      },
+      cancelProcessing: {
+        success: 'Đã dừng học',
+        failed: 'Không thể dừng học',
+      },
       delete: {
         title: 'Xác nhận xóa',
         desc: 'Xóa tài liệu ',
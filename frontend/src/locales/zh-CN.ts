@@ -1061,6 +1061,15 @@ export default {
       stop: '停止',
       copy: '复制',
       copyFailed: '复制失败',
+      rateUp: '回答很好',
+      rateDown: '回答不好',
+      rateFailed: '保存评分失败',
+      contextReset: '清除上下文（保留记录）',
+      contextResetUndo: '恢复之前的上下文',
+      contextCleared: '已清除上下文，之前的消息仍会显示，但不再发送给模型',
+      contextRestored: '已恢复之前的上下文',
+      contextResetFailed: '更新上下文失败',
+      contextResetDivider: '上下文已清除，模型看不到以上消息',
       edit: '编辑',
       resend: '重新发送',
       error: '出错了',
@@ -1072,6 +1081,8 @@ export default {
       thinkingInProgress: '思考中...',
       thinkingOn: '思考模式已开启',
       thinkingOff: '思考模式已关闭',
+      showThinkingOn: '显示思考过程',
+      showThinkingOff: '隐藏思考过程（仍会记录）',
       toolCalling: '调用中',
       toolCompleted: '已完成',
       toolError: '失败',
@@ -1468,6 +1479,8 @@ export default {
       menu: {
         rename: '重命名',
         relearn: '重新学习',
+        retryEmbedding: '重试向量化',
+        cancelProcessing: '停止学习',
         delete: '删除',
       },
       upload: {
@@ -1475,6 +1488,7 @@ export default {
         failed: '文档上传失败',
         count: '已上传 {count} 个文档',
         uploading: '正在上传 {done}/{total}',
+        reused: '{count} 个文档复用了已有的学习结果',
       },
       drop: {
         hint: '拖放文件到此处上传',
@@ -1494,6 +1508,21 @@ export default {
         successBatch: '已开始重新学习 {count} 个文档',
         failed: '重新学习失败',
       },
+      crossLibraryDuplicate: {
+        title: '文件已在其他知识库中学习过',
+        desc: '「{name}」已在知识库「{library}」中学习过，是否直接复用其分段与向量，而不重新学习？',
+        descBatch: '有 {count} 个文件已在其他知识库中学习过，是否直接复用其分段与向量，而不重新学习？',
+        reuse: '复用',
+        relearn: '重新学习',
+      },
+      retryEmbedding: {
+        success: '已开始重新向量化',
+        failed: '重试向量化失败',
+      },
+      cancelProcessing: {
+        success: '已停止学习',
+        failed: '停止学习失败',
+      },
       delete: {
         title: '确认删除',
         desc: '确定要删除文档「{name}」吗？此操作无法撤销。',
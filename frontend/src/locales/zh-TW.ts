@@ -1054,6 +1054,15 @@ export default {
       stop: '停止',
       copy: '複製',
       copyFailed: '複製失敗',
+      rateUp: '回答很好',
+      rateDown: '回答不好',
+      rateFailed: '儲存評分失敗',
+      contextReset: '清除上下文（保留紀錄）',
+      contextResetUndo: '恢復之前的上下文',
+      contextCleared: '已清除上下文，之前的訊息仍會顯示，但不再傳送給模型',
+      contextRestored: '已恢復之前的上下文',
+      contextResetFailed: '更新上下文失敗',
+      contextResetDivider: '上下文已清除，模型看不到以上訊息',
       edit: '編輯',
       resend: '重新發送',
       error: '生成過程中出現錯誤',
@@ -1065,6 +1074,8 @@ export default {
       thinkingInProgress: '正在思考...',
       thinkingOn: '思考模式已開啟',
       thinkingOff: '思考模式已關閉',
+      showThinkingOn: '顯示思考過程',
+      showThinkingOff: '隱藏思考過程（仍會記錄）',
       toolCalling: '執行中',
       toolCompleted: '已完成',
       toolError: '執行失敗',
@@ -1464,6 +1475,8 @@ export default {
       menu: {
         rename: '重命名',
         relearn: '重新學習',
+        retryEmbedding: '重試向量化',
+        cancelProcessing: '停止學習',
         delete: '刪除',
       },
       upload: {
@@ -1471,6 +1484,7 @@ export default {
         failed: '檔案上傳失敗',
         count: '已上傳 {count} 個檔案',
         uploading: '正在上傳 {done}/{total}',
+        reused: '{count} 個文件複用了已有的學習結果',
         hint: '拖放檔案到此處上傳',
         formats: '支援 PDF、Word、Excel、TXT、Markdown、CSV、HTML、OFD 格式',
       },
@@ -1492,6 +1506,21 @@ export default {
         successBatch: '已開始重新學習 {count} 個文件',
         failed: '重新學習失敗',
       },
+      crossLibraryDuplicate: {
+        title: '檔案已在其他知識庫中學習過',
+        desc: '「{name}」已在知識庫「{library}」中學習過，是否直接複用其分段與向量，而不重新學習？',
+        descBatch: '有 {count} 個檔案已在其他知識庫中學習過，是否直接複用其分段與向量，而不重新學習？',
+        reuse: '複用',
+        relearn: '重新學習',
+      },
+      retryEmbedding: {
+        success: '已開始重新向量化',
+        failed: '重試向量化失敗',
+      },
+      cancelProcessing: {
+        success: '已停止學習',
+        failed: '停止學習失敗',
+      },
       delete: {
         title: '確認刪除',
         desc: '確定要刪除檔案「{name}」嗎？此操作無法撤銷。',
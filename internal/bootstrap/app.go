@@ -14,6 +14,8 @@ import (
 
 	"chatclaw/internal/deeplink"
 	"chatclaw/internal/define"
+	"chatclaw/internal/fts/tokenizer"
+	"chatclaw/internal/httpclient"
 	"chatclaw/internal/logger"
 	openclawagents "chatclaw/internal/openclaw/agents"
 	openclawcron "chatclaw/internal/openclaw/cron"
@@ -33,11 +35,13 @@ import (
 	"chatclaw/internal/services/i18n"
 	"chatclaw/internal/services/library"
 	"chatclaw/internal/services/librarymcp"
+	"chatclaw/internal/services/maintenance"
 	"chatclaw/internal/services/mcp"
 	"chatclaw/internal/services/memory"
 	"chatclaw/internal/services/multiask"
 	openclawchannels "chatclaw/internal/services/openclaw/channels"
 	"chatclaw/internal/services/providers"
+	"chatclaw/internal/services/retrieval"
 	"chatclaw/internal/services/scheduledtasks"
 	"chatclaw/internal/services/settings"
 	"chatclaw/internal/services/skillmarket"
@@ -59,6 +63,9 @@ import (
 	"github.com/wailsapp/wails/v3/pkg/events"
 )
 
+// shutdownGenerationsTimeout 退出时等待 chat 生成停止的上限（略大于 chat 包自身的等待时间）
+const shutdownGenerationsTimeout = 4 * time.Second
+
 // mainWindowManager handles safe main window operations with validity checks.
 // It ensures the main window is valid before performing operations and
 // can recreate the window if it becomes invalid.
@@ -254,6 +261,19 @@ func NewApp(opts Options) (app *application.App, cleanup func(), err error) {
 		i18n.SetLocale(lang)
 	}
 
+	retrieval.SetCacheSize(settings.GetInt("retrieval_cache_size", retrieval.DefaultCacheSize))
+	settings.ApplyHTTPPoolSettings()
+	httpclient.SetAuditEnabled(settings.GetBool("debug_llm_requests", false))
+	if lang, ok := settings.GetValue(tokenizer.LanguageSettingKey); ok {
+		tokenizer.SetLanguage(lang)
+	}
+	settings.ApplySearchQueryRules()
+	if lvl, ok := settings.GetValue("log_level"); ok {
+		logger.SetLevel(lvl)
+	}
+	logger.SetFileEnabled(settings.GetBool("log_to_file", true))
+	logger.SetIncludeContent(settings.GetBool("log_include_content", false))
+
 	// Sync ADK built-in prompt language with app locale.
 	if i18n.GetLocale() == i18n.LocaleZhCN {
 		_ = adk.SetLanguage(adk.LanguageChinese)
@@ -289,6 +309,7 @@ func NewApp(opts Options) (app *application.App, cleanup func(), err error) {
 		return nil, nil, fmt.Errorf("ensure main agent: %w", err)
 	}
 	app.RegisterService(application.NewService(agentsService))
+	providers.RegisterConfigAgents(agents.NewConfigBundleAgents())
 	// 注册 OpenClaw 助手服务
 	openClawAgentsService := openclawagents.NewOpenClawAgentsService(app)
 	if err := openClawAgentsService.EnsureMainAgent(); err != nil {
@@ -349,6 +370,8 @@ func NewApp(opts Options) (app *application.App, cleanup func(), err error) {
 	app.RegisterService(application.NewService(library.NewLibraryService(app)))
 	// 注册文档服务
 	app.RegisterService(application.NewService(document.NewDocumentService(app)))
+	// 注册数据库维护服务（备份 / 压缩）
+	app.RegisterService(application.NewService(maintenance.NewMaintenanceService(app)))
 	// Startup self-heal for sqlite-vec shadow-table drift caused by previous
 	// embedding-dimension swaps. Run after taskmanager init so repair can queue
 	// global re-embedding jobs when needed.
@@ -509,19 +532,16 @@ func NewApp(opts Options) (app *application.App, cleanup func(), err error) {
 	)
 	app.RegisterService(application.NewService(textSelectionService))
 
-	// 创建系统托盘
+	// 创建系统托盘（菜单由 trayService 构建，见 tray/menu.go）
 	systrayMenu := app.NewMenu()
-	systrayMenu.Add(i18n.T("systray.show")).OnClick(func(ctx *application.Context) {
+	showMainAndFloatingBall := func() {
 		// 安全地显示主窗口
 		mainWinMgr.safeShow()
 		// 若悬浮球开关为开启，则在唤醒主窗口时恢复悬浮球
 		if floatingBallService != nil && settings.GetBool("show_floating_window", false) && !floatingBallService.IsVisible() {
 			_ = floatingBallService.SetVisible(true)
 		}
-	})
-	systrayMenu.Add(i18n.T("systray.quit")).OnClick(func(ctx *application.Context) {
-		app.Quit()
-	})
+	}
 
 	// macOS 使用模板图标，自动适应深色/浅色模式
 	var systray *application.SystemTray
@@ -551,6 +571,21 @@ func NewApp(opts Options) (app *application.App, cleanup func(), err error) {
 	// 创建托盘服务（用于前端动态控制 show/hide + 缓存关闭策略）
 	trayService := tray.NewTrayService(app, systray)
 	app.RegisterService(application.NewService(trayService))
+	trayService.SetupMenu(conversationsService, tray.MenuActions{
+		ShowMainWindow:      showMainAndFloatingBall,
+		FloatingBallVisible: floatingBallService.IsVisible,
+		ToggleFloatingBall:  floatingBallService.ToggleVisible,
+	})
+	// 会话变更、语言切换时刷新托盘菜单中的最近会话和文案
+	for _, name := range []string{
+		conversations.EventConversationsChanged,
+		conversations.EventConversationCreated,
+		"locale:changed",
+	} {
+		app.Event.On(name, func(_ *application.CustomEvent) {
+			trayService.ScheduleMenuRebuild()
+		})
+	}
 	// macOS: URL Scheme is delivered via Apple Event, not via command-line args.
 	// Listen for ApplicationLaunchedWithUrl to handle chatclaw:// deep links.
 	app.Event.OnApplicationEvent(events.Common.ApplicationLaunchedWithUrl, func(event *application.ApplicationEvent) {
@@ -683,19 +718,44 @@ func NewApp(opts Options) (app *application.App, cleanup func(), err error) {
 		restoreFloatingBall("mac_reopen")
 	})
 
-	return app, func() {
-		openclawManager.Shutdown()
-		assistantMCPService.StopAllServers()
-		libraryMCPService.Stop()
-		channelGateway.StopAll(context.Background())
-		scheduledTasksService.Stop()
-		chatService.Shutdown()
-		// Stop task manager before closing database
-		if tm := taskmanager.Get(); tm != nil {
-			tm.StopNow()
-		}
-		sqlite.Close()
-		// Close log file last so all shutdown logs are captured.
-		logCleanup()
-	}, nil
+	// 退出流程：Wails 的 OnShutdown 在进程结束前执行（部分平台上 Run() 不会返回，main 中的 defer 不一定执行），
+	// main 的 defer 作为兜底；sync.Once 保证只执行一次
+	var shutdownOnce sync.Once
+	shutdown := func() {
+		shutdownOnce.Do(func() {
+			// Refuse new background jobs while services wind down
+			tm := taskmanager.Get()
+			if tm != nil {
+				tm.StopAccepting()
+			}
+			openclawManager.Shutdown()
+			assistantMCPService.StopAllServers()
+			libraryMCPService.Stop()
+			channelGateway.StopAll(context.Background())
+			scheduledTasksService.Stop()
+			// Stop generations and persist partial replies before the database closes. OnShutdown
+			// runs on the main thread, which the generations may need to finish emitting; stop them
+			// in a goroutine and wait a bounded time so quitting never hangs on a stuck generation.
+			chatDone := make(chan struct{})
+			go func() {
+				defer close(chatDone)
+				chatService.Shutdown()
+			}()
+			select {
+			case <-chatDone:
+			case <-time.After(shutdownGenerationsTimeout):
+				app.Logger.Warn("chat generations still stopping at shutdown; closing anyway")
+			}
+			// Stop task manager before closing database
+			if tm != nil {
+				tm.StopNow()
+			}
+			sqlite.Close()
+			// Close log file last so all shutdown logs are captured.
+			logCleanup()
+		})
+	}
+	app.OnShutdown(shutdown)
+
+	return app, shutdown, nil
 }
@@ -18,6 +18,13 @@ type BuiltinModelConfig struct {
 	Type         string
 	SortOrder    int
 	Capabilities []string // 支持的输入类型: text, image, audio, video, file
+
+	SupportsReasoning bool // 支持思考模式（enable_thinking）
+}
+
+// SupportsTools 是否支持工具调用（内置 llm 模型均支持）
+func (m BuiltinModelConfig) SupportsTools() bool {
+	return m.Type == "llm"
 }
 
 // BuiltinProviders 内置供应商列表
@@ -40,43 +47,43 @@ var BuiltinProviders = []BuiltinProviderConfig{
 // BuiltinModels 内置模型列表（初始化时写入 models 表）
 var BuiltinModels = []BuiltinModelConfig{
 	// OpenAI
-	{ProviderID: "openai", ModelID: "gpt-5.4", Name: "GPT-5.4", Type: "llm", SortOrder: 100, Capabilities: []string{"text", "image", "file"}},
-	{ProviderID: "openai", ModelID: "gpt-5.4-pro", Name: "GPT-5.4 Pro", Type: "llm", SortOrder: 101, Capabilities: []string{"text", "image", "file"}},
-	{ProviderID: "openai", ModelID: "gpt-5.2", Name: "GPT-5.2", Type: "llm", SortOrder: 102, Capabilities: []string{"text", "image", "file"}},
-	{ProviderID: "openai", ModelID: "gpt-5.1", Name: "GPT-5.1", Type: "llm", SortOrder: 103, Capabilities: []string{"text", "image", "file"}},
-	{ProviderID: "openai", ModelID: "gpt-5", Name: "GPT-5", Type: "llm", SortOrder: 104, Capabilities: []string{"text", "image", "file"}},
-	{ProviderID: "openai", ModelID: "gpt-5-mini", Name: "GPT-5 mini", Type: "llm", SortOrder: 105, Capabilities: []string{"text", "image", "file"}},
-	{ProviderID: "openai", ModelID: "gpt-5.2-nano", Name: "GPT-5.2 nano", Type: "llm", SortOrder: 106, Capabilities: []string{"text", "image", "file"}},
-	{ProviderID: "openai", ModelID: "gpt-5.2-pro", Name: "GPT-5.2 Pro", Type: "llm", SortOrder: 107, Capabilities: []string{"text", "image", "file"}},
+	{ProviderID: "openai", ModelID: "gpt-5.4", Name: "GPT-5.4", Type: "llm", SortOrder: 100, Capabilities: []string{"text", "image", "file"}, SupportsReasoning: true},
+	{ProviderID: "openai", ModelID: "gpt-5.4-pro", Name: "GPT-5.4 Pro", Type: "llm", SortOrder: 101, Capabilities: []string{"text", "image", "file"}, SupportsReasoning: true},
+	{ProviderID: "openai", ModelID: "gpt-5.2", Name: "GPT-5.2", Type: "llm", SortOrder: 102, Capabilities: []string{"text", "image", "file"}, SupportsReasoning: true},
+	{ProviderID: "openai", ModelID: "gpt-5.1", Name: "GPT-5.1", Type: "llm", SortOrder: 103, Capabilities: []string{"text", "image", "file"}, SupportsReasoning: true},
+	{ProviderID: "openai", ModelID: "gpt-5", Name: "GPT-5", Type: "llm", SortOrder: 104, Capabilities: []string{"text", "image", "file"}, SupportsReasoning: true},
+	{ProviderID: "openai", ModelID: "gpt-5-mini", Name: "GPT-5 mini", Type: "llm", SortOrder: 105, Capabilities: []string{"text", "image", "file"}, SupportsReasoning: true},
+	{ProviderID: "openai", ModelID: "gpt-5.2-nano", Name: "GPT-5.2 nano", Type: "llm", SortOrder: 106, Capabilities: []string{"text", "image", "file"}, SupportsReasoning: true},
+	{ProviderID: "openai", ModelID: "gpt-5.2-pro", Name: "GPT-5.2 Pro", Type: "llm", SortOrder: 107, Capabilities: []string{"text", "image", "file"}, SupportsReasoning: true},
 	{ProviderID: "openai", ModelID: "text-embedding-3-large", Name: "Text Embedding 3 Large", Type: "embedding", SortOrder: 100, Capabilities: []string{"text"}},
 	{ProviderID: "openai", ModelID: "text-embedding-3-small", Name: "Text Embedding 3 Small", Type: "embedding", SortOrder: 101, Capabilities: []string{"text"}},
 
 	// Anthropic
-	{ProviderID: "anthropic", ModelID: "claude-opus-4-6", Name: "Claude Opus 4.6", Type: "llm", SortOrder: 100, Capabilities: []string{"text", "image"}},
-	{ProviderID: "anthropic", ModelID: "claude-sonnet-4-6", Name: "Claude Sonnet 4.6", Type: "llm", SortOrder: 101, Capabilities: []string{"text", "image"}},
-	{ProviderID: "anthropic", ModelID: "claude-haiku-4-5", Name: "Claude Haiku 4.5", Type: "llm", SortOrder: 102, Capabilities: []string{"text", "image"}},
+	{ProviderID: "anthropic", ModelID: "claude-opus-4-6", Name: "Claude Opus 4.6", Type: "llm", SortOrder: 100, Capabilities: []string{"text", "image"}, SupportsReasoning: true},
+	{ProviderID: "anthropic", ModelID: "claude-sonnet-4-6", Name: "Claude Sonnet 4.6", Type: "llm", SortOrder: 101, Capabilities: []string{"text", "image"}, SupportsReasoning: true},
+	{ProviderID: "anthropic", ModelID: "claude-haiku-4-5", Name: "Claude Haiku 4.5", Type: "llm", SortOrder: 102, Capabilities: []string{"text", "image"}, SupportsReasoning: true},
 
 	// Google
-	{ProviderID: "google", ModelID: "gemini-3.1-pro-preview", Name: "Gemini 3.1 Pro", Type: "llm", SortOrder: 99, Capabilities: []string{"text", "image", "audio", "video", "file"}},
-	{ProviderID: "google", ModelID: "gemini-3-pro-preview", Name: "Gemini 3 Pro", Type: "llm", SortOrder: 100, Capabilities: []string{"text", "image", "audio", "video", "file"}},
-	{ProviderID: "google", ModelID: "gemini-3-flash-preview", Name: "Gemini 3 Flash", Type: "llm", SortOrder: 101, Capabilities: []string{"text", "image", "audio", "video", "file"}},
-	{ProviderID: "google", ModelID: "gemini-2.5-flash", Name: "Gemini 2.5 Flash", Type: "llm", SortOrder: 102, Capabilities: []string{"text", "image", "audio", "video", "file"}},
-	{ProviderID: "google", ModelID: "gemini-2.5-flash-lite", Name: "Gemini 2.5 Flash-Lite", Type: "llm", SortOrder: 103, Capabilities: []string{"text", "image", "audio", "video", "file"}},
-	{ProviderID: "google", ModelID: "gemini-2.5-pro", Name: "Gemini 2.5 Pro", Type: "llm", SortOrder: 104, Capabilities: []string{"text", "image", "audio", "video", "file"}},
+	{ProviderID: "google", ModelID: "gemini-3.1-pro-preview", Name: "Gemini 3.1 Pro", Type: "llm", SortOrder: 99, Capabilities: []string{"text", "image", "audio", "video", "file"}, SupportsReasoning: true},
+	{ProviderID: "google", ModelID: "gemini-3-pro-preview", Name: "Gemini 3 Pro", Type: "llm", SortOrder: 100, Capabilities: []string{"text", "image", "audio", "video", "file"}, SupportsReasoning: true},
+	{ProviderID: "google", ModelID: "gemini-3-flash-preview", Name: "Gemini 3 Flash", Type: "llm", SortOrder: 101, Capabilities: []string{"text", "image", "audio", "video", "file"}, SupportsReasoning: true},
+	{ProviderID: "google", ModelID: "gemini-2.5-flash", Name: "Gemini 2.5 Flash", Type: "llm", SortOrder: 102, Capabilities: []string{"text", "image", "audio", "video", "file"}, SupportsReasoning: true},
+	{ProviderID: "google", ModelID: "gemini-2.5-flash-lite", Name: "Gemini 2.5 Flash-Lite", Type: "llm", SortOrder: 103, Capabilities: []string{"text", "image", "audio", "video", "file"}, SupportsReasoning: true},
+	{ProviderID: "google", ModelID: "gemini-2.5-pro", Name: "Gemini 2.5 Pro", Type: "llm", SortOrder: 104, Capabilities: []string{"text", "image", "audio", "video", "file"}, SupportsReasoning: true},
 
 	// DeepSeek
 	{ProviderID: "deepseek", ModelID: "deepseek-chat", Name: "DeepSeek V3", Type: "llm", SortOrder: 100, Capabilities: []string{"text"}},
-	{ProviderID: "deepseek", ModelID: "deepseek-reasoner", Name: "DeepSeek R1", Type: "llm", SortOrder: 101, Capabilities: []string{"text"}},
+	{ProviderID: "deepseek", ModelID: "deepseek-reasoner", Name: "DeepSeek R1", Type: "llm", SortOrder: 101, Capabilities: []string{"text"}, SupportsReasoning: true},
 
 	// 智谱
-	{ProviderID: "zhipu", ModelID: "glm-5", Name: "glm-5", Type: "llm", SortOrder: 99, Capabilities: []string{"text"}},
-	{ProviderID: "zhipu", ModelID: "glm-4.7", Name: "glm-4.7", Type: "llm", SortOrder: 100, Capabilities: []string{"text"}},
-	{ProviderID: "zhipu", ModelID: "glm-4.7-flash", Name: "glm-4.7-flash", Type: "llm", SortOrder: 101, Capabilities: []string{"text"}},
-	{ProviderID: "zhipu", ModelID: "glm-4.7-flashx", Name: "glm-4.7-flashx", Type: "llm", SortOrder: 102, Capabilities: []string{"text"}},
-	{ProviderID: "zhipu", ModelID: "glm-4.6", Name: "glm-4.6", Type: "llm", SortOrder: 103, Capabilities: []string{"text"}},
-	{ProviderID: "zhipu", ModelID: "glm-4.5-air", Name: "glm-4.5-air", Type: "llm", SortOrder: 104, Capabilities: []string{"text"}},
-	{ProviderID: "zhipu", ModelID: "glm-4.5-airx", Name: "glm-4.5-airx", Type: "llm", SortOrder: 105, Capabilities: []string{"text"}},
-	{ProviderID: "zhipu", ModelID: "glm-4.5-flash", Name: "glm-4.5-flash", Type: "llm", SortOrder: 106, Capabilities: []string{"text"}},
+	{ProviderID: "zhipu", ModelID: "glm-5", Name: "glm-5", Type: "llm", SortOrder: 99, Capabilities: []string{"text"}, SupportsReasoning: true},
+	{ProviderID: "zhipu", ModelID: "glm-4.7", Name: "glm-4.7", Type: "llm", SortOrder: 100, Capabilities: []string{"text"}, SupportsReasoning: true},
+	{ProviderID: "zhipu", ModelID: "glm-4.7-flash", Name: "glm-4.7-flash", Type: "llm", SortOrder: 101, Capabilities: []string{"text"}, SupportsReasoning: true},
+	{ProviderID: "zhipu", ModelID: "glm-4.7-flashx", Name: "glm-4.7-flashx", Type: "llm", SortOrder: 102, Capabilities: []string{"text"}, SupportsReasoning: true},
+	{ProviderID: "zhipu", ModelID: "glm-4.6", Name: "glm-4.6", Type: "llm", SortOrder: 103, Capabilities: []string{"text"}, SupportsReasoning: true},
+	{ProviderID: "zhipu", ModelID: "glm-4.5-air", Name: "glm-4.5-air", Type: "llm", SortOrder: 104, Capabilities: []string{"text"}, SupportsReasoning: true},
+	{ProviderID: "zhipu", ModelID: "glm-4.5-airx", Name: "glm-4.5-airx", Type: "llm", SortOrder: 105, Capabilities: []string{"text"}, SupportsReasoning: true},
+	{ProviderID: "zhipu", ModelID: "glm-4.5-flash", Name: "glm-4.5-flash", Type: "llm", SortOrder: 106, Capabilities: []string{"text"}, SupportsReasoning: true},
 	{ProviderID: "zhipu", ModelID: "glm-4-flash-250414", Name: "glm-4-flash-250414", Type: "llm", SortOrder: 107, Capabilities: []string{"text"}},
 	{ProviderID: "zhipu", ModelID: "glm-4-flashx-250414", Name: "glm-4-flashx-250414", Type: "llm", SortOrder: 108, Capabilities: []string{"text"}},
 	{ProviderID: "zhipu", ModelID: "glm-4v-flash", Name: "GLM-4V Flash", Type: "llm", SortOrder: 109, Capabilities: []string{"text", "image"}},
@@ -84,11 +91,11 @@ var BuiltinModels = []BuiltinModelConfig{
 	{ProviderID: "zhipu", ModelID: "embedding-3", Name: "Embedding-3", Type: "embedding", SortOrder: 100, Capabilities: []string{"text"}},
 
 	// 通义千问
-	{ProviderID: "qwen", ModelID: "qwen3.5-plus", Name: "通义千问 3.5 Plus", Type: "llm", SortOrder: 98, Capabilities: []string{"text", "image"}},
-	{ProviderID: "qwen", ModelID: "qwen3.5-flash", Name: "通义千问 3.5 Flash", Type: "llm", SortOrder: 99, Capabilities: []string{"text", "image"}},
+	{ProviderID: "qwen", ModelID: "qwen3.5-plus", Name: "通义千问 3.5 Plus", Type: "llm", SortOrder: 98, Capabilities: []string{"text", "image"}, SupportsReasoning: true},
+	{ProviderID: "qwen", ModelID: "qwen3.5-flash", Name: "通义千问 3.5 Flash", Type: "llm", SortOrder: 99, Capabilities: []string{"text", "image"}, SupportsReasoning: true},
 	{ProviderID: "qwen", ModelID: "qwen3-max", Name: "通义千问 Max", Type: "llm", SortOrder: 100, Capabilities: []string{"text"}},
-	{ProviderID: "qwen", ModelID: "qwen-plus", Name: "通义千问 Plus", Type: "llm", SortOrder: 101, Capabilities: []string{"text"}},
-	{ProviderID: "qwen", ModelID: "qwen-flash", Name: "通义千问 Flash", Type: "llm", SortOrder: 102, Capabilities: []string{"text"}},
+	{ProviderID: "qwen", ModelID: "qwen-plus", Name: "通义千问 Plus", Type: "llm", SortOrder: 101, Capabilities: []string{"text"}, SupportsReasoning: true},
+	{ProviderID: "qwen", ModelID: "qwen-flash", Name: "通义千问 Flash", Type: "llm", SortOrder: 102, Capabilities: []string{"text"}, SupportsReasoning: true},
 	{ProviderID: "qwen", ModelID: "qwen-long", Name: "通义千问 Long", Type: "llm", SortOrder: 103, Capabilities: []string{"text"}},
 	{ProviderID: "qwen", ModelID: "qwen3-vl-plus", Name: "通义千问 vl Plus", Type: "llm", SortOrder: 103, Capabilities: []string{"text", "image"}},
 	{ProviderID: "qwen", ModelID: "qwen3-vl-flash", Name: "通义千问 vl flash", Type: "llm", SortOrder: 103, Capabilities: []string{"text", "image"}},
@@ -96,7 +103,7 @@ var BuiltinModels = []BuiltinModelConfig{
 	{ProviderID: "qwen", ModelID: "qwen3-rerank", Name: "Qwen3 Rerank", Type: "rerank", SortOrder: 100, Capabilities: []string{"text"}},
 
 	// 百度文心
-	{ProviderID: "baidu", ModelID: "ernie-5.0-thinking-latest", Name: "ERNIE 5.0", Type: "llm", SortOrder: 100, Capabilities: []string{"text", "image"}},
+	{ProviderID: "baidu", ModelID: "ernie-5.0-thinking-latest", Name: "ERNIE 5.0", Type: "llm", SortOrder: 100, Capabilities: []string{"text", "image"}, SupportsReasoning: true},
 	{ProviderID: "baidu", ModelID: "ernie-4.5-turbo-vl-32k", Name: "ERNIE 4.5 Turbo VL", Type: "llm", SortOrder: 101, Capabilities: []string{"text", "image"}},
 	{ProviderID: "baidu", ModelID: "ernie-speed-pro-128k", Name: "ERNIE Speed", Type: "llm", SortOrder: 102, Capabilities: []string{"text"}},
 	{ProviderID: "baidu", ModelID: "ernie-lite-pro-128k", Name: "ERNIE Lite", Type: "llm", SortOrder: 103, Capabilities: []string{"text"}},
@@ -108,14 +115,14 @@ var BuiltinModels = []BuiltinModelConfig{
 	{ProviderID: "doubao", ModelID: "doubao-1.5-vision-lite", Name: "Doubao 1.5 Vision Lite", Type: "llm", SortOrder: 103, Capabilities: []string{"text", "image"}},
 
 	// Grok
-	{ProviderID: "grok", ModelID: "grok-4-1-fast-reasoning", Name: "Grok 4.1 Fast Reasoning", Type: "llm", SortOrder: 100, Capabilities: []string{"text", "image"}},
-	{ProviderID: "grok", ModelID: "grok-4-1-fast-reasoning-pro", Name: "Grok 4.1 Fast Reasoning Pro", Type: "llm", SortOrder: 101, Capabilities: []string{"text", "image"}},
-	{ProviderID: "grok", ModelID: "grok-4-fast-reasoning", Name: "Grok 4 Fast Reasoning", Type: "llm", SortOrder: 102, Capabilities: []string{"text", "image"}},
+	{ProviderID: "grok", ModelID: "grok-4-1-fast-reasoning", Name: "Grok 4.1 Fast Reasoning", Type: "llm", SortOrder: 100, Capabilities: []string{"text", "image"}, SupportsReasoning: true},
+	{ProviderID: "grok", ModelID: "grok-4-1-fast-reasoning-pro", Name: "Grok 4.1 Fast Reasoning Pro", Type: "llm", SortOrder: 101, Capabilities: []string{"text", "image"}, SupportsReasoning: true},
+	{ProviderID: "grok", ModelID: "grok-4-fast-reasoning", Name: "Grok 4 Fast Reasoning", Type: "llm", SortOrder: 102, Capabilities: []string{"text", "image"}, SupportsReasoning: true},
 	{ProviderID: "grok", ModelID: "grok-4-fast-non-reasoning", Name: "Grok 4 Fast Non-Reasoning", Type: "llm", SortOrder: 103, Capabilities: []string{"text", "image"}},
 
 	// MiniMax
-	{ProviderID: "minimax", ModelID: "MiniMax-M2.7", Name: "MiniMax-M2.7", Type: "llm", SortOrder: 100, Capabilities: []string{"text"}},
-	{ProviderID: "minimax", ModelID: "MiniMax-M2.7-highspeed", Name: "MiniMax-M2.7-highspeed", Type: "llm", SortOrder: 101, Capabilities: []string{"text"}},
+	{ProviderID: "minimax", ModelID: "MiniMax-M2.7", Name: "MiniMax-M2.7", Type: "llm", SortOrder: 100, Capabilities: []string{"text"}, SupportsReasoning: true},
+	{ProviderID: "minimax", ModelID: "MiniMax-M2.7-highspeed", Name: "MiniMax-M2.7-highspeed", Type: "llm", SortOrder: 101, Capabilities: []string{"text"}, SupportsReasoning: true},
 }
 
 // GetBuiltinProviderDefaultEndpoint 获取内置供应商的默认 API 地址
@@ -131,6 +138,16 @@ func GetBuiltinProviderDefaultEndpoint(providerID string) (string, bool) {
 	return "", false
 }
 
+// GetBuiltinModel 按 provider_id + model_id 查找内置模型
+func GetBuiltinModel(providerID, modelID string) (BuiltinModelConfig, bool) {
+	for _, m := range BuiltinModels {
+		if m.ProviderID == providerID && m.ModelID == modelID {
+			return m, true
+		}
+	}
+	return BuiltinModelConfig{}, false
+}
+
 // GetBuiltinModelCapabilities 获取内置模型的默认能力
 func GetBuiltinModelCapabilities(providerID, modelID string) []string {
 	for _, m := range BuiltinModels {
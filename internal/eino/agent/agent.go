@@ -40,6 +40,39 @@ import (
 // UnlimitedIterations removes the ReAct loop iteration limit (eino defaults to 20).
 var UnlimitedIterations = math.MaxInt32
 
+// IsMaxIterationsError reports whether a run stopped because the ReAct loop hit Config.MaxIterations.
+func IsMaxIterationsError(err error) bool {
+	return errors.Is(err, adk.ErrExceedMaxIterations)
+}
+
+// Tool error policies for Config.OnToolError.
+const (
+	ToolErrorContinue = "continue" // feed the error back to the model as the tool result (default)
+	ToolErrorAbort    = "abort"    // stop the run on the first failing tool call
+)
+
+// ToolAbortError is returned by a run stopped by the ToolErrorAbort policy.
+type ToolAbortError struct {
+	Tool string
+	Err  error
+}
+
+func (e *ToolAbortError) Error() string {
+	return fmt.Sprintf("tool %q failed: %v", e.Tool, e.Err)
+}
+
+func (e *ToolAbortError) Unwrap() error { return e.Err }
+
+// AsToolAbortError reports whether a run stopped because a tool failed under the ToolErrorAbort
+// policy, returning the innermost failing tool.
+func AsToolAbortError(err error) (*ToolAbortError, bool) {
+	var abortErr *ToolAbortError
+	if errors.As(err, &abortErr) {
+		return abortErr, true
+	}
+	return nil, false
+}
+
 const (
 	einoMetaDir    = ".eino"            // per-session metadata directory under WorkDir
 	sessionsSubdir = "sessions"         // subdirectory for per-agent/conversation working dirs
@@ -75,6 +108,7 @@ type Config struct {
 	ContextCount   int  // Max messages in context (0 or >=200 = unlimited)
 	RetrievalTopK  int  // Max document chunks to retrieve
 	EnableThinking bool // Thinking mode (for providers that support it)
+	ThinkingBudget int  // Thinking token budget (conversations.thinking_budget, else agents.thinking_budget); 0 = provider default
 
 	SandboxMode    string // "codex" or "native"
 	SandboxNetwork bool   // Allow network access in sandbox
@@ -83,8 +117,11 @@ type Config struct {
 	AgentID        int64 // Agent database ID (used to generate session subdirectory)
 	ConversationID int64 // Conversation database ID (used to generate session subdirectory)
 
-	ToolchainBinDir string // Directory containing managed tool binaries (uv, bun, etc.)
-	SkillsEnabled   bool   // Global skills toggle from settings
+	ToolchainBinDir string   // Directory containing managed tool binaries (uv, bun, etc.)
+	SkillsEnabled   bool     // Global skills toggle from settings
+	EnabledTools    []string // Tool filter from agents.enabled_tools (see tools.ToolAllowed); empty = all
+	MaxIterations   int      // Lead agent ReAct iteration limit (conversations.max_iterations, else agents.max_tool_iterations); 0 = unlimited
+	OnToolError     string   // agents.on_tool_error: ToolErrorContinue (default) or ToolErrorAbort
 
 	IMGateway          *channels.Gateway // Gateway for IM tools (nil = no IM tools)
 	IMDefaultChannelID int64             // Auto-filled from channel source context (0 = not set)
@@ -135,6 +172,7 @@ func NewChatModelAgent(ctx context.Context, config Config, toolRegistry *tools.T
 	subAgentTools = append(subAgentTools, browserTool)
 	subAgentTools = append(subAgentTools, NewConfirmExecutionTool())
 	subAgentTools = append(subAgentTools, extraTools...)
+	subAgentTools = filterEnabledTools(subAgentTools, config.EnabledTools)
 
 	// Prepare skill resources
 	var skillBackend *filteringSkillBackend
@@ -166,7 +204,7 @@ func NewChatModelAgent(ctx context.Context, config Config, toolRegistry *tools.T
 	toolsConfig := adk.ToolsConfig{
 		ToolsNodeConfig: compose.ToolsNodeConfig{
 			Tools:               leadTools,
-			ToolCallMiddlewares: []compose.ToolMiddleware{ErrorCatchingToolMiddleware(leadTools, logger)},
+			ToolCallMiddlewares: []compose.ToolMiddleware{ErrorCatchingToolMiddleware(leadTools, config.OnToolError == ToolErrorAbort, logger)},
 			UnknownToolsHandler: unknownToolsHandler(leadTools, logger),
 		},
 		EmitInternalEvents: true,
@@ -174,6 +212,11 @@ func NewChatModelAgent(ctx context.Context, config Config, toolRegistry *tools.T
 
 	handlers := buildHandlers(ctx, backend, config, chatModel, extraHandlers, logger, messageCount)
 
+	maxIterations := UnlimitedIterations
+	if config.MaxIterations > 0 {
+		maxIterations = config.MaxIterations
+	}
+
 	agent, err := adk.NewChatModelAgent(ctx, &adk.ChatModelAgentConfig{
 		Name:          config.Name,
 		Description:   "AI Assistant",
@@ -181,7 +224,7 @@ func NewChatModelAgent(ctx context.Context, config Config, toolRegistry *tools.T
 		Model:         chatModel,
 		ToolsConfig:   toolsConfig,
 		Handlers:      handlers,
-		MaxIterations: UnlimitedIterations,
+		MaxIterations: maxIterations,
 	})
 	if err != nil {
 		browserTool.Close()
@@ -196,6 +239,26 @@ func NewChatModelAgent(ctx context.Context, config Config, toolRegistry *tools.T
 	}, nil
 }
 
+// filterEnabledTools drops configurable built-in tools rejected by the agent's
+// tool filter (see tools.ToolAllowed); non-configurable tools are always kept.
+func filterEnabledTools(all []tool.BaseTool, enabled []string) []tool.BaseTool {
+	if len(enabled) == 0 {
+		return all
+	}
+	result := make([]tool.BaseTool, 0, len(all))
+	for _, t := range all {
+		info, err := t.Info(context.Background())
+		if err != nil || info == nil {
+			continue
+		}
+		if tools.IsConfigurableTool(info.Name) && !tools.ToolAllowed(info.Name, enabled) {
+			continue
+		}
+		result = append(result, t)
+	}
+	return result
+}
+
 // buildLeadAgentTools selects the minimal read-only toolset for the lead agent.
 // The lead agent is an orchestrator — it delegates execution to sub-agents.
 // It only keeps tools needed for quick reads and lightweight management.
@@ -638,7 +701,9 @@ func isInterruptErr(err error) bool {
 // the model can self-correct. After 3 consecutive failures on the same tool,
 // a suggestion to try an alternative approach is appended.
 // Interrupt signals are not caught — they must propagate to the ADK framework.
-func ErrorCatchingToolMiddleware(allTools []tool.BaseTool, logger *slog.Logger) compose.ToolMiddleware {
+// With abortOnError, failures are returned as *ToolAbortError instead, which ends the run
+// (a sub-agent reaching its iteration limit is still reported to the model).
+func ErrorCatchingToolMiddleware(allTools []tool.BaseTool, abortOnError bool, logger *slog.Logger) compose.ToolMiddleware {
 	schemaMap := buildToolSchemaMap(allTools)
 	var failureCounters sync.Map // tool name -> *int32
 
@@ -671,6 +736,19 @@ func ErrorCatchingToolMiddleware(allTools []tool.BaseTool, logger *slog.Logger)
 		return msg
 	}
 
+	abortErr := func(toolName string, err error) error {
+		if !abortOnError {
+			return nil
+		}
+		if errors.Is(err, adk.ErrExceedMaxIterations) && subAgentNames[toolName] {
+			return nil
+		}
+		if _, ok := AsToolAbortError(err); ok {
+			return err
+		}
+		return &ToolAbortError{Tool: toolName, Err: err}
+	}
+
 	resetCounter := func(toolName string) {
 		if counterVal, ok := failureCounters.Load(toolName); ok {
 			atomic.StoreInt32(counterVal.(*int32), 0)
@@ -686,6 +764,9 @@ func ErrorCatchingToolMiddleware(allTools []tool.BaseTool, logger *slog.Logger)
 						return nil, err
 					}
 					logger.Warn("[agent] tool error", "tool", input.Name, "error", err)
+					if abort := abortErr(input.Name, err); abort != nil {
+						return nil, abort
+					}
 					return &compose.ToolOutput{Result: formatError(input.Name, err)}, nil
 				}
 				resetCounter(input.Name)
@@ -700,6 +781,9 @@ func ErrorCatchingToolMiddleware(allTools []tool.BaseTool, logger *slog.Logger)
 						return nil, err
 					}
 					logger.Warn("[agent] streaming tool error", "tool", input.Name, "error", err)
+					if abort := abortErr(input.Name, err); abort != nil {
+						return nil, abort
+					}
 					return &compose.StreamToolOutput{
 						Result: schema.StreamReaderFromArray([]string{formatError(input.Name, err)}),
 					}, nil
@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"log/slog"
 
+	"chatclaw/internal/eino/chatmodel"
 	"chatclaw/internal/errs"
+	"chatclaw/internal/httpclient"
 
 	"github.com/cloudwego/eino-ext/components/model/claude"
 	einogemini "github.com/cloudwego/eino-ext/components/model/gemini"
@@ -35,6 +37,46 @@ func applyOpenAIModelParams(cfg *openai.ChatModelConfig, config Config) {
 	}
 }
 
+// Reasoning effort thresholds used to map ThinkingBudget onto providers that only take a level.
+const (
+	lowEffortMaxBudget    = 4096
+	mediumEffortMaxBudget = 16384
+)
+
+// reasoningEffortForBudget maps a thinking token budget to an OpenAI reasoning effort; 0 keeps "medium".
+func reasoningEffortForBudget(budget int) string {
+	switch {
+	case budget <= 0:
+		return "medium"
+	case budget <= lowEffortMaxBudget:
+		return "low"
+	case budget <= mediumEffortMaxBudget:
+		return "medium"
+	default:
+		return "high"
+	}
+}
+
+// Anthropic's minimum thinking budget, and the room left for the answer when max_tokens is raised.
+const (
+	claudeMinThinkingBudget = 1024
+	claudeAnswerTokens      = 4096
+)
+
+// claudeThinking returns the thinking config for Claude and the max_tokens to use with it.
+// Anthropic requires budget_tokens >= 1024 and below max_tokens, so max_tokens is raised
+// when the budget would not leave room for the answer.
+func claudeThinking(budget, maxTokens int) (*claude.Thinking, int) {
+	if budget <= 0 {
+		return &claude.Thinking{Enable: true, BudgetTokens: maxTokens}, maxTokens
+	}
+	budget = max(budget, claudeMinThinkingBudget)
+	if maxTokens <= budget {
+		maxTokens = budget + claudeAnswerTokens
+	}
+	return &claude.Thinking{Enable: true, BudgetTokens: budget}, maxTokens
+}
+
 // CreateChatModel creates a ToolCallingChatModel based on the provider type.
 func CreateChatModel(ctx context.Context, config Config) (model.ToolCallingChatModel, error) {
 	chatModelLogger().Info("[chatmodel] CreateChatModel start",
@@ -44,11 +86,14 @@ func CreateChatModel(ctx context.Context, config Config) (model.ToolCallingChatM
 		"api_endpoint", config.Provider.APIEndpoint,
 		"api_key_len", len(config.Provider.APIKey),
 		"enable_thinking", config.EnableThinking,
+		"thinking_budget", config.ThinkingBudget,
 	)
 
 	switch config.Provider.Type {
 	case "openai":
 		return createOpenAIChatModel(ctx, config)
+	case chatmodel.ProviderTypeOpenAIResponses:
+		return createOpenAIResponsesChatModel(ctx, config)
 	case "azure":
 		return createAzureChatModel(ctx, config)
 	case "anthropic":
@@ -66,9 +111,10 @@ func CreateChatModel(ctx context.Context, config Config) (model.ToolCallingChatM
 
 func createOpenAIChatModel(ctx context.Context, config Config) (model.ToolCallingChatModel, error) {
 	cfg := &openai.ChatModelConfig{
-		APIKey:  config.Provider.APIKey,
-		Model:   config.ModelID,
-		BaseURL: config.Provider.APIEndpoint,
+		APIKey:     config.Provider.APIKey,
+		Model:      config.ModelID,
+		BaseURL:    config.Provider.APIEndpoint,
+		HTTPClient: httpclient.Shared(),
 	}
 	chatModelLogger().Info("[chatmodel] create openai config",
 		"provider_id", config.Provider.ProviderID,
@@ -83,6 +129,10 @@ func createOpenAIChatModel(ctx context.Context, config Config) (model.ToolCallin
 			cfg.ExtraFields = make(map[string]any)
 		}
 		cfg.ExtraFields["enable_thinking"] = true
+		if config.ThinkingBudget > 0 {
+			// OpenAI-compatible reasoning endpoints (e.g. DashScope, SiliconFlow) read thinking_budget
+			cfg.ExtraFields["thinking_budget"] = config.ThinkingBudget
+		}
 	}
 
 	chatModel, err := openai.NewChatModel(ctx, cfg)
@@ -92,6 +142,29 @@ func createOpenAIChatModel(ctx context.Context, config Config) (model.ToolCallin
 	return chatModel, nil
 }
 
+func createOpenAIResponsesChatModel(ctx context.Context, config Config) (model.ToolCallingChatModel, error) {
+	cfg := &chatmodel.ResponsesConfig{
+		APIKey:  config.Provider.APIKey,
+		Model:   config.ModelID,
+		BaseURL: config.Provider.APIEndpoint,
+	}
+	if config.EnableTemp && config.Temperature != nil {
+		temp := float32(*config.Temperature)
+		cfg.Temperature = &temp
+	}
+	if config.EnableTopP && config.TopP != nil {
+		topP := float32(*config.TopP)
+		cfg.TopP = &topP
+	}
+	if config.EnableMaxTokens && config.MaxTokens != nil {
+		cfg.MaxOutputTokens = config.MaxTokens
+	}
+	if config.EnableThinking {
+		cfg.ReasoningEffort = reasoningEffortForBudget(config.ThinkingBudget)
+	}
+	return chatmodel.NewResponsesChatModel(ctx, cfg)
+}
+
 func createAzureChatModel(ctx context.Context, config Config) (model.ToolCallingChatModel, error) {
 	var extraConfig struct {
 		APIVersion string `json:"api_version"`
@@ -114,6 +187,7 @@ func createAzureChatModel(ctx context.Context, config Config) (model.ToolCalling
 		BaseURL:    config.Provider.APIEndpoint,
 		ByAzure:    true,
 		APIVersion: extraConfig.APIVersion,
+		HTTPClient: httpclient.Shared(),
 	}
 	applyOpenAIModelParams(cfg, config)
 
@@ -122,6 +196,9 @@ func createAzureChatModel(ctx context.Context, config Config) (model.ToolCalling
 			cfg.ExtraFields = make(map[string]any)
 		}
 		cfg.ExtraFields["enable_thinking"] = true
+		if config.ThinkingBudget > 0 {
+			cfg.ExtraFields["thinking_budget"] = config.ThinkingBudget
+		}
 	}
 
 	return openai.NewChatModel(ctx, cfg)
@@ -134,9 +211,10 @@ func createClaudeChatModel(ctx context.Context, config Config) (model.ToolCallin
 	}
 
 	cfg := &claude.Config{
-		APIKey:  config.Provider.APIKey,
-		Model:   config.ModelID,
-		BaseURL: baseURL,
+		APIKey:     config.Provider.APIKey,
+		Model:      config.ModelID,
+		BaseURL:    baseURL,
+		HTTPClient: httpclient.Shared(),
 	}
 
 	if config.EnableTemp && config.Temperature != nil {
@@ -153,18 +231,21 @@ func createClaudeChatModel(ctx context.Context, config Config) (model.ToolCallin
 		cfg.MaxTokens = 4096
 	}
 	if config.EnableThinking {
-		cfg.Thinking = &claude.Thinking{
-			Enable:       true,
-			BudgetTokens: cfg.MaxTokens,
-		}
+		cfg.Thinking, cfg.MaxTokens = claudeThinking(config.ThinkingBudget, cfg.MaxTokens)
 	}
 
 	return claude.NewChatModel(ctx, cfg)
 }
 
 func createGeminiChatModel(ctx context.Context, config Config) (model.ToolCallingChatModel, error) {
+	safetySettings, err := parseGeminiSafetySettings(config.Provider.ExtraConfig)
+	if err != nil {
+		return nil, errs.Wrap("error.chat_invalid_extra_config", err)
+	}
+
 	clientConfig := &genai.ClientConfig{
-		APIKey: config.Provider.APIKey,
+		APIKey:     config.Provider.APIKey,
+		HTTPClient: httpclient.Shared(),
 	}
 	if config.Provider.APIEndpoint != "" {
 		clientConfig.HTTPOptions = genai.HTTPOptions{
@@ -178,8 +259,9 @@ func createGeminiChatModel(ctx context.Context, config Config) (model.ToolCallin
 	}
 
 	cfg := &einogemini.Config{
-		Client: client,
-		Model:  config.ModelID,
+		Client:         client,
+		Model:          config.ModelID,
+		SafetySettings: safetySettings,
 	}
 
 	if config.EnableTemp && config.Temperature != nil {
@@ -190,27 +272,40 @@ func createGeminiChatModel(ctx context.Context, config Config) (model.ToolCallin
 		topP := float32(*config.TopP)
 		cfg.TopP = &topP
 	}
+	if config.EnableMaxTokens && config.MaxTokens != nil {
+		cfg.MaxTokens = config.MaxTokens
+	}
 	if config.EnableThinking {
 		cfg.ThinkingConfig = &genai.ThinkingConfig{
 			IncludeThoughts: true,
 		}
+		if config.ThinkingBudget > 0 {
+			budget := int32(config.ThinkingBudget)
+			cfg.ThinkingConfig.ThinkingBudget = &budget
+		}
 	}
 
-	return einogemini.NewChatModel(ctx, cfg)
+	chatModel, err := einogemini.NewChatModel(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return newGeminiChatModel(chatModel, config.Instruction), nil
 }
 
 func createOllamaChatModel(ctx context.Context, config Config) (model.ToolCallingChatModel, error) {
 	cfg := &ollama.ChatModelConfig{
-		BaseURL: config.Provider.APIEndpoint,
-		Model:   config.ModelID,
+		BaseURL:    config.Provider.APIEndpoint,
+		Model:      config.ModelID,
+		HTTPClient: httpclient.Shared(),
 	}
 	return ollama.NewChatModel(ctx, cfg)
 }
 
 func createQwenChatModel(ctx context.Context, config Config) (model.ToolCallingChatModel, error) {
 	cfg := &qwen.ChatModelConfig{
-		APIKey: config.Provider.APIKey,
-		Model:  config.ModelID,
+		APIKey:     config.Provider.APIKey,
+		Model:      config.ModelID,
+		HTTPClient: httpclient.Shared(),
 	}
 	if config.Provider.APIEndpoint != "" {
 		cfg.BaseURL = config.Provider.APIEndpoint
@@ -228,6 +323,7 @@ func createQwenChatModel(ctx context.Context, config Config) (model.ToolCallingC
 		cfg.MaxTokens = config.MaxTokens
 	}
 
+	// The qwen client has no thinking budget parameter; ThinkingBudget applies via the OpenAI-compatible endpoint.
 	enableThinking := config.EnableThinking
 	cfg.EnableThinking = &enableThinking
 
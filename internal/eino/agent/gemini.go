@@ -0,0 +1,180 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+	"google.golang.org/genai"
+)
+
+// geminiExtraConfig is the Gemini part of a provider's extra_config, e.g.
+//
+//	{"safety_settings": [{"category": "HARM_CATEGORY_HARASSMENT", "threshold": "BLOCK_NONE"}]}
+//
+// Categories and thresholds may also be given without their prefix and in lower case
+// ("harassment", "block_none", "off").
+type geminiExtraConfig struct {
+	SafetySettings []struct {
+		Category  string `json:"category"`
+		Threshold string `json:"threshold"`
+	} `json:"safety_settings"`
+}
+
+var geminiHarmCategories = map[string]genai.HarmCategory{
+	"HARASSMENT":        genai.HarmCategoryHarassment,
+	"HATE_SPEECH":       genai.HarmCategoryHateSpeech,
+	"SEXUALLY_EXPLICIT": genai.HarmCategorySexuallyExplicit,
+	"DANGEROUS_CONTENT": genai.HarmCategoryDangerousContent,
+	"CIVIC_INTEGRITY":   genai.HarmCategoryCivicIntegrity,
+}
+
+var geminiHarmThresholds = map[string]genai.HarmBlockThreshold{
+	"BLOCK_LOW_AND_ABOVE":    genai.HarmBlockThresholdBlockLowAndAbove,
+	"BLOCK_MEDIUM_AND_ABOVE": genai.HarmBlockThresholdBlockMediumAndAbove,
+	"BLOCK_ONLY_HIGH":        genai.HarmBlockThresholdBlockOnlyHigh,
+	"BLOCK_NONE":             genai.HarmBlockThresholdBlockNone,
+	"OFF":                    genai.HarmBlockThresholdOff,
+}
+
+// parseGeminiSafetySettings reads safety_settings from a Gemini provider's extra_config.
+// An empty extra_config or one without safety_settings keeps Gemini's own defaults.
+func parseGeminiSafetySettings(extraConfig string) ([]*genai.SafetySetting, error) {
+	if strings.TrimSpace(extraConfig) == "" {
+		return nil, nil
+	}
+	var cfg geminiExtraConfig
+	if err := json.Unmarshal([]byte(extraConfig), &cfg); err != nil {
+		return nil, err
+	}
+
+	settings := make([]*genai.SafetySetting, 0, len(cfg.SafetySettings))
+	for _, s := range cfg.SafetySettings {
+		name := strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(s.Category)), "HARM_CATEGORY_")
+		category, ok := geminiHarmCategories[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown gemini safety category %q", s.Category)
+		}
+		threshold, ok := geminiHarmThresholds[strings.ToUpper(strings.TrimSpace(s.Threshold))]
+		if !ok {
+			return nil, fmt.Errorf("unknown gemini safety threshold %q", s.Threshold)
+		}
+		settings = append(settings, &genai.SafetySetting{Category: category, Threshold: threshold})
+	}
+	return settings, nil
+}
+
+// geminiChatModel adapts the eino Gemini model to how ChatClaw builds its input:
+//
+//   - The eino model only sends the first message as Gemini's system instruction; any other
+//     system message (retrieval context, memory) is sent as a user turn. All system messages
+//     are merged into one leading system message, falling back to the agent instruction when
+//     the input has none.
+//   - Gemini reports cumulative usage on every stream chunk, while the stream loops sum
+//     per-chunk usage. Stream chunks are rewritten to carry the increment only.
+type geminiChatModel struct {
+	inner       model.ToolCallingChatModel
+	instruction string
+}
+
+func newGeminiChatModel(inner model.ToolCallingChatModel, instruction string) *geminiChatModel {
+	return &geminiChatModel{inner: inner, instruction: instruction}
+}
+
+func (m *geminiChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	return m.inner.Generate(ctx, m.withSystemInstruction(input), opts...)
+}
+
+func (m *geminiChatModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	sr, err := m.inner.Stream(ctx, m.withSystemInstruction(input), opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var prompt, completion, total int
+	return schema.StreamReaderWithConvert(sr, func(msg *schema.Message) (*schema.Message, error) {
+		if msg == nil || msg.ResponseMeta == nil || msg.ResponseMeta.Usage == nil {
+			return msg, nil
+		}
+		u := *msg.ResponseMeta.Usage
+		delta := u
+		delta.PromptTokens = max(u.PromptTokens-prompt, 0)
+		delta.CompletionTokens = max(u.CompletionTokens-completion, 0)
+		delta.TotalTokens = max(u.TotalTokens-total, 0)
+		prompt, completion, total = max(prompt, u.PromptTokens), max(completion, u.CompletionTokens), max(total, u.TotalTokens)
+
+		out := *msg
+		meta := *msg.ResponseMeta
+		meta.Usage = &delta
+		out.ResponseMeta = &meta
+		return &out, nil
+	}), nil
+}
+
+func (m *geminiChatModel) WithTools(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	inner, err := m.inner.WithTools(tools)
+	if err != nil {
+		return nil, err
+	}
+	return newGeminiChatModel(inner, m.instruction), nil
+}
+
+// GetType and IsCallbacksEnabled forward to the eino model so callbacks are not run twice.
+func (m *geminiChatModel) GetType() string {
+	if t, ok := m.inner.(interface{ GetType() string }); ok {
+		return t.GetType()
+	}
+	return "Gemini"
+}
+
+func (m *geminiChatModel) IsCallbacksEnabled() bool {
+	if c, ok := m.inner.(interface{ IsCallbacksEnabled() bool }); ok {
+		return c.IsCallbacksEnabled()
+	}
+	return false
+}
+
+// withSystemInstruction returns input with every system message merged into one leading
+// system message. Input without any other message is left as is: the eino model only lifts
+// the system message out when something follows it, and Gemini rejects empty turns.
+func (m *geminiChatModel) withSystemInstruction(input []*schema.Message) []*schema.Message {
+	var system []string
+	rest := make([]*schema.Message, 0, len(input))
+	for _, msg := range input {
+		if msg == nil {
+			continue
+		}
+		if msg.Role == schema.System {
+			if text := strings.TrimSpace(msg.Content); text != "" {
+				system = append(system, text)
+			}
+			continue
+		}
+		rest = append(rest, msg)
+	}
+	if len(system) == 0 && strings.TrimSpace(m.instruction) != "" {
+		system = append(system, strings.TrimSpace(m.instruction))
+	}
+	if len(rest) == 0 {
+		return input
+	}
+	if len(system) == 0 {
+		return rest
+	}
+
+	out := make([]*schema.Message, 0, len(rest)+1)
+	out = append(out, schema.SystemMessage(strings.Join(system, "\n\n")))
+	return append(out, rest...)
+}
+
+var _ model.ToolCallingChatModel = (*geminiChatModel)(nil)
+
+// IsGeminiBlockedError reports whether a Gemini call failed because the response had no
+// candidates, which is what the eino model returns when the prompt itself was blocked
+// (promptFeedback.blockReason, usually a default-blocked safety category).
+func IsGeminiBlockedError(providerType string, err error) bool {
+	return providerType == "gemini" && err != nil && strings.Contains(err.Error(), "gemini result is empty")
+}
@@ -243,7 +243,7 @@ func newGeneralPurposeSubAgent(
 		ToolsConfig: adk.ToolsConfig{
 			ToolsNodeConfig: compose.ToolsNodeConfig{
 				Tools:               tools,
-				ToolCallMiddlewares: []compose.ToolMiddleware{ErrorCatchingToolMiddleware(tools, logger)},
+				ToolCallMiddlewares: []compose.ToolMiddleware{ErrorCatchingToolMiddleware(tools, config.OnToolError == ToolErrorAbort, logger)},
 			},
 		},
 		Handlers:      handlers,
@@ -356,7 +356,7 @@ func newBashSubAgent(
 		ToolsConfig: adk.ToolsConfig{
 			ToolsNodeConfig: compose.ToolsNodeConfig{
 				Tools:               bashTools,
-				ToolCallMiddlewares: []compose.ToolMiddleware{ErrorCatchingToolMiddleware(bashTools, logger)},
+				ToolCallMiddlewares: []compose.ToolMiddleware{ErrorCatchingToolMiddleware(bashTools, config.OnToolError == ToolErrorAbort, logger)},
 			},
 		},
 		Handlers:      handlers,
@@ -4,10 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"net/http"
 	"time"
 
 	"chatclaw/internal/errs"
+	"chatclaw/internal/httpclient"
 
 	"github.com/cloudwego/eino-ext/components/model/claude"
 	einogemini "github.com/cloudwego/eino-ext/components/model/gemini"
@@ -47,6 +47,13 @@ func NewChatModel(ctx context.Context, cfg *ProviderConfig) (model.ChatModel, er
 	switch cfg.ProviderType {
 	case "openai":
 		return newOpenAIChatModel(ctx, cfg)
+	case ProviderTypeOpenAIResponses:
+		return NewResponsesChatModel(ctx, &ResponsesConfig{
+			APIKey:     cfg.APIKey,
+			BaseURL:    cfg.APIEndpoint,
+			Model:      cfg.ModelID,
+			HTTPClient: httpclient.New(cfg.Timeout),
+		})
 	case "azure":
 		return newAzureChatModel(ctx, cfg)
 	case "ollama":
@@ -66,8 +73,9 @@ func NewChatModel(ctx context.Context, cfg *ProviderConfig) (model.ChatModel, er
 // newOpenAIChatModel 创建 OpenAI ChatModel
 func newOpenAIChatModel(ctx context.Context, cfg *ProviderConfig) (model.ChatModel, error) {
 	config := &openai.ChatModelConfig{
-		APIKey: cfg.APIKey,
-		Model:  cfg.ModelID,
+		APIKey:     cfg.APIKey,
+		Model:      cfg.ModelID,
+		HTTPClient: httpclient.Shared(),
 	}
 	if cfg.APIEndpoint != "" {
 		config.BaseURL = cfg.APIEndpoint
@@ -102,7 +110,7 @@ func newAzureChatModel(ctx context.Context, cfg *ProviderConfig) (model.ChatMode
 		BaseURL:    cfg.APIEndpoint,
 		ByAzure:    true,
 		APIVersion: extraConfig.APIVersion,
-		Timeout:    cfg.Timeout,
+		HTTPClient: httpclient.New(cfg.Timeout),
 	}
 	return openai.NewChatModel(ctx, config)
 }
@@ -115,8 +123,9 @@ func newOllamaChatModel(ctx context.Context, cfg *ProviderConfig) (model.ChatMod
 	}
 
 	config := &ollama.ChatModelConfig{
-		BaseURL: baseURL,
-		Model:   cfg.ModelID,
+		BaseURL:    baseURL,
+		Model:      cfg.ModelID,
+		HTTPClient: httpclient.Shared(),
 	}
 	return ollama.NewChatModel(ctx, config)
 }
@@ -124,7 +133,8 @@ func newOllamaChatModel(ctx context.Context, cfg *ProviderConfig) (model.ChatMod
 // newGeminiChatModel 创建 Gemini ChatModel
 func newGeminiChatModel(ctx context.Context, cfg *ProviderConfig) (model.ChatModel, error) {
 	clientConfig := &genai.ClientConfig{
-		APIKey: cfg.APIKey,
+		APIKey:     cfg.APIKey,
+		HTTPClient: httpclient.Shared(),
 	}
 	if cfg.APIEndpoint != "" {
 		clientConfig.HTTPOptions = genai.HTTPOptions{
@@ -154,22 +164,20 @@ func newClaudeChatModel(ctx context.Context, cfg *ProviderConfig) (model.ChatMod
 		Model:      cfg.ModelID,
 		BaseURL:    baseURL,
 		MaxTokens:  4096,
-		HTTPClient: &http.Client{Timeout: cfg.Timeout},
+		HTTPClient: httpclient.New(cfg.Timeout),
 	})
 }
 
 // newQwenChatModel 创建 Qwen ChatModel
 func newQwenChatModel(ctx context.Context, cfg *ProviderConfig) (model.ChatModel, error) {
 	config := &qwen.ChatModelConfig{
-		APIKey: cfg.APIKey,
-		Model:  cfg.ModelID,
+		APIKey:     cfg.APIKey,
+		Model:      cfg.ModelID,
+		HTTPClient: httpclient.New(cfg.Timeout),
 	}
 	if cfg.APIEndpoint != "" {
 		config.BaseURL = cfg.APIEndpoint
 	}
-	if cfg.Timeout > 0 {
-		config.Timeout = cfg.Timeout
-	}
 	if cfg.DisableThinking {
 		disableThinking := false
 		config.EnableThinking = &disableThinking
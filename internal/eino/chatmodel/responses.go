@@ -0,0 +1,461 @@
+package chatmodel
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"chatclaw/internal/httpclient"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// ProviderTypeOpenAIResponses is the provider type for OpenAI-compatible gateways that expose the
+// Responses API (POST {base}/responses) instead of /chat/completions.
+const ProviderTypeOpenAIResponses = "openai-responses"
+
+const defaultResponsesBaseURL = "https://api.openai.com/v1"
+
+// ResponsesConfig configures a ResponsesChatModel.
+type ResponsesConfig struct {
+	APIKey  string
+	BaseURL string // defaults to https://api.openai.com/v1
+	Model   string
+
+	Temperature     *float32
+	TopP            *float32
+	MaxOutputTokens *int
+	// ReasoningEffort ("low", "medium", "high") requests reasoning; its summary is returned as
+	// ReasoningContent. Empty sends no reasoning parameter.
+	ReasoningEffort string
+
+	HTTPClient *http.Client
+}
+
+// ResponsesChatModel is a thin eino ToolCallingChatModel over the OpenAI Responses API.
+// Requests are stateless (store=false): the full history is sent on every call, like chat/completions.
+type ResponsesChatModel struct {
+	cfg   ResponsesConfig
+	tools []*schema.ToolInfo
+}
+
+var (
+	_ model.ToolCallingChatModel = (*ResponsesChatModel)(nil)
+	_ model.ChatModel            = (*ResponsesChatModel)(nil)
+)
+
+// NewResponsesChatModel creates a Responses API chat model.
+func NewResponsesChatModel(_ context.Context, cfg *ResponsesConfig) (*ResponsesChatModel, error) {
+	if cfg == nil || strings.TrimSpace(cfg.Model) == "" {
+		return nil, fmt.Errorf("responses: model is required")
+	}
+	c := *cfg
+	c.BaseURL = strings.TrimRight(strings.TrimSpace(c.BaseURL), "/")
+	if c.BaseURL == "" {
+		c.BaseURL = defaultResponsesBaseURL
+	}
+	if c.HTTPClient == nil {
+		c.HTTPClient = httpclient.Shared()
+	}
+	return &ResponsesChatModel{cfg: c}, nil
+}
+
+// WithTools returns a copy of the model with tools bound.
+func (m *ResponsesChatModel) WithTools(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	return &ResponsesChatModel{cfg: m.cfg, tools: tools}, nil
+}
+
+// BindTools binds tools in place. It satisfies model.ChatModel, which NewChatModel returns;
+// prefer WithTools, which does not mutate a model that may be shared.
+func (m *ResponsesChatModel) BindTools(tools []*schema.ToolInfo) error {
+	m.tools = tools
+	return nil
+}
+
+// --- request ---
+
+type responsesTool struct {
+	Type        string          `json:"type"`
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Parameters  json.RawMessage `json:"parameters"`
+}
+
+type responsesRequest struct {
+	Model           string           `json:"model"`
+	Instructions    string           `json:"instructions,omitempty"`
+	Input           []map[string]any `json:"input"`
+	Tools           []responsesTool  `json:"tools,omitempty"`
+	ToolChoice      string           `json:"tool_choice,omitempty"`
+	Temperature     *float32         `json:"temperature,omitempty"`
+	TopP            *float32         `json:"top_p,omitempty"`
+	MaxOutputTokens *int             `json:"max_output_tokens,omitempty"`
+	Reasoning       map[string]any   `json:"reasoning,omitempty"`
+	Stream          bool             `json:"stream"`
+	Store           bool             `json:"store"`
+}
+
+func (m *ResponsesChatModel) buildRequest(input []*schema.Message, stream bool, opts ...model.Option) (*responsesRequest, error) {
+	options := model.GetCommonOptions(&model.Options{
+		Temperature: m.cfg.Temperature,
+		TopP:        m.cfg.TopP,
+		MaxTokens:   m.cfg.MaxOutputTokens,
+		Tools:       m.tools,
+	}, opts...)
+
+	req := &responsesRequest{
+		Model:           m.cfg.Model,
+		Temperature:     options.Temperature,
+		TopP:            options.TopP,
+		MaxOutputTokens: options.MaxTokens,
+		Stream:          stream,
+	}
+	if options.Model != nil && *options.Model != "" {
+		req.Model = *options.Model
+	}
+	if m.cfg.ReasoningEffort != "" {
+		req.Reasoning = map[string]any{"effort": m.cfg.ReasoningEffort, "summary": "auto"}
+	}
+
+	var instructions []string
+	for _, msg := range input {
+		if msg == nil {
+			continue
+		}
+		switch msg.Role {
+		case schema.System:
+			instructions = append(instructions, msg.Content)
+		case schema.Tool:
+			req.Input = append(req.Input, map[string]any{
+				"type":    "function_call_output",
+				"call_id": msg.ToolCallID,
+				"output":  msg.Content,
+			})
+		case schema.Assistant:
+			if msg.Content != "" {
+				req.Input = append(req.Input, map[string]any{"role": "assistant", "content": msg.Content})
+			}
+			for _, tc := range msg.ToolCalls {
+				req.Input = append(req.Input, map[string]any{
+					"type":      "function_call",
+					"call_id":   tc.ID,
+					"name":      tc.Function.Name,
+					"arguments": tc.Function.Arguments,
+				})
+			}
+		default:
+			req.Input = append(req.Input, map[string]any{"role": "user", "content": userContent(msg)})
+		}
+	}
+	req.Instructions = strings.Join(instructions, "\n\n")
+
+	for _, t := range options.Tools {
+		params := json.RawMessage(`{"type":"object","properties":{}}`)
+		if t.ParamsOneOf != nil {
+			js, err := t.ParamsOneOf.ToJSONSchema()
+			if err != nil {
+				return nil, fmt.Errorf("responses: tool %s schema: %w", t.Name, err)
+			}
+			if js != nil {
+				b, err := json.Marshal(js)
+				if err != nil {
+					return nil, fmt.Errorf("responses: tool %s schema: %w", t.Name, err)
+				}
+				params = b
+			}
+		}
+		req.Tools = append(req.Tools, responsesTool{Type: "function", Name: t.Name, Description: t.Desc, Parameters: params})
+	}
+	if len(req.Tools) > 0 && options.ToolChoice != nil {
+		switch *options.ToolChoice {
+		case schema.ToolChoiceForbidden:
+			req.ToolChoice = "none"
+		case schema.ToolChoiceForced:
+			req.ToolChoice = "required"
+		default:
+			req.ToolChoice = "auto"
+		}
+	}
+	return req, nil
+}
+
+// userContent returns a plain string, or input_text/input_image parts for multimodal messages.
+func userContent(msg *schema.Message) any {
+	if len(msg.UserInputMultiContent) == 0 {
+		return msg.Content
+	}
+	parts := make([]map[string]any, 0, len(msg.UserInputMultiContent))
+	for _, p := range msg.UserInputMultiContent {
+		switch {
+		case p.Type == schema.ChatMessagePartTypeText:
+			parts = append(parts, map[string]any{"type": "input_text", "text": p.Text})
+		case p.Type == schema.ChatMessagePartTypeImageURL && p.Image != nil:
+			var url string
+			if p.Image.URL != nil {
+				url = *p.Image.URL
+			} else if p.Image.Base64Data != nil {
+				url = "data:" + p.Image.MIMEType + ";base64," + *p.Image.Base64Data
+			}
+			if url != "" {
+				parts = append(parts, map[string]any{"type": "input_image", "image_url": url})
+			}
+		}
+	}
+	return parts
+}
+
+func (m *ResponsesChatModel) do(ctx context.Context, req *responsesRequest) (*http.Response, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, m.cfg.BaseURL+"/responses", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if m.cfg.APIKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+m.cfg.APIKey)
+	}
+	if req.Stream {
+		httpReq.Header.Set("Accept", "text/event-stream")
+	}
+
+	resp, err := m.cfg.HTTPClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 != 2 {
+		defer resp.Body.Close()
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		var apiErr struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if json.Unmarshal(b, &apiErr) == nil && apiErr.Error.Message != "" {
+			return nil, fmt.Errorf("responses: status %d: %s", resp.StatusCode, apiErr.Error.Message)
+		}
+		return nil, fmt.Errorf("responses: status %d: %s", resp.StatusCode, strings.TrimSpace(string(b)))
+	}
+	return resp, nil
+}
+
+// --- response ---
+
+type responsesUsage struct {
+	InputTokens        int `json:"input_tokens"`
+	OutputTokens       int `json:"output_tokens"`
+	TotalTokens        int `json:"total_tokens"`
+	InputTokensDetails struct {
+		CachedTokens int `json:"cached_tokens"`
+	} `json:"input_tokens_details"`
+	OutputTokensDetails struct {
+		ReasoningTokens int `json:"reasoning_tokens"`
+	} `json:"output_tokens_details"`
+}
+
+type responsesOutputItem struct {
+	Type      string `json:"type"` // message, reasoning, function_call
+	ID        string `json:"id"`
+	CallID    string `json:"call_id"`
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+	Content   []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content"`
+	Summary []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"summary"`
+}
+
+type responsesResponse struct {
+	Status            string                `json:"status"`
+	Output            []responsesOutputItem `json:"output"`
+	Usage             *responsesUsage       `json:"usage"`
+	IncompleteDetails *struct {
+		Reason string `json:"reason"`
+	} `json:"incomplete_details"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// responseMeta maps status/usage to eino's chat/completions-style finish reasons ("length" when
+// the output token limit was hit).
+func (r *responsesResponse) responseMeta(hasToolCalls bool) *schema.ResponseMeta {
+	meta := &schema.ResponseMeta{FinishReason: "stop"}
+	switch {
+	case r.Status == "incomplete" && r.IncompleteDetails != nil && r.IncompleteDetails.Reason == "max_output_tokens":
+		meta.FinishReason = "length"
+	case r.Status == "incomplete" && r.IncompleteDetails != nil && r.IncompleteDetails.Reason == "content_filter":
+		meta.FinishReason = "content_filter"
+	case hasToolCalls:
+		meta.FinishReason = "tool_calls"
+	}
+	if u := r.Usage; u != nil {
+		meta.Usage = &schema.TokenUsage{
+			PromptTokens:       u.InputTokens,
+			PromptTokenDetails: schema.PromptTokenDetails{CachedTokens: u.InputTokensDetails.CachedTokens},
+			CompletionTokens:   u.OutputTokens,
+			TotalTokens:        u.TotalTokens,
+			CompletionTokensDetails: schema.CompletionTokensDetails{
+				ReasoningTokens: u.OutputTokensDetails.ReasoningTokens,
+			},
+		}
+	}
+	return meta
+}
+
+// Generate sends a non-streaming request.
+func (m *ResponsesChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	req, err := m.buildRequest(input, false, opts...)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := m.do(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var r responsesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return nil, fmt.Errorf("responses: decode response: %w", err)
+	}
+	if r.Status == "failed" && r.Error != nil {
+		return nil, fmt.Errorf("responses: %s", r.Error.Message)
+	}
+
+	out := &schema.Message{Role: schema.Assistant}
+	var content, reasoning strings.Builder
+	for _, item := range r.Output {
+		switch item.Type {
+		case "message":
+			for _, c := range item.Content {
+				if c.Type == "output_text" {
+					content.WriteString(c.Text)
+				}
+			}
+		case "reasoning":
+			for _, s := range item.Summary {
+				reasoning.WriteString(s.Text)
+			}
+		case "function_call":
+			out.ToolCalls = append(out.ToolCalls, schema.ToolCall{
+				ID:       item.CallID,
+				Type:     "function",
+				Function: schema.FunctionCall{Name: item.Name, Arguments: item.Arguments},
+			})
+		}
+	}
+	out.Content = content.String()
+	out.ReasoningContent = reasoning.String()
+	out.ResponseMeta = r.responseMeta(len(out.ToolCalls) > 0)
+	return out, nil
+}
+
+type responsesStreamEvent struct {
+	Type        string              `json:"type"`
+	Delta       string              `json:"delta"`
+	OutputIndex int                 `json:"output_index"`
+	Item        responsesOutputItem `json:"item"`
+	Response    responsesResponse   `json:"response"`
+	Message     string              `json:"message"` // type "error"
+}
+
+// Stream sends a streaming request. Text and reasoning deltas become Content / ReasoningContent
+// chunks; function calls are streamed as tool call deltas keyed by their output index.
+func (m *ResponsesChatModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	req, err := m.buildRequest(input, true, opts...)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := m.do(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	sr, sw := schema.Pipe[*schema.Message](16)
+	go func() {
+		defer resp.Body.Close()
+		defer sw.Close()
+		if err := readResponsesStream(resp.Body, sw); err != nil {
+			sw.Send(nil, err)
+		}
+	}()
+	return sr, nil
+}
+
+func readResponsesStream(body io.Reader, sw *schema.StreamWriter[*schema.Message]) error {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64*1024), 8*1024*1024)
+
+	hasToolCalls := false
+	for scanner.Scan() {
+		line := scanner.Text()
+		data, ok := strings.CutPrefix(line, "data:")
+		if !ok {
+			continue
+		}
+		data = strings.TrimSpace(data)
+		if data == "" || data == "[DONE]" {
+			continue
+		}
+
+		var ev responsesStreamEvent
+		if err := json.Unmarshal([]byte(data), &ev); err != nil {
+			return fmt.Errorf("responses: decode stream event: %w", err)
+		}
+
+		var chunk *schema.Message
+		switch ev.Type {
+		case "response.output_text.delta":
+			chunk = &schema.Message{Role: schema.Assistant, Content: ev.Delta}
+		case "response.reasoning_summary_text.delta", "response.reasoning_text.delta":
+			chunk = &schema.Message{Role: schema.Assistant, ReasoningContent: ev.Delta}
+		case "response.output_item.added":
+			if ev.Item.Type != "function_call" {
+				continue
+			}
+			hasToolCalls = true
+			idx := ev.OutputIndex
+			chunk = &schema.Message{Role: schema.Assistant, ToolCalls: []schema.ToolCall{{
+				Index:    &idx,
+				ID:       ev.Item.CallID,
+				Type:     "function",
+				Function: schema.FunctionCall{Name: ev.Item.Name, Arguments: ev.Item.Arguments},
+			}}}
+		case "response.function_call_arguments.delta":
+			idx := ev.OutputIndex
+			chunk = &schema.Message{Role: schema.Assistant, ToolCalls: []schema.ToolCall{{
+				Index:    &idx,
+				Function: schema.FunctionCall{Arguments: ev.Delta},
+			}}}
+		case "response.completed", "response.incomplete":
+			chunk = &schema.Message{Role: schema.Assistant, ResponseMeta: ev.Response.responseMeta(hasToolCalls)}
+		case "response.failed":
+			if ev.Response.Error != nil {
+				return fmt.Errorf("responses: %s", ev.Response.Error.Message)
+			}
+			return errors.New("responses: response failed")
+		case "error":
+			return fmt.Errorf("responses: %s", ev.Message)
+		default:
+			continue
+		}
+		if closed := sw.Send(chunk, nil); closed {
+			return nil
+		}
+	}
+	return scanner.Err()
+}
@@ -10,6 +10,8 @@ import (
 	"sort"
 	"strings"
 
+	"chatclaw/internal/httpclient"
+
 	einoembedding "github.com/cloudwego/eino/components/embedding"
 )
 
@@ -22,10 +24,7 @@ type chatWikiEmbedder struct {
 }
 
 func newChatWikiEmbedder(cfg *ProviderConfig) *chatWikiEmbedder {
-	client := &http.Client{Timeout: cfg.Timeout}
-	if cfg.Timeout == 0 {
-		client = http.DefaultClient
-	}
+	client := httpclient.New(cfg.Timeout)
 	var dimension *int
 	if cfg.Dimension > 0 {
 		dimension = &cfg.Dimension
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"chatclaw/internal/errs"
+	"chatclaw/internal/httpclient"
 
 	ollamaembed "github.com/cloudwego/eino-ext/components/embedding/ollama"
 	openaiembed "github.com/cloudwego/eino-ext/components/embedding/openai"
@@ -37,6 +38,8 @@ type ProviderConfig struct {
 	ExtraConfig string
 	// Timeout 请求超时时间
 	Timeout time.Duration
+	// BatchSize 单次请求的最大文本数（0 表示 DefaultBatchSize）
+	BatchSize int
 }
 
 // NewEmbedder 根据供应商配置创建新的 Embedder
@@ -44,6 +47,10 @@ func NewEmbedder(ctx context.Context, cfg *ProviderConfig) (embedding.Embedder,
 	if cfg.Timeout == 0 {
 		cfg.Timeout = 60 * time.Second
 	}
+	batchSize := cfg.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
 
 	switch cfg.ProviderType {
 	case "openai":
@@ -51,26 +58,26 @@ func NewEmbedder(ctx context.Context, cfg *ProviderConfig) (embedding.Embedder,
 		if err != nil {
 			return nil, err
 		}
-		return WrapWithBatchLimit(emb, DefaultBatchSize), nil
+		return WrapWithBatchLimit(emb, batchSize), nil
 	case "azure":
 		emb, err := newAzureEmbedder(ctx, cfg)
 		if err != nil {
 			return nil, err
 		}
-		return WrapWithBatchLimit(emb, DefaultBatchSize), nil
+		return WrapWithBatchLimit(emb, batchSize), nil
 	case "ollama":
 		emb, err := newOllamaEmbedder(ctx, cfg)
 		if err != nil {
 			return nil, err
 		}
-		return WrapWithBatchLimit(emb, DefaultBatchSize), nil
+		return WrapWithBatchLimit(emb, batchSize), nil
 	default:
 		// 默认使用 OpenAI 兼容 API
 		emb, err := newOpenAIEmbedder(ctx, cfg)
 		if err != nil {
 			return nil, err
 		}
-		return WrapWithBatchLimit(emb, DefaultBatchSize), nil
+		return WrapWithBatchLimit(emb, batchSize), nil
 	}
 }
 
@@ -80,9 +87,9 @@ func newOpenAIEmbedder(ctx context.Context, cfg *ProviderConfig) (embedding.Embe
 		return newChatWikiEmbedder(cfg), nil
 	}
 	config := &openaiembed.EmbeddingConfig{
-		APIKey:  cfg.APIKey,
-		Model:   cfg.ModelID,
-		Timeout: cfg.Timeout,
+		APIKey:     cfg.APIKey,
+		Model:      cfg.ModelID,
+		HTTPClient: httpclient.New(cfg.Timeout),
 	}
 	if cfg.APIEndpoint != "" {
 		config.BaseURL = cfg.APIEndpoint
@@ -119,7 +126,7 @@ func newAzureEmbedder(ctx context.Context, cfg *ProviderConfig) (embedding.Embed
 		BaseURL:    cfg.APIEndpoint,
 		ByAzure:    true,
 		APIVersion: extraConfig.APIVersion,
-		Timeout:    cfg.Timeout,
+		HTTPClient: httpclient.New(cfg.Timeout),
 	}
 	return openaiembed.NewEmbedder(ctx, config)
 }
@@ -132,9 +139,9 @@ func newOllamaEmbedder(ctx context.Context, cfg *ProviderConfig) (embedding.Embe
 	}
 
 	config := &ollamaembed.EmbeddingConfig{
-		BaseURL: baseURL,
-		Model:   cfg.ModelID,
-		Timeout: cfg.Timeout,
+		BaseURL:    baseURL,
+		Model:      cfg.ModelID,
+		HTTPClient: httpclient.New(cfg.Timeout),
 	}
 	return ollamaembed.NewEmbedder(ctx, config)
 }
@@ -2,10 +2,13 @@ package docx
 
 import (
 	"context"
+	"encoding/xml"
 	"io"
 	"os"
 	"strings"
 
+	"chatclaw/internal/eino/parser/table"
+
 	"github.com/cloudwego/eino/components/document/parser"
 	"github.com/cloudwego/eino/schema"
 	"github.com/nguyenthenguyen/docx"
@@ -62,9 +65,6 @@ func (p *Parser) Parse(ctx context.Context, reader io.Reader, opts ...parser.Opt
 	docx1 := r.Editable()
 	content := docx1.GetContent()
 
-	// 清理内容 - 从 XML 中提取纯文本
-	content = extractPlainText(content)
-
 	// 构建元数据
 	metadata := make(map[string]any)
 	if commonOpts.URI != "" {
@@ -74,6 +74,16 @@ func (p *Parser) Parse(ctx context.Context, reader io.Reader, opts ...parser.Opt
 		metadata[k] = v
 	}
 
+	if table.GetOptions(opts...).Preserve {
+		if docs, err := p.splitTables(content, metadata); err == nil {
+			return docs, nil
+		}
+		// Malformed XML: fall back to flattened text
+	}
+
+	// 清理内容 - 从 XML 中提取纯文本
+	content = extractPlainText(content)
+
 	return []*schema.Document{
 		{
 			Content:  content,
@@ -125,3 +135,128 @@ func extractPlainText(xmlContent string) string {
 
 	return text
 }
+
+// splitTables walks document.xml and returns the body as alternating text and table documents.
+// Paragraphs are joined with the paragraph separator; each top-level <w:tbl> becomes one markdown
+// table document (nested tables are flattened into their cell).
+func (p *Parser) splitTables(xmlContent string, metadata map[string]any) ([]*schema.Document, error) {
+	var (
+		docs       []*schema.Document
+		paragraphs []string
+		para       strings.Builder
+		cell       strings.Builder
+		row        []string
+		rows       [][]string
+		tblDepth   int
+		inText     bool
+	)
+
+	newDoc := func(content string, isTable bool) *schema.Document {
+		meta := make(map[string]any, len(metadata)+1)
+		for k, v := range metadata {
+			meta[k] = v
+		}
+		if isTable {
+			meta[table.MetaKey] = true
+		}
+		return &schema.Document{Content: content, MetaData: meta}
+	}
+	flushText := func() {
+		if len(paragraphs) > 0 {
+			docs = append(docs, newDoc(strings.Join(paragraphs, p.paragraphSeparator), false))
+			paragraphs = nil
+		}
+	}
+	write := func(s string) {
+		if tblDepth > 0 {
+			cell.WriteString(s)
+		} else {
+			para.WriteString(s)
+		}
+	}
+
+	dec := xml.NewDecoder(strings.NewReader(xmlContent))
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "tbl":
+				tblDepth++
+				if tblDepth == 1 {
+					rows = nil
+				}
+			case "tr":
+				if tblDepth == 1 {
+					row = nil
+				}
+			case "tc":
+				if tblDepth == 1 {
+					cell.Reset()
+				}
+			case "t":
+				inText = true
+			case "tab":
+				write(" ")
+			case "br", "cr":
+				write("\n")
+			}
+		case xml.CharData:
+			if inText {
+				write(string(t))
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				if tblDepth > 0 {
+					write("\n")
+				} else {
+					if text := strings.TrimSpace(para.String()); text != "" {
+						paragraphs = append(paragraphs, text)
+					}
+					para.Reset()
+				}
+			case "tc":
+				if tblDepth == 1 {
+					row = append(row, strings.TrimSpace(cell.String()))
+				}
+			case "tr":
+				if tblDepth == 1 {
+					rows = append(rows, row)
+				}
+			case "tbl":
+				tblDepth--
+				if tblDepth == 0 && hasCellText(rows) {
+					flushText()
+					docs = append(docs, newDoc(table.Markdown(rows), true))
+				}
+			}
+		}
+	}
+	flushText()
+
+	if len(docs) == 0 {
+		docs = append(docs, newDoc("", false))
+	}
+	return docs, nil
+}
+
+func hasCellText(rows [][]string) bool {
+	for _, r := range rows {
+		for _, c := range r {
+			if c != "" {
+				return true
+			}
+		}
+	}
+	return false
+}
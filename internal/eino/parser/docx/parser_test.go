@@ -0,0 +1,90 @@
+package docx
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"chatclaw/internal/eino/parser/table"
+)
+
+const sampleDocumentXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">
+<w:body>
+<w:p><w:r><w:t>Quarterly revenue report</w:t></w:r></w:p>
+<w:tbl>
+<w:tr><w:tc><w:p><w:r><w:t>Region</w:t></w:r></w:p></w:tc><w:tc><w:p><w:r><w:t>Q1</w:t></w:r></w:p></w:tc></w:tr>
+<w:tr><w:tc><w:p><w:r><w:t>North</w:t></w:r></w:p></w:tc><w:tc><w:p><w:r><w:t>1,200</w:t></w:r></w:p></w:tc></w:tr>
+<w:tr><w:tc><w:p><w:r><w:t>South | East</w:t></w:r></w:p></w:tc><w:tc><w:p><w:r><w:t>950</w:t></w:r></w:p></w:tc></w:tr>
+</w:tbl>
+<w:p><w:r><w:t>Figures are unaudited.</w:t></w:r></w:p>
+</w:body>
+</w:document>`
+
+func sampleDocx(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	files := map[string]string{
+		"word/document.xml":            sampleDocumentXML,
+		"word/_rels/document.xml.rels": `<?xml version="1.0" encoding="UTF-8"?><Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"></Relationships>`,
+	}
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestParsePreserveTables(t *testing.T) {
+	p, err := NewParser(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	docs, err := p.Parse(context.Background(), bytes.NewReader(sampleDocx(t)), table.WithPreserve(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(docs) != 3 {
+		t.Fatalf("got %d documents, want text/table/text", len(docs))
+	}
+	if table.IsTable(docs[0]) || docs[0].Content != "Quarterly revenue report" {
+		t.Errorf("first document = %q", docs[0].Content)
+	}
+	want := "| Region | Q1 |\n| --- | --- |\n| North | 1,200 |\n| South \\| East | 950 |"
+	if !table.IsTable(docs[1]) || docs[1].Content != want {
+		t.Errorf("table document = %q, want %q", docs[1].Content, want)
+	}
+	if table.IsTable(docs[2]) || docs[2].Content != "Figures are unaudited." {
+		t.Errorf("last document = %q", docs[2].Content)
+	}
+}
+
+func TestParseWithoutPreserveTablesFlattens(t *testing.T) {
+	p, err := NewParser(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	docs, err := p.Parse(context.Background(), bytes.NewReader(sampleDocx(t)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(docs) != 1 || table.IsTable(docs[0]) {
+		t.Fatalf("got %d documents, want one flattened document", len(docs))
+	}
+	if !strings.Contains(docs[0].Content, "North 1,200") {
+		t.Errorf("flattened content = %q", docs[0].Content)
+	}
+}
@@ -5,9 +5,13 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"math"
 	"os"
+	"sort"
 	"strings"
 
+	"chatclaw/internal/eino/parser/table"
+
 	"github.com/cloudwego/eino/components/document/parser"
 	"github.com/cloudwego/eino/schema"
 	"github.com/ledongthuc/pdf"
@@ -15,7 +19,7 @@ import (
 
 // Config PDF 解析器配置
 type Config struct {
-	// ToPages 是否按页面分割文档，默认为 false
+	// ToPages 是否按页面分割文档，默认为 false（table.WithPreserve 时忽略）
 	ToPages bool
 	// PageSeparator 页面分隔符，仅在 ToPages=false 时使用，默认为 "\n\n"
 	PageSeparator string
@@ -121,6 +125,11 @@ func (p *Parser) Parse(ctx context.Context, reader io.Reader, opts ...parser.Opt
 		return docs, nil
 	}
 
+	if table.GetOptions(opts...).Preserve {
+		baseMeta["total_pages"] = numPages
+		return p.parseWithTables(r, numPages, baseMeta), nil
+	}
+
 	// 合并所有页面
 	var allText strings.Builder
 	for pageNum := 1; pageNum <= numPages; pageNum++ {
@@ -191,3 +200,226 @@ func cleanText(text string) string {
 
 	return strings.Join(result, "\n")
 }
+
+// columnTolerance is how far (in points) cell start positions may drift between rows of one table.
+const columnTolerance = 3.0
+
+// parseWithTables merges all pages like the default path, but cuts detected tables out into
+// their own markdown documents. Pages without a detected table keep the plain-text extraction.
+func (p *Parser) parseWithTables(r *pdf.Reader, numPages int, baseMeta map[string]any) []*schema.Document {
+	var docs []*schema.Document
+	var text strings.Builder
+
+	newDoc := func(content string, isTable bool) *schema.Document {
+		meta := make(map[string]any, len(baseMeta)+1)
+		for k, v := range baseMeta {
+			meta[k] = v
+		}
+		if isTable {
+			meta[table.MetaKey] = true
+		}
+		return &schema.Document{Content: content, MetaData: meta}
+	}
+	appendText := func(s string) {
+		if s = strings.TrimSpace(s); s == "" {
+			return
+		}
+		if text.Len() > 0 {
+			text.WriteString(p.pageSeparator)
+		}
+		text.WriteString(s)
+	}
+	flushText := func() {
+		if text.Len() > 0 {
+			docs = append(docs, newDoc(text.String(), false))
+			text.Reset()
+		}
+	}
+
+	for pageNum := 1; pageNum <= numPages; pageNum++ {
+		page := r.Page(pageNum)
+		if page.V.IsNull() {
+			continue
+		}
+
+		var blocks []pageBlock
+		if rows, err := pageRows(page); err == nil {
+			blocks = detectTables(rows)
+		}
+		if !hasTable(blocks) {
+			if pageText, err := extractPageText(page); err == nil {
+				appendText(pageText)
+			}
+			continue
+		}
+
+		for _, b := range blocks {
+			if b.rows == nil {
+				appendText(b.text)
+				continue
+			}
+			flushText()
+			docs = append(docs, newDoc(table.Markdown(b.rows), true))
+		}
+	}
+	flushText()
+
+	if len(docs) == 0 {
+		docs = append(docs, newDoc("", false))
+	}
+	return docs
+}
+
+// textCell is a horizontally contiguous run of glyphs on one line.
+type textCell struct {
+	X float64
+	S string
+}
+
+// pageRows groups the page glyphs into lines (top to bottom) and each line into cells, splitting
+// wherever the horizontal gap between glyphs is wider than 1.5 times the font size.
+func pageRows(page pdf.Page) (rows [][]textCell, err error) {
+	// page.Content panics on malformed content streams; report it as an error so the caller
+	// falls back to plain-text extraction for this page instead of crashing the import.
+	defer func() {
+		if r := recover(); r != nil {
+			rows = nil
+			err = fmt.Errorf("pdf page panic: %v", r)
+		}
+	}()
+
+	byLine := make(map[int64][]pdf.Text)
+	for _, t := range page.Content().Text {
+		y := int64(math.Round(t.Y))
+		byLine[y] = append(byLine[y], t)
+	}
+	ys := make([]int64, 0, len(byLine))
+	for y := range byLine {
+		ys = append(ys, y)
+	}
+	sort.Slice(ys, func(i, j int) bool { return ys[i] > ys[j] })
+
+	for _, y := range ys {
+		glyphs := byLine[y]
+		sort.SliceStable(glyphs, func(i, j int) bool { return glyphs[i].X < glyphs[j].X })
+
+		var cells []textCell
+		var cur strings.Builder
+		var curX, end float64
+		for i, g := range glyphs {
+			if i > 0 && g.X-end > 1.5*math.Max(g.FontSize, 1) {
+				cells = append(cells, textCell{X: curX, S: cur.String()})
+				cur.Reset()
+			}
+			if cur.Len() == 0 {
+				curX = g.X
+			}
+			cur.WriteString(g.S)
+			end = g.X + g.W
+		}
+		if cur.Len() > 0 {
+			cells = append(cells, textCell{X: curX, S: cur.String()})
+		}
+		rows = append(rows, nonEmptyCells(cells))
+	}
+	return rows, nil
+}
+
+// pageBlock is either a run of text lines (rows == nil) or a detected table.
+type pageBlock struct {
+	text string
+	rows [][]string
+}
+
+func hasTable(blocks []pageBlock) bool {
+	for _, b := range blocks {
+		if b.rows != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// detectTables groups the page lines into text and tables. A table is two or more consecutive
+// lines with the same number (>= 2) of cells whose X positions line up.
+func detectTables(rows [][]textCell) []pageBlock {
+	var blocks []pageBlock
+	var lines []string
+	var run [][]textCell
+
+	flushLines := func() {
+		if len(lines) > 0 {
+			blocks = append(blocks, pageBlock{text: strings.Join(lines, "\n")})
+			lines = nil
+		}
+	}
+	flushRun := func() {
+		if len(run) >= 2 {
+			flushLines()
+			cells := make([][]string, 0, len(run))
+			for _, r := range run {
+				row := make([]string, len(r))
+				for i, c := range r {
+					row[i] = strings.TrimSpace(c.S)
+				}
+				cells = append(cells, row)
+			}
+			blocks = append(blocks, pageBlock{rows: cells})
+		} else {
+			for _, r := range run {
+				lines = append(lines, joinCells(r))
+			}
+		}
+		run = nil
+	}
+
+	for _, row := range rows {
+		if len(row) < 2 {
+			flushRun()
+			if len(row) == 1 {
+				lines = append(lines, joinCells(row))
+			}
+			continue
+		}
+		if len(run) > 0 && !columnsAligned(run[len(run)-1], row) {
+			flushRun()
+		}
+		run = append(run, row)
+	}
+	flushRun()
+	flushLines()
+	return blocks
+}
+
+func nonEmptyCells(cells []textCell) []textCell {
+	out := make([]textCell, 0, len(cells))
+	for _, c := range cells {
+		if strings.TrimSpace(c.S) != "" {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// columnsAligned requires the same cell count and at least half of the cells starting at the
+// same X as in the previous line (right-aligned numeric columns may drift).
+func columnsAligned(prev, cur []textCell) bool {
+	if len(prev) != len(cur) {
+		return false
+	}
+	aligned := 0
+	for i := range cur {
+		if math.Abs(prev[i].X-cur[i].X) <= columnTolerance {
+			aligned++
+		}
+	}
+	return aligned*2 >= len(cur)
+}
+
+func joinCells(cells []textCell) string {
+	parts := make([]string, len(cells))
+	for i, c := range cells {
+		parts[i] = strings.TrimSpace(c.S)
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,96 @@
+package pdf
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"chatclaw/internal/eino/parser/table"
+)
+
+// samplePDF builds a one-page PDF: a title line, a 3x2 table drawn as positioned text, and a
+// closing line, the way table cells are typically laid out by word processors.
+func samplePDF() []byte {
+	type text struct {
+		x, y int
+		s    string
+	}
+	texts := []text{
+		{72, 720, "Quarterly revenue report"},
+		{72, 680, "Region"}, {220, 680, "Q1"},
+		{72, 660, "North"}, {220, 660, "1200"},
+		{72, 640, "South"}, {220, 640, "950"},
+		{72, 600, "Figures are unaudited."},
+	}
+	var stream bytes.Buffer
+	for _, t := range texts {
+		fmt.Fprintf(&stream, "BT /F1 12 Tf %d %d Td (%s) Tj ET\n", t.x, t.y, t.s)
+	}
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 4 0 R /Resources << /Font << /F1 5 0 R >> >> >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", stream.Len(), stream.String()),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return buf.Bytes()
+}
+
+func TestParsePreserveTables(t *testing.T) {
+	p, err := NewParser(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	docs, err := p.Parse(context.Background(), bytes.NewReader(samplePDF()), table.WithPreserve(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(docs) != 3 {
+		for i, d := range docs {
+			t.Logf("doc %d (table=%v): %q", i, table.IsTable(d), d.Content)
+		}
+		t.Fatalf("got %d documents, want text/table/text", len(docs))
+	}
+	if table.IsTable(docs[0]) || docs[0].Content != "Quarterly revenue report" {
+		t.Errorf("first document = %q", docs[0].Content)
+	}
+	want := "| Region | Q1 |\n| --- | --- |\n| North | 1200 |\n| South | 950 |"
+	if !table.IsTable(docs[1]) || docs[1].Content != want {
+		t.Errorf("table document = %q, want %q", docs[1].Content, want)
+	}
+	if table.IsTable(docs[2]) || docs[2].Content != "Figures are unaudited." {
+		t.Errorf("last document = %q", docs[2].Content)
+	}
+}
+
+func TestParseWithoutPreserveTablesSingleDocument(t *testing.T) {
+	p, err := NewParser(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	docs, err := p.Parse(context.Background(), bytes.NewReader(samplePDF()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(docs) != 1 || table.IsTable(docs[0]) {
+		t.Fatalf("got %d documents, want one plain document", len(docs))
+	}
+}
@@ -0,0 +1,116 @@
+package table
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/cloudwego/eino/components/document/parser"
+	"github.com/cloudwego/eino/schema"
+)
+
+// MetaKey marks a schema.Document whose content is a single markdown table.
+const MetaKey = "table"
+
+// Options are the table handling options understood by the PDF and DOCX parsers.
+type Options struct {
+	// Preserve emits every detected table as its own document (markdown, MetaKey=true)
+	// instead of flattening it into the surrounding text.
+	Preserve bool
+}
+
+// WithPreserve sets Options.Preserve for a Parse call.
+func WithPreserve(preserve bool) parser.Option {
+	return parser.WrapImplSpecificOptFn(func(o *Options) {
+		o.Preserve = preserve
+	})
+}
+
+// GetOptions extracts the table options from parser options.
+func GetOptions(opts ...parser.Option) *Options {
+	return parser.GetImplSpecificOptions(&Options{}, opts...)
+}
+
+// IsTable reports whether doc was emitted as a table document.
+func IsTable(doc *schema.Document) bool {
+	if doc == nil || doc.MetaData == nil {
+		return false
+	}
+	v, _ := doc.MetaData[MetaKey].(bool)
+	return v
+}
+
+// Markdown renders rows as a markdown table; the first row is the header. Short rows are padded,
+// and pipes / line breaks inside cells are escaped so the table stays well-formed.
+func Markdown(rows [][]string) string {
+	cols := 0
+	for _, r := range rows {
+		cols = max(cols, len(r))
+	}
+	if cols == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	writeRow := func(r []string) {
+		sb.WriteString("|")
+		for i := 0; i < cols; i++ {
+			cell := ""
+			if i < len(r) {
+				cell = escapeCell(r[i])
+			}
+			sb.WriteString(" ")
+			sb.WriteString(cell)
+			sb.WriteString(" |")
+		}
+		sb.WriteString("\n")
+	}
+
+	writeRow(rows[0])
+	sb.WriteString("|")
+	sb.WriteString(strings.Repeat(" --- |", cols))
+	sb.WriteString("\n")
+	for _, r := range rows[1:] {
+		writeRow(r)
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+func escapeCell(s string) string {
+	s = strings.TrimSpace(s)
+	s = strings.ReplaceAll(s, "|", `\|`)
+	s = strings.ReplaceAll(s, "\r\n", "<br>")
+	s = strings.ReplaceAll(s, "\n", "<br>")
+	return s
+}
+
+// Split cuts a markdown table produced by Markdown into chunks of at most maxRunes runes
+// (a single oversized row still becomes its own chunk). Every chunk repeats the header and
+// separator lines, so each one is a well-formed table on its own.
+func Split(markdown string, maxRunes int) []string {
+	lines := strings.Split(strings.TrimSpace(markdown), "\n")
+	if len(lines) <= 2 || maxRunes <= 0 || utf8.RuneCountInString(markdown) <= maxRunes {
+		return []string{strings.TrimSpace(markdown)}
+	}
+
+	head := lines[0] + "\n" + lines[1]
+	headLen := utf8.RuneCountInString(head)
+
+	var chunks []string
+	var cur strings.Builder
+	curLen := 0
+	for _, line := range lines[2:] {
+		n := utf8.RuneCountInString(line) + 1
+		if curLen > 0 && headLen+curLen+n > maxRunes {
+			chunks = append(chunks, head+cur.String())
+			cur.Reset()
+			curLen = 0
+		}
+		cur.WriteString("\n")
+		cur.WriteString(line)
+		curLen += n
+	}
+	if curLen > 0 {
+		chunks = append(chunks, head+cur.String())
+	}
+	return chunks
+}
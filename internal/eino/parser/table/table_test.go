@@ -0,0 +1,38 @@
+package table
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMarkdownPadsAndEscapes(t *testing.T) {
+	got := Markdown([][]string{{"Name", "Note"}, {"a|b"}, {"c", "line1\nline2"}})
+	want := "| Name | Note |\n| --- | --- |\n| a\\|b |  |\n| c | line1<br>line2 |"
+	if got != want {
+		t.Fatalf("Markdown() = %q, want %q", got, want)
+	}
+}
+
+func TestSplitRepeatsHeader(t *testing.T) {
+	rows := [][]string{{"Region", "Revenue"}}
+	for i := 0; i < 20; i++ {
+		rows = append(rows, []string{"region-" + strings.Repeat("x", 10), "1000"})
+	}
+	md := Markdown(rows)
+
+	chunks := Split(md, 120)
+	if len(chunks) < 2 {
+		t.Fatalf("got %d chunks, want the table split", len(chunks))
+	}
+	total := 0
+	for _, c := range chunks {
+		lines := strings.Split(c, "\n")
+		if lines[0] != "| Region | Revenue |" || lines[1] != "| --- | --- |" {
+			t.Fatalf("chunk without header: %q", c)
+		}
+		total += len(lines) - 2
+	}
+	if total != 20 {
+		t.Errorf("got %d data rows across chunks, want 20", total)
+	}
+}
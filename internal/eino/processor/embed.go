@@ -0,0 +1,229 @@
+package processor
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/eino/components/embedding"
+	"github.com/uptrace/bun"
+)
+
+// ErrEmbeddingRateLimited 嵌入请求被供应商限流（重试退避后仍失败）；用 errors.Is 判断
+var ErrEmbeddingRateLimited = errors.New("embedding rate limited")
+
+const (
+	maxEmbeddingConcurrency = 8
+
+	// 限流重试：最多重试 embeddingRateLimitRetries 次，等待时间从 embeddingRateLimitBackoff 起倍增
+	embeddingRateLimitRetries    = 4
+	embeddingRateLimitBackoff    = 2 * time.Second
+	embeddingRateLimitMaxBackoff = 30 * time.Second
+)
+
+// EmbeddingTuning 嵌入阶段的批量与并发配置
+type EmbeddingTuning struct {
+	BatchSize   int // 单次请求的文本数
+	Concurrency int // 同时进行的请求数
+}
+
+// EmbeddingStats 嵌入阶段的进度与吞吐，随每个完成的批次上报
+type EmbeddingStats struct {
+	Done            int     // 已嵌入的段数
+	Total           int     // 总段数
+	Percent         int     // 完成百分比
+	ChunksPerSecond float64 // 自开始以来的平均吞吐（段/秒）
+}
+
+// maxEmbeddingBatchSize 各供应商类型单次请求的安全上限（Azure 旧版 API 限制 16 条）
+func maxEmbeddingBatchSize(providerType string) int {
+	switch providerType {
+	case "azure":
+		return 16
+	default:
+		return 64
+	}
+}
+
+// GetEmbeddingTuning 读取 embedding_batch_size / embedding_concurrency 设置。
+// embedding_batch_size 为 0 时使用知识库的 batch_max_chunks；结果按供应商类型限制在安全范围内。
+func GetEmbeddingTuning(ctx context.Context, db *bun.DB, providerType string, libraryBatchSize int) EmbeddingTuning {
+	tuning := EmbeddingTuning{
+		BatchSize:   NormalizeEmbeddingBatchSize(libraryBatchSize),
+		Concurrency: 1,
+	}
+
+	type settingRow struct {
+		Key   string         `bun:"key"`
+		Value sql.NullString `bun:"value"`
+	}
+	rows := make([]settingRow, 0, 2)
+	if err := db.NewSelect().
+		TableExpr("settings").
+		Column("key", "value").
+		Where("key IN (?)", bun.In([]string{"embedding_batch_size", "embedding_concurrency"})).
+		Scan(ctx, &rows); err != nil {
+		slog.Warn("[processor] read embedding tuning settings failed", "error", err)
+		return tuning
+	}
+	for _, r := range rows {
+		n, err := strconv.Atoi(strings.TrimSpace(r.Value.String))
+		if !r.Value.Valid || err != nil || n <= 0 {
+			continue
+		}
+		switch r.Key {
+		case "embedding_batch_size":
+			tuning.BatchSize = n
+		case "embedding_concurrency":
+			tuning.Concurrency = n
+		}
+	}
+
+	tuning.BatchSize = min(tuning.BatchSize, maxEmbeddingBatchSize(providerType))
+	tuning.Concurrency = min(tuning.Concurrency, maxEmbeddingConcurrency)
+	return tuning
+}
+
+// isRateLimitError 判断供应商返回的是否为限流错误（HTTP 429 / rate limit / quota）
+func isRateLimitError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	for _, s := range []string{"429", "rate limit", "rate_limit", "ratelimit", "too many requests", "throttl", "quota exceeded", "resource_exhausted"} {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// embedWithBackoff 调用 EmbedStrings，遇到限流时指数退避重试；最终仍被限流时返回的错误包含 ErrEmbeddingRateLimited
+func embedWithBackoff(ctx context.Context, embedder embedding.Embedder, texts []string) ([][]float64, error) {
+	delay := embeddingRateLimitBackoff
+	for attempt := 0; ; attempt++ {
+		vectors, err := embedder.EmbedStrings(ctx, texts)
+		if err == nil {
+			return vectors, nil
+		}
+		if !isRateLimitError(err) {
+			return nil, err
+		}
+		if attempt >= embeddingRateLimitRetries {
+			return nil, fmt.Errorf("%w: %w", ErrEmbeddingRateLimited, err)
+		}
+		slog.Warn("[processor] embedding rate limited, backing off", "attempt", attempt+1, "delay", delay, "error", err)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, embeddingRateLimitMaxBackoff)
+	}
+}
+
+// embedInBatches 按 tuning 将 texts 分批并发嵌入。每批完成后调用 onBatch(start, vectors)（可能并发调用），
+// onProgress 收到当前进度与吞吐（串行调用）。任一批失败时取消其余批次并返回第一个错误。
+func embedInBatches(
+	ctx context.Context,
+	embedder embedding.Embedder,
+	texts []string,
+	tuning EmbeddingTuning,
+	onBatch func(start int, vectors [][]float64) error,
+	onProgress func(EmbeddingStats),
+) error {
+	batchSize := max(tuning.BatchSize, 1)
+	concurrency := max(tuning.Concurrency, 1)
+	started := time.Now()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+		done     int
+	)
+	sem := make(chan struct{}, concurrency)
+
+	for i := 0; i < len(texts); i += batchSize {
+		end := min(i+batchSize, len(texts))
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			slog.Debug("[processor] embedding batch", "from", start+1, "to", end, "total", len(texts))
+			vectors, err := embedWithBackoff(ctx, embedder, texts[start:end])
+			if err == nil {
+				err = onBatch(start, vectors)
+			} else {
+				slog.Error("[processor] embedding batch failed", "from", start+1, "to", end, "error", err)
+				err = fmt.Errorf("嵌入批次（从 %d 开始）: %w", start, err)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				return
+			}
+			done += end - start
+			if onProgress != nil {
+				stats := EmbeddingStats{
+					Done:    done,
+					Total:   len(texts),
+					Percent: done * 100 / len(texts),
+				}
+				if elapsed := time.Since(started).Seconds(); elapsed > 0 {
+					stats.ChunksPerSecond = float64(done) / elapsed
+				}
+				onProgress(stats)
+			}
+		}(i, end)
+	}
+
+	wg.Wait()
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
+
+// EmbedTexts 按 GetEmbeddingTuning 的批量与并发嵌入 texts（限流时退避重试），返回与 texts 一一对应的向量。
+// 不写入任何表，用于临时的内存索引。
+func EmbedTexts(ctx context.Context, db *bun.DB, embedder embedding.Embedder, providerType string, texts []string) ([][]float64, error) {
+	vectors := make([][]float64, len(texts))
+	tuning := GetEmbeddingTuning(ctx, db, providerType, 0)
+	err := embedInBatches(ctx, embedder, texts, tuning, func(start int, batch [][]float64) error {
+		for j, vec := range batch {
+			if start+j < len(vectors) {
+				vectors[start+j] = vec
+			}
+		}
+		return nil
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return vectors, nil
+}
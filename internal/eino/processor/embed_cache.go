@@ -0,0 +1,258 @@
+package processor
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+	"strings"
+
+	"chatclaw/internal/sqlite"
+
+	"github.com/cloudwego/eino/components/embedding"
+	"github.com/uptrace/bun"
+)
+
+const (
+	// defaultEmbeddingCacheMaxEntries 未读到 embedding_cache_max_entries 设置时的缓存上限
+	defaultEmbeddingCacheMaxEntries = 50000
+
+	// embeddingCacheLookupChunk 单条 IN 查询的 hash 数（低于 SQLite 变量上限）
+	embeddingCacheLookupChunk = 500
+)
+
+// embeddingCacheKey 缓存按 供应商 + 模型 + 维度 区分，切换任一项都不会命中旧向量
+func embeddingCacheKey(config *EmbeddingConfig) string {
+	return fmt.Sprintf("%s::%s::%d", config.ProviderID, config.ModelID, config.Dimension)
+}
+
+func hashEmbeddingText(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
+
+func encodeCachedVector(vec []float64) []byte {
+	buf := make([]byte, 8*len(vec))
+	for i, v := range vec {
+		binary.LittleEndian.PutUint64(buf[i*8:], math.Float64bits(v))
+	}
+	return buf
+}
+
+func decodeCachedVector(buf []byte) []float64 {
+	if len(buf) == 0 || len(buf)%8 != 0 {
+		return nil
+	}
+	vec := make([]float64, len(buf)/8)
+	for i := range vec {
+		vec[i] = math.Float64frombits(binary.LittleEndian.Uint64(buf[i*8:]))
+	}
+	return vec
+}
+
+// getEmbeddingCacheMaxEntries 读取 embedding_cache_max_entries；0 表示关闭缓存
+func getEmbeddingCacheMaxEntries(ctx context.Context, db *bun.DB) int {
+	var value sql.NullString
+	if err := db.NewSelect().
+		TableExpr("settings").
+		Column("value").
+		Where("key = ?", "embedding_cache_max_entries").
+		Scan(ctx, &value); err != nil {
+		return defaultEmbeddingCacheMaxEntries
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(value.String))
+	if !value.Valid || err != nil || n < 0 {
+		return defaultEmbeddingCacheMaxEntries
+	}
+	return n
+}
+
+// cachedEmbedder 在调用供应商前先查 embedding_cache，新结果写回缓存。
+// 缓存读写失败只记录日志，不影响嵌入本身。
+type cachedEmbedder struct {
+	inner      embedding.Embedder
+	db         *bun.DB
+	modelKey   string
+	dimension  int
+	maxEntries int
+}
+
+// withEmbeddingCache 为 embedder 包装嵌入缓存；缓存上限为 0 时原样返回
+func (p *Processor) withEmbeddingCache(ctx context.Context, embedder embedding.Embedder, config *EmbeddingConfig) embedding.Embedder {
+	maxEntries := getEmbeddingCacheMaxEntries(ctx, p.db)
+	if maxEntries == 0 {
+		return embedder
+	}
+	return &cachedEmbedder{
+		inner:      embedder,
+		db:         p.db,
+		modelKey:   embeddingCacheKey(config),
+		dimension:  config.Dimension,
+		maxEntries: maxEntries,
+	}
+}
+
+func (c *cachedEmbedder) EmbedStrings(ctx context.Context, texts []string, opts ...embedding.Option) ([][]float64, error) {
+	hashes := make([]string, len(texts))
+	for i, t := range texts {
+		hashes[i] = hashEmbeddingText(t)
+	}
+
+	cached, err := c.lookup(ctx, hashes)
+	if err != nil {
+		slog.Warn("[processor] embedding cache lookup failed", "error", err)
+		cached = nil
+	}
+
+	vectors := make([][]float64, len(texts))
+	var missTexts []string
+	var missHashes []string
+	missIndex := make(map[string][]int) // 同一批内的重复文本只请求一次
+	for i, h := range hashes {
+		if vec, ok := cached[h]; ok {
+			vectors[i] = vec
+			continue
+		}
+		if _, ok := missIndex[h]; !ok {
+			missTexts = append(missTexts, texts[i])
+			missHashes = append(missHashes, h)
+		}
+		missIndex[h] = append(missIndex[h], i)
+	}
+
+	if len(cached) > 0 {
+		c.touch(ctx, hashes)
+	}
+	if len(missTexts) == 0 {
+		slog.Debug("[processor] embedding cache hit", "texts", len(texts))
+		return vectors, nil
+	}
+
+	fresh, err := c.inner.EmbedStrings(ctx, missTexts, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if len(fresh) != len(missTexts) {
+		return nil, fmt.Errorf("embedder returned %d vectors for %d texts", len(fresh), len(missTexts))
+	}
+	for j, vec := range fresh {
+		for _, i := range missIndex[missHashes[j]] {
+			vectors[i] = vec
+		}
+	}
+	slog.Debug("[processor] embedding cache", "texts", len(texts), "hits", len(texts)-len(missTexts), "misses", len(missTexts))
+
+	if err := c.store(ctx, missHashes, fresh); err != nil {
+		slog.Warn("[processor] embedding cache store failed", "error", err)
+	}
+	return vectors, nil
+}
+
+// lookup 返回已缓存的 hash -> 向量；维度与当前配置不符的条目视为未命中
+func (c *cachedEmbedder) lookup(ctx context.Context, hashes []string) (map[string][]float64, error) {
+	type cacheRow struct {
+		TextHash string `bun:"text_hash"`
+		Vector   []byte `bun:"vector"`
+	}
+	result := make(map[string][]float64, len(hashes))
+	for start := 0; start < len(hashes); start += embeddingCacheLookupChunk {
+		end := min(start+embeddingCacheLookupChunk, len(hashes))
+		var rows []cacheRow
+		if err := c.db.NewSelect().
+			TableExpr("embedding_cache").
+			Column("text_hash", "vector").
+			Where("model_key = ?", c.modelKey).
+			Where("text_hash IN (?)", bun.In(hashes[start:end])).
+			Scan(ctx, &rows); err != nil {
+			return nil, err
+		}
+		for _, r := range rows {
+			vec := decodeCachedVector(r.Vector)
+			if vec == nil || (c.dimension > 0 && len(vec) != c.dimension) {
+				continue
+			}
+			result[r.TextHash] = vec
+		}
+	}
+	return result, nil
+}
+
+// touch 刷新命中条目的 last_used_at，淘汰时按最近使用时间保留
+func (c *cachedEmbedder) touch(ctx context.Context, hashes []string) {
+	now := sqlite.NowUTC()
+	for start := 0; start < len(hashes); start += embeddingCacheLookupChunk {
+		end := min(start+embeddingCacheLookupChunk, len(hashes))
+		if _, err := c.db.NewUpdate().
+			TableExpr("embedding_cache").
+			Set("last_used_at = ?", now).
+			Where("model_key = ?", c.modelKey).
+			Where("text_hash IN (?)", bun.In(hashes[start:end])).
+			Exec(ctx); err != nil {
+			slog.Warn("[processor] embedding cache touch failed", "error", err)
+			return
+		}
+	}
+}
+
+// store 写入新向量，超过上限时淘汰最久未使用的条目
+func (c *cachedEmbedder) store(ctx context.Context, hashes []string, vectors [][]float64) error {
+	now := sqlite.NowUTC()
+	err := c.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		for i, h := range hashes {
+			if len(vectors[i]) == 0 {
+				continue
+			}
+			if _, err := tx.ExecContext(ctx,
+				`INSERT OR REPLACE INTO embedding_cache (model_key, text_hash, vector, created_at, last_used_at) VALUES (?, ?, ?, ?, ?)`,
+				c.modelKey, h, encodeCachedVector(vectors[i]), now, now,
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	_, err = EvictEmbeddingCache(ctx, c.db, c.maxEntries)
+	return err
+}
+
+// EvictEmbeddingCache 将 embedding_cache 裁剪到 maxEntries 条（按 last_used_at 淘汰最旧的），返回删除条数
+func EvictEmbeddingCache(ctx context.Context, db *bun.DB, maxEntries int) (int64, error) {
+	if maxEntries < 0 {
+		maxEntries = 0
+	}
+	count, err := db.NewSelect().TableExpr("embedding_cache").Count(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if count <= maxEntries {
+		return 0, nil
+	}
+	res, err := db.ExecContext(ctx,
+		`DELETE FROM embedding_cache WHERE rowid IN (SELECT rowid FROM embedding_cache ORDER BY last_used_at ASC LIMIT ?)`,
+		count-maxEntries,
+	)
+	if err != nil {
+		return 0, err
+	}
+	n, _ := res.RowsAffected()
+	slog.Info("[processor] embedding cache evicted", "deleted", n, "max_entries", maxEntries)
+	return n, nil
+}
+
+// ClearEmbeddingCache 清空嵌入缓存，返回删除条数
+func ClearEmbeddingCache(ctx context.Context, db *bun.DB) (int64, error) {
+	res, err := db.ExecContext(ctx, `DELETE FROM embedding_cache`)
+	if err != nil {
+		return 0, err
+	}
+	n, _ := res.RowsAffected()
+	return n, nil
+}
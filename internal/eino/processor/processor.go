@@ -12,6 +12,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 	"unicode/utf8"
 
@@ -23,6 +24,7 @@ import (
 	"chatclaw/internal/eino/chatmodel"
 	einoembed "chatclaw/internal/eino/embedding"
 	einoparser "chatclaw/internal/eino/parser"
+	"chatclaw/internal/eino/parser/table"
 	"chatclaw/internal/eino/raptor"
 	"chatclaw/internal/eino/splitter"
 	"chatclaw/internal/fts/tokenizer"
@@ -80,6 +82,8 @@ type LibraryConfig struct {
 	RaptorLLMModelID            string
 	BatchMaxDocuments           int
 	BatchMaxChunks              int
+	PreserveTables              bool
+	Language                    string // 分词语言提示（auto/zh/en/multi），见 tokenizer.ResolveLanguage
 }
 
 // NormalizeEmbeddingBatchSize clamps per-request embedding segment count (1~20).
@@ -118,12 +122,31 @@ type ProcessResult struct {
 	WordTotal  int
 	SplitTotal int
 	Error      error
+	NodesKept  bool // 向量化失败但分段节点已入库，可仅重新向量化
 }
 
 // Processor 处理文档的解析、分割和嵌入
 type Processor struct {
-	db     *bun.DB
-	parser parser.Parser
+	db      *bun.DB
+	parser  parser.Parser
+	onStats func(EmbeddingStats)
+}
+
+// SetEmbeddingStatsHandler 设置嵌入吞吐回调（在对应的进度回调之前调用）
+func (p *Processor) SetEmbeddingStatsHandler(fn func(EmbeddingStats)) {
+	p.onStats = fn
+}
+
+// embeddingProgress 先上报吞吐再把百分比交给 onPercent
+func (p *Processor) embeddingProgress(onPercent func(int)) func(EmbeddingStats) {
+	return func(stats EmbeddingStats) {
+		if p.onStats != nil {
+			p.onStats(stats)
+		}
+		if onPercent != nil {
+			onPercent(stats.Percent)
+		}
+	}
 }
 
 // ReembedDocumentNodes 仅对已有的 document_nodes 重新向量化（不重新解析/分段）
@@ -141,10 +164,21 @@ func (p *Processor) ReembedDocumentNodes(
 		return errors.New("embeddingConfig required")
 	}
 
-	embedder, err := p.createEmbedder(ctx, embeddingConfig)
+	var libID int64
+	if err := p.db.NewSelect().Table("documents").Column("library_id").Where("id = ?", docID).Scan(ctx, &libID); err != nil {
+		return fmt.Errorf("读取文档 library_id 失败: %w", err)
+	}
+	lc, err := GetLibraryConfig(ctx, p.db, libID)
+	if err != nil {
+		return fmt.Errorf("读取知识库配置失败: %w", err)
+	}
+	tuning := GetEmbeddingTuning(ctx, p.db, embeddingConfig.ProviderType, lc.BatchMaxChunks)
+
+	embedder, err := p.createEmbedder(ctx, embeddingConfig, tuning.BatchSize)
 	if err != nil {
 		return fmt.Errorf("创建 embedder 失败: %w", err)
 	}
+	embedder = p.withEmbeddingCache(ctx, embedder, embeddingConfig)
 
 	nodes := make([]*DocumentNode, 0, 256)
 	if err := p.db.NewSelect().
@@ -160,17 +194,7 @@ func (p *Processor) ReembedDocumentNodes(
 		return errors.New("no document nodes")
 	}
 
-	var libID int64
-	if err := p.db.NewSelect().Table("documents").Column("library_id").Where("id = ?", docID).Scan(ctx, &libID); err != nil {
-		return fmt.Errorf("读取文档 library_id 失败: %w", err)
-	}
-	lc, err := GetLibraryConfig(ctx, p.db, libID)
-	if err != nil {
-		return fmt.Errorf("读取知识库配置失败: %w", err)
-	}
-	batch := NormalizeEmbeddingBatchSize(lc.BatchMaxChunks)
-
-	return p.embedNodes(ctx, nodes, embedder, onProgress, batch)
+	return p.embedNodes(ctx, nodes, embedder, p.embeddingProgress(onProgress), tuning)
 }
 
 // NewProcessor 创建新的文档处理器
@@ -206,7 +230,8 @@ func (p *Processor) ProcessDocument(
 		onProgress("parsing", 10)
 	}
 
-	docs, err := p.parseDocument(ctx, localPath)
+	preserveTables := libraryConfig != nil && libraryConfig.PreserveTables
+	docs, err := p.parseDocument(ctx, localPath, table.WithPreserve(preserveTables))
 	if err != nil {
 		result.Error = wrapPhase(PhaseParsing, fmt.Errorf("解析失败: %w", err))
 		return result, result.Error
@@ -229,7 +254,12 @@ func (p *Processor) ProcessDocument(
 	}
 
 	// 提前创建 embedder（用于语义分割和后续向量化，同一实例复用）
-	embedder, err := p.createEmbedder(ctx, embeddingConfig)
+	libraryBatchSize := 0
+	if libraryConfig != nil {
+		libraryBatchSize = libraryConfig.BatchMaxChunks
+	}
+	tuning := GetEmbeddingTuning(ctx, p.db, embeddingConfig.ProviderType, libraryBatchSize)
+	embedder, err := p.createEmbedder(ctx, embeddingConfig, tuning.BatchSize)
 	if err != nil {
 		// This happens before embedding phase starts; treat it as parsing/setup error.
 		result.Error = wrapPhase(PhaseParsing, fmt.Errorf("创建 embedder 失败: %w", err))
@@ -301,7 +331,7 @@ func (p *Processor) ProcessDocument(
 			LibraryID:     libraryConfig.ID,
 			DocumentID:    docID,
 			Content:       chunk.Content,
-			ContentTokens: tokenizeContent(chunk.Content),
+			ContentTokens: tokenizeContent(chunk.Content, libraryConfig.Language),
 			Level:         0,
 			ParentID:      nil,
 			ChunkOrder:    i,
@@ -313,20 +343,28 @@ func (p *Processor) ProcessDocument(
 		onProgress("parsing", 100)
 	}
 
-	embedBatch := NormalizeEmbeddingBatchSize(libraryConfig.BatchMaxChunks)
-
 	// 阶段 4：嵌入 level-0 节点（内存中）
 	slog.Info("[processor] embedding level-0 nodes", "count", len(level0))
 	embedStart := time.Now()
 	if onProgress != nil {
 		onProgress("embedding", 10)
 	}
-	if err := embedRaptorNodes(ctx, level0, embedder, func(progress int) {
+	// 仅对入库节点使用嵌入缓存；语义分割的句子向量不写入缓存
+	if err := embedRaptorNodes(ctx, level0, p.withEmbeddingCache(ctx, embedder, embeddingConfig), p.embeddingProgress(func(progress int) {
 		if onProgress != nil {
 			onProgress("embedding", 10+progress*70/100)
 		}
-	}, embedBatch); err != nil {
+	}), tuning); err != nil {
 		result.Error = wrapPhase(PhaseEmbedding, fmt.Errorf("嵌入失败: %w", err))
+		// 未开启 RAPTOR 时先保存已分段的节点（含已得到的向量），重试向量化时无需重新解析/分段；
+		// 开启 RAPTOR 时摘要节点依赖完整向量，只能完整重新处理
+		if !raptorEnabled && ctx.Err() == nil {
+			if perr := p.persistNodesAndVectors(ctx, docID, level0); perr != nil {
+				slog.Warn("[processor] keep nodes after embedding failure failed", "doc_id", docID, "error", perr)
+			} else {
+				result.NodesKept = true
+			}
+		}
 		return result, result.Error
 	}
 	slog.Info("[processor] level-0 embedding completed", "elapsed", time.Since(embedStart))
@@ -353,7 +391,7 @@ func (p *Processor) ProcessDocument(
 	// 确保所有节点都有 content_tokens（摘要节点也需要）
 	for _, n := range allNodes {
 		if strings.TrimSpace(n.ContentTokens) == "" {
-			n.ContentTokens = tokenizeContent(n.Content)
+			n.ContentTokens = tokenizeContent(n.Content, libraryConfig.Language)
 		}
 	}
 
@@ -377,14 +415,14 @@ func (p *Processor) ProcessDocument(
 }
 
 // parseDocument 解析文档文件并返回 schema.Document 列表
-func (p *Processor) parseDocument(ctx context.Context, localPath string) ([]*schema.Document, error) {
+func (p *Processor) parseDocument(ctx context.Context, localPath string, opts ...parser.Option) ([]*schema.Document, error) {
 	file, err := os.Open(localPath)
 	if err != nil {
 		return nil, fmt.Errorf("打开文件: %w", err)
 	}
 	defer file.Close()
 
-	docs, err := p.parser.Parse(ctx, file, parser.WithURI(localPath))
+	docs, err := p.parser.Parse(ctx, file, append([]parser.Option{parser.WithURI(localPath)}, opts...)...)
 	if err != nil {
 		return nil, err
 	}
@@ -392,6 +430,54 @@ func (p *Processor) parseDocument(ctx context.Context, localPath string) ([]*sch
 	return docs, nil
 }
 
+// ExtractText 仅执行解析阶段（不分段、不嵌入），返回文件的纯文本内容。
+// 用于聊天中的一次性文档附件。
+func ExtractText(ctx context.Context, localPath string) (string, error) {
+	docParser, err := einoparser.NewDocumentParser(ctx)
+	if err != nil {
+		return "", fmt.Errorf("创建文档解析器失败: %w", err)
+	}
+	p := &Processor{parser: docParser}
+	docs, err := p.parseDocument(ctx, localPath)
+	if err != nil {
+		return "", wrapPhase(PhaseParsing, err)
+	}
+
+	parts := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		if text := strings.TrimSpace(doc.Content); text != "" {
+			parts = append(parts, text)
+		}
+	}
+	return strings.Join(parts, "\n\n"), nil
+}
+
+// ExtractChunks 执行解析与分段（按 chunkSize / chunkOverlap 递归分割，Markdown 按标题分割，不做语义分割、不嵌入），
+// 返回各分块文本。用于聊天中仅对单条消息生效的参考文档检索。
+func ExtractChunks(ctx context.Context, localPath string, chunkSize, chunkOverlap int) ([]string, error) {
+	docParser, err := einoparser.NewDocumentParser(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("创建文档解析器失败: %w", err)
+	}
+	p := &Processor{parser: docParser}
+	docs, err := p.parseDocument(ctx, localPath)
+	if err != nil {
+		return nil, wrapPhase(PhaseParsing, err)
+	}
+
+	chunks, err := p.splitDocument(ctx, docs, localPath, &LibraryConfig{ChunkSize: chunkSize, ChunkOverlap: chunkOverlap}, nil)
+	if err != nil {
+		return nil, wrapPhase(PhaseSplitting, err)
+	}
+	out := make([]string, 0, len(chunks))
+	for _, c := range chunks {
+		if text := strings.TrimSpace(c.Content); text != "" {
+			out = append(out, text)
+		}
+	}
+	return out, nil
+}
+
 // splitDocument 将文档分割成块
 // 分割器选择优先级：Markdown Header Splitter > Semantic Splitter > Recursive Splitter
 func (p *Processor) splitDocument(
@@ -420,7 +506,47 @@ func (p *Processor) splitDocument(
 		return nil, err
 	}
 
-	return docSplitter.Transform(ctx, docs)
+	hasTables := false
+	for _, d := range docs {
+		if table.IsTable(d) {
+			hasTables = true
+			break
+		}
+	}
+	if !hasTables {
+		return docSplitter.Transform(ctx, docs)
+	}
+
+	// 表格文档（preserve_tables）不走通用分割器：按行切分并在每块重复表头，保证每个节点都是完整表格
+	var chunks, pending []*schema.Document
+	flush := func() error {
+		if len(pending) == 0 {
+			return nil
+		}
+		out, err := docSplitter.Transform(ctx, pending)
+		if err != nil {
+			return err
+		}
+		chunks = append(chunks, out...)
+		pending = nil
+		return nil
+	}
+	for _, d := range docs {
+		if !table.IsTable(d) {
+			pending = append(pending, d)
+			continue
+		}
+		if err := flush(); err != nil {
+			return nil, err
+		}
+		for _, part := range table.Split(d.Content, libraryConfig.ChunkSize) {
+			chunks = append(chunks, &schema.Document{Content: part, MetaData: d.MetaData})
+		}
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return chunks, nil
 }
 
 // storeNodes 将文档块作为节点存储到数据库
@@ -432,7 +558,7 @@ func (p *Processor) storeNodes(ctx context.Context, docID, libraryID int64, chun
 	err := p.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
 		for i, chunk := range chunks {
 			// 为 FTS 对内容进行分词
-			tokens := tokenizeContent(chunk.Content)
+			tokens := tokenizeContent(chunk.Content, tokenizer.LanguageAuto)
 
 			node := &DocumentNode{
 				LibraryID:     libraryID,
@@ -472,7 +598,7 @@ func (p *Processor) storeNodes(ctx context.Context, docID, libraryID int64, chun
 }
 
 // createEmbedder 根据配置创建 embedding.Embedder
-func (p *Processor) createEmbedder(ctx context.Context, config *EmbeddingConfig) (embedding.Embedder, error) {
+func (p *Processor) createEmbedder(ctx context.Context, config *EmbeddingConfig, batchSize int) (embedding.Embedder, error) {
 	return einoembed.NewEmbedder(ctx, &einoembed.ProviderConfig{
 		ProviderID:   config.ProviderID,
 		ProviderType: config.ProviderType,
@@ -481,42 +607,27 @@ func (p *Processor) createEmbedder(ctx context.Context, config *EmbeddingConfig)
 		ModelID:      config.ModelID,
 		Dimension:    config.Dimension,
 		ExtraConfig:  config.ExtraConfig,
+		BatchSize:    batchSize,
 	})
 }
 
 // embedNodes 为节点生成嵌入向量并存储
-func (p *Processor) embedNodes(ctx context.Context, nodes []*DocumentNode, embedder embedding.Embedder, onProgress func(int), embedBatchSize int) error {
+func (p *Processor) embedNodes(ctx context.Context, nodes []*DocumentNode, embedder embedding.Embedder, onProgress func(EmbeddingStats), tuning EmbeddingTuning) error {
 	if len(nodes) == 0 {
 		slog.Debug("[processor] no nodes to embed")
 		return nil
 	}
 
-	slog.Info("[processor] embedding nodes", "count", len(nodes))
-
-	// 批量嵌入以提高效率（每批段数由知识库配置；底层 Embedder 仍可能按供应商上限再拆分）
-	batchSize := NormalizeEmbeddingBatchSize(embedBatchSize)
-	storedCount := 0
-	for i := 0; i < len(nodes); i += batchSize {
-		end := i + batchSize
-		if end > len(nodes) {
-			end = len(nodes)
-		}
-		batch := nodes[i:end]
-
-		// 收集批量嵌入的内容
-		contents := make([]string, len(batch))
-		for j, node := range batch {
-			contents[j] = node.Content
-		}
-
-		slog.Debug("[processor] embedding batch", "from", i+1, "to", end, "total", len(nodes))
+	slog.Info("[processor] embedding nodes", "count", len(nodes), "batch", tuning.BatchSize, "concurrency", tuning.Concurrency)
 
-		vectors, err := embedder.EmbedStrings(ctx, contents)
-		if err != nil {
-			slog.Error("[processor] embedding batch failed", "from", i+1, "to", end, "error", err)
-			return fmt.Errorf("嵌入批次（从 %d 开始）: %w", i, err)
-		}
+	// 批量嵌入以提高效率（每批段数与并发数见 GetEmbeddingTuning）
+	contents := make([]string, len(nodes))
+	for i, node := range nodes {
+		contents[i] = node.Content
+	}
 
+	var storedCount atomic.Int64
+	err := embedInBatches(ctx, embedder, contents, tuning, func(start int, vectors [][]float64) error {
 		dim := 0
 		if len(vectors) > 0 {
 			dim = len(vectors[0])
@@ -524,24 +635,24 @@ func (p *Processor) embedNodes(ctx context.Context, nodes []*DocumentNode, embed
 		slog.Debug("[processor] embedding batch result", "vectors", len(vectors), "dimension", dim)
 
 		// 存储向量
-		for j, node := range batch {
-			if j < len(vectors) {
-				node.Vector = vectors[j]
-				if err := p.storeVector(ctx, node.ID, vectors[j]); err != nil {
-					return fmt.Errorf("存储节点 %d 的向量: %w", node.ID, err)
-				}
-				storedCount++
+		for j, vec := range vectors {
+			if start+j >= len(nodes) {
+				break
 			}
+			node := nodes[start+j]
+			node.Vector = vec
+			if err := p.storeVector(ctx, node.ID, vec); err != nil {
+				return fmt.Errorf("存储节点 %d 的向量: %w", node.ID, err)
+			}
+			storedCount.Add(1)
 		}
-
-		// 报告进度
-		if onProgress != nil {
-			progress := (end * 100) / len(nodes)
-			onProgress(progress)
-		}
+		return nil
+	}, onProgress)
+	if err != nil {
+		return err
 	}
 
-	slog.Info("[processor] embedding completed", "stored", storedCount, "nodes", len(nodes))
+	slog.Info("[processor] embedding completed", "stored", storedCount.Load(), "nodes", len(nodes))
 	return nil
 }
 
@@ -650,7 +761,7 @@ func (p *Processor) buildRaptorTree(
 // createRaptorNode 在数据库中创建 RAPTOR 摘要节点
 // 使用 LastInsertId() 获取插入的 ID
 func (p *Processor) createRaptorNode(ctx context.Context, node *raptor.DocumentNode) (int64, error) {
-	tokens := tokenizeContent(node.Content)
+	tokens := tokenizeContent(node.Content, tokenizer.LanguageAuto)
 
 	res, err := p.db.NewRaw(
 		"INSERT INTO document_nodes (library_id, document_id, content, content_tokens, level, chunk_order) VALUES (?, ?, ?, ?, ?, ?)",
@@ -691,13 +802,13 @@ func (p *Processor) updateDocumentStats(ctx context.Context, docID int64, wordTo
 }
 
 // tokenizeContent 对内容进行分词，用于 FTS
-// 使用 gse 进行中文/英文分词
-func tokenizeContent(content string) string {
-	return tokenizer.TokenizeContent(content)
+// 使用 gse 进行中文/英文分词，language 为知识库的分词语言提示
+func tokenizeContent(content, language string) string {
+	return tokenizer.TokenizeContentFor(content, language)
 }
 
 // embedRaptorNodes embeds contents for raptor nodes (in-memory, no DB writes).
-func embedRaptorNodes(ctx context.Context, nodes []*raptor.DocumentNode, embedder embedding.Embedder, onProgress func(int), embedBatchSize int) error {
+func embedRaptorNodes(ctx context.Context, nodes []*raptor.DocumentNode, embedder embedding.Embedder, onProgress func(EmbeddingStats), tuning EmbeddingTuning) error {
 	if len(nodes) == 0 {
 		return nil
 	}
@@ -705,34 +816,18 @@ func embedRaptorNodes(ctx context.Context, nodes []*raptor.DocumentNode, embedde
 		return errors.New("embedder is nil")
 	}
 
-	batchSize := NormalizeEmbeddingBatchSize(embedBatchSize)
-	for i := 0; i < len(nodes); i += batchSize {
-		end := i + batchSize
-		if end > len(nodes) {
-			end = len(nodes)
-		}
-		batch := nodes[i:end]
-
-		contents := make([]string, len(batch))
-		for j, n := range batch {
-			contents[j] = n.Content
-		}
-
-		vecs, err := embedder.EmbedStrings(ctx, contents)
-		if err != nil {
-			return err
-		}
-		for j := range batch {
-			if j < len(vecs) {
-				batch[j].Vector = vecs[j]
+	contents := make([]string, len(nodes))
+	for i, n := range nodes {
+		contents[i] = n.Content
+	}
+	return embedInBatches(ctx, embedder, contents, tuning, func(start int, vectors [][]float64) error {
+		for j, vec := range vectors {
+			if start+j < len(nodes) {
+				nodes[start+j].Vector = vec
 			}
 		}
-
-		if onProgress != nil {
-			onProgress((end * 100) / len(nodes))
-		}
-	}
-	return nil
+		return nil
+	}, onProgress)
 }
 
 // buildRaptorPlan builds RAPTOR summary nodes in memory (no DB writes).
@@ -856,7 +951,7 @@ func GetLibraryConfig(ctx context.Context, db *bun.DB, libraryID int64) (*Librar
 	var config LibraryConfig
 	err := db.NewSelect().
 		TableExpr("library").
-		Column("id", "chunk_size", "chunk_overlap", "semantic_segmentation_enabled", "raptor_llm_provider_id", "raptor_llm_model_id", "batch_max_documents", "batch_max_chunks").
+		Column("id", "chunk_size", "chunk_overlap", "semantic_segmentation_enabled", "raptor_llm_provider_id", "raptor_llm_model_id", "batch_max_documents", "batch_max_chunks", "preserve_tables", "language").
 		Where("id = ?", libraryID).
 		Scan(ctx, &config)
 	if err != nil {
@@ -1,5 +1,10 @@
 package tools
 
+import (
+	"slices"
+	"strings"
+)
+
 // ToolsConfig defines the configuration for enabling/disabling tools.
 // This is reserved for future use - currently all tools are enabled by default.
 type ToolsConfig struct {
@@ -55,6 +60,9 @@ const (
 	ToolIDSequentialThinking = "sequential_thinking"
 	ToolIDWikipedia          = "wikipedia_search"
 	ToolIDLibraryRetriever   = "library_retriever"
+	// ToolIDReferenceRetriever searches the reference files of the current message; added only
+	// for turns that carry them and not configurable per agent.
+	ToolIDReferenceRetriever = "reference_retriever"
 
 	// Filesystem tool IDs — registered as independent tools, not via filesystem middleware.
 	ToolIDLs        = "ls"
@@ -73,3 +81,74 @@ const (
 	ToolIDDingTalkSender = "dingtalk_sender"
 	ToolIDQQSender     = "qq_sender"
 )
+
+// ConfigurableToolIDs lists the built-in tools an agent can allow or deny
+// individually (agents.enabled_tools). Skill, MCP and IM tools are governed by
+// their own settings and are always kept.
+func ConfigurableToolIDs() []string {
+	return []string{
+		ToolIDCalculator,
+		ToolIDDuckDuckGoSearch,
+		ToolIDBrowserUse,
+		ToolIDHTTPRequest,
+		ToolIDSequentialThinking,
+		ToolIDWikipedia,
+		ToolIDLibraryRetriever,
+		ToolIDLs,
+		ToolIDReadFile,
+		ToolIDWriteFile,
+		ToolIDEditFile,
+		ToolIDPatchFile,
+		ToolIDGlob,
+		ToolIDGrep,
+		ToolIDExecute,
+		ToolIDExecuteBackground,
+	}
+}
+
+// IsConfigurableTool reports whether id is one of ConfigurableToolIDs.
+func IsConfigurableTool(id string) bool {
+	return slices.Contains(ConfigurableToolIDs(), id)
+}
+
+// Tool filter (agents.enabled_tools) entries besides plain tool IDs:
+//   - ToolFilterNone gives the agent no tools at all;
+//   - ToolFilterDenyPrefix+id ("!execute") denies a single tool.
+//
+// An empty filter allows every tool. Plain IDs form an allow list; when the
+// filter only contains denials, every other tool stays allowed.
+const (
+	ToolFilterNone       = "none"
+	ToolFilterDenyPrefix = "!"
+)
+
+// IsValidToolFilterEntry reports whether entry may appear in agents.enabled_tools.
+func IsValidToolFilterEntry(entry string) bool {
+	if entry == ToolFilterNone {
+		return true
+	}
+	return IsConfigurableTool(strings.TrimPrefix(entry, ToolFilterDenyPrefix))
+}
+
+// NoToolsAllowed reports whether filter disables all tools.
+func NoToolsAllowed(filter []string) bool {
+	return slices.Contains(filter, ToolFilterNone)
+}
+
+// ToolAllowed reports whether the configurable tool id passes filter.
+func ToolAllowed(id string, filter []string) bool {
+	if len(filter) == 0 {
+		return true
+	}
+	if NoToolsAllowed(filter) || slices.Contains(filter, ToolFilterDenyPrefix+id) {
+		return false
+	}
+	hasAllowList := false
+	for _, entry := range filter {
+		if !strings.HasPrefix(entry, ToolFilterDenyPrefix) {
+			hasAllowList = true
+			break
+		}
+	}
+	return !hasAllowList || slices.Contains(filter, id)
+}
@@ -82,6 +82,12 @@ const toolDescriptionZh = `从用户私有知识库中搜索并检索相关信
 // maxConcurrentQueries limits the number of parallel retrieval goroutines.
 const maxConcurrentQueries = 5
 
+// Searcher is what a retriever tool searches: a retrieval.Service over libraries or a
+// retrieval.MemoryIndex over the reference files of a single message.
+type Searcher interface {
+	Search(ctx context.Context, input retrieval.SearchInput) ([]retrieval.SearchResult, error)
+}
+
 // NewLibraryRetrieverTool creates a new library retriever tool.
 func NewLibraryRetrieverTool(ctx context.Context, config *LibraryRetrieverConfig) (tool.InvokableTool, error) {
 	if config == nil {
@@ -91,15 +97,20 @@ func NewLibraryRetrieverTool(ctx context.Context, config *LibraryRetrieverConfig
 		config.TopK = 10
 	}
 
-	// Capture config in closure
-	libraryIDs := config.LibraryIDs
-	topK := config.TopK
-	matchThreshold := config.MatchThreshold
-	retriever := config.Retriever
+	var retriever Searcher
+	if config.Retriever != nil {
+		retriever = config.Retriever
+	}
+	return newRetrieverTool(ToolIDLibraryRetriever, selectDesc(toolDescriptionEng, toolDescriptionZh),
+		config.LibraryIDs, config.TopK, config.MatchThreshold, retriever, true)
+}
 
+// newRetrieverTool builds a multi-query retriever tool over retriever. requireLibraries makes the
+// tool report "no knowledge base" when libraryIDs is empty (library searches need library IDs).
+func newRetrieverTool(name, desc string, libraryIDs []int64, topK int, matchThreshold float64, retriever Searcher, requireLibraries bool) (tool.InvokableTool, error) {
 	return utils.InferTool(
-		ToolIDLibraryRetriever,
-		selectDesc(toolDescriptionEng, toolDescriptionZh),
+		name,
+		desc,
 		func(ctx context.Context, input *LibraryRetrieverInput) (*LibraryRetrieverOutput, error) {
 			// Validate input
 			if len(input.Queries) == 0 {
@@ -140,7 +151,7 @@ func NewLibraryRetrieverTool(ctx context.Context, config *LibraryRetrieverConfig
 			}
 
 			// Check if there are associated libraries
-			if len(libraryIDs) == 0 {
+			if requireLibraries && len(libraryIDs) == 0 {
 				return &LibraryRetrieverOutput{
 					TotalCount:  0,
 					Message:     "No knowledge base associated",
@@ -0,0 +1,31 @@
+package tools
+
+import (
+	"context"
+
+	"chatclaw/internal/services/retrieval"
+
+	"github.com/cloudwego/eino/components/tool"
+)
+
+const referenceToolDescriptionEng = `Search the reference documents the user attached to their latest message. These files are not in any knowledge base and are only available for this reply — use this tool FIRST for any question about them.
+
+Provide 2-5 queries from different angles; results from all queries are merged and ranked by relevance. Every result is a detailed chunk, so the level parameter can be omitted.`
+
+const referenceToolDescriptionZh = `检索用户在最新一条消息中附带的参考文档。这些文件不属于任何知识库，仅在本次回复中可用——凡是与这些文件相关的问题，务必先使用此工具。
+
+请提供 2-5 个不同角度的查询；所有查询的结果会合并并按相关性排序。结果均为详细片段，可省略 level 参数。`
+
+// NewReferenceRetrieverTool creates a retriever tool scoped to the ephemeral index of a
+// message's reference files.
+func NewReferenceRetrieverTool(ctx context.Context, index *retrieval.MemoryIndex, topK int) (tool.InvokableTool, error) {
+	if topK <= 0 {
+		topK = 10
+	}
+	var retriever Searcher
+	if index != nil {
+		retriever = index
+	}
+	return newRetrieverTool(ToolIDReferenceRetriever, selectDesc(referenceToolDescriptionEng, referenceToolDescriptionZh),
+		nil, topK, 0, retriever, false)
+}
@@ -55,3 +55,13 @@ func Wrapf(key string, cause error, data map[string]any) error {
 		Cause:   cause,
 	}
 }
+
+// ListErrorKeys 返回全部已定义的错误 key（含英文/中文文案和 Newf 可用的参数名）
+func ListErrorKeys() ([]i18n.ErrorKey, error) {
+	return i18n.ErrorKeys()
+}
+
+// ResolveErrorKey 按指定语言解析错误 key 的文案（未翻译的 key 返回可读的默认文案）
+func ResolveErrorKey(key, locale string) string {
+	return i18n.ResolveErrorKey(key, locale)
+}
@@ -0,0 +1,193 @@
+package tokenizer
+
+import (
+	"strings"
+	"sync/atomic"
+)
+
+// Settings keys holding the custom query rules. Stopwords are listed one per line (or separated
+// by commas); synonyms are one group of equivalent terms per line, separated by commas.
+const (
+	StopwordsSettingKey = "fts_stopwords"
+	SynonymsSettingKey  = "fts_synonyms"
+)
+
+const (
+	// MaxSynonymExpansion caps the synonyms added for one query term.
+	MaxSynonymExpansion = 5
+	// maxQueryTerms caps the total number of terms in a match query after expansion.
+	maxQueryTerms = 64
+)
+
+type queryRules struct {
+	stopwords map[string]struct{}
+	synonyms  map[string][]string // term -> other terms of its group
+}
+
+var rules atomic.Pointer[queryRules]
+
+func currentRules() *queryRules {
+	if r := rules.Load(); r != nil {
+		return r
+	}
+	return &queryRules{}
+}
+
+// ParseStopwords splits a stopword list setting into normalized, deduped words.
+func ParseStopwords(value string) []string {
+	var out []string
+	seen := make(map[string]struct{})
+	for _, w := range splitList(value) {
+		if w = normalizeToken(w); w == "" {
+			continue
+		}
+		if _, ok := seen[w]; ok {
+			continue
+		}
+		seen[w] = struct{}{}
+		out = append(out, w)
+	}
+	return out
+}
+
+// ParseSynonyms splits a synonym list setting into groups of normalized terms. Groups with fewer
+// than two distinct terms are dropped.
+func ParseSynonyms(value string) [][]string {
+	var out [][]string
+	for _, line := range strings.Split(strings.ReplaceAll(value, "\r\n", "\n"), "\n") {
+		var group []string
+		seen := make(map[string]struct{})
+		for _, t := range strings.FieldsFunc(line, isListSeparator) {
+			if t = normalizeToken(t); t == "" {
+				continue
+			}
+			if _, ok := seen[t]; ok {
+				continue
+			}
+			seen[t] = struct{}{}
+			group = append(group, t)
+		}
+		if len(group) >= 2 {
+			out = append(out, group)
+		}
+	}
+	return out
+}
+
+// FormatStopwords is the inverse of ParseStopwords.
+func FormatStopwords(words []string) string {
+	return strings.Join(ParseStopwords(strings.Join(words, "\n")), "\n")
+}
+
+// FormatSynonyms is the inverse of ParseSynonyms.
+func FormatSynonyms(groups [][]string) string {
+	lines := make([]string, 0, len(groups))
+	for _, g := range groups {
+		lines = append(lines, strings.Join(g, ","))
+	}
+	parsed := ParseSynonyms(strings.Join(lines, "\n"))
+	lines = lines[:0]
+	for _, g := range parsed {
+		lines = append(lines, strings.Join(g, ", "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// SetQueryRules replaces the stopword and synonym lists used by SearchTerms and BuildMatchQuery.
+// Both take the raw setting values. A term listed in several synonym groups expands to the union.
+func SetQueryRules(stopwords, synonyms string) {
+	r := &queryRules{
+		stopwords: make(map[string]struct{}),
+		synonyms:  make(map[string][]string),
+	}
+	for _, w := range ParseStopwords(stopwords) {
+		r.stopwords[w] = struct{}{}
+	}
+	for _, group := range ParseSynonyms(synonyms) {
+		for _, t := range group {
+			for _, other := range group {
+				if other != t && !containsString(r.synonyms[t], other) {
+					r.synonyms[t] = append(r.synonyms[t], other)
+				}
+			}
+		}
+	}
+	rules.Store(r)
+}
+
+// SearchTermGroups returns the search terms of keyword (see SearchTerms), each expanded into a
+// group: the term itself followed by at most MaxSynonymExpansion synonyms.
+func SearchTermGroups(keyword string) [][]string {
+	return SearchTermGroupsFor(keyword, LanguageAuto)
+}
+
+// SearchTermGroupsFor is SearchTermGroups for a library language hint (see ResolveLanguage).
+func SearchTermGroupsFor(keyword, hint string) [][]string {
+	r := currentRules()
+	terms := SearchTermsFor(keyword, hint)
+	groups := make([][]string, 0, len(terms))
+	for _, t := range terms {
+		syn := r.synonyms[t]
+		if len(syn) > MaxSynonymExpansion {
+			syn = syn[:MaxSynonymExpansion]
+		}
+		groups = append(groups, append([]string{t}, syn...))
+	}
+	return groups
+}
+
+// dropStopwords removes stopwords from terms. If every term is a stopword the terms are kept,
+// so a query never becomes empty just because of the list.
+func dropStopwords(terms []string) []string {
+	r := currentRules()
+	if len(r.stopwords) == 0 {
+		return terms
+	}
+	out := terms[:0:0]
+	for _, t := range terms {
+		if _, ok := r.stopwords[t]; !ok {
+			out = append(out, t)
+		}
+	}
+	if len(out) == 0 {
+		return terms
+	}
+	return out
+}
+
+// matchTerm renders one term for an FTS5 query: a prefix match for single words, a quoted phrase
+// for multi-word synonyms. It returns "" when nothing is left after escaping.
+func matchTerm(term string) string {
+	words := strings.Fields(term)
+	for i, w := range words {
+		words[i] = escapeFTS5Token(w)
+	}
+	words = strings.Fields(strings.Join(words, " "))
+	switch len(words) {
+	case 0:
+		return ""
+	case 1:
+		return words[0] + "*"
+	default:
+		return `"` + strings.Join(words, " ") + `"`
+	}
+}
+
+func splitList(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool {
+		return r == '\n' || r == '\r' || isListSeparator(r)
+	})
+}
+
+func isListSeparator(r rune) bool {
+	return r == ',' || r == '，' || r == ';' || r == '；'
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -4,6 +4,7 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"unicode"
 
 	"github.com/go-ego/gse"
@@ -17,6 +18,74 @@ const (
 	MaxPinyinChars = 200
 )
 
+// Segmentation languages (setting fts_language). Index and query must be tokenized with the same
+// language, so documents indexed under another one need RetokenizeDocuments.
+const (
+	// LanguageMulti segments with gse and also splits on non-word runes (default, mixed content)
+	LanguageMulti = "multi"
+	// LanguageZH segments with gse only
+	LanguageZH = "zh"
+	// LanguageEN splits on unicode word boundaries; the gse dictionary and pinyin are never loaded
+	LanguageEN = "en"
+)
+
+// LanguageSettingKey is the settings key holding the segmentation language.
+const LanguageSettingKey = "fts_language"
+
+// LanguageAuto is the library language hint that follows the global fts_language setting.
+const LanguageAuto = "auto"
+
+var language atomic.Value // string
+
+// NormalizeLanguage validates a language value ("" means LanguageMulti).
+func NormalizeLanguage(lang string) (string, bool) {
+	switch lang = strings.ToLower(strings.TrimSpace(lang)); lang {
+	case "":
+		return LanguageMulti, true
+	case LanguageMulti, LanguageZH, LanguageEN:
+		return lang, true
+	}
+	return "", false
+}
+
+// SetLanguage sets the segmentation language; invalid values fall back to LanguageMulti.
+func SetLanguage(lang string) {
+	if v, ok := NormalizeLanguage(lang); ok {
+		language.Store(v)
+		return
+	}
+	language.Store(LanguageMulti)
+}
+
+// Language returns the current segmentation language.
+func Language() string {
+	if v, ok := language.Load().(string); ok {
+		return v
+	}
+	return LanguageMulti
+}
+
+// NormalizeLibraryLanguage validates a per-library language hint: LanguageAuto ("" included) or one
+// of the segmentation languages.
+func NormalizeLibraryLanguage(hint string) (string, bool) {
+	switch hint = strings.ToLower(strings.TrimSpace(hint)); hint {
+	case "", LanguageAuto:
+		return LanguageAuto, true
+	case LanguageMulti, LanguageZH, LanguageEN:
+		return hint, true
+	}
+	return "", false
+}
+
+// ResolveLanguage returns the segmentation language for a library language hint: the hint itself,
+// or the global Language() for LanguageAuto and unknown values.
+func ResolveLanguage(hint string) string {
+	if v, ok := NormalizeLibraryLanguage(hint); ok && v != LanguageAuto {
+		return v
+	}
+	return Language()
+}
+
 var (
 	segOnce sync.Once
 	seg     gse.Segmenter
@@ -40,19 +109,53 @@ func initSegmenter() {
 	})
 }
 
+// segment splits text into search tokens according to the configured language.
+func segment(text string, lang string) []string {
+	if lang == LanguageEN {
+		return splitByNonWord(text)
+	}
+	initSegmenter()
+	segMu.Lock()
+	defer segMu.Unlock()
+	return seg.CutSearch(text, true)
+}
+
 // TokenizeName tokenizes a file name for FTS indexing
 // It removes the extension, segments the name, and generates pinyin tokens for Chinese characters
+// (pinyin is skipped for LanguageEN)
 func TokenizeName(originalName string) string {
-	initSegmenter()
+	return TokenizeNameFor(originalName, LanguageAuto)
+}
 
+// TokenizeNameFor is TokenizeName for a library language hint (see ResolveLanguage)
+func TokenizeNameFor(originalName, hint string) string {
 	// Remove extension for tokenization
 	ext := filepath.Ext(originalName)
-	nameWithoutExt := strings.TrimSuffix(originalName, ext)
+	result := nameTokens(strings.TrimSuffix(originalName, ext), ResolveLanguage(hint))
+
+	// Add extension as token (without dot, lowercase)
+	if ext != "" {
+		extToken := strings.ToLower(strings.TrimPrefix(ext, "."))
+		if !containsString(result, extToken) {
+			result = append(result, extToken)
+		}
+	}
+
+	return strings.Join(result, " ")
+}
+
+// TokenizeTitle tokenizes a short title (e.g. a conversation name) for FTS indexing, the same way
+// as TokenizeName but without treating a trailing ".xxx" as a file extension
+func TokenizeTitle(title string) string {
+	return strings.Join(nameTokens(title, Language()), " ")
+}
+
+// nameTokens segments a name and adds pinyin tokens for Chinese characters, deduped
+func nameTokens(nameWithoutExt, lang string) []string {
+	withPinyin := lang != LanguageEN
 
 	// Segment the name
-	segMu.Lock()
-	tokens := seg.CutSearch(nameWithoutExt, true)
-	segMu.Unlock()
+	tokens := segment(nameWithoutExt, lang)
 
 	// Clean and dedupe tokens
 	tokenSet := make(map[string]struct{})
@@ -70,7 +173,7 @@ func TokenizeName(originalName string) string {
 
 		// Generate pinyin tokens per segmented token (improves partial pinyin search)
 		ch := extractChinese(token)
-		if ch != "" && len([]rune(ch)) <= MaxPinyinChars {
+		if withPinyin && ch != "" && len([]rune(ch)) <= MaxPinyinChars {
 			for _, pt := range generatePinyinTokens(ch) {
 				if pt == "" {
 					continue
@@ -85,7 +188,11 @@ func TokenizeName(originalName string) string {
 	}
 
 	// Fallback: split by common filename separators (e.g. "foo_bar-v1" -> ["foo","bar","v1"])
-	for _, token := range splitByNonWord(nameWithoutExt) {
+	var fallback []string
+	if lang == LanguageMulti {
+		fallback = splitByNonWord(nameWithoutExt)
+	}
+	for _, token := range fallback {
 		token = normalizeToken(token)
 		if token == "" {
 			continue
@@ -96,7 +203,7 @@ func TokenizeName(originalName string) string {
 		}
 
 		ch := extractChinese(token)
-		if ch != "" && len([]rune(ch)) <= MaxPinyinChars {
+		if withPinyin && ch != "" && len([]rune(ch)) <= MaxPinyinChars {
 			for _, pt := range generatePinyinTokens(ch) {
 				if pt == "" {
 					continue
@@ -112,7 +219,7 @@ func TokenizeName(originalName string) string {
 
 	// Generate pinyin tokens for Chinese text (if short enough)
 	chineseText := extractChinese(nameWithoutExt)
-	if len([]rune(chineseText)) <= MaxPinyinChars && chineseText != "" {
+	if withPinyin && len([]rune(chineseText)) <= MaxPinyinChars && chineseText != "" {
 		pinyinTokens := generatePinyinTokens(chineseText)
 		for _, pt := range pinyinTokens {
 			if _, exists := tokenSet[pt]; !exists {
@@ -122,26 +229,19 @@ func TokenizeName(originalName string) string {
 		}
 	}
 
-	// Add extension as token (without dot, lowercase)
-	if ext != "" {
-		extToken := strings.ToLower(strings.TrimPrefix(ext, "."))
-		if _, exists := tokenSet[extToken]; !exists {
-			result = append(result, extToken)
-		}
-	}
-
-	return strings.Join(result, " ")
+	return result
 }
 
 // TokenizeContent tokenizes document content for FTS indexing
 // It segments the content and applies token limits to prevent oversized index entries
 func TokenizeContent(content string) string {
-	initSegmenter()
+	return TokenizeContentFor(content, LanguageAuto)
+}
 
+// TokenizeContentFor is TokenizeContent for a library language hint (see ResolveLanguage)
+func TokenizeContentFor(content, hint string) string {
 	// Segment content
-	segMu.Lock()
-	tokens := seg.CutSearch(content, true)
-	segMu.Unlock()
+	tokens := segment(content, ResolveLanguage(hint))
 
 	// Clean and dedupe tokens with limit
 	tokenSet := make(map[string]struct{})
@@ -165,41 +265,72 @@ func TokenizeContent(content string) string {
 }
 
 // BuildMatchQuery builds an FTS5 MATCH query string from user input
-// It tokenizes the input and generates prefix-match queries joined by OR
+// It tokenizes the input and generates prefix-match queries joined by OR; a term with synonyms
+// becomes a parenthesized OR group. The total number of terms is capped at maxQueryTerms.
 func BuildMatchQuery(keyword string) string {
-	keyword = strings.TrimSpace(keyword)
-	if keyword == "" {
+	return BuildMatchQueryFor(keyword, LanguageAuto)
+}
+
+// BuildMatchQueryFor is BuildMatchQuery for a library language hint; the query must be segmented
+// like the library's index (see ResolveLanguage)
+func BuildMatchQueryFor(keyword, hint string) string {
+	var queryParts []string
+	total := 0
+	for _, group := range SearchTermGroupsFor(keyword, hint) {
+		var alts []string
+		for _, term := range group {
+			if total >= maxQueryTerms {
+				break
+			}
+			// Escape FTS5 special characters and add prefix match
+			if m := matchTerm(term); m != "" {
+				alts = append(alts, m)
+				total++
+			}
+		}
+		switch len(alts) {
+		case 0:
+		case 1:
+			queryParts = append(queryParts, alts[0])
+		default:
+			queryParts = append(queryParts, "("+strings.Join(alts, " OR ")+")")
+		}
+	}
+
+	if len(queryParts) == 0 {
 		return ""
 	}
 
-	initSegmenter()
+	// Join with OR for more flexible matching
+	return strings.Join(queryParts, " OR ")
+}
 
-	// Segment the keyword
-	segMu.Lock()
-	tokens := seg.CutSearch(keyword, true)
-	segMu.Unlock()
+// SearchTerms returns the normalized (lowercase, deduped) search terms of user input, using the
+// same segmentation as BuildMatchQuery, with custom stopwords removed. Useful for highlighting
+// matches outside of FTS5.
+func SearchTerms(keyword string) []string {
+	return SearchTermsFor(keyword, LanguageAuto)
+}
 
-	// Build match query with prefix matching
-	var queryParts []string
-	seen := make(map[string]struct{})
+// SearchTermsFor is SearchTerms for a library language hint (see ResolveLanguage)
+func SearchTermsFor(keyword, hint string) []string {
+	keyword = strings.TrimSpace(keyword)
+	if keyword == "" {
+		return nil
+	}
 
-	for _, token := range tokens {
-		token = normalizeToken(token)
-		if token == "" {
-			continue
-		}
-		if _, exists := seen[token]; exists {
-			continue
-		}
-		seen[token] = struct{}{}
+	// Segment the keyword
+	lang := ResolveLanguage(hint)
+	tokens := segment(keyword, lang)
 
-		// Escape FTS5 special characters and add prefix match
-		escaped := escapeFTS5Token(token)
-		queryParts = append(queryParts, escaped+"*")
+	// Fallback: also split by non-word separators to support typical filenames like "foo_bar-v1.pdf"
+	if lang == LanguageMulti {
+		tokens = append(tokens, splitByNonWord(keyword)...)
 	}
 
-	// Fallback: also split by non-word separators to support typical filenames like "foo_bar-v1.pdf"
-	for _, token := range splitByNonWord(keyword) {
+	var terms []string
+	seen := make(map[string]struct{})
+	for _, token := range tokens {
 		token = normalizeToken(token)
 		if token == "" {
 			continue
@@ -208,16 +339,9 @@ func BuildMatchQuery(keyword string) string {
 			continue
 		}
 		seen[token] = struct{}{}
-		escaped := escapeFTS5Token(token)
-		queryParts = append(queryParts, escaped+"*")
-	}
-
-	if len(queryParts) == 0 {
-		return ""
+		terms = append(terms, token)
 	}
-
-	// Join with OR for more flexible matching
-	return strings.Join(queryParts, " OR ")
+	return dropStopwords(terms)
 }
 
 // splitByNonWord splits text by any rune that is not a letter, digit, or Han character.
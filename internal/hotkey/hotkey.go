@@ -0,0 +1,298 @@
+// Package hotkey registers system-wide keyboard shortcuts.
+//
+// Accelerators use the same notation as menu accelerators, e.g. "CmdOrCtrl+Shift+H":
+// modifiers CmdOrCtrl, Ctrl, Alt (Option), Shift and Super (Cmd/Win) joined by "+" with
+// a single key (A-Z, 0-9, F1-F12 or Space). Each binding is owned by an id (usually the
+// settings key it comes from); two ids can never hold the same effective key combination,
+// and combinations other features rely on can be reserved so they are never grabbed.
+package hotkey
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+var (
+	// ErrInvalid is returned for an accelerator that cannot be parsed.
+	ErrInvalid = errors.New("invalid hotkey")
+	// ErrUnsupported is returned when global hotkeys are not available on this platform.
+	ErrUnsupported = errors.New("global hotkeys are not supported on this platform")
+)
+
+// ConflictError reports that an accelerator is already bound or reserved by Owner.
+type ConflictError struct {
+	Accelerator string
+	Owner       string
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("hotkey %s is already used by %s", e.Accelerator, e.Owner)
+}
+
+// Modifier bits of a resolved key combination.
+const (
+	modCtrl uint8 = 1 << iota
+	modAlt
+	modShift
+	modSuper
+	modCmdOrCtrl // resolved to modSuper on macOS and modCtrl elsewhere
+)
+
+// combo is a parsed accelerator.
+type combo struct {
+	mods uint8
+	key  string // canonical key name: "A".."Z", "0".."9", "F1".."F12", "Space"
+}
+
+// cmdOrCtrlTarget is the modifier CmdOrCtrl stands for on the current platform.
+func cmdOrCtrlTarget() uint8 {
+	if runtime.GOOS == "darwin" {
+		return modSuper
+	}
+	return modCtrl
+}
+
+// effective resolves CmdOrCtrl for the current platform so combos can be compared.
+func (c combo) effective() combo {
+	if c.mods&modCmdOrCtrl != 0 {
+		c.mods = c.mods&^modCmdOrCtrl | cmdOrCtrlTarget()
+	}
+	return c
+}
+
+func (c combo) String() string {
+	parts := make([]string, 0, 6)
+	for _, m := range []struct {
+		bit  uint8
+		name string
+	}{{modCmdOrCtrl, "CmdOrCtrl"}, {modCtrl, "Ctrl"}, {modAlt, "Alt"}, {modShift, "Shift"}, {modSuper, "Super"}} {
+		if c.mods&m.bit != 0 {
+			parts = append(parts, m.name)
+		}
+	}
+	return strings.Join(append(parts, c.key), "+")
+}
+
+var modifierNames = map[string]uint8{
+	"cmdorctrl":        modCmdOrCtrl,
+	"commandorcontrol": modCmdOrCtrl,
+	"ctrl":             modCtrl,
+	"control":          modCtrl,
+	"alt":              modAlt,
+	"option":           modAlt,
+	"shift":            modShift,
+	"super":            modSuper,
+	"cmd":              modSuper,
+	"command":          modSuper,
+	"meta":             modSuper,
+	"win":              modSuper,
+}
+
+func parse(accel string) (combo, error) {
+	var c combo
+	parts := strings.Split(strings.TrimSpace(accel), "+")
+	for i, p := range parts {
+		p = strings.TrimSpace(p)
+		if i < len(parts)-1 {
+			bit, ok := modifierNames[strings.ToLower(p)]
+			if !ok || c.mods&bit != 0 {
+				return combo{}, fmt.Errorf("%w: %q", ErrInvalid, accel)
+			}
+			c.mods |= bit
+			continue
+		}
+		key, ok := canonicalKey(p)
+		if !ok {
+			return combo{}, fmt.Errorf("%w: %q", ErrInvalid, accel)
+		}
+		c.key = key
+	}
+	// A bare key (other than a function key) would swallow normal typing system-wide
+	if c.mods == 0 && !strings.HasPrefix(c.key, "F") {
+		return combo{}, fmt.Errorf("%w: %q needs a modifier", ErrInvalid, accel)
+	}
+	// CmdOrCtrl together with the modifier it resolves to is a duplicate
+	if c.mods&modCmdOrCtrl != 0 && c.mods&cmdOrCtrlTarget() != 0 {
+		return combo{}, fmt.Errorf("%w: %q", ErrInvalid, accel)
+	}
+	return c, nil
+}
+
+func canonicalKey(s string) (string, bool) {
+	u := strings.ToUpper(s)
+	switch {
+	case len(u) == 1 && (u[0] >= 'A' && u[0] <= 'Z' || u[0] >= '0' && u[0] <= '9'):
+		return u, true
+	case u == "SPACE":
+		return "Space", true
+	case len(u) >= 2 && len(u) <= 3 && u[0] == 'F':
+		for n := 1; n <= 12; n++ {
+			if u[1:] == fmt.Sprint(n) {
+				return u, true
+			}
+		}
+	}
+	return "", false
+}
+
+// Normalize parses an accelerator and returns its canonical spelling.
+// An empty string stays empty (no hotkey).
+func Normalize(accel string) (string, error) {
+	if strings.TrimSpace(accel) == "" {
+		return "", nil
+	}
+	c, err := parse(accel)
+	if err != nil {
+		return "", err
+	}
+	return c.String(), nil
+}
+
+type binding struct {
+	accel  string // canonical; empty when unbound
+	combo  combo  // effective combo, valid when accel != ""
+	action func()
+	handle uintptr // platform registration, 0 when not registered
+}
+
+var (
+	mu       sync.Mutex
+	bindings = map[string]*binding{}
+	reserved = map[combo]string{} // effective combo -> owner
+
+	// Platform backend (hotkey_<os>.go); variables so tests can stub them.
+	supported  = platformSupported
+	register   = platformRegister
+	unregister = platformUnregister
+)
+
+// Reserve marks an accelerator as used by owner (e.g. a shortcut the app simulates),
+// so no binding may take it. It panics on an invalid accelerator.
+func Reserve(accel, owner string) {
+	c, err := parse(accel)
+	if err != nil {
+		panic(err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	reserved[c.effective()] = owner
+}
+
+// Check validates accel for id without changing any binding and returns its canonical
+// spelling. It fails with ErrInvalid, a *ConflictError or, for a non-empty accel on a
+// platform without global hotkeys, ErrUnsupported.
+func Check(id, accel string) (string, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	canon, _, err := checkLocked(id, accel)
+	return canon, err
+}
+
+func checkLocked(id, accel string) (string, combo, error) {
+	if strings.TrimSpace(accel) == "" {
+		return "", combo{}, nil
+	}
+	c, err := parse(accel)
+	if err != nil {
+		return "", combo{}, err
+	}
+	canon, eff := c.String(), c.effective()
+	if owner, ok := reserved[eff]; ok {
+		return "", combo{}, &ConflictError{Accelerator: canon, Owner: owner}
+	}
+	for other, b := range bindings {
+		if other != id && b.accel != "" && b.combo == eff {
+			return "", combo{}, &ConflictError{Accelerator: canon, Owner: other}
+		}
+	}
+	if !supported {
+		return "", combo{}, ErrUnsupported
+	}
+	return canon, eff, nil
+}
+
+// Set binds accel to action under id, replacing any previous binding of id. An empty
+// accel only removes the binding. On failure the previous binding stays in place.
+func Set(id, accel string, action func()) error {
+	mu.Lock()
+	defer mu.Unlock()
+	return setLocked(id, accel, action)
+}
+
+// Update rebinds id to accel keeping the action given to Set. Before Set has been
+// called for id, the accelerator is only validated and remembered.
+func Update(id, accel string) error {
+	mu.Lock()
+	defer mu.Unlock()
+	var action func()
+	if b := bindings[id]; b != nil {
+		action = b.action
+	}
+	return setLocked(id, accel, action)
+}
+
+// Remove unbinds id.
+func Remove(id string) {
+	mu.Lock()
+	defer mu.Unlock()
+	if b := bindings[id]; b != nil {
+		if b.handle != 0 {
+			unregister(b.handle)
+		}
+		delete(bindings, id)
+	}
+}
+
+// Current returns the canonical accelerator bound to id, or "".
+func Current(id string) string {
+	mu.Lock()
+	defer mu.Unlock()
+	if b := bindings[id]; b != nil {
+		return b.accel
+	}
+	return ""
+}
+
+func setLocked(id, accel string, action func()) error {
+	canon, eff, err := checkLocked(id, accel)
+	if err != nil {
+		return err
+	}
+	old := bindings[id]
+	next := &binding{accel: canon, combo: eff, action: action}
+	switch {
+	case canon == "" || action == nil:
+		// Nothing to register (yet)
+	case old != nil && old.handle != 0 && old.combo == eff:
+		// Same keys, possibly spelled differently ("Ctrl+H" vs "CmdOrCtrl+H" on Windows):
+		// keep the existing registration
+		next.handle, old.handle = old.handle, 0
+	default:
+		h, err := register(eff, func() { dispatch(id) })
+		if err != nil {
+			return err
+		}
+		next.handle = h
+	}
+	if old != nil && old.handle != 0 {
+		unregister(old.handle)
+	}
+	bindings[id] = next
+	return nil
+}
+
+// dispatch runs the action bound to id; called by the platform backend on a key press.
+func dispatch(id string) {
+	mu.Lock()
+	var action func()
+	if b := bindings[id]; b != nil {
+		action = b.action
+	}
+	mu.Unlock()
+	if action != nil {
+		action()
+	}
+}
@@ -0,0 +1,146 @@
+//go:build darwin && cgo
+
+package hotkey
+
+/*
+#cgo darwin LDFLAGS: -framework Carbon
+
+#include <Carbon/Carbon.h>
+#include <dispatch/dispatch.h>
+#include <pthread.h>
+
+extern void hotkeyDarwinPressed(unsigned int id);
+
+static EventHandlerRef hotkeyHandlerRef = NULL;
+
+static OSStatus hotkeyHandler(EventHandlerCallRef next, EventRef event, void *data) {
+	EventHotKeyID hkID;
+	if (GetEventParameter(event, kEventParamDirectObject, typeEventHotKeyID, NULL, sizeof(hkID), NULL, &hkID) == noErr) {
+		hotkeyDarwinPressed(hkID.id);
+	}
+	return noErr;
+}
+
+// Carbon hotkeys are delivered through the application event target, so registration
+// runs on the main thread.
+static void runOnMain(dispatch_block_t work) {
+	if (pthread_main_np()) {
+		work();
+	} else {
+		dispatch_sync(dispatch_get_main_queue(), work);
+	}
+}
+
+static int registerHotkeyDarwin(unsigned int id, unsigned int keyCode, unsigned int mods, EventHotKeyRef *out) {
+	__block OSStatus status = noErr;
+	runOnMain(^{
+		if (hotkeyHandlerRef == NULL) {
+			EventTypeSpec spec = {kEventClassKeyboard, kEventHotKeyPressed};
+			status = InstallApplicationEventHandler(&hotkeyHandler, 1, &spec, NULL, &hotkeyHandlerRef);
+			if (status != noErr) {
+				return;
+			}
+		}
+		EventHotKeyID hkID = {'CCHK', id};
+		status = RegisterEventHotKey(keyCode, mods, hkID, GetApplicationEventTarget(), 0, out);
+	});
+	return (int)status;
+}
+
+static void unregisterHotkeyDarwin(EventHotKeyRef ref) {
+	runOnMain(^{
+		UnregisterEventHotKey(ref);
+	});
+}
+*/
+import "C"
+
+import (
+	"fmt"
+	"sync"
+
+	"chatclaw/internal/safego"
+)
+
+const platformSupported = true
+
+// Carbon modifier masks (Events.h)
+const (
+	carbonCmdKey     = 0x0100
+	carbonShiftKey   = 0x0200
+	carbonOptionKey  = 0x0800
+	carbonControlKey = 0x1000
+)
+
+// Virtual key codes (kVK_* in Events.h); they follow the ANSI layout, not the letters.
+var virtualKeys = map[string]C.uint{
+	"A": 0x00, "S": 0x01, "D": 0x02, "F": 0x03, "H": 0x04, "G": 0x05, "Z": 0x06, "X": 0x07,
+	"C": 0x08, "V": 0x09, "B": 0x0B, "Q": 0x0C, "W": 0x0D, "E": 0x0E, "R": 0x0F, "Y": 0x10,
+	"T": 0x11, "1": 0x12, "2": 0x13, "3": 0x14, "4": 0x15, "6": 0x16, "5": 0x17, "9": 0x19,
+	"7": 0x1A, "8": 0x1C, "0": 0x1D, "O": 0x1F, "U": 0x20, "I": 0x22, "P": 0x23, "L": 0x25,
+	"J": 0x26, "K": 0x28, "N": 0x2D, "M": 0x2E, "Space": 0x31,
+	"F1": 0x7A, "F2": 0x78, "F3": 0x63, "F4": 0x76, "F5": 0x60, "F6": 0x61,
+	"F7": 0x62, "F8": 0x64, "F9": 0x65, "F10": 0x6D, "F11": 0x67, "F12": 0x6F,
+}
+
+var (
+	darwinMu      sync.Mutex
+	darwinNextID  uintptr
+	darwinRefs    = map[uintptr]C.EventHotKeyRef{}
+	darwinActions = map[uintptr]func(){}
+)
+
+//export hotkeyDarwinPressed
+func hotkeyDarwinPressed(id C.uint) {
+	darwinMu.Lock()
+	action := darwinActions[uintptr(id)]
+	darwinMu.Unlock()
+	if action != nil {
+		// Leave the main thread right away; the action may call back into Set.
+		safego.Go("hotkey.action", action, nil)
+	}
+}
+
+func platformRegister(c combo, action func()) (uintptr, error) {
+	var mods C.uint
+	if c.mods&modCtrl != 0 {
+		mods |= carbonControlKey
+	}
+	if c.mods&modAlt != 0 {
+		mods |= carbonOptionKey
+	}
+	if c.mods&modShift != 0 {
+		mods |= carbonShiftKey
+	}
+	if c.mods&modSuper != 0 {
+		mods |= carbonCmdKey
+	}
+
+	darwinMu.Lock()
+	darwinNextID++
+	id := darwinNextID
+	darwinMu.Unlock()
+
+	var ref C.EventHotKeyRef
+	if status := C.registerHotkeyDarwin(C.uint(id), virtualKeys[c.key], mods, &ref); status != 0 {
+		// eventHotKeyExistsErr (-9878) when another application owns the combination
+		return 0, fmt.Errorf("register hotkey %s: OSStatus %d", c, int(status))
+	}
+
+	darwinMu.Lock()
+	darwinRefs[id] = ref
+	darwinActions[id] = action
+	darwinMu.Unlock()
+	return id, nil
+}
+
+func platformUnregister(id uintptr) {
+	darwinMu.Lock()
+	ref, ok := darwinRefs[id]
+	delete(darwinRefs, id)
+	delete(darwinActions, id)
+	darwinMu.Unlock()
+	if ok {
+		C.unregisterHotkeyDarwin(ref)
+	}
+}
@@ -0,0 +1,13 @@
+//go:build !windows && !(darwin && cgo)
+
+package hotkey
+
+// Global hotkeys need a platform backend; elsewhere (Linux, macOS without cgo) only
+// validation and conflict checks are available.
+const platformSupported = false
+
+func platformRegister(c combo, action func()) (uintptr, error) {
+	return 0, ErrUnsupported
+}
+
+func platformUnregister(id uintptr) {}
@@ -0,0 +1,184 @@
+package hotkey
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+)
+
+// stubBackend replaces the platform backend for the duration of a test.
+func stubBackend(t *testing.T) map[uintptr]combo {
+	t.Helper()
+	live := map[uintptr]combo{}
+	var next uintptr
+	prevSupported, prevRegister, prevUnregister := supported, register, unregister
+	prevBindings, prevReserved := bindings, reserved
+	supported = true
+	register = func(c combo, action func()) (uintptr, error) {
+		for _, other := range live {
+			if other == c {
+				return 0, errors.New("already registered")
+			}
+		}
+		next++
+		live[next] = c
+		return next, nil
+	}
+	unregister = func(id uintptr) { delete(live, id) }
+	bindings = map[string]*binding{}
+	reserved = map[combo]string{}
+	t.Cleanup(func() {
+		supported, register, unregister = prevSupported, prevRegister, prevUnregister
+		bindings, reserved = prevBindings, prevReserved
+	})
+	return live
+}
+
+func TestNormalize(t *testing.T) {
+	cases := []struct {
+		in, want string
+		wantErr  bool
+	}{
+		{"", "", false},
+		{"  ", "", false},
+		{"ctrl+shift+h", "Ctrl+Shift+H", false},
+		{"Shift + CmdOrCtrl + f", "CmdOrCtrl+Shift+F", false},
+		{"CommandOrControl+Space", "CmdOrCtrl+Space", false},
+		{"Cmd+Option+1", "Alt+Super+1", false},
+		{"F9", "F9", false},
+		{"alt+f12", "Alt+F12", false},
+		{"H", "", true},           // bare key needs a modifier
+		{"Ctrl+Ctrl+H", "", true}, // duplicate modifier
+		{"Ctrl+Hyper+H", "", true},
+		{"Ctrl+", "", true},
+		{"Ctrl+F13", "", true},
+		{"Ctrl+Shift", "", true},
+	}
+	for _, tc := range cases {
+		got, err := Normalize(tc.in)
+		if tc.wantErr {
+			if !errors.Is(err, ErrInvalid) {
+				t.Errorf("Normalize(%q) err = %v, want ErrInvalid", tc.in, err)
+			}
+			continue
+		}
+		if err != nil || got != tc.want {
+			t.Errorf("Normalize(%q) = %q, %v; want %q", tc.in, got, err, tc.want)
+		}
+	}
+}
+
+func TestNormalizeCmdOrCtrlDuplicate(t *testing.T) {
+	dup := "CmdOrCtrl+Ctrl+H"
+	if runtime.GOOS == "darwin" {
+		dup = "CmdOrCtrl+Cmd+H"
+	}
+	if _, err := Normalize(dup); !errors.Is(err, ErrInvalid) {
+		t.Fatalf("Normalize(%q) err = %v, want ErrInvalid", dup, err)
+	}
+}
+
+func TestCheckConflicts(t *testing.T) {
+	stubBackend(t)
+	Reserve("CmdOrCtrl+C", "copy")
+
+	var conflict *ConflictError
+	if _, err := Check("a", "CmdOrCtrl+C"); !errors.As(err, &conflict) || conflict.Owner != "copy" {
+		t.Fatalf("reserved combo: err = %v", err)
+	}
+
+	if err := Set("a", "CmdOrCtrl+Shift+H", func() {}); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	// Same effective keys spelled differently still conflict
+	same := "Ctrl+Shift+H"
+	if runtime.GOOS == "darwin" {
+		same = "Cmd+Shift+H"
+	}
+	if _, err := Check("b", same); !errors.As(err, &conflict) || conflict.Owner != "a" {
+		t.Fatalf("duplicate binding: err = %v", err)
+	}
+	// An id never conflicts with itself
+	if got, err := Check("a", same); err != nil || got == "" {
+		t.Fatalf("rebinding own combo: %q, %v", got, err)
+	}
+	if got, err := Check("b", ""); err != nil || got != "" {
+		t.Fatalf("empty accelerator: %q, %v", got, err)
+	}
+}
+
+func TestSetRebindAndRemove(t *testing.T) {
+	live := stubBackend(t)
+
+	if err := Set("a", "Alt+1", func() {}); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if err := Set("a", "Alt+2", func() {}); err != nil {
+		t.Fatalf("rebind: %v", err)
+	}
+	if len(live) != 1 || Current("a") != "Alt+2" {
+		t.Fatalf("after rebind: live = %v, current = %q", live, Current("a"))
+	}
+
+	// A failed registration keeps the previous binding
+	live[99] = combo{mods: modAlt, key: "3"}
+	if err := Set("a", "Alt+3", func() {}); err == nil {
+		t.Fatal("expected registration failure")
+	}
+	delete(live, 99)
+	if len(live) != 1 || Current("a") != "Alt+2" {
+		t.Fatalf("after failed rebind: live = %v, current = %q", live, Current("a"))
+	}
+
+	if err := Update("a", ""); err != nil {
+		t.Fatalf("Update to empty: %v", err)
+	}
+	if len(live) != 0 || Current("a") != "" {
+		t.Fatalf("after unbind: live = %v, current = %q", live, Current("a"))
+	}
+
+	// Update keeps the action from Set
+	fired := make(chan struct{}, 1)
+	if err := Set("b", "", func() { fired <- struct{}{} }); err != nil {
+		t.Fatalf("Set empty: %v", err)
+	}
+	if err := Update("b", "Shift+F5"); err != nil || len(live) != 1 {
+		t.Fatalf("Update: %v, live = %v", err, live)
+	}
+	dispatch("b")
+	select {
+	case <-fired:
+	default:
+		t.Fatal("action not kept by Update")
+	}
+
+	Remove("b")
+	if len(live) != 0 || Current("b") != "" {
+		t.Fatalf("after Remove: live = %v, current = %q", live, Current("b"))
+	}
+}
+
+func TestUpdateBeforeSetOnlyRemembers(t *testing.T) {
+	live := stubBackend(t)
+	if err := Update("a", "Ctrl+Alt+K"); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if len(live) != 0 || Current("a") != "Ctrl+Alt+K" {
+		t.Fatalf("live = %v, current = %q", live, Current("a"))
+	}
+	// The remembered accelerator already blocks other ids
+	if _, err := Check("b", "Ctrl+Alt+K"); err == nil {
+		t.Fatal("expected conflict with remembered accelerator")
+	}
+}
+
+func TestUnsupportedPlatform(t *testing.T) {
+	stubBackend(t)
+	supported = false
+	if _, err := Check("a", "Ctrl+H"); !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("err = %v, want ErrUnsupported", err)
+	}
+	if _, err := Check("a", ""); err != nil {
+		t.Fatalf("empty accelerator on unsupported platform: %v", err)
+	}
+}
@@ -0,0 +1,170 @@
+//go:build windows
+
+package hotkey
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"unsafe"
+
+	"chatclaw/internal/safego"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	modUser32   = windows.NewLazySystemDLL("user32.dll")
+	modKernel32 = windows.NewLazySystemDLL("kernel32.dll")
+
+	procRegisterHotKey     = modUser32.NewProc("RegisterHotKey")
+	procUnregisterHotKey   = modUser32.NewProc("UnregisterHotKey")
+	procGetMessageW        = modUser32.NewProc("GetMessageW")
+	procPeekMessageW       = modUser32.NewProc("PeekMessageW")
+	procPostThreadMessageW = modUser32.NewProc("PostThreadMessageW")
+	procGetCurrentThreadId = modKernel32.NewProc("GetCurrentThreadId")
+)
+
+const (
+	platformSupported = true
+
+	wmHotkey = 0x0312
+	wmUser   = 0x0400
+	wmApp    = 0x8000 // wakes the loop to run queued requests
+
+	pmNoRemove = 0x0000
+
+	winModAlt      = 0x0001
+	winModControl  = 0x0002
+	winModShift    = 0x0004
+	winModWin      = 0x0008
+	winModNoRepeat = 0x4000
+)
+
+type winMsg struct {
+	Hwnd     uintptr
+	Message  uint32
+	WParam   uintptr
+	LParam   uintptr
+	Time     uint32
+	Pt       struct{ X, Y int32 }
+	LPrivate uint32
+}
+
+// RegisterHotKey with a nil window posts WM_HOTKEY to the calling thread's queue, so
+// every registration must happen on the single thread that runs the message loop.
+type hotkeyThread struct {
+	once     sync.Once
+	tid      uintptr
+	requests chan func()
+	actions  map[uintptr]func() // hotkey id -> action; only touched on the loop thread
+	nextID   uintptr
+}
+
+var thread = &hotkeyThread{requests: make(chan func(), 8), actions: map[uintptr]func(){}}
+
+func (t *hotkeyThread) start() {
+	t.once.Do(func() {
+		ready := make(chan struct{})
+		go t.loop(ready)
+		<-ready
+	})
+}
+
+// do runs f on the loop thread and waits for it.
+func (t *hotkeyThread) do(f func()) {
+	t.start()
+	done := make(chan struct{})
+	t.requests <- func() { f(); close(done) }
+	procPostThreadMessageW.Call(t.tid, wmApp, 0, 0)
+	<-done
+}
+
+func (t *hotkeyThread) loop(ready chan struct{}) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	t.tid, _, _ = procGetCurrentThreadId.Call()
+	// Force creation of the thread message queue before anyone posts to it
+	var m winMsg
+	procPeekMessageW.Call(uintptr(unsafe.Pointer(&m)), 0, wmUser, wmUser, pmNoRemove)
+	close(ready)
+
+	for {
+		ret, _, _ := procGetMessageW.Call(uintptr(unsafe.Pointer(&m)), 0, 0, 0)
+		if int32(ret) <= 0 {
+			return
+		}
+		switch m.Message {
+		case wmApp:
+			for drained := false; !drained; {
+				select {
+				case f := <-t.requests:
+					f()
+				default:
+					drained = true
+				}
+			}
+		case wmHotkey:
+			if action := t.actions[m.WParam]; action != nil {
+				// Never run the action on the loop thread: it may call back into Set,
+				// which waits for this thread.
+				safego.Go("hotkey.action", action, nil)
+			}
+		}
+	}
+}
+
+var virtualKeys = map[string]uintptr{"Space": 0x20}
+
+func init() {
+	for c := 'A'; c <= 'Z'; c++ {
+		virtualKeys[string(c)] = uintptr(c)
+	}
+	for c := '0'; c <= '9'; c++ {
+		virtualKeys[string(c)] = uintptr(c)
+	}
+	for n := 1; n <= 12; n++ {
+		virtualKeys[fmt.Sprintf("F%d", n)] = uintptr(0x70 + n - 1)
+	}
+}
+
+func platformRegister(c combo, action func()) (uintptr, error) {
+	mods := uintptr(winModNoRepeat)
+	if c.mods&modCtrl != 0 {
+		mods |= winModControl
+	}
+	if c.mods&modAlt != 0 {
+		mods |= winModAlt
+	}
+	if c.mods&modShift != 0 {
+		mods |= winModShift
+	}
+	if c.mods&modSuper != 0 {
+		mods |= winModWin
+	}
+	vk := virtualKeys[c.key]
+
+	var id uintptr
+	var err error
+	thread.do(func() {
+		thread.nextID++
+		next := thread.nextID
+		ret, _, callErr := procRegisterHotKey.Call(0, next, mods, vk)
+		if ret == 0 {
+			// Usually ERROR_HOTKEY_ALREADY_REGISTERED: another application owns it
+			err = fmt.Errorf("register hotkey %s: %w", c, callErr)
+			return
+		}
+		thread.actions[next] = action
+		id = next
+	})
+	return id, err
+}
+
+func platformUnregister(id uintptr) {
+	thread.do(func() {
+		procUnregisterHotKey.Call(0, id)
+		delete(thread.actions, id)
+	})
+}
@@ -0,0 +1,282 @@
+package httpclient
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"chatclaw/internal/logger"
+)
+
+const (
+	// AuditBufferSize is the number of calls kept by the audit log; older calls are overwritten.
+	AuditBufferSize = 100
+	// auditRequestLimit bounds the request body stored per call; it is large enough to keep the
+	// full prompt of most chat requests for ExportLLMCall-style reports.
+	auditRequestLimit = 32 * 1024
+	// auditBodyLimit bounds the response text stored per call.
+	auditBodyLimit = 4 * 1024
+	// auditTailLimit is the trailing part of a response kept for token usage, which streaming
+	// APIs send in the last chunk.
+	auditTailLimit = 2 * 1024
+
+	truncatedMarker = "\n… (truncated) …\n"
+)
+
+// Call is one provider HTTP request recorded by the audit log. Request and Response are
+// truncated and have credentials masked; headers are never recorded.
+type Call struct {
+	ID           int64     `json:"id"`
+	StartedAt    time.Time `json:"started_at"`
+	Provider     string    `json:"provider"` // request host
+	Method       string    `json:"method"`
+	Endpoint     string    `json:"endpoint"`
+	Model        string    `json:"model"`
+	Status       int       `json:"status"`
+	LatencyMs    int64     `json:"latency_ms"` // until the response body is fully read or closed
+	InputTokens  int       `json:"input_tokens"`
+	OutputTokens int       `json:"output_tokens"`
+	Request      string    `json:"request"`
+	Response     string    `json:"response"`
+	Error        string    `json:"error,omitempty"`
+}
+
+var auditEnabled atomic.Bool
+
+// SetAuditEnabled turns recording of provider calls on or off. Calls already in flight are
+// unaffected.
+func SetAuditEnabled(enabled bool) {
+	auditEnabled.Store(enabled)
+}
+
+// AuditEnabled reports whether provider calls are being recorded.
+func AuditEnabled() bool {
+	return auditEnabled.Load()
+}
+
+// auditLog is a fixed-size ring of the most recent calls.
+var auditLog struct {
+	sync.Mutex
+	calls  [AuditBufferSize]*Call
+	next   int
+	lastID int64
+}
+
+func recordCall(c *Call) {
+	auditLog.Lock()
+	defer auditLog.Unlock()
+	auditLog.lastID++
+	c.ID = auditLog.lastID
+	auditLog.calls[auditLog.next] = c
+	auditLog.next = (auditLog.next + 1) % AuditBufferSize
+}
+
+// RecentCalls returns the recorded calls, newest first.
+func RecentCalls() []Call {
+	auditLog.Lock()
+	defer auditLog.Unlock()
+	out := make([]Call, 0, AuditBufferSize)
+	for i := 1; i <= AuditBufferSize; i++ {
+		c := auditLog.calls[(auditLog.next-i+AuditBufferSize)%AuditBufferSize]
+		if c == nil {
+			break
+		}
+		out = append(out, *c)
+	}
+	return out
+}
+
+// FindCall returns the recorded call with the given ID, if it is still in the log.
+func FindCall(id int64) (Call, bool) {
+	auditLog.Lock()
+	defer auditLog.Unlock()
+	for _, c := range auditLog.calls {
+		if c != nil && c.ID == id {
+			return *c, true
+		}
+	}
+	return Call{}, false
+}
+
+// ClearCalls empties the audit log.
+func ClearCalls() {
+	auditLog.Lock()
+	defer auditLog.Unlock()
+	auditLog.calls = [AuditBufferSize]*Call{}
+	auditLog.next = 0
+}
+
+// auditRoundTrip performs req on rt and records it. The call is added to the log once the
+// response body is drained or closed, so streamed responses are captured in full latency.
+func auditRoundTrip(rt http.RoundTripper, req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	call := &Call{
+		StartedAt: start,
+		Provider:  req.URL.Host,
+		Method:    req.Method,
+		Endpoint:  logger.RedactString(req.URL.String()),
+	}
+
+	body, req := peekRequestBody(req)
+	call.Model = requestModel(body, req.URL.Path)
+	if len(body) > auditRequestLimit {
+		body = append(body[:auditRequestLimit:auditRequestLimit], truncatedMarker...)
+	}
+	call.Request = redactBody(body)
+
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		call.LatencyMs = time.Since(start).Milliseconds()
+		call.Error = logger.RedactString(err.Error())
+		recordCall(call)
+		return nil, err
+	}
+	call.Status = resp.StatusCode
+	resp.Body = &auditBody{ReadCloser: resp.Body, call: call, start: start}
+	return resp, nil
+}
+
+// peekRequestBody returns up to auditRequestLimit+1 bytes of the request body (the extra byte
+// marks truncation), leaving the body readable by the transport. Requests without GetBody are cloned with a buffered body.
+func peekRequestBody(req *http.Request) ([]byte, *http.Request) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, req
+	}
+	if req.GetBody != nil {
+		rc, err := req.GetBody()
+		if err != nil {
+			return nil, req
+		}
+		defer rc.Close()
+		b, _ := io.ReadAll(io.LimitReader(rc, auditRequestLimit+1))
+		return b, req
+	}
+	b, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	clone := req.Clone(req.Context())
+	clone.Body = io.NopCloser(bytes.NewReader(b))
+	clone.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(b)), nil }
+	if err != nil {
+		return nil, clone
+	}
+	if len(b) > auditRequestLimit+1 {
+		b = b[:auditRequestLimit+1]
+	}
+	return b, clone
+}
+
+// auditBody captures the head and tail of a response body and records the call when the body
+// ends.
+type auditBody struct {
+	io.ReadCloser
+	call  *Call
+	start time.Time
+	head  []byte
+	tail  []byte
+	total int
+	err   error
+	once  sync.Once
+}
+
+func (b *auditBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 {
+		b.capture(p[:n])
+	}
+	if err != nil {
+		if err != io.EOF {
+			b.err = err
+		}
+		b.finish()
+	}
+	return n, err
+}
+
+func (b *auditBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.finish()
+	return err
+}
+
+func (b *auditBody) capture(p []byte) {
+	b.total += len(p)
+	if room := auditBodyLimit - len(b.head); room > 0 {
+		b.head = append(b.head, p[:min(room, len(p))]...)
+	}
+	b.tail = append(b.tail, p...)
+	if len(b.tail) > auditTailLimit {
+		b.tail = b.tail[len(b.tail)-auditTailLimit:]
+	}
+}
+
+func (b *auditBody) finish() {
+	b.once.Do(func() {
+		c := b.call
+		c.LatencyMs = time.Since(b.start).Milliseconds()
+		text := b.head
+		if b.total > len(b.head) {
+			text = append(text[:len(text):len(text)], truncatedMarker...)
+			text = append(text, b.tail...)
+		}
+		c.Response = redactBody(text)
+		c.InputTokens, c.OutputTokens = parseUsage(b.head, b.tail)
+		if b.err != nil {
+			c.Error = logger.RedactString(b.err.Error())
+		} else if c.Status >= http.StatusBadRequest {
+			c.Error = http.StatusText(c.Status)
+		}
+		recordCall(c)
+	})
+}
+
+var (
+	// bodyModelPattern matches the "model" field of a JSON request; the body may be truncated,
+	// so it is not decoded.
+	bodyModelPattern = regexp.MustCompile(`"model"\s*:\s*"([^"]+)"`)
+	// pathModelPattern matches models addressed in the URL, e.g. Gemini's /models/gemini-pro:generateContent.
+	pathModelPattern = regexp.MustCompile(`/models/([^/:]+)`)
+	// secretFieldPattern matches JSON string fields that carry credentials.
+	secretFieldPattern = regexp.MustCompile(`(?i)("(?:api[_-]?key|key|token|access_token|secret|client_secret|password|authorization)"\s*:\s*")([^"]*)(")`)
+
+	inputTokenPattern  = regexp.MustCompile(`"(?:prompt_tokens|input_tokens|promptTokenCount|prompt_eval_count)"\s*:\s*(\d+)`)
+	outputTokenPattern = regexp.MustCompile(`"(?:completion_tokens|output_tokens|candidatesTokenCount|eval_count)"\s*:\s*(\d+)`)
+)
+
+func requestModel(body []byte, path string) string {
+	if m := bodyModelPattern.FindSubmatch(body); m != nil {
+		return string(m[1])
+	}
+	if m := pathModelPattern.FindStringSubmatch(path); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// redactBody masks credentials in a captured body.
+func redactBody(b []byte) string {
+	s := secretFieldPattern.ReplaceAllStringFunc(string(b), func(m string) string {
+		sub := secretFieldPattern.FindStringSubmatch(m)
+		return sub[1] + logger.MaskSecret(sub[2]) + sub[3]
+	})
+	return logger.RedactString(s)
+}
+
+// parseUsage returns the token counts reported in the response, preferring the last value seen
+// (streaming APIs report cumulative usage at the end).
+func parseUsage(head, tail []byte) (input, output int) {
+	find := func(re *regexp.Regexp) int {
+		for _, b := range [][]byte{tail, head} {
+			if all := re.FindAllSubmatch(b, -1); len(all) > 0 {
+				n, _ := strconv.Atoi(string(all[len(all)-1][1]))
+				return n
+			}
+		}
+		return 0
+	}
+	return find(inputTokenPattern), find(outputTokenPattern)
+}
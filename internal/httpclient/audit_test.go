@@ -0,0 +1,66 @@
+package httpclient
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAuditRecordsRedactedCall(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = io.WriteString(w, `{"error":"bad key sk-abcdefghijklmnop","usage":{"prompt_tokens":12,"completion_tokens":3}}`)
+	}))
+	defer srv.Close()
+
+	SetAuditEnabled(true)
+	defer SetAuditEnabled(false)
+	ClearCalls()
+
+	req, _ := http.NewRequest(http.MethodPost, srv.URL+"/v1/chat/completions?key=secret123456",
+		strings.NewReader(`{"model":"gpt-4o","api_key":"topsecretvalue","messages":[]}`))
+	req.Header.Set("Authorization", "Bearer sk-abcdefghijklmnop")
+	resp, err := Shared().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	calls := RecentCalls()
+	if len(calls) != 1 {
+		t.Fatalf("got %d calls, want 1", len(calls))
+	}
+	c := calls[0]
+	if c.Model != "gpt-4o" || c.Status != http.StatusBadRequest || c.InputTokens != 12 || c.OutputTokens != 3 {
+		t.Fatalf("unexpected call: %+v", c)
+	}
+	for _, secret := range []string{"secret123456", "topsecretvalue", "sk-abcdefghijklmnop"} {
+		if strings.Contains(c.Endpoint+c.Request+c.Response, secret) {
+			t.Fatalf("secret %q not redacted: %+v", secret, c)
+		}
+	}
+}
+
+func TestAuditBufferIsCapped(t *testing.T) {
+	ClearCalls()
+	for range AuditBufferSize + 10 {
+		recordCall(&Call{})
+	}
+	calls := RecentCalls()
+	if len(calls) != AuditBufferSize {
+		t.Fatalf("got %d calls, want %d", len(calls), AuditBufferSize)
+	}
+	if calls[0].ID <= calls[1].ID {
+		t.Fatalf("calls not newest first: %d, %d", calls[0].ID, calls[1].ID)
+	}
+	if _, ok := FindCall(calls[0].ID); !ok {
+		t.Fatalf("newest call %d not found", calls[0].ID)
+	}
+	if _, ok := FindCall(calls[len(calls)-1].ID - 1); ok {
+		t.Fatal("evicted call still found")
+	}
+}
@@ -0,0 +1,132 @@
+// Package httpclient provides the process-wide HTTP connection pool used for model provider
+// traffic (chat, embedding, provider checks and catalog fetches), so keep-alive connections to
+// the same endpoint are reused instead of being re-dialed by a fresh client per request.
+package httpclient
+
+import (
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// DefaultMaxIdleConns is the total number of idle connections kept when http_max_idle_conns is unset.
+	DefaultMaxIdleConns = 100
+	// DefaultMaxIdleConnsPerHost is the per-host idle pool size when http_max_idle_conns_per_host is unset.
+	// It is well above net/http's default of 2 so concurrent embedding batches to one endpoint
+	// (e.g. a local Ollama) do not close and re-dial connections after every request.
+	DefaultMaxIdleConnsPerHost = 16
+	// DefaultIdleConnTimeout is how long an idle connection stays pooled when http_idle_conn_timeout is unset.
+	DefaultIdleConnTimeout = 90 * time.Second
+
+	keepAlive   = 30 * time.Second
+	dialTimeout = 30 * time.Second
+)
+
+// PoolConfig holds the tunable connection pool settings.
+type PoolConfig struct {
+	MaxIdleConns        int
+	MaxIdleConnsPerHost int
+	IdleConnTimeout     time.Duration
+}
+
+// DefaultPoolConfig returns the pool settings used until SetPoolConfig is called.
+func DefaultPoolConfig() PoolConfig {
+	return PoolConfig{
+		MaxIdleConns:        DefaultMaxIdleConns,
+		MaxIdleConnsPerHost: DefaultMaxIdleConnsPerHost,
+		IdleConnTimeout:     DefaultIdleConnTimeout,
+	}
+}
+
+// swappableTransport lets SetPoolConfig replace the underlying transport without touching
+// clients that were already handed out.
+type swappableTransport struct {
+	current atomic.Pointer[http.Transport]
+}
+
+func (t *swappableTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if auditEnabled.Load() {
+		return auditRoundTrip(t.current.Load(), req)
+	}
+	return t.current.Load().RoundTrip(req)
+}
+
+// CloseIdleConnections is picked up by http.Client.CloseIdleConnections.
+func (t *swappableTransport) CloseIdleConnections() {
+	t.current.Load().CloseIdleConnections()
+}
+
+var (
+	mu        sync.Mutex
+	transport = newSwappableTransport(DefaultPoolConfig())
+	shared    = &http.Client{Transport: transport}
+)
+
+func newSwappableTransport(cfg PoolConfig) *swappableTransport {
+	t := &swappableTransport{}
+	t.current.Store(newTransport(cfg))
+	return t
+}
+
+// newTransport builds a transport like http.DefaultTransport (so HTTP_PROXY / HTTPS_PROXY are
+// still honoured) with the given pool settings.
+func newTransport(cfg PoolConfig) *http.Transport {
+	cfg = normalize(cfg)
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   dialTimeout,
+			KeepAlive: keepAlive,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          cfg.MaxIdleConns,
+		MaxIdleConnsPerHost:   cfg.MaxIdleConnsPerHost,
+		IdleConnTimeout:       cfg.IdleConnTimeout,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+}
+
+func normalize(cfg PoolConfig) PoolConfig {
+	def := DefaultPoolConfig()
+	if cfg.MaxIdleConns <= 0 {
+		cfg.MaxIdleConns = def.MaxIdleConns
+	}
+	if cfg.MaxIdleConnsPerHost <= 0 {
+		cfg.MaxIdleConnsPerHost = def.MaxIdleConnsPerHost
+	}
+	if cfg.MaxIdleConnsPerHost > cfg.MaxIdleConns {
+		cfg.MaxIdleConnsPerHost = cfg.MaxIdleConns
+	}
+	if cfg.IdleConnTimeout <= 0 {
+		cfg.IdleConnTimeout = def.IdleConnTimeout
+	}
+	return cfg
+}
+
+// SetPoolConfig applies new pool settings. Existing clients switch to the new pool on their next
+// request; idle connections of the old pool are closed. Non-positive values fall back to defaults.
+func SetPoolConfig(cfg PoolConfig) {
+	mu.Lock()
+	defer mu.Unlock()
+	old := transport.current.Swap(newTransport(cfg))
+	old.CloseIdleConnections()
+}
+
+// Shared returns the pooled client without a client-level timeout; callers bound requests with
+// a context deadline.
+func Shared() *http.Client {
+	return shared
+}
+
+// New returns a client on the shared pool with the given overall timeout (0 = no timeout).
+// Clients are cheap: connections live in the shared transport, not in the client.
+func New(timeout time.Duration) *http.Client {
+	if timeout <= 0 {
+		return shared
+	}
+	return &http.Client{Transport: transport, Timeout: timeout}
+}
@@ -0,0 +1,108 @@
+package httpclient
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// newCountingServer returns a fake embedding endpoint and a counter of accepted TCP connections.
+func newCountingServer(tb testing.TB) (*httptest.Server, *atomic.Int64) {
+	tb.Helper()
+	var conns atomic.Int64
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"data":[{"index":0,"embedding":[0.1,0.2,0.3]}]}`)
+	}))
+	srv.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	srv.Start()
+	tb.Cleanup(srv.Close)
+	return srv, &conns
+}
+
+// embedBatches simulates bulk document embedding: workers concurrently post batches.
+func embedBatches(tb testing.TB, client func() *http.Client, url string, batches, workers int) {
+	tb.Helper()
+	var wg sync.WaitGroup
+	jobs := make(chan struct{})
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range jobs {
+				resp, err := client().Post(url, "application/json", strings.NewReader(`{"input":["chunk"]}`))
+				if err != nil {
+					tb.Error(err)
+					return
+				}
+				_, _ = io.Copy(io.Discard, resp.Body)
+				resp.Body.Close()
+			}
+		}()
+	}
+	for range batches {
+		jobs <- struct{}{}
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+func TestSharedClientReusesConnections(t *testing.T) {
+	srv, conns := newCountingServer(t)
+	const workers = 8
+	embedBatches(t, func() *http.Client { return New(0) }, srv.URL, 200, workers)
+	if got := conns.Load(); got > workers {
+		t.Fatalf("expected at most %d connections for %d workers, got %d", workers, workers, got)
+	}
+}
+
+func TestSetPoolConfigKeepsHandedOutClients(t *testing.T) {
+	srv, _ := newCountingServer(t)
+	client := New(0)
+	SetPoolConfig(PoolConfig{MaxIdleConns: 4, MaxIdleConnsPerHost: 100})
+	defer SetPoolConfig(DefaultPoolConfig())
+
+	if got := transport.current.Load().MaxIdleConnsPerHost; got != 4 {
+		t.Fatalf("MaxIdleConnsPerHost should be capped by MaxIdleConns, got %d", got)
+	}
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+}
+
+// BenchmarkBulkEmbeddingConnections compares connection churn of a fresh client per request
+// (the previous behaviour) with the shared pool. Run with:
+//
+//	go test ./internal/httpclient -bench BulkEmbedding -benchtime 500x
+func BenchmarkBulkEmbeddingConnections(b *testing.B) {
+	const workers = 8
+	cases := []struct {
+		name   string
+		client func() *http.Client
+	}{
+		{"fresh-client", func() *http.Client { return &http.Client{Transport: &http.Transport{}} }},
+		{"shared-pool", func() *http.Client { return New(0) }},
+	}
+	for _, tc := range cases {
+		b.Run(tc.name, func(b *testing.B) {
+			srv, conns := newCountingServer(b)
+			b.ResetTimer()
+			embedBatches(b, tc.client, srv.URL, b.N, workers)
+			b.StopTimer()
+			b.ReportMetric(float64(conns.Load()), "conns")
+			b.ReportMetric(float64(conns.Load())/float64(b.N), "conns/op")
+		})
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"chatclaw/internal/define"
@@ -153,14 +154,41 @@ func (w *rotatingWriter) Close() error {
 var (
 	globalWriter *rotatingWriter
 	globalMu     sync.Mutex
+
+	// level is shared by every logger returned from New so it can be changed at runtime.
+	level slog.LevelVar
+	// fileEnabled controls whether log records are also written to the log file.
+	fileEnabled atomic.Bool
 )
 
+func init() {
+	level.Set(slog.LevelInfo)
+	fileEnabled.Store(true)
+}
+
+// fileSink forwards writes to the global rotating writer while file logging is enabled.
+type fileSink struct{}
+
+func (fileSink) Write(p []byte) (int, error) {
+	if !fileEnabled.Load() {
+		return len(p), nil
+	}
+	globalMu.Lock()
+	w := globalWriter
+	globalMu.Unlock()
+	if w == nil {
+		return len(p), nil
+	}
+	return w.Write(p)
+}
+
 // New creates a *slog.Logger that writes to a log file under the app config directory.
 //
 // Behavior by environment:
 //   - Development: writes to both stderr (colored) and file.
 //   - Production:  writes to file only (Wails default discards all logs in production).
 //
+// The level and the file sink can be changed later with SetLevel and SetFileEnabled.
 // The returned cleanup function must be called on application shutdown to flush
 // and close the log file.
 func New() (logger *slog.Logger, cleanup func(), err error) {
@@ -181,21 +209,13 @@ func New() (logger *slog.Logger, cleanup func(), err error) {
 	var writer io.Writer
 	if define.IsDev() {
 		// Development: dual-write to stderr + file.
-		writer = io.MultiWriter(os.Stderr, w)
+		writer = io.MultiWriter(os.Stderr, fileSink{})
 	} else {
 		// Production: file only (console is unavailable in packaged apps).
-		writer = w
-	}
-
-	level := slog.LevelInfo
-	if define.IsDev() {
-		level = slog.LevelInfo
+		writer = fileSink{}
 	}
 
-	handler := slog.NewTextHandler(writer, &slog.HandlerOptions{
-		Level: level,
-	})
-	logger = slog.New(handler)
+	logger = slog.New(newHandler(writer))
 
 	cleanup = func() {
 		globalMu.Lock()
@@ -209,6 +229,59 @@ func New() (logger *slog.Logger, cleanup func(), err error) {
 	return logger, cleanup, nil
 }
 
+// newHandler builds the text handler shared by all loggers: runtime-adjustable level and
+// redaction of secrets and user content (see redactAttr).
+func newHandler(w io.Writer) slog.Handler {
+	return slog.NewTextHandler(w, &slog.HandlerOptions{
+		Level:       &level,
+		ReplaceAttr: redactAttr,
+	})
+}
+
+// ParseLevel converts "error" / "warn" / "info" / "debug" (case-insensitive) to a slog level.
+func ParseLevel(s string) (slog.Level, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "error":
+		return slog.LevelError, true
+	case "warn", "warning":
+		return slog.LevelWarn, true
+	case "info":
+		return slog.LevelInfo, true
+	case "debug":
+		return slog.LevelDebug, true
+	}
+	return slog.LevelInfo, false
+}
+
+// SetLevel changes the minimum level of all loggers created by New.
+// Unknown values are ignored and reported as false.
+func SetLevel(s string) bool {
+	l, ok := ParseLevel(s)
+	if ok {
+		level.Set(l)
+	}
+	return ok
+}
+
+// SetFileEnabled turns writing to the log file on or off. Development builds keep logging to stderr.
+func SetFileEnabled(enabled bool) {
+	fileEnabled.Store(enabled)
+}
+
+// Dir returns the directory that holds the current and rotated log files.
+func Dir() (string, error) {
+	return resolveLogDir()
+}
+
+// FilePath returns the path of the current log file.
+func FilePath() (string, error) {
+	dir, err := resolveLogDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, logFileName), nil
+}
+
 // resolveLogDir returns the directory for log files: $HOME/.chatclaw/native/logs
 func resolveLogDir() (string, error) {
 	dir, err := define.AppDataDir()
@@ -0,0 +1,107 @@
+package logger
+
+import (
+	"log/slog"
+	"regexp"
+	"strings"
+	"sync/atomic"
+)
+
+// includeContent controls whether user/model content (message text, prompts,
+// response previews) is written to the logs. Off by default, including in dev builds.
+var includeContent atomic.Bool
+
+// SetIncludeContent turns logging of user and model content on or off.
+func SetIncludeContent(enabled bool) {
+	includeContent.Store(enabled)
+}
+
+// secretKeySuffixes match attribute keys (lowercased, "_" and "-" removed) whose values are
+// always masked, e.g. api_key, openai-api-key, client_secret, access_token.
+var secretKeySuffixes = []string{"apikey", "secret", "password", "accesstoken", "refreshtoken"}
+
+// secretKeys are matched exactly after the same normalization.
+var secretKeys = map[string]bool{
+	"token":         true,
+	"authorization": true,
+	"bearer":        true,
+}
+
+// contentKeys hold user or model content; they are dropped unless SetIncludeContent(true).
+var contentKeys = map[string]bool{
+	"content":          true,
+	"prompt":           true,
+	"query":            true,
+	"text":             true,
+	"body":             true,
+	"preview":          true,
+	"response_preview": true,
+	"messages":         true,
+	"user_content":     true,
+	"thinking":         true,
+}
+
+var (
+	// bearerPattern matches "Bearer <token>" in free-form strings such as error messages.
+	bearerPattern = regexp.MustCompile(`(?i)(bearer\s+)([A-Za-z0-9._~+/=\-]+)`)
+	// queryKeyPattern matches credentials passed as URL query parameters.
+	queryKeyPattern = regexp.MustCompile(`(?i)([?&](?:api[_-]?key|key|token|access_token)=)([^&\s"']+)`)
+	// skKeyPattern matches OpenAI-style secret keys (sk-..., sk-proj-...).
+	skKeyPattern = regexp.MustCompile(`\bsk-[A-Za-z0-9_\-]{8,}`)
+)
+
+// MaskSecret hides all but the last 4 characters of a secret.
+func MaskSecret(s string) string {
+	if len(s) <= 4 {
+		return "****"
+	}
+	return "****" + s[len(s)-4:]
+}
+
+// RedactString masks credentials embedded in a free-form string.
+func RedactString(s string) string {
+	s = bearerPattern.ReplaceAllStringFunc(s, func(m string) string {
+		sub := bearerPattern.FindStringSubmatch(m)
+		return sub[1] + MaskSecret(sub[2])
+	})
+	s = queryKeyPattern.ReplaceAllStringFunc(s, func(m string) string {
+		sub := queryKeyPattern.FindStringSubmatch(m)
+		return sub[1] + MaskSecret(sub[2])
+	})
+	return skKeyPattern.ReplaceAllStringFunc(s, MaskSecret)
+}
+
+// redactAttr is used as slog.HandlerOptions.ReplaceAttr for every logger created by this package.
+func redactAttr(_ []string, a slog.Attr) slog.Attr {
+	if a.Value.Kind() == slog.KindGroup {
+		return a
+	}
+
+	key := strings.ToLower(a.Key)
+	normalized := strings.NewReplacer("_", "", "-", "").Replace(key)
+	if secretKeys[normalized] || hasAnySuffix(normalized, secretKeySuffixes) {
+		return slog.String(a.Key, MaskSecret(a.Value.Resolve().String()))
+	}
+	if contentKeys[key] && !includeContent.Load() {
+		return slog.String(a.Key, "[redacted]")
+	}
+
+	switch v := a.Value.Resolve(); v.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, RedactString(v.String()))
+	case slog.KindAny:
+		if err, ok := v.Any().(error); ok && err != nil {
+			return slog.String(a.Key, RedactString(err.Error()))
+		}
+	}
+	return a
+}
+
+func hasAnySuffix(s string, suffixes []string) bool {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(s, suffix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,67 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+const testAPIKey = "sk-proj-abcdef0123456789WXYZ"
+
+func TestRedact_noFullKeyInOutput(t *testing.T) {
+	var buf bytes.Buffer
+	log := slog.New(newHandler(&buf))
+
+	log.Info("provider request",
+		"api_key", testAPIKey,
+		"openai-api-key", testAPIKey,
+		"authorization", "Bearer "+testAPIKey,
+		"url", "https://example.com/v1/models?api_key="+testAPIKey,
+		"error", errors.New("401 Unauthorized: invalid key "+testAPIKey),
+	)
+	log.With("client_secret", testAPIKey).Warn("with attrs")
+	log.Info("grouped", slog.Group("provider", "api_key", testAPIKey))
+
+	out := buf.String()
+	if strings.Contains(out, testAPIKey) {
+		t.Fatalf("full key leaked into log output:\n%s", out)
+	}
+	if !strings.Contains(out, "****WXYZ") {
+		t.Fatalf("expected masked key with last 4 chars, got:\n%s", out)
+	}
+}
+
+func TestRedact_contentGatedByOptIn(t *testing.T) {
+	t.Cleanup(func() { SetIncludeContent(false) })
+
+	var buf bytes.Buffer
+	log := slog.New(newHandler(&buf))
+
+	log.Info("message", "content", "my private question", "response_preview", "model answer")
+	if out := buf.String(); strings.Contains(out, "my private question") || strings.Contains(out, "model answer") {
+		t.Fatalf("content logged without opt-in:\n%s", out)
+	}
+
+	buf.Reset()
+	SetIncludeContent(true)
+	log.Info("message", "content", "my private question")
+	if out := buf.String(); !strings.Contains(out, "my private question") {
+		t.Fatalf("content missing after opt-in:\n%s", out)
+	}
+}
+
+func TestMaskSecret(t *testing.T) {
+	cases := map[string]string{
+		"":         "****",
+		"abcd":     "****",
+		"abcdefgh": "****efgh",
+		testAPIKey: "****WXYZ",
+	}
+	for in, want := range cases {
+		if got := MaskSecret(in); got != want {
+			t.Errorf("MaskSecret(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
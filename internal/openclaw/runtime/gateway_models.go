@@ -43,12 +43,13 @@ type openclawModelEntry struct {
 }
 
 var chatclawTypeToOpenClawAPI = map[string]string{
-	"openai":    "openai-completions",
-	"azure":     "openai-completions",
-	"anthropic": "anthropic-messages",
-	"gemini":    "google-generativeai",
-	"ollama":    "openai-completions",
-	"qwen":      "openai-completions",
+	"openai":           "openai-completions",
+	"openai-responses": "openai-responses",
+	"azure":            "openai-completions",
+	"anthropic":        "anthropic-messages",
+	"gemini":           "google-generativeai",
+	"ollama":           "openai-completions",
+	"qwen":             "openai-completions",
 }
 
 // chatWikiSyncMu protects the ChatWiki model catalog cache during sync.
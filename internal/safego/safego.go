@@ -0,0 +1,43 @@
+// Package safego 为后台常驻功能（悬浮球、划词弹窗、吸附循环等）的回调提供 panic 隔离：
+// 回调里的 panic 被记录日志后吞掉，不会导致整个应用退出。
+package safego
+
+import (
+	"log/slog"
+	"runtime/debug"
+)
+
+// Recover 必须直接以 defer 调用：defer safego.Recover("where", onPanic)。
+// 捕获到 panic 时记录位置、panic 值和调用栈，然后调用 onPanic（可为 nil）用于重置相关状态。
+func Recover(where string, onPanic func(v any)) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	slog.Error("recovered panic", "where", where, "panic", r, "stack", string(debug.Stack()))
+	if onPanic != nil {
+		// 重置逻辑本身出错时不再向上传播
+		defer func() {
+			if r2 := recover(); r2 != nil {
+				slog.Error("panic in panic handler", "where", where, "panic", r2)
+			}
+		}()
+		onPanic(r)
+	}
+}
+
+// Func 返回包装后的 f：执行时发生的 panic 会被 Recover 处理。适用于 time.AfterFunc 等入口。
+func Func(where string, f func(), onPanic func(v any)) func() {
+	return func() {
+		defer Recover(where, onPanic)
+		f()
+	}
+}
+
+// Go 在新 goroutine 中执行 f，并捕获其中的 panic。
+func Go(where string, f func(), onPanic func(v any)) {
+	go func() {
+		defer Recover(where, onPanic)
+		f()
+	}()
+}
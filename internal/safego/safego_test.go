@@ -0,0 +1,62 @@
+package safego
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFuncRecoversPanic(t *testing.T) {
+	var got any
+	f := Func("test", func() { panic("boom") }, func(v any) { got = v })
+
+	f() // must not propagate
+
+	if got != "boom" {
+		t.Fatalf("onPanic got %v, want boom", got)
+	}
+}
+
+func TestFuncWithoutPanic(t *testing.T) {
+	ran, handled := false, false
+	Func("test", func() { ran = true }, func(any) { handled = true })()
+
+	if !ran || handled {
+		t.Fatalf("ran=%v handled=%v, want ran=true handled=false", ran, handled)
+	}
+}
+
+func TestPanickingOnPanicDoesNotPropagate(t *testing.T) {
+	Func("test", func() { panic("boom") }, func(any) { panic("again") })()
+}
+
+func TestGoRecoversPanic(t *testing.T) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+	Go("test", func() { panic("boom") }, func(any) { wg.Done() })
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("onPanic was not called")
+	}
+}
+
+func TestTimerCallbackRecovers(t *testing.T) {
+	handled := make(chan any, 1)
+	time.AfterFunc(time.Millisecond, Func("timer", func() { panic(42) }, func(v any) { handled <- v }))
+
+	select {
+	case v := <-handled:
+		if v != 42 {
+			t.Fatalf("onPanic got %v, want 42", v)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("onPanic was not called")
+	}
+}
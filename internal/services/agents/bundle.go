@@ -0,0 +1,177 @@
+package agents
+
+import (
+	"context"
+	"strings"
+
+	"chatclaw/internal/define"
+	"chatclaw/internal/errs"
+	"chatclaw/internal/services/providers"
+
+	"github.com/uptrace/bun"
+)
+
+// bundleSettingColumns are the portable agent settings (everything but the name, OpenClaw ID,
+// work dir and MCP servers, which are machine-specific).
+var bundleSettingColumns = []string{
+	"prompt", "icon", "default_llm_provider_id", "default_llm_model_id",
+	"llm_temperature", "llm_top_p", "llm_max_context_count", "llm_max_tokens",
+	"enable_llm_temperature", "enable_llm_top_p", "enable_llm_max_tokens",
+	"retrieval_match_threshold", "retrieval_top_k",
+	"sandbox_mode", "sandbox_network", "enabled_tools", "max_tool_iterations",
+	"thinking_budget", "response_language", "on_tool_error",
+}
+
+// configBundleAgents implements providers.ConfigAgents: the agent part of the providers config
+// bundle lives here because providers cannot import agents.
+type configBundleAgents struct{}
+
+// NewConfigBundleAgents returns the handler registered with providers.RegisterConfigAgents.
+func NewConfigBundleAgents() providers.ConfigAgents {
+	return configBundleAgents{}
+}
+
+func (m *agentModel) toBundle() providers.ConfigBundleAgent {
+	return providers.ConfigBundleAgent{
+		Name:                    m.Name,
+		IsDefault:               m.OpenClawAgentID == define.OpenClawMainAgentID,
+		Prompt:                  m.Prompt,
+		Icon:                    m.Icon,
+		DefaultLLMProviderID:    m.DefaultLLMProviderID,
+		DefaultLLMModelID:       m.DefaultLLMModelID,
+		LLMTemperature:          m.LLMTemperature,
+		LLMTopP:                 m.LLMTopP,
+		LLMMaxContextCount:      m.LLMMaxContextCount,
+		LLMMaxTokens:            m.LLMMaxTokens,
+		EnableLLMTemperature:    m.EnableLLMTemperature,
+		EnableLLMTopP:           m.EnableLLMTopP,
+		EnableLLMMaxTokens:      m.EnableLLMMaxTokens,
+		RetrievalMatchThreshold: m.RetrievalMatchThreshold,
+		RetrievalTopK:           m.RetrievalTopK,
+		SandboxMode:             m.SandboxMode,
+		SandboxNetwork:          m.SandboxNetwork,
+		EnabledTools:            m.EnabledTools,
+		MaxToolIterations:       m.MaxToolIterations,
+		ThinkingBudget:          m.ThinkingBudget,
+		ResponseLanguage:        m.ResponseLanguage,
+		OnToolError:             m.OnToolError,
+	}
+}
+
+// applyBundle copies the bundleSettingColumns fields of a onto m.
+func (m *agentModel) applyBundle(a providers.ConfigBundleAgent) {
+	m.Prompt = a.Prompt
+	m.Icon = a.Icon
+	m.DefaultLLMProviderID = a.DefaultLLMProviderID
+	m.DefaultLLMModelID = a.DefaultLLMModelID
+	m.LLMTemperature = a.LLMTemperature
+	m.LLMTopP = a.LLMTopP
+	m.LLMMaxContextCount = a.LLMMaxContextCount
+	m.LLMMaxTokens = a.LLMMaxTokens
+	m.EnableLLMTemperature = a.EnableLLMTemperature
+	m.EnableLLMTopP = a.EnableLLMTopP
+	m.EnableLLMMaxTokens = a.EnableLLMMaxTokens
+	m.RetrievalMatchThreshold = a.RetrievalMatchThreshold
+	m.RetrievalTopK = a.RetrievalTopK
+	m.SandboxMode = a.SandboxMode
+	m.SandboxNetwork = a.SandboxNetwork
+	m.EnabledTools = a.EnabledTools
+	m.MaxToolIterations = a.MaxToolIterations
+	m.ThinkingBudget = a.ThinkingBudget
+	m.ResponseLanguage = a.ResponseLanguage
+	m.OnToolError = a.OnToolError
+}
+
+// NormalizeAgent trims and validates a bundle agent with the rules of CreateAgent / UpdateAgent.
+func (configBundleAgents) NormalizeAgent(a *providers.ConfigBundleAgent) error {
+	a.Name = strings.TrimSpace(a.Name)
+	a.Prompt = strings.TrimSpace(a.Prompt)
+	if a.Name == "" {
+		return errs.New("error.agent_name_required")
+	}
+	if len([]rune(a.Name)) > 100 {
+		return errs.New("error.agent_name_too_long")
+	}
+	if len([]rune(a.Prompt)) > 1000 {
+		return errs.New("error.agent_prompt_too_long")
+	}
+	if len(a.Icon) > 250_000 {
+		return errs.New("error.agent_icon_too_large")
+	}
+	if a.SandboxMode != "codex" && a.SandboxMode != "native" {
+		a.SandboxMode = "codex"
+	}
+	enabledTools, err := normalizeEnabledTools(a.EnabledTools)
+	if err != nil {
+		return err
+	}
+	a.EnabledTools = enabledTools
+	if a.MaxToolIterations < 0 {
+		a.MaxToolIterations = 0
+	}
+	if a.ThinkingBudget < 0 {
+		a.ThinkingBudget = 0
+	}
+	if a.OnToolError != OnToolErrorAbort {
+		a.OnToolError = OnToolErrorContinue
+	}
+	return nil
+}
+
+// ExportAgents returns the portable settings of every agent, ordered by id.
+func (configBundleAgents) ExportAgents(ctx context.Context, db bun.IDB) ([]providers.ConfigBundleAgent, error) {
+	var models []agentModel
+	if err := db.NewSelect().Model(&models).OrderExpr("id ASC").Scan(ctx); err != nil {
+		return nil, err
+	}
+	out := make([]providers.ConfigBundleAgent, 0, len(models))
+	for i := range models {
+		out = append(out, models[i].toBundle())
+	}
+	return out, nil
+}
+
+// ImportAgents creates the agents missing locally (matched by name, the default agent by
+// IsDefault) and, with replace, overwrites the settings of matching ones.
+func (configBundleAgents) ImportAgents(ctx context.Context, db bun.IDB, items []providers.ConfigBundleAgent, replace bool) (added, updated int, err error) {
+	var locals []agentModel
+	if err := db.NewSelect().Model(&locals).Scan(ctx); err != nil {
+		return 0, 0, err
+	}
+	byName := make(map[string]*agentModel, len(locals))
+	var mainAgent *agentModel
+	for i := range locals {
+		byName[locals[i].Name] = &locals[i]
+		if locals[i].OpenClawAgentID == define.OpenClawMainAgentID {
+			mainAgent = &locals[i]
+		}
+	}
+
+	for _, item := range items {
+		local := byName[item.Name]
+		if item.IsDefault && mainAgent != nil {
+			local = mainAgent
+		}
+
+		if local == nil {
+			m := newAgentModel(item.Name, define.NewOpenClawManagedAgentID(), "", "")
+			m.applyBundle(item)
+			if _, err := db.NewInsert().Model(m).Exec(ctx); err != nil {
+				return added, updated, err
+			}
+			byName[m.Name] = m
+			added++
+			continue
+		}
+
+		if !replace {
+			continue
+		}
+		local.applyBundle(item)
+		if _, err := db.NewUpdate().Model(local).Column(bundleSettingColumns...).WherePK().Exec(ctx); err != nil {
+			return added, updated, err
+		}
+		updated++
+	}
+	return added, updated, nil
+}
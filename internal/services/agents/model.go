@@ -9,6 +9,21 @@ import (
 	"github.com/uptrace/bun"
 )
 
+// MaxToolIterationsLimit caps an agent's max_tool_iterations (0 = unlimited).
+const MaxToolIterationsLimit = 500
+
+// Thinking budget range in tokens for agents.thinking_budget; 0 keeps the provider default.
+const (
+	MinThinkingBudget = 1024
+	MaxThinkingBudget = 32768
+)
+
+// Values of agents.on_tool_error.
+const (
+	OnToolErrorContinue = "continue" // feed the tool error back to the model (default)
+	OnToolErrorAbort    = "abort"    // stop the reply on the first failing tool call
+)
+
 // Agent 助手 DTO（暴露给前端）
 type Agent struct {
 	ID int64 `json:"id"`
@@ -38,6 +53,23 @@ type Agent struct {
 	MCPServerIDs        string `json:"mcp_server_ids"`
 	MCPServerEnabledIDs string `json:"mcp_server_enabled_ids"`
 
+	// EnabledTools is a JSON array of built-in tool IDs the agent may use; "[]" enables all.
+	EnabledTools string `json:"enabled_tools"`
+
+	// MaxToolIterations caps the ReAct tool round-trips per reply; 0 = unlimited.
+	// A conversation's max_iterations overrides it when set.
+	MaxToolIterations int `json:"max_tool_iterations"`
+
+	// ThinkingBudget caps the reasoning tokens when thinking is enabled; 0 = provider default.
+	// A conversation's thinking_budget overrides it when set.
+	ThinkingBudget int `json:"thinking_budget"`
+
+	// ResponseLanguage is the language every reply must use (e.g. "zh-CN"); empty = auto.
+	ResponseLanguage string `json:"response_language"`
+
+	// OnToolError is what happens when a tool call fails: "continue" or "abort".
+	OnToolError string `json:"on_tool_error"`
+
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
@@ -78,6 +110,17 @@ type UpdateAgentInput struct {
 	MCPEnabled          *bool   `json:"mcp_enabled"`
 	MCPServerIDs        *string `json:"mcp_server_ids"`
 	MCPServerEnabledIDs *string `json:"mcp_server_enabled_ids"`
+
+	EnabledTools *string `json:"enabled_tools"`
+
+	MaxToolIterations *int `json:"max_tool_iterations"`
+
+	ThinkingBudget *int `json:"thinking_budget"`
+
+	// ResponseLanguage: "" or "auto" clears it.
+	ResponseLanguage *string `json:"response_language"`
+
+	OnToolError *string `json:"on_tool_error"`
 }
 
 type agentModel struct {
@@ -111,6 +154,16 @@ type agentModel struct {
 	MCPEnabled          bool   `bun:"mcp_enabled,notnull"`
 	MCPServerIDs        string `bun:"mcp_server_ids,notnull"`
 	MCPServerEnabledIDs string `bun:"mcp_server_enabled_ids,notnull"`
+
+	EnabledTools string `bun:"enabled_tools,notnull"`
+
+	MaxToolIterations int `bun:"max_tool_iterations,notnull"`
+
+	ThinkingBudget int `bun:"thinking_budget,notnull"`
+
+	ResponseLanguage string `bun:"response_language,notnull"`
+
+	OnToolError string `bun:"on_tool_error,notnull"`
 }
 
 // BeforeInsert 在 INSERT 时自动设置 created_at 和 updated_at（字符串格式）
@@ -160,6 +213,16 @@ func (m *agentModel) toDTO() Agent {
 		MCPServerIDs:        m.MCPServerIDs,
 		MCPServerEnabledIDs: m.MCPServerEnabledIDs,
 
+		EnabledTools: m.EnabledTools,
+
+		MaxToolIterations: m.MaxToolIterations,
+
+		ThinkingBudget: m.ThinkingBudget,
+
+		ResponseLanguage: m.ResponseLanguage,
+
+		OnToolError: m.OnToolError,
+
 		CreatedAt: m.CreatedAt,
 		UpdatedAt: m.UpdatedAt,
 	}
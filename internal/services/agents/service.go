@@ -6,6 +6,7 @@ import (
 	"database/sql"
 	"encoding/base64"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"net/http"
 	"os"
@@ -15,6 +16,7 @@ import (
 	"time"
 
 	"chatclaw/internal/define"
+	"chatclaw/internal/eino/tools"
 	"chatclaw/internal/errs"
 	"chatclaw/internal/services/i18n"
 	"chatclaw/internal/sqlite"
@@ -87,6 +89,10 @@ func newAgentModel(name, openclawAgentID, prompt, icon string) *agentModel {
 
 		MCPServerIDs:        "[]",
 		MCPServerEnabledIDs: "[]",
+
+		EnabledTools: "[]",
+
+		OnToolError: OnToolErrorContinue,
 	}
 }
 
@@ -442,6 +448,35 @@ func (s *AgentsService) UpdateAgent(id int64, input UpdateAgentInput) (*Agent, e
 	if input.MCPServerEnabledIDs != nil {
 		q = q.Set("mcp_server_enabled_ids = ?", *input.MCPServerEnabledIDs)
 	}
+	if input.EnabledTools != nil {
+		enabledTools, err := normalizeEnabledTools(*input.EnabledTools)
+		if err != nil {
+			return nil, err
+		}
+		q = q.Set("enabled_tools = ?", enabledTools)
+	}
+	if input.MaxToolIterations != nil {
+		if *input.MaxToolIterations < 0 || *input.MaxToolIterations > MaxToolIterationsLimit {
+			return nil, errs.Newf("error.agent_max_tool_iterations_invalid", map[string]any{"Max": MaxToolIterationsLimit})
+		}
+		q = q.Set("max_tool_iterations = ?", *input.MaxToolIterations)
+	}
+	if input.ThinkingBudget != nil {
+		if n := *input.ThinkingBudget; n != 0 && (n < MinThinkingBudget || n > MaxThinkingBudget) {
+			return nil, errs.Newf("error.agent_thinking_budget_invalid", map[string]any{"Min": MinThinkingBudget, "Max": MaxThinkingBudget})
+		}
+		q = q.Set("thinking_budget = ?", *input.ThinkingBudget)
+	}
+	if input.ResponseLanguage != nil {
+		q = q.Set("response_language = ?", normalizeResponseLanguage(*input.ResponseLanguage))
+	}
+	if input.OnToolError != nil {
+		policy := strings.TrimSpace(*input.OnToolError)
+		if policy != OnToolErrorContinue && policy != OnToolErrorAbort {
+			return nil, errs.New("error.agent_on_tool_error_invalid")
+		}
+		q = q.Set("on_tool_error = ?", policy)
+	}
 
 	result, err := q.Exec(ctx)
 	if err != nil {
@@ -624,3 +659,42 @@ func ensureLLMModelExists(ctx context.Context, db *bun.DB, app *application.App,
 	}
 	return nil
 }
+
+// normalizeEnabledTools validates an agent tool filter (JSON array of tool IDs, "!id" denials or
+// "none"; see tools.ToolAllowed) and returns it re-encoded without duplicates. An empty string or
+// empty array means all tools are enabled.
+func normalizeEnabledTools(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "[]", nil
+	}
+	var ids []string
+	if err := json.Unmarshal([]byte(raw), &ids); err != nil {
+		return "", errs.Wrap("error.agent_enabled_tools_invalid_json", err)
+	}
+	seen := make(map[string]bool, len(ids))
+	normalized := make([]string, 0, len(ids))
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if seen[id] {
+			continue
+		}
+		if !tools.IsValidToolFilterEntry(id) {
+			return "", errs.Newf("error.agent_enabled_tool_unknown", map[string]any{"Tool": id})
+		}
+		seen[id] = true
+		normalized = append(normalized, id)
+	}
+	data, _ := json.Marshal(normalized)
+	return string(data), nil
+}
+
+// normalizeResponseLanguage trims a response language; "auto" (any case) is stored as empty,
+// which leaves the reply language to the model.
+func normalizeResponseLanguage(raw string) string {
+	raw = strings.TrimSpace(raw)
+	if strings.EqualFold(raw, "auto") {
+		return ""
+	}
+	return raw
+}
@@ -0,0 +1,376 @@
+package chat
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"chatclaw/internal/errs"
+	"chatclaw/internal/sqlite"
+
+	"github.com/uptrace/bun"
+)
+
+// Archive reasons stored in archived_messages.reason
+const (
+	ArchiveReasonEdit         = "edit"         // replaced by EditAndResend
+	ArchiveReasonEditSource   = "edit_source"  // the edited user message as it was before the edit
+	ArchiveReasonConversation = "conversation" // the whole conversation was archived
+)
+
+// ArchivedMessage is a message moved out of its conversation, kept for recovery.
+type ArchivedMessage struct {
+	Message
+	Reason      string    `json:"reason"`
+	OperationID string    `json:"operation_id,omitempty"` // edit operation (EditAndResend) that archived it
+	ArchivedAt  time.Time `json:"archived_at"`
+}
+
+// archivedMessageModel holds a full messages row as JSON so columns added by later
+// migrations survive an archive/restore round trip without touching this table.
+type archivedMessageModel struct {
+	bun.BaseModel `bun:"table:archived_messages,alias:am"`
+
+	ID             int64     `bun:"id,pk,autoincrement"`
+	ArchivedAt     time.Time `bun:"archived_at,notnull"`
+	ConversationID int64     `bun:"conversation_id,notnull"`
+	MessageID      int64     `bun:"message_id,notnull"`
+	Reason         string    `bun:"reason,notnull"`
+	Data           string    `bun:"data,notnull"`
+	OperationID    string    `bun:"operation_id,notnull"`
+}
+
+var _ bun.BeforeInsertHook = (*archivedMessageModel)(nil)
+
+// BeforeInsert stamps archived_at in the same text format as the other timestamp columns.
+func (*archivedMessageModel) BeforeInsert(ctx context.Context, query *bun.InsertQuery) error {
+	query.Value("archived_at", "?", sqlite.NowUTC())
+	return nil
+}
+
+// archiveMessagesAfter moves the messages of a conversation with id > afterID into archived_messages.
+func archiveMessagesAfter(ctx context.Context, db bun.IDB, conversationID, afterID int64, reason, operationID string) error {
+	var models []messageModel
+	if err := db.NewSelect().
+		Model(&models).
+		Where("conversation_id = ?", conversationID).
+		Where("id > ?", afterID).
+		OrderExpr("id ASC").
+		Scan(ctx); err != nil {
+		return err
+	}
+	if len(models) == 0 {
+		return nil
+	}
+
+	archived := make([]archivedMessageModel, 0, len(models))
+	ids := make([]int64, 0, len(models))
+	for i := range models {
+		am, err := newArchivedMessage(&models[i], reason, operationID)
+		if err != nil {
+			return err
+		}
+		archived = append(archived, am)
+		ids = append(ids, models[i].ID)
+	}
+
+	if _, err := db.NewInsert().Model(&archived).Exec(ctx); err != nil {
+		return err
+	}
+	_, err := db.NewDelete().
+		Model((*messageModel)(nil)).
+		Where("id IN (?)", bun.In(ids)).
+		Exec(ctx)
+	return err
+}
+
+func newArchivedMessage(m *messageModel, reason, operationID string) (archivedMessageModel, error) {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return archivedMessageModel{}, err
+	}
+	return archivedMessageModel{
+		ConversationID: m.ConversationID,
+		MessageID:      m.ID,
+		Reason:         reason,
+		Data:           string(data),
+		OperationID:    operationID,
+	}, nil
+}
+
+// archiveEdit records an edit & resend as one undoable operation: a snapshot of the edited user
+// message (kept in place) plus the messages after it, which are moved out of the conversation.
+func archiveEdit(ctx context.Context, db bun.IDB, conversationID, messageID int64, operationID string) error {
+	var source messageModel
+	if err := db.NewSelect().
+		Model(&source).
+		Where("id = ?", messageID).
+		Where("conversation_id = ?", conversationID).
+		Scan(ctx); err != nil {
+		return err
+	}
+	snapshot, err := newArchivedMessage(&source, ArchiveReasonEditSource, operationID)
+	if err != nil {
+		return err
+	}
+	if _, err := db.NewInsert().Model(&snapshot).Exec(ctx); err != nil {
+		return err
+	}
+	return archiveMessagesAfter(ctx, db, conversationID, messageID, ArchiveReasonEdit, operationID)
+}
+
+// ArchiveConversation archives a conversation (sets archived_at, which hides it from the
+// conversation list) and moves all of its messages into the archive; RestoreConversation undoes it.
+// ConversationsService.ArchiveConversation sets the same flag but leaves the messages in place.
+func (s *ChatService) ArchiveConversation(conversationID int64) error {
+	if conversationID <= 0 {
+		return errs.New("error.chat_conversation_id_required")
+	}
+	if _, ok := s.activeGenerations.Load(conversationID); ok {
+		return errs.New("error.chat_generation_in_progress")
+	}
+
+	db, err := s.db()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	err = db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		res, err := tx.NewUpdate().
+			Table("conversations").
+			Set("archived_at = COALESCE(archived_at, ?)", sqlite.NowUTC()).
+			Where("id = ?", conversationID).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return errs.New("error.chat_conversation_not_found")
+		}
+		return archiveMessagesAfter(ctx, tx, conversationID, 0, ArchiveReasonConversation, "")
+	})
+	if err != nil {
+		if _, ok := err.(*errs.I18nError); ok {
+			return err
+		}
+		return errs.Wrap("error.chat_archive_failed", err)
+	}
+
+	s.app.Logger.Info("[chat] conversation archived", "conv", conversationID)
+	return nil
+}
+
+// RestoreConversation puts the messages archived by ArchiveConversation back (with their original
+// IDs and timestamps) and shows the conversation in the list again. Messages archived by
+// EditAndResend stay in the archive since the conversation has moved on from them.
+func (s *ChatService) RestoreConversation(conversationID int64) error {
+	if conversationID <= 0 {
+		return errs.New("error.chat_conversation_id_required")
+	}
+
+	db, err := s.db()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	err = db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		res, err := tx.NewUpdate().
+			Table("conversations").
+			Set("archived_at = NULL").
+			Where("id = ?", conversationID).
+			Where("archived_at IS NOT NULL").
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return errs.New("error.chat_conversation_not_found")
+		}
+		return restoreConversationMessages(ctx, tx, conversationID)
+	})
+	if err != nil {
+		if _, ok := err.(*errs.I18nError); ok {
+			return err
+		}
+		return errs.Wrap("error.chat_restore_failed", err)
+	}
+
+	s.app.Logger.Info("[chat] conversation restored", "conv", conversationID)
+	return nil
+}
+
+// restoreConversationMessages moves the messages archived with a conversation back into it.
+// Also used by ConversationsService.UnarchiveConversation (conversations.RestoreArchivedMessages).
+func restoreConversationMessages(ctx context.Context, db bun.IDB, conversationID int64) error {
+	var archived []archivedMessageModel
+	if err := db.NewSelect().
+		Model(&archived).
+		Where("conversation_id = ?", conversationID).
+		Where("reason = ?", ArchiveReasonConversation).
+		OrderExpr("message_id ASC").
+		Scan(ctx); err != nil {
+		return err
+	}
+	if len(archived) == 0 {
+		return nil
+	}
+	for i := range archived {
+		if err := restoreArchivedMessage(ctx, db, &archived[i]); err != nil {
+			return err
+		}
+	}
+	_, err := db.NewDelete().
+		Model((*archivedMessageModel)(nil)).
+		Where("conversation_id = ?", conversationID).
+		Where("reason = ?", ArchiveReasonConversation).
+		Exec(ctx)
+	return err
+}
+
+// restoreArchivedMessage inserts an archived row back into messages with its original ID and timestamps.
+func restoreArchivedMessage(ctx context.Context, db bun.IDB, am *archivedMessageModel) error {
+	var m messageModel
+	if err := json.Unmarshal([]byte(am.Data), &m); err != nil {
+		return err
+	}
+	m.ConversationID = am.ConversationID
+	if _, err := db.NewInsert().Model(&m).Exec(ctx); err != nil {
+		return err
+	}
+	// The insert hook stamps now; put the original timestamps back
+	_, err := db.NewUpdate().
+		Table("messages").
+		Set("created_at = ?", m.CreatedAt).
+		Set("updated_at = ?", m.UpdatedAt).
+		Where("id = ?", m.ID).
+		Exec(ctx)
+	return err
+}
+
+// GetArchivedMessages returns the archived messages of a conversation, oldest first.
+func (s *ChatService) GetArchivedMessages(conversationID int64) ([]ArchivedMessage, error) {
+	if conversationID <= 0 {
+		return nil, errs.New("error.chat_conversation_id_required")
+	}
+
+	db, err := s.db()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var archived []archivedMessageModel
+	if err := db.NewSelect().
+		Model(&archived).
+		Where("conversation_id = ?", conversationID).
+		OrderExpr("message_id ASC").
+		Scan(ctx); err != nil {
+		return nil, errs.Wrap("error.chat_messages_failed", err)
+	}
+
+	out := make([]ArchivedMessage, 0, len(archived))
+	for i := range archived {
+		var m messageModel
+		if err := json.Unmarshal([]byte(archived[i].Data), &m); err != nil {
+			s.app.Logger.Warn("[chat] failed to parse archived message", "id", archived[i].ID, "error", err)
+			continue
+		}
+		out = append(out, ArchivedMessage{
+			Message:     m.toDTO(),
+			Reason:      archived[i].Reason,
+			OperationID: archived[i].OperationID,
+			ArchivedAt:  archived[i].ArchivedAt,
+		})
+	}
+	return out, nil
+}
+
+// UndoLastEdit reverts the most recent archived EditAndResend of a conversation: the replies
+// generated after the edit are deleted, the edited message gets its previous content back and the
+// archived messages are restored. editID (SendMessageResult.EditID of EditAndResend) pins the
+// operation so a tab cannot undo an edit made later from another tab; empty = the latest edit.
+// Returns the conversation's messages after the undo.
+func (s *ChatService) UndoLastEdit(conversationID int64, editID string) ([]Message, error) {
+	if conversationID <= 0 {
+		return nil, errs.New("error.chat_conversation_id_required")
+	}
+	if _, ok := s.activeGenerations.Load(conversationID); ok {
+		return nil, errs.New("error.chat_generation_in_progress")
+	}
+
+	db, err := s.db()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var latest archivedMessageModel
+	if err := db.NewSelect().
+		Model(&latest).
+		Where("conversation_id = ?", conversationID).
+		Where("reason = ?", ArchiveReasonEditSource).
+		OrderExpr("id DESC").
+		Limit(1).
+		Scan(ctx); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errs.New("error.chat_undo_nothing")
+		}
+		return nil, errs.Wrap("error.chat_undo_failed", err)
+	}
+	if editID != "" && latest.OperationID != editID {
+		return nil, errs.New("error.chat_undo_edit_superseded")
+	}
+
+	err = db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		var source messageModel
+		if err := json.Unmarshal([]byte(latest.Data), &source); err != nil {
+			return err
+		}
+
+		// Drop what was generated after the edit, then put the edited message back as it was
+		if _, err := tx.NewDelete().
+			Model((*messageModel)(nil)).
+			Where("conversation_id = ?", conversationID).
+			Where("id >= ?", source.ID).
+			Exec(ctx); err != nil {
+			return err
+		}
+		var archived []archivedMessageModel
+		if err := tx.NewSelect().
+			Model(&archived).
+			Where("conversation_id = ?", conversationID).
+			Where("operation_id = ?", latest.OperationID).
+			OrderExpr("message_id ASC").
+			Scan(ctx); err != nil {
+			return err
+		}
+		for i := range archived {
+			if err := restoreArchivedMessage(ctx, tx, &archived[i]); err != nil {
+				return err
+			}
+		}
+		_, err := tx.NewDelete().
+			Model((*archivedMessageModel)(nil)).
+			Where("operation_id = ?", latest.OperationID).
+			Exec(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, errs.Wrap("error.chat_undo_failed", err)
+	}
+
+	s.app.Logger.Info("[chat] edit undone", "conv", conversationID, "edit", latest.OperationID)
+	return s.GetMessages(conversationID)
+}
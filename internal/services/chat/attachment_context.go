@@ -0,0 +1,125 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"chatclaw/internal/eino/processor"
+)
+
+const (
+	// maxAttachmentContextRunes caps the document text injected into a single message
+	// (all inline attachments combined).
+	maxAttachmentContextRunes = 60000
+	attachmentParseTimeout    = 60 * time.Second
+)
+
+// Inline attachment parse states reported in ChatAttachmentEvent.
+const (
+	AttachmentParsing = "parsing"
+	AttachmentParsed  = "done"
+	AttachmentFailed  = "failed"
+)
+
+// hasInlineAttachments reports whether any attachment needs parsing by buildAttachmentContext.
+func hasInlineAttachments(attachments []ImagePayload) bool {
+	for _, att := range attachments {
+		if att.Kind == "file" && att.Inline && att.FilePath != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// buildAttachmentContext parses inline document attachments (kind=file, inline=true) and returns
+// the text to prepend to the message context. Parsing reuses the knowledge base parser but skips
+// splitting and embedding. Attachments whose text had to be cut are marked Truncated.
+// It runs in the generation goroutine and reports each file with EventChatAttachment; only files
+// stored under attachmentDir (the conversation work dir) are read.
+func (s *ChatService) buildAttachmentContext(ctx context.Context, ev ChatEvent, attachmentDir string, attachments []ImagePayload) string {
+	remaining := maxAttachmentContextRunes
+	var blocks []string
+
+	total := 0
+	for _, att := range attachments {
+		if att.Kind == "file" && att.Inline && att.FilePath != "" {
+			total++
+		}
+	}
+
+	index := 0
+	for i := range attachments {
+		att := &attachments[i]
+		if att.Kind != "file" || !att.Inline || att.FilePath == "" {
+			continue
+		}
+		index++
+		name := att.OriginalName
+		if name == "" {
+			name = att.FileName
+		}
+		progress := func(status string) {
+			e := ev
+			e.Ts = time.Now().UnixMilli()
+			s.app.Event.Emit(EventChatAttachment, ChatAttachmentEvent{
+				ChatEvent: e,
+				Index:     index,
+				Total:     total,
+				FileName:  name,
+				Status:    status,
+			})
+		}
+		unreadable := fmt.Sprintf("[Attached document: %s]\n(The document could not be read.)", name)
+
+		if attachmentDir == "" || !pathWithin(att.FilePath, attachmentDir) {
+			s.app.Logger.Warn("[chat] inline attachment outside conversation work dir", "path", att.FilePath)
+			progress(AttachmentFailed)
+			blocks = append(blocks, unreadable)
+			continue
+		}
+
+		progress(AttachmentParsing)
+		parseCtx, cancel := context.WithTimeout(ctx, attachmentParseTimeout)
+		text, err := processor.ExtractText(parseCtx, att.FilePath)
+		cancel()
+		if err != nil {
+			s.app.Logger.Warn("[chat] failed to parse inline attachment", "path", att.FilePath, "error", err)
+			progress(AttachmentFailed)
+			blocks = append(blocks, unreadable)
+			continue
+		}
+		progress(AttachmentParsed)
+
+		runes := []rune(strings.TrimSpace(text))
+		if len(runes) > remaining {
+			att.Truncated = true
+			s.app.Logger.Warn("[chat] inline attachment truncated", "path", att.FilePath, "chars", len(runes), "kept", remaining)
+			runes = runes[:remaining]
+		}
+		remaining -= len(runes)
+
+		block := fmt.Sprintf("[Attached document: %s]\n%s", name, string(runes))
+		if att.Truncated {
+			block += fmt.Sprintf("\n[Document truncated: only the first %d characters are included]", len(runes))
+		}
+		blocks = append(blocks, block+"\n[End of document]")
+	}
+
+	return strings.Join(blocks, "\n\n")
+}
+
+// parseInlineAttachments runs buildAttachmentContext for a generation and returns the attachment
+// context together with imagesJSON re-serialized so the Truncated flags are persisted.
+func (s *ChatService) parseInlineAttachments(ctx context.Context, conversationID int64, tabID, requestID, attachmentDir, imagesJSON string, attachments []ImagePayload) (string, string) {
+	ev := ChatEvent{ConversationID: conversationID, TabID: tabID, RequestID: requestID}
+	attachmentContext := s.buildAttachmentContext(ctx, ev, attachmentDir, attachments)
+	if b, err := json.Marshal(attachments); err != nil {
+		s.app.Logger.Warn("[chat] failed to serialize updated images", "error", err)
+	} else {
+		imagesJSON = string(b)
+	}
+	return imagesJSON, attachmentContext
+}
@@ -0,0 +1,101 @@
+package chat
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"chatclaw/internal/errs"
+
+	"github.com/uptrace/bun"
+)
+
+// PickAttachmentFiles opens the native file picker and returns the chosen files. Only paths
+// handed out here, or files the app stored under the conversation's work directory, are
+// accepted as ImagePayload.FilePath / SendMessageInput.ReferenceFiles.
+func (s *ChatService) PickAttachmentFiles() ([]string, error) {
+	paths, err := s.app.Dialog.OpenFile().
+		CanChooseFiles(true).
+		CanChooseDirectories(false).
+		PromptForMultipleSelection()
+	if err != nil {
+		return nil, errs.Wrap("error.chat_pick_files_failed", err)
+	}
+	out := make([]string, 0, len(paths))
+	for _, p := range paths {
+		if p = strings.TrimSpace(p); p == "" {
+			continue
+		}
+		p = filepath.Clean(p)
+		s.pickedFiles.Store(p, struct{}{})
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+// conversationAttachmentDir returns the directory saveImagesToWorkDir stores the attachments
+// of a conversation under.
+func (s *ChatService) conversationAttachmentDir(ctx context.Context, db *bun.DB, conversationID int64) (string, error) {
+	var agentID int64
+	if err := db.NewSelect().
+		Table("conversations").
+		Column("agent_id").
+		Where("id = ?", conversationID).
+		Scan(ctx, &agentID); err != nil {
+		return "", errs.Wrap("error.chat_conversation_read_failed", err)
+	}
+	return s.resolveWorkDir(ctx, db, agentID, conversationID)
+}
+
+// checkAttachmentPath validates a caller-supplied local path: it must be absolute and either
+// issued by PickAttachmentFiles or point into attachmentDir. Returns the cleaned path.
+func (s *ChatService) checkAttachmentPath(path, attachmentDir string) (string, error) {
+	p := filepath.Clean(strings.TrimSpace(path))
+	if !filepath.IsAbs(p) {
+		return "", errs.Newf("error.chat_attachment_path_not_allowed", map[string]any{"Path": path})
+	}
+	if _, ok := s.pickedFiles.Load(p); ok {
+		return p, nil
+	}
+	if attachmentDir != "" && pathWithin(p, attachmentDir) {
+		return p, nil
+	}
+	return "", errs.Newf("error.chat_attachment_path_not_allowed", map[string]any{"Path": path})
+}
+
+// attachmentPathChecker returns a checkAttachmentPath bound to the conversation's attachment
+// directory, resolved on first use so messages without local paths skip the lookup.
+func (s *ChatService) attachmentPathChecker(conversationID int64) func(string) (string, error) {
+	var attachmentDir string
+	return func(path string) (string, error) {
+		if attachmentDir == "" {
+			db, err := s.db()
+			if err != nil {
+				return "", err
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if attachmentDir, err = s.conversationAttachmentDir(ctx, db, conversationID); err != nil {
+				return "", err
+			}
+		}
+		return s.checkAttachmentPath(path, attachmentDir)
+	}
+}
+
+// pathWithin reports whether path lies inside dir once symlinks are resolved, so a link
+// inside dir cannot point back out of it.
+func pathWithin(path, dir string) bool {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+		dir = resolved
+	}
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	return rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) && !filepath.IsAbs(rel)
+}
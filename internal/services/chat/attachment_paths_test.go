@@ -0,0 +1,40 @@
+package chat
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPathWithin(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "sessions", "conv")
+	if err := os.MkdirAll(filepath.Join(dir, "files"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	outside := filepath.Join(root, "secret.txt")
+	if err := os.WriteFile(outside, []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(dir, "files", "link.txt")
+	if err := os.Symlink(outside, link); err != nil {
+		t.Skipf("symlinks unsupported: %v", err)
+	}
+
+	cases := []struct {
+		path string
+		want bool
+	}{
+		{filepath.Join(dir, "files", "a.pdf"), true},
+		{dir, false},
+		{filepath.Join(dir, "..", "other", "a.pdf"), false},
+		{filepath.Join(root, "sessions", "conv2", "a.pdf"), false},
+		{outside, false},
+		{link, false},
+	}
+	for _, c := range cases {
+		if got := pathWithin(c.path, dir); got != c.want {
+			t.Errorf("pathWithin(%q) = %v, want %v", c.path, got, c.want)
+		}
+	}
+}
@@ -19,7 +19,7 @@ import (
 // runChatModeGeneration handles the "chat" mode: direct LLM call with
 // knowledge-base retrieval injected into the system prompt.
 // No ReAct loop or tool calling — just a single streaming LLM invocation.
-func (s *ChatService) runChatModeGeneration(ctx context.Context, db *bun.DB, conversationID int64, tabID, requestID, userContent, imagesJSON string, agentConfig einoagent.Config, providerConfig einoagent.ProviderConfig, agentExtras AgentExtras) {
+func (s *ChatService) runChatModeGeneration(ctx context.Context, db *bun.DB, conversationID int64, tabID, requestID, userContent, imagesJSON, attachmentsJSON, attachmentContext string, agentConfig einoagent.Config, providerConfig einoagent.ProviderConfig, agentExtras AgentExtras) {
 	gc := &generationContext{
 		service:        s,
 		db:             db,
@@ -34,13 +34,18 @@ func (s *ChatService) runChatModeGeneration(ctx context.Context, db *bun.DB, con
 	if imagesJSON == "" {
 		imagesJSON = "[]"
 	}
+	if attachmentsJSON == "" {
+		attachmentsJSON = "[]"
+	}
 	userMsg := &messageModel{
-		ConversationID: conversationID,
-		Role:           RoleUser,
-		Content:        userContent,
-		Status:         StatusSuccess,
-		ToolCalls:      "[]",
-		ImagesJSON:     imagesJSON,
+		ConversationID:    conversationID,
+		Role:              RoleUser,
+		Content:           userContent,
+		Status:            StatusSuccess,
+		ToolCalls:         "[]",
+		ImagesJSON:        imagesJSON,
+		Attachments:       attachmentsJSON,
+		AttachmentContext: attachmentContext,
 	}
 
 	dbCtx, dbCancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -52,9 +57,10 @@ func (s *ChatService) runChatModeGeneration(ctx context.Context, db *bun.DB, con
 	dbCancel()
 
 	gc.emit(EventChatUserMessage, ChatUserMessageEvent{
-		ChatEvent:  gc.chatEvent(userMsg.ID),
-		Content:    userContent,
-		ImagesJSON: imagesJSON,
+		ChatEvent:   gc.chatEvent(userMsg.ID),
+		Content:     userContent,
+		ImagesJSON:  imagesJSON,
+		Attachments: decodeMessageAttachments(attachmentsJSON),
 	})
 
 	s.runChatModeCore(ctx, gc, userContent)
@@ -101,6 +107,7 @@ func (s *ChatService) runChatModeCore(ctx context.Context, gc *generationContext
 	}
 	dbCancel()
 
+	s.setGenerationMessage(conversationID, gc.requestID, assistantMsg.ID)
 	gc.emit(EventChatStart, ChatStartEvent{
 		ChatEvent: gc.chatEvent(assistantMsg.ID),
 		Status:    StatusStreaming,
@@ -170,6 +177,39 @@ func (s *ChatService) runChatModeCore(ctx context.Context, gc *generationContext
 		return
 	}
 
+	streamFailed, streamErrMsg := s.consumeModelStream(ctx, gc, ss, assistantMsg.ID, stream)
+
+	if ctx.Err() != nil {
+		s.updateMessageFinal(db, assistantMsg.ID, ss.contentBuilder.String(), ss.thinkingBuilder.String(), "[]", ss.segmentsStr(), StatusCancelled, "", "cancelled", ss.inputTokens, ss.outputTokens)
+		gc.emit(EventChatStopped, ChatStoppedEvent{
+			ChatEvent: gc.chatEvent(assistantMsg.ID),
+			Status:    StatusCancelled,
+		})
+		return
+	}
+
+	if streamFailed {
+		s.updateMessageFinal(db, assistantMsg.ID, ss.contentBuilder.String(), ss.thinkingBuilder.String(), "[]", ss.segmentsStr(), StatusError, streamErrMsg, "", ss.inputTokens, ss.outputTokens)
+		return
+	}
+
+	citations := s.saveCitations(db, assistantMsg.ID, ss)
+	s.updateMessageFinal(db, assistantMsg.ID, ss.contentBuilder.String(), ss.thinkingBuilder.String(), "[]", ss.segmentsStr(), StatusSuccess, "", ss.finishReason, ss.inputTokens, ss.outputTokens)
+
+	complete := newChatCompleteEvent(gc.chatEvent(assistantMsg.ID), StatusSuccess, ss.finishReason)
+	complete.Citations = citations
+	gc.emit(EventChatComplete, complete)
+	go s.maybeGenerateTitle(conversationID)
+}
+
+// consumeModelStream forwards a single chat model stream into ss, emitting thinking/chunk events for
+// messageID. It returns whether the stream failed (the error event has already been emitted).
+func (s *ChatService) consumeModelStream(ctx context.Context, gc *generationContext, ss *streamState, messageID int64, stream *schema.StreamReader[*schema.Message]) (bool, string) {
+	conversationID := gc.conversationID
+	if stripThinkTagsEnabled(gc.providerConfig.ExtraConfig) {
+		stream = splitThinkTagStream(stream)
+		defer stream.Close()
+	}
 	streamFailed := false
 	streamErrMsg := ""
 	for {
@@ -182,7 +222,7 @@ func (s *ChatService) runChatModeCore(ctx context.Context, gc *generationContext
 				break
 			}
 			s.app.Logger.Error("[chat] chat_mode stream recv failed", "conv", conversationID, "error", err)
-			gc.emitError("error.chat_stream_failed", map[string]any{"Error": err.Error()})
+			gc.emitError(streamErrorKey(gc, err), map[string]any{"Error": err.Error()})
 			streamFailed = true
 			streamErrMsg = err.Error()
 			break
@@ -196,7 +236,7 @@ func (s *ChatService) runChatModeCore(ctx context.Context, gc *generationContext
 			ss.thinkingBuilder.WriteString(msg.ReasoningContent)
 			ss.addThinkingToSegments(msg.ReasoningContent)
 			gc.emit(EventChatThinking, ChatThinkingEvent{
-				ChatEvent: gc.chatEvent(assistantMsg.ID),
+				ChatEvent: gc.chatEvent(messageID),
 				Delta:     msg.ReasoningContent,
 			})
 		}
@@ -206,7 +246,7 @@ func (s *ChatService) runChatModeCore(ctx context.Context, gc *generationContext
 			ss.addContentToSegments(msg.Content)
 			s.appendGenerationContent(conversationID, gc.requestID, msg.Content)
 			gc.emit(EventChatChunk, ChatChunkEvent{
-				ChatEvent: gc.chatEvent(assistantMsg.ID),
+				ChatEvent: gc.chatEvent(messageID),
 				Delta:     msg.Content,
 			})
 			// Notify registered streaming sinks (e.g. DingTalk real-time card updates).
@@ -225,28 +265,7 @@ func (s *ChatService) runChatModeCore(ctx context.Context, gc *generationContext
 			}
 		}
 	}
-
-	if ctx.Err() != nil {
-		s.updateMessageFinal(db, assistantMsg.ID, ss.contentBuilder.String(), ss.thinkingBuilder.String(), "[]", ss.segmentsStr(), StatusCancelled, "", "cancelled", ss.inputTokens, ss.outputTokens)
-		gc.emit(EventChatStopped, ChatStoppedEvent{
-			ChatEvent: gc.chatEvent(assistantMsg.ID),
-			Status:    StatusCancelled,
-		})
-		return
-	}
-
-	if streamFailed {
-		s.updateMessageFinal(db, assistantMsg.ID, ss.contentBuilder.String(), ss.thinkingBuilder.String(), "[]", ss.segmentsStr(), StatusError, streamErrMsg, "", ss.inputTokens, ss.outputTokens)
-		return
-	}
-
-	s.updateMessageFinal(db, assistantMsg.ID, ss.contentBuilder.String(), ss.thinkingBuilder.String(), "[]", ss.segmentsStr(), StatusSuccess, "", ss.finishReason, ss.inputTokens, ss.outputTokens)
-
-	gc.emit(EventChatComplete, ChatCompleteEvent{
-		ChatEvent:    gc.chatEvent(assistantMsg.ID),
-		Status:       StatusSuccess,
-		FinishReason: ss.finishReason,
-	})
+	return streamFailed, streamErrMsg
 }
 
 // buildRetrievalContext performs knowledge-base retrieval, emits a
@@ -267,12 +286,34 @@ func (s *ChatService) buildRetrievalContext(ctx context.Context, gc *generationC
 			for i, r := range kbResults {
 				sb.WriteString(fmt.Sprintf("---\n[Source %d] (score: %.2f)\n%s\n", i+1, r.Score, r.Content))
 				retrievalItems = append(retrievalItems, RetrievalItem{Source: "knowledge", Content: r.Content, Score: r.Score})
+				ss.addCitations(Citation{DocumentID: r.DocumentID, NodeID: r.NodeID, DocumentName: r.DocumentName, Content: r.Content, Score: r.Score})
 			}
 			sb.WriteString(teamRecallContextFooter)
 			parts = append(parts, sb.String())
 			s.app.Logger.Info("[chat] chat_mode kb retrieval", "conv", gc.conversationID, "results", len(kbResults))
 		}
 	}
+	if index := agentExtras.ReferenceIndex; index.Len() > 0 {
+		topK := agentConfig.RetrievalTopK
+		if topK <= 0 {
+			topK = 10
+		}
+		refResults, err := index.Search(ctx, retrieval.SearchInput{Query: userQuery, TopK: topK})
+		if err != nil {
+			s.app.Logger.Warn("[chat] chat_mode reference search failed", "error", err)
+		} else if len(refResults) > 0 {
+			var sb strings.Builder
+			sb.WriteString(teamRecallContextHeader)
+			for i, r := range refResults {
+				sb.WriteString(fmt.Sprintf("---\n[Reference %d: %s] (score: %.2f)\n%s\n", i+1, r.DocumentName, r.Score, r.Content))
+				retrievalItems = append(retrievalItems, RetrievalItem{Source: "knowledge", Content: r.Content, Score: r.Score})
+				ss.addCitations(Citation{DocumentID: r.DocumentID, NodeID: r.NodeID, DocumentName: r.DocumentName, Content: r.Content, Score: r.Score})
+			}
+			sb.WriteString(teamRecallContextFooter)
+			parts = append(parts, sb.String())
+			s.app.Logger.Info("[chat] chat_mode reference retrieval", "conv", gc.conversationID, "results", len(refResults))
+		}
+	}
 	if agentExtras.TeamLibraryID != "" {
 		teamResults := s.retrieveFromTeamLibrary(ctx, agentExtras.TeamLibraryID, userQuery, teamRecallSize)
 		if len(teamResults) > 0 {
@@ -301,8 +342,11 @@ func (s *ChatService) buildRetrievalContext(ctx context.Context, gc *generationC
 }
 
 type retrievalResult struct {
-	Content string
-	Score   float64
+	NodeID       int64
+	DocumentID   int64
+	DocumentName string
+	Content      string
+	Score        float64
 }
 
 func (s *ChatService) retrieveFromKnowledgeBase(ctx context.Context, db *bun.DB, libraryIDs []int64, query string, topK int, matchThreshold float64) []retrievalResult {
@@ -344,7 +388,13 @@ func (s *ChatService) retrieveFromKnowledgeBase(ctx context.Context, db *bun.DB,
 
 	out := make([]retrievalResult, 0, len(results))
 	for _, r := range results {
-		out = append(out, retrievalResult{Content: r.Content, Score: r.Score})
+		out = append(out, retrievalResult{
+			NodeID:       r.NodeID,
+			DocumentID:   r.DocumentID,
+			DocumentName: r.DocumentName,
+			Content:      r.Content,
+			Score:        r.Score,
+		})
 	}
 	return out
 }
@@ -0,0 +1,107 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"chatclaw/internal/eino/tools"
+
+	"github.com/uptrace/bun"
+)
+
+// Citation is a knowledge chunk the reply was grounded on. Index is the 1-based footnote number,
+// stable for the message: chunks are numbered in the order they were first retrieved.
+type Citation struct {
+	Index        int     `json:"index"`
+	DocumentID   int64   `json:"document_id"`
+	NodeID       int64   `json:"node_id"`
+	DocumentName string  `json:"document_name,omitempty"`
+	Content      string  `json:"content"`
+	Score        float64 `json:"score"`
+}
+
+type citationKey struct {
+	documentID int64
+	nodeID     int64
+}
+
+// addCitations records retrieved chunks; duplicates are merged by dedupeCitations when the reply
+// completes.
+func (ss *streamState) addCitations(items ...Citation) {
+	ss.citations = append(ss.citations, items...)
+}
+
+// addToolResultCitations records the chunks returned by a retriever tool call. Other tools and
+// results that are not retriever output (e.g. error text) are ignored.
+func (ss *streamState) addToolResultCitations(toolName, content string) {
+	if toolName != tools.ToolIDLibraryRetriever && toolName != tools.ToolIDReferenceRetriever {
+		return
+	}
+	var out tools.LibraryRetrieverOutput
+	if err := json.Unmarshal([]byte(content), &out); err != nil {
+		return
+	}
+	for _, r := range out.Results {
+		ss.addCitations(Citation{
+			DocumentID:   r.DocumentID,
+			NodeID:       r.NodeID,
+			DocumentName: r.DocumentName,
+			Content:      r.Content,
+			Score:        r.Score,
+		})
+	}
+}
+
+// dedupeCitations merges citations of the same chunk (document_id, node_id), keeping the best
+// score, and numbers the result in order of first appearance. Chunks without a node ID cannot be
+// identified and are dropped.
+func dedupeCitations(raw []Citation) []Citation {
+	out := make([]Citation, 0, len(raw))
+	seen := make(map[citationKey]int, len(raw))
+	for _, c := range raw {
+		if c.NodeID == 0 {
+			continue
+		}
+		key := citationKey{c.DocumentID, c.NodeID}
+		if i, ok := seen[key]; ok {
+			if c.Score > out[i].Score {
+				out[i].Score = c.Score
+			}
+			if out[i].DocumentName == "" {
+				out[i].DocumentName = c.DocumentName
+			}
+			continue
+		}
+		seen[key] = len(out)
+		out = append(out, c)
+	}
+	for i := range out {
+		out[i].Index = i + 1
+	}
+	return out
+}
+
+// saveCitations stores the deduplicated citations of a completed reply and returns them for the
+// complete event. It runs once per generation, before updateMessageFinal.
+func (s *ChatService) saveCitations(db *bun.DB, messageID int64, ss *streamState) []Citation {
+	citations := dedupeCitations(ss.citations)
+	if len(citations) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(citations)
+	if err != nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := db.NewUpdate().
+		Model((*messageModel)(nil)).
+		Set("citations = ?", string(data)).
+		Where("id = ?", messageID).
+		Exec(ctx); err != nil {
+		s.app.Logger.Warn("[chat] save citations failed", "messageID", messageID, "error", err)
+	}
+	return citations
+}
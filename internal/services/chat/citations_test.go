@@ -0,0 +1,25 @@
+package chat
+
+import "testing"
+
+func TestDedupeCitationsKeepsBestScoreAndFirstOrder(t *testing.T) {
+	ss := newStreamState(nil, nil)
+	ss.addCitations(Citation{DocumentID: 1, NodeID: 10, Content: "a", Score: 0.4})
+	ss.addToolResultCitations("library_retriever", `{"results":[
+		{"node_id":20,"document_id":2,"document_name":"b.pdf","content":"b","score":0.9},
+		{"node_id":10,"document_id":1,"document_name":"a.pdf","content":"a","score":0.7}
+	],"total_count":2,"query_count":1}`)
+	ss.addToolResultCitations("read_file", `{"results":[{"node_id":30,"document_id":3}]}`)
+	ss.addCitations(Citation{Content: "team recall without ids", Score: 1})
+
+	got := dedupeCitations(ss.citations)
+	if len(got) != 2 {
+		t.Fatalf("got %d citations, want 2: %+v", len(got), got)
+	}
+	if got[0].NodeID != 10 || got[0].Index != 1 || got[0].Score != 0.7 || got[0].DocumentName != "a.pdf" {
+		t.Fatalf("first citation = %+v", got[0])
+	}
+	if got[1].NodeID != 20 || got[1].Index != 2 {
+		t.Fatalf("second citation = %+v", got[1])
+	}
+}
@@ -0,0 +1,185 @@
+package chat
+
+import (
+	"context"
+	"time"
+
+	"chatclaw/internal/errs"
+	"chatclaw/internal/sqlite"
+
+	"github.com/uptrace/bun"
+)
+
+// cleanupBatchSize bounds the number of conversation IDs bound into one DELETE statement.
+const cleanupBatchSize = 500
+
+// ClearConversationsResult reports what a bulk conversation cleanup removed.
+type ClearConversationsResult struct {
+	Conversations int64 `json:"conversations"`
+	Messages      int64 `json:"messages"` // live and archived messages
+}
+
+// ClearConversationsEvent is emitted on EventConversationsChanged, once per affected agent,
+// after a bulk cleanup.
+type ClearConversationsEvent struct {
+	AgentID int64  `json:"agent_id"`
+	Action  string `json:"action"` // always "cleared"
+}
+
+// DeleteConversationsOlderThan deletes conversations that have not been updated in the last
+// `days` days, together with their messages. Pinned conversations are kept.
+func (s *ChatService) DeleteConversationsOlderThan(days int) (*ClearConversationsResult, error) {
+	if days <= 0 {
+		return nil, errs.New("error.chat_cleanup_days_invalid")
+	}
+	cutoff := time.Now().UTC().AddDate(0, 0, -days).Format(sqlite.DateTimeFormat)
+	return s.clearConversations(func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Where("updated_at < ?", cutoff).Where("is_pinned = ?", false)
+	})
+}
+
+// ClearAllConversations deletes every conversation (including pinned and archived ones) and
+// all of their messages.
+func (s *ChatService) ClearAllConversations() (*ClearConversationsResult, error) {
+	return s.clearConversations(func(q *bun.SelectQuery) *bun.SelectQuery { return q })
+}
+
+// DeleteConversations deletes the given conversations together with their messages, cancelling
+// any generation still running for them first. IDs that no longer exist are skipped; the result
+// tells how many conversations were actually deleted.
+func (s *ChatService) DeleteConversations(ids []int64) (*ClearConversationsResult, error) {
+	seen := make(map[int64]bool, len(ids))
+	valid := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if id > 0 && !seen[id] {
+			seen[id] = true
+			valid = append(valid, id)
+		}
+	}
+	if len(valid) == 0 {
+		return nil, errs.New("error.chat_conversation_id_required")
+	}
+	return s.clearConversations(func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Where("id IN (?)", bun.In(valid))
+	})
+}
+
+// clearConversations cancels running generations for the selected conversations, then deletes
+// the conversations, their messages and archived messages in one transaction. Attachments go
+// with their messages through the foreign key cascade; scheduled task runs are kept as history
+// but lose their link to the deleted conversation.
+func (s *ChatService) clearConversations(filter func(*bun.SelectQuery) *bun.SelectQuery) (*ClearConversationsResult, error) {
+	db, err := s.db()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	var rows []struct {
+		ID      int64 `bun:"id"`
+		AgentID int64 `bun:"agent_id"`
+	}
+	if err := filter(db.NewSelect().Table("conversations").Column("id", "agent_id")).Scan(ctx, &rows); err != nil {
+		return nil, errs.Wrap("error.chat_cleanup_failed", err)
+	}
+	result := &ClearConversationsResult{}
+	if len(rows) == 0 {
+		return result, nil
+	}
+	ids := make([]int64, len(rows))
+	agentIDs := make(map[int64]bool)
+	for i, r := range rows {
+		ids[i] = r.ID
+		agentIDs[r.AgentID] = true
+	}
+
+	s.stopGenerationsFor(ids)
+
+	err = db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		for start := 0; start < len(ids); start += cleanupBatchSize {
+			batch := ids[start:min(start+cleanupBatchSize, len(ids))]
+
+			res, err := tx.NewDelete().
+				Model((*messageModel)(nil)).
+				Where("conversation_id IN (?)", bun.In(batch)).
+				Exec(ctx)
+			if err != nil {
+				return err
+			}
+			n, _ := res.RowsAffected()
+			result.Messages += n
+
+			res, err = tx.NewDelete().
+				Model((*archivedMessageModel)(nil)).
+				Where("conversation_id IN (?)", bun.In(batch)).
+				Exec(ctx)
+			if err != nil {
+				return err
+			}
+			n, _ = res.RowsAffected()
+			result.Messages += n
+
+			if _, err := tx.NewUpdate().
+				Table("scheduled_task_runs").
+				Set("conversation_id = NULL").
+				Set("user_message_id = NULL").
+				Set("assistant_message_id = NULL").
+				Where("conversation_id IN (?)", bun.In(batch)).
+				Exec(ctx); err != nil {
+				return err
+			}
+
+			res, err = tx.NewDelete().
+				Table("conversations").
+				Where("id IN (?)", bun.In(batch)).
+				Exec(ctx)
+			if err != nil {
+				return err
+			}
+			n, _ = res.RowsAffected()
+			result.Conversations += n
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errs.Wrap("error.chat_cleanup_failed", err)
+	}
+
+	s.app.Logger.Info("[chat] conversations cleared", "conversations", result.Conversations, "messages", result.Messages)
+	for agentID := range agentIDs {
+		s.app.Event.Emit(EventConversationsChanged, ClearConversationsEvent{
+			AgentID: agentID,
+			Action:  "cleared",
+		})
+	}
+	return result, nil
+}
+
+// stopGenerationsFor cancels the active generations of the given conversations and waits
+// (bounded by stopGenerationTimeout each) for them to exit, so none of them writes to a
+// conversation that is about to be deleted.
+func (s *ChatService) stopGenerationsFor(conversationIDs []int64) {
+	for _, id := range conversationIDs {
+		existing, ok := s.activeGenerations.Load(id)
+		if !ok {
+			continue
+		}
+		gen := existing.(*activeGeneration)
+		gen.cancel()
+		select {
+		case <-gen.done:
+		case <-time.After(stopGenerationTimeout):
+			s.app.Logger.Warn("[chat] generation did not finish within timeout", "conv", id)
+		}
+
+		gen.mu.Lock()
+		interrupted := gen.interrupted
+		gen.interrupted = false
+		gen.mu.Unlock()
+		if interrupted {
+			s.cleanupGeneration(gen, id)
+		}
+	}
+}
@@ -9,7 +9,9 @@ import (
 	"strings"
 
 	einoagent "chatclaw/internal/eino/agent"
+	"chatclaw/internal/eino/tools"
 	"chatclaw/internal/errs"
+	"chatclaw/internal/services/retrieval"
 	"chatclaw/internal/services/settings"
 	"chatclaw/internal/services/toolchain"
 
@@ -26,6 +28,14 @@ type AgentExtras struct {
 	MCPEnabled          bool
 	MCPServerIDs        []string // IDs in agent list
 	MCPServerEnabledIDs []string // IDs enabled for generation (subset)
+
+	// ThinkingUnsupported is set when the conversation asks for thinking but the model has no
+	// reasoning support; EnableThinking was turned off for this request.
+	ThinkingUnsupported bool
+
+	// ReferenceIndex holds the reference files of the message being answered (SendMessageInput.ReferenceFiles);
+	// nil when there are none. It is closed when the turn ends.
+	ReferenceIndex *retrieval.MemoryIndex
 }
 
 // getAgentAndProviderConfig gets the agent and provider configuration for a conversation.
@@ -41,11 +51,21 @@ func (s *ChatService) getAgentAndProviderConfig(ctx context.Context, db *bun.DB,
 		TeamLibraryID  string `bun:"team_library_id"`
 		EnableThinking bool   `bun:"enable_thinking"`
 		ChatMode       string `bun:"chat_mode"`
+		MaxIterations  int    `bun:"max_iterations"`
+		ThinkingBudget int    `bun:"thinking_budget"`
+
+		LLMTemperature       float64 `bun:"llm_temperature"`
+		LLMTopP              float64 `bun:"llm_top_p"`
+		LLMMaxTokens         int     `bun:"llm_max_tokens"`
+		EnableLLMTemperature bool    `bun:"enable_llm_temperature"`
+		EnableLLMTopP        bool    `bun:"enable_llm_top_p"`
+		EnableLLMMaxTokens   bool    `bun:"enable_llm_max_tokens"`
 	}
 	var conv conversationRow
 	if err := db.NewSelect().
 		Table("conversations").
-		Column("agent_id", "agent_type", "llm_provider_id", "llm_model_id", "library_ids", "team_library_id", "enable_thinking", "chat_mode").
+		Column("agent_id", "agent_type", "llm_provider_id", "llm_model_id", "library_ids", "team_library_id", "enable_thinking", "chat_mode", "max_iterations", "thinking_budget",
+			"llm_temperature", "llm_top_p", "llm_max_tokens", "enable_llm_temperature", "enable_llm_top_p", "enable_llm_max_tokens").
 		Where("id = ?", conversationID).
 		Scan(ctx, &conv); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -82,6 +102,11 @@ func (s *ChatService) getAgentAndProviderConfig(ctx context.Context, db *bun.DB,
 		MCPEnabled              bool    `bun:"mcp_enabled"`
 		MCPServerIDs            string  `bun:"mcp_server_ids"`
 		MCPServerEnabledIDs     string  `bun:"mcp_server_enabled_ids"`
+		EnabledTools            string  `bun:"enabled_tools"`
+		MaxToolIterations       int     `bun:"max_tool_iterations"`
+		ThinkingBudget          int     `bun:"thinking_budget"`
+		ResponseLanguage        string  `bun:"response_language"`
+		OnToolError             string  `bun:"on_tool_error"`
 	}
 	var agent agentRow
 
@@ -94,6 +119,7 @@ func (s *ChatService) getAgentAndProviderConfig(ctx context.Context, db *bun.DB,
 		"llm_max_context_count", "retrieval_top_k", "retrieval_match_threshold",
 		"sandbox_mode", "sandbox_network", "work_dir",
 		"mcp_enabled", "mcp_server_ids", "mcp_server_enabled_ids",
+		"enabled_tools", "max_tool_iterations", "thinking_budget", "response_language", "on_tool_error",
 	}
 	if conv.AgentType == "openclaw" {
 		agentTable = "openclaw_agents"
@@ -105,6 +131,8 @@ func (s *ChatService) getAgentAndProviderConfig(ctx context.Context, db *bun.DB,
 			"llm_max_context_count", "retrieval_top_k", "retrieval_match_threshold",
 			"sandbox_mode", "sandbox_network", "work_dir",
 			"mcp_enabled", "mcp_server_ids", "mcp_server_enabled_ids",
+			"'[]' AS enabled_tools", "0 AS max_tool_iterations", "0 AS thinking_budget", "'' AS response_language",
+			"'continue' AS on_tool_error",
 		}
 	}
 
@@ -119,6 +147,17 @@ func (s *ChatService) getAgentAndProviderConfig(ctx context.Context, db *bun.DB,
 		return einoagent.Config{}, einoagent.ProviderConfig{}, AgentExtras{}, errs.Wrap("error.chat_agent_read_failed", err)
 	}
 
+	// Conversation sampling overrides win over the agent's values (conversation > agent > provider default)
+	if conv.EnableLLMTemperature {
+		agent.LLMTemperature, agent.EnableLLMTemperature = conv.LLMTemperature, true
+	}
+	if conv.EnableLLMTopP {
+		agent.LLMTopP, agent.EnableLLMTopP = conv.LLMTopP, true
+	}
+	if conv.EnableLLMMaxTokens {
+		agent.LLMMaxTokens, agent.EnableLLMMaxTokens = conv.LLMMaxTokens, true
+	}
+
 	providerID := conv.LLMProviderID
 	modelID := conv.LLMModelID
 	if providerID == "" {
@@ -155,7 +194,16 @@ func (s *ChatService) getAgentAndProviderConfig(ctx context.Context, db *bun.DB,
 		return einoagent.Config{}, einoagent.ProviderConfig{}, AgentExtras{}, errs.New("error.chat_provider_not_enabled")
 	}
 
-	instruction := fmt.Sprintf("# System Instruction\n\n%s", strings.TrimSpace(agent.Prompt))
+	prompt := strings.TrimSpace(agent.Prompt)
+	if strings.Contains(prompt, "{{") {
+		vars, err := loadPromptTemplateVars(ctx, db, convLibraryIDs, modelID)
+		if err != nil {
+			s.app.Logger.Warn("[chat] failed to load prompt template vars", "conv", conversationID, "error", err)
+		}
+		prompt = expandPromptTemplate(prompt, vars)
+	}
+	prompt = withResponseLanguage(prompt, agent.ResponseLanguage)
+	instruction := fmt.Sprintf("# System Instruction\n\n%s", prompt)
 
 	agentConfig := einoagent.Config{
 		Name:            agent.Name,
@@ -177,6 +225,23 @@ func (s *ChatService) getAgentAndProviderConfig(ctx context.Context, db *bun.DB,
 		ConversationID:  conversationID,
 		ToolchainBinDir: toolchain.BinDirIfReady(),
 		SkillsEnabled:   settings.GetBool("skills_enabled", true),
+		MaxIterations:   agent.MaxToolIterations,
+		ThinkingBudget:  agent.ThinkingBudget,
+		OnToolError:     agent.OnToolError,
+	}
+	// The conversation's limit (task mode) overrides the agent default
+	if conv.MaxIterations > 0 {
+		agentConfig.MaxIterations = conv.MaxIterations
+	}
+	if conv.ThinkingBudget > 0 {
+		agentConfig.ThinkingBudget = conv.ThinkingBudget
+	}
+
+	if agent.EnabledTools != "" && agent.EnabledTools != "[]" {
+		if err := json.Unmarshal([]byte(agent.EnabledTools), &agentConfig.EnabledTools); err != nil {
+			s.app.Logger.Warn("[chat] failed to parse enabled_tools", "agent", conv.AgentID, "error", err)
+			agentConfig.EnabledTools = nil
+		}
 	}
 
 	providerConfig := einoagent.ProviderConfig{
@@ -199,6 +264,24 @@ func (s *ChatService) getAgentAndProviderConfig(ctx context.Context, db *bun.DB,
 		chatMode = "task"
 	}
 
+	// Respect the model's capability flags: thinking is not sent to models without reasoning
+	// support (reported through AgentExtras.ThinkingUnsupported so user-initiated sends can
+	// reject it), and tool-less models run in chat mode since task mode relies on tool calls.
+	flags := getModelFlags(providerID, modelID)
+	thinkingUnsupported := agentConfig.EnableThinking && !flags.SupportsReasoning
+	if thinkingUnsupported {
+		s.app.Logger.Warn("[chat] thinking enabled but model does not support reasoning", "conv", conversationID, "provider", providerID, "model", modelID)
+		agentConfig.EnableThinking = false
+	}
+	if chatMode == "task" && !flags.SupportsTools {
+		s.app.Logger.Info("[chat] model does not support tools, fallback to chat mode", "conv", conversationID, "model", modelID)
+		chatMode = "chat"
+	}
+	if chatMode == "task" && tools.NoToolsAllowed(agentConfig.EnabledTools) {
+		s.app.Logger.Info("[chat] agent has all tools disabled, fallback to chat mode", "conv", conversationID, "agent", conv.AgentID)
+		chatMode = "chat"
+	}
+
 	var mcpServerIDs []string
 	if agent.MCPServerIDs != "" && agent.MCPServerIDs != "[]" {
 		if err := json.Unmarshal([]byte(agent.MCPServerIDs), &mcpServerIDs); err != nil {
@@ -228,6 +311,7 @@ func (s *ChatService) getAgentAndProviderConfig(ctx context.Context, db *bun.DB,
 		MCPEnabled:          agent.MCPEnabled && settings.GetBool("mcp_enabled", false),
 		MCPServerIDs:        mcpServerIDs,
 		MCPServerEnabledIDs: mcpServerEnabledIDs,
+		ThinkingUnsupported: thinkingUnsupported,
 	}
 
 	return agentConfig, providerConfig, extras, nil
@@ -0,0 +1,166 @@
+package chat
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	einoagent "chatclaw/internal/eino/agent"
+	"chatclaw/internal/errs"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/uptrace/bun"
+)
+
+// continuationPrompt is sent (not saved) after a reply that was cut off by the output token limit.
+const continuationPrompt = "Your previous reply was cut off because it reached the output length limit. " +
+	"Continue exactly where it stopped. Do not repeat any text that was already written and do not add any preamble."
+
+// ContinueGeneration resumes an assistant reply that stopped at the output length limit.
+// messageID must be the last message of the conversation (0 = whatever the last message is).
+// The continuation is streamed into the same message (same MessageID).
+func (s *ChatService) ContinueGeneration(conversationID int64, tabID string, messageID int64) (*SendMessageResult, error) {
+	if conversationID <= 0 {
+		return nil, errs.New("error.chat_conversation_id_required")
+	}
+
+	if existing, ok := s.activeGenerations.Load(conversationID); ok {
+		if existing.(*activeGeneration).tabID != tabID {
+			return nil, errs.New("error.chat_generation_in_progress_other_tab")
+		}
+		return nil, errs.New("error.chat_generation_in_progress")
+	}
+
+	db, err := s.db()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var last messageModel
+	if err := db.NewSelect().
+		Model(&last).
+		Where("conversation_id = ?", conversationID).
+		OrderExpr("id DESC").
+		Limit(1).
+		Scan(ctx); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errs.New("error.chat_message_not_found")
+		}
+		return nil, errs.Wrap("error.chat_message_read_failed", err)
+	}
+	if messageID > 0 && last.ID != messageID {
+		return nil, errs.New("error.chat_continue_not_last_message")
+	}
+	if !canContinueReply(last.Role, last.Status, last.FinishReason) {
+		return nil, errs.New("error.chat_continue_not_truncated")
+	}
+
+	agentConfig, providerConfig, agentExtras, err := s.getAgentAndProviderConfig(ctx, db, conversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.app.Logger.Info("[chat] ContinueGeneration", "conv", conversationID, "tab", tabID, "msg", last.ID)
+
+	result, err := s.startGeneration(db, conversationID, tabID, agentConfig, providerConfig, agentExtras, func(genCtx context.Context, requestID string) {
+		s.runContinuation(genCtx, db, conversationID, tabID, requestID, &last, agentConfig, providerConfig, agentExtras)
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.MessageID = last.ID
+	return result, nil
+}
+
+// runContinuation streams a plain model call (no tools) whose output is appended to assistantMsg.
+// The truncated reply is already part of the loaded history, followed by continuationPrompt.
+func (s *ChatService) runContinuation(ctx context.Context, db *bun.DB, conversationID int64, tabID, requestID string, assistantMsg *messageModel, agentConfig einoagent.Config, providerConfig einoagent.ProviderConfig, agentExtras AgentExtras) {
+	gc := &generationContext{
+		service:        s,
+		db:             db,
+		conversationID: conversationID,
+		tabID:          tabID,
+		requestID:      requestID,
+		agentConfig:    agentConfig,
+		providerConfig: providerConfig,
+		agentExtras:    agentExtras,
+	}
+
+	messages, err := s.loadMessagesForContext(ctx, db, conversationID, agentConfig.ContextCount, providerConfig.ProviderID, agentConfig.ModelID)
+	if err != nil {
+		gc.emitError("error.chat_messages_failed", nil)
+		return
+	}
+	messages = patchToolCallsForChatMode(messages)
+
+	// Seed the stream state with what was already generated so the final update keeps it
+	ss := newStreamState(gc, assistantMsg)
+	ss.contentBuilder.WriteString(assistantMsg.Content)
+	ss.thinkingBuilder.WriteString(assistantMsg.ThinkingContent)
+	ss.inputTokens = assistantMsg.InputTokens
+	ss.outputTokens = assistantMsg.OutputTokens
+	if assistantMsg.Segments != "" && assistantMsg.Segments != "[]" {
+		if err := json.Unmarshal([]byte(assistantMsg.Segments), &ss.segments); err != nil {
+			s.app.Logger.Warn("[chat] continue: failed to parse segments", "msg", assistantMsg.ID, "error", err)
+		}
+	}
+	if n := len(ss.segments); n > 0 {
+		ss.lastSegmentType = ss.segments[n-1].Type
+	}
+	toolCalls := assistantMsg.ToolCalls
+	if toolCalls == "" {
+		toolCalls = "[]"
+	}
+
+	s.updateMessageStatus(db, assistantMsg.ID, StatusStreaming, "", "")
+	s.setGenerationMessage(conversationID, gc.requestID, assistantMsg.ID)
+	gc.emit(EventChatStart, ChatStartEvent{
+		ChatEvent: gc.chatEvent(assistantMsg.ID),
+		Status:    StatusStreaming,
+	})
+
+	agentConfig.Provider = providerConfig
+	chatModel, err := einoagent.CreateChatModel(ctx, agentConfig)
+	if err != nil {
+		gc.emitError("error.chat_agent_create_failed", map[string]any{"Error": err.Error()})
+		s.updateMessageFinal(db, assistantMsg.ID, ss.contentBuilder.String(), ss.thinkingBuilder.String(), toolCalls, ss.segmentsStr(), StatusError, err.Error(), "", ss.inputTokens, ss.outputTokens)
+		return
+	}
+
+	fullMessages := make([]*schema.Message, 0, len(messages)+2)
+	fullMessages = append(fullMessages, &schema.Message{Role: schema.System, Content: agentConfig.Instruction})
+	fullMessages = append(fullMessages, messages...)
+	fullMessages = append(fullMessages, &schema.Message{Role: schema.User, Content: continuationPrompt})
+
+	stream, err := chatModel.Stream(ctx, fullMessages)
+	if err != nil {
+		gc.emitError("error.chat_generation_failed", map[string]any{"Error": err.Error()})
+		s.updateMessageFinal(db, assistantMsg.ID, ss.contentBuilder.String(), ss.thinkingBuilder.String(), toolCalls, ss.segmentsStr(), StatusError, err.Error(), "", ss.inputTokens, ss.outputTokens)
+		return
+	}
+
+	streamFailed, streamErrMsg := s.consumeModelStream(ctx, gc, ss, assistantMsg.ID, stream)
+
+	if ctx.Err() != nil {
+		s.updateMessageFinal(db, assistantMsg.ID, ss.contentBuilder.String(), ss.thinkingBuilder.String(), toolCalls, ss.segmentsStr(), StatusCancelled, "", "cancelled", ss.inputTokens, ss.outputTokens)
+		gc.emit(EventChatStopped, ChatStoppedEvent{
+			ChatEvent: gc.chatEvent(assistantMsg.ID),
+			Status:    StatusCancelled,
+		})
+		return
+	}
+
+	if streamFailed {
+		s.updateMessageFinal(db, assistantMsg.ID, ss.contentBuilder.String(), ss.thinkingBuilder.String(), toolCalls, ss.segmentsStr(), StatusError, streamErrMsg, "", ss.inputTokens, ss.outputTokens)
+		return
+	}
+
+	s.updateMessageFinal(db, assistantMsg.ID, ss.contentBuilder.String(), ss.thinkingBuilder.String(), toolCalls, ss.segmentsStr(), StatusSuccess, "", ss.finishReason, ss.inputTokens, ss.outputTokens)
+
+	gc.emit(EventChatComplete, newChatCompleteEvent(gc.chatEvent(assistantMsg.ID), StatusSuccess, ss.finishReason))
+}
@@ -0,0 +1,361 @@
+package chat
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"chatclaw/internal/errs"
+
+	"github.com/uptrace/bun"
+)
+
+const (
+	conversationBundleVersion     = 1
+	conversationBundleTranscript  = "transcript.md"
+	conversationBundleMetadata    = "metadata.json"
+	conversationBundleAttachments = "attachments/"
+	conversationExportTimeout     = 2 * time.Minute
+)
+
+// ConversationBundleMetadata is the metadata.json of a conversation bundle.
+type ConversationBundleMetadata struct {
+	Version      int                          `json:"version"`
+	ExportedAt   time.Time                    `json:"exported_at"`
+	Conversation ConversationBundleInfo       `json:"conversation"`
+	MessageCount int                          `json:"message_count"`
+	Attachments  []ConversationBundleAttached `json:"attachments"`
+}
+
+// ConversationBundleInfo describes the exported conversation.
+type ConversationBundleInfo struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	AgentID   int64     `json:"agent_id"`
+	AgentType string    `json:"agent_type"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ConversationBundleAttached is one image or file referenced by a message.
+type ConversationBundleAttached struct {
+	MessageID int64  `json:"message_id"`
+	Kind      string `json:"kind"` // "image" or "file"
+	Name      string `json:"name"`
+	MimeType  string `json:"mime_type,omitempty"`
+	Path      string `json:"path,omitempty"` // path inside the bundle; empty when the source was missing
+	Size      int64  `json:"size,omitempty"`
+	Missing   bool   `json:"missing,omitempty"`
+}
+
+// ExportConversationMarkdown renders a conversation as Markdown. Attachments are linked by their
+// local path (images without a saved file are inlined as data URLs), so the result is meant for
+// this machine; use ExportConversationBundle to share it.
+func (s *ChatService) ExportConversationMarkdown(conversationID int64) (string, error) {
+	db, err := s.db()
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	info, messages, err := loadConversationForExport(ctx, db, conversationID)
+	if err != nil {
+		return "", err
+	}
+	return renderConversationMarkdown(info, messages, func(_ Message, _ int, att ImagePayload) string {
+		if att.FilePath != "" {
+			return att.FilePath
+		}
+		if att.Kind != "file" && att.Base64 != "" {
+			return "data:" + att.MimeType + ";base64," + att.Base64
+		}
+		return ""
+	}), nil
+}
+
+// ExportConversationBundle writes a self-contained zip of a conversation to destPath: the Markdown
+// transcript (transcript.md), the referenced images and files under attachments/ with the
+// transcript linking to them relatively, and metadata.json. Attachments whose file is gone are
+// listed as missing instead of failing the export.
+func (s *ChatService) ExportConversationBundle(conversationID int64, destPath string) error {
+	destPath = strings.TrimSpace(destPath)
+	if destPath == "" {
+		return errs.New("error.chat_export_path_required")
+	}
+
+	db, err := s.db()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), conversationExportTimeout)
+	defer cancel()
+
+	info, messages, err := loadConversationForExport(ctx, db, conversationID)
+	if err != nil {
+		return err
+	}
+
+	// Write to a temp file and rename on success so a failed export leaves no half-written bundle
+	tmpPath := destPath + ".tmp"
+	if err := s.writeConversationBundle(info, messages, tmpPath); err != nil {
+		os.Remove(tmpPath)
+		return errs.Wrap("error.chat_export_failed", err)
+	}
+	if err := os.Rename(tmpPath, destPath); err != nil {
+		os.Remove(tmpPath)
+		return errs.Wrap("error.chat_export_failed", err)
+	}
+	s.app.Logger.Info("[chat] conversation bundle exported", "conv", conversationID, "path", destPath)
+	return nil
+}
+
+func (s *ChatService) writeConversationBundle(info *ConversationBundleInfo, messages []Message, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	zw := zip.NewWriter(f)
+
+	meta := ConversationBundleMetadata{
+		Version:      conversationBundleVersion,
+		ExportedAt:   time.Now().UTC(),
+		Conversation: *info,
+		MessageCount: len(messages),
+		Attachments:  []ConversationBundleAttached{},
+	}
+
+	// 1. Attachments, remembering where each one landed for the transcript links
+	links := make(map[string]string)
+	for _, m := range messages {
+		for i, att := range messageAttachments(m) {
+			entry := ConversationBundleAttached{
+				MessageID: m.ID,
+				Kind:      attachmentKind(att),
+				Name:      attachmentName(att),
+				MimeType:  att.MimeType,
+			}
+			name := fmt.Sprintf("%s%d_%d_%s", conversationBundleAttachments, m.ID, i+1, sanitizeFileName(entry.Name))
+			size, err := addAttachmentToBundle(zw, name, att)
+			switch {
+			case err == nil:
+				entry.Path = name
+				entry.Size = size
+				links[attachmentKey(m.ID, i)] = name
+			case os.IsNotExist(err) || errors.Is(err, errAttachmentNoData):
+				entry.Missing = true
+				s.app.Logger.Warn("[chat] export bundle: attachment missing", "msg", m.ID, "path", att.FilePath)
+			default:
+				return fmt.Errorf("add attachment %s: %w", entry.Name, err)
+			}
+			meta.Attachments = append(meta.Attachments, entry)
+		}
+	}
+
+	// 2. Transcript
+	transcript := renderConversationMarkdown(info, messages, func(m Message, i int, _ ImagePayload) string {
+		return links[attachmentKey(m.ID, i)]
+	})
+	w, err := zw.Create(conversationBundleTranscript)
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, transcript); err != nil {
+		return err
+	}
+
+	// 3. Metadata
+	w, err = zw.Create(conversationBundleMetadata)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(&meta); err != nil {
+		return err
+	}
+
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+var errAttachmentNoData = errors.New("attachment has no file or inline data")
+
+// addAttachmentToBundle copies an attachment into the zip, from its saved file or else from its
+// inline base64 data, and returns the number of bytes written.
+func addAttachmentToBundle(zw *zip.Writer, name string, att ImagePayload) (int64, error) {
+	var src io.Reader
+	if att.FilePath != "" {
+		f, err := os.Open(att.FilePath)
+		switch {
+		case err == nil:
+			defer f.Close()
+			src = f
+		case !os.IsNotExist(err) || att.Base64 == "":
+			return 0, err
+		}
+	}
+	if src == nil {
+		if att.Base64 == "" {
+			return 0, errAttachmentNoData
+		}
+		data, err := base64.StdEncoding.DecodeString(att.Base64)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %v", errAttachmentNoData, err)
+		}
+		src = bytes.NewReader(data)
+	}
+	w, err := zw.Create(name)
+	if err != nil {
+		return 0, err
+	}
+	return io.Copy(w, src)
+}
+
+// loadConversationForExport reads the conversation and its user/assistant messages in order.
+func loadConversationForExport(ctx context.Context, db *bun.DB, conversationID int64) (*ConversationBundleInfo, []Message, error) {
+	if conversationID <= 0 {
+		return nil, nil, errs.New("error.chat_conversation_id_required")
+	}
+
+	var info ConversationBundleInfo
+	if err := db.NewSelect().
+		Table("conversations").
+		Column("id", "name", "agent_id", "agent_type", "created_at", "updated_at").
+		Where("id = ?", conversationID).
+		Limit(1).
+		Scan(ctx, &info.ID, &info.Name, &info.AgentID, &info.AgentType, &info.CreatedAt, &info.UpdatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, errs.New("error.chat_conversation_not_found")
+		}
+		return nil, nil, errs.Wrap("error.chat_conversation_read_failed", err)
+	}
+
+	var models []messageModel
+	if err := db.NewSelect().
+		Model(&models).
+		Where("conversation_id = ?", conversationID).
+		Where("role IN (?)", bun.In([]string{RoleUser, RoleAssistant})).
+		OrderExpr("created_at ASC, id ASC").
+		Scan(ctx); err != nil {
+		return nil, nil, errs.Wrap("error.chat_messages_failed", err)
+	}
+	messages := make([]Message, 0, len(models))
+	for i := range models {
+		messages = append(messages, models[i].toDTO())
+	}
+	return &info, messages, nil
+}
+
+// renderConversationMarkdown renders the transcript. link returns the target for the i-th
+// attachment of a message; an empty target renders the attachment name without a link.
+func renderConversationMarkdown(info *ConversationBundleInfo, messages []Message, link func(m Message, i int, att ImagePayload) string) string {
+	var b strings.Builder
+	title := strings.TrimSpace(info.Name)
+	if title == "" {
+		title = fmt.Sprintf("Conversation %d", info.ID)
+	}
+	fmt.Fprintf(&b, "# %s\n\n", title)
+	fmt.Fprintf(&b, "_Created %s · updated %s_\n", formatExportTime(info.CreatedAt), formatExportTime(info.UpdatedAt))
+
+	for _, m := range messages {
+		atts := messageAttachments(m)
+		content := strings.TrimSpace(m.Content)
+		// Tool-call-only assistant turns carry nothing readable
+		if m.Role == RoleAssistant && content == "" && m.Error == "" && len(atts) == 0 {
+			continue
+		}
+
+		speaker := "User"
+		if m.Role == RoleAssistant {
+			speaker = "Assistant"
+			if m.ModelID != "" {
+				speaker += " (" + m.ModelID + ")"
+			}
+		}
+		fmt.Fprintf(&b, "\n## %s · %s\n\n", speaker, formatExportTime(m.CreatedAt))
+
+		if thinking := strings.TrimSpace(m.ThinkingContent); thinking != "" {
+			b.WriteString("<details>\n<summary>Thinking</summary>\n\n")
+			b.WriteString(thinking)
+			b.WriteString("\n\n</details>\n\n")
+		}
+		if content != "" {
+			b.WriteString(content)
+			b.WriteString("\n")
+		}
+		if m.Error != "" {
+			fmt.Fprintf(&b, "\n> Error: %s\n", strings.ReplaceAll(m.Error, "\n", " "))
+		}
+
+		if len(atts) > 0 {
+			b.WriteString("\n")
+		}
+		for i, att := range atts {
+			name := markdownLinkText.Replace(attachmentName(att))
+			target := link(m, i, att)
+			switch {
+			case target == "":
+				fmt.Fprintf(&b, "- %s (missing)\n", name)
+			case attachmentKind(att) == "image":
+				fmt.Fprintf(&b, "![%s](<%s>)\n", name, target)
+			default:
+				fmt.Fprintf(&b, "- [%s](<%s>)\n", name, target)
+			}
+		}
+	}
+	return b.String()
+}
+
+var markdownLinkText = strings.NewReplacer("[", `\[`, "]", `\]`)
+
+// messageAttachments parses a message's images_json; malformed data yields no attachments.
+func messageAttachments(m Message) []ImagePayload {
+	if strings.TrimSpace(m.ImagesJSON) == "" {
+		return nil
+	}
+	var atts []ImagePayload
+	if err := json.Unmarshal([]byte(m.ImagesJSON), &atts); err != nil {
+		return nil
+	}
+	return atts
+}
+
+func attachmentKind(att ImagePayload) string {
+	if att.Kind == "file" {
+		return "file"
+	}
+	return "image"
+}
+
+func attachmentName(att ImagePayload) string {
+	for _, name := range []string{att.OriginalName, att.FileName, filepath.Base(att.FilePath)} {
+		if name != "" && name != "." {
+			return name
+		}
+	}
+	return attachmentKind(att)
+}
+
+func attachmentKey(messageID int64, i int) string {
+	return fmt.Sprintf("%d/%d", messageID, i)
+}
+
+func formatExportTime(t time.Time) string {
+	return t.Local().Format("2006-01-02 15:04")
+}
@@ -0,0 +1,42 @@
+package chat
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRenderConversationMarkdown(t *testing.T) {
+	at := time.Date(2026, 1, 2, 3, 4, 0, 0, time.UTC)
+	info := &ConversationBundleInfo{ID: 7, Name: "Trip plan", CreatedAt: at, UpdatedAt: at}
+	messages := []Message{
+		{ID: 1, Role: RoleUser, Content: "See attached", CreatedAt: at,
+			ImagesJSON: `[{"kind":"image","file_name":"a.png"},{"kind":"file","original_name":"notes [v2].pdf"},{"kind":"file","original_name":"gone.txt"}]`},
+		{ID: 2, Role: RoleAssistant, Content: "", ToolCalls: `[{"id":"x"}]`, CreatedAt: at},
+		{ID: 3, Role: RoleAssistant, Content: "Done.", ThinkingContent: "hmm", ModelID: "m1", CreatedAt: at},
+	}
+	links := map[string]string{
+		attachmentKey(1, 0): "attachments/1_1_a.png",
+		attachmentKey(1, 1): "attachments/1_2_notes [v2].pdf",
+	}
+	md := renderConversationMarkdown(info, messages, func(m Message, i int, _ ImagePayload) string {
+		return links[attachmentKey(m.ID, i)]
+	})
+
+	for _, want := range []string{
+		"# Trip plan\n",
+		"![a.png](<attachments/1_1_a.png>)",
+		`- [notes \[v2\].pdf](<attachments/1_2_notes [v2].pdf>)`,
+		"- gone.txt (missing)",
+		"## Assistant (m1)",
+		"<summary>Thinking</summary>\n\nhmm",
+		"Done.",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("transcript missing %q:\n%s", want, md)
+		}
+	}
+	if n := strings.Count(md, "## Assistant"); n != 1 {
+		t.Errorf("tool-call-only turn should be skipped, got %d assistant headings", n)
+	}
+}
@@ -2,8 +2,10 @@ package chat
 
 import (
 	"context"
+	"database/sql"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -15,6 +17,7 @@ import (
 	einoagent "chatclaw/internal/eino/agent"
 	"chatclaw/internal/eino/tools"
 	"chatclaw/internal/services/channels"
+	"chatclaw/internal/services/conversations"
 	"chatclaw/internal/services/mcp"
 	"chatclaw/internal/services/skills"
 	"chatclaw/internal/sqlite"
@@ -76,7 +79,7 @@ func (g *generationContext) chatEvent(messageID int64) ChatEvent {
 }
 
 // runGeneration inserts the user message then delegates to runGenerationCore.
-func (s *ChatService) runGeneration(ctx context.Context, db *bun.DB, conversationID int64, tabID, requestID, userContent, imagesJSON string, agentConfig einoagent.Config, providerConfig einoagent.ProviderConfig, agentExtras AgentExtras) {
+func (s *ChatService) runGeneration(ctx context.Context, db *bun.DB, conversationID int64, tabID, requestID, userContent, imagesJSON, attachmentsJSON, attachmentContext string, agentConfig einoagent.Config, providerConfig einoagent.ProviderConfig, agentExtras AgentExtras) {
 	gc := &generationContext{
 		service:        s,
 		db:             db,
@@ -91,13 +94,18 @@ func (s *ChatService) runGeneration(ctx context.Context, db *bun.DB, conversatio
 	if imagesJSON == "" {
 		imagesJSON = "[]"
 	}
+	if attachmentsJSON == "" {
+		attachmentsJSON = "[]"
+	}
 	userMsg := &messageModel{
-		ConversationID: conversationID,
-		Role:           RoleUser,
-		Content:        userContent,
-		Status:         StatusSuccess,
-		ToolCalls:      "[]",
-		ImagesJSON:     imagesJSON,
+		ConversationID:    conversationID,
+		Role:              RoleUser,
+		Content:           userContent,
+		Status:            StatusSuccess,
+		ToolCalls:         "[]",
+		ImagesJSON:        imagesJSON,
+		Attachments:       attachmentsJSON,
+		AttachmentContext: attachmentContext,
 	}
 
 	dbCtx, dbCancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -109,9 +117,10 @@ func (s *ChatService) runGeneration(ctx context.Context, db *bun.DB, conversatio
 	dbCancel()
 
 	gc.emit(EventChatUserMessage, ChatUserMessageEvent{
-		ChatEvent:  gc.chatEvent(userMsg.ID),
-		Content:    userContent,
-		ImagesJSON: imagesJSON,
+		ChatEvent:   gc.chatEvent(userMsg.ID),
+		Content:     userContent,
+		ImagesJSON:  imagesJSON,
+		Attachments: decodeMessageAttachments(attachmentsJSON),
 	})
 
 	s.runGenerationCore(ctx, gc)
@@ -159,6 +168,7 @@ func (s *ChatService) runGenerationCore(ctx context.Context, gc *generationConte
 	}
 	dbCancel()
 
+	s.setGenerationMessage(conversationID, gc.requestID, assistantMsg.ID)
 	gc.emit(EventChatStart, ChatStartEvent{
 		ChatEvent: gc.chatEvent(assistantMsg.ID),
 		Status:    StatusStreaming,
@@ -285,6 +295,7 @@ func (s *ChatService) resumeGeneration(gen *activeGeneration, conversationID int
 	}
 	dbCancel()
 
+	s.setGenerationMessage(conversationID, gc.requestID, assistantMsg.ID)
 	gc.emit(EventChatStart, ChatStartEvent{
 		ChatEvent: gc.chatEvent(assistantMsg.ID),
 		Status:    StatusStreaming,
@@ -346,7 +357,7 @@ func (s *ChatService) buildExtras(ctx context.Context, gc *generationContext) ([
 	var extraHandlers []adk.ChatModelAgentMiddleware
 	var cleanups []func()
 
-	if len(agentExtras.LibraryIDs) > 0 {
+	if len(agentExtras.LibraryIDs) > 0 && tools.ToolAllowed(tools.ToolIDLibraryRetriever, agentConfig.EnabledTools) {
 		retrieverTool, toolErr := s.createLibraryRetrieverTool(ctx, gc.db, agentExtras.LibraryIDs, agentConfig.RetrievalTopK, agentExtras.MatchThreshold)
 		if toolErr != nil {
 			s.app.Logger.Warn("[chat] failed to create library retriever tool", "error", toolErr)
@@ -356,6 +367,20 @@ func (s *ChatService) buildExtras(ctx context.Context, gc *generationContext) ([
 		}
 	}
 
+	if index := agentExtras.ReferenceIndex; index.Len() > 0 && !tools.NoToolsAllowed(agentConfig.EnabledTools) {
+		referenceTool, toolErr := tools.NewReferenceRetrieverTool(ctx, index, agentConfig.RetrievalTopK)
+		if toolErr != nil {
+			s.app.Logger.Warn("[chat] failed to create reference retriever tool", "error", toolErr)
+		} else {
+			extraTools = append(extraTools, referenceTool)
+			s.app.Logger.Info("[chat] reference retriever tool created", "chunks", index.Len())
+		}
+	}
+	// The reference index lives as long as the agent (including an interrupted turn awaiting confirmation)
+	if agentExtras.ReferenceIndex != nil {
+		cleanups = append(cleanups, agentExtras.ReferenceIndex.Close)
+	}
+
 	if agentConfig.SkillsEnabled {
 		skillsSvc := skills.NewSkillsService(s.app)
 		skillTools, toolErr := tools.NewSkillManagementTools(&tools.SkillManagementConfig{
@@ -481,12 +506,39 @@ type streamState struct {
 
 	// Maps sub-agent name → active parent tool_call_id (for routing streaming events)
 	activeSubAgentToolCall map[string]string
+
+	// Retrieved knowledge chunks, deduplicated into the message citations on completion
+	citations []Citation
 }
 
 type toolCallState struct {
-	id   string
-	name string
-	args string
+	id       string
+	name     string
+	args     string
+	index    int    // stream index the call was first seen at
+	sentArgs int    // length of args already emitted as ArgsDelta
+	splitID  string // "<id>#<index>" when the provider reused id for another call, see updateToolStates
+	answered bool   // a tool result has been matched to this call
+}
+
+// callID is the ID persisted and emitted for the call. Calls split off by updateToolStates get
+// their state key so every call of a message has a unique ID.
+func (st *toolCallState) callID() string {
+	if st.splitID != "" {
+		return st.splitID
+	}
+	return st.id
+}
+
+// takeArgsDelta returns the part of args not yet emitted and marks it as sent. updateArgs only
+// ever extends args, so the unsent part is always a suffix.
+func (st *toolCallState) takeArgsDelta() string {
+	if st.sentArgs > len(st.args) {
+		st.sentArgs = 0
+	}
+	delta := st.args[st.sentArgs:]
+	st.sentArgs = len(st.args)
+	return delta
 }
 
 func newStreamState(gc *generationContext, assistantMsg *messageModel) *streamState {
@@ -588,25 +640,27 @@ func updateArgs(oldArgs, newArgs string) string {
 
 func (ss *streamState) buildToolCallsForDB() []schema.ToolCall {
 	out := make([]schema.ToolCall, 0, len(ss.toolOrder))
+	// Dedupe by state key, not by ID: a call split off by updateToolStates shares its ID with
+	// another call but is a distinct invocation.
 	seen := make(map[string]struct{})
 	for _, key := range ss.toolOrder {
 		st := ss.toolStatesByKey[key]
 		if st == nil || st.id == "" || st.name == "" {
 			continue
 		}
-		if _, ok := seen[st.id]; ok {
+		if _, ok := seen[key]; ok {
 			continue
 		}
 		if isHiddenTool(st.name) {
 			continue
 		}
-		seen[st.id] = struct{}{}
+		seen[key] = struct{}{}
 		args := st.args
 		if !json.Valid([]byte(args)) {
 			args = "{}"
 		}
 		out = append(out, schema.ToolCall{
-			ID: st.id,
+			ID: st.callID(),
 			Function: schema.FunctionCall{
 				Name:      st.name,
 				Arguments: args,
@@ -624,17 +678,23 @@ func (ss *streamState) updateToolStates(toolCalls []schema.ToolCall) {
 		}
 
 		key := tc.ID
-		if key == "" {
-			if existing, ok := ss.indexKeyMap[idx]; ok {
-				key = existing
-			} else {
-				key = fmt.Sprintf("idx_%d", idx)
-			}
+		split := false
+		if existing, ok := ss.indexKeyMap[idx]; ok && (tc.ID == "" || ss.toolStatesByKey[existing].id == tc.ID) {
+			key = existing
+		} else if key == "" {
+			key = fmt.Sprintf("idx_%d", idx)
+		} else if prev, ok := ss.toolStatesByKey[key]; ok && prev.index != idx && startsNewToolCall(prev, tc) {
+			// Some proxies reuse one tool_call ID across stream indices; keep those calls apart
+			key = fmt.Sprintf("%s#%d", tc.ID, idx)
+			split = true
 		}
 
 		st, ok := ss.toolStatesByKey[key]
 		if !ok {
-			st = &toolCallState{}
+			st = &toolCallState{index: idx}
+			if split {
+				st.splitID = key
+			}
 			ss.toolStatesByKey[key] = st
 			ss.toolOrder = append(ss.toolOrder, key)
 		}
@@ -653,6 +713,44 @@ func (ss *streamState) updateToolStates(toolCalls []schema.ToolCall) {
 	}
 }
 
+// resolveToolResult maps the tool_call_id of a tool result to the call it answers. When a
+// provider reused one ID for several calls, results are matched to those calls in order, so
+// each result carries the same unique ID as its call in the persisted tool_calls.
+func (ss *streamState) resolveToolResult(toolCallID string) (id, name string) {
+	if toolCallID == "" {
+		return "", ""
+	}
+	var fallback *toolCallState
+	for _, key := range ss.toolOrder {
+		st := ss.toolStatesByKey[key]
+		if st == nil || st.id != toolCallID {
+			continue
+		}
+		if !st.answered {
+			st.answered = true
+			return st.callID(), st.name
+		}
+		if fallback == nil {
+			fallback = st
+		}
+	}
+	if fallback != nil {
+		return fallback.callID(), fallback.name
+	}
+	return toolCallID, ""
+}
+
+// startsNewToolCall reports whether a chunk at a different stream index that carries the same ID
+// as prev is the head of another call rather than a delta of prev: it names a tool while prev is
+// already named, or both carry complete but different JSON arguments.
+func startsNewToolCall(prev *toolCallState, tc schema.ToolCall) bool {
+	if tc.Function.Name != "" && prev.name != "" {
+		return true
+	}
+	args := tc.Function.Arguments
+	return args != "" && args != prev.args && json.Valid([]byte(args)) && json.Valid([]byte(prev.args))
+}
+
 func (ss *streamState) toolCallsStr() string {
 	if len(ss.toolCallsJSON) > 0 {
 		return string(ss.toolCallsJSON)
@@ -715,11 +813,24 @@ func (s *ChatService) consumeEventIter(ctx context.Context, gc *generationContex
 		if event.Err != nil {
 			errMsg := event.Err.Error()
 			errorKey := "error.chat_generation_failed"
-			if strings.Contains(errMsg, "exceeds max iterations") {
+			abortErr, toolAborted := einoagent.AsToolAbortError(event.Err)
+			if einoagent.IsMaxIterationsError(event.Err) {
 				errorKey = "error.max_iterations_exceeded"
+			} else if toolAborted {
+				// on_tool_error = "abort": stop instead of letting the model retry a broken tool
+				errorKey = "error.chat_tool_failed_aborted"
+				errMsg = abortErr.Error()
 			}
 			s.app.Logger.Error("[chat] generation failed", "conv", gc.conversationID, "tab", gc.tabID, "req", gc.requestID, "error", event.Err)
-			gc.emitError(errorKey, map[string]any{"Error": errMsg})
+			errData := map[string]any{"Error": errMsg}
+			if errorKey == "error.max_iterations_exceeded" {
+				errData["MaxIterations"] = gc.agentConfig.MaxIterations
+				errData["MaxIterationsLimit"] = conversations.MaxIterationsLimit
+			} else if toolAborted {
+				errData["Tool"] = abortErr.Tool
+				errData["Error"] = abortErr.Err.Error()
+			}
+			gc.emitError(errorKey, errData)
 			s.updateMessageFinal(gc.db, assistantMsg.ID, ss.contentBuilder.String(), ss.thinkingBuilder.String(), ss.toolCallsStr(), ss.segmentsStr(), StatusError, errMsg, "", ss.inputTokens, ss.outputTokens)
 			return processStreamResult{}
 		}
@@ -749,13 +860,13 @@ func (s *ChatService) consumeEventIter(ctx context.Context, gc *generationContex
 		return processStreamResult{}
 	}
 
+	citations := s.saveCitations(gc.db, assistantMsg.ID, ss)
 	s.updateMessageFinal(gc.db, assistantMsg.ID, ss.contentBuilder.String(), ss.thinkingBuilder.String(), ss.toolCallsStr(), ss.segmentsStr(), StatusSuccess, "", ss.finishReason, ss.inputTokens, ss.outputTokens)
 
-	gc.emit(EventChatComplete, ChatCompleteEvent{
-		ChatEvent:    gc.chatEvent(assistantMsg.ID),
-		Status:       StatusSuccess,
-		FinishReason: ss.finishReason,
-	})
+	complete := newChatCompleteEvent(gc.chatEvent(assistantMsg.ID), StatusSuccess, ss.finishReason)
+	complete.Citations = citations
+	gc.emit(EventChatComplete, complete)
+	go s.maybeGenerateTitle(gc.conversationID)
 	return processStreamResult{}
 }
 
@@ -781,11 +892,7 @@ func (s *ChatService) handleInterrupt(_ context.Context, gc *generationContext,
 		ChatEvent: gc.chatEvent(assistantMsg.ID),
 		Delta:     promptText,
 	})
-	gc.emit(EventChatComplete, ChatCompleteEvent{
-		ChatEvent:    gc.chatEvent(assistantMsg.ID),
-		Status:       StatusInterrupted,
-		FinishReason: "interrupted",
-	})
+	gc.emit(EventChatComplete, newChatCompleteEvent(gc.chatEvent(assistantMsg.ID), StatusInterrupted, "interrupted"))
 
 	s.app.Logger.Info("[chat] generation interrupted, waiting for user confirmation", "conv", gc.conversationID)
 
@@ -823,8 +930,13 @@ func extractInterruptCommand(event *adk.AgentEvent) *einoagent.InterruptInfo {
 }
 
 func (s *ChatService) processStreamingOutput(ctx context.Context, gc *generationContext, ss *streamState, msgOutput *adk.MessageVariant) {
+	stream := msgOutput.MessageStream
+	if stripThinkTagsEnabled(gc.providerConfig.ExtraConfig) {
+		stream = splitThinkTagStream(stream)
+		defer stream.Close()
+	}
 	for {
-		msg, err := msgOutput.MessageStream.Recv()
+		msg, err := stream.Recv()
 		if err == io.EOF {
 			break
 		}
@@ -833,7 +945,7 @@ func (s *ChatService) processStreamingOutput(ctx context.Context, gc *generation
 				break
 			}
 			s.app.Logger.Error("[chat] stream recv failed", "conv", gc.conversationID, "tab", gc.tabID, "req", gc.requestID, "error", err)
-			gc.emitError("error.chat_stream_failed", map[string]any{"Error": err.Error()})
+			gc.emitError(streamErrorKey(gc, err), map[string]any{"Error": err.Error()})
 			break
 		}
 
@@ -881,6 +993,16 @@ func (s *ChatService) processStreamingOutput(ctx context.Context, gc *generation
 	}
 }
 
+// streamErrorKey picks the error key for a failed stream recv. Gemini fails with an empty
+// result when the prompt is blocked by its safety filter, which the generic message hides;
+// the dedicated key points the user at the provider's safety_settings instead.
+func streamErrorKey(gc *generationContext, err error) string {
+	if einoagent.IsGeminiBlockedError(gc.providerConfig.Type, err) {
+		return "error.chat_gemini_prompt_blocked"
+	}
+	return "error.chat_stream_failed"
+}
+
 // isHiddenTool returns true for tools whose call/result events should not be
 // sent to the frontend or persisted as tool messages.
 func isHiddenTool(name string) bool {
@@ -888,23 +1010,21 @@ func isHiddenTool(name string) bool {
 }
 
 func (s *ChatService) processNonStreamingOutput(gc *generationContext, ss *streamState, msg *schema.Message) {
+	if msg.Role != schema.Tool && stripThinkTagsEnabled(gc.providerConfig.ExtraConfig) {
+		msg = splitNonStreamingThinkTags(msg)
+	}
 	if len(msg.ToolCalls) > 0 {
 		ss.updateToolStates(msg.ToolCalls)
 	}
 
 	if msg.Role == schema.Tool {
+		toolCallID, callName := ss.resolveToolResult(msg.ToolCallID)
 		toolName := msg.ToolName
 		if toolName == "" {
 			toolName = msg.Name
 		}
-		if toolName == "" && msg.ToolCallID != "" {
-			for _, key := range ss.toolOrder {
-				st := ss.toolStatesByKey[key]
-				if st != nil && st.id == msg.ToolCallID && st.name != "" {
-					toolName = st.name
-					break
-				}
-			}
+		if toolName == "" {
+			toolName = callName
 		}
 
 		if isHiddenTool(toolName) {
@@ -915,44 +1035,70 @@ func (s *ChatService) processNonStreamingOutput(gc *generationContext, ss *strea
 		if len(ss.currentRunPath) <= 1 {
 			delete(ss.activeSubAgentToolCall, toolName)
 		}
+		ss.addToolResultCitations(toolName, msg.Content)
 
 		gc.emit(EventChatTool, ChatToolEvent{
 			ChatEvent:        gc.chatEvent(ss.assistantMsg.ID),
 			Type:             "result",
-			ToolCallID:       msg.ToolCallID,
+			ToolCallID:       toolCallID,
 			ToolName:         toolName,
 			ResultJSON:       msg.Content,
 			RunPath:          ss.currentRunPath,
 			ParentToolCallID: ss.parentToolCallID(),
 		})
 
+		// Large results are stored truncated so they don't flood later context; the full
+		// output is kept separately for display when it fits under maxStoredToolResultChars.
+		content, truncated := truncateToolResult(msg.Content, toolResultMaxChars())
+		var fullContent string
+		if truncated {
+			s.app.Logger.Info("[chat] tool result truncated", "conv", gc.conversationID, "tool", toolName, "chars", len(msg.Content))
+			if len(msg.Content) <= maxStoredToolResultChars {
+				fullContent = msg.Content
+			}
+		}
 		toolMsg := &messageModel{
 			ConversationID: gc.conversationID,
 			Role:           RoleTool,
-			Content:        msg.Content,
+			Content:        content,
+			FullContent:    fullContent,
 			Status:         StatusSuccess,
-			ToolCallID:     msg.ToolCallID,
+			ToolCallID:     toolCallID,
 			ToolCallName:   toolName,
 			ToolCalls:      "[]",
 		}
 		dbCtx, dbCancel := context.WithTimeout(context.Background(), 5*time.Second)
 		if _, err := gc.db.NewInsert().Model(toolMsg).Exec(dbCtx); err != nil {
-			s.app.Logger.Warn("[chat] failed to save tool message", "conv", gc.conversationID, "tool", toolName, "call_id", msg.ToolCallID, "error", err)
+			s.app.Logger.Warn("[chat] failed to save tool message", "conv", gc.conversationID, "tool", toolName, "call_id", toolCallID, "error", err)
 		}
 		dbCancel()
-	} else if msg.Content != "" {
-		ss.contentBuilder.WriteString(msg.Content)
-		ss.addContentToSegments(msg.Content)
-		s.appendGenerationContent(gc.conversationID, gc.requestID, msg.Content)
-		gc.emit(EventChatChunk, ChatChunkEvent{
-			ChatEvent:        gc.chatEvent(ss.assistantMsg.ID),
-			Delta:            msg.Content,
-			RunPath:          ss.currentRunPath,
-			ParentToolCallID: ss.parentToolCallID(),
-		})
-		// Notify registered streaming sinks.
-		if cb, ok := s.chunkCallbacks.Load(gc.conversationID); ok {
-			cb.(ChunkCallback)(ss.contentBuilder.String())
+	} else {
+		// Providers that don't stream return reasoning in the same message as the content;
+		// record it first so the thinking segment precedes the answer, as when streaming.
+		if msg.ReasoningContent != "" {
+			ss.thinkingBuilder.WriteString(msg.ReasoningContent)
+			ss.addThinkingToSegments(msg.ReasoningContent)
+			gc.emit(EventChatThinking, ChatThinkingEvent{
+				ChatEvent:        gc.chatEvent(ss.assistantMsg.ID),
+				Delta:            msg.ReasoningContent,
+				RunPath:          ss.currentRunPath,
+				ParentToolCallID: ss.parentToolCallID(),
+			})
+		}
+		if msg.Content != "" {
+			ss.contentBuilder.WriteString(msg.Content)
+			ss.addContentToSegments(msg.Content)
+			s.appendGenerationContent(gc.conversationID, gc.requestID, msg.Content)
+			gc.emit(EventChatChunk, ChatChunkEvent{
+				ChatEvent:        gc.chatEvent(ss.assistantMsg.ID),
+				Delta:            msg.Content,
+				RunPath:          ss.currentRunPath,
+				ParentToolCallID: ss.parentToolCallID(),
+			})
+			// Notify registered streaming sinks.
+			if cb, ok := s.chunkCallbacks.Load(gc.conversationID); ok {
+				cb.(ChunkCallback)(ss.contentBuilder.String())
+			}
 		}
 	}
 
@@ -986,18 +1132,21 @@ func (s *ChatService) emitToolCallChunks(gc *generationContext, ss *streamState,
 			continue
 		}
 
-		var toolName, args string
-		for _, key := range ss.toolOrder {
-			st := ss.toolStatesByKey[key]
-			if st != nil && st.id == resolvedID {
-				toolName = st.name
-				args = st.args
-				break
+		st := ss.toolStatesByKey[ss.indexKeyMap[idx]]
+		if st == nil || st.id != resolvedID {
+			st = nil
+			for _, key := range ss.toolOrder {
+				if cand := ss.toolStatesByKey[key]; cand != nil && cand.id == resolvedID {
+					st = cand
+					break
+				}
 			}
 		}
-		if toolName == "" {
+		if st == nil || st.name == "" {
 			continue
 		}
+		toolName, args := st.name, st.args
+		resolvedID = st.callID()
 
 		if isHiddenTool(toolName) {
 			continue
@@ -1020,6 +1169,7 @@ func (s *ChatService) emitToolCallChunks(gc *generationContext, ss *streamState,
 			ToolCallID:       resolvedID,
 			ToolName:         toolName,
 			ArgsJSON:         args,
+			ArgsDelta:        st.takeArgsDelta(),
 			RunPath:          ss.currentRunPath,
 			ParentToolCallID: ss.parentToolCallID(),
 		})
@@ -1040,18 +1190,13 @@ func hasCapability(capabilities []string, capability string) bool {
 	return false
 }
 
-// getModelCapabilities retrieves model capabilities from database or builtin config
+// getModelCapabilities retrieves model capabilities from database or builtin config.
+// The models table wins because users can edit capabilities of any model; builtin
+// config is only a fallback (GetBuiltinModelCapabilities returns ["text"] when unknown).
 func getModelCapabilities(providerID, modelID string) []string {
-	// Try to get from builtin config first
-	capabilities := define.GetBuiltinModelCapabilities(providerID, modelID)
-	if len(capabilities) > 0 {
-		return capabilities
-	}
-
-	// Try to get from database
 	db := sqlite.DB()
 	if db == nil {
-		return []string{"text"}
+		return define.GetBuiltinModelCapabilities(providerID, modelID)
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
@@ -1074,8 +1219,52 @@ func getModelCapabilities(providerID, modelID string) []string {
 		}
 	}
 
-	// Return default text-only capability
-	return []string{"text"}
+	return define.GetBuiltinModelCapabilities(providerID, modelID)
+}
+
+// modelFlags holds the per-model feature switches stored in the models table.
+type modelFlags struct {
+	SupportsTools     bool
+	SupportsReasoning bool
+}
+
+// getModelFlags reads supports_tools / supports_reasoning for a model.
+// Models missing from the models table fall back to the builtin definition;
+// unknown custom models are assumed to support both, matching the column defaults.
+func getModelFlags(providerID, modelID string) modelFlags {
+	flags := modelFlags{SupportsTools: true, SupportsReasoning: true}
+	if m, ok := define.GetBuiltinModel(providerID, modelID); ok {
+		flags = modelFlags{SupportsTools: m.SupportsTools(), SupportsReasoning: m.SupportsReasoning}
+	}
+
+	db := sqlite.DB()
+	if db == nil {
+		return flags
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	// NULL = not set yet (see 202610151100_add_model_capability_flags): keep the default.
+	var row struct {
+		SupportsTools     sql.NullBool `bun:"supports_tools"`
+		SupportsReasoning sql.NullBool `bun:"supports_reasoning"`
+	}
+	if err := db.NewSelect().
+		Model(&row).
+		Table("models").
+		Where("provider_id = ?", providerID).
+		Where("model_id = ?", modelID).
+		Limit(1).
+		Scan(ctx); err == nil {
+		if row.SupportsTools.Valid {
+			flags.SupportsTools = row.SupportsTools.Bool
+		}
+		if row.SupportsReasoning.Valid {
+			flags.SupportsReasoning = row.SupportsReasoning.Bool
+		}
+	}
+	return flags
 }
 
 // supportsMultimodal checks if a model supports multimodal (vision) capabilities.
@@ -1142,6 +1331,7 @@ func supportsMultimodal(providerID, modelID string) bool {
 
 // loadMessagesForContext loads messages for agent/chat context.
 // contextCount: maximum number of messages to include (0 or >=200 means unlimited).
+// Messages at or before the conversation's context reset marker are skipped, except system messages.
 // providerID and modelID are used to check if the model supports multimodal capabilities.
 //
 // Tool-call repair (dangling tool calls without responses) is handled by the
@@ -1152,10 +1342,22 @@ func (s *ChatService) loadMessagesForContext(ctx context.Context, db *bun.DB, co
 
 	needLimit := contextCount > 0 && contextCount < 200
 
+	var resetAt int64
+	if err := db.NewSelect().
+		Table("conversations").
+		Column("context_reset_at_message_id").
+		Where("id = ?", conversationID).
+		Scan(ctx, &resetAt); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
 	q := db.NewSelect().
 		Model(&models).
 		Where("conversation_id = ?", conversationID).
 		Where("status IN (?)", bun.In([]string{StatusSuccess, StatusCancelled}))
+	if resetAt > 0 {
+		q = q.Where("(id > ? OR role = ?)", resetAt, RoleSystem)
+	}
 
 	if needLimit {
 		q = q.OrderExpr("created_at DESC, id DESC").Limit(contextCount)
@@ -1173,6 +1375,12 @@ func (s *ChatService) loadMessagesForContext(ctx context.Context, db *bun.DB, co
 		}
 	}
 
+	// Text-only models never receive image parts (SendMessage rejects new images for them,
+	// but history may contain images sent while another model was selected). The saved
+	// image paths are still listed as text so skills can open and recognize the files.
+	visionEnabled := supportsMultimodal(providerID, modelID)
+	toolResultLimit := toolResultMaxChars()
+
 	messages := make([]*schema.Message, 0, len(models))
 	for _, m := range models {
 		var role schema.RoleType
@@ -1202,34 +1410,37 @@ func (s *ChatService) loadMessagesForContext(ctx context.Context, db *bun.DB, co
 				}
 			}
 
-			// 不再根据模型能力过滤图片，因为不支持图片识别的模型可以通过调用技能去识别图片
-			// if !supportsMultimodal && len(images) > 0 {
-			// 	s.app.Logger.Info("[chat] filtering out images - model does not support multimodal", "msg_id", m.ID, "provider", providerID, "model", modelID, "image_count", len(images))
-			// 	images = nil
-			// }
+			// Inline document attachments are prepended to the text of the message they were sent with
+			content := m.Content
+			if m.AttachmentContext != "" {
+				content = m.AttachmentContext + "\n\n" + m.Content
+			}
+
+			attachments := decodeMessageAttachments(m.Attachments)
 
-			hasText := strings.TrimSpace(m.Content) != ""
-			if !hasText && len(images) == 0 {
+			hasText := strings.TrimSpace(content) != ""
+			if !hasText && len(images) == 0 && len(attachments) == 0 {
 				// Skip empty messages
 				continue
 			}
 
 			// Log only when images are actually passed (text-only messages are the common case)
-			if len(images) > 0 {
-				s.app.Logger.Info("[chat] passing images to model", "msg_id", m.ID, "image_count", len(images))
+			if len(images) > 0 || len(attachments) > 0 {
+				s.app.Logger.Info("[chat] passing images to model", "msg_id", m.ID, "image_count", len(images)+len(attachments))
 			}
 
 			// If there are images, use multi-content form
-			if len(images) > 0 {
+			if len(images) > 0 || len(attachments) > 0 {
 				var parts []schema.MessageInputPart
 
 				var imageRefs []string
 				var fileRefs []string
+				omittedImages := 0
 
 				if hasText {
 					parts = append(parts, schema.MessageInputPart{
 						Type: schema.ChatMessagePartTypeText,
-						Text: m.Content,
+						Text: content,
 					})
 				}
 
@@ -1248,6 +1459,10 @@ func (s *ChatService) loadMessagesForContext(ctx context.Context, db *bun.DB, co
 					// Handle local file images
 					if img.Source == "local_file" && img.FilePath != "" {
 						imageRefs = append(imageRefs, img.FilePath)
+						if !visionEnabled {
+							omittedImages++
+							continue
+						}
 
 						data, err := os.ReadFile(img.FilePath)
 						if err != nil {
@@ -1272,6 +1487,10 @@ func (s *ChatService) loadMessagesForContext(ctx context.Context, db *bun.DB, co
 					if img.Source != "inline_base64" || img.Base64 == "" || img.MimeType == "" {
 						continue
 					}
+					if !visionEnabled {
+						omittedImages++
+						continue
+					}
 					base64Data := img.Base64
 					parts = append(parts, schema.MessageInputPart{
 						Type: schema.ChatMessagePartTypeImageURL,
@@ -1284,6 +1503,22 @@ func (s *ChatService) loadMessagesForContext(ctx context.Context, db *bun.DB, co
 					})
 				}
 
+				for _, att := range attachments {
+					if att.Path != "" {
+						imageRefs = append(imageRefs, att.Path)
+					}
+					if !visionEnabled {
+						omittedImages++
+						continue
+					}
+					part, err := att.imagePart()
+					if err != nil {
+						s.app.Logger.Warn("[chat] failed to load image attachment", "msg_id", m.ID, "path", att.Path, "error", err)
+						continue
+					}
+					parts = append(parts, part)
+				}
+
 				// Add image file path references as a text part for skills
 				if len(imageRefs) > 0 {
 					refText := "\n\n[Attached Images]\n" + strings.Join(imageRefs, "\n")
@@ -1293,6 +1528,14 @@ func (s *ChatService) loadMessagesForContext(ctx context.Context, db *bun.DB, co
 					})
 				}
 
+				// Tell text-only models that the user attached images they cannot see
+				if omittedImages > 0 {
+					parts = append(parts, schema.MessageInputPart{
+						Type: schema.ChatMessagePartTypeText,
+						Text: fmt.Sprintf("\n\n[%d image(s) attached, but the current model does not support image input]", omittedImages),
+					})
+				}
+
 				// Add file path references as a text part so skills can locate and open files
 				if len(fileRefs) > 0 {
 					refText := "\n\n[Attached Files]\n" + strings.Join(fileRefs, "\n")
@@ -1305,11 +1548,11 @@ func (s *ChatService) loadMessagesForContext(ctx context.Context, db *bun.DB, co
 				if len(parts) > 0 {
 					msg.UserInputMultiContent = parts
 				} else {
-					msg.Content = m.Content
+					msg.Content = content
 				}
 			} else {
 				// No images, use simple content
-				msg.Content = m.Content
+				msg.Content = content
 			}
 		} else {
 			// Non-user messages: use simple content
@@ -1319,6 +1562,8 @@ func (s *ChatService) loadMessagesForContext(ctx context.Context, db *bun.DB, co
 		if m.Role == RoleTool {
 			msg.ToolCallID = m.ToolCallID
 			msg.Name = m.ToolCallName
+			// Also applies to results saved before the limit was introduced or lowered
+			msg.Content, _ = truncateToolResult(msg.Content, toolResultLimit)
 		}
 
 		if m.Role == RoleAssistant && m.ToolCalls != "" && m.ToolCalls != "[]" {
@@ -0,0 +1,123 @@
+package chat
+
+import (
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+func toolChunk(index int, id, name, args string) []schema.ToolCall {
+	return []schema.ToolCall{{
+		Index:    &index,
+		ID:       id,
+		Function: schema.FunctionCall{Name: name, Arguments: args},
+	}}
+}
+
+func TestUpdateToolStatesMergesDeltas(t *testing.T) {
+	ss := newStreamState(nil, nil)
+	// OpenAI-style stream: ID and name only on the first chunk of each index.
+	for _, chunk := range [][]schema.ToolCall{
+		toolChunk(0, "call_a", "read_file", ""),
+		toolChunk(0, "", "", `{"path":`),
+		toolChunk(0, "", "", `"a.txt"}`),
+		toolChunk(1, "call_b", "ls", `{}`),
+	} {
+		ss.updateToolStates(chunk)
+	}
+
+	got := ss.buildToolCallsForDB()
+	if len(got) != 2 {
+		t.Fatalf("got %d tool calls, want 2: %#v", len(got), got)
+	}
+	if got[0].ID != "call_a" || got[0].Function.Arguments != `{"path":"a.txt"}` {
+		t.Errorf("first call = %#v", got[0])
+	}
+	if got[1].ID != "call_b" || got[1].Function.Name != "ls" {
+		t.Errorf("second call = %#v", got[1])
+	}
+}
+
+func TestUpdateToolStatesRepeatedIDOnEveryDelta(t *testing.T) {
+	ss := newStreamState(nil, nil)
+	// Some providers repeat the ID (and name) on every delta of the same index.
+	for _, chunk := range [][]schema.ToolCall{
+		toolChunk(0, "call_a", "read_file", `{"path":`),
+		toolChunk(0, "call_a", "read_file", `"a.txt"}`),
+	} {
+		ss.updateToolStates(chunk)
+	}
+
+	got := ss.buildToolCallsForDB()
+	if len(got) != 1 || got[0].Function.Arguments != `{"path":"a.txt"}` {
+		t.Fatalf("got %#v, want one merged call", got)
+	}
+}
+
+func TestUpdateToolStatesDuplicateIDAcrossIndices(t *testing.T) {
+	ss := newStreamState(nil, nil)
+	// Captured from a proxy that stamps every parallel call with the same ID.
+	for _, chunk := range [][]schema.ToolCall{
+		toolChunk(0, "call_0", "read_file", ""),
+		toolChunk(0, "", "", `{"path":"a.txt"}`),
+		toolChunk(1, "call_0", "read_file", ""),
+		toolChunk(1, "", "", `{"path":"b.txt"}`),
+	} {
+		ss.updateToolStates(chunk)
+	}
+
+	got := ss.buildToolCallsForDB()
+	if len(got) != 2 {
+		t.Fatalf("got %d tool calls, want 2: %#v", len(got), got)
+	}
+	if got[0].Function.Arguments != `{"path":"a.txt"}` {
+		t.Errorf("first call args = %q", got[0].Function.Arguments)
+	}
+	if got[1].Function.Name != "read_file" || got[1].Function.Arguments != `{"path":"b.txt"}` {
+		t.Errorf("second call = %#v", got[1])
+	}
+}
+
+func TestDuplicateIDCallsGetUniqueIDsAndResults(t *testing.T) {
+	ss := newStreamState(nil, nil)
+	for _, chunk := range [][]schema.ToolCall{
+		toolChunk(0, "call_0", "read_file", `{"path":"a.txt"}`),
+		toolChunk(1, "call_0", "read_file", `{"path":"b.txt"}`),
+	} {
+		ss.updateToolStates(chunk)
+	}
+
+	got := ss.buildToolCallsForDB()
+	if len(got) != 2 || got[0].ID != "call_0" || got[1].ID != "call_0#1" {
+		t.Fatalf("got %#v, want IDs call_0 and call_0#1", got)
+	}
+	// Both results come back with the reused ID; they pair with the calls in order.
+	for _, want := range []string{"call_0", "call_0#1"} {
+		if id, name := ss.resolveToolResult("call_0"); id != want || name != "read_file" {
+			t.Errorf("resolveToolResult = (%q, %q), want (%q, read_file)", id, name, want)
+		}
+	}
+	if id, name := ss.resolveToolResult("call_x"); id != "call_x" || name != "" {
+		t.Errorf("unknown call = (%q, %q)", id, name)
+	}
+}
+
+func TestToolCallArgsDelta(t *testing.T) {
+	ss := newStreamState(nil, nil)
+	var deltas []string
+	for _, chunk := range [][]schema.ToolCall{
+		toolChunk(0, "call_a", "read_file", `{"pa`),
+		toolChunk(0, "", "", `th":"a.txt"}`),
+		toolChunk(0, "", "", ""),
+	} {
+		ss.updateToolStates(chunk)
+		deltas = append(deltas, ss.toolStatesByKey["call_a"].takeArgsDelta())
+	}
+
+	want := []string{`{"pa`, `th":"a.txt"}`, ""}
+	for i := range want {
+		if deltas[i] != want[i] {
+			t.Errorf("delta %d = %q, want %q", i, deltas[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package chat
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+
+	"chatclaw/internal/errs"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// Limits shared by SendMessageInput.Images and SendMessageInput.Attachments
+const (
+	maxMessageImages            = 4
+	maxMessageImageSize   int64 = 2 * 1024 * 1024 // 2MB per image
+	maxMessageImagesTotal int64 = 8 * 1024 * 1024 // 8MB total images
+)
+
+// MessageAttachment is an image attached to a message by local path or data URI, stored in
+// messages.attachments. Exactly one of Path and DataURI is set.
+type MessageAttachment struct {
+	Path     string `json:"path,omitempty"`      // local image file
+	DataURI  string `json:"data_uri,omitempty"`  // "data:<mime>;base64,<data>"
+	MimeType string `json:"mime_type,omitempty"` // derived from Path / DataURI when empty
+	Size     int64  `json:"size,omitempty"`      // set by the backend
+}
+
+// normalizeMessageAttachments validates attachments sent with a message and fills in
+// MimeType and Size. checkPath resolves and authorizes local paths (see attachmentPathChecker).
+func normalizeMessageAttachments(atts []MessageAttachment, checkPath func(string) (string, error)) ([]MessageAttachment, error) {
+	out := make([]MessageAttachment, 0, len(atts))
+	for _, att := range atts {
+		att.Path = strings.TrimSpace(att.Path)
+		att.DataURI = strings.TrimSpace(att.DataURI)
+		switch {
+		case att.Path != "" && att.DataURI == "":
+			p, err := checkPath(att.Path)
+			if err != nil {
+				return nil, err
+			}
+			info, err := os.Stat(p)
+			if err != nil || info.IsDir() {
+				return nil, errs.Newf("error.chat_attachment_file_not_found", map[string]any{"Path": p})
+			}
+			att.Path = p
+			att.Size = info.Size()
+			att.MimeType = guessOpenClawAttachmentMime(p, att.MimeType)
+		case att.DataURI != "" && att.Path == "":
+			mimeType, data, ok := parseDataURL(att.DataURI)
+			if !ok {
+				return nil, errs.New("error.chat_image_base64_required")
+			}
+			decoded, err := base64.StdEncoding.DecodeString(data)
+			if err != nil {
+				return nil, errs.New("error.chat_image_base64_required")
+			}
+			att.Size = int64(len(decoded))
+			if att.MimeType == "" {
+				att.MimeType = mimeType
+			}
+		default:
+			return nil, errs.New("error.chat_image_base64_required")
+		}
+
+		att.MimeType = strings.ToLower(strings.TrimSpace(att.MimeType))
+		if !strings.HasPrefix(att.MimeType, "image/") {
+			return nil, errs.New("error.chat_invalid_image_type")
+		}
+		if att.Size > maxMessageImageSize {
+			return nil, errs.New("error.chat_image_too_large")
+		}
+		out = append(out, att)
+	}
+	return out, nil
+}
+
+// parseDataURL splits "data:<mime>;base64,<data>" into its mime type and base64 payload.
+func parseDataURL(dataURL string) (mimeType, data string, ok bool) {
+	rest, found := strings.CutPrefix(strings.TrimSpace(dataURL), "data:")
+	if !found {
+		return "", "", false
+	}
+	meta, data, found := strings.Cut(rest, ",")
+	if !found || data == "" {
+		return "", "", false
+	}
+	mimeType, found = strings.CutSuffix(meta, ";base64")
+	if !found {
+		return "", "", false
+	}
+	return mimeType, data, true
+}
+
+// decodeMessageAttachments parses messages.attachments; malformed data yields no attachments.
+func decodeMessageAttachments(raw string) []MessageAttachment {
+	if strings.TrimSpace(raw) == "" || raw == "[]" {
+		return nil
+	}
+	var atts []MessageAttachment
+	if err := json.Unmarshal([]byte(raw), &atts); err != nil {
+		return nil
+	}
+	return atts
+}
+
+// imagePart loads the attachment as an image part for vision models.
+func (a MessageAttachment) imagePart() (schema.MessageInputPart, error) {
+	var data string
+	if a.Path != "" {
+		b, err := os.ReadFile(a.Path)
+		if err != nil {
+			return schema.MessageInputPart{}, err
+		}
+		data = base64.StdEncoding.EncodeToString(b)
+	} else {
+		_, payload, ok := parseDataURL(a.DataURI)
+		if !ok {
+			return schema.MessageInputPart{}, errors.New("invalid data URI")
+		}
+		data = payload
+	}
+	return schema.MessageInputPart{
+		Type: schema.ChatMessagePartTypeImageURL,
+		Image: &schema.MessageInputImage{
+			MessagePartCommon: schema.MessagePartCommon{
+				Base64Data: &data,
+				MIMEType:   a.MimeType,
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,44 @@
+package chat
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNormalizeMessageAttachments(t *testing.T) {
+	dir := t.TempDir()
+	pngPath := filepath.Join(dir, "shot.png")
+	if err := os.WriteFile(pngPath, []byte("png-bytes"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	checkPath := func(p string) (string, error) { return p, nil }
+
+	got, err := normalizeMessageAttachments([]MessageAttachment{
+		{DataURI: "data:image/png;base64,aGVsbG8="},
+		{Path: pngPath},
+	}, checkPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got[0].MimeType != "image/png" || got[0].Size != 5 {
+		t.Errorf("data URI attachment = %+v", got[0])
+	}
+	if got[1].MimeType != "image/png" || got[1].Size != int64(len("png-bytes")) {
+		t.Errorf("path attachment = %+v", got[1])
+	}
+
+	for name, att := range map[string]MessageAttachment{
+		"empty":          {},
+		"both":           {Path: pngPath, DataURI: "data:image/png;base64,aGVsbG8="},
+		"not base64":     {DataURI: "data:image/png,hello"},
+		"bad payload":    {DataURI: "data:image/png;base64,!!"},
+		"not an image":   {DataURI: "data:application/pdf;base64,aGVsbG8="},
+		"missing file":   {Path: filepath.Join(dir, "gone.png")},
+		"directory path": {Path: dir},
+	} {
+		if _, err := normalizeMessageAttachments([]MessageAttachment{att}, checkPath); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
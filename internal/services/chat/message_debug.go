@@ -0,0 +1,108 @@
+package chat
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"chatclaw/internal/errs"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/uptrace/bun"
+)
+
+// MessageDebug is the raw persisted data of a single assistant turn, for debugging agent behavior.
+type MessageDebug struct {
+	MessageID      int64     `json:"message_id"`
+	ConversationID int64     `json:"conversation_id"`
+	Role           string    `json:"role"`
+	ProviderID     string    `json:"provider_id"`
+	ModelID        string    `json:"model_id"`
+	Status         string    `json:"status"`
+	Error          string    `json:"error,omitempty"`
+	FinishReason   string    `json:"finish_reason"`
+	InputTokens    int       `json:"input_tokens"`
+	OutputTokens   int       `json:"output_tokens"`
+	ToolCalls      string    `json:"tool_calls"` // stored JSON of []schema.ToolCall, as written by updateMessageFinal
+	Segments       string    `json:"segments"`   // stored JSON of the interleaved content/tool-call segments
+	ToolResults    []Message `json:"tool_results"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// GetMessageDebug returns the stored tool calls, segments and matching tool results of a message.
+// Tool results are the tool-role messages of the same conversation whose tool_call_id appears in
+// the message's tool_calls; their FullContent holds the untruncated result when one was cut.
+func (s *ChatService) GetMessageDebug(messageID int64) (*MessageDebug, error) {
+	if messageID <= 0 {
+		return nil, errs.New("error.chat_message_not_found")
+	}
+
+	db, err := s.db()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var msg messageModel
+	if err := db.NewSelect().Model(&msg).Where("id = ?", messageID).Limit(1).Scan(ctx); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errs.New("error.chat_message_not_found")
+		}
+		return nil, errs.Wrap("error.chat_message_read_failed", err)
+	}
+
+	debug := &MessageDebug{
+		MessageID:      msg.ID,
+		ConversationID: msg.ConversationID,
+		Role:           msg.Role,
+		ProviderID:     msg.ProviderID,
+		ModelID:        msg.ModelID,
+		Status:         msg.Status,
+		Error:          msg.Error,
+		FinishReason:   msg.FinishReason,
+		InputTokens:    msg.InputTokens,
+		OutputTokens:   msg.OutputTokens,
+		ToolCalls:      msg.ToolCalls,
+		Segments:       msg.Segments,
+		ToolResults:    []Message{},
+		CreatedAt:      msg.CreatedAt,
+		UpdatedAt:      msg.UpdatedAt,
+	}
+
+	var toolCalls []schema.ToolCall
+	if msg.ToolCalls != "" && msg.ToolCalls != "[]" {
+		if err := json.Unmarshal([]byte(msg.ToolCalls), &toolCalls); err != nil {
+			// Keep the raw string; a mangled tool_calls column is exactly what this is for
+			s.app.Logger.Warn("[chat] message debug: failed to parse tool_calls", "msg", msg.ID, "error", err)
+		}
+	}
+	ids := make([]string, 0, len(toolCalls))
+	for _, tc := range toolCalls {
+		if tc.ID != "" {
+			ids = append(ids, tc.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return debug, nil
+	}
+
+	var results []messageModel
+	if err := db.NewSelect().
+		Model(&results).
+		Where("conversation_id = ?", msg.ConversationID).
+		Where("role = ?", RoleTool).
+		Where("tool_call_id IN (?)", bun.In(ids)).
+		OrderExpr("id ASC").
+		Scan(ctx); err != nil {
+		return nil, errs.Wrap("error.chat_messages_failed", err)
+	}
+	for i := range results {
+		debug.ToolResults = append(debug.ToolResults, results[i].toDTO())
+	}
+	return debug, nil
+}
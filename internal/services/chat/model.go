@@ -34,14 +34,16 @@ type ImagePayload struct {
 	Kind         string `json:"kind"`                   // "image" or "file"
 	Source       string `json:"source"`                 // "inline_base64" or "local_file"
 	MimeType     string `json:"mime_type"`
-	Base64       string `json:"base64"`                 // without "data:" prefix
+	Base64       string `json:"base64"`                 // without "data:" prefix; may be empty when FilePath is set
 	DataURL      string `json:"data_url,omitempty"`     // optional convenience field for frontend
 	Width        int    `json:"width,omitempty"`
 	Height       int    `json:"height,omitempty"`
 	FileName     string `json:"file_name,omitempty"`
-	FilePath     string `json:"file_path,omitempty"`     // local file path when saved to work dir
+	FilePath     string `json:"file_path,omitempty"`     // local file path (saved to work dir, or supplied by the caller instead of Base64)
 	Size         int64  `json:"size,omitempty"`
 	OriginalName string `json:"original_name,omitempty"` // user's original filename (preserved for display)
+	Inline       bool   `json:"inline,omitempty"`        // kind=file only: parse the document and inject its text into this message's context
+	Truncated    bool   `json:"truncated,omitempty"`     // set by the backend when the injected document text was cut off
 }
 
 // Message DTO (exposed to frontend)
@@ -57,14 +59,26 @@ type Message struct {
 	InputTokens     int       `json:"input_tokens"`
 	OutputTokens    int       `json:"output_tokens"`
 	FinishReason    string    `json:"finish_reason,omitempty"`
+	StopReason      string    `json:"stop_reason,omitempty"`  // normalized FinishReason, see NormalizeStopReason
+	CanContinue     bool      `json:"can_continue,omitempty"` // reply hit the length limit; ContinueGeneration can resume it
 	ToolCalls       string    `json:"tool_calls,omitempty"`
 	ToolCallID      string    `json:"tool_call_id,omitempty"`
 	ToolCallName    string    `json:"tool_call_name,omitempty"`
 	ThinkingContent string    `json:"thinking_content,omitempty"`
-	Segments        string    `json:"segments,omitempty"`    // JSON array for interleaved content/tool-call order
-	ImagesJSON      string    `json:"images_json,omitempty"` // raw JSON string of []ImagePayload
+	Segments        string    `json:"segments,omitempty"`     // JSON array for interleaved content/tool-call order
+	Citations       string    `json:"citations,omitempty"`    // JSON array of deduplicated Citation (assistant replies)
+	ImagesJSON      string    `json:"images_json,omitempty"`  // raw JSON string of []ImagePayload
+	FullContent     string    `json:"full_content,omitempty"` // tool messages: untruncated result when Content was cut for the model context
 	CreatedAt       time.Time `json:"created_at"`
 	UpdatedAt       time.Time `json:"updated_at"`
+
+	// Images attached by path or data URI (SendMessageInput.Attachments)
+	Attachments []MessageAttachment `json:"attachments,omitempty"`
+
+	// User feedback on assistant replies (see RateMessage)
+	Rating       int        `json:"rating"` // 1 thumbs up, -1 thumbs down, 0 not rated
+	FeedbackNote string     `json:"feedback_note,omitempty"`
+	RatedAt      *time.Time `json:"rated_at,omitempty"`
 }
 
 // SendMessageInput input for sending a message
@@ -73,6 +87,14 @@ type SendMessageInput struct {
 	Content        string         `json:"content"`
 	TabID          string         `json:"tab_id"`
 	Images         []ImagePayload `json:"images,omitempty"` // from frontend (base64)
+	// Attachments are images given by local path or data URI; they count towards the image limits
+	Attachments []MessageAttachment `json:"attachments,omitempty"`
+	// ReferenceFiles are local files searched only while answering this message: they are parsed
+	// and embedded into an in-memory index instead of being added to a library.
+	ReferenceFiles []string `json:"reference_files,omitempty"`
+	// FallbackToAgentModel clears a pinned model that was deleted or disabled and answers with the
+	// agent's default model instead of failing with error.chat_model_unavailable.
+	FallbackToAgentModel bool `json:"fallback_to_agent_model,omitempty"`
 }
 
 // EditAndResendInput input for editing and resending a message
@@ -82,12 +104,14 @@ type EditAndResendInput struct {
 	NewContent     string         `json:"new_content"`
 	TabID          string         `json:"tab_id"`
 	Images         []ImagePayload `json:"images,omitempty"` // images to attach (for resending with new images)
+	Archive        *bool          `json:"archive,omitempty"` // archive the replaced messages instead of deleting; nil = chat_archive_on_edit setting
 }
 
 // SendMessageResult result of sending a message
 type SendMessageResult struct {
 	RequestID string `json:"request_id"`
 	MessageID int64  `json:"message_id"`
+	EditID    string `json:"edit_id,omitempty"` // EditAndResend with archive: pass to UndoLastEdit
 }
 
 // messageModel database model for messages
@@ -112,7 +136,17 @@ type messageModel struct {
 	ToolCallName    string    `bun:"tool_call_name,notnull"`
 	ThinkingContent string    `bun:"thinking_content,notnull"`
 	Segments        string    `bun:"segments,notnull"`
+	Citations       string    `bun:"citations,notnull"`
 	ImagesJSON      string    `bun:"images_json,notnull"`
+	Attachments     string    `bun:"attachments,notnull"` // JSON []MessageAttachment
+	// AttachmentContext holds text extracted from inline document attachments (not exposed to the frontend).
+	AttachmentContext string `bun:"attachment_context,notnull"`
+	// FullContent keeps the untruncated tool result (display only) when Content was truncated.
+	FullContent string `bun:"full_content,notnull"`
+	// Rating is the user's feedback on an assistant reply: nil (not rated), 1 or -1.
+	Rating       *int       `bun:"rating"`
+	FeedbackNote string     `bun:"feedback_note,notnull"`
+	RatedAt      *time.Time `bun:"rated_at"`
 }
 
 var _ bun.BeforeInsertHook = (*messageModel)(nil)
@@ -144,14 +178,23 @@ func (m *messageModel) toDTO() Message {
 		InputTokens:     m.InputTokens,
 		OutputTokens:    m.OutputTokens,
 		FinishReason:    m.FinishReason,
+		StopReason:      NormalizeStopReason(m.FinishReason),
+		CanContinue:     canContinueReply(m.Role, m.Status, m.FinishReason),
 		ToolCalls:       m.ToolCalls,
 		ToolCallID:      m.ToolCallID,
 		ToolCallName:    m.ToolCallName,
 		ThinkingContent: m.ThinkingContent,
 		Segments:        m.Segments,
+		Citations:       m.Citations,
 		ImagesJSON:      m.ImagesJSON,
+		Attachments:     decodeMessageAttachments(m.Attachments),
+		FullContent:     m.FullContent,
 		CreatedAt:       m.CreatedAt,
 		UpdatedAt:       m.UpdatedAt,
+
+		Rating:       ratingValue(m.Rating),
+		FeedbackNote: m.FeedbackNote,
+		RatedAt:      m.RatedAt,
 	}
 }
 
@@ -194,7 +237,8 @@ type ChatToolEvent struct {
 	Type             string   `json:"type"` // "call" or "result"
 	ToolCallID       string   `json:"tool_call_id"`
 	ToolName         string   `json:"tool_name"`
-	ArgsJSON         string   `json:"args_json,omitempty"`
+	ArgsJSON         string   `json:"args_json,omitempty"`  // accumulated arguments so far; complete on the last "call" event
+	ArgsDelta        string   `json:"args_delta,omitempty"` // arguments added since the previous "call" event of this tool call
 	ResultJSON       string   `json:"result_json,omitempty"`
 	RunPath          []string `json:"run_path,omitempty"`
 	ParentToolCallID string   `json:"parent_tool_call_id,omitempty"`
@@ -205,6 +249,21 @@ type ChatCompleteEvent struct {
 	ChatEvent
 	Status       string `json:"status"`
 	FinishReason string `json:"finish_reason"`
+	StopReason   string `json:"stop_reason"`            // normalized FinishReason
+	CanContinue  bool   `json:"can_continue,omitempty"` // set when StopReason is "length"; offer ContinueGeneration
+
+	Citations []Citation `json:"citations,omitempty"` // deduplicated knowledge sources, numbered for footnotes
+}
+
+// newChatCompleteEvent builds a ChatCompleteEvent with the normalized stop reason filled in.
+func newChatCompleteEvent(ev ChatEvent, status, finishReason string) ChatCompleteEvent {
+	return ChatCompleteEvent{
+		ChatEvent:    ev,
+		Status:       status,
+		FinishReason: finishReason,
+		StopReason:   NormalizeStopReason(finishReason),
+		CanContinue:  canContinueReply(RoleAssistant, status, finishReason),
+	}
 }
 
 // ChatStoppedEvent event sent when generation is stopped
@@ -237,8 +296,18 @@ type ChatRetrievalEvent struct {
 // ChatUserMessageEvent event sent when a user message is inserted (for external callers like MCP).
 type ChatUserMessageEvent struct {
 	ChatEvent
-	Content    string `json:"content"`
-	ImagesJSON string `json:"images_json,omitempty"`
+	Content     string              `json:"content"`
+	ImagesJSON  string              `json:"images_json,omitempty"`
+	Attachments []MessageAttachment `json:"attachments,omitempty"`
+}
+
+// ChatAttachmentEvent event sent while inline document attachments are parsed before generation.
+type ChatAttachmentEvent struct {
+	ChatEvent
+	Index    int    `json:"index"`
+	Total    int    `json:"total"`
+	FileName string `json:"file_name"`
+	Status   string `json:"status"` // parsing | done | failed
 }
 
 // Event names
@@ -252,4 +321,5 @@ const (
 	EventChatStopped     = "chat:stopped"
 	EventChatError       = "chat:error"
 	EventChatUserMessage = "chat:user-message"
+	EventChatAttachment  = "chat:attachment"
 )
@@ -0,0 +1,88 @@
+package chat
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"chatclaw/internal/errs"
+	"chatclaw/internal/services/conversations"
+
+	"github.com/uptrace/bun"
+)
+
+// ensureConversationModelAvailable checks, before anything is written, that the model pinned on
+// the conversation still exists and is enabled. A stale pin returns error.chat_model_unavailable
+// (with the provider/model id) so the UI can ask the user to pick another model. With fallback
+// set, the pin is cleared instead and the conversation uses the agent's default model.
+func (s *ChatService) ensureConversationModelAvailable(ctx context.Context, db *bun.DB, conversationID int64, fallback bool) error {
+	var conv struct {
+		AgentID       int64  `bun:"agent_id"`
+		AgentType     string `bun:"agent_type"`
+		LLMProviderID string `bun:"llm_provider_id"`
+		LLMModelID    string `bun:"llm_model_id"`
+	}
+	if err := db.NewSelect().
+		Table("conversations").
+		Column("agent_id", "agent_type", "llm_provider_id", "llm_model_id").
+		Where("id = ?", conversationID).
+		Scan(ctx, &conv); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errs.New("error.chat_conversation_not_found")
+		}
+		return errs.Wrap("error.chat_conversation_read_failed", err)
+	}
+	// No pinned model: getAgentAndProviderConfig resolves the agent default.
+	if conv.LLMProviderID == "" || conv.LLMModelID == "" {
+		return nil
+	}
+
+	usable, err := conversations.LLMModelUsable(ctx, db, conv.LLMProviderID, conv.LLMModelID)
+	if err != nil {
+		return errs.Wrap("error.chat_conversation_read_failed", err)
+	}
+	if usable {
+		return nil
+	}
+	unavailable := errs.Newf("error.chat_model_unavailable", map[string]any{
+		"ProviderID": conv.LLMProviderID,
+		"ModelID":    conv.LLMModelID,
+	})
+	if !fallback {
+		return unavailable
+	}
+
+	agentTable := "agents"
+	if conv.AgentType == conversations.AgentTypeOpenClaw {
+		agentTable = "openclaw_agents"
+	}
+	var agent struct {
+		ProviderID string `bun:"default_llm_provider_id"`
+		ModelID    string `bun:"default_llm_model_id"`
+	}
+	if err := db.NewSelect().
+		Table(agentTable).
+		Column("default_llm_provider_id", "default_llm_model_id").
+		Where("id = ?", conv.AgentID).
+		Scan(ctx, &agent); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return errs.Wrap("error.chat_agent_read_failed", err)
+	}
+	if ok, err := conversations.LLMModelUsable(ctx, db, agent.ProviderID, agent.ModelID); err != nil {
+		return errs.Wrap("error.chat_agent_read_failed", err)
+	} else if !ok {
+		return unavailable
+	}
+
+	if _, err := db.NewUpdate().
+		Table("conversations").
+		Set("llm_provider_id = ''").
+		Set("llm_model_id = ''").
+		Where("id = ?", conversationID).
+		Exec(ctx); err != nil {
+		return errs.Wrap("error.conversation_update_failed", err)
+	}
+	s.app.Logger.Warn("[chat] conversation model unavailable, fallback to agent default",
+		"conv", conversationID, "stale_provider", conv.LLMProviderID, "stale_model", conv.LLMModelID,
+		"provider", agent.ProviderID, "model", agent.ModelID)
+	return nil
+}
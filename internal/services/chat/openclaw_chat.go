@@ -533,6 +533,9 @@ func (s *ChatService) getOpenClawAgentConfig(conversationID int64) (openClawAgen
 	}
 	if providerID != "" && modelID != "" {
 		cfg.Capabilities = getModelCapabilities(providerID, modelID)
+		if cfg.EnableThinking && !getModelFlags(providerID, modelID).SupportsReasoning {
+			cfg.EnableThinking = false
+		}
 	}
 
 	if conv.LibraryIDs != "" {
@@ -1519,6 +1522,9 @@ func (s *ChatService) SendOpenClawMessage(input SendMessageInput) (*SendMessageR
 	if input.ConversationID <= 0 {
 		return nil, errs.New("error.chat_conversation_id_required")
 	}
+	if s.shuttingDown.Load() {
+		return nil, errs.New("error.chat_shutting_down")
+	}
 	content := strings.TrimSpace(input.Content)
 	if content == "" && len(input.Images) == 0 {
 		return nil, errs.New("error.chat_content_required")
@@ -1604,6 +1610,9 @@ func (s *ChatService) EditAndResendOpenClaw(input EditAndResendInput) (*SendMess
 	if input.ConversationID <= 0 {
 		return nil, errs.New("error.chat_conversation_id_required")
 	}
+	if s.shuttingDown.Load() {
+		return nil, errs.New("error.chat_shutting_down")
+	}
 	content := strings.TrimSpace(input.NewContent)
 	if content == "" && len(input.Images) == 0 {
 		return nil, errs.New("error.chat_content_required")
@@ -2710,9 +2719,5 @@ func (s *ChatService) runOpenClawChatRun(ctx context.Context, conversationID int
 		rid = runResult.RunID
 	}
 	s.logOpenClawRawStreamSummary(conversationID, rid)
-	emit(EventChatComplete, ChatCompleteEvent{
-		ChatEvent:    ce(),
-		Status:       StatusSuccess,
-		FinishReason: st.finishReason,
-	})
+	emit(EventChatComplete, newChatCompleteEvent(ce(), StatusSuccess, st.finishReason))
 }
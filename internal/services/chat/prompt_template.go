@@ -0,0 +1,92 @@
+package chat
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"chatclaw/internal/services/settings"
+
+	"github.com/uptrace/bun"
+)
+
+// promptVarPattern matches a bare {{name}} placeholder (optionally padded with spaces).
+var promptVarPattern = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+// promptTemplateVars are the values an agent system prompt can reference as {{date}},
+// {{user_name}}, {{library_names}} and {{model_id}}.
+type promptTemplateVars struct {
+	Date         string
+	UserName     string
+	LibraryNames string
+	ModelID      string
+}
+
+func (v promptTemplateVars) lookup(name string) (string, bool) {
+	switch name {
+	case "date":
+		return v.Date, true
+	case "user_name":
+		return v.UserName, true
+	case "library_names":
+		return v.LibraryNames, true
+	case "model_id":
+		return v.ModelID, true
+	}
+	return "", false
+}
+
+// expandPromptTemplate replaces the known {{name}} variables in prompt. Nothing is executed as a
+// template: unknown placeholders and any other brace text ({{.Name}}, {{ if }}, …) stay verbatim.
+func expandPromptTemplate(prompt string, vars promptTemplateVars) string {
+	if !strings.Contains(prompt, "{{") {
+		return prompt
+	}
+	return promptVarPattern.ReplaceAllStringFunc(prompt, func(m string) string {
+		if value, ok := vars.lookup(promptVarPattern.FindStringSubmatch(m)[1]); ok {
+			return value
+		}
+		return m
+	})
+}
+
+// loadPromptTemplateVars collects the template values for a conversation.
+// Library names follow the order of libraryIDs; libraries that no longer exist are skipped.
+func loadPromptTemplateVars(ctx context.Context, db *bun.DB, libraryIDs []int64, modelID string) (promptTemplateVars, error) {
+	vars := promptTemplateVars{
+		Date:    time.Now().Format("2006-01-02"),
+		ModelID: modelID,
+	}
+	if v, ok := settings.GetValue("user_name"); ok {
+		vars.UserName = strings.TrimSpace(v)
+	}
+
+	if len(libraryIDs) == 0 {
+		return vars, nil
+	}
+	type libraryRow struct {
+		ID   int64  `bun:"id"`
+		Name string `bun:"name"`
+	}
+	var rows []libraryRow
+	if err := db.NewSelect().
+		Table("library").
+		Column("id", "name").
+		Where("id IN (?)", bun.In(libraryIDs)).
+		Scan(ctx, &rows); err != nil {
+		return vars, err
+	}
+	names := make(map[int64]string, len(rows))
+	for _, r := range rows {
+		names[r.ID] = r.Name
+	}
+	ordered := make([]string, 0, len(libraryIDs))
+	for _, id := range libraryIDs {
+		if name, ok := names[id]; ok {
+			ordered = append(ordered, name)
+		}
+	}
+	vars.LibraryNames = strings.Join(ordered, ", ")
+	return vars, nil
+}
@@ -0,0 +1,23 @@
+package chat
+
+import "testing"
+
+func TestExpandPromptTemplate(t *testing.T) {
+	vars := promptTemplateVars{Date: "2026-10-16", UserName: "Ada", LibraryNames: "Docs, FAQ", ModelID: "gpt-4o"}
+	cases := []struct {
+		name, prompt, want string
+	}{
+		{"known vars", "Today is {{date}}, user {{ user_name }} on {{model_id}} with {{library_names}}.",
+			"Today is 2026-10-16, user Ada on gpt-4o with Docs, FAQ."},
+		{"unknown bare placeholder", "Hi {{name}} at {{date}}", "Hi {{name}} at 2026-10-16"},
+		{"dotted braces", "Render {{.Name}} and {{ .Values.x }}", "Render {{.Name}} and {{ .Values.x }}"},
+		{"template actions", "{{ if .x }}yes{{ end }} {{range}}", "{{ if .x }}yes{{ end }} {{range}}"},
+		{"unbalanced", "open {{date and {{ close", "open {{date and {{ close"},
+		{"no braces", "plain prompt", "plain prompt"},
+	}
+	for _, c := range cases {
+		if got := expandPromptTemplate(c.prompt, vars); got != c.want {
+			t.Errorf("%s: got %q, want %q", c.name, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package chat
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"chatclaw/internal/errs"
+	"chatclaw/internal/sqlite"
+)
+
+// Rating values stored in messages.rating (NULL = not rated).
+const (
+	RatingUp   = 1
+	RatingDown = -1
+)
+
+// maxFeedbackNoteLength caps the note saved with a rating (in characters).
+const maxFeedbackNoteLength = 2000
+
+// Page size bounds for ListRatedMessages.
+const (
+	defaultRatedMessagesLimit = 100
+	maxRatedMessagesLimit     = 500
+)
+
+// EventChatMessageRated is emitted after RateMessage changes a rating.
+const EventChatMessageRated = "chat:message-rated"
+
+// ChatMessageRatedEvent is the payload of EventChatMessageRated.
+type ChatMessageRatedEvent struct {
+	ConversationID int64  `json:"conversation_id"`
+	MessageID      int64  `json:"message_id"`
+	Rating         int    `json:"rating"` // 0 = rating cleared
+	FeedbackNote   string `json:"feedback_note,omitempty"`
+}
+
+// ListRatedMessagesInput filters ListRatedMessages.
+type ListRatedMessagesInput struct {
+	Rating         int   `json:"rating"`          // 1 or -1; 0 = both
+	ConversationID int64 `json:"conversation_id"` // 0 = all conversations
+	BeforeID       int64 `json:"before_id"`       // cursor: only messages with id < before_id
+	Limit          int   `json:"limit"`           // default 100, max 500
+}
+
+// RatedMessage is a rated assistant reply with the context needed to review it.
+type RatedMessage struct {
+	Message
+	ConversationName string `json:"conversation_name"`
+	Question         string `json:"question"` // the closest preceding user message
+}
+
+// ratingValue maps the nullable column to the DTO value (0 = not rated).
+func ratingValue(r *int) int {
+	if r == nil {
+		return 0
+	}
+	return *r
+}
+
+// RateMessage sets the user's rating on an assistant reply: 1 (thumbs up), -1 (thumbs down),
+// or 0 to clear the rating and its note. The rating lives on the message row, so it is kept
+// when other messages of the conversation are edited or regenerated, and archived with the
+// message when the reply itself is replaced.
+func (s *ChatService) RateMessage(messageID int64, rating int, note string) error {
+	if messageID <= 0 {
+		return errs.New("error.chat_message_id_required")
+	}
+	if rating != 0 && rating != RatingUp && rating != RatingDown {
+		return errs.New("error.chat_rating_invalid")
+	}
+	note = strings.TrimSpace(note)
+	if utf8.RuneCountInString(note) > maxFeedbackNoteLength {
+		return errs.Newf("error.chat_feedback_note_too_long", map[string]any{"Max": maxFeedbackNoteLength})
+	}
+
+	db, err := s.db()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var msg messageModel
+	if err := db.NewSelect().
+		Model(&msg).
+		Column("id", "conversation_id", "role").
+		Where("id = ?", messageID).
+		Scan(ctx); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errs.New("error.chat_message_not_found")
+		}
+		return errs.Wrap("error.chat_message_read_failed", err)
+	}
+	if msg.Role != RoleAssistant {
+		return errs.New("error.chat_message_not_rateable")
+	}
+
+	// Only the rating columns are written: updated_at tracks content changes, not feedback.
+	q := db.NewUpdate().Table("messages").Where("id = ?", messageID)
+	if rating == 0 {
+		note = ""
+		q = q.Set("rating = NULL").Set("feedback_note = ''").Set("rated_at = NULL")
+	} else {
+		q = q.Set("rating = ?", rating).Set("feedback_note = ?", note).Set("rated_at = ?", sqlite.NowUTC())
+	}
+	if _, err := q.Exec(ctx); err != nil {
+		return errs.Wrap("error.chat_message_update_failed", err)
+	}
+
+	s.app.Event.Emit(EventChatMessageRated, ChatMessageRatedEvent{
+		ConversationID: msg.ConversationID,
+		MessageID:      messageID,
+		Rating:         rating,
+		FeedbackNote:   note,
+	})
+	return nil
+}
+
+// ListRatedMessages returns rated assistant replies, newest first, for a "good/bad answers"
+// review view. Page with BeforeID set to the smallest ID of the previous page.
+func (s *ChatService) ListRatedMessages(input ListRatedMessagesInput) ([]RatedMessage, error) {
+	if input.Rating != 0 && input.Rating != RatingUp && input.Rating != RatingDown {
+		return nil, errs.New("error.chat_rating_invalid")
+	}
+	limit := input.Limit
+	if limit <= 0 {
+		limit = defaultRatedMessagesLimit
+	}
+	limit = min(limit, maxRatedMessagesLimit)
+
+	db, err := s.db()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var rows []struct {
+		messageModel     `bun:",extend"`
+		ConversationName string `bun:"conversation_name"`
+		Question         string `bun:"question"`
+	}
+	q := db.NewSelect().
+		Model(&rows).
+		ColumnExpr("m.*").
+		ColumnExpr("COALESCE(c.name, '') AS conversation_name").
+		ColumnExpr(`COALESCE((SELECT u.content FROM messages AS u
+			WHERE u.conversation_id = m.conversation_id AND u.role = ? AND u.id < m.id
+			ORDER BY u.id DESC LIMIT 1), '') AS question`, RoleUser).
+		Join("LEFT JOIN conversations AS c ON c.id = m.conversation_id").
+		Where("m.rating IS NOT NULL")
+	if input.Rating != 0 {
+		q = q.Where("m.rating = ?", input.Rating)
+	}
+	if input.ConversationID > 0 {
+		q = q.Where("m.conversation_id = ?", input.ConversationID)
+	}
+	if input.BeforeID > 0 {
+		q = q.Where("m.id < ?", input.BeforeID)
+	}
+	if err := q.OrderExpr("m.id DESC").Limit(limit).Scan(ctx); err != nil {
+		return nil, errs.Wrap("error.chat_messages_failed", err)
+	}
+
+	out := make([]RatedMessage, 0, len(rows))
+	for i := range rows {
+		out = append(out, RatedMessage{
+			Message:          rows[i].messageModel.toDTO(),
+			ConversationName: rows[i].ConversationName,
+			Question:         rows[i].Question,
+		})
+	}
+	return out, nil
+}
@@ -0,0 +1,45 @@
+package chat
+
+import (
+	"context"
+	"time"
+
+	"chatclaw/internal/services/i18n"
+
+	"github.com/uptrace/bun"
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// ServiceStartup implements the Wails service lifecycle; it cleans up generations
+// left unfinished by a crash or forced quit before the UI loads any messages.
+func (s *ChatService) ServiceStartup(ctx context.Context, options application.ServiceOptions) error {
+	s.recoverStaleGenerations()
+	return nil
+}
+
+// recoverStaleGenerations marks messages still pending/streaming from a previous run as errored.
+// No generation can be running yet at startup, so every such message was cut off by a restart.
+// Content, thinking, tool calls and segments flushed before the crash are kept as-is.
+func (s *ChatService) recoverStaleGenerations() {
+	db, err := s.db()
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := db.NewUpdate().
+		Model((*messageModel)(nil)).
+		Set("status = ?", StatusError).
+		Set("error = ?", i18n.T("error.chat_interrupted_by_restart")).
+		Where("status IN (?)", bun.In([]string{StatusPending, StatusStreaming})).
+		Exec(ctx)
+	if err != nil {
+		s.app.Logger.Error("[chat] failed to recover interrupted generations", "error", err)
+		return
+	}
+	if n, _ := result.RowsAffected(); n > 0 {
+		s.app.Logger.Warn("[chat] marked generations interrupted by restart as failed", "count", n)
+	}
+}
@@ -0,0 +1,113 @@
+package chat
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	einoembed "chatclaw/internal/eino/embedding"
+	"chatclaw/internal/eino/processor"
+	"chatclaw/internal/errs"
+	"chatclaw/internal/services/retrieval"
+
+	"github.com/uptrace/bun"
+)
+
+const (
+	// maxReferenceFiles caps SendMessageInput.ReferenceFiles.
+	maxReferenceFiles           = 5
+	maxReferenceFileSize  int64 = 50 * 1024 * 1024
+	referenceIndexTimeout       = 3 * time.Minute
+
+	// Chunking for reference files, matching the library defaults.
+	referenceChunkSize    = 512
+	referenceChunkOverlap = 50
+)
+
+// validateReferenceFiles checks the ad-hoc reference file paths of a message and returns them
+// cleaned and de-duplicated. checkPath restricts which local files may be read (see
+// attachmentPathChecker).
+func validateReferenceFiles(paths []string, checkPath func(string) (string, error)) ([]string, error) {
+	out := make([]string, 0, len(paths))
+	seen := make(map[string]bool, len(paths))
+	for _, p := range paths {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		cleaned, err := checkPath(p)
+		if err != nil {
+			return nil, err
+		}
+		p = cleaned
+		if seen[p] {
+			continue
+		}
+		seen[p] = true
+
+		info, err := os.Stat(p)
+		if err != nil || info.IsDir() {
+			return nil, errs.Newf("error.chat_attachment_file_not_found", map[string]any{"Path": p})
+		}
+		if info.Size() > maxReferenceFileSize {
+			return nil, errs.New("error.chat_file_too_large")
+		}
+		out = append(out, p)
+	}
+	if len(out) > maxReferenceFiles {
+		return nil, errs.Newf("error.chat_too_many_reference_files", map[string]any{"Max": maxReferenceFiles})
+	}
+	return out, nil
+}
+
+// buildReferenceIndex parses, splits and embeds the reference files of a single message into an
+// ephemeral in-memory index. Nothing is stored in the libraries; the caller closes the index
+// once the turn is over.
+func (s *ChatService) buildReferenceIndex(ctx context.Context, db *bun.DB, paths []string) (*retrieval.MemoryIndex, error) {
+	ctx, cancel := context.WithTimeout(ctx, referenceIndexTimeout)
+	defer cancel()
+
+	embeddingConfig, err := processor.GetEmbeddingConfig(ctx, db)
+	if err != nil {
+		return nil, errs.Wrap("error.chat_reference_index_failed", err)
+	}
+	embedder, err := einoembed.NewEmbedder(ctx, &einoembed.ProviderConfig{
+		ProviderID:   embeddingConfig.ProviderID,
+		ProviderType: embeddingConfig.ProviderType,
+		APIKey:       embeddingConfig.APIKey,
+		APIEndpoint:  embeddingConfig.APIEndpoint,
+		ModelID:      embeddingConfig.ModelID,
+		Dimension:    embeddingConfig.Dimension,
+		ExtraConfig:  embeddingConfig.ExtraConfig,
+	})
+	if err != nil {
+		return nil, errs.Wrap("error.chat_reference_index_failed", err)
+	}
+
+	index := retrieval.NewMemoryIndex(embedder)
+	for _, path := range paths {
+		name := filepath.Base(path)
+		chunks, err := processor.ExtractChunks(ctx, path, referenceChunkSize, referenceChunkOverlap)
+		if err != nil {
+			index.Close()
+			return nil, errs.Wrap("error.chat_reference_index_failed", err)
+		}
+		if len(chunks) == 0 {
+			s.app.Logger.Warn("[chat] reference file has no text", "path", path)
+			continue
+		}
+		vectors, err := processor.EmbedTexts(ctx, db, embedder, embeddingConfig.ProviderType, chunks)
+		if err != nil {
+			index.Close()
+			return nil, errs.Wrap("error.chat_reference_index_failed", err)
+		}
+		if err := index.Add(name, chunks, vectors); err != nil {
+			index.Close()
+			return nil, errs.Wrap("error.chat_reference_index_failed", err)
+		}
+		s.app.Logger.Info("[chat] reference file indexed", "path", path, "chunks", len(chunks))
+	}
+	return index, nil
+}
@@ -0,0 +1,55 @@
+package chat
+
+import (
+	"fmt"
+	"strings"
+)
+
+// responseLanguageNames maps the locale codes the UI offers to the language name used in the
+// instruction. Anything else (e.g. "Spanish" or "Klingon") is used verbatim.
+var responseLanguageNames = map[string]string{
+	"zh-cn": "Simplified Chinese",
+	"zh-tw": "Traditional Chinese",
+	"en-us": "English",
+	"ja-jp": "Japanese",
+	"ko-kr": "Korean",
+	"ar-sa": "Arabic",
+	"bn-bd": "Bengali",
+	"de-de": "German",
+	"es-es": "Spanish",
+	"fr-fr": "French",
+	"hi-in": "Hindi",
+	"it-it": "Italian",
+	"pt-br": "Brazilian Portuguese",
+	"sl-si": "Slovenian",
+	"tr-tr": "Turkish",
+	"vi-vn": "Vietnamese",
+}
+
+// responseLanguageName resolves an agent's response_language to a language name;
+// "" and "auto" resolve to "" (no constraint).
+func responseLanguageName(lang string) string {
+	lang = strings.TrimSpace(lang)
+	if lang == "" || strings.EqualFold(lang, "auto") {
+		return ""
+	}
+	key := strings.ToLower(strings.ReplaceAll(lang, "_", "-"))
+	if name, ok := responseLanguageNames[key]; ok {
+		return name
+	}
+	return lang
+}
+
+// withResponseLanguage appends the standard reply-language instruction after the agent's own
+// prompt, so the user's wording stays intact and the constraint is the last thing the model reads.
+func withResponseLanguage(prompt, lang string) string {
+	name := responseLanguageName(lang)
+	if name == "" {
+		return prompt
+	}
+	rule := fmt.Sprintf("Always respond in %s, regardless of the language of the user's messages or of any retrieved content, unless the user explicitly asks for another language.", name)
+	if prompt == "" {
+		return rule
+	}
+	return prompt + "\n\n" + rule
+}
@@ -0,0 +1,132 @@
+package chat
+
+import (
+	"context"
+	"sort"
+	"time"
+	"unicode"
+	"unicode/utf16"
+
+	"chatclaw/internal/errs"
+	"chatclaw/internal/fts/tokenizer"
+)
+
+// maxConversationSearchMatches caps the number of hits returned for one in-conversation search.
+const maxConversationSearchMatches = 1000
+
+// MessageSearchMatch is one highlighted hit inside a message's content.
+// Start/End are offsets in UTF-16 code units (JavaScript string indexes), End exclusive.
+type MessageSearchMatch struct {
+	MessageID int64 `json:"message_id"`
+	Start     int   `json:"start"`
+	End       int   `json:"end"`
+}
+
+// SearchMessagesInConversation finds keyword hits in the user and assistant messages of one
+// conversation, ordered by message and position so the frontend can jump between them.
+// The keyword is segmented like the knowledge-base full-text search (tokenizer.SearchTermGroups),
+// so custom stopwords are dropped and synonyms also match; matching is case-insensitive and
+// overlapping hits within a message are merged.
+func (s *ChatService) SearchMessagesInConversation(conversationID int64, keyword string) ([]MessageSearchMatch, error) {
+	if conversationID <= 0 {
+		return nil, errs.New("error.chat_conversation_id_required")
+	}
+
+	groups := tokenizer.SearchTermGroups(keyword)
+	if len(groups) == 0 {
+		return []MessageSearchMatch{}, nil
+	}
+	var termRunes [][]rune
+	for _, group := range groups {
+		for _, t := range group {
+			termRunes = append(termRunes, []rune(t))
+		}
+	}
+
+	db, err := s.db()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var models []messageModel
+	if err := db.NewSelect().
+		Model(&models).
+		Column("id", "content").
+		Where("conversation_id = ?", conversationID).
+		Where("role IN (?, ?)", RoleUser, RoleAssistant).
+		Where("content != ''").
+		OrderExpr("created_at ASC, id ASC").
+		Scan(ctx); err != nil {
+		return nil, errs.Wrap("error.chat_messages_failed", err)
+	}
+
+	matches := []MessageSearchMatch{}
+	for _, m := range models {
+		for _, r := range findMatchRanges(m.Content, termRunes) {
+			matches = append(matches, MessageSearchMatch{MessageID: m.ID, Start: r[0], End: r[1]})
+			if len(matches) >= maxConversationSearchMatches {
+				return matches, nil
+			}
+		}
+	}
+	return matches, nil
+}
+
+// findMatchRanges returns the merged, sorted [start, end) ranges (UTF-16 offsets) of all
+// case-insensitive occurrences of terms (already lowercase) in content.
+func findMatchRanges(content string, terms [][]rune) [][2]int {
+	text := []rune(content)
+	lower := make([]rune, len(text))
+	for i, r := range text {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	var ranges [][2]int
+	for _, term := range terms {
+		n := len(term)
+		if n == 0 {
+			continue
+		}
+		for i := 0; i+n <= len(lower); i++ {
+			if runesEqual(lower[i:i+n], term) {
+				ranges = append(ranges, [2]int{i, i + n})
+			}
+		}
+	}
+	if len(ranges) == 0 {
+		return nil
+	}
+
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i][0] < ranges[j][0] })
+	merged := ranges[:1]
+	for _, r := range ranges[1:] {
+		last := &merged[len(merged)-1]
+		if r[0] <= last[1] {
+			last[1] = max(last[1], r[1])
+			continue
+		}
+		merged = append(merged, r)
+	}
+
+	// Convert rune indexes to UTF-16 offsets
+	offsets := make([]int, len(text)+1)
+	for i, r := range text {
+		offsets[i+1] = offsets[i] + utf16.RuneLen(r)
+	}
+	for i := range merged {
+		merged[i] = [2]int{offsets[merged[i][0]], offsets[merged[i][1]]}
+	}
+	return merged
+}
+
+func runesEqual(a, b []rune) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -14,6 +14,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"chatclaw/internal/define"
@@ -22,6 +23,8 @@ import (
 	"chatclaw/internal/errs"
 	"chatclaw/internal/services/channels"
 	"chatclaw/internal/services/chatwiki"
+	"chatclaw/internal/services/conversations"
+	"chatclaw/internal/services/settings"
 	"chatclaw/internal/sqlite"
 
 	"github.com/cloudwego/eino/adk"
@@ -54,6 +57,10 @@ type activeGeneration struct {
 	interrupted  bool
 	agentCleanup func() // deferred agent cleanup, held during interrupt
 	streamText   string
+	// messageID is the assistant row the generation streams into (0 until created);
+	// streamText[messageTextStart:] is the part streamed into that row.
+	messageID        int64
+	messageTextStart int
 }
 
 // ChunkCallback is called each time a new content chunk is appended during streaming.
@@ -68,14 +75,19 @@ type ChatService struct {
 	checkpointStore    adk.CheckPointStore
 	chatWikiService    chatWikiBindingGetter
 	extraToolFactories []func() ([]tool.BaseTool, error)
-	activeGenerations  sync.Map // map[int64]*activeGeneration
+	activeGenerations  sync.Map     // map[int64]*activeGeneration
+	runningGenerations atomic.Int32 // generation goroutines currently running (see max_concurrent_generations)
+	shuttingDown       atomic.Bool  // set by Shutdown; new generations are refused
 	gateway            *channels.Gateway
 	chunkCallbacks     sync.Map // map[int64]ChunkCallback — per-conversation streaming sinks
+	pickedFiles        sync.Map // map[string]struct{} — paths issued by PickAttachmentFiles
 	openclawGateway    OpenClawGatewayInfo
 }
 
 // NewChatService creates a new ChatService
 func NewChatService(app *application.App) *ChatService {
+	// Unarchiving from the conversation list restores messages archived by ArchiveConversation
+	conversations.RestoreArchivedMessages = restoreConversationMessages
 	return &ChatService{
 		app:              app,
 		toolRegistry:     tools.NewToolRegistry(),
@@ -196,11 +208,26 @@ func (s *ChatService) saveImagesToWorkDir(ctx context.Context, db *bun.DB, agent
 
 	updatedImages := make([]ImagePayload, len(images))
 	for i, img := range images {
-		data, err := base64.StdEncoding.DecodeString(img.Base64)
-		if err != nil {
-			s.app.Logger.Warn("[chat] failed to decode attachment base64", "kind", img.Kind, "error", err)
-			updatedImages[i] = img
-			continue
+		var data []byte
+		if img.Base64 == "" && img.FilePath != "" {
+			if _, pathErr := s.checkAttachmentPath(img.FilePath, workDir); pathErr != nil {
+				s.app.Logger.Warn("[chat] attachment path not allowed", "kind", img.Kind, "path", img.FilePath)
+				updatedImages[i] = img
+				continue
+			}
+			data, err = os.ReadFile(img.FilePath)
+			if err != nil {
+				s.app.Logger.Warn("[chat] failed to read attachment file", "kind", img.Kind, "path", img.FilePath, "error", err)
+				updatedImages[i] = img
+				continue
+			}
+		} else {
+			data, err = base64.StdEncoding.DecodeString(img.Base64)
+			if err != nil {
+				s.app.Logger.Warn("[chat] failed to decode attachment base64", "kind", img.Kind, "error", err)
+				updatedImages[i] = img
+				continue
+			}
 		}
 
 		if img.Kind == "file" {
@@ -230,6 +257,7 @@ func (s *ChatService) saveImagesToWorkDir(ctx context.Context, db *bun.DB, agent
 				FileName:     filename,
 				OriginalName: originalName,
 				Size:         int64(len(data)),
+				Inline:       img.Inline,
 			}
 			s.app.Logger.Info("[chat] file saved to workdir", "path", savePath, "original", originalName)
 		} else {
@@ -263,7 +291,7 @@ func (s *ChatService) saveImagesToWorkDir(ctx context.Context, db *bun.DB, agent
 				Kind:     "image",
 				Source:   "local_file",
 				MimeType: img.MimeType,
-				Base64:   img.Base64,
+				Base64:   base64.StdEncoding.EncodeToString(data),
 				FilePath: savePath,
 				FileName: filename,
 				Size:     int64(len(data)),
@@ -322,8 +350,14 @@ func (s *ChatService) UnregisterChunkCallback(conversationID int64) {
 }
 
 // Shutdown cleans up all resources held by the ChatService, including
+// stopping streaming generations (so they don't write to a closing DB) and
 // killing any background processes started by execute_background.
+// Shutdown must run before the database is closed. New generations are refused, active ones are
+// cancelled (which persists their partial output as cancelled), and generations that do not exit in
+// time get their streamed text written by persistStalledGeneration.
 func (s *ChatService) Shutdown() {
+	s.shuttingDown.Store(true)
+	s.StopAllGenerations()
 	s.bgProcessManager.Cleanup()
 }
 
@@ -376,20 +410,27 @@ func (s *ChatService) SendMessage(input SendMessageInput) (*SendMessageResult, e
 	hasAttachments := len(input.Images) > 0
 
 	// Validate: content or attachments must be non-empty
-	if content == "" && !hasAttachments {
+	if content == "" && !hasAttachments && len(input.Attachments) == 0 {
 		return nil, errs.New("error.chat_content_required")
 	}
 
+	attachments, err := normalizeMessageAttachments(input.Attachments, s.attachmentPathChecker(input.ConversationID))
+	if err != nil {
+		return nil, err
+	}
+	imageCount := len(attachments)
+	var imageTotalSize int64
+	for _, att := range attachments {
+		imageTotalSize += att.Size
+	}
+
 	// Validate attachments (images + files)
 	if hasAttachments {
-		const maxImages = 4
-		const maxImageSize int64 = 2 * 1024 * 1024  // 2MB per image
-		const maxImageTotal int64 = 8 * 1024 * 1024 // 8MB total images
 		const maxFiles = 4
 		const maxFileSize int64 = 20 * 1024 * 1024 // 20MB per file
 
-		var imageCount, fileCount int
-		var imageTotalSize int64
+		var fileCount int
+		checkPath := s.attachmentPathChecker(input.ConversationID)
 
 		allowedFileMIME := map[string]bool{
 			"application/pdf":    true,
@@ -410,9 +451,28 @@ func (s *ChatService) SendMessage(input SendMessageInput) (*SendMessageResult, e
 			"application/octet-stream": true, // fallback for .log etc.
 		}
 
-		for _, att := range input.Images {
+		for i := range input.Images {
+			att := &input.Images[i]
 			if att.Base64 == "" {
-				return nil, errs.New("error.chat_image_base64_required")
+				// Attachments may reference a local file instead of carrying inline data.
+				if strings.TrimSpace(att.FilePath) == "" {
+					return nil, errs.New("error.chat_image_base64_required")
+				}
+				p, pathErr := checkPath(att.FilePath)
+				if pathErr != nil {
+					return nil, pathErr
+				}
+				att.FilePath = p
+				info, statErr := os.Stat(att.FilePath)
+				if statErr != nil || info.IsDir() {
+					return nil, errs.Newf("error.chat_attachment_file_not_found", map[string]any{"Path": att.FilePath})
+				}
+				att.Source = "local_file"
+				att.Size = info.Size()
+				att.MimeType = guessOpenClawAttachmentMime(att.FilePath, att.MimeType)
+				if att.OriginalName == "" {
+					att.OriginalName = filepath.Base(att.FilePath)
+				}
 			}
 
 			if att.Kind == "file" {
@@ -429,22 +489,26 @@ func (s *ChatService) SendMessage(input SendMessageInput) (*SendMessageResult, e
 			} else {
 				// Default: treat as image
 				imageCount++
-				if imageCount > maxImages {
-					return nil, errs.New("error.chat_too_many_images")
-				}
 				if !strings.HasPrefix(att.MimeType, "image/") {
 					return nil, errs.New("error.chat_invalid_image_type")
 				}
-				if att.Size > maxImageSize {
+				if att.Size > maxMessageImageSize {
 					return nil, errs.New("error.chat_image_too_large")
 				}
 				imageTotalSize += att.Size
 			}
 		}
+	}
+	if imageCount > maxMessageImages {
+		return nil, errs.New("error.chat_too_many_images")
+	}
+	if imageTotalSize > maxMessageImagesTotal {
+		return nil, errs.New("error.chat_images_total_too_large")
+	}
 
-		if imageTotalSize > maxImageTotal {
-			return nil, errs.New("error.chat_images_total_too_large")
-		}
+	referenceFiles, err := validateReferenceFiles(input.ReferenceFiles, s.attachmentPathChecker(input.ConversationID))
+	if err != nil {
+		return nil, err
 	}
 
 	// Serialize attachments to JSON
@@ -456,8 +520,16 @@ func (s *ChatService) SendMessage(input SendMessageInput) (*SendMessageResult, e
 		}
 		imagesJSON = string(b)
 	}
+	attachmentsJSON := "[]"
+	if len(attachments) > 0 {
+		b, err := json.Marshal(attachments)
+		if err != nil {
+			return nil, errs.Wrap("error.chat_images_serialize_failed", err)
+		}
+		attachmentsJSON = string(b)
+	}
 
-	s.app.Logger.Info("[chat] SendMessage", "conv", input.ConversationID, "tab", input.TabID, "content_len", len(content), "attachments_count", len(input.Images))
+	s.app.Logger.Info("[chat] SendMessage", "conv", input.ConversationID, "tab", input.TabID, "content_len", len(content), "attachments_count", len(input.Images)+len(attachments))
 
 	if existing, ok := s.activeGenerations.Load(input.ConversationID); ok {
 		gen := existing.(*activeGeneration)
@@ -472,6 +544,9 @@ func (s *ChatService) SendMessage(input SendMessageInput) (*SendMessageResult, e
 		}
 		return nil, errs.New("error.chat_generation_in_progress")
 	}
+	if err := s.checkGenerationCapacity(); err != nil {
+		return nil, err
+	}
 
 	db, err := s.db()
 	if err != nil {
@@ -480,37 +555,95 @@ func (s *ChatService) SendMessage(input SendMessageInput) (*SendMessageResult, e
 
 	ctx := context.Background()
 
+	// Fail before persisting the user message when the pinned model was deleted or disabled.
+	if err := s.ensureConversationModelAvailable(ctx, db, input.ConversationID, input.FallbackToAgentModel); err != nil {
+		return nil, err
+	}
+
 	agentConfig, providerConfig, agentExtras, err := s.getAgentAndProviderConfig(ctx, db, input.ConversationID)
 	if err != nil {
 		return nil, err
 	}
+	if agentExtras.ThinkingUnsupported {
+		return nil, errs.Newf("error.chat_thinking_unsupported", map[string]any{"ModelID": agentConfig.ModelID})
+	}
+
+	// Reject images up front for text-only models instead of failing mid-generation.
+	if (hasOpenClawImageAttachment(input.Images) || len(attachments) > 0) && !supportsMultimodal(providerConfig.ProviderID, agentConfig.ModelID) {
+		return nil, errs.Newf("error.chat_model_not_support_image", map[string]any{
+			"ProviderID": providerConfig.ProviderID,
+			"ModelID":    agentConfig.ModelID,
+		})
+	}
 
 	// Save attachments (images + files) to work directory and update payloads
+	var inlineImages []ImagePayload
+	attachmentDir := ""
 	if hasAttachments && len(input.Images) > 0 {
 		updatedImages, saveErr := s.saveImagesToWorkDir(ctx, db, agentConfig.AgentID, input.ConversationID, input.Images)
 		if saveErr != nil {
 			s.app.Logger.Warn("[chat] failed to save images to workdir, using original", "error", saveErr)
 			// Continue with original images if save fails
+			updatedImages = input.Images
+		}
+		// Inline documents are parsed in the generation goroutine (see parseInlineAttachments)
+		if hasInlineAttachments(updatedImages) {
+			inlineImages = updatedImages
+			if dir, err := s.resolveWorkDir(ctx, db, agentConfig.AgentID, input.ConversationID); err == nil {
+				attachmentDir = dir
+			}
+		}
+		// Update imagesJSON with saved image paths
+		b, err := json.Marshal(updatedImages)
+		if err != nil {
+			s.app.Logger.Warn("[chat] failed to serialize updated images", "error", err)
 		} else {
-			// Update imagesJSON with saved image paths
-			b, err := json.Marshal(updatedImages)
-			if err != nil {
-				s.app.Logger.Warn("[chat] failed to serialize updated images", "error", err)
-			} else {
-				imagesJSON = string(b)
+			imagesJSON = string(b)
+		}
+	}
+
+	// Reference files are indexed in memory for this turn only (chat mode injects the hits,
+	// task mode gets a reference_retriever tool)
+	if len(referenceFiles) > 0 {
+		index, err := s.buildReferenceIndex(ctx, db, referenceFiles)
+		if err != nil {
+			return nil, err
+		}
+		agentExtras.ReferenceIndex = index
+		if agentExtras.ChatMode != "chat" {
+			names := make([]string, len(referenceFiles))
+			for i, p := range referenceFiles {
+				names[i] = filepath.Base(p)
 			}
+			agentConfig.Instruction += fmt.Sprintf("\n\nThe user attached reference files to their latest message (%s). They are not in any knowledge base; search them with the %s tool.",
+				strings.Join(names, ", "), tools.ToolIDReferenceRetriever)
 		}
 	}
 
+	var result *SendMessageResult
 	if agentExtras.ChatMode == "chat" {
-		return s.startGeneration(db, input.ConversationID, input.TabID, agentConfig, providerConfig, agentExtras, func(genCtx context.Context, requestID string) {
-			s.runChatModeGeneration(genCtx, db, input.ConversationID, input.TabID, requestID, content, imagesJSON, agentConfig, providerConfig, agentExtras)
+		result, err = s.startGeneration(db, input.ConversationID, input.TabID, agentConfig, providerConfig, agentExtras, func(genCtx context.Context, requestID string) {
+			defer agentExtras.ReferenceIndex.Close()
+			imagesJSON, attachmentContext := imagesJSON, ""
+			if inlineImages != nil {
+				imagesJSON, attachmentContext = s.parseInlineAttachments(genCtx, input.ConversationID, input.TabID, requestID, attachmentDir, imagesJSON, inlineImages)
+			}
+			s.runChatModeGeneration(genCtx, db, input.ConversationID, input.TabID, requestID, content, imagesJSON, attachmentsJSON, attachmentContext, agentConfig, providerConfig, agentExtras)
+		})
+	} else {
+		// Task mode: the index is closed with the agent's tools (see buildExtras)
+		result, err = s.startGeneration(db, input.ConversationID, input.TabID, agentConfig, providerConfig, agentExtras, func(genCtx context.Context, requestID string) {
+			imagesJSON, attachmentContext := imagesJSON, ""
+			if inlineImages != nil {
+				imagesJSON, attachmentContext = s.parseInlineAttachments(genCtx, input.ConversationID, input.TabID, requestID, attachmentDir, imagesJSON, inlineImages)
+			}
+			s.runGeneration(genCtx, db, input.ConversationID, input.TabID, requestID, content, imagesJSON, attachmentsJSON, attachmentContext, agentConfig, providerConfig, agentExtras)
 		})
 	}
-
-	return s.startGeneration(db, input.ConversationID, input.TabID, agentConfig, providerConfig, agentExtras, func(genCtx context.Context, requestID string) {
-		s.runGeneration(genCtx, db, input.ConversationID, input.TabID, requestID, content, imagesJSON, agentConfig, providerConfig, agentExtras)
-	})
+	if err != nil {
+		agentExtras.ReferenceIndex.Close()
+	}
+	return result, err
 }
 
 // handleResumeMessage processes user confirmation/rejection for an interrupted generation.
@@ -586,6 +719,9 @@ func (s *ChatService) EditAndResend(input EditAndResendInput) (*SendMessageResul
 			return nil, errs.New("error.chat_previous_generation_not_finished")
 		}
 	}
+	if err := s.checkGenerationCapacity(); err != nil {
+		return nil, err
+	}
 
 	db, err := s.db()
 	if err != nil {
@@ -608,7 +744,15 @@ func (s *ChatService) EditAndResend(input EditAndResendInput) (*SendMessageResul
 		return nil, errs.Wrap("error.chat_message_read_failed", err)
 	}
 
-	if err := s.deleteMessagesAfter(ctx, db, input.ConversationID, input.MessageID, false); err != nil {
+	archive := settings.GetBool("chat_archive_on_edit", false)
+	if input.Archive != nil {
+		archive = *input.Archive
+	}
+	editID := ""
+	if archive {
+		editID = uuid.New().String()
+	}
+	if err := s.deleteMessagesAfter(ctx, db, input.ConversationID, input.MessageID, editID); err != nil {
 		return nil, err
 	}
 
@@ -617,31 +761,43 @@ func (s *ChatService) EditAndResend(input EditAndResendInput) (*SendMessageResul
 	if err != nil {
 		return nil, err
 	}
+	if agentExtras.ThinkingUnsupported {
+		return nil, errs.Newf("error.chat_thinking_unsupported", map[string]any{"ModelID": agentConfig.ModelID})
+	}
+	if hasOpenClawImageAttachment(input.Images) && !supportsMultimodal(providerConfig.ProviderID, agentConfig.ModelID) {
+		return nil, errs.Newf("error.chat_model_not_support_image", map[string]any{
+			"ProviderID": providerConfig.ProviderID,
+			"ModelID":    agentConfig.ModelID,
+		})
+	}
 
 	// Update message content and images
 	// If new images are provided, update them; otherwise keep existing images
 	updateQuery := db.NewUpdate().Model((*messageModel)(nil)).Where("id = ?", input.MessageID)
 	imagesJSON := msg.ImagesJSON // keep existing by default
+	var inlineImages []ImagePayload
+	attachmentDir := ""
 	if len(input.Images) > 0 {
 		// Save new images to work directory
 		updatedImages, saveErr := s.saveImagesToWorkDir(ctx, db, agentConfig.AgentID, input.ConversationID, input.Images)
 		if saveErr != nil {
 			s.app.Logger.Warn("[chat] failed to save images to workdir, using original", "error", saveErr)
 			// Use original input images if save fails
-			b, err := json.Marshal(input.Images)
-			if err != nil {
-				return nil, errs.Wrap("error.chat_images_serialize_failed", err)
-			}
-			imagesJSON = string(b)
-		} else {
-			// Use updated images with file paths
-			b, err := json.Marshal(updatedImages)
-			if err != nil {
-				return nil, errs.Wrap("error.chat_images_serialize_failed", err)
+			updatedImages = input.Images
+		}
+		if hasInlineAttachments(updatedImages) {
+			inlineImages = updatedImages
+			if dir, err := s.resolveWorkDir(ctx, db, agentConfig.AgentID, input.ConversationID); err == nil {
+				attachmentDir = dir
 			}
-			imagesJSON = string(b)
 		}
-		updateQuery = updateQuery.Set("content = ?, images_json = ?", content, imagesJSON)
+		b, err := json.Marshal(updatedImages)
+		if err != nil {
+			return nil, errs.Wrap("error.chat_images_serialize_failed", err)
+		}
+		imagesJSON = string(b)
+		// attachment_context is filled in by the generation goroutine once the documents are parsed
+		updateQuery = updateQuery.Set("content = ?, images_json = ?, attachment_context = ''", content, imagesJSON)
 	} else {
 		updateQuery = updateQuery.Set("content = ?", content)
 	}
@@ -649,13 +805,32 @@ func (s *ChatService) EditAndResend(input EditAndResendInput) (*SendMessageResul
 		return nil, errs.Wrap("error.chat_message_update_failed", err)
 	}
 
+	// parseEdited parses the edited message's inline documents before the history is loaded.
+	parseEdited := func(genCtx context.Context, requestID string) {
+		if inlineImages == nil {
+			return
+		}
+		newImagesJSON, attachmentContext := s.parseInlineAttachments(genCtx, input.ConversationID, input.TabID, requestID, attachmentDir, imagesJSON, inlineImages)
+		dbCtx, dbCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer dbCancel()
+		if _, err := db.NewUpdate().
+			Model((*messageModel)(nil)).
+			Set("images_json = ?, attachment_context = ?", newImagesJSON, attachmentContext).
+			Where("id = ?", input.MessageID).
+			Exec(dbCtx); err != nil {
+			s.app.Logger.Warn("[chat] failed to save attachment context", "message_id", input.MessageID, "error", err)
+		}
+	}
+
 	var result *SendMessageResult
 	if agentExtras.ChatMode == "chat" {
 		result, err = s.startGeneration(db, input.ConversationID, input.TabID, agentConfig, providerConfig, agentExtras, func(genCtx context.Context, requestID string) {
+			parseEdited(genCtx, requestID)
 			s.runChatModeWithExistingHistory(genCtx, db, input.ConversationID, input.TabID, requestID, agentConfig, providerConfig, agentExtras)
 		})
 	} else {
 		result, err = s.startGeneration(db, input.ConversationID, input.TabID, agentConfig, providerConfig, agentExtras, func(genCtx context.Context, requestID string) {
+			parseEdited(genCtx, requestID)
 			s.runGenerationWithExistingHistory(genCtx, db, input.ConversationID, input.TabID, requestID, agentConfig, providerConfig, agentExtras)
 		})
 	}
@@ -663,6 +838,7 @@ func (s *ChatService) EditAndResend(input EditAndResendInput) (*SendMessageResul
 		return nil, err
 	}
 	result.MessageID = input.MessageID
+	result.EditID = editID
 	return result, nil
 }
 
@@ -682,6 +858,105 @@ func (s *ChatService) StopGeneration(conversationID int64) error {
 	return nil
 }
 
+// stopGenerationTimeout bounds how long StopAllGenerations waits, in total, for the
+// generation goroutines to exit after being cancelled.
+const stopGenerationTimeout = 2 * time.Second
+
+// StopAllGenerations cancels every active generation and waits (all together, bounded by
+// stopGenerationTimeout) for their goroutines to finish; generations still running after that
+// are logged and their partial replies persisted. It returns the number of generations that
+// were stopped. Safe to call when nothing is running.
+func (s *ChatService) StopAllGenerations() int {
+	type entry struct {
+		conversationID int64
+		gen            *activeGeneration
+	}
+	var entries []entry
+	s.activeGenerations.Range(func(key, value any) bool {
+		entries = append(entries, entry{conversationID: key.(int64), gen: value.(*activeGeneration)})
+		return true
+	})
+	if len(entries) == 0 {
+		return 0
+	}
+
+	for _, e := range entries {
+		e.gen.cancel()
+	}
+
+	timeout := time.After(stopGenerationTimeout)
+	expired := make(chan time.Time)
+	close(expired)
+	var stalled []int64
+	for _, e := range entries {
+		select {
+		case <-e.gen.done:
+		case <-timeout:
+			// Past the deadline the remaining generations are only checked, not waited for
+			timeout = expired
+		}
+		select {
+		case <-e.gen.done:
+		default:
+			stalled = append(stalled, e.conversationID)
+			s.persistStalledGeneration(e.conversationID, e.gen)
+		}
+
+		// Interrupted generations keep their entry (and agent resources) alive while
+		// waiting for user confirmation; release them explicitly.
+		e.gen.mu.Lock()
+		interrupted := e.gen.interrupted
+		e.gen.interrupted = false
+		e.gen.mu.Unlock()
+		if interrupted {
+			s.cleanupGeneration(e.gen, e.conversationID)
+		}
+	}
+
+	if len(stalled) > 0 && s.app != nil {
+		s.app.Logger.Warn("[chat] generations did not finish within timeout", "conversations", stalled)
+	}
+	if s.app != nil {
+		s.app.Logger.Info("[chat] stopped all generations", "count", len(entries))
+	}
+	return len(entries)
+}
+
+// persistStalledGeneration saves what a generation has streamed so far when its goroutine did not
+// exit after being cancelled, so quitting cannot leave the reply stuck in streaming state or drop
+// its text. Streamed content is appended to whatever the generation's assistant row already holds
+// (continued replies); thinking and tool calls are kept only as far as they were already written.
+// If the goroutine finishes later, its own final update overwrites this one.
+func (s *ChatService) persistStalledGeneration(conversationID int64, gen *activeGeneration) {
+	gen.mu.Lock()
+	text := gen.streamText[gen.messageTextStart:]
+	messageID := gen.messageID
+	gen.mu.Unlock()
+	if messageID == 0 {
+		// The assistant row was never created; recoverStaleGenerations handles anything left
+		return
+	}
+
+	db, err := s.db()
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	if _, err := db.NewUpdate().
+		Model((*messageModel)(nil)).
+		Set("content = content || ?", text).
+		Set("status = ?", StatusCancelled).
+		Set("finish_reason = ?", "cancelled").
+		Where("id = ?", messageID).
+		Where("status IN (?)", bun.In([]string{StatusPending, StatusStreaming})).
+		Exec(ctx); err != nil && s.app != nil {
+		s.app.Logger.Error("[chat] persist stalled generation failed", "conv", conversationID, "error", err)
+	}
+}
+
 // WaitForGeneration waits until the active generation for a conversation is finished.
 func (s *ChatService) WaitForGeneration(conversationID int64, requestID string) error {
 	existing, ok := s.activeGenerations.Load(conversationID)
@@ -735,6 +1010,25 @@ func (s *ChatService) appendGenerationContent(conversationID int64, requestID st
 	gen.mu.Unlock()
 }
 
+// setGenerationMessage records the assistant message an active generation streams into, so
+// persistStalledGeneration only touches that row.
+func (s *ChatService) setGenerationMessage(conversationID int64, requestID string, messageID int64) {
+	existing, ok := s.activeGenerations.Load(conversationID)
+	if !ok {
+		return
+	}
+
+	gen := existing.(*activeGeneration)
+	if gen.requestID != requestID {
+		return
+	}
+
+	gen.mu.Lock()
+	gen.messageID = messageID
+	gen.messageTextStart = len(gen.streamText)
+	gen.mu.Unlock()
+}
+
 // HasActiveGeneration reports whether the conversation still has a live generation.
 // Cron history uses this to avoid marking manual runs as completed too early.
 func (s *ChatService) HasActiveGeneration(conversationID int64) bool {
@@ -747,6 +1041,13 @@ func (s *ChatService) HasActiveGeneration(conversationID int64) bool {
 
 // startGeneration creates a new generation context and launches the goroutine.
 func (s *ChatService) startGeneration(db *bun.DB, conversationID int64, tabID string, agentConfig einoagent.Config, providerConfig einoagent.ProviderConfig, agentExtras AgentExtras, runFn func(ctx context.Context, requestID string)) (*SendMessageResult, error) {
+	if s.shuttingDown.Load() {
+		return nil, errs.New("error.chat_shutting_down")
+	}
+	if !s.acquireGenerationSlot() {
+		return nil, errs.Newf("error.too_many_active_generations", map[string]any{"Max": maxConcurrentGenerations()})
+	}
+
 	requestID := uuid.New().String()
 	genCtx, cancel := context.WithCancel(context.Background())
 
@@ -761,6 +1062,7 @@ func (s *ChatService) startGeneration(db *bun.DB, conversationID int64, tabID st
 	go func() {
 		defer close(gen.done)
 		defer s.tryDeleteGeneration(conversationID, gen)
+		defer s.runningGenerations.Add(-1)
 		runFn(genCtx, requestID)
 	}()
 
@@ -770,6 +1072,39 @@ func (s *ChatService) startGeneration(db *bun.DB, conversationID int64, tabID st
 	}, nil
 }
 
+// maxConcurrentGenerations returns the max_concurrent_generations setting (0 = unlimited).
+func maxConcurrentGenerations() int {
+	return settings.GetInt("max_concurrent_generations", 5)
+}
+
+// acquireGenerationSlot reserves one of the max_concurrent_generations slots for a generation
+// goroutine; the goroutine releases it when it exits. Interrupted generations waiting for user
+// confirmation hold no goroutine and so no slot.
+func (s *ChatService) acquireGenerationSlot() bool {
+	limit := maxConcurrentGenerations()
+	for {
+		cur := s.runningGenerations.Load()
+		if limit > 0 && int(cur) >= limit {
+			return false
+		}
+		if s.runningGenerations.CompareAndSwap(cur, cur+1) {
+			return true
+		}
+	}
+}
+
+// checkGenerationCapacity fails fast when no generation slot is free, for callers that modify
+// history before calling startGeneration.
+func (s *ChatService) checkGenerationCapacity() error {
+	if s.shuttingDown.Load() {
+		return errs.New("error.chat_shutting_down")
+	}
+	if limit := maxConcurrentGenerations(); limit > 0 && int(s.runningGenerations.Load()) >= limit {
+		return errs.Newf("error.too_many_active_generations", map[string]any{"Max": limit})
+	}
+	return nil
+}
+
 // tryDeleteGeneration removes the generation from the map only if it is still
 // the active one and not in an interrupted state (waiting for user confirmation).
 func (s *ChatService) tryDeleteGeneration(conversationID int64, gen *activeGeneration) {
@@ -785,8 +1120,20 @@ func (s *ChatService) tryDeleteGeneration(conversationID int64, gen *activeGener
 }
 
 // deleteMessagesAfter deletes all messages after the given message ID.
-// archive parameter is reserved for future use.
-func (s *ChatService) deleteMessagesAfter(ctx context.Context, db *bun.DB, conversationID, messageID int64, archive bool) error {
+// With a non-empty editID they are archived as that edit operation instead (see UndoLastEdit).
+func (s *ChatService) deleteMessagesAfter(ctx context.Context, db *bun.DB, conversationID, messageID int64, editID string) error {
+	if editID != "" {
+		if err := db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+			if err := archiveEdit(ctx, tx, conversationID, messageID, editID); err != nil {
+				return err
+			}
+			return rewindContextReset(ctx, tx, conversationID, messageID)
+		}); err != nil {
+			return errs.Wrap("error.chat_archive_failed", err)
+		}
+		return nil
+	}
+
 	_, err := db.NewDelete().
 		Model((*messageModel)(nil)).
 		Where("conversation_id = ?", conversationID).
@@ -795,5 +1142,20 @@ func (s *ChatService) deleteMessagesAfter(ctx context.Context, db *bun.DB, conve
 	if err != nil {
 		return errs.Wrap("error.chat_messages_delete_failed", err)
 	}
+	if err := rewindContextReset(ctx, db, conversationID, messageID); err != nil {
+		return errs.Wrap("error.chat_messages_delete_failed", err)
+	}
 	return nil
 }
+
+// rewindContextReset moves a context reset marker that is at or after messageID back to just before
+// it, so the message being edited or regenerated from is still sent to the model.
+func rewindContextReset(ctx context.Context, db bun.IDB, conversationID, messageID int64) error {
+	_, err := db.NewUpdate().
+		Table("conversations").
+		Set("context_reset_at_message_id = ?", messageID-1).
+		Where("id = ?", conversationID).
+		Where("context_reset_at_message_id >= ?", messageID).
+		Exec(ctx)
+	return err
+}
@@ -0,0 +1,66 @@
+package chat
+
+import "strings"
+
+// Normalized stop reasons exposed to the frontend (Message.StopReason, ChatCompleteEvent.StopReason).
+const (
+	StopReasonStop          = "stop"           // natural end of the reply (or a stop sequence)
+	StopReasonLength        = "length"         // output token limit reached; the reply can be continued
+	StopReasonToolCalls     = "tool_calls"     // the model ended its turn to call tools
+	StopReasonContentFilter = "content_filter" // blocked or cut by the provider's safety filter
+	StopReasonCancelled     = "cancelled"      // stopped by the user
+	StopReasonInterrupted   = "interrupted"    // paused waiting for user confirmation
+	StopReasonUnknown       = "unknown"        // a provider value not in stopReasonMap
+)
+
+// stopReasonMap maps raw provider finish reasons (lowercased) to normalized stop reasons.
+// OpenAI-compatible: stop/length/tool_calls/function_call/content_filter;
+// Claude: end_turn/stop_sequence/max_tokens/tool_use/refusal;
+// Gemini: STOP/MAX_TOKENS/SAFETY/RECITATION/BLOCKLIST/PROHIBITED_CONTENT/SPII;
+// Ollama: stop/length/load; Responses API incomplete reasons: max_output_tokens.
+var stopReasonMap = map[string]string{
+	"stop":          StopReasonStop,
+	"end_turn":      StopReasonStop,
+	"stop_sequence": StopReasonStop,
+	"eos":           StopReasonStop,
+	"load":          StopReasonStop,
+
+	"length":            StopReasonLength,
+	"max_tokens":        StopReasonLength,
+	"max_output_tokens": StopReasonLength,
+
+	"tool_calls":    StopReasonToolCalls,
+	"tool_use":      StopReasonToolCalls,
+	"function_call": StopReasonToolCalls,
+
+	"content_filter":     StopReasonContentFilter,
+	"refusal":            StopReasonContentFilter,
+	"safety":             StopReasonContentFilter,
+	"recitation":         StopReasonContentFilter,
+	"blocklist":          StopReasonContentFilter,
+	"prohibited_content": StopReasonContentFilter,
+	"spii":               StopReasonContentFilter,
+
+	"cancelled":   StopReasonCancelled,
+	"interrupted": StopReasonInterrupted,
+}
+
+// NormalizeStopReason maps a raw finish_reason to one of the StopReason* constants.
+// An empty reason (still generating, or failed before the provider reported one) stays empty;
+// values missing from stopReasonMap become StopReasonUnknown.
+func NormalizeStopReason(finishReason string) string {
+	raw := strings.ToLower(strings.TrimSpace(finishReason))
+	if raw == "" {
+		return ""
+	}
+	if reason, ok := stopReasonMap[raw]; ok {
+		return reason
+	}
+	return StopReasonUnknown
+}
+
+// canContinueReply reports whether a finished assistant reply stopped at the output length limit,
+// i.e. ContinueGeneration can resume it.
+func canContinueReply(role, status, finishReason string) bool {
+	return role == RoleAssistant && status == StatusSuccess && NormalizeStopReason(finishReason) == StopReasonLength
+}
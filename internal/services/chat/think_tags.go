@@ -0,0 +1,160 @@
+package chat
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+const (
+	thinkOpenTag  = "<think>"
+	thinkCloseTag = "</think>"
+)
+
+// thinkTagsExtraConfig is the inline-reasoning part of a provider's extra_config, e.g.
+//
+//	{"strip_think_tags": true}
+//
+// for OpenAI-compatible gateways that return reasoning as <think>...</think> inside the content
+// instead of in reasoning_content.
+type thinkTagsExtraConfig struct {
+	StripThinkTags bool `json:"strip_think_tags"`
+}
+
+// stripThinkTagsEnabled reports whether extraConfig turns on strip_think_tags. Invalid JSON is
+// reported when the chat model is created, so it just counts as off here.
+func stripThinkTagsEnabled(extraConfig string) bool {
+	if strings.TrimSpace(extraConfig) == "" {
+		return false
+	}
+	var cfg thinkTagsExtraConfig
+	if err := json.Unmarshal([]byte(extraConfig), &cfg); err != nil {
+		return false
+	}
+	return cfg.StripThinkTags
+}
+
+// thinkTagSplitter separates <think>...</think> blocks from streamed content. Tags may be split
+// across chunks, so a trailing partial tag is held back until the next chunk decides it.
+type thinkTagSplitter struct {
+	inThink  bool
+	pending  string
+	trimNext bool // drop the whitespace right after a tag
+}
+
+// split consumes the next content chunk and returns the parts that are reasoning and answer.
+func (t *thinkTagSplitter) split(chunk string) (thinking, content string) {
+	text := t.pending + chunk
+	t.pending = ""
+
+	var think, out strings.Builder
+	for text != "" {
+		tag := thinkOpenTag
+		if t.inThink {
+			tag = thinkCloseTag
+		}
+		if i := strings.Index(text, tag); i >= 0 {
+			t.write(&think, &out, text[:i])
+			text = text[i+len(tag):]
+			t.inThink = !t.inThink
+			t.trimNext = true
+			continue
+		}
+		keep := partialTagSuffix(text, tag)
+		t.write(&think, &out, text[:len(text)-keep])
+		t.pending = text[len(text)-keep:]
+		break
+	}
+	return think.String(), out.String()
+}
+
+// flush returns the held-back text once the stream has ended.
+func (t *thinkTagSplitter) flush() (thinking, content string) {
+	var think, out strings.Builder
+	t.write(&think, &out, t.pending)
+	t.pending = ""
+	return think.String(), out.String()
+}
+
+func (t *thinkTagSplitter) write(think, out *strings.Builder, s string) {
+	if t.trimNext {
+		s = strings.TrimLeftFunc(s, unicode.IsSpace)
+		if s == "" {
+			return
+		}
+		t.trimNext = false
+	}
+	if t.inThink {
+		think.WriteString(s)
+	} else {
+		out.WriteString(s)
+	}
+}
+
+// partialTagSuffix returns the length of the longest suffix of text that is a proper prefix of tag.
+func partialTagSuffix(text, tag string) int {
+	for n := min(len(text), len(tag)-1); n > 0; n-- {
+		if strings.HasSuffix(text, tag[:n]) {
+			return n
+		}
+	}
+	return 0
+}
+
+// splitMessage moves the <think> parts of msg.Content into ReasoningContent. msg is left untouched.
+func (t *thinkTagSplitter) splitMessage(msg *schema.Message) *schema.Message {
+	if msg == nil || msg.Content == "" {
+		return msg
+	}
+	thinking, content := t.split(msg.Content)
+	out := *msg
+	out.ReasoningContent += thinking
+	out.Content = content
+	return &out
+}
+
+// splitThinkTagStream wraps a model stream so <think> blocks arrive as ReasoningContent and the
+// consumer can treat them like any provider's native reasoning. The returned reader must be closed.
+func splitThinkTagStream(stream *schema.StreamReader[*schema.Message]) *schema.StreamReader[*schema.Message] {
+	sr, sw := schema.Pipe[*schema.Message](1)
+	go func() {
+		defer stream.Close()
+		defer sw.Close()
+
+		splitter := &thinkTagSplitter{}
+		for {
+			msg, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				if thinking, content := splitter.flush(); thinking != "" || content != "" {
+					sw.Send(&schema.Message{Role: schema.Assistant, ReasoningContent: thinking, Content: content}, nil)
+				}
+				return
+			}
+			if err != nil {
+				sw.Send(nil, err)
+				return
+			}
+			if closed := sw.Send(splitter.splitMessage(msg), nil); closed {
+				return
+			}
+		}
+	}()
+	return sr
+}
+
+// splitNonStreamingThinkTags applies the <think> split to a complete (non-streamed) message.
+func splitNonStreamingThinkTags(msg *schema.Message) *schema.Message {
+	splitter := &thinkTagSplitter{}
+	out := splitter.splitMessage(msg)
+	if out == nil {
+		return nil
+	}
+	thinking, content := splitter.flush()
+	out.ReasoningContent += thinking
+	out.Content += content
+	return out
+}
@@ -0,0 +1,47 @@
+package chat
+
+import "testing"
+
+func TestThinkTagSplitterAcrossChunks(t *testing.T) {
+	var splitter thinkTagSplitter
+	var thinking, content string
+	for _, chunk := range []string{"<thi", "nk>\nplan the", " answer</th", "ink>\n\nHello <b", ">world</b>"} {
+		th, c := splitter.split(chunk)
+		thinking += th
+		content += c
+	}
+	th, c := splitter.flush()
+	thinking += th
+	content += c
+
+	if thinking != "plan the answer" {
+		t.Errorf("thinking = %q", thinking)
+	}
+	if content != "Hello <b>world</b>" {
+		t.Errorf("content = %q", content)
+	}
+}
+
+func TestThinkTagSplitterUnclosedThink(t *testing.T) {
+	var splitter thinkTagSplitter
+	th1, c1 := splitter.split("<think>still going </")
+	th2, c2 := splitter.flush()
+	if th1+th2 != "still going </" || c1+c2 != "" {
+		t.Errorf("thinking = %q, content = %q", th1+th2, c1+c2)
+	}
+}
+
+func TestStripThinkTagsEnabled(t *testing.T) {
+	cases := map[string]bool{
+		"":                           false,
+		"{}":                         false,
+		`{"strip_think_tags":true}`:  true,
+		`{"strip_think_tags":false}`: false,
+		"not json":                   false,
+	}
+	for in, want := range cases {
+		if got := stripThinkTagsEnabled(in); got != want {
+			t.Errorf("stripThinkTagsEnabled(%q) = %v, want %v", in, got, want)
+		}
+	}
+}
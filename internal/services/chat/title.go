@@ -0,0 +1,304 @@
+package chat
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	einoagent "chatclaw/internal/eino/agent"
+	"chatclaw/internal/errs"
+	"chatclaw/internal/fts/tokenizer"
+	"chatclaw/internal/services/conversations"
+	"chatclaw/internal/services/settings"
+	"chatclaw/internal/sqlite"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/uptrace/bun"
+)
+
+const (
+	// titleMaxRunes mirrors the 100-rune cap enforced by the conversations service,
+	// but generated titles are meant to be much shorter.
+	titleMaxRunes = 50
+	// titleExcerptRunes limits how much of the first exchange is sent to the model.
+	titleExcerptRunes = 1000
+	titleTimeout      = 30 * time.Second
+
+	titleSystemPrompt = "You write titles for chat conversations. Reply with a 3-6 word title that summarizes the conversation below, " +
+		"in the same language the user wrote in. Reply with the title only: no quotes, no trailing punctuation, no explanation."
+)
+
+// EventConversationsChanged is the event the assistant sidebar listens to for refreshing
+// its conversation list (also emitted by the frontend when tabs create/rename conversations).
+const EventConversationsChanged = conversations.EventConversationsChanged
+
+// ConversationTitleEvent is emitted after a title has been generated for a conversation.
+type ConversationTitleEvent struct {
+	AgentID        int64  `json:"agent_id"`
+	ConversationID int64  `json:"conversation_id"`
+	Name           string `json:"name"`
+	Action         string `json:"action"` // always "title_generated"
+}
+
+// GenerateConversationTitle summarizes the first exchange of a conversation into a
+// short title, stores it as the conversation name and returns it.
+// The model configured via chat_title_provider_id/chat_title_model_id is used when set,
+// otherwise the conversation's own provider/model.
+func (s *ChatService) GenerateConversationTitle(conversationID int64) (string, error) {
+	if conversationID <= 0 {
+		return "", errs.New("error.chat_conversation_id_required")
+	}
+
+	db, err := s.db()
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), titleTimeout)
+	defer cancel()
+
+	var agentID int64
+	if err := db.NewSelect().
+		Table("conversations").
+		Column("agent_id").
+		Where("id = ?", conversationID).
+		Scan(ctx, &agentID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", errs.New("error.chat_conversation_not_found")
+		}
+		return "", errs.Wrap("error.chat_conversation_read_failed", err)
+	}
+
+	userContent, assistantContent, err := s.loadFirstExchange(ctx, db, conversationID)
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(assistantContent) == "" {
+		return "", errs.New("error.chat_title_no_exchange")
+	}
+
+	agentConfig, err := s.resolveTitleModelConfig(ctx, db, conversationID)
+	if err != nil {
+		return "", err
+	}
+
+	chatModel, err := einoagent.CreateChatModel(ctx, agentConfig)
+	if err != nil {
+		return "", errs.Wrap("error.chat_title_generate_failed", err)
+	}
+
+	var prompt strings.Builder
+	prompt.WriteString("User:\n")
+	prompt.WriteString(truncateRunes(strings.TrimSpace(userContent), titleExcerptRunes))
+	prompt.WriteString("\n\nAssistant:\n")
+	prompt.WriteString(truncateRunes(strings.TrimSpace(assistantContent), titleExcerptRunes))
+
+	resp, err := chatModel.Generate(ctx, []*schema.Message{
+		schema.SystemMessage(titleSystemPrompt),
+		schema.UserMessage(prompt.String()),
+	})
+	if err != nil {
+		return "", errs.Wrap("error.chat_title_generate_failed", err)
+	}
+
+	title := sanitizeTitle(resp.Content)
+	if title == "" {
+		return "", errs.New("error.chat_title_generate_failed")
+	}
+
+	if _, err := db.NewUpdate().
+		Table("conversations").
+		Set("name = ?", title).
+		Set("name_tokens = ?", tokenizer.TokenizeTitle(title)).
+		Set("updated_at = ?", sqlite.NowUTC()).
+		Where("id = ?", conversationID).
+		Exec(ctx); err != nil {
+		return "", errs.Wrap("error.conversation_update_failed", err)
+	}
+
+	s.app.Logger.Info("[chat] conversation title generated", "conv", conversationID, "title", title)
+	s.app.Event.Emit(EventConversationsChanged, ConversationTitleEvent{
+		AgentID:        agentID,
+		ConversationID: conversationID,
+		Name:           title,
+		Action:         "title_generated",
+	})
+	return title, nil
+}
+
+// maybeGenerateTitle triggers title generation once per conversation: right after the
+// first assistant reply, and only while the name is still the default one derived from
+// the first user message (i.e. the user has not renamed it).
+func (s *ChatService) maybeGenerateTitle(conversationID int64) {
+	if !settings.GetBool("chat_auto_title", true) {
+		return
+	}
+
+	db, err := s.db()
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	type conversationRow struct {
+		Name       string `bun:"name"`
+		ExternalID string `bun:"external_id"`
+	}
+	var conv conversationRow
+	if err := db.NewSelect().
+		Table("conversations").
+		Column("name", "external_id").
+		Where("id = ?", conversationID).
+		Scan(ctx, &conv); err != nil {
+		return
+	}
+	// Channel conversations are named after the chat they mirror.
+	if strings.TrimSpace(conv.ExternalID) != "" {
+		return
+	}
+
+	assistantCount, err := db.NewSelect().
+		Model((*messageModel)(nil)).
+		Where("conversation_id = ?", conversationID).
+		Where("role = ?", RoleAssistant).
+		Where("status = ?", StatusSuccess).
+		Count(ctx)
+	if err != nil || assistantCount != 1 {
+		return
+	}
+
+	userContent, _, err := s.loadFirstExchange(ctx, db, conversationID)
+	if err != nil || !isDefaultConversationName(conv.Name, userContent) {
+		return
+	}
+
+	if _, err := s.GenerateConversationTitle(conversationID); err != nil {
+		s.app.Logger.Warn("[chat] auto title generation failed", "conv", conversationID, "error", err)
+	}
+}
+
+// loadFirstExchange returns the content of the first user message and the first
+// successful assistant reply of a conversation.
+func (s *ChatService) loadFirstExchange(ctx context.Context, db *bun.DB, conversationID int64) (string, string, error) {
+	var userContent string
+	if err := db.NewSelect().
+		Model((*messageModel)(nil)).
+		Column("content").
+		Where("conversation_id = ?", conversationID).
+		Where("role = ?", RoleUser).
+		OrderExpr("id ASC").
+		Limit(1).
+		Scan(ctx, &userContent); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return "", "", errs.Wrap("error.chat_messages_failed", err)
+	}
+
+	var assistantContent string
+	if err := db.NewSelect().
+		Model((*messageModel)(nil)).
+		Column("content").
+		Where("conversation_id = ?", conversationID).
+		Where("role = ?", RoleAssistant).
+		Where("status = ?", StatusSuccess).
+		Where("content != ''").
+		OrderExpr("id ASC").
+		Limit(1).
+		Scan(ctx, &assistantContent); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return "", "", errs.Wrap("error.chat_messages_failed", err)
+	}
+
+	return userContent, assistantContent, nil
+}
+
+// resolveTitleModelConfig picks the model used for title generation.
+func (s *ChatService) resolveTitleModelConfig(ctx context.Context, db *bun.DB, conversationID int64) (einoagent.Config, error) {
+	providerID, _ := settings.GetValue("chat_title_provider_id")
+	modelID, _ := settings.GetValue("chat_title_model_id")
+	providerID = strings.TrimSpace(providerID)
+	modelID = strings.TrimSpace(modelID)
+
+	if providerID == "" || modelID == "" {
+		agentConfig, providerConfig, _, err := s.getAgentAndProviderConfig(ctx, db, conversationID)
+		if err != nil {
+			return einoagent.Config{}, err
+		}
+		return einoagent.Config{
+			Name:     "title",
+			ModelID:  agentConfig.ModelID,
+			Provider: providerConfig,
+		}, nil
+	}
+
+	type providerRow struct {
+		Type        string `bun:"type"`
+		APIKey      string `bun:"api_key"`
+		APIEndpoint string `bun:"api_endpoint"`
+		ExtraConfig string `bun:"extra_config"`
+		Enabled     bool   `bun:"enabled"`
+	}
+	var provider providerRow
+	if err := db.NewSelect().
+		Table("providers").
+		Column("type", "api_key", "api_endpoint", "extra_config", "enabled").
+		Where("provider_id = ?", providerID).
+		Scan(ctx, &provider); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return einoagent.Config{}, errs.Newf("error.chat_provider_not_found", map[string]any{"ProviderID": providerID})
+		}
+		return einoagent.Config{}, errs.Wrap("error.chat_provider_read_failed", err)
+	}
+	if !provider.Enabled {
+		return einoagent.Config{}, errs.New("error.chat_provider_not_enabled")
+	}
+
+	return einoagent.Config{
+		Name:    "title",
+		ModelID: modelID,
+		Provider: einoagent.ProviderConfig{
+			ProviderID:  providerID,
+			Type:        provider.Type,
+			APIKey:      provider.APIKey,
+			APIEndpoint: provider.APIEndpoint,
+			ExtraConfig: provider.ExtraConfig,
+		},
+	}, nil
+}
+
+// isDefaultConversationName reports whether name is still the one the frontend derives
+// from the first user message when it creates the conversation.
+func isDefaultConversationName(name, firstUserContent string) bool {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return true
+	}
+	return strings.HasPrefix(strings.TrimSpace(firstUserContent), name)
+}
+
+// sanitizeTitle keeps the first non-empty line of the model output and strips
+// common decorations (labels, quotes, trailing punctuation).
+func sanitizeTitle(raw string) string {
+	title := ""
+	for _, line := range strings.Split(raw, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			title = line
+			break
+		}
+	}
+	for _, prefix := range []string{"Title:", "title:", "标题：", "标题:"} {
+		title = strings.TrimPrefix(title, prefix)
+	}
+	title = strings.Trim(strings.TrimSpace(title), "\"'`“”‘’「」《》*#")
+	title = strings.TrimRight(strings.TrimSpace(title), ".。!！?？,，;；:：")
+	return truncateRunes(strings.TrimSpace(title), titleMaxRunes)
+}
+
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
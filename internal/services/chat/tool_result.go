@@ -0,0 +1,37 @@
+package chat
+
+import (
+	"fmt"
+
+	"chatclaw/internal/services/settings"
+)
+
+const (
+	// defaultToolResultMaxChars is used when the tool_result_max_chars setting is missing.
+	defaultToolResultMaxChars = 20000
+	// maxStoredToolResultChars caps the untruncated copy kept in messages.full_content for display;
+	// larger results are only stored truncated.
+	maxStoredToolResultChars = 1_000_000
+)
+
+// toolResultMaxChars returns the configured tool result limit (0 = unlimited).
+func toolResultMaxChars() int {
+	n := settings.GetInt("tool_result_max_chars", defaultToolResultMaxChars)
+	if n < 0 {
+		return defaultToolResultMaxChars
+	}
+	return n
+}
+
+// truncateToolResult cuts content to limit characters and appends a marker telling the model how
+// much was omitted. It reports whether the content was truncated.
+func truncateToolResult(content string, limit int) (string, bool) {
+	if limit <= 0 || len(content) <= limit {
+		return content, false
+	}
+	runes := []rune(content)
+	if len(runes) <= limit {
+		return content, false
+	}
+	return string(runes[:limit]) + fmt.Sprintf("\n\n[Tool result truncated: showing %d of %d characters]", limit, len(runes)), true
+}
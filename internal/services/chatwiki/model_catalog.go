@@ -3,7 +3,11 @@ package chatwiki
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
 	"sort"
 	"strconv"
 	"strings"
@@ -43,6 +47,15 @@ type ModelCatalog struct {
 	EmbeddingModels []ModelCatalogItem `json:"embedding_models"`
 	RerankModels    []ModelCatalogItem `json:"rerank_models"`
 	IntegralStats   *IntegralStats     `json:"integral_stats,omitempty"`
+
+	syncStats ModelSyncStats // changes applied to the local models table by the fetch
+}
+
+// ModelSyncStats counts the local model rows changed by one catalog sync.
+type ModelSyncStats struct {
+	Added   int `json:"added"`
+	Updated int `json:"updated"`
+	Deleted int `json:"deleted"`
 }
 
 type modelCatalogSource struct {
@@ -59,6 +72,24 @@ var (
 	getChatWikiSyncDB = sqlite.DB
 )
 
+const (
+	// modelCatalogFreshTTL is how long RefreshModelCatalogIfStale reuses the last successful fetch.
+	modelCatalogFreshTTL = 30 * time.Second
+
+	modelCatalogFetchAttempts  = 3
+	modelCatalogRetryBaseDelay = 500 * time.Millisecond
+)
+
+// chatWikiStatusError is a non-200 response from chatWikiGETLoose.
+type chatWikiStatusError struct {
+	Status int
+	Body   string
+}
+
+func (e *chatWikiStatusError) Error() string {
+	return fmt.Sprintf("unexpected status %d: %s", e.Status, e.Body)
+}
+
 func previewChatWikiLogBody(raw []byte) string {
 	trimmed := strings.TrimSpace(string(raw))
 	if trimmed == "" {
@@ -178,7 +209,7 @@ func (s *ChatWikiService) RefreshModelCatalog() (*ModelCatalog, error) {
 		return nil, err
 	}
 	source := getModelCatalogSource(binding)
-	catalog, err := s.fetchModelCatalog(source)
+	catalog, err := s.fetchModelCatalogWithRetry(source)
 	if err != nil {
 		s.app.Logger.Error("[chatwiki] RefreshModelCatalog fetch failed",
 			"server_url", source.ServerURL,
@@ -186,6 +217,7 @@ func (s *ChatWikiService) RefreshModelCatalog() (*ModelCatalog, error) {
 			"bound", source.Bound,
 			"error", err,
 		)
+		recordModelSyncFailure(err)
 		modelCatalogMu.RLock()
 		cached := cloneModelCatalog(modelCatalogCache)
 		modelCatalogMu.RUnlock()
@@ -196,12 +228,22 @@ func (s *ChatWikiService) RefreshModelCatalog() (*ModelCatalog, error) {
 			)
 			return cached, nil
 		}
+		// No in-memory catalog yet (e.g. the startup fetch failed too): fall back to the models
+		// synced into the local DB by an earlier successful fetch.
+		if dbCatalog := loadModelCatalogFromLocalDB(source); dbCatalog != nil {
+			s.app.Logger.Warn("[chatwiki] RefreshModelCatalog using local DB models after fetch failure",
+				"llm_count", len(dbCatalog.LLMModels),
+				"embedding_count", len(dbCatalog.EmbeddingModels),
+			)
+			return dbCatalog, nil
+		}
 		return nil, err
 	}
 
 	modelCatalogMu.Lock()
 	modelCatalogCache = cloneModelCatalog(catalog)
 	modelCatalogMu.Unlock()
+	recordModelSyncFailure(nil)
 	s.app.Logger.Info("[chatwiki] RefreshModelCatalog done",
 		"user_id", source.UserID,
 		"server_url", source.ServerURL,
@@ -234,6 +276,64 @@ func (s *ChatWikiService) GetModelCatalog(forceRefresh bool) (*ModelCatalog, err
 	return s.RefreshModelCatalog()
 }
 
+// SyncModels fetches the model catalog and syncs it to the local models table. Unlike
+// RefreshModelCatalog it never falls back to cached models, so a failed fetch is returned as an
+// error; the stats count the local rows changed by a successful sync.
+func (s *ChatWikiService) SyncModels() (ModelSyncStats, error) {
+	catalog, err := s.RefreshModelCatalog()
+	if err != nil {
+		return ModelSyncStats{}, err
+	}
+	if syncErr := LastModelSyncError(); syncErr != nil {
+		return ModelSyncStats{}, syncErr
+	}
+	return catalog.syncStats, nil
+}
+
+// lastModelSyncErr is the error of the latest catalog fetch, nil after a successful one.
+var lastModelSyncErr struct {
+	sync.Mutex
+	err error
+}
+
+func recordModelSyncFailure(err error) {
+	lastModelSyncErr.Lock()
+	lastModelSyncErr.err = err
+	lastModelSyncErr.Unlock()
+}
+
+// LastModelSyncError returns the error of the latest model catalog fetch (e.g. the startup sync
+// on a restricted network), or nil if it succeeded or none has run yet.
+func LastModelSyncError() error {
+	lastModelSyncErr.Lock()
+	defer lastModelSyncErr.Unlock()
+	return lastModelSyncErr.err
+}
+
+// RefreshModelCatalogIfStale returns the in-memory catalog when it was fetched for the current
+// binding within modelCatalogFreshTTL, and refreshes it otherwise. Used by callers that run on
+// every model list view (GetProviderWithModels) so they don't hit the ChatWiki API each time.
+func (s *ChatWikiService) RefreshModelCatalogIfStale() (*ModelCatalog, error) {
+	binding, err := s.GetBinding()
+	if err != nil {
+		return nil, err
+	}
+	source := getModelCatalogSource(binding)
+
+	modelCatalogMu.RLock()
+	cached := modelCatalogCache
+	fresh := cached != nil &&
+		cached.Bound == source.Bound &&
+		cached.BindingUserID == source.UserID &&
+		time.Since(time.Unix(cached.LoadedAtUnix, 0)) < modelCatalogFreshTTL
+	cached = cloneModelCatalog(cached)
+	modelCatalogMu.RUnlock()
+	if fresh {
+		return cached, nil
+	}
+	return s.RefreshModelCatalog()
+}
+
 func cloneModelCatalog(in *ModelCatalog) *ModelCatalog {
 	if in == nil {
 		return nil
@@ -307,10 +407,12 @@ func (s *ChatWikiService) fetchModelCatalog(source modelCatalogSource) (*ModelCa
 		s.app.Logger.Error("[chatwiki] fetchModelCatalog decode failed", "url", modelURL, "error", err)
 		return nil, err
 	}
-	if err := syncModelCatalogToLocalDB(catalog); err != nil {
+	stats, err := syncModelCatalogToLocalDB(catalog)
+	if err != nil {
 		s.app.Logger.Error("[chatwiki] fetchModelCatalog sync db failed", "url", modelURL, "error", err)
 		return nil, err
 	}
+	catalog.syncStats = stats
 	s.app.Logger.Info("[chatwiki] fetchModelCatalog decoded",
 		"llm_count", len(catalog.LLMModels),
 		"embedding_count", len(catalog.EmbeddingModels),
@@ -337,6 +439,89 @@ func (s *ChatWikiService) fetchModelCatalog(source modelCatalogSource) (*ModelCa
 	return catalog, nil
 }
 
+// fetchModelCatalogWithRetry retries transient failures (network errors, 5xx) of
+// fetchModelCatalog with a short backoff. API and decode errors are returned immediately.
+func (s *ChatWikiService) fetchModelCatalogWithRetry(source modelCatalogSource) (*ModelCatalog, error) {
+	var lastErr error
+	for attempt := 0; attempt < modelCatalogFetchAttempts; attempt++ {
+		if attempt > 0 {
+			delay := modelCatalogRetryBaseDelay << (attempt - 1)
+			s.app.Logger.Warn("[chatwiki] fetchModelCatalog retrying",
+				"attempt", attempt+1,
+				"delay_ms", delay.Milliseconds(),
+				"error", lastErr,
+			)
+			time.Sleep(delay)
+		}
+		catalog, err := s.fetchModelCatalog(source)
+		if err == nil {
+			return catalog, nil
+		}
+		lastErr = err
+		if !isTransientChatWikiError(err) {
+			break
+		}
+	}
+	return nil, lastErr
+}
+
+func isTransientChatWikiError(err error) bool {
+	var statusErr *chatWikiStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.Status >= http.StatusInternalServerError || statusErr.Status == http.StatusTooManyRequests
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// loadModelCatalogFromLocalDB rebuilds a catalog from the chatwiki rows in the local models
+// table. Returns nil when there are none. LoadedAtUnix stays 0 so it is never treated as fresh.
+func loadModelCatalogFromLocalDB(source modelCatalogSource) *ModelCatalog {
+	db := getChatWikiSyncDB()
+	if db == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows := make([]syncedModelRow, 0)
+	if err := db.NewSelect().
+		Model(&rows).
+		Where("provider_id = ?", "chatwiki").
+		OrderExpr("type ASC, sort_order ASC, id ASC").
+		Scan(ctx); err != nil || len(rows) == 0 {
+		return nil
+	}
+
+	catalog := &ModelCatalog{
+		Bound:         source.Bound,
+		BindingUserID: source.UserID,
+	}
+	for _, row := range rows {
+		var capabilities []string
+		_ = json.Unmarshal([]byte(row.Capabilities), &capabilities)
+		item := ModelCatalogItem{
+			ModelID:         row.ModelID,
+			Name:            row.Name,
+			Type:            normalizeCatalogModelType(row.Type),
+			Enabled:         row.Enabled,
+			DefaultUseModel: normalizeDefaultUseModel(row.DefaultUseModel),
+			SortOrder:       row.SortOrder,
+			Capabilities:    capabilities,
+			UniModelName:    row.ModelID,
+		}
+		switch item.Type {
+		case "embedding":
+			catalog.EmbeddingModels = append(catalog.EmbeddingModels, item)
+		case "rerank":
+			catalog.RerankModels = append(catalog.RerankModels, item)
+		default:
+			catalog.LLMModels = append(catalog.LLMModels, item)
+		}
+	}
+	return catalog
+}
+
 func decodeModelCatalogResponse(raw json.RawMessage) (*ModelCatalog, error) {
 	trimmed := strings.TrimSpace(string(raw))
 	if trimmed == "" || strings.EqualFold(trimmed, "null") {
@@ -648,13 +833,16 @@ type syncedCatalogModel struct {
 	SortOrder       int
 }
 
-func syncModelCatalogToLocalDB(catalog *ModelCatalog) error {
+func syncModelCatalogToLocalDB(catalog *ModelCatalog) (ModelSyncStats, error) {
 	return syncModelCatalogToDB(context.Background(), getChatWikiSyncDB(), "chatwiki", catalog)
 }
 
-func syncModelCatalogToDB(ctx context.Context, db *bun.DB, providerID string, catalog *ModelCatalog) error {
+// syncModelCatalogToDB replaces the provider's models with the catalog and stamps the provider's
+// last_synced_at.
+func syncModelCatalogToDB(ctx context.Context, db *bun.DB, providerID string, catalog *ModelCatalog) (ModelSyncStats, error) {
+	var stats ModelSyncStats
 	if db == nil || strings.TrimSpace(providerID) == "" || catalog == nil {
-		return nil
+		return stats, nil
 	}
 	if ctx == nil {
 		ctx = context.Background()
@@ -666,7 +854,7 @@ func syncModelCatalogToDB(ctx context.Context, db *bun.DB, providerID string, ca
 		remoteMap[item.ModelID] = item
 	}
 
-	return db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+	err := db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
 		existing := make([]syncedModelRow, 0)
 		if err := tx.NewSelect().
 			Model(&existing).
@@ -695,6 +883,7 @@ func syncModelCatalogToDB(ctx context.Context, db *bun.DB, providerID string, ca
 				return err
 			}
 		}
+		stats.Deleted = len(toDelete)
 
 		toInsert := make([]syncedModelRow, 0)
 		for _, item := range remote {
@@ -724,6 +913,7 @@ func syncModelCatalogToDB(ctx context.Context, db *bun.DB, providerID string, ca
 					Exec(ctx); err != nil {
 					return err
 				}
+				stats.Updated++
 				continue
 			}
 
@@ -745,8 +935,19 @@ func syncModelCatalogToDB(ctx context.Context, db *bun.DB, providerID string, ca
 				return err
 			}
 		}
-		return nil
+		stats.Added = len(toInsert)
+
+		_, err := tx.NewUpdate().
+			Table("providers").
+			Where("provider_id = ?", providerID).
+			Set("last_synced_at = ?", sqlite.NowUTC()).
+			Exec(ctx)
+		return err
 	})
+	if err != nil {
+		return ModelSyncStats{}, err
+	}
+	return stats, nil
 }
 
 func clearSyncedModelCatalogFromDB(ctx context.Context, db *bun.DB, providerID string) error {
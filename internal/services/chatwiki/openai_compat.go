@@ -9,6 +9,8 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"chatclaw/internal/httpclient"
 )
 
 const chatWikiModelCatalogCacheTTL = 2 * time.Minute
@@ -71,7 +73,7 @@ func loadModelCatalogForOpenAI(apiKey, apiEndpoint string) (*ModelCatalog, error
 	}
 	req.Header.Set("Token", strings.TrimSpace(apiKey))
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := httpclient.Shared().Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("request chatwiki model catalog: %w", err)
 	}
@@ -89,7 +91,7 @@ func loadModelCatalogForOpenAI(apiKey, apiEndpoint string) (*ModelCatalog, error
 	if err != nil {
 		return nil, fmt.Errorf("decode chatwiki model catalog: %w", err)
 	}
-	if err := syncModelCatalogToLocalDB(catalog); err != nil {
+	if _, err := syncModelCatalogToLocalDB(catalog); err != nil {
 		return nil, fmt.Errorf("sync chatwiki model catalog to db: %w", err)
 	}
 
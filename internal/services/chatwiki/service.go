@@ -17,6 +17,7 @@ import (
 	"time"
 
 	"chatclaw/internal/define"
+	"chatclaw/internal/httpclient"
 	"chatclaw/internal/sqlite"
 	"chatclaw/internal/sysinfo"
 
@@ -494,7 +495,7 @@ func (s *ChatWikiService) TokenForceOffline() error {
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Token", binding.Token)
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := httpclient.Shared().Do(req)
 	if err != nil {
 		s.app.Logger.Warn("[ChatWiki] tokenForceOffline request failed", "error", err)
 		return nil
@@ -619,7 +620,7 @@ func (s *ChatWikiService) callRefreshTokenAPI(binding *Binding) (newToken string
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Token", binding.Token)
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := httpclient.Shared().Do(req)
 	if err != nil {
 		return "", 0, fmt.Errorf("refresh request failed: %w", err)
 	}
@@ -707,7 +708,7 @@ func (s *ChatWikiService) getRobotList(onlyOpen int) ([]Robot, error) {
 	}
 	req.Header.Set("Token", binding.Token)
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := httpclient.Shared().Do(req)
 	if err != nil {
 		s.app.Logger.Error("[ChatWiki] getRobotList request failed", "error", err)
 		return nil, fmt.Errorf("request failed: %w", err)
@@ -961,7 +962,7 @@ func (s *ChatWikiService) runTeamChatStream(
 		req.Header.Set("Token", binding.Token)
 		req.Header.Set("AppType", "chat_claw_client")
 
-		tryResp, doErr := http.DefaultClient.Do(req)
+		tryResp, doErr := httpclient.Shared().Do(req)
 		if doErr != nil {
 			if ctx.Err() == context.Canceled {
 				stopped := emitBase()
@@ -1423,7 +1424,7 @@ func (s *ChatWikiService) getLibraryList(libType int, onlyOpen int) ([]Library,
 	}
 	req.Header.Set("Token", binding.Token)
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := httpclient.Shared().Do(req)
 	if err != nil {
 		s.app.Logger.Error("[ChatWiki] GetLibraryList request failed", "error", err)
 		return nil, fmt.Errorf("request failed: %w", err)
@@ -1819,7 +1820,7 @@ func (s *ChatWikiService) UpdateRobotSwitchStatus(id string, switchStatus int) e
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Token", binding.Token)
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := httpclient.Shared().Do(req)
 	if err != nil {
 		return fmt.Errorf("request failed: %w", err)
 	}
@@ -1892,7 +1893,7 @@ func (s *ChatWikiService) UpdateLibrarySwitchStatus(id string, switchStatus int)
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Token", binding.Token)
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := httpclient.Shared().Do(req)
 	if err != nil {
 		return fmt.Errorf("request failed: %w", err)
 	}
@@ -2033,7 +2034,7 @@ func (s *ChatWikiService) chatWikiGET(token string, apiURL string) ([]byte, erro
 	}
 	req.Header.Set("Token", token)
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := httpclient.Shared().Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
@@ -2080,7 +2081,7 @@ func (s *ChatWikiService) chatWikiGETLoose(token string, apiURL string) (json.Ra
 	}
 	req.Header.Set("Token", token)
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := httpclient.Shared().Do(req)
 	if err != nil {
 		s.app.Logger.Error("[chatwiki] GET request failed", "url", apiURL, "error", err)
 		return nil, fmt.Errorf("request failed: %w", err)
@@ -2099,7 +2100,7 @@ func (s *ChatWikiService) chatWikiGETLoose(token string, apiURL string) (json.Ra
 		"body_preview", previewChatWikiLogBody(body),
 	)
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, string(body))
+		return nil, &chatWikiStatusError{Status: resp.StatusCode, Body: string(body)}
 	}
 
 	trimmed := strings.TrimSpace(string(body))
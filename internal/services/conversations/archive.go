@@ -0,0 +1,183 @@
+package conversations
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"chatclaw/internal/errs"
+	"chatclaw/internal/services/settings"
+	"chatclaw/internal/sqlite"
+
+	"github.com/uptrace/bun"
+)
+
+// KeepRecentSettingKey holds how many recent unpinned conversations per agent stay in the sidebar;
+// older ones are archived when a new conversation is created. 0 disables auto-archiving.
+const KeepRecentSettingKey = "conversation_keep_recent"
+
+// searchConversationsLimit caps the results of SearchConversations.
+const searchConversationsLimit = 50
+
+// RestoreArchivedMessages puts the messages of a conversation archived by
+// ChatService.ArchiveConversation back into it. Set by the chat package so unarchiving here
+// restores them too; nil means there is nothing to restore.
+var RestoreArchivedMessages func(ctx context.Context, db bun.IDB, conversationID int64) error
+
+// ArchiveConversation hides a conversation from ListConversations (sets archived_at) without
+// touching its messages, so it stays searchable and opens as usual. ChatService.ArchiveConversation
+// sets the same flag and also moves the messages out of the way.
+func (s *ConversationsService) ArchiveConversation(id int64) (*Conversation, error) {
+	if id <= 0 {
+		return nil, errs.New("error.conversation_id_required")
+	}
+
+	db, err := s.db()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	// Table update so updated_at (the sidebar order) is left alone
+	res, err := db.NewUpdate().
+		Table("conversations").
+		Set("archived_at = COALESCE(archived_at, ?)", sqlite.NowUTC()).
+		Where("id = ?", id).
+		Exec(ctx)
+	if err != nil {
+		return nil, errs.Wrap("error.conversation_update_failed", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil, errs.Newf("error.conversation_not_found", map[string]any{"ID": id})
+	}
+	return s.changed(id, "archived")
+}
+
+// UnarchiveConversation clears archived_at and restores the messages archived with the
+// conversation, however it was archived.
+func (s *ConversationsService) UnarchiveConversation(id int64) (*Conversation, error) {
+	if id <= 0 {
+		return nil, errs.New("error.conversation_id_required")
+	}
+
+	db, err := s.db()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if _, err := s.GetConversation(id); err != nil {
+		return nil, err
+	}
+	if err := unarchiveConversation(ctx, db, id); err != nil {
+		return nil, errs.Wrap("error.conversation_update_failed", err)
+	}
+	return s.changed(id, "unarchived")
+}
+
+// unarchiveConversation clears archived_at and restores the archived messages in one transaction.
+func unarchiveConversation(ctx context.Context, db *bun.DB, id int64) error {
+	return db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.NewUpdate().
+			Table("conversations").
+			Set("archived_at = NULL").
+			Where("id = ?", id).
+			Exec(ctx); err != nil {
+			return err
+		}
+		if RestoreArchivedMessages == nil {
+			return nil
+		}
+		return RestoreArchivedMessages(ctx, tx, id)
+	})
+}
+
+// changed reloads a conversation after an archive change and broadcasts it.
+func (s *ConversationsService) changed(id int64, action string) (*Conversation, error) {
+	conv, err := s.GetConversation(id)
+	if err != nil {
+		return nil, err
+	}
+	s.emitChanged(conv.AgentID, conv.ID, action)
+	return conv, nil
+}
+
+// SearchConversations finds an agent's conversations by name or last message, archived ones
+// included (see Conversation.IsArchived), most recently updated first. The keyword is matched
+// literally: LIKE wildcards in it are escaped.
+func (s *ConversationsService) SearchConversations(agentID int64, agentType, keyword string) ([]Conversation, error) {
+	if agentID <= 0 {
+		return nil, errs.New("error.agent_id_required")
+	}
+	if agentType == "" {
+		agentType = AgentTypeEino
+	}
+	keyword = strings.TrimSpace(keyword)
+	if keyword == "" {
+		return []Conversation{}, nil
+	}
+
+	db, err := s.db()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	pattern := "%" + escapeLike(keyword) + "%"
+	models := make([]conversationModel, 0)
+	if err := db.NewSelect().
+		Model(&models).
+		Where("agent_id = ?", agentID).
+		Where("agent_type = ?", agentType).
+		WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Where(`name LIKE ? ESCAPE '\'`, pattern).
+				WhereOr(`last_message LIKE ? ESCAPE '\'`, pattern)
+		}).
+		OrderExpr("updated_at DESC, id DESC").
+		Limit(searchConversationsLimit).
+		Scan(ctx); err != nil {
+		return nil, errs.Wrap("error.conversation_list_failed", err)
+	}
+
+	out := make([]Conversation, 0, len(models))
+	for i := range models {
+		out = append(out, models[i].toDTO())
+	}
+	return out, nil
+}
+
+// likeEscaper escapes the LIKE wildcards for use with ESCAPE '\'.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+// autoArchiveConversations archives an agent's unpinned, visible conversations beyond the newest
+// KeepRecentSettingKey ones and returns how many were archived.
+func autoArchiveConversations(ctx context.Context, db *bun.DB, agentID int64, agentType string) (int64, error) {
+	keep := settings.GetInt(KeepRecentSettingKey, 0)
+	if keep <= 0 {
+		return 0, nil
+	}
+	res, err := db.NewRaw(`
+		UPDATE conversations SET archived_at = ?
+		WHERE id IN (
+			SELECT id FROM conversations
+			WHERE agent_id = ? AND agent_type = ? AND is_pinned = 0
+				AND archived_at IS NULL
+			ORDER BY updated_at DESC, id DESC
+			LIMIT -1 OFFSET ?
+		)
+	`, sqlite.NowUTC(), agentID, agentType, keep).Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
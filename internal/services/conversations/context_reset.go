@@ -0,0 +1,70 @@
+package conversations
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"chatclaw/internal/errs"
+)
+
+// ResetContext starts a fresh model context without clearing the transcript: the marker is set to
+// the conversation's latest message, and only later messages (plus system messages) are sent to the
+// model. The UI keeps showing the full history.
+func (s *ConversationsService) ResetContext(conversationID int64) (*Conversation, error) {
+	if conversationID <= 0 {
+		return nil, errs.New("error.conversation_id_required")
+	}
+
+	db, err := s.db()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var latestID sql.NullInt64
+	if err := db.NewSelect().
+		Table("messages").
+		ColumnExpr("MAX(id)").
+		Where("conversation_id = ?", conversationID).
+		Scan(ctx, &latestID); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, errs.Wrap("error.conversation_read_failed", err)
+	}
+
+	return s.setContextReset(ctx, conversationID, latestID.Int64)
+}
+
+// ClearContextReset undoes ResetContext so the whole history is sent to the model again.
+func (s *ConversationsService) ClearContextReset(conversationID int64) (*Conversation, error) {
+	if conversationID <= 0 {
+		return nil, errs.New("error.conversation_id_required")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	return s.setContextReset(ctx, conversationID, 0)
+}
+
+func (s *ConversationsService) setContextReset(ctx context.Context, conversationID, messageID int64) (*Conversation, error) {
+	db, err := s.db()
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := db.NewUpdate().
+		Model((*conversationModel)(nil)).
+		Set("context_reset_at_message_id = ?", messageID).
+		Where("id = ?", conversationID).
+		Exec(ctx)
+	if err != nil {
+		return nil, errs.Wrap("error.conversation_update_failed", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil, errs.Newf("error.conversation_not_found", map[string]any{"ID": conversationID})
+	}
+	return s.GetConversation(conversationID)
+}
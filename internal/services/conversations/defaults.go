@@ -0,0 +1,82 @@
+package conversations
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"chatclaw/internal/services/i18n"
+	"chatclaw/internal/services/settings"
+
+	"github.com/uptrace/bun"
+)
+
+// applyConversationDefaults fills an empty agent and provider/model of a new eino conversation from
+// the default_agent_id and default_chat_model ("provider::model") settings. Defaults that point at a
+// deleted agent, a disabled provider or a missing/disabled model are skipped; the returned warning
+// (localized, empty when all good) tells the user why so the conversation falls back to the agent's
+// own default model instead of failing at first send.
+func applyConversationDefaults(ctx context.Context, db *bun.DB, input *CreateConversationInput, agentType, teamType string) (string, error) {
+	if agentType != AgentTypeEino || teamType == TeamTypeTeam {
+		return "", nil
+	}
+	var warnings []string
+
+	if input.AgentID <= 0 {
+		if raw, ok := settings.GetValue("default_agent_id"); ok && strings.TrimSpace(raw) != "" {
+			agentID, _ := strconv.ParseInt(strings.TrimSpace(raw), 10, 64)
+			cnt := 0
+			if agentID > 0 {
+				var err error
+				cnt, err = db.NewSelect().Table("agents").Where("id = ?", agentID).Count(ctx)
+				if err != nil {
+					return "", err
+				}
+			}
+			if cnt > 0 {
+				input.AgentID = agentID
+			} else {
+				warnings = append(warnings, i18n.T("conversation.default_agent_unavailable"))
+			}
+		}
+	}
+
+	if strings.TrimSpace(input.LLMProviderID) == "" && strings.TrimSpace(input.LLMModelID) == "" {
+		if raw, ok := settings.GetValue("default_chat_model"); ok && strings.TrimSpace(raw) != "" {
+			providerID, modelID, _ := strings.Cut(strings.TrimSpace(raw), "::")
+			usable, err := LLMModelUsable(ctx, db, providerID, modelID)
+			if err != nil {
+				return "", err
+			}
+			if usable {
+				input.LLMProviderID = providerID
+				input.LLMModelID = modelID
+			} else {
+				warnings = append(warnings, i18n.Tf("conversation.default_model_unavailable", map[string]any{"Model": raw}))
+			}
+		}
+	}
+
+	return strings.Join(warnings, "\n"), nil
+}
+
+// LLMModelUsable reports whether modelID is an enabled LLM of an enabled provider.
+// Also used by chat to catch conversations pinned to a deleted or disabled model.
+func LLMModelUsable(ctx context.Context, db *bun.DB, providerID, modelID string) (bool, error) {
+	if providerID == "" || modelID == "" {
+		return false, nil
+	}
+	cnt, err := db.NewSelect().
+		TableExpr("models AS m").
+		Join("JOIN providers AS p ON p.provider_id = m.provider_id").
+		Where("m.provider_id = ?", providerID).
+		Where("m.model_id = ?", modelID).
+		Where("m.type = ?", "llm").
+		Where("m.enabled = ?", true).
+		Where("p.enabled = ?", true).
+		Count(ctx)
+	if err != nil {
+		return false, err
+	}
+	return cnt > 0, nil
+}
@@ -0,0 +1,190 @@
+package conversations
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"chatclaw/internal/errs"
+
+	"github.com/uptrace/bun"
+)
+
+// EventConversationCreated is emitted with the new Conversation when a conversation is created by the backend.
+const EventConversationCreated = "conversation:created"
+
+// ForkConversation creates a new conversation that contains the messages of conversationID up to
+// and including fromMessageID, so an alternate direction can be explored without losing the
+// original thread. Agent, model, knowledge libraries and chat settings (including max iterations) are copied; the pin state,
+// external ID and OpenClaw session are not. A context reset is kept at the same point of the copied
+// history. Returns the new conversation ID.
+func (s *ConversationsService) ForkConversation(conversationID, fromMessageID int64) (int64, error) {
+	if conversationID <= 0 {
+		return 0, errs.New("error.conversation_id_required")
+	}
+	if fromMessageID <= 0 {
+		return 0, errs.New("error.chat_message_not_found")
+	}
+
+	db, err := s.db()
+	if err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var src conversationModel
+	if err := db.NewSelect().Model(&src).Where("id = ?", conversationID).Limit(1).Scan(ctx); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, errs.Newf("error.conversation_not_found", map[string]any{"ID": conversationID})
+		}
+		return 0, errs.Wrap("error.conversation_read_failed", err)
+	}
+
+	var lastContent string
+	if err := db.NewSelect().
+		Table("messages").
+		Column("content").
+		Where("id = ?", fromMessageID).
+		Where("conversation_id = ?", conversationID).
+		Scan(ctx, &lastContent); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, errs.New("error.chat_message_not_found")
+		}
+		return 0, errs.Wrap("error.conversation_read_failed", err)
+	}
+	lastMessage := strings.TrimSpace(lastContent)
+	if runes := []rune(lastMessage); len(runes) > 100 {
+		lastMessage = string(runes[:100])
+	}
+
+	fork := &conversationModel{
+		AgentID:        src.AgentID,
+		AgentType:      src.AgentType,
+		Name:           src.Name,
+		LastMessage:    lastMessage,
+		LLMProviderID:  src.LLMProviderID,
+		LLMModelID:     src.LLMModelID,
+		LibraryIDs:     src.LibraryIDs,
+		EnableThinking: src.EnableThinking,
+		ShowThinking:   src.ShowThinking,
+		ChatMode:       src.ChatMode,
+		TeamType:       src.TeamType,
+		DialogueID:     src.DialogueID,
+		TeamLibraryID:  src.TeamLibraryID,
+		MaxIterations:  src.MaxIterations,
+		ThinkingBudget: src.ThinkingBudget,
+
+		LLMTemperature:       src.LLMTemperature,
+		LLMTopP:              src.LLMTopP,
+		LLMMaxTokens:         src.LLMMaxTokens,
+		EnableLLMTemperature: src.EnableLLMTemperature,
+		EnableLLMTopP:        src.EnableLLMTopP,
+		EnableLLMMaxTokens:   src.EnableLLMMaxTokens,
+	}
+
+	err = db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.NewInsert().Model(fork).Exec(ctx); err != nil {
+			return err
+		}
+		if err := copyMessages(ctx, tx, conversationID, fork.ID, fromMessageID); err != nil {
+			return err
+		}
+		return copyContextReset(ctx, tx, &src, fork, fromMessageID)
+	})
+	if err != nil {
+		return 0, errs.Wrap("error.conversation_fork_failed", err)
+	}
+
+	s.app.Logger.Info("[conversations] forked conversation", "from", conversationID, "message", fromMessageID, "to", fork.ID)
+	s.app.Event.Emit(EventConversationCreated, fork.toDTO())
+	return fork.ID, nil
+}
+
+// forkSkippedMessageColumns are not copied into a fork: ids are new, and ratings stay with the
+// original reply so a forked answer is not counted twice in feedback reviews.
+var forkSkippedMessageColumns = map[string]bool{
+	"id":              true,
+	"conversation_id": true,
+	"rating":          true,
+	"feedback_note":   true,
+	"rated_at":        true,
+}
+
+// copyContextReset carries a context reset over to the fork. Copied messages get new ids, so the
+// marker is moved to the copy of the last message at or before the original marker.
+func copyContextReset(ctx context.Context, tx bun.Tx, src, fork *conversationModel, upToID int64) error {
+	marker := min(src.ContextResetAtMessageID, upToID)
+	if marker <= 0 {
+		return nil
+	}
+	var kept int
+	if err := tx.NewSelect().
+		Table("messages").
+		ColumnExpr("COUNT(*)").
+		Where("conversation_id = ?", src.ID).
+		Where("id <= ?", marker).
+		Scan(ctx, &kept); err != nil {
+		return err
+	}
+	if kept == 0 {
+		return nil
+	}
+	var forkMarker int64
+	if err := tx.NewSelect().
+		Table("messages").
+		Column("id").
+		Where("conversation_id = ?", fork.ID).
+		OrderExpr("id ASC").
+		Offset(kept-1).
+		Limit(1).
+		Scan(ctx, &forkMarker); err != nil {
+		return err
+	}
+	if _, err := tx.NewUpdate().
+		Table("conversations").
+		Set("context_reset_at_message_id = ?", forkMarker).
+		Where("id = ?", fork.ID).
+		Exec(ctx); err != nil {
+		return err
+	}
+	fork.ContextResetAtMessageID = forkMarker
+	return nil
+}
+
+// copyMessages duplicates messages (id <= upToID) into another conversation, keeping every column
+// except forkSkippedMessageColumns. Columns are read from the live schema so later migrations that add
+// message fields are copied too.
+func copyMessages(ctx context.Context, tx bun.Tx, fromConversationID, toConversationID, upToID int64) error {
+	rows, err := tx.QueryContext(ctx, `SELECT name FROM pragma_table_info('messages')`)
+	if err != nil {
+		return err
+	}
+	var columns []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return err
+		}
+		if !forkSkippedMessageColumns[name] {
+			columns = append(columns, name)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	cols := strings.Join(columns, ", ")
+	stmt := fmt.Sprintf(
+		"INSERT INTO messages (conversation_id, %s) SELECT ?, %s FROM messages WHERE conversation_id = ? AND id <= ? ORDER BY id ASC",
+		cols, cols,
+	)
+	_, err = tx.ExecContext(ctx, stmt, toConversationID, fromConversationID, upToID)
+	return err
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"chatclaw/internal/fts/tokenizer"
 	"chatclaw/internal/sqlite"
 
 	"github.com/uptrace/bun"
@@ -24,6 +25,25 @@ const (
 	ChatModeTask = "task" // ReAct agent with tool calling
 )
 
+// MaxIterationsLimit caps the per-conversation ReAct iteration limit (0 = unlimited).
+const MaxIterationsLimit = 500
+
+// ValidMaxIterations reports whether n is an accepted max_iterations value.
+func ValidMaxIterations(n int) bool {
+	return n >= 0 && n <= MaxIterationsLimit
+}
+
+// Thinking budget range in tokens; 0 falls back to the agent's budget, then the provider default.
+const (
+	MinThinkingBudget = 1024
+	MaxThinkingBudget = 32768
+)
+
+// ValidThinkingBudget reports whether n is an accepted thinking_budget value.
+func ValidThinkingBudget(n int) bool {
+	return n == 0 || (n >= MinThinkingBudget && n <= MaxThinkingBudget)
+}
+
 // TeamType constants
 const (
 	TeamTypePerson = "person"
@@ -74,14 +94,34 @@ type Conversation struct {
 	LLMModelID         string  `json:"llm_model_id"`
 	LibraryIDs         []int64 `json:"library_ids"`
 	EnableThinking     bool    `json:"enable_thinking"`
+	ShowThinking       bool    `json:"show_thinking"` // display only; reasoning is stored either way
 	OpenClawSessionKey string  `json:"openclaw_session_key"`
 	ChatMode           string  `json:"chat_mode"`
 	TeamType           string  `json:"team_type"`
 	DialogueID         int64   `json:"dialogue_id"`     // team mode only
 	TeamLibraryID      string  `json:"team_library_id"` // optional: ChatWiki team library id for recall
+	MaxIterations      int     `json:"max_iterations"`  // task mode tool round-trip limit; 0 = unlimited
+	ThinkingBudget     int     `json:"thinking_budget"` // thinking tokens; 0 = agent's value, else provider default
+
+	// Messages with id <= this stay visible but are not sent to the model; 0 = full history
+	ContextResetAtMessageID int64 `json:"context_reset_at_message_id"`
+
+	// Sampling overrides; each applies only while its Enable flag is set (else the agent's value)
+	LLMTemperature       float64 `json:"llm_temperature"`
+	LLMTopP              float64 `json:"llm_top_p"`
+	LLMMaxTokens         int     `json:"llm_max_tokens"`
+	EnableLLMTemperature bool    `json:"enable_llm_temperature"`
+	EnableLLMTopP        bool    `json:"enable_llm_top_p"`
+	EnableLLMMaxTokens   bool    `json:"enable_llm_max_tokens"`
+
+	ArchivedAt *time.Time `json:"archived_at,omitempty"` // set while the conversation is archived
+	IsArchived bool       `json:"is_archived"`           // ArchivedAt != nil
 
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+
+	// Warning is set only by CreateConversation when a configured default agent/model was unusable
+	Warning string `json:"warning,omitempty"`
 }
 
 // CreateConversationInput 创建会话的输入参数
@@ -95,11 +135,40 @@ type CreateConversationInput struct {
 	LLMModelID         string  `json:"llm_model_id"`
 	LibraryIDs         []int64 `json:"library_ids"`
 	EnableThinking     bool    `json:"enable_thinking"`
+	ShowThinking       *bool   `json:"show_thinking"` // optional: nil = true
 	OpenClawSessionKey string  `json:"openclaw_session_key"`
 	ChatMode           string  `json:"chat_mode"`
 	TeamType           string  `json:"team_type"`
 	DialogueID         int64   `json:"dialogue_id"`     // team mode only, default 0
 	TeamLibraryID      string  `json:"team_library_id"` // optional: ChatWiki team library id for recall
+	MaxIterations      int     `json:"max_iterations"`  // optional: 0 = unlimited
+	ThinkingBudget     int     `json:"thinking_budget"` // optional: 0 = agent's value
+}
+
+// ListConversationsPageInput 会话分页查询输入参数（cursor 分页）
+// - BeforeID: 上一页最后一条的 id，返回排在它之后的数据
+// - Limit: 每次返回条数（默认/最大 100）
+// - SortBy: 排序方式（"updated_desc" 或 "created_desc"），默认 "updated_desc"
+// - Keyword: 按会话名称全文检索
+type ListConversationsPageInput struct {
+	AgentID   int64  `json:"agent_id"`
+	AgentType string `json:"agent_type"`
+	Keyword   string `json:"keyword"`
+	BeforeID  int64  `json:"before_id"`
+	Limit     int    `json:"limit"`
+	SortBy    string `json:"sort_by"`
+}
+
+// ConversationSummary 会话列表项（ListConversationsPage 返回）
+type ConversationSummary struct {
+	ID           int64     `json:"id"`
+	Name         string    `json:"name"`
+	LastMessage  string    `json:"last_message"` // 预览，最多 conversationPreviewRunes 个字符
+	IsPinned     bool      `json:"is_pinned"`
+	IsArchived   bool      `json:"is_archived"` // archived_at 不为空
+	MessageCount int       `json:"message_count"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
 }
 
 // UpdateConversationInput 更新会话的输入参数
@@ -111,10 +180,23 @@ type UpdateConversationInput struct {
 	LLMModelID     *string  `json:"llm_model_id"`
 	LibraryIDs     *[]int64 `json:"library_ids"`
 	EnableThinking *bool    `json:"enable_thinking"`
+	ShowThinking   *bool    `json:"show_thinking"`
 	ChatMode       *string  `json:"chat_mode"`
 	TeamType       *string  `json:"team_type"`
 	DialogueID     *int64   `json:"dialogue_id"`     // team mode only
 	TeamLibraryID  *string  `json:"team_library_id"` // optional
+	MaxIterations  *int     `json:"max_iterations"`  // 0 = unlimited
+	ThinkingBudget *int     `json:"thinking_budget"` // 0 = agent's value
+}
+
+// UpdateConversationParamsInput 更新会话采样参数的输入参数（nil 表示不修改）
+type UpdateConversationParamsInput struct {
+	LLMTemperature       *float64 `json:"llm_temperature"`
+	LLMTopP              *float64 `json:"llm_top_p"`
+	LLMMaxTokens         *int     `json:"llm_max_tokens"`
+	EnableLLMTemperature *bool    `json:"enable_llm_temperature"`
+	EnableLLMTopP        *bool    `json:"enable_llm_top_p"`
+	EnableLLMMaxTokens   *bool    `json:"enable_llm_max_tokens"`
 }
 
 // conversationModel 数据库模型
@@ -135,17 +217,40 @@ type conversationModel struct {
 	LLMModelID         string `bun:"llm_model_id,notnull"`
 	LibraryIDs         string `bun:"library_ids,notnull"` // JSON array stored as string
 	EnableThinking     bool   `bun:"enable_thinking,notnull"`
+	ShowThinking       bool   `bun:"show_thinking,notnull"`
 	OpenClawSessionKey string `bun:"openclaw_session_key,notnull"`
 	ChatMode           string `bun:"chat_mode,notnull"`
 	TeamType           string `bun:"team_type,notnull"`
 	DialogueID         int64  `bun:"dialogue_id,notnull"`     // team mode only, default 0
 	TeamLibraryID      string `bun:"team_library_id,notnull"` // optional, default ''
+	MaxIterations      int    `bun:"max_iterations,notnull"`  // 0 = unlimited
+	ThinkingBudget     int    `bun:"thinking_budget,notnull"` // 0 = agent's value
+
+	ContextResetAtMessageID int64 `bun:"context_reset_at_message_id,notnull"` // 0 = full history
+
+	LLMTemperature       float64 `bun:"llm_temperature,notnull"`
+	LLMTopP              float64 `bun:"llm_top_p,notnull"`
+	LLMMaxTokens         int     `bun:"llm_max_tokens,notnull"`
+	EnableLLMTemperature bool    `bun:"enable_llm_temperature,notnull"`
+	EnableLLMTopP        bool    `bun:"enable_llm_top_p,notnull"`
+	EnableLLMMaxTokens   bool    `bun:"enable_llm_max_tokens,notnull"`
+
+	ArchivedAt *time.Time `bun:"archived_at"`
+
+	NameTokens string `bun:"name_tokens,notnull"` // pre-tokenized name for conv_name_fts
 }
 
 // BeforeInsert 在 INSERT 时自动设置 created_at 和 updated_at
 var _ bun.BeforeInsertHook = (*conversationModel)(nil)
 
-func (*conversationModel) BeforeInsert(ctx context.Context, query *bun.InsertQuery) error {
+func (m *conversationModel) BeforeInsert(ctx context.Context, query *bun.InsertQuery) error {
+	// Sampling overrides left unset start from the same defaults as the column definitions
+	if m.LLMTemperature == 0 && m.LLMTopP == 0 && m.LLMMaxTokens == 0 {
+		m.LLMTemperature = defaultLLMTemperature
+		m.LLMTopP = defaultLLMTopP
+		m.LLMMaxTokens = defaultLLMMaxTokens
+	}
+	m.NameTokens = tokenizer.TokenizeTitle(m.Name)
 	now := sqlite.NowUTC()
 	query.Value("created_at", "?", now)
 	query.Value("updated_at", "?", now)
@@ -201,11 +306,26 @@ func (m *conversationModel) toDTO() Conversation {
 		LLMModelID:         m.LLMModelID,
 		LibraryIDs:         libraryIDs,
 		EnableThinking:     m.EnableThinking,
+		ShowThinking:       m.ShowThinking,
 		OpenClawSessionKey: m.OpenClawSessionKey,
 		ChatMode:           chatMode,
 		TeamType:           teamType,
 		DialogueID:         m.DialogueID,
 		TeamLibraryID:      m.TeamLibraryID,
+		MaxIterations:      m.MaxIterations,
+		ThinkingBudget:     m.ThinkingBudget,
+
+		ContextResetAtMessageID: m.ContextResetAtMessageID,
+
+		LLMTemperature:       m.LLMTemperature,
+		LLMTopP:              m.LLMTopP,
+		LLMMaxTokens:         m.LLMMaxTokens,
+		EnableLLMTemperature: m.EnableLLMTemperature,
+		EnableLLMTopP:        m.EnableLLMTopP,
+		EnableLLMMaxTokens:   m.EnableLLMMaxTokens,
+
+		ArchivedAt: m.ArchivedAt,
+		IsArchived: m.ArchivedAt != nil,
 
 		CreatedAt: m.CreatedAt,
 		UpdatedAt: m.UpdatedAt,
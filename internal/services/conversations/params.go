@@ -0,0 +1,73 @@
+package conversations
+
+import (
+	"context"
+	"time"
+
+	"chatclaw/internal/errs"
+)
+
+// Column defaults of the per-conversation sampling overrides (same as a new agent's).
+const (
+	defaultLLMTemperature = 0.5
+	defaultLLMTopP        = 1.0
+	defaultLLMMaxTokens   = 1000
+)
+
+// UpdateConversationParams updates the sampling overrides of a conversation. An override is used
+// instead of the agent's setting only while its enable flag is on; precedence at generation time is
+// conversation > agent > provider default.
+func (s *ConversationsService) UpdateConversationParams(conversationID int64, input UpdateConversationParamsInput) (*Conversation, error) {
+	if conversationID <= 0 {
+		return nil, errs.New("error.conversation_id_required")
+	}
+	if input.LLMTemperature != nil && (*input.LLMTemperature < 0 || *input.LLMTemperature > 2) {
+		return nil, errs.Newf("error.conversation_params_invalid", map[string]any{"Param": "llm_temperature"})
+	}
+	if input.LLMTopP != nil && (*input.LLMTopP < 0 || *input.LLMTopP > 1) {
+		return nil, errs.Newf("error.conversation_params_invalid", map[string]any{"Param": "llm_top_p"})
+	}
+	if input.LLMMaxTokens != nil && *input.LLMMaxTokens < 1 {
+		return nil, errs.Newf("error.conversation_params_invalid", map[string]any{"Param": "llm_max_tokens"})
+	}
+
+	db, err := s.db()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	q := db.NewUpdate().
+		Model((*conversationModel)(nil)).
+		Where("id = ?", conversationID)
+	if input.LLMTemperature != nil {
+		q = q.Set("llm_temperature = ?", *input.LLMTemperature)
+	}
+	if input.LLMTopP != nil {
+		q = q.Set("llm_top_p = ?", *input.LLMTopP)
+	}
+	if input.LLMMaxTokens != nil {
+		q = q.Set("llm_max_tokens = ?", *input.LLMMaxTokens)
+	}
+	if input.EnableLLMTemperature != nil {
+		q = q.Set("enable_llm_temperature = ?", *input.EnableLLMTemperature)
+	}
+	if input.EnableLLMTopP != nil {
+		q = q.Set("enable_llm_top_p = ?", *input.EnableLLMTopP)
+	}
+	if input.EnableLLMMaxTokens != nil {
+		q = q.Set("enable_llm_max_tokens = ?", *input.EnableLLMMaxTokens)
+	}
+
+	res, err := q.Exec(ctx)
+	if err != nil {
+		return nil, errs.Wrap("error.conversation_update_failed", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil, errs.Newf("error.conversation_not_found", map[string]any{"ID": conversationID})
+	}
+
+	return s.GetConversation(conversationID)
+}
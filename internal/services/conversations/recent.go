@@ -0,0 +1,53 @@
+package conversations
+
+import (
+	"context"
+	"time"
+
+	"chatclaw/internal/errs"
+)
+
+const (
+	// DefaultRecentLimit 托盘菜单等快捷入口默认展示的最近会话条数
+	DefaultRecentLimit = 5
+	// maxRecentLimit ListRecentConversations 单次最多返回的条数
+	maxRecentLimit = 20
+)
+
+// ListRecentConversations 获取所有助手下最近更新的会话（按 updated_at 倒序），用于托盘菜单等快捷入口。
+// 只包含主窗口可直接打开的普通会话：不含已归档、频道会话（external_id）和 OpenClaw 会话。
+// limit <= 0 时使用 DefaultRecentLimit。
+func (s *ConversationsService) ListRecentConversations(limit int) ([]Conversation, error) {
+	if limit <= 0 {
+		limit = DefaultRecentLimit
+	}
+	if limit > maxRecentLimit {
+		limit = maxRecentLimit
+	}
+
+	db, err := s.db()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	models := make([]conversationModel, 0, limit)
+	if err := db.NewSelect().
+		Model(&models).
+		Where("agent_type = ?", AgentTypeEino).
+		Where("external_id = ''").
+		Where("archived_at IS NULL").
+		OrderExpr("updated_at DESC, id DESC").
+		Limit(limit).
+		Scan(ctx); err != nil {
+		return nil, errs.Wrap("error.conversation_list_failed", err)
+	}
+
+	out := make([]Conversation, 0, len(models))
+	for i := range models {
+		out = append(out, models[i].toDTO())
+	}
+	return out, nil
+}
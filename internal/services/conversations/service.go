@@ -5,10 +5,12 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"strings"
 	"time"
 
 	"chatclaw/internal/errs"
+	"chatclaw/internal/fts/tokenizer"
 	"chatclaw/internal/services/channels"
 	"chatclaw/internal/sqlite"
 
@@ -16,6 +18,18 @@ import (
 	"github.com/wailsapp/wails/v3/pkg/application"
 )
 
+// EventConversationsChanged 助手侧边栏据此刷新会话列表（托盘菜单也会刷新最近会话）。
+// 本服务创建、修改、归档或删除会话后发出 ConversationChangedEvent；
+// 前端标签页新建/重命名会话、chat 包生成标题或批量清理时也会发出同名事件
+const EventConversationsChanged = "conversations:changed"
+
+// ConversationChangedEvent EventConversationsChanged 的负载
+type ConversationChangedEvent struct {
+	AgentID        int64  `json:"agent_id"`
+	ConversationID int64  `json:"conversation_id,omitempty"` // 0: 该助手的全部会话
+	Action         string `json:"action"`                    // created / updated / archived / unarchived / deleted
+}
+
 // ConversationsService 会话服务（暴露给前端调用）
 type ConversationsService struct {
 	app *application.App
@@ -25,6 +39,15 @@ func NewConversationsService(app *application.App) *ConversationsService {
 	return &ConversationsService{app: app}
 }
 
+// emitChanged 广播 EventConversationsChanged
+func (s *ConversationsService) emitChanged(agentID, conversationID int64, action string) {
+	s.app.Event.Emit(EventConversationsChanged, ConversationChangedEvent{
+		AgentID:        agentID,
+		ConversationID: conversationID,
+		Action:         action,
+	})
+}
+
 func (s *ConversationsService) db() (*bun.DB, error) {
 	db := sqlite.DB()
 	if db == nil {
@@ -48,7 +71,7 @@ func (s *ConversationsService) serializeLibraryIDs(ids []int64) string {
 }
 
 // ListConversations 获取指定助手的会话列表（置顶优先，然后按更新时间倒序）
-// agentType 为空时默认过滤 "eino" 类型会话。
+// agentType 为空时默认过滤 "eino" 类型会话；已归档的会话不在列表中，见 ListArchivedConversations。
 func (s *ConversationsService) ListConversations(agentID int64, agentType string) ([]Conversation, error) {
 	if agentID <= 0 {
 		return nil, errs.New("error.agent_id_required")
@@ -71,6 +94,7 @@ func (s *ConversationsService) ListConversations(agentID int64, agentType string
 		Model(&models).
 		Where("agent_id = ?", agentID).
 		Where("agent_type = ?", agentType).
+		Where("archived_at IS NULL").
 		OrderExpr("is_pinned DESC, updated_at DESC, id DESC").
 		Scan(ctx); err != nil {
 		return nil, errs.Wrap("error.conversation_list_failed", err)
@@ -86,6 +110,147 @@ func (s *ConversationsService) ListConversations(agentID int64, agentType string
 	return out, nil
 }
 
+// ListArchivedConversations 获取助手下已归档（archived_at 不为空）的会话，按归档时间倒序
+func (s *ConversationsService) ListArchivedConversations(agentID int64, agentType string) ([]Conversation, error) {
+	if agentID <= 0 {
+		return nil, errs.New("error.agent_id_required")
+	}
+	if agentType == "" {
+		agentType = AgentTypeEino
+	}
+
+	db, err := s.db()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	models := make([]conversationModel, 0)
+	if err := db.NewSelect().
+		Model(&models).
+		Where("agent_id = ?", agentID).
+		Where("agent_type = ?", agentType).
+		Where("archived_at IS NOT NULL").
+		OrderExpr("archived_at DESC, id DESC").
+		Scan(ctx); err != nil {
+		return nil, errs.Wrap("error.conversation_list_failed", err)
+	}
+
+	out := make([]Conversation, 0, len(models))
+	for i := range models {
+		out = append(out, models[i].toDTO())
+	}
+	return out, nil
+}
+
+const (
+	// conversationPageLimit 分页查询每页的默认/最大条数
+	conversationPageLimit = 100
+	// conversationPreviewRunes 会话摘要中最后一条消息的预览长度
+	conversationPreviewRunes = 100
+)
+
+// ListConversationsPage 获取助手的会话分页（cursor 分页），用于会话很多时的列表与查找
+// - 无关键词时：不含已归档会话，按 sort_by 排序，支持 before_id 游标分页
+//   - "updated_desc"（默认）: updated_at DESC, id DESC；before_id 为上一页最后一条的 id
+//   - "created_desc": id DESC；before_id 为上一页最小 id
+//
+// - 有关键词时：按会话名称全文检索（含已归档会话），BM25 相关度排序，不使用 before_id，sort_by 被忽略
+// - 每次返回 limit（默认/最大 100）
+func (s *ConversationsService) ListConversationsPage(input ListConversationsPageInput) ([]ConversationSummary, error) {
+	if input.AgentID <= 0 {
+		return nil, errs.New("error.agent_id_required")
+	}
+	agentType := strings.TrimSpace(input.AgentType)
+	if agentType == "" {
+		agentType = AgentTypeEino
+	}
+
+	limit := input.Limit
+	if limit <= 0 || limit > conversationPageLimit {
+		limit = conversationPageLimit
+	}
+
+	db, err := s.db()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	type summaryRow struct {
+		ID           int64     `bun:"id"`
+		Name         string    `bun:"name"`
+		LastMessage  string    `bun:"last_message"`
+		IsPinned     bool      `bun:"is_pinned"`
+		IsArchived   bool      `bun:"is_archived"`
+		MessageCount int       `bun:"message_count"`
+		CreatedAt    time.Time `bun:"created_at"`
+		UpdatedAt    time.Time `bun:"updated_at"`
+	}
+	q := db.NewSelect().
+		TableExpr("conversations AS c").
+		ColumnExpr("c.id, c.name, c.last_message, c.is_pinned, c.created_at, c.updated_at").
+		ColumnExpr("(c.archived_at IS NOT NULL) AS is_archived").
+		ColumnExpr("(SELECT COUNT(1) FROM messages m WHERE m.conversation_id = c.id) AS message_count").
+		Where("c.agent_id = ?", input.AgentID).
+		Where("c.agent_type = ?", agentType).
+		Limit(limit)
+
+	keyword := strings.TrimSpace(input.Keyword)
+	if keyword != "" {
+		matchQuery := tokenizer.BuildMatchQuery(keyword)
+		if matchQuery == "" {
+			return []ConversationSummary{}, nil
+		}
+		// FTS5 syntax: (keyword tokens) AND agent_id:value
+		ftsMatch := fmt.Sprintf("(%s) AND agent_id:%d", matchQuery, input.AgentID)
+		q = q.Join("INNER JOIN conv_name_fts ON conv_name_fts.rowid = c.id").
+			Where("conv_name_fts MATCH ?", ftsMatch).
+			OrderExpr("conv_name_fts.rank, c.id DESC")
+	} else {
+		q = q.Where("c.archived_at IS NULL")
+		if input.SortBy == "created_desc" {
+			if input.BeforeID > 0 {
+				q = q.Where("c.id < ?", input.BeforeID)
+			}
+			q = q.OrderExpr("c.id DESC")
+		} else {
+			if input.BeforeID > 0 {
+				q = q.Where("(c.updated_at, c.id) < (SELECT updated_at, id FROM conversations WHERE id = ?)", input.BeforeID)
+			}
+			q = q.OrderExpr("c.updated_at DESC, c.id DESC")
+		}
+	}
+
+	rows := make([]summaryRow, 0, limit)
+	if err := q.Scan(ctx, &rows); err != nil {
+		return nil, errs.Wrap("error.conversation_list_failed", err)
+	}
+
+	out := make([]ConversationSummary, 0, len(rows))
+	for _, r := range rows {
+		preview := r.LastMessage
+		if runes := []rune(preview); len(runes) > conversationPreviewRunes {
+			preview = string(runes[:conversationPreviewRunes])
+		}
+		out = append(out, ConversationSummary{
+			ID:           r.ID,
+			Name:         r.Name,
+			LastMessage:  preview,
+			IsPinned:     r.IsPinned,
+			IsArchived:   r.IsArchived,
+			MessageCount: r.MessageCount,
+			CreatedAt:    r.CreatedAt,
+			UpdatedAt:    r.UpdatedAt,
+		})
+	}
+	return out, nil
+}
+
 // dedupeChannelScopedConversations collapses rows that share the same canonical channel
 // external_id (e.g. ch:2:group:xxx) after fixing case-sensitivity; keeps the first row in
 // sort order (pinned / newest first).
@@ -173,6 +338,12 @@ func (s *ConversationsService) FindOrCreateByExternalID(agentID int64, externalI
 		Scan(ctx)
 
 	if findErr == nil && m.ID > 0 {
+		// A channel conversation with new activity comes back from the archive.
+		if m.ArchivedAt != nil {
+			if err := unarchiveConversation(ctx, db, m.ID); err != nil {
+				s.app.Logger.Warn("[conversations] failed to unarchive channel conversation", "id", m.ID, "error", err)
+			}
+		}
 		// Migrate legacy mixed-case external_id to canonical form.
 		if strings.TrimSpace(m.ExternalID) != externalID {
 			_, _ = db.NewUpdate().
@@ -260,6 +431,12 @@ func (s *ConversationsService) CreateConversation(input CreateConversationInput)
 	if !ok {
 		return nil, errs.Newf("error.conversation_team_type_invalid", map[string]any{"TeamType": input.TeamType})
 	}
+	if !ValidMaxIterations(input.MaxIterations) {
+		return nil, errs.Newf("error.conversation_max_iterations_invalid", map[string]any{"Max": MaxIterationsLimit})
+	}
+	if !ValidThinkingBudget(input.ThinkingBudget) {
+		return nil, errs.Newf("error.conversation_thinking_budget_invalid", map[string]any{"Min": MinThinkingBudget, "Max": MaxThinkingBudget})
+	}
 	s.app.Logger.Info(
 		"[conversations] CreateConversation request",
 		"agent_id", input.AgentID,
@@ -269,14 +446,22 @@ func (s *ConversationsService) CreateConversation(input CreateConversationInput)
 		"name", name,
 	)
 
-	if input.AgentID <= 0 {
-		return nil, errs.New("error.agent_id_required")
-	}
-
 	agentType := strings.TrimSpace(input.AgentType)
 	if agentType == "" {
 		agentType = AgentTypeEino
 	}
+
+	warning, err := applyConversationDefaults(ctx, db, &input, agentType, teamType)
+	if err != nil {
+		return nil, errs.Wrap("error.conversation_create_failed", err)
+	}
+	if warning != "" {
+		s.app.Logger.Warn("[conversations] default agent/model skipped", "warning", warning)
+	}
+
+	if input.AgentID <= 0 {
+		return nil, errs.New("error.agent_id_required")
+	}
 	// Team conversations use virtual agent groups; OpenClaw conversations reference openclaw_agents table.
 	// Only validate against the agents table for standard eino agents.
 	if teamType != TeamTypeTeam && agentType != AgentTypeOpenClaw {
@@ -310,18 +495,31 @@ func (s *ConversationsService) CreateConversation(input CreateConversationInput)
 		LLMModelID:         strings.TrimSpace(input.LLMModelID),
 		LibraryIDs:         s.serializeLibraryIDs(input.LibraryIDs),
 		EnableThinking:     input.EnableThinking,
+		ShowThinking:       input.ShowThinking == nil || *input.ShowThinking,
 		OpenClawSessionKey: strings.TrimSpace(input.OpenClawSessionKey),
 		ChatMode:           chatMode,
 		TeamType:           teamType,
 		DialogueID:         dialogueID,
 		TeamLibraryID:      teamLibraryID,
+		MaxIterations:      input.MaxIterations,
+		ThinkingBudget:     input.ThinkingBudget,
 	}
 
 	if _, err := db.NewInsert().Model(m).Exec(ctx); err != nil {
 		return nil, errs.Wrap("error.conversation_create_failed", err)
 	}
 
+	// 超出保留数量的旧会话自动归档；失败不影响新会话
+	if n, err := autoArchiveConversations(ctx, db, m.AgentID, m.AgentType); err != nil {
+		s.app.Logger.Warn("[conversations] auto-archive failed", "agent_id", m.AgentID, "error", err)
+	} else if n > 0 {
+		s.app.Logger.Info("[conversations] auto-archived conversations", "agent_id", m.AgentID, "count", n)
+	}
+
+	s.emitChanged(m.AgentID, m.ID, "created")
+
 	dto := m.toDTO()
+	dto.Warning = warning
 	return &dto, nil
 }
 
@@ -384,7 +582,8 @@ func (s *ConversationsService) UpdateConversation(id int64, input UpdateConversa
 			if len(nameRunes) > 100 {
 				name = string(nameRunes[:100])
 			}
-			q = q.Set("name = ?", name)
+			q = q.Set("name = ?", name).
+				Set("name_tokens = ?", tokenizer.TokenizeTitle(name))
 		}
 
 		if input.LastMessage != nil {
@@ -411,6 +610,10 @@ func (s *ConversationsService) UpdateConversation(id int64, input UpdateConversa
 			q = q.Set("enable_thinking = ?", *input.EnableThinking)
 		}
 
+		if input.ShowThinking != nil {
+			q = q.Set("show_thinking = ?", *input.ShowThinking)
+		}
+
 		if input.ChatMode != nil {
 			chatMode, ok := NormalizeChatMode(*input.ChatMode)
 			if !ok {
@@ -419,6 +622,20 @@ func (s *ConversationsService) UpdateConversation(id int64, input UpdateConversa
 			q = q.Set("chat_mode = ?", chatMode)
 		}
 
+		if input.MaxIterations != nil {
+			if !ValidMaxIterations(*input.MaxIterations) {
+				return errs.Newf("error.conversation_max_iterations_invalid", map[string]any{"Max": MaxIterationsLimit})
+			}
+			q = q.Set("max_iterations = ?", *input.MaxIterations)
+		}
+
+		if input.ThinkingBudget != nil {
+			if !ValidThinkingBudget(*input.ThinkingBudget) {
+				return errs.Newf("error.conversation_thinking_budget_invalid", map[string]any{"Min": MinThinkingBudget, "Max": MaxThinkingBudget})
+			}
+			q = q.Set("thinking_budget = ?", *input.ThinkingBudget)
+		}
+
 		if input.TeamType != nil {
 			teamType, ok := NormalizeTeamType(*input.TeamType)
 			if !ok {
@@ -461,6 +678,7 @@ func (s *ConversationsService) UpdateConversation(id int64, input UpdateConversa
 		return nil, err
 	}
 
+	s.emitChanged(result.AgentID, result.ID, "updated")
 	return result, nil
 }
 
@@ -478,6 +696,14 @@ func (s *ConversationsService) DeleteConversation(id int64) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 
+	// 删除前取出 agent_id，供侧边栏判断是否需要刷新
+	var agentID int64
+	_ = db.NewSelect().
+		Model((*conversationModel)(nil)).
+		Column("agent_id").
+		Where("id = ?", id).
+		Scan(ctx, &agentID)
+
 	result, err := db.NewDelete().
 		Model((*conversationModel)(nil)).
 		Where("id = ?", id).
@@ -490,6 +716,7 @@ func (s *ConversationsService) DeleteConversation(id int64) error {
 	if rowsAffected == 0 {
 		return errs.Newf("error.conversation_not_found", map[string]any{"ID": id})
 	}
+	s.emitChanged(agentID, id, "deleted")
 	return nil
 }
 
@@ -516,6 +743,7 @@ func (s *ConversationsService) DeleteConversationsByAgentID(agentID int64) error
 		return errs.Wrap("error.conversation_delete_failed", err)
 	}
 
+	s.emitChanged(agentID, 0, "deleted")
 	return nil
 }
 
@@ -0,0 +1,74 @@
+package document
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"chatclaw/internal/errs"
+	"chatclaw/internal/fts/tokenizer"
+)
+
+// contentSearchLimit 正文搜索返回的文档数上限
+const contentSearchLimit = 50
+
+// SearchDocumentContent 按正文搜索知识库文档：在分段全文索引 doc_fts 中匹配关键词，
+// 每个文档取最相关分段的 BM25 得分排序，并附带命中分段的摘要（MatchSnippet）。
+// doc_fts 由 document_nodes 的触发器维护，学习、重新学习、删除文档时自动同步；
+// 只匹配原文分段（level 0），不含 RAPTOR 生成的摘要节点。
+func (s *DocumentService) SearchDocumentContent(libraryID int64, query string) ([]Document, error) {
+	if libraryID <= 0 {
+		return nil, errs.New("error.library_id_required")
+	}
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return []Document{}, nil
+	}
+
+	db, err := s.db()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	language := libraryLanguage(ctx, db, libraryID)
+	matchQuery := tokenizer.BuildMatchQueryFor(query, language)
+	if matchQuery == "" {
+		return []Document{}, nil
+	}
+
+	models := make([]documentModel, 0)
+	if err := db.NewRaw(`
+		SELECT d.*
+		FROM (
+			SELECT dn.document_id, MIN(doc_fts.rank) AS best_rank
+			FROM doc_fts
+			INNER JOIN document_nodes dn ON dn.id = doc_fts.rowid
+			WHERE doc_fts MATCH ? AND dn.level = 0
+			GROUP BY dn.document_id
+			ORDER BY best_rank
+			LIMIT ?
+		) hit
+		INNER JOIN documents d ON d.id = hit.document_id
+		ORDER BY hit.best_rank, d.id DESC
+	`, fmt.Sprintf("(%s) AND library_id:%d", matchQuery, libraryID), contentSearchLimit).Scan(ctx, &models); err != nil {
+		return nil, errs.Wrap("error.document_list_failed", err)
+	}
+
+	out := make([]Document, 0, len(models))
+	for i := range models {
+		doc := models[i].toDTO()
+		if doc.LocalPath != "" {
+			if _, err := os.Stat(doc.LocalPath); os.IsNotExist(err) {
+				doc.FileMissing = true
+			}
+		}
+		out = append(out, doc)
+	}
+	attachMatchSnippets(ctx, db, libraryID, language, matchQuery, query, out)
+	return out, nil
+}
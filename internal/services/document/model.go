@@ -2,6 +2,8 @@ package document
 
 import (
 	"context"
+	"sort"
+	"strings"
 	"time"
 
 	"chatclaw/internal/sqlite"
@@ -50,6 +52,35 @@ type Document struct {
 
 	WordTotal  int `json:"word_total"`
 	SplitTotal int `json:"split_total"`
+
+	// 仅关键词搜索时填充：命中分段的摘要（已 HTML 转义，命中词以 <mark> 标出）；仅文件名命中时为空
+	MatchSnippet string `json:"match_snippet,omitempty"`
+
+	// 仅上传接口返回时填充：uploaded / duplicate_overwritten，原因说明及被覆盖的旧文档 ID
+	UploadStatus     string `json:"upload_status,omitempty"`
+	UploadReason     string `json:"upload_reason,omitempty"`
+	UploadReplacedID int64  `json:"upload_replaced_id,omitempty"`
+	// 复用了其他知识库中相同文件的分段与向量时，为来源文档 ID（此时不再重新学习）
+	UploadReusedFromID int64 `json:"upload_reused_from_id,omitempty"`
+}
+
+// 单文件上传结果状态
+const (
+	UploadStatusUploaded             = "uploaded"
+	UploadStatusDuplicateOverwritten = "duplicate_overwritten" // 同库内相同 hash 的旧文档被覆盖
+	UploadStatusUnsupported          = "unsupported"           // 不支持的文件类型
+	UploadStatusFailed               = "failed"
+)
+
+// UploadFileResult 单个文件的上传结果（随 document:upload_progress 事件下发）
+type UploadFileResult struct {
+	FileName   string `json:"file_name"`
+	Status     string `json:"status"`
+	Reason     string `json:"reason,omitempty"`
+	DocumentID int64  `json:"document_id,omitempty"`
+	ReplacedID int64  `json:"replaced_id,omitempty"` // duplicate_overwritten 时被覆盖的旧文档 ID
+	// 复用其他知识库已学习文档时的来源文档 ID
+	ReusedFromID int64 `json:"reused_from_id,omitempty"`
 }
 
 // UploadInput 上传文档的输入参数
@@ -57,6 +88,8 @@ type UploadInput struct {
 	LibraryID int64    `json:"library_id"`
 	FilePaths []string `json:"file_paths"`
 	FolderID  *int64   `json:"folder_id,omitempty"` // nil 表示未分组，可选字段
+	// 其他知识库已学习过相同文件（content_hash 相同）时复制其分段与向量，跳过重新解析/向量化
+	ReuseExisting bool `json:"reuse_existing"`
 }
 
 // BrowserUploadFile 上传文档的浏览器文件数据
@@ -70,13 +103,38 @@ type UploadBrowserInput struct {
 	LibraryID int64               `json:"library_id"`
 	Files     []BrowserUploadFile `json:"files"`
 	FolderID  *int64              `json:"folder_id,omitempty"` // nil 表示未分组，可选字段
+	// 同 UploadInput.ReuseExisting
+	ReuseExisting bool `json:"reuse_existing"`
+}
+
+// DocumentHashMatch 内容 hash 相同的已有文档（用于上传前提示跨知识库重复）
+type DocumentHashMatch struct {
+	DocumentID      int64  `json:"document_id"`
+	LibraryID       int64  `json:"library_id"`
+	LibraryName     string `json:"library_name"`
+	OriginalName    string `json:"original_name"`
+	ParsingStatus   int    `json:"parsing_status"`
+	EmbeddingStatus int    `json:"embedding_status"`
+	SplitTotal      int    `json:"split_total"`
+	// 解析与向量化均已完成，上传时可通过 ReuseExisting 复用其分段与向量
+	Reusable bool `json:"reusable"`
 }
 
 // UploadProgressEvent 上传进度事件（发送给前端）
 type UploadProgressEvent struct {
-	LibraryID int64 `json:"library_id"`
-	Total     int   `json:"total"`
-	Done      int   `json:"done"`
+	LibraryID int64             `json:"library_id"`
+	Total     int               `json:"total"`
+	Done      int               `json:"done"`
+	Result    *UploadFileResult `json:"result,omitempty"` // 刚处理完的文件的结果（首个事件为空）
+}
+
+// DocumentText 文档解析后的文本预览（由 document_nodes 原始块按顺序拼接，不重新解析文件）
+type DocumentText struct {
+	DocumentID int64  `json:"document_id"`
+	Text       string `json:"text"`
+	Truncated  bool   `json:"truncated"` // 超过 maxChars 被截断
+	WordTotal  int    `json:"word_total"`
+	SplitTotal int    `json:"split_total"`
 }
 
 // RenameInput 重命名文档的输入参数
@@ -109,6 +167,10 @@ type ProgressEvent struct {
 	EmbeddingStatus   int    `json:"embedding_status"`
 	EmbeddingProgress int    `json:"embedding_progress"`
 	EmbeddingError    string `json:"embedding_error"`
+	// 嵌入吞吐：已嵌入段数 / 总段数 / 平均每秒嵌入段数（未开始嵌入时为 0）
+	EmbeddedChunks int     `json:"embedded_chunks"`
+	TotalChunks    int     `json:"total_chunks"`
+	EmbeddingRate  float64 `json:"embedding_rate"`
 }
 
 // ThumbnailEvent 缩略图更新事件数据（发送给前端）
@@ -153,6 +215,13 @@ type documentModel struct {
 
 	WordTotal  int `bun:"word_total,notnull"`
 	SplitTotal int `bun:"split_total,notnull"`
+
+	// 最近一次处理使用的参数，见 ListStaleDocuments
+	ProcessedChunkSize           int    `bun:"processed_chunk_size,notnull"`
+	ProcessedChunkOverlap        int    `bun:"processed_chunk_overlap,notnull"`
+	ProcessedEmbeddingProviderID string `bun:"processed_embedding_provider_id,notnull"`
+	ProcessedEmbeddingModelID    string `bun:"processed_embedding_model_id,notnull"`
+	ProcessedEmbeddingDimension  int    `bun:"processed_embedding_dimension,notnull"`
 }
 
 // BeforeInsert 在 INSERT 时自动设置 created_at 和 updated_at
@@ -222,16 +291,57 @@ var supportedExtensions = map[string]string{
 	"ofd":  "application/ofd",
 }
 
+// 扩展名对应的展示名称（文件选择器、"支持的格式"提示）
+var extensionLabels = map[string]string{
+	"pdf":  "PDF",
+	"doc":  "Word 97-2003",
+	"docx": "Word",
+	"txt":  "Text",
+	"md":   "Markdown",
+	"csv":  "CSV",
+	"xlsx": "Excel",
+	"html": "HTML",
+	"htm":  "HTML",
+	"ofd":  "OFD",
+}
+
+// SupportedExtension 支持的文件扩展名（暴露给前端）
+type SupportedExtension struct {
+	Extension string `json:"extension"` // 不带小数点前缀
+	MimeType  string `json:"mime_type"`
+	Label     string `json:"label"`
+}
+
+// lookupExtension 查找扩展名的 MIME 类型
+func lookupExtension(ext string) (string, bool) {
+	mime, ok := supportedExtensions[ext]
+	return mime, ok
+}
+
 // IsSupportedExtension 检查扩展名是否支持
 func IsSupportedExtension(ext string) bool {
-	_, ok := supportedExtensions[ext]
+	_, ok := lookupExtension(ext)
 	return ok
 }
 
 // GetMimeType 获取扩展名对应的 MIME 类型
 func GetMimeType(ext string) string {
-	if mime, ok := supportedExtensions[ext]; ok {
+	if mime, ok := lookupExtension(ext); ok {
 		return mime
 	}
 	return "application/octet-stream"
 }
+
+// listSupportedExtensions 返回当前支持的全部扩展名（按扩展名排序）
+func listSupportedExtensions() []SupportedExtension {
+	out := make([]SupportedExtension, 0, len(supportedExtensions))
+	for ext, mime := range supportedExtensions {
+		label := extensionLabels[ext]
+		if label == "" {
+			label = strings.ToUpper(ext)
+		}
+		out = append(out, SupportedExtension{Extension: ext, MimeType: mime, Label: label})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Extension < out[j].Extension })
+	return out
+}
@@ -0,0 +1,128 @@
+package document
+
+import (
+	"context"
+	"time"
+
+	"chatclaw/internal/errs"
+	"chatclaw/internal/fts/tokenizer"
+
+	"github.com/uptrace/bun"
+)
+
+const (
+	retokenizeBatchSize = 500
+	retokenizeTimeout   = 10 * time.Minute
+)
+
+// RetokenizeResult 重新分词的结果统计
+type RetokenizeResult struct {
+	Documents int `json:"documents"`
+	Nodes     int `json:"nodes"`
+}
+
+// RetokenizeDocuments 按当前分词语言（知识库的 language，auto 时为设置 fts_language）重新生成
+// 文档名与分段内容的分词结果。只改写 name_tokens / content_tokens，全文索引由触发器同步；
+// 不重新解析文件，也不重新向量化。libraryID 为 0 时处理全部知识库。
+func (s *DocumentService) RetokenizeDocuments(libraryID int64) (*RetokenizeResult, error) {
+	db, err := s.db()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), retokenizeTimeout)
+	defer cancel()
+
+	return RetokenizeLibrary(ctx, db, libraryID)
+}
+
+// RetokenizeLibrary 是 RetokenizeDocuments 的实现，供修改知识库分词语言后直接调用
+func RetokenizeLibrary(ctx context.Context, db *bun.DB, libraryID int64) (*RetokenizeResult, error) {
+	result := &RetokenizeResult{}
+
+	type docRow struct {
+		ID           int64  `bun:"id"`
+		OriginalName string `bun:"original_name"`
+		Language     string `bun:"language"`
+	}
+	var lastID int64
+	for {
+		var docs []docRow
+		q := db.NewSelect().
+			TableExpr("documents AS d").
+			Join("INNER JOIN library AS l ON l.id = d.library_id").
+			ColumnExpr("d.id, d.original_name, l.language").
+			Where("d.id > ?", lastID).
+			OrderExpr("d.id ASC").
+			Limit(retokenizeBatchSize)
+		if libraryID > 0 {
+			q = q.Where("d.library_id = ?", libraryID)
+		}
+		if err := q.Scan(ctx, &docs); err != nil {
+			return nil, errs.Wrap("error.document_read_failed", err)
+		}
+		if len(docs) == 0 {
+			break
+		}
+		err := db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+			for _, d := range docs {
+				if _, err := tx.NewRaw(
+					"UPDATE documents SET name_tokens = ? WHERE id = ?",
+					tokenizer.TokenizeNameFor(d.OriginalName, d.Language), d.ID,
+				).Exec(ctx); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, errs.Wrap("error.document_retokenize_failed", err)
+		}
+		result.Documents += len(docs)
+		lastID = docs[len(docs)-1].ID
+	}
+
+	type nodeRow struct {
+		ID       int64  `bun:"id"`
+		Content  string `bun:"content"`
+		Language string `bun:"language"`
+	}
+	lastID = 0
+	for {
+		var nodes []nodeRow
+		q := db.NewSelect().
+			TableExpr("document_nodes AS n").
+			Join("INNER JOIN library AS l ON l.id = n.library_id").
+			ColumnExpr("n.id, n.content, l.language").
+			Where("n.id > ?", lastID).
+			OrderExpr("n.id ASC").
+			Limit(retokenizeBatchSize)
+		if libraryID > 0 {
+			q = q.Where("n.library_id = ?", libraryID)
+		}
+		if err := q.Scan(ctx, &nodes); err != nil {
+			return nil, errs.Wrap("error.document_read_failed", err)
+		}
+		if len(nodes) == 0 {
+			break
+		}
+		err := db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+			for _, n := range nodes {
+				if _, err := tx.NewRaw(
+					"UPDATE document_nodes SET content_tokens = ? WHERE id = ?",
+					tokenizer.TokenizeContentFor(n.Content, n.Language), n.ID,
+				).Exec(ctx); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, errs.Wrap("error.document_retokenize_failed", err)
+		}
+		result.Nodes += len(nodes)
+		lastID = nodes[len(nodes)-1].ID
+	}
+
+	return result, nil
+}
@@ -0,0 +1,171 @@
+package document
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"chatclaw/internal/errs"
+	"chatclaw/internal/taskmanager"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// EmbeddingAutoRetrySettingKey 向量化失败后自动重试的次数（0 = 关闭）
+const EmbeddingAutoRetrySettingKey = "embedding_auto_retry"
+
+const (
+	maxEmbeddingAutoRetry    = 5
+	embeddingRetryBaseDelay  = 30 * time.Second // 第 n 次重试等待 30s * 2^(n-1)
+	embeddingRetryQueryLimit = 5 * time.Second
+)
+
+// embeddingRetries 记录每个文档已自动重试的次数；重启后清零，成功或手动重试时重置
+var embeddingRetries = struct {
+	sync.Mutex
+	attempts map[int64]int
+}{attempts: make(map[int64]int)}
+
+func resetEmbeddingRetries(docID int64) {
+	embeddingRetries.Lock()
+	delete(embeddingRetries.attempts, docID)
+	embeddingRetries.Unlock()
+}
+
+// RetryEmbedding 仅重新向量化向量化失败的文档：复用已入库的分段节点，不重新解析。
+// 解析/分段失败，或失败时没有保留节点（如开启了 RAPTOR）的文档需要完整重新学习（ReprocessDocument）。
+func (s *DocumentService) RetryEmbedding(id int64) error {
+	if id <= 0 {
+		return errs.New("error.document_id_required")
+	}
+
+	db, err := s.db()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), embeddingRetryQueryLimit)
+	defer cancel()
+
+	var m documentModel
+	if err := db.NewSelect().Model(&m).Where("id = ?", id).Scan(ctx); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errs.Newf("error.document_not_found", map[string]any{"ID": id})
+		}
+		return errs.Wrap("error.document_read_failed", err)
+	}
+	if m.EmbeddingStatus != StatusFailed || m.ParsingStatus != StatusCompleted {
+		if m.ParsingStatus == StatusFailed {
+			return errs.New("error.document_retry_needs_reprocess")
+		}
+		return errs.New("error.document_embedding_not_failed")
+	}
+	nodes, err := db.NewSelect().Table("document_nodes").Where("document_id = ?", id).Count(ctx)
+	if err != nil {
+		return errs.Wrap("error.document_read_failed", err)
+	}
+	if nodes == 0 {
+		return errs.New("error.document_retry_needs_reprocess")
+	}
+
+	resetEmbeddingRetries(id)
+	if err := startReembedTask(ctx, db, id, m.LibraryID); err != nil {
+		return errs.Wrap("error.document_retry_failed", err)
+	}
+	return nil
+}
+
+// startReembedTask 以新的 run ID 提交仅向量化任务（旧任务因 run ID 不匹配自动失效）
+func startReembedTask(ctx context.Context, db bun.IDB, docID, libraryID int64) error {
+	tm := taskmanager.Get()
+	if tm == nil {
+		return errors.New("task manager not initialized")
+	}
+
+	runID := uuid.New().String()
+	if _, err := db.NewUpdate().
+		Table("documents").
+		Set("processing_run_id = ?", runID).
+		Set("embedding_status = ?", StatusPending).
+		Set("embedding_progress = ?", 0).
+		Set("embedding_error = ?", "").
+		Where("id = ?", docID).
+		Exec(ctx); err != nil {
+		return err
+	}
+
+	jobData, _ := json.Marshal(ProcessJobData{
+		DocID:     docID,
+		LibraryID: libraryID,
+		RunID:     runID,
+	})
+	tm.Submit(taskmanager.QueueDocument, JobTypeReembed, fmt.Sprintf("doc:%d", docID), runID, jobData)
+	return nil
+}
+
+// embeddingAutoRetryLimit 读取自动重试次数设置（限制在 0..maxEmbeddingAutoRetry）
+func embeddingAutoRetryLimit(ctx context.Context, db bun.IDB) int {
+	var value sql.NullString
+	if err := db.NewSelect().
+		TableExpr("settings").
+		Column("value").
+		Where("key = ?", EmbeddingAutoRetrySettingKey).
+		Scan(ctx, &value); err != nil {
+		return 0
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(value.String))
+	if err != nil || n <= 0 {
+		return 0
+	}
+	return min(n, maxEmbeddingAutoRetry)
+}
+
+// scheduleEmbeddingAutoRetry 在向量化失败（且分段节点已入库）后按退避间隔安排一次自动重新向量化。
+// 到期时若文档已被删除、重新学习或手动重试（run ID 变化），则放弃本次重试。
+func (s *DocumentService) scheduleEmbeddingAutoRetry(ctx context.Context, db *bun.DB, docID, libraryID int64, runID string) {
+	limit := embeddingAutoRetryLimit(ctx, db)
+	if limit == 0 {
+		return
+	}
+
+	embeddingRetries.Lock()
+	attempt := embeddingRetries.attempts[docID] + 1
+	if attempt > limit {
+		delete(embeddingRetries.attempts, docID)
+		embeddingRetries.Unlock()
+		s.app.Logger.Warn("document embedding auto-retry exhausted", "docID", docID, "attempts", limit)
+		return
+	}
+	embeddingRetries.attempts[docID] = attempt
+	embeddingRetries.Unlock()
+
+	delay := embeddingRetryBaseDelay << (attempt - 1)
+	s.app.Logger.Info("document embedding auto-retry scheduled", "docID", docID, "attempt", attempt, "delay", delay)
+	time.AfterFunc(delay, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), embeddingRetryQueryLimit)
+		defer cancel()
+
+		var m documentModel
+		if err := db.NewSelect().
+			Model(&m).
+			Column("processing_run_id", "embedding_status").
+			Where("id = ?", docID).
+			Scan(ctx); err != nil {
+			resetEmbeddingRetries(docID)
+			return
+		}
+		if m.ProcessingRunID != runID || m.EmbeddingStatus != StatusFailed {
+			return
+		}
+		if err := startReembedTask(ctx, db, docID, libraryID); err != nil {
+			s.app.Logger.Warn("document embedding auto-retry submit failed", "docID", docID, "error", err)
+		}
+	})
+}
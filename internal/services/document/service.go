@@ -19,10 +19,11 @@ import (
 	"sync/atomic"
 	"time"
 
-	"chatclaw/internal/define"
 	"chatclaw/internal/eino/processor"
 	"chatclaw/internal/errs"
 	"chatclaw/internal/fts/tokenizer"
+	"chatclaw/internal/services/i18n"
+	"chatclaw/internal/services/retrieval"
 	"chatclaw/internal/services/thumbnail"
 	"chatclaw/internal/sqlite"
 	"chatclaw/internal/taskmanager"
@@ -53,6 +54,10 @@ type ProcessJobData struct {
 	RunID     string `json:"run_id"`
 }
 
+// cancelledRunIDPrefix marks processing_run_id of documents whose processing was cancelled by the user;
+// such documents stay pending and are not resumed on startup.
+const cancelledRunIDPrefix = "cancelled-"
+
 // docProcessSem prevents concurrent processDocument runs for the same document when
 // duplicate jobs exist (e.g. crash recovery + startup re-queue).
 var docProcessSem sync.Map // int64 -> *int32 (0 = free, 1 = held)
@@ -69,6 +74,30 @@ func acquireDocProcessSlot(docID int64) (release func()) {
 	}
 }
 
+// withTaskCancel 返回在任务被 taskmanager.Cancel 标记取消后自动取消的 ctx，使进行中的解析/向量化请求及时停止
+func withTaskCancel(parent context.Context, info *taskmanager.TaskInfo) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+	if info == nil {
+		return ctx, cancel
+	}
+	go func() {
+		ticker := time.NewTicker(500 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if info.IsCancelled() {
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+	return ctx, cancel
+}
+
 // DocumentService 文档服务（暴露给前端调用）
 type DocumentService struct {
 	app *application.App
@@ -141,7 +170,8 @@ func (s *DocumentService) registerTaskHandlers() {
 }
 
 // resumeInterruptedDocumentJobs submits process jobs for documents that are not in a
-// terminal success state (both parsing and embedding completed) and not failed.
+// terminal success state (both parsing and embedding completed), not failed and not
+// cancelled by the user (see CancelDocumentProcessing).
 func (s *DocumentService) resumeInterruptedDocumentJobs(ctx context.Context) {
 	db, err := s.db()
 	if err != nil {
@@ -153,6 +183,7 @@ func (s *DocumentService) resumeInterruptedDocumentJobs(ctx context.Context) {
 		Where("NOT (parsing_status = ? AND embedding_status = ?)", StatusCompleted, StatusCompleted).
 		Where("parsing_status != ?", StatusFailed).
 		Where("embedding_status != ?", StatusFailed).
+		Where("processing_run_id NOT LIKE ?", cancelledRunIDPrefix+"%").
 		Scan(ctx)
 	if err != nil {
 		s.app.Logger.Error("resume interrupted document jobs: query failed", "error", err)
@@ -177,13 +208,39 @@ func (s *DocumentService) db() (*bun.DB, error) {
 	return db, nil
 }
 
-// GetDocumentsDir 获取文档存储目录
+// libraryLanguage 读取知识库的分词语言提示（见 tokenizer.ResolveLanguage）；读取失败时按 auto 处理
+func libraryLanguage(ctx context.Context, db bun.IDB, libraryID int64) string {
+	var language string
+	if err := db.NewSelect().
+		Table("library").
+		Column("language").
+		Where("id = ?", libraryID).
+		Limit(1).
+		Scan(ctx, &language); err != nil {
+		return tokenizer.LanguageAuto
+	}
+	return language
+}
+
+// GetSupportedExtensions 获取可上传的文件扩展名列表（含可选功能注册的扩展名，随设置动态变化）
+func (s *DocumentService) GetSupportedExtensions() []SupportedExtension {
+	return listSupportedExtensions()
+}
+
+// GetDocumentsDir 获取文档存储目录（优先使用 documents_dir 设置，目录不存在时自动创建）
 func (s *DocumentService) GetDocumentsDir() (string, error) {
-	dir, err := define.AppDataDir()
+	db, err := s.db()
+	if err != nil {
+		return "", err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	dir, release, err := AcquireDocumentsDir(ctx, db)
 	if err != nil {
-		return "", errs.Wrap("error.document_dir_failed", err)
+		return "", err
 	}
-	return filepath.Join(dir, "documents"), nil
+	release()
+	return dir, nil
 }
 
 func (s *DocumentService) ensureEmbeddingConfiguredForUpload(ctx context.Context, db *bun.DB) error {
@@ -224,10 +281,12 @@ func (s *DocumentService) ListDocumentsPage(input ListDocumentsPageInput) ([]Doc
 
 	models := make([]documentModel, 0, limit)
 	keyword := strings.TrimSpace(input.Keyword)
+	language := tokenizer.LanguageAuto
 
 	if keyword != "" {
-		// Build FTS match query
-		matchQuery := tokenizer.BuildMatchQuery(keyword)
+		// Build FTS match query, segmented like the library's index
+		language = libraryLanguage(ctx, db, input.LibraryID)
+		matchQuery := tokenizer.BuildMatchQueryFor(keyword, language)
 		if matchQuery == "" {
 			return []Document{}, nil
 		}
@@ -294,6 +353,9 @@ func (s *DocumentService) ListDocumentsPage(input ListDocumentsPageInput) ([]Doc
 		}
 		out = append(out, doc)
 	}
+	if keyword != "" {
+		attachMatchSnippets(ctx, db, input.LibraryID, language, tokenizer.BuildMatchQueryFor(keyword, language), keyword, out)
+	}
 	return out, nil
 }
 
@@ -347,10 +409,12 @@ func (s *DocumentService) ListDocuments(libraryID int64, keyword string) ([]Docu
 
 	models := make([]documentModel, 0)
 	keyword = strings.TrimSpace(keyword)
+	language := tokenizer.LanguageAuto
 
 	if keyword != "" {
-		// Build FTS match query from keyword
-		matchQuery := tokenizer.BuildMatchQuery(keyword)
+		// Build FTS match query from keyword, segmented like the library's index
+		language = libraryLanguage(ctx, db, libraryID)
+		matchQuery := tokenizer.BuildMatchQueryFor(keyword, language)
 		if matchQuery != "" {
 			// 调试日志
 			s.app.Logger.Debug("FTS search", "keyword", keyword, "matchQuery", matchQuery, "libraryID", libraryID)
@@ -392,6 +456,9 @@ func (s *DocumentService) ListDocuments(libraryID int64, keyword string) ([]Docu
 		}
 		out = append(out, doc)
 	}
+	if keyword != "" {
+		attachMatchSnippets(ctx, db, libraryID, language, tokenizer.BuildMatchQueryFor(keyword, language), keyword, out)
+	}
 	return out, nil
 }
 
@@ -416,10 +483,12 @@ func (s *DocumentService) UploadDocuments(input UploadInput) ([]Document, error)
 		return nil, err
 	}
 
-	docsDir, err := s.GetDocumentsDir()
+	// 持读锁直到文件写完，避免与 MoveDocumentsDir 交错
+	docsDir, releaseDir, err := AcquireDocumentsDir(ctx, db)
 	if err != nil {
 		return nil, err
 	}
+	defer releaseDir()
 
 	// 确保目录存在
 	libraryDir := filepath.Join(docsDir, fmt.Sprintf("%d", input.LibraryID))
@@ -431,19 +500,21 @@ func (s *DocumentService) UploadDocuments(input UploadInput) ([]Document, error)
 	total := len(input.FilePaths)
 	done := 0
 
-	emitUploadProgress := func() {
+	emitUploadProgress := func(result *UploadFileResult) {
 		s.app.Event.Emit("document:upload_progress", UploadProgressEvent{
 			LibraryID: input.LibraryID,
 			Total:     total,
 			Done:      done,
+			Result:    result,
 		})
 	}
-	emitUploadProgress()
+	emitUploadProgress(nil)
 
 	for _, srcPath := range input.FilePaths {
-		doc, err := s.uploadSingleFile(ctx, db, input.LibraryID, input.FolderID, libraryDir, srcPath)
+		doc, err := s.uploadSingleFile(ctx, db, input.LibraryID, input.FolderID, libraryDir, srcPath, input.ReuseExisting)
 		done++
-		emitUploadProgress()
+		result := newUploadFileResult(filepath.Base(srcPath), doc, err)
+		emitUploadProgress(&result)
 		if err != nil {
 			// 记录错误但继续处理其他文件
 			s.app.Logger.Warn("upload file failed", "path", srcPath, "error", err)
@@ -454,8 +525,10 @@ func (s *DocumentService) UploadDocuments(input UploadInput) ([]Document, error)
 		// 可选：实时通知前端新增文档（用于即时渲染/反馈）
 		s.app.Event.Emit("document:uploaded", *doc)
 
-		// 启动异步处理任务
-		s.startProcessingTask(doc)
+		// 启动异步处理任务（复用了其他知识库的分段与向量时已完成，无需处理）
+		if doc.UploadReusedFromID == 0 {
+			s.startProcessingTask(doc)
+		}
 
 		// 启动缩略图生成任务
 		s.startThumbnailTask(doc)
@@ -489,10 +562,12 @@ func (s *DocumentService) UploadBrowserDocuments(input UploadBrowserInput) ([]Do
 		return nil, err
 	}
 
-	docsDir, err := s.GetDocumentsDir()
+	// 持读锁直到文件写完，避免与 MoveDocumentsDir 交错
+	docsDir, releaseDir, err := AcquireDocumentsDir(ctx, db)
 	if err != nil {
 		return nil, err
 	}
+	defer releaseDir()
 
 	libraryDir := filepath.Join(docsDir, fmt.Sprintf("%d", input.LibraryID))
 	if err := os.MkdirAll(libraryDir, 0o755); err != nil {
@@ -503,19 +578,21 @@ func (s *DocumentService) UploadBrowserDocuments(input UploadBrowserInput) ([]Do
 	total := len(input.Files)
 	done := 0
 
-	emitUploadProgress := func() {
+	emitUploadProgress := func(result *UploadFileResult) {
 		s.app.Event.Emit("document:upload_progress", UploadProgressEvent{
 			LibraryID: input.LibraryID,
 			Total:     total,
 			Done:      done,
+			Result:    result,
 		})
 	}
-	emitUploadProgress()
+	emitUploadProgress(nil)
 
 	for _, file := range input.Files {
-		doc, err := s.uploadSingleBrowserFile(ctx, db, input.LibraryID, input.FolderID, libraryDir, file)
+		doc, err := s.uploadSingleBrowserFile(ctx, db, input.LibraryID, input.FolderID, libraryDir, file, input.ReuseExisting)
 		done++
-		emitUploadProgress()
+		result := newUploadFileResult(filepath.Base(strings.TrimSpace(file.FileName)), doc, err)
+		emitUploadProgress(&result)
 		if err != nil {
 			s.app.Logger.Warn("upload browser file failed", "fileName", file.FileName, "error", err)
 			continue
@@ -523,7 +600,9 @@ func (s *DocumentService) UploadBrowserDocuments(input UploadBrowserInput) ([]Do
 		uploaded = append(uploaded, *doc)
 
 		s.app.Event.Emit("document:uploaded", *doc)
-		s.startProcessingTask(doc)
+		if doc.UploadReusedFromID == 0 {
+			s.startProcessingTask(doc)
+		}
 		s.startThumbnailTask(doc)
 	}
 
@@ -535,7 +614,7 @@ func (s *DocumentService) UploadBrowserDocuments(input UploadBrowserInput) ([]Do
 }
 
 // uploadSingleFile 上传单个文件
-func (s *DocumentService) uploadSingleFile(ctx context.Context, db *bun.DB, libraryID int64, folderID *int64, libraryDir, srcPath string) (*Document, error) {
+func (s *DocumentService) uploadSingleFile(ctx context.Context, db *bun.DB, libraryID int64, folderID *int64, libraryDir, srcPath string, reuseExisting bool) (*Document, error) {
 	// 检查文件是否存在
 	srcInfo, err := os.Stat(srcPath)
 	if err != nil {
@@ -565,13 +644,14 @@ func (s *DocumentService) uploadSingleFile(ctx context.Context, db *bun.DB, libr
 		srcInfo.Size(),
 		ext,
 		hash,
+		reuseExisting,
 		func(destPath string) error {
 			return s.copyFile(srcPath, destPath)
 		},
 	)
 }
 
-func (s *DocumentService) uploadSingleBrowserFile(ctx context.Context, db *bun.DB, libraryID int64, folderID *int64, libraryDir string, file BrowserUploadFile) (*Document, error) {
+func (s *DocumentService) uploadSingleBrowserFile(ctx context.Context, db *bun.DB, libraryID int64, folderID *int64, libraryDir string, file BrowserUploadFile, reuseExisting bool) (*Document, error) {
 	originalName := filepath.Base(strings.TrimSpace(file.FileName))
 	if originalName == "" {
 		return nil, errs.New("error.document_file_required")
@@ -606,6 +686,7 @@ func (s *DocumentService) uploadSingleBrowserFile(ctx context.Context, db *bun.D
 		int64(len(data)),
 		ext,
 		hash,
+		reuseExisting,
 		func(destPath string) error {
 			return s.writeFileBytes(destPath, data)
 		},
@@ -620,9 +701,11 @@ func (s *DocumentService) saveUploadedDocument(
 	libraryDir, originalName string,
 	fileSize int64,
 	ext, hash string,
+	reuseExisting bool,
 	writeContent func(destPath string) error,
 ) (*Document, error) {
 	// 检查是否已存在相同文件，如果存在则删除旧记录（覆盖上传）
+	var replacedID int64
 	var existingDoc documentModel
 	err := db.NewSelect().
 		Model(&existingDoc).
@@ -643,6 +726,7 @@ func (s *DocumentService) saveUploadedDocument(
 		if _, err := db.NewDelete().Model(&existingDoc).Where("id = ?", existingDoc.ID).Exec(ctx); err != nil {
 			s.app.Logger.Error("delete existing document failed", "id", existingDoc.ID, "error", err)
 		}
+		replacedID = existingDoc.ID
 	}
 
 	// 生成目标文件名：hash_原始文件名
@@ -662,7 +746,7 @@ func (s *DocumentService) saveUploadedDocument(
 		LibraryID:       libraryID,
 		FolderID:        folderID,
 		OriginalName:    originalName,
-		NameTokens:      tokenizer.TokenizeName(originalName),
+		NameTokens:      tokenizer.TokenizeNameFor(originalName, libraryLanguage(ctx, db, libraryID)),
 		ThumbIcon:       "",
 		FileSize:        fileSize,
 		ContentHash:     hash,
@@ -681,10 +765,201 @@ func (s *DocumentService) saveUploadedDocument(
 		return nil, fmt.Errorf("insert record: %w", err)
 	}
 
+	var reusedFromID int64
+	if reuseExisting {
+		srcID, err := s.reuseDocumentNodes(ctx, db, m)
+		if err != nil {
+			// 复用失败时按正常流程重新学习
+			s.app.Logger.Warn("reuse document nodes failed, falling back to processing", "docID", m.ID, "error", err)
+		}
+		reusedFromID = srcID
+	}
+
 	dto := m.toDTO()
+	dto.UploadStatus = UploadStatusUploaded
+	dto.UploadReusedFromID = reusedFromID
+	if replacedID > 0 {
+		dto.UploadStatus = UploadStatusDuplicateOverwritten
+		dto.UploadReason = i18n.Tf("document.upload_duplicate_replaced", map[string]any{"ID": replacedID, "Name": existingDoc.OriginalName})
+		dto.UploadReplacedID = replacedID
+	}
 	return &dto, nil
 }
 
+// newUploadFileResult 根据单文件上传的返回值生成上传结果
+func newUploadFileResult(fileName string, doc *Document, err error) UploadFileResult {
+	if err != nil {
+		status := UploadStatusFailed
+		var ie *errs.I18nError
+		if errors.As(err, &ie) && ie.Key == "error.document_file_type_not_supported" {
+			status = UploadStatusUnsupported
+		}
+		return UploadFileResult{FileName: fileName, Status: status, Reason: err.Error()}
+	}
+	return UploadFileResult{
+		FileName:   fileName,
+		Status:     doc.UploadStatus,
+		Reason:     doc.UploadReason,
+		DocumentID: doc.ID,
+		ReplacedID: doc.UploadReplacedID,
+
+		ReusedFromID: doc.UploadReusedFromID,
+	}
+}
+
+// reuseDocumentNodes 在其他知识库中查找已学习完成的相同文件（content_hash 相同），
+// 将其分段节点与向量复制到 doc 并把 doc 标记为已完成。返回来源文档 ID，未找到可复用文档时返回 0。
+// 向量由全局嵌入模型生成，因此可跨知识库直接复用。
+func (s *DocumentService) reuseDocumentNodes(ctx context.Context, db *bun.DB, doc *documentModel) (int64, error) {
+	var src documentModel
+	err := db.NewSelect().
+		Model(&src).
+		Where("content_hash = ?", doc.ContentHash).
+		Where("library_id != ?", doc.LibraryID).
+		Where("parsing_status = ?", StatusCompleted).
+		Where("embedding_status = ?", StatusCompleted).
+		OrderExpr("id DESC").
+		Limit(1).
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	type nodeRow struct {
+		ID            int64  `bun:"id"`
+		Content       string `bun:"content"`
+		ContentTokens string `bun:"content_tokens"`
+		Level         int    `bun:"level"`
+		ParentID      *int64 `bun:"parent_id"`
+		ChunkOrder    int    `bun:"chunk_order"`
+	}
+
+	err = db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		var nodes []nodeRow
+		if err := tx.NewSelect().
+			TableExpr("document_nodes").
+			Column("id", "content", "content_tokens", "level", "parent_id", "chunk_order").
+			Where("document_id = ?", src.ID).
+			OrderExpr("level ASC, chunk_order ASC, id ASC").
+			Scan(ctx, &nodes); err != nil {
+			return err
+		}
+		if len(nodes) == 0 {
+			return errors.New("source document has no nodes")
+		}
+
+		// 旧节点 ID -> 新节点 ID
+		idMap := make(map[int64]int64, len(nodes))
+		for _, n := range nodes {
+			res, err := tx.NewRaw(
+				"INSERT INTO document_nodes (library_id, document_id, content, content_tokens, level, chunk_order) VALUES (?, ?, ?, ?, ?, ?)",
+				doc.LibraryID, doc.ID, n.Content, n.ContentTokens, n.Level, n.ChunkOrder,
+			).Exec(ctx)
+			if err != nil {
+				return err
+			}
+			newID, err := res.LastInsertId()
+			if err != nil {
+				return err
+			}
+			idMap[n.ID] = newID
+
+			if _, err := tx.NewRaw(
+				"INSERT INTO doc_vec (id, content) SELECT ?, content FROM doc_vec WHERE id = ?",
+				newID, n.ID,
+			).Exec(ctx); err != nil {
+				return err
+			}
+		}
+
+		// 恢复 RAPTOR 父子关系
+		for _, n := range nodes {
+			if n.ParentID == nil {
+				continue
+			}
+			parentID, ok := idMap[*n.ParentID]
+			if !ok {
+				continue
+			}
+			if _, err := tx.NewRaw("UPDATE document_nodes SET parent_id = ? WHERE id = ?", parentID, idMap[n.ID]).Exec(ctx); err != nil {
+				return err
+			}
+		}
+
+		_, err := tx.NewUpdate().
+			Table("documents").
+			Set("parsing_status = ?", StatusCompleted).
+			Set("parsing_progress = 100").
+			Set("embedding_status = ?", StatusCompleted).
+			Set("embedding_progress = 100").
+			Set("word_total = ?", src.WordTotal).
+			Set("split_total = ?", src.SplitTotal).
+			Set("processed_chunk_size = ?", src.ProcessedChunkSize).
+			Set("processed_chunk_overlap = ?", src.ProcessedChunkOverlap).
+			Set("processed_embedding_provider_id = ?", src.ProcessedEmbeddingProviderID).
+			Set("processed_embedding_model_id = ?", src.ProcessedEmbeddingModelID).
+			Set("processed_embedding_dimension = ?", src.ProcessedEmbeddingDimension).
+			Set("updated_at = ?", sqlite.NowUTC()).
+			Where("id = ?", doc.ID).
+			Exec(ctx)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	doc.ParsingStatus, doc.ParsingProgress = StatusCompleted, 100
+	doc.EmbeddingStatus, doc.EmbeddingProgress = StatusCompleted, 100
+	doc.WordTotal, doc.SplitTotal = src.WordTotal, src.SplitTotal
+	retrieval.InvalidateLibrary(doc.LibraryID)
+	s.app.Logger.Info("reused document nodes from another library", "docID", doc.ID, "sourceDocID", src.ID, "nodes", doc.SplitTotal)
+	return src.ID, nil
+}
+
+// FindDocumentsByHash 按内容 hash 查找所有知识库中的相同文件，供上传前提示跨知识库重复
+func (s *DocumentService) FindDocumentsByHash(hash string) ([]DocumentHashMatch, error) {
+	hash = strings.ToLower(strings.TrimSpace(hash))
+	if hash == "" {
+		return nil, errs.New("error.document_hash_required")
+	}
+
+	db, err := s.db()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	matches := make([]DocumentHashMatch, 0)
+	if err := db.NewSelect().
+		TableExpr("documents AS d").
+		Join("JOIN library AS l ON l.id = d.library_id").
+		ColumnExpr("d.id AS document_id, d.library_id, l.name AS library_name, d.original_name").
+		ColumnExpr("d.parsing_status, d.embedding_status, d.split_total").
+		Where("d.content_hash = ?", hash).
+		OrderExpr("d.id DESC").
+		Scan(ctx, &matches); err != nil {
+		return nil, errs.Wrap("error.document_read_failed", err)
+	}
+	for i := range matches {
+		matches[i].Reusable = matches[i].ParsingStatus == StatusCompleted && matches[i].EmbeddingStatus == StatusCompleted
+	}
+	return matches, nil
+}
+
+// GetFileHash 计算本地文件的内容 hash（与上传时写入的 content_hash 一致），配合 FindDocumentsByHash 使用
+func (s *DocumentService) GetFileHash(path string) (string, error) {
+	hash, err := s.calculateFileHash(path)
+	if err != nil {
+		return "", errs.Wrap("error.document_hash_failed", err)
+	}
+	return hash, nil
+}
+
 // RenameDocument 重命名文档
 func (s *DocumentService) RenameDocument(input RenameInput) (*Document, error) {
 	if input.ID <= 0 {
@@ -739,7 +1014,7 @@ func (s *DocumentService) RenameDocument(input RenameInput) (*Document, error) {
 
 	// 更新数据库
 	m.OriginalName = newName
-	m.NameTokens = tokenizer.TokenizeName(newName)
+	m.NameTokens = tokenizer.TokenizeNameFor(newName, libraryLanguage(ctx, db, m.LibraryID))
 	if _, err := db.NewUpdate().Model(&m).
 		Column("original_name", "name_tokens", "local_path", "updated_at").
 		Where("id = ?", input.ID).
@@ -778,6 +1053,7 @@ func (s *DocumentService) ReprocessDocument(id int64) error {
 	if tm := taskmanager.Get(); tm != nil {
 		tm.Cancel(fmt.Sprintf("doc:%d", id))
 	}
+	resetEmbeddingRetries(id)
 
 	// 3. 查询并删除向量（doc_vec 没有外键约束，需要手动删除）
 	var nodeIDs []int64
@@ -799,6 +1075,7 @@ func (s *DocumentService) ReprocessDocument(id int64) error {
 	if _, err := db.NewDelete().Table("document_nodes").Where("document_id = ?", id).Exec(ctx); err != nil {
 		s.app.Logger.Warn("delete document_nodes failed", "error", err)
 	}
+	retrieval.InvalidateLibrary(m.LibraryID)
 
 	// 5. 生成新的处理运行 ID 并重置状态
 	runID := fmt.Sprintf("%d-%d", id, time.Now().UnixNano())
@@ -831,6 +1108,62 @@ func (s *DocumentService) ReprocessDocument(id int64) error {
 	return nil
 }
 
+// CancelDocumentProcessing 取消文档当前的解析/向量化任务，文档保留并回到待处理状态（非失败），
+// 已有节点不删除；之后可通过 ReprocessDocument 重新学习。
+func (s *DocumentService) CancelDocumentProcessing(id int64) error {
+	if id <= 0 {
+		return errs.New("error.document_id_required")
+	}
+
+	db, err := s.db()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var m documentModel
+	if err := db.NewSelect().Model(&m).Where("id = ?", id).Scan(ctx); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errs.Newf("error.document_not_found", map[string]any{"ID": id})
+		}
+		return errs.Wrap("error.document_read_failed", err)
+	}
+	if m.ParsingStatus == StatusCompleted && m.EmbeddingStatus == StatusCompleted {
+		return errs.New("error.document_not_processing")
+	}
+
+	// 取消正在进行的任务
+	if tm := taskmanager.Get(); tm != nil {
+		tm.Cancel(fmt.Sprintf("doc:%d", id))
+	}
+
+	// 更换运行 ID：仍在执行或排队中的旧任务会因运行 ID 不匹配而停止，启动时也不会自动恢复
+	runID := fmt.Sprintf("%s%d-%d", cancelledRunIDPrefix, id, time.Now().UnixNano())
+	if _, err := db.NewUpdate().Model(&m).
+		Set("processing_run_id = ?", runID).
+		Set("parsing_status = ?", StatusPending).
+		Set("parsing_progress = ?", 0).
+		Set("parsing_error = ?", "").
+		Set("embedding_status = ?", StatusPending).
+		Set("embedding_progress = ?", 0).
+		Set("embedding_error = ?", "").
+		Set("updated_at = ?", sqlite.NowUTC()).
+		Where("id = ?", id).
+		Exec(ctx); err != nil {
+		return errs.Wrap("error.document_update_failed", err)
+	}
+
+	s.app.Event.Emit("document:progress", ProgressEvent{
+		DocumentID:      id,
+		LibraryID:       m.LibraryID,
+		ParsingStatus:   StatusPending,
+		EmbeddingStatus: StatusPending,
+	})
+	return nil
+}
+
 // DeleteDocument 删除文档
 func (s *DocumentService) DeleteDocument(id int64) error {
 	if id <= 0 {
@@ -892,6 +1225,7 @@ func (s *DocumentService) DeleteDocument(id int64) error {
 	if _, err := db.NewDelete().Model(&m).Where("id = ?", id).Exec(ctx); err != nil {
 		return errs.Wrap("error.document_delete_failed", err)
 	}
+	retrieval.InvalidateLibrary(m.LibraryID)
 
 	return nil
 }
@@ -1051,6 +1385,85 @@ func (s *DocumentService) GetDocumentContent(id int64) (string, error) {
 	return string(content), nil
 }
 
+// 文本预览的字符数上限（按 rune 计）
+const (
+	defaultDocumentTextChars = 20000
+	maxDocumentTextChars     = 200000
+)
+
+// GetDocumentText 返回文档解析后的文本（level 0 原始块按 chunk_order 拼接），用于预览和确认解析结果。
+// maxChars <= 0 时使用默认上限；未解析完成的文档返回 error.document_not_processed。
+func (s *DocumentService) GetDocumentText(id int64, maxChars int) (*DocumentText, error) {
+	if id <= 0 {
+		return nil, errs.New("error.document_id_required")
+	}
+	if maxChars <= 0 {
+		maxChars = defaultDocumentTextChars
+	}
+	maxChars = min(maxChars, maxDocumentTextChars)
+
+	db, err := s.db()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var m documentModel
+	if err := db.NewSelect().Model(&m).Where("id = ?", id).Scan(ctx); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errs.Newf("error.document_not_found", map[string]any{"ID": id})
+		}
+		return nil, errs.Wrap("error.document_read_failed", err)
+	}
+	if m.ParsingStatus != StatusCompleted {
+		return nil, errs.New("error.document_not_processed")
+	}
+
+	rows, err := db.NewSelect().
+		Table("document_nodes").
+		Column("content").
+		Where("document_id = ?", id).
+		Where("level = 0").
+		OrderExpr("chunk_order ASC, id ASC").
+		Rows(ctx)
+	if err != nil {
+		return nil, errs.Wrap("error.document_read_failed", err)
+	}
+	defer rows.Close()
+
+	result := &DocumentText{
+		DocumentID: id,
+		WordTotal:  m.WordTotal,
+		SplitTotal: m.SplitTotal,
+	}
+	var sb strings.Builder
+	remaining := maxChars
+	for rows.Next() {
+		var content string
+		if err := rows.Scan(&content); err != nil {
+			return nil, errs.Wrap("error.document_read_failed", err)
+		}
+		if sb.Len() > 0 {
+			content = "\n\n" + content
+		}
+		runes := []rune(content)
+		if len(runes) > remaining {
+			sb.WriteString(string(runes[:remaining]))
+			result.Truncated = true
+			break
+		}
+		sb.WriteString(content)
+		remaining -= len(runes)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errs.Wrap("error.document_read_failed", err)
+	}
+	result.Text = sb.String()
+	return result, nil
+}
+
 // GetDocumentBytes 获取文档文件二进制内容（base64 编码，用于前端预览 Office 文件）
 func (s *DocumentService) GetDocumentBytes(id int64) (string, error) {
 	if id <= 0 {
@@ -1260,6 +1673,8 @@ func (s *DocumentService) processDocument(jobCtx context.Context, docID, library
 	if release == nil {
 		return
 	}
+	// 节点在处理过程中被重写，结束时（无论成功与否）清除该知识库的检索缓存
+	defer retrieval.InvalidateLibrary(libraryID)
 	defer release()
 
 	tm := taskmanager.Get()
@@ -1290,6 +1705,9 @@ func (s *DocumentService) processDocument(jobCtx context.Context, docID, library
 		return currentRunID == runID
 	}
 
+	// 最近一次嵌入吞吐（由处理器的嵌入批次回调更新）
+	var embedStats atomic.Pointer[processor.EmbeddingStats]
+
 	// 辅助函数：更新状态并发送事件
 	updateAndEmit := func(parsingStatus, parsingProgress int, parsingError string, embeddingStatus, embeddingProgress int, embeddingError string) {
 		if _, err := db.NewUpdate().
@@ -1308,7 +1726,7 @@ func (s *DocumentService) processDocument(jobCtx context.Context, docID, library
 		}
 
 		if tm != nil {
-			tm.Emit("document:progress", ProgressEvent{
+			event := ProgressEvent{
 				DocumentID:        docID,
 				LibraryID:         libraryID,
 				ParsingStatus:     parsingStatus,
@@ -1317,7 +1735,13 @@ func (s *DocumentService) processDocument(jobCtx context.Context, docID, library
 				EmbeddingStatus:   embeddingStatus,
 				EmbeddingProgress: embeddingProgress,
 				EmbeddingError:    embeddingError,
-			})
+			}
+			if st := embedStats.Load(); st != nil {
+				event.EmbeddedChunks = st.Done
+				event.TotalChunks = st.Total
+				event.EmbeddingRate = st.ChunksPerSecond
+			}
+			tm.Emit("document:progress", event)
 		}
 	}
 
@@ -1358,6 +1782,9 @@ func (s *DocumentService) processDocument(jobCtx context.Context, docID, library
 		updateAndEmit(StatusFailed, 0, "创建处理器失败: "+err.Error(), StatusPending, 0, "")
 		return
 	}
+	proc.SetEmbeddingStatsHandler(func(st processor.EmbeddingStats) {
+		embedStats.Store(&st)
+	})
 
 	// Re-read library config on each wait spin so batch_max_documents changes apply and
 	// stale max values from older goroutines cannot shrink the shared gate.
@@ -1410,9 +1837,11 @@ func (s *DocumentService) processDocument(jobCtx context.Context, docID, library
 		lastPhase = phase
 	}
 
-	// 执行文档处理
+	// 执行文档处理（任务被取消时中断进行中的请求）
+	procCtx, stopProc := withTaskCancel(ctx, info)
+	defer stopProc()
 	result, err := proc.ProcessDocument(
-		ctx,
+		procCtx,
 		docID,
 		doc.LocalPath,
 		libraryConfig,
@@ -1433,6 +1862,16 @@ func (s *DocumentService) processDocument(jobCtx context.Context, docID, library
 			switch pe.Phase {
 			case processor.PhaseParsing, processor.PhaseSplitting:
 				updateAndEmit(StatusFailed, 0, errMsg, StatusPending, 0, "")
+			case processor.PhaseEmbedding:
+				updateAndEmit(StatusCompleted, 100, "", StatusFailed, 0, errMsg)
+				// 分段节点已保留时可仅重新向量化（RetryEmbedding / 自动重试）
+				if result != nil && result.NodesKept {
+					s.updateDocumentTotals(ctx, db, docID, runID, result)
+					if err := recordProcessedParams(ctx, db, docID, runID, libraryConfig, nil); err != nil {
+						s.app.Logger.Warn("record processed params failed", "docID", docID, "error", err)
+					}
+					s.scheduleEmbeddingAutoRetry(ctx, db, docID, libraryID, runID)
+				}
 			default:
 				updateAndEmit(StatusCompleted, 100, "", StatusFailed, 0, errMsg)
 			}
@@ -1443,7 +1882,19 @@ func (s *DocumentService) processDocument(jobCtx context.Context, docID, library
 		return
 	}
 
-	// 更新文档统计信息
+	// 更新文档统计信息，并记录本次使用的分段/嵌入参数（ListStaleDocuments 据此判断是否过期）
+	s.updateDocumentTotals(ctx, db, docID, runID, result)
+	if err := recordProcessedParams(ctx, db, docID, runID, libraryConfig, embeddingConfig); err != nil {
+		s.app.Logger.Warn("record processed params failed", "docID", docID, "error", err)
+	}
+
+	// 全部完成
+	resetEmbeddingRetries(docID)
+	updateAndEmit(StatusCompleted, 100, "", StatusCompleted, 100, "")
+}
+
+// updateDocumentTotals 写入字数与分段数（仅限当前运行的任务）
+func (s *DocumentService) updateDocumentTotals(ctx context.Context, db *bun.DB, docID int64, runID string, result *processor.ProcessResult) {
 	if _, err := db.NewUpdate().
 		Table("documents").
 		Set("word_total = ?", result.WordTotal).
@@ -1454,9 +1905,6 @@ func (s *DocumentService) processDocument(jobCtx context.Context, docID, library
 		Exec(ctx); err != nil {
 		s.app.Logger.Warn("update document stats failed", "docID", docID, "error", err)
 	}
-
-	// 全部完成
-	updateAndEmit(StatusCompleted, 100, "", StatusCompleted, 100, "")
 }
 
 // reembedDocument 仅对已有节点重新向量化（不重新解析/分段）
@@ -1464,6 +1912,7 @@ func (s *DocumentService) reembedDocument(docID, libraryID int64, runID string,
 	if info != nil && info.IsCancelled() {
 		return
 	}
+	defer retrieval.InvalidateLibrary(libraryID)
 
 	db, err := s.db()
 	if err != nil {
@@ -1485,6 +1934,7 @@ func (s *DocumentService) reembedDocument(docID, libraryID int64, runID string,
 	parsingProgress := doc.ParsingProgress
 	parsingError := doc.ParsingError
 
+	var embedStats atomic.Pointer[processor.EmbeddingStats]
 	emitProgress := func(status int, progress int, errMsg string) {
 		// Update DB
 		q := db.NewUpdate().
@@ -1501,7 +1951,7 @@ func (s *DocumentService) reembedDocument(docID, libraryID int64, runID string,
 		}
 
 		// Emit event
-		s.app.Event.Emit("document:progress", ProgressEvent{
+		event := ProgressEvent{
 			DocumentID:        docID,
 			LibraryID:         libraryID,
 			ParsingStatus:     parsingStatus,
@@ -1510,7 +1960,13 @@ func (s *DocumentService) reembedDocument(docID, libraryID int64, runID string,
 			EmbeddingStatus:   status,
 			EmbeddingProgress: progress,
 			EmbeddingError:    errMsg,
-		})
+		}
+		if st := embedStats.Load(); st != nil {
+			event.EmbeddedChunks = st.Done
+			event.TotalChunks = st.Total
+			event.EmbeddingRate = st.ChunksPerSecond
+		}
+		s.app.Event.Emit("document:progress", event)
 	}
 
 	// Start embedding
@@ -1529,17 +1985,30 @@ func (s *DocumentService) reembedDocument(docID, libraryID int64, runID string,
 		emitProgress(StatusFailed, 0, "创建处理器失败: "+err.Error())
 		return
 	}
+	proc.SetEmbeddingStatsHandler(func(st processor.EmbeddingStats) {
+		embedStats.Store(&st)
+	})
 
-	err = proc.ReembedDocumentNodes(ctx, docID, embeddingConfig, func(p int) {
+	procCtx, stopProc := withTaskCancel(ctx, info)
+	defer stopProc()
+	err = proc.ReembedDocumentNodes(procCtx, docID, embeddingConfig, func(p int) {
 		if info != nil && info.IsCancelled() {
 			return
 		}
 		emitProgress(StatusProcessing, p, "")
 	})
+	if info != nil && info.IsCancelled() {
+		return
+	}
 	if err != nil {
 		emitProgress(StatusFailed, 0, err.Error())
+		s.scheduleEmbeddingAutoRetry(ctx, db, docID, libraryID, runID)
 		return
 	}
 
+	if err := recordProcessedParams(ctx, db, docID, runID, nil, embeddingConfig); err != nil {
+		s.app.Logger.Warn("record processed params failed", "docID", docID, "error", err)
+	}
+	resetEmbeddingRetries(docID)
 	emitProgress(StatusCompleted, 100, "")
 }
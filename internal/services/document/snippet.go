@@ -0,0 +1,129 @@
+package document
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+	"unicode"
+
+	"chatclaw/internal/fts/tokenizer"
+
+	"github.com/uptrace/bun"
+)
+
+const (
+	// 摘要窗口：命中位置之前/之后保留的字符数
+	snippetBefore = 30
+	snippetAfter  = 90
+	// 参与摘要查询的分段上限，避免关键词过于宽泛时扫描过多节点
+	snippetNodeLimit = 500
+)
+
+// attachMatchSnippets 为搜索结果填充 MatchSnippet：取每个文档中与关键词最相关的分段，截取命中附近的文字。
+// doc_fts / doc_name_fts 是 contentless FTS，snippet()/highlight() 只能返回 NULL，
+// 因此用 FTS 找到相关分段后回表 document_nodes 取原文，再在 Go 中截取并高亮。
+// language 为知识库的分词语言提示，须与生成 matchQuery 时一致。摘要获取失败不影响搜索结果本身。
+func attachMatchSnippets(ctx context.Context, db *bun.DB, libraryID int64, language, matchQuery, keyword string, docs []Document) {
+	if len(docs) == 0 || matchQuery == "" {
+		return
+	}
+	ids := make([]int64, 0, len(docs))
+	for _, d := range docs {
+		ids = append(ids, d.ID)
+	}
+
+	type nodeRow struct {
+		DocumentID int64  `bun:"document_id"`
+		Content    string `bun:"content"`
+	}
+	var rows []nodeRow
+	if err := db.NewRaw(`
+		SELECT dn.document_id, dn.content
+		FROM doc_fts
+		INNER JOIN document_nodes dn ON dn.id = doc_fts.rowid
+		WHERE doc_fts MATCH ? AND dn.level = 0 AND dn.document_id IN (?)
+		ORDER BY doc_fts.rank
+		LIMIT ?
+	`, fmt.Sprintf("(%s) AND library_id:%d", matchQuery, libraryID), bun.In(ids), snippetNodeLimit).Scan(ctx, &rows); err != nil {
+		return
+	}
+
+	// 按相关度排序，每个文档取第一个能截出摘要的分段
+	var terms []string
+	for _, group := range tokenizer.SearchTermGroupsFor(keyword, language) {
+		terms = append(terms, group...)
+	}
+	snippets := make(map[int64]string, len(docs))
+	for _, r := range rows {
+		if _, ok := snippets[r.DocumentID]; ok {
+			continue
+		}
+		if s := buildMatchSnippet(r.Content, terms); s != "" {
+			snippets[r.DocumentID] = s
+		}
+	}
+	for i := range docs {
+		docs[i].MatchSnippet = snippets[docs[i].ID]
+	}
+}
+
+// buildMatchSnippet 截取 content 中第一个命中词附近的文字，HTML 转义后用 <mark> 标出所有命中词。
+// terms 须为小写；没有命中时返回空字符串。
+func buildMatchSnippet(content string, terms []string) string {
+	text := []rune(content)
+	lower := make([]rune, len(text))
+	for i, r := range text {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	// 每个位置命中的最长词长度
+	hits := make([]int, len(lower))
+	first := -1
+	for _, t := range terms {
+		term := []rune(t)
+		n := len(term)
+		if n == 0 {
+			continue
+		}
+		for i := 0; i+n <= len(lower); i++ {
+			if string(lower[i:i+n]) == t && n > hits[i] {
+				hits[i] = n
+				if first < 0 || i < first {
+					first = i
+				}
+			}
+		}
+	}
+	if first < 0 {
+		return ""
+	}
+
+	start := max(first-snippetBefore, 0)
+	end := min(first+snippetAfter, len(text))
+
+	var sb strings.Builder
+	if start > 0 {
+		sb.WriteString("…")
+	}
+	for i := start; i < end; {
+		if n := hits[i]; n > 0 {
+			j := min(i+n, end)
+			sb.WriteString("<mark>")
+			sb.WriteString(html.EscapeString(string(text[i:j])))
+			sb.WriteString("</mark>")
+			i = j
+			continue
+		}
+		r := text[i]
+		if unicode.IsSpace(r) {
+			r = ' '
+		}
+		sb.WriteString(html.EscapeString(string(r)))
+		i++
+	}
+	if end < len(text) {
+		sb.WriteString("…")
+	}
+	return sb.String()
+}
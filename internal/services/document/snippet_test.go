@@ -0,0 +1,14 @@
+package document
+
+import "testing"
+
+func TestBuildMatchSnippet(t *testing.T) {
+	got := buildMatchSnippet("Intro.\nThe <b>Quick</b> fox jumps over the lazy dog", []string{"quick", "dog"})
+	want := "Intro. The &lt;b&gt;<mark>Quick</mark>&lt;/b&gt; fox jumps over the lazy <mark>dog</mark>"
+	if got != want {
+		t.Errorf("buildMatchSnippet() = %q, want %q", got, want)
+	}
+	if got := buildMatchSnippet("nothing here", []string{"quick"}); got != "" {
+		t.Errorf("buildMatchSnippet() without hits = %q, want empty", got)
+	}
+}
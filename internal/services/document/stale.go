@@ -0,0 +1,140 @@
+package document
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"chatclaw/internal/eino/processor"
+	"chatclaw/internal/errs"
+
+	"github.com/uptrace/bun"
+)
+
+// 文档过期原因（StaleDocument.Reasons）
+const (
+	StaleReasonChunkSize      = "chunk_size"
+	StaleReasonChunkOverlap   = "chunk_overlap"
+	StaleReasonEmbeddingModel = "embedding_model"
+	StaleReasonEmbeddingDim   = "embedding_dimension"
+)
+
+// StaleDocument 处理参数与当前配置不一致、需要重新学习的文档
+type StaleDocument struct {
+	ID           int64    `json:"id"`
+	OriginalName string   `json:"original_name"`
+	Reasons      []string `json:"reasons"`
+
+	ProcessedChunkSize           int    `json:"processed_chunk_size"`
+	ProcessedChunkOverlap        int    `json:"processed_chunk_overlap"`
+	ProcessedEmbeddingProviderID string `json:"processed_embedding_provider_id"`
+	ProcessedEmbeddingModelID    string `json:"processed_embedding_model_id"`
+	ProcessedEmbeddingDimension  int    `json:"processed_embedding_dimension"`
+}
+
+// ListStaleDocuments 列出知识库中已学习完成、但分段参数（chunk_size/chunk_overlap）与知识库当前配置
+// 或嵌入模型与全局嵌入配置不一致的文档。分段参数变化需 ReprocessDocument，仅嵌入模型变化时也可只重新向量化。
+// 从未记录过处理参数的文档（processed_chunk_size 为 0）无法判断，不计入。
+func (s *DocumentService) ListStaleDocuments(libraryID int64) ([]StaleDocument, error) {
+	if libraryID <= 0 {
+		return nil, errs.New("error.library_id_required")
+	}
+
+	db, err := s.db()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	libraryConfig, err := processor.GetLibraryConfig(ctx, db, libraryID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errs.Newf("error.library_not_found", map[string]any{"ID": libraryID})
+		}
+		return nil, errs.Wrap("error.library_read_failed", err)
+	}
+	embeddingConfig, err := processor.GetEmbeddingConfig(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	type docRow struct {
+		ID                  int64  `bun:"id"`
+		OriginalName        string `bun:"original_name"`
+		ChunkSize           int    `bun:"processed_chunk_size"`
+		ChunkOverlap        int    `bun:"processed_chunk_overlap"`
+		EmbeddingProviderID string `bun:"processed_embedding_provider_id"`
+		EmbeddingModelID    string `bun:"processed_embedding_model_id"`
+		EmbeddingDimension  int    `bun:"processed_embedding_dimension"`
+	}
+	var rows []docRow
+	if err := db.NewSelect().
+		Table("documents").
+		Column("id", "original_name", "processed_chunk_size", "processed_chunk_overlap",
+			"processed_embedding_provider_id", "processed_embedding_model_id", "processed_embedding_dimension").
+		Where("library_id = ?", libraryID).
+		Where("parsing_status = ?", StatusCompleted).
+		Where("embedding_status = ?", StatusCompleted).
+		Where("processed_chunk_size > 0").
+		OrderExpr("id ASC").
+		Scan(ctx, &rows); err != nil {
+		return nil, errs.Wrap("error.document_read_failed", err)
+	}
+
+	out := make([]StaleDocument, 0)
+	for _, r := range rows {
+		d := StaleDocument{
+			ID:                           r.ID,
+			OriginalName:                 r.OriginalName,
+			ProcessedChunkSize:           r.ChunkSize,
+			ProcessedChunkOverlap:        r.ChunkOverlap,
+			ProcessedEmbeddingProviderID: r.EmbeddingProviderID,
+			ProcessedEmbeddingModelID:    r.EmbeddingModelID,
+			ProcessedEmbeddingDimension:  r.EmbeddingDimension,
+		}
+		if d.ProcessedChunkSize != libraryConfig.ChunkSize {
+			d.Reasons = append(d.Reasons, StaleReasonChunkSize)
+		}
+		if d.ProcessedChunkOverlap != libraryConfig.ChunkOverlap {
+			d.Reasons = append(d.Reasons, StaleReasonChunkOverlap)
+		}
+		if d.ProcessedEmbeddingProviderID != embeddingConfig.ProviderID || d.ProcessedEmbeddingModelID != embeddingConfig.ModelID {
+			d.Reasons = append(d.Reasons, StaleReasonEmbeddingModel)
+		}
+		if d.ProcessedEmbeddingDimension != embeddingConfig.Dimension {
+			d.Reasons = append(d.Reasons, StaleReasonEmbeddingDim)
+		}
+		if len(d.Reasons) > 0 {
+			out = append(out, d)
+		}
+	}
+	return out, nil
+}
+
+// recordProcessedParams 记录文档本次处理使用的分段参数与嵌入模型（仅限当前运行的任务）。
+// libraryConfig 为 nil 时只记录嵌入模型（仅重新向量化），embeddingConfig 为 nil 时只记录分段参数。
+func recordProcessedParams(ctx context.Context, db bun.IDB, docID int64, runID string, libraryConfig *processor.LibraryConfig, embeddingConfig *processor.EmbeddingConfig) error {
+	if libraryConfig == nil && embeddingConfig == nil {
+		return nil
+	}
+	q := db.NewUpdate().
+		Table("documents").
+		Where("id = ?", docID)
+	if runID != "" {
+		q = q.Where("processing_run_id = ?", runID)
+	}
+	if libraryConfig != nil {
+		q = q.Set("processed_chunk_size = ?", libraryConfig.ChunkSize).
+			Set("processed_chunk_overlap = ?", libraryConfig.ChunkOverlap)
+	}
+	if embeddingConfig != nil {
+		q = q.Set("processed_embedding_provider_id = ?", embeddingConfig.ProviderID).
+			Set("processed_embedding_model_id = ?", embeddingConfig.ModelID).
+			Set("processed_embedding_dimension = ?", embeddingConfig.Dimension)
+	}
+	_, err := q.Exec(ctx)
+	return err
+}
@@ -0,0 +1,276 @@
+package document
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"chatclaw/internal/define"
+	"chatclaw/internal/errs"
+	"chatclaw/internal/sqlite"
+	"chatclaw/internal/taskmanager"
+
+	"github.com/uptrace/bun"
+)
+
+// DocumentsDirSettingKey 自定义文档存储目录的设置项（为空时使用应用数据目录下的 documents）
+const DocumentsDirSettingKey = "documents_dir"
+
+// documentsDirMu 保护存储目录：迁移持写锁，读取目录并写入文件的一方（上传、导入）持读锁，
+// 避免文件写到正在迁移的旧目录
+var documentsDirMu sync.RWMutex
+
+// DefaultDocumentsDir 返回默认的文档存储目录
+func DefaultDocumentsDir() (string, error) {
+	dir, err := define.AppDataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "documents"), nil
+}
+
+// DocumentsDir 读取设置中的文档存储目录（未设置时使用默认目录），并确保目录存在。
+// 直接读 settings 表而不走 settings 包的缓存：settings 包依赖本包，不能反向引用。
+func DocumentsDir(ctx context.Context, db bun.IDB) (string, error) {
+	var value sql.NullString
+	err := db.NewSelect().
+		TableExpr("settings").
+		Column("value").
+		Where("key = ?", DocumentsDirSettingKey).
+		Scan(ctx, &value)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return "", errs.Wrap("error.document_dir_failed", err)
+	}
+
+	dir := strings.TrimSpace(value.String)
+	if dir == "" {
+		if dir, err = DefaultDocumentsDir(); err != nil {
+			return "", errs.Wrap("error.document_dir_failed", err)
+		}
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", errs.Wrap("error.document_dir_failed", err)
+	}
+	return dir, nil
+}
+
+// AcquireDocumentsDir 持读锁返回文档存储目录，调用方写完文件和 local_path 后调用 release。
+// 持锁期间不能再调用 GetDocumentsDir 等会加锁的方法。
+func AcquireDocumentsDir(ctx context.Context, db bun.IDB) (dir string, release func(), err error) {
+	documentsDirMu.RLock()
+	dir, err = DocumentsDir(ctx, db)
+	if err != nil {
+		documentsDirMu.RUnlock()
+		return "", nil, err
+	}
+	return dir, documentsDirMu.RUnlock, nil
+}
+
+// movedFile 记录一次文件迁移，用于失败时回滚、成功后清理
+type movedFile struct {
+	src, dst string
+	copied   bool // true: 跨盘复制（源文件待提交后删除）；false: 同盘重命名
+}
+
+// MoveDocumentsDir 将文档存储目录迁移到 newPath：
+// 1. 把 local_path 位于旧目录下的文件按原相对路径移动到新目录（同盘重命名，跨盘复制）
+// 2. 在同一事务中改写 documents.local_path 和 documents_dir 设置
+// 3. 事务失败时撤销已移动的文件；成功后删除跨盘复制留下的源文件及空目录
+// 返回迁移后的目录。有文档正在解析/向量化或有排队中的文档任务时拒绝迁移；
+// 迁移期间文档和缩略图任务暂停，新提交的任务在迁移结束后才入队。
+func MoveDocumentsDir(ctx context.Context, db *bun.DB, newPath string) (string, error) {
+	newPath = strings.TrimSpace(newPath)
+	if newPath == "" || !filepath.IsAbs(newPath) {
+		return "", errs.New("error.document_dir_invalid")
+	}
+	newDir := filepath.Clean(newPath)
+
+	documentsDirMu.Lock()
+	defer documentsDirMu.Unlock()
+
+	oldDir, err := DocumentsDir(ctx, db)
+	if err != nil {
+		return "", err
+	}
+	oldDir = filepath.Clean(oldDir)
+	if oldDir == newDir {
+		return newDir, nil
+	}
+	if isSubPath(oldDir, newDir) || isSubPath(newDir, oldDir) {
+		return "", errs.New("error.document_dir_nested")
+	}
+
+	tm := taskmanager.Get()
+	for _, prefix := range []string{"doc:", "thumb:"} {
+		resume, n := tm.Pause(prefix)
+		if n > 0 {
+			return "", errs.Newf("error.document_dir_busy", map[string]any{"Count": n})
+		}
+		defer resume()
+	}
+
+	busy, err := db.NewSelect().
+		Table("documents").
+		Where("parsing_status = ? OR embedding_status = ?", StatusProcessing, StatusProcessing).
+		Count(ctx)
+	if err != nil {
+		return "", errs.Wrap("error.document_dir_move_failed", err)
+	}
+	if busy > 0 {
+		return "", errs.Newf("error.document_dir_busy", map[string]any{"Count": busy})
+	}
+
+	if err := os.MkdirAll(newDir, 0o755); err != nil {
+		return "", errs.Wrap("error.document_dir_move_failed", err)
+	}
+
+	type row struct {
+		ID        int64  `bun:"id"`
+		LocalPath string `bun:"local_path"`
+	}
+	var rows []row
+	if err := db.NewSelect().
+		Table("documents").
+		Column("id", "local_path").
+		Where("local_path IS NOT NULL AND local_path != ''").
+		Scan(ctx, &rows); err != nil {
+		return "", errs.Wrap("error.document_dir_move_failed", err)
+	}
+
+	// 第一步：移动文件（源文件缺失的记录同样改写路径，保持与新目录一致）
+	moved := make([]movedFile, 0, len(rows))
+	newPaths := make(map[int64]string, len(rows))
+	rollback := func() {
+		for i := len(moved) - 1; i >= 0; i-- {
+			if moved[i].copied {
+				os.Remove(moved[i].dst)
+			} else {
+				os.Rename(moved[i].dst, moved[i].src)
+			}
+		}
+	}
+	for _, r := range rows {
+		rel, err := filepath.Rel(oldDir, r.LocalPath)
+		if err != nil || !isSubPath(oldDir, r.LocalPath) {
+			// 不在旧目录下的文件（例如历史数据）保持原样
+			continue
+		}
+		dst := filepath.Join(newDir, rel)
+		newPaths[r.ID] = dst
+		if _, err := os.Stat(r.LocalPath); os.IsNotExist(err) {
+			continue
+		}
+		if _, err := os.Stat(dst); err == nil {
+			rollback()
+			return "", errs.Newf("error.document_dir_file_exists", map[string]any{"Path": dst})
+		}
+		m, err := moveDocumentFile(r.LocalPath, dst)
+		if err != nil {
+			rollback()
+			return "", errs.Wrap("error.document_dir_move_failed", err)
+		}
+		moved = append(moved, m)
+	}
+
+	// 第二步：事务内改写路径与设置
+	err = db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		for id, p := range newPaths {
+			if _, err := tx.NewUpdate().
+				Table("documents").
+				Set("local_path = ?", p).
+				Where("id = ?", id).
+				Exec(ctx); err != nil {
+				return err
+			}
+		}
+		res, err := tx.NewUpdate().
+			Table("settings").
+			Set("value = ?", newDir).
+			Set("updated_at = ?", sqlite.NowUTC()).
+			Where("key = ?", DocumentsDirSettingKey).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return fmt.Errorf("setting %q not found", DocumentsDirSettingKey)
+		}
+		return nil
+	})
+	if err != nil {
+		rollback()
+		return "", errs.Wrap("error.document_dir_move_failed", err)
+	}
+
+	// 第三步：清理跨盘复制的源文件和旧目录下的空目录
+	for _, m := range moved {
+		if m.copied {
+			os.Remove(m.src)
+		}
+	}
+	removeEmptyDirs(oldDir)
+	return newDir, nil
+}
+
+// moveDocumentFile 优先重命名；跨盘等重命名失败的情况退回复制
+func moveDocumentFile(src, dst string) (movedFile, error) {
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return movedFile{}, err
+	}
+	if err := os.Rename(src, dst); err == nil {
+		return movedFile{src: src, dst: dst}, nil
+	}
+	if err := copyDocumentFile(src, dst); err != nil {
+		os.Remove(dst)
+		return movedFile{}, err
+	}
+	return movedFile{src: src, dst: dst, copied: true}, nil
+}
+
+func copyDocumentFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// isSubPath 判断 path 是否位于 dir 之内（不含 dir 本身）
+func isSubPath(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil || rel == "." {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) && !filepath.IsAbs(rel)
+}
+
+// removeEmptyDirs 自底向上删除 root 下（含 root）的空目录，非空目录保持不动
+func removeEmptyDirs(root string) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		if e.IsDir() {
+			removeEmptyDirs(filepath.Join(root, e.Name()))
+		}
+	}
+	// 非空时 os.Remove 会失败，忽略即可
+	os.Remove(root)
+}
@@ -0,0 +1,95 @@
+package floatingball
+
+import (
+	"time"
+
+	"chatclaw/internal/safego"
+)
+
+// ClickThroughSettingKey 贴边缩小时点击穿透（默认关闭）：缩小的悬浮球不再拦截鼠标，点击落到下方的应用；
+// 鼠标移到悬浮球上时恢复交互并展开。穿透期间窗口收不到鼠标事件，悬停改为轮询鼠标位置判断，
+// 无法获取全局鼠标位置的平台不穿透。
+const ClickThroughSettingKey = "floating_ball_clickthrough_when_collapsed"
+
+const clickThroughPollInterval = 100 * time.Millisecond
+
+// SetClickThrough 切换贴边缩小时点击穿透（前端写入 floating_ball_clickthrough_when_collapsed 后调用，立即生效）
+func (s *FloatingBallService) SetClickThrough(enabled bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.clickThrough = enabled
+	s.applyClickThroughLocked()
+}
+
+func (s *FloatingBallService) clickThroughActiveLocked() bool {
+	return s.clickThrough && s.win != nil && s.visible && s.dock != DockNone
+}
+
+// applyClickThroughLocked 按当前状态切换 IgnoreMouseEvents：仅在开启设置且贴边缩小时穿透。
+// 贴边期间（无论是否缩小）保持轮询，用于穿透时的悬停展开和展开后漏掉的移出。
+func (s *FloatingBallService) applyClickThroughLocked() {
+	if s.win == nil {
+		return
+	}
+	active := s.clickThroughActiveLocked()
+	if active {
+		if _, ok := cursorInWindow(s.win); !ok {
+			active = false
+		}
+	}
+	s.setIgnoreMouseLocked(active && s.collapsed)
+
+	if !active {
+		if s.clickThroughTimer != nil {
+			s.clickThroughTimer.Stop()
+			s.clickThroughTimer = nil
+		}
+		return
+	}
+	if s.clickThroughTimer == nil {
+		s.clickThroughTimer = time.AfterFunc(clickThroughPollInterval, safego.Func("floatingball.clickthrough", func() {
+			s.clickThroughTick()
+		}, s.resetAfterPanic))
+	}
+}
+
+func (s *FloatingBallService) setIgnoreMouseLocked(ignore bool) {
+	if s.ignoringMouse == ignore || s.win == nil {
+		return
+	}
+	s.debugLog("clickthrough", map[string]any{"ignore": ignore, "dock": s.dock, "collapsed": s.collapsed})
+	s.ignoringMouse = ignore
+	s.win.SetIgnoreMouseEvents(ignore)
+}
+
+func (s *FloatingBallService) clickThroughTick() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.clickThroughTimer = nil
+	if !s.clickThroughActiveLocked() {
+		s.applyClickThroughLocked()
+		return
+	}
+	inside, ok := cursorInWindow(s.win)
+	switch {
+	case !ok || s.dragging:
+	case s.collapsed && inside:
+		// 悬停：恢复交互并展开，之后由前端 Hover(false) 触发回缩（回缩后重新穿透）
+		s.setIgnoreMouseLocked(false)
+		if s.idleDockTimer != nil {
+			s.idleDockTimer.Stop()
+			s.idleDockTimer = nil
+		}
+		s.hovered = true
+		s.lastHoverEnterAt = time.Now()
+		s.lastHoverEnterWasCollapsed = true
+		s.expandLocked()
+	case !s.collapsed && !inside && s.hovered && s.rehideTimer == nil:
+		// 恢复交互时鼠标已在窗口内，webview 可能收不到 pointerenter/leave：按移出处理
+		s.hovered = false
+		s.scheduleRehideLocked()
+	}
+	s.applyClickThroughLocked()
+}
@@ -0,0 +1,35 @@
+//go:build darwin && !ios
+
+package floatingball
+
+/*
+#cgo CFLAGS: -x objective-c
+#cgo LDFLAGS: -framework Cocoa
+
+#include <stdbool.h>
+
+bool floatingballCursorInWindow(void *nsWindowPtr, bool *outInside);
+*/
+import "C"
+
+import (
+	"unsafe"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// cursorInWindow 鼠标是否在窗口范围内（点击穿透时窗口收不到鼠标事件，用轮询判断悬停）
+func cursorInWindow(win *application.WebviewWindow) (inside bool, ok bool) {
+	if win == nil {
+		return false, false
+	}
+	nw := win.NativeWindow()
+	if nw == nil {
+		return false, false
+	}
+	var in C.bool
+	if !C.floatingballCursorInWindow(unsafe.Pointer(nw), &in) {
+		return false, false
+	}
+	return bool(in), true
+}
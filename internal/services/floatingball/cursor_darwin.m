@@ -0,0 +1,23 @@
+#import <Cocoa/Cocoa.h>
+#import <stdbool.h>
+#import <dispatch/dispatch.h>
+
+// mouseLocation and the window frame are both in Cocoa global coordinates.
+bool floatingballCursorInWindow(void *nsWindowPtr, bool *outInside) {
+  if (nsWindowPtr == NULL) return false;
+  if (![NSThread isMainThread]) {
+    __block bool ok = false;
+    dispatch_sync(dispatch_get_main_queue(), ^{
+      ok = floatingballCursorInWindow(nsWindowPtr, outInside);
+    });
+    return ok;
+  }
+  @autoreleasepool {
+    NSWindow *win = (__bridge NSWindow *)nsWindowPtr;
+    if (win == nil) return false;
+    NSRect fr = [win frame];
+    if (fr.size.width <= 0 || fr.size.height <= 0) return false;
+    if (outInside) *outInside = NSPointInRect([NSEvent mouseLocation], fr);
+    return true;
+  }
+}
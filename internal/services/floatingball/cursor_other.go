@@ -0,0 +1,10 @@
+//go:build !windows && (!darwin || ios)
+
+package floatingball
+
+import "github.com/wailsapp/wails/v3/pkg/application"
+
+// cursorInWindow 其他平台无法获取全局鼠标位置，点击穿透不生效
+func cursorInWindow(_ *application.WebviewWindow) (inside bool, ok bool) {
+	return false, false
+}
@@ -0,0 +1,35 @@
+//go:build windows
+
+package floatingball
+
+import (
+	"unsafe"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+var (
+	procGetCursorPos  = user32.NewProc("GetCursorPos")
+	procGetWindowRect = user32.NewProc("GetWindowRect")
+)
+
+// cursorInWindow 鼠标是否在窗口范围内（点击穿透时窗口收不到鼠标事件，用轮询判断悬停）。
+// 进程为 per-monitor DPI aware，GetCursorPos 与 GetWindowRect 都是物理像素，可直接比较。
+func cursorInWindow(win *application.WebviewWindow) (inside bool, ok bool) {
+	if win == nil {
+		return false, false
+	}
+	nw := win.NativeWindow()
+	if nw == nil {
+		return false, false
+	}
+	var pt struct{ X, Y int32 }
+	if r, _, _ := procGetCursorPos.Call(uintptr(unsafe.Pointer(&pt))); r == 0 {
+		return false, false
+	}
+	var rc struct{ Left, Top, Right, Bottom int32 }
+	if r, _, _ := procGetWindowRect.Call(uintptr(unsafe.Pointer(nw)), uintptr(unsafe.Pointer(&rc))); r == 0 {
+		return false, false
+	}
+	return pt.X >= rc.Left && pt.X < rc.Right && pt.Y >= rc.Top && pt.Y < rc.Bottom, true
+}
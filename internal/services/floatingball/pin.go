@@ -0,0 +1,124 @@
+package floatingball
+
+import (
+	"strings"
+	"time"
+
+	"chatclaw/internal/services/settings"
+)
+
+// PinCornerSettingKey 固定模式：悬浮球固定在主屏工作区的某个角落，不贴边、不缩小、不可拖动，
+// 分辨率/工作区变化后重新定位到该角落。空值表示不固定（默认）。
+// 取值需与 settings.SetValue 中的校验保持一致。
+const PinCornerSettingKey = "floating_ball_pin_corner"
+
+const (
+	PinCornerNone        = ""
+	PinCornerTopLeft     = "top-left"
+	PinCornerTopRight    = "top-right"
+	PinCornerBottomLeft  = "bottom-left"
+	PinCornerBottomRight = "bottom-right"
+)
+
+// normalizePinCorner 无法识别的值视为不固定
+func normalizePinCorner(v string) string {
+	switch v = strings.ToLower(strings.TrimSpace(v)); v {
+	case PinCornerTopLeft, PinCornerTopRight, PinCornerBottomLeft, PinCornerBottomRight:
+		return v
+	}
+	return PinCornerNone
+}
+
+// GetPinCorner 返回当前固定的角落（未固定为空字符串）
+func (s *FloatingBallService) GetPinCorner() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.pinCorner
+}
+
+// SetPinCorner 切换固定模式（前端写入 floating_ball_pin_corner 后调用，立即生效）。
+// 固定时取消贴边/缩小并移动到对应角落；取消固定时悬浮球留在原处，之后的拖动恢复正常贴边逻辑。
+func (s *FloatingBallService) SetPinCorner(corner string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pinCorner = normalizePinCorner(corner)
+	if s.pinCorner == PinCornerNone {
+		return
+	}
+	s.lastDock = DockNone
+	s.lastCollapsed = false
+	if s.win == nil || !s.visible {
+		return
+	}
+	s.applyPinLocked()
+}
+
+// applyPinLocked 以完整大小移动到固定角落，并停止所有贴边/回缩计时器
+func (s *FloatingBallService) applyPinLocked() {
+	if s.win == nil {
+		return
+	}
+	for _, t := range []**time.Timer{&s.snapTimer, &s.rehideTimer, &s.idleDockTimer} {
+		if *t != nil {
+			(*t).Stop()
+			*t = nil
+		}
+	}
+	s.dock = DockNone
+	s.collapsed = false
+	s.setSizeLocked(ballSize, ballSize)
+
+	x, y := s.pinnedPositionLocked()
+	s.debugLog("pin:apply", map[string]any{"corner": s.pinCorner, "x": x, "y": y})
+	if cx, cy := s.safeRelativePositionLocked(); cx == x && cy == y {
+		return
+	}
+	s.setRelativePositionLocked(x, y)
+}
+
+// pinnedPositionLocked 固定角落对应的位置（相对主屏工作区，DIP），与边缘保留 defaultMargin
+func (s *FloatingBallService) pinnedPositionLocked() (int, int) {
+	work, ok := s.workAreaLocked()
+	if !ok {
+		return 0, 0
+	}
+	left, top := defaultMargin, defaultMargin
+	right := max(work.Width-ballSize-defaultMargin, 0)
+	bottom := max(work.Height-ballSize-defaultMargin, 0)
+	switch s.pinCorner {
+	case PinCornerTopLeft:
+		return left, top
+	case PinCornerTopRight:
+		return right, top
+	case PinCornerBottomLeft:
+		return left, bottom
+	default:
+		return right, bottom
+	}
+}
+
+// onScreenChanged 分辨率/缩放/显示器变化：重新获取主屏工作区，固定模式下重新定位到角落，否则把悬浮球限制回工作区内
+func (s *FloatingBallService) onScreenChanged() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// 标记缓存过期：workAreaLocked 会重新查询，查询不到时仍沿用旧值
+	s.primaryWorkAreaSource = "stale"
+	if s.win == nil || !s.visible {
+		return
+	}
+	s.debugLog("screen:changed", map[string]any{"pin": s.pinCorner})
+	if s.pinCorner != PinCornerNone {
+		s.applyPinLocked()
+		return
+	}
+	if clamped, relX, relY := s.clampToPrimaryDipLocked("screen"); clamped {
+		s.snapAfterMoveAtLocked(relX, relY)
+	}
+}
+
+func pinCornerFromSettings() string {
+	v, _ := settings.GetValue(PinCornerSettingKey)
+	return normalizePinCorner(v)
+}
@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"chatclaw/internal/define"
+	"chatclaw/internal/hotkey"
+	"chatclaw/internal/safego"
 	"chatclaw/internal/services/settings"
 
 	"github.com/wailsapp/wails/v3/pkg/application"
@@ -25,6 +27,7 @@ const (
 	windowName = "floatingball"
 
 	// UI/behavior tuning (DIP pixels)
+	// edgeSnapGap / rehideDebounce / idleDockDelay are defaults, see tuning.go
 	ballSize        = 64
 	defaultMargin   = 24 // breathing room from screen right edge
 	edgeSnapGap     = 24
@@ -47,6 +50,22 @@ const (
 	dragClampEpsilonDip  = 10
 )
 
+// AutoCollapseSettingKey 是否自动贴边缩小（默认开启）。关闭后悬浮球始终保持完整大小，
+// 拖到哪里停在哪里，只限制在工作区内。
+const AutoCollapseSettingKey = "floating_ball_auto_collapse"
+
+// HotkeySettingKey 切换悬浮球显示/隐藏的全局快捷键（如 "CmdOrCtrl+Shift+B"，空为不启用）。
+// 修改设置时由 settings 通过 hotkey.Update 立即重新注册。
+const HotkeySettingKey = "floating_ball_hotkey"
+
+// OpenGestureSettingKey 唤起主窗口的点击方式："double"（默认，双击）或 "single"（单击）。
+// 拖动结束时的 pointerup 不算点击（由 SetDragging(false) 的位移判定）。
+const (
+	OpenGestureSettingKey = "floating_ball_open_gesture"
+	OpenGestureSingle     = "single"
+	OpenGestureDouble     = "double"
+)
+
 // FloatingBallService 悬浮球服务（暴露给前端调用）
 //
 // 职责：
@@ -66,6 +85,10 @@ type FloatingBallService struct {
 	hovered bool
 	collapsed bool
 	appActive bool
+	autoCollapse bool
+	pinCorner string // 固定模式的角落，空为不固定（见 pin.go）
+	clickThrough  bool // 贴边缩小时点击穿透（见 clickthrough.go）
+	ignoringMouse bool
 	dragging bool
 	dragStartX int
 	dragStartY int
@@ -91,6 +114,7 @@ type FloatingBallService struct {
 	idleDockTimer   *time.Timer
 	repositionTimer *time.Timer
 	repositionTries int
+	clickThroughTimer *time.Timer
 
 	// windows: enforce size after resize requests (webview2/frameless can lag)
 	sizeEnforceTimer *time.Timer
@@ -111,6 +135,29 @@ type FloatingBallService struct {
 	loggedScreenProbe       bool
 }
 
+// resetAfterPanic 定时回调 panic 后重置拖拽/悬停等瞬时状态并停止所有定时器，
+// 悬浮球保持当前位置，下一次移动/悬停事件会重新驱动状态机。
+// 回调都通过 defer 释放 s.mu，panic 展开后锁已释放。
+func (s *FloatingBallService) resetAfterPanic(any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, t := range []**time.Timer{&s.snapTimer, &s.rehideTimer, &s.idleDockTimer, &s.repositionTimer, &s.sizeEnforceTimer, &s.clickThroughTimer} {
+		if *t != nil {
+			(*t).Stop()
+			*t = nil
+		}
+	}
+	s.dragging = false
+	s.dragMoved = false
+	s.hovered = false
+	s.repositionTries = 0
+	s.sizeEnforceTries = 0
+	s.ignoreMoveUntil = time.Time{}
+	// 不能让悬浮球停留在无法点击的状态
+	s.setIgnoreMouseLocked(false)
+}
+
 func (s *FloatingBallService) debugEnabled() bool {
 	// Enable via environment variable (preferred for local debugging):
 	//   CHATCLAW_DEBUG_FLOATINGBALL=1
@@ -238,13 +285,30 @@ func NewFloatingBallService(app *application.App, mainWindow *application.Webvie
 		visible:    false,
 		dock:       DockNone,
 		appActive:  true,
+		autoCollapse: true,
 	}
 }
 
 // InitFromSettings 根据 settings 内存缓存初始化悬浮球显示状态
 func (s *FloatingBallService) InitFromSettings() {
+	s.mu.Lock()
+	s.autoCollapse = settings.GetBool(AutoCollapseSettingKey, true)
+	s.pinCorner = pinCornerFromSettings()
+	s.clickThrough = settings.GetBool(ClickThroughSettingKey, false)
+	s.mu.Unlock()
 	visible := settings.GetBool("show_floating_window", false)
 	_ = s.SetVisible(visible)
+
+	accel, _ := settings.GetValue(HotkeySettingKey)
+	if err := hotkey.Set(HotkeySettingKey, accel, s.ToggleVisible); err != nil {
+		s.app.Logger.Warn("[floatingball] register hotkey failed", "hotkey", accel, "error", err)
+	}
+}
+
+// ToggleVisible 切换悬浮球显示/隐藏（全局快捷键触发）。只改变本次运行的状态，不写入 show_floating_window，
+// 便于屏幕共享时临时隐藏。
+func (s *FloatingBallService) ToggleVisible() {
+	_ = s.SetVisible(!s.IsVisible())
 }
 
 // IsVisible 返回悬浮球窗口是否可见
@@ -284,6 +348,7 @@ func (s *FloatingBallService) SetVisible(visible bool) error {
 		s.collapsed = false
 		s.dragging = false
 		s.dragMoved = false
+		s.applyClickThroughLocked()
 		return nil
 	}
 
@@ -304,6 +369,35 @@ func (s *FloatingBallService) SetVisible(visible bool) error {
 	return nil
 }
 
+// SetAutoCollapse 切换自动贴边缩小（前端写入 floating_ball_auto_collapse 后调用，立即生效）。
+// 关闭时停止待执行的回缩/空闲缩小，已缩小或贴边的悬浮球恢复完整大小并留在工作区内。
+func (s *FloatingBallService) SetAutoCollapse(enabled bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.autoCollapse = enabled
+	if enabled {
+		return
+	}
+	if s.rehideTimer != nil {
+		s.rehideTimer.Stop()
+		s.rehideTimer = nil
+	}
+	if s.idleDockTimer != nil {
+		s.idleDockTimer.Stop()
+		s.idleDockTimer = nil
+	}
+	s.lastCollapsed = false
+	if s.win == nil || !s.visible || s.dock == DockNone {
+		s.collapsed = false
+		return
+	}
+	// expand 按贴边方向定位到工作区边缘，之后视为自由悬浮
+	s.expandLocked()
+	s.dock = DockNone
+	s.collapsed = false
+}
+
 // Hover 通知后端鼠标是否移入悬浮球（用于贴边展开/回缩）
 func (s *FloatingBallService) Hover(entered bool) {
 	s.mu.Lock()
@@ -355,17 +449,21 @@ func (s *FloatingBallService) Hover(entered bool) {
 			s.debugLog("Hover:spurious_leave", map[string]any{"enterAgeMs": enterAgeMs})
 			return
 		}
-		// Schedule rehide when mouse leaves a docked-but-expanded ball.
-		if s.dock != DockNone && !s.collapsed && !s.dragging {
-			s.rehideTimer = time.AfterFunc(rehideDebounce, func() {
-				s.mu.Lock()
-				defer s.mu.Unlock()
-				if s.win == nil || !s.visible || s.hovered || s.dragging {
-					return
-				}
-				s.rehideLocked()
-			})
-		}
+		s.scheduleRehideLocked()
+	}
+}
+
+// scheduleRehideLocked schedules rehide when the mouse leaves a docked-but-expanded ball.
+func (s *FloatingBallService) scheduleRehideLocked() {
+	if s.autoCollapse && s.dock != DockNone && !s.collapsed && !s.dragging {
+		s.rehideTimer = time.AfterFunc(rehideDelay(), safego.Func("floatingball.rehide", func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			if s.win == nil || !s.visible || s.hovered || s.dragging {
+				return
+			}
+			s.rehideLocked()
+		}, s.resetAfterPanic))
 	}
 }
 
@@ -445,9 +543,9 @@ func (s *FloatingBallService) SetDragging(dragging bool) {
 	s.dock = DockNone
 
 	// 拖拽结束：稍作延迟等待系统最终位置稳定，然后立刻判断贴边/对齐（不在这里缩小）
-	time.AfterFunc(60*time.Millisecond, func() {
+	time.AfterFunc(60*time.Millisecond, safego.Func("floatingball.drag_end_snap", func() {
 		s.dragEndSnap()
-	})
+	}, s.resetAfterPanic))
 }
 
 func (s *FloatingBallService) dragEndSnap() {
@@ -490,7 +588,34 @@ func (s *FloatingBallService) CloseFromUI() {
 	_ = s.SetVisible(false)
 }
 
-// OpenMainFromUI 前端双击悬浮球，唤起主窗口
+// GetOpenGesture 返回当前唤起主窗口的点击方式（OpenGestureSingle 或 OpenGestureDouble）
+func (s *FloatingBallService) GetOpenGesture() string {
+	if v, ok := settings.GetValue(OpenGestureSettingKey); ok && v == OpenGestureSingle {
+		return OpenGestureSingle
+	}
+	return OpenGestureDouble
+}
+
+// ActivateFromUI 前端每次点击悬浮球时调用（clickCount 为 click 事件的 detail，1=单击，2=双击），
+// 前端需在 SetDragging(false) 完成后再调用。点击次数与设置的打开方式一致、且本次不是拖动时唤起主窗口。
+func (s *FloatingBallService) ActivateFromUI(clickCount int) {
+	s.mu.Lock()
+	moved := s.dragMoved
+	s.mu.Unlock()
+	if moved {
+		return
+	}
+	want := 2
+	if s.GetOpenGesture() == OpenGestureSingle {
+		want = 1
+	}
+	if clickCount != want {
+		return
+	}
+	s.OpenMainFromUI()
+}
+
+// OpenMainFromUI 唤起主窗口
 func (s *FloatingBallService) OpenMainFromUI() {
 	if s.mainWindow == nil {
 		return
@@ -519,6 +644,10 @@ func (s *FloatingBallService) DragMoveTo(relX, relY int) {
 	if s.win == nil || !s.visible {
 		return
 	}
+	// Pinned to a corner: the ball cannot be dragged away.
+	if s.pinCorner != PinCornerNone {
+		return
+	}
 	work, ok := s.workAreaLocked()
 	if !ok {
 		return
@@ -617,6 +746,13 @@ func (s *FloatingBallService) ensureLocked() *application.WebviewWindow {
 	w.RegisterHook(events.Common.WindowDidMove, func(_ *application.WindowEvent) {
 		s.onWindowDidMove()
 	})
+	// 分辨率/缩放变化：重新定位（固定模式回到角落）
+	w.RegisterHook(events.Common.WindowDPIChanged, func(_ *application.WindowEvent) {
+		s.onScreenChanged()
+	})
+	s.app.Event.OnApplicationEvent(events.Mac.ApplicationDidChangeScreenParameters, func(_ *application.ApplicationEvent) {
+		s.onScreenChanged()
+	})
 	// 显示后再次兜底定位（部分平台首次 SetPosition 可能被忽略）
 	w.RegisterHook(events.Common.WindowShow, func(_ *application.WindowEvent) {
 		s.mu.Lock()
@@ -632,7 +768,7 @@ func (s *FloatingBallService) ensureLocked() *application.WebviewWindow {
 
 		// Post-show verification: on some systems the window manager may adjust the window frame
 		// asynchronously after Show(). We verify and clamp once after a short delay.
-		time.AfterFunc(220*time.Millisecond, func() {
+		time.AfterFunc(220*time.Millisecond, safego.Func("floatingball.show_verify", func() {
 			s.mu.Lock()
 			defer s.mu.Unlock()
 			if s.win == nil || !s.visible {
@@ -647,7 +783,7 @@ func (s *FloatingBallService) ensureLocked() *application.WebviewWindow {
 			})
 			// If it somehow ended up off-primary, clamp it back.
 			_, _, _ = s.clampToPrimaryDipLocked("show_after")
-		})
+		}, s.resetAfterPanic))
 	})
 
 	s.win = w
@@ -714,9 +850,9 @@ func (s *FloatingBallService) onWindowDidMove() {
 		s.snapTimer.Stop()
 		s.snapTimer = nil
 	}
-	s.snapTimer = time.AfterFunc(snapDebounce, func() {
+	s.snapTimer = time.AfterFunc(snapDebounce, safego.Func("floatingball.snap", func() {
 		s.snapAfterMove()
-	})
+	}, s.resetAfterPanic))
 }
 
 // clampToPrimaryDipLocked clamps the window into the primary WorkArea (DIP).
@@ -819,6 +955,11 @@ func (s *FloatingBallService) snapAfterMoveAtLocked(relX, relY int) {
 	if s.win == nil || !s.visible {
 		return
 	}
+	// Pinned: never dock; any move (native drag, work area change) goes back to the corner.
+	if s.pinCorner != PinCornerNone {
+		s.applyPinLocked()
+		return
+	}
 	bounds := s.win.Bounds()
 	width := bounds.Width
 	height := bounds.Height
@@ -829,15 +970,16 @@ func (s *FloatingBallService) snapAfterMoveAtLocked(relX, relY int) {
 	}
 
 	y := clamp(relY, 0, work.Height-height)
+	gap := edgeSnapGapDip()
 
 	// Snap to left edge if close enough.
-	if relX <= edgeSnapGap {
+	if s.autoCollapse && relX <= gap {
 		s.dock = DockLeft
 		s.collapseToYLocked(y)
 		return
 	}
 	// Snap to right edge if close enough.
-	if relX+width >= work.Width-edgeSnapGap {
+	if s.autoCollapse && relX+width >= work.Width-gap {
 		s.dock = DockRight
 		s.collapseToYLocked(y)
 		return
@@ -891,9 +1033,9 @@ func (s *FloatingBallService) scheduleRepositionLocked() {
 		s.repositionTimer = nil
 	}
 	s.repositionTries = 0
-	s.repositionTimer = time.AfterFunc(postShowRepositionDelay, func() {
+	s.repositionTimer = time.AfterFunc(postShowRepositionDelay, safego.Func("floatingball.reposition", func() {
 		s.repositionTick()
-	})
+	}, s.resetAfterPanic))
 }
 
 func (s *FloatingBallService) repositionTick() {
@@ -918,15 +1060,21 @@ func (s *FloatingBallService) repositionTick() {
 	}
 
 	// retry
-	s.repositionTimer = time.AfterFunc(postShowRepositionDelay, func() {
+	s.repositionTimer = time.AfterFunc(postShowRepositionDelay, safego.Func("floatingball.reposition", func() {
 		s.repositionTick()
-	})
+	}, s.resetAfterPanic))
 }
 
 func (s *FloatingBallService) restoreOrDefaultLocked() {
 	if s.win == nil {
 		return
 	}
+	// 恢复的可能是贴边缩小状态，按需重新开启点击穿透
+	defer s.applyClickThroughLocked()
+	if s.pinCorner != PinCornerNone {
+		s.applyPinLocked()
+		return
+	}
 	// If we have a last known state, restore it; otherwise use default.
 	if s.hasLastState {
 		s.debugLog("restore:last_state", map[string]any{
@@ -935,6 +1083,11 @@ func (s *FloatingBallService) restoreOrDefaultLocked() {
 		// Restore dock and collapsed state.
 		s.dock = s.lastDock
 		s.collapsed = s.lastCollapsed
+		// Auto-collapse was turned off after the state was saved: come back full-size and undocked.
+		if !s.autoCollapse {
+			s.dock = DockNone
+			s.collapsed = false
+		}
 		w := ballSize
 		if s.collapsed {
 			w = collapsedWidth
@@ -980,7 +1133,7 @@ func (s *FloatingBallService) expandLocked() {
 }
 
 func (s *FloatingBallService) rehideLocked() {
-	if s.win == nil || s.dock == DockNone {
+	if s.win == nil || s.dock == DockNone || !s.autoCollapse {
 		return
 	}
 
@@ -996,7 +1149,7 @@ func (s *FloatingBallService) rehideLocked() {
 }
 
 func (s *FloatingBallService) scheduleIdleDockLocked() {
-	if s.win == nil || !s.visible {
+	if s.win == nil || !s.visible || !s.autoCollapse || s.pinCorner != PinCornerNone {
 		return
 	}
 	// 未 hover 时生效（无论是否已贴边），用于“停留一段时间后自动缩小”
@@ -1011,14 +1164,14 @@ func (s *FloatingBallService) scheduleIdleDockLocked() {
 		s.idleDockTimer.Stop()
 		s.idleDockTimer = nil
 	}
-	s.idleDockTimer = time.AfterFunc(idleDockDelay, func() {
+	s.idleDockTimer = time.AfterFunc(idleDockDelayDuration(), safego.Func("floatingball.idle_dock", func() {
 		s.mu.Lock()
 		defer s.mu.Unlock()
 
 		if s.win == nil || !s.visible {
 			return
 		}
-		if s.hovered || s.collapsed {
+		if s.hovered || s.collapsed || !s.autoCollapse || s.pinCorner != PinCornerNone {
 			return
 		}
 		// Some platforms may temporarily report IsVisible=false right after the first Show()
@@ -1041,17 +1194,18 @@ func (s *FloatingBallService) scheduleIdleDockLocked() {
 			return
 		}
 		// decide side by proximity
-		if relX <= edgeSnapGap {
+		gap := edgeSnapGapDip()
+		if relX <= gap {
 			s.dock = DockLeft
 			s.collapseToYLocked(y)
 			return
 		}
-		if relX+width >= work.Width-edgeSnapGap {
+		if relX+width >= work.Width-gap {
 			s.dock = DockRight
 			s.collapseToYLocked(y)
 			return
 		}
-	})
+	}, s.resetAfterPanic))
 }
 
 func (s *FloatingBallService) stopTimersLocked() {
@@ -1075,6 +1229,10 @@ func (s *FloatingBallService) stopTimersLocked() {
 		s.sizeEnforceTimer.Stop()
 		s.sizeEnforceTimer = nil
 	}
+	if s.clickThroughTimer != nil {
+		s.clickThroughTimer.Stop()
+		s.clickThroughTimer = nil
+	}
 }
 
 func (s *FloatingBallService) setPositionLocked(x, y int) {
@@ -1153,9 +1311,9 @@ func (s *FloatingBallService) requestSizeEnforceLocked(w, h int, why string) {
 	s.sizeEnforceH = h
 	s.sizeEnforceTries = 0
 	s.sizeEnforceWhy = why
-	s.sizeEnforceTimer = time.AfterFunc(80*time.Millisecond, func() {
+	s.sizeEnforceTimer = time.AfterFunc(80*time.Millisecond, safego.Func("floatingball.size_enforce", func() {
 		s.sizeEnforceTick()
-	})
+	}, s.resetAfterPanic))
 }
 
 func (s *FloatingBallService) sizeEnforceTick() {
@@ -1203,9 +1361,9 @@ func (s *FloatingBallService) sizeEnforceTick() {
 	}
 
 	if s.sizeEnforceTries < 5 {
-		s.sizeEnforceTimer = time.AfterFunc(120*time.Millisecond, func() {
+		s.sizeEnforceTimer = time.AfterFunc(120*time.Millisecond, safego.Func("floatingball.size_enforce", func() {
 			s.sizeEnforceTick()
-		})
+		}, s.resetAfterPanic))
 	}
 }
 
@@ -1236,6 +1394,7 @@ func (s *FloatingBallService) expandToYLocked(y int) {
 		"boundsW": b.Width, "boundsH": b.Height,
 	})
 	s.setRelativePositionLocked(x, y)
+	s.applyClickThroughLocked()
 }
 
 func (s *FloatingBallService) collapseToYLocked(y int) {
@@ -1265,6 +1424,7 @@ func (s *FloatingBallService) collapseToYLocked(y int) {
 		"boundsW": b.Width, "boundsH": b.Height,
 	})
 	s.setRelativePositionLocked(x, y)
+	s.applyClickThroughLocked()
 }
 
 func clamp(v, min, max int) int {
@@ -0,0 +1,38 @@
+package floatingball
+
+import (
+	"time"
+
+	"chatclaw/internal/services/settings"
+)
+
+// 可调的贴边/回缩参数（settings 中的整数值）。每次使用时从设置缓存读取，修改后立即生效，无需重建窗口；
+// 缺失或超出范围时使用对应的默认常量。取值范围需与 settings.SetValue 中的校验保持一致。
+const (
+	EdgeSnapGapSettingKey   = "floating_ball_edge_snap_gap"      // DIP，0~200，默认 edgeSnapGap
+	RehideDelaySettingKey   = "floating_ball_rehide_delay_ms"    // 毫秒，0~10000，默认 rehideDebounce
+	IdleDockDelaySettingKey = "floating_ball_idle_dock_delay_ms" // 毫秒，1000~600000，默认 idleDockDelay
+)
+
+// edgeSnapGapDip 距工作区左右边缘多近时贴边
+func edgeSnapGapDip() int {
+	return intSetting(EdgeSnapGapSettingKey, edgeSnapGap, 0, 200)
+}
+
+// rehideDelay 鼠标移出已展开的贴边悬浮球后多久回缩（0 为立即回缩）
+func rehideDelay() time.Duration {
+	return time.Duration(intSetting(RehideDelaySettingKey, int(rehideDebounce/time.Millisecond), 0, 10000)) * time.Millisecond
+}
+
+// idleDockDelayDuration 未悬停时多久自动贴边缩小
+func idleDockDelayDuration() time.Duration {
+	return time.Duration(intSetting(IdleDockDelaySettingKey, int(idleDockDelay/time.Millisecond), 1000, 600000)) * time.Millisecond
+}
+
+func intSetting(key string, def, min, max int) int {
+	v := settings.GetInt(key, def)
+	if v < min || v > max {
+		return def
+	}
+	return v
+}
@@ -0,0 +1,103 @@
+package i18n
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrorKey 后端错误目录中的一项（errs.New/Newf/Wrap 使用的 key）
+type ErrorKey struct {
+	Key    string   `json:"key"`
+	EnUS   string   `json:"en_us"`
+	ZhCN   string   `json:"zh_cn"`
+	Params []string `json:"params"` // Newf 可携带的模板参数名，如 ProviderID、LibraryName
+}
+
+// errorTemplateParam 匹配 go-i18n 模板中的参数，如 {{.ID}}、{{ .Count }}
+var errorTemplateParam = regexp.MustCompile(`\{\{-?\s*\.(\w+)\s*-?\}\}`)
+
+// ErrorKeys 返回全部 "error." 开头的 key 及其英文/中文默认文案和模板参数（按 key 排序）。
+// 直接由内嵌的翻译文件生成，与 errs 实际输出的文案同源，不会出现不一致。
+var ErrorKeys = sync.OnceValues(func() ([]ErrorKey, error) {
+	en, err := loadLocaleMessages(LocaleEnUS)
+	if err != nil {
+		return nil, err
+	}
+	zh, err := loadLocaleMessages(LocaleZhCN)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]bool)
+	for k := range en {
+		keys[k] = true
+	}
+	for k := range zh {
+		keys[k] = true
+	}
+
+	out := make([]ErrorKey, 0, len(keys))
+	for k := range keys {
+		if !strings.HasPrefix(k, "error.") {
+			continue
+		}
+		out = append(out, ErrorKey{
+			Key:    k,
+			EnUS:   en[k],
+			ZhCN:   zh[k],
+			Params: templateParams(en[k], zh[k]),
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Key < out[j].Key })
+	return out, nil
+})
+
+// ListErrorKeys 返回后端错误目录（暴露给前端，用于核对前端翻译和错误可携带的参数）
+func (s *Service) ListErrorKeys() ([]ErrorKey, error) {
+	return ErrorKeys()
+}
+
+// ResolveErrorKey 按指定语言解析错误 key 的文案（不影响当前语言）。
+// locale 为空或不支持时按英文解析；该语言缺少 key 时回退英文，都没有时返回由 key 生成的可读文案。
+func ResolveErrorKey(key, locale string) string {
+	if !supportedLocales[locale] {
+		locale = LocaleEnUS
+	}
+	return localize(newLocalizer(locale), key, nil)
+}
+
+// ResolveErrorKey 按指定语言解析错误 key 的文案（暴露给前端，便于排查用户反馈的错误 key）
+func (s *Service) ResolveErrorKey(key, locale string) string {
+	return ResolveErrorKey(key, locale)
+}
+
+func loadLocaleMessages(locale string) (map[string]string, error) {
+	data, err := localesFS.ReadFile("locales/" + locale + ".json")
+	if err != nil {
+		return nil, err
+	}
+	var messages map[string]string
+	if err := json.Unmarshal(data, &messages); err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+// templateParams 汇总各语言文案中出现的模板参数（去重、排序）
+func templateParams(texts ...string) []string {
+	seen := make(map[string]bool)
+	params := []string{}
+	for _, text := range texts {
+		for _, m := range errorTemplateParam.FindAllStringSubmatch(text, -1) {
+			if !seen[m[1]] {
+				seen[m[1]] = true
+				params = append(params, m[1])
+			}
+		}
+	}
+	sort.Strings(params)
+	return params
+}
@@ -0,0 +1,108 @@
+package i18n
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// TestServiceErrorKeysTranslated 确保 chat/providers 服务里 errs.New/Newf/Wrap/Wrapf 使用的 key 在每个语言文件中都有翻译
+func TestServiceErrorKeysTranslated(t *testing.T) {
+	keys := map[string]string{}
+	for _, dir := range []string{"../chat", "../providers"} {
+		for k, pos := range errsKeysInDir(t, dir) {
+			keys[k] = pos
+		}
+	}
+	if len(keys) == 0 {
+		t.Fatal("no errs keys found")
+	}
+
+	entries, err := localesFS.ReadDir("locales")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		locale := strings.TrimSuffix(e.Name(), ".json")
+		messages, err := loadLocaleMessages(locale)
+		if err != nil {
+			t.Fatalf("%s: %v", locale, err)
+		}
+		for k, pos := range keys {
+			if messages[k] == "" {
+				t.Errorf("%s: missing %q (used at %s)", locale, k, pos)
+			}
+		}
+	}
+}
+
+// errsKeysInDir 收集目录下非测试 Go 文件中 errs.* 调用的字面量 key
+func errsKeysInDir(t *testing.T, dir string) map[string]string {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := map[string]string{}
+	fset := token.NewFileSet()
+	for _, path := range files {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		src, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f, err := parser.ParseFile(fset, path, src, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ast.Inspect(f, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || len(call.Args) == 0 {
+				return true
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			if pkg, ok := sel.X.(*ast.Ident); !ok || pkg.Name != "errs" {
+				return true
+			}
+			switch sel.Sel.Name {
+			case "New", "Newf", "Wrap", "Wrapf":
+			default:
+				return true
+			}
+			lit, ok := call.Args[0].(*ast.BasicLit)
+			if !ok || lit.Kind != token.STRING {
+				return true
+			}
+			if key, err := strconv.Unquote(lit.Value); err == nil {
+				keys[key] = fset.Position(lit.Pos()).String()
+			}
+			return true
+		})
+	}
+	return keys
+}
+
+func TestResolveErrorKey(t *testing.T) {
+	if got := ResolveErrorKey("error.chat_image_too_large", LocaleEnUS); got != "image size exceeds limit" {
+		t.Errorf("en-US = %q", got)
+	}
+	if got := ResolveErrorKey("error.chat_image_too_large", LocaleZhCN); got != "图片大小超过限制" {
+		t.Errorf("zh-CN = %q", got)
+	}
+	if got := ResolveErrorKey("error.chat_image_too_large", "xx-XX"); got != "image size exceeds limit" {
+		t.Errorf("unsupported locale = %q", got)
+	}
+	if got := ResolveErrorKey("error.some_unknown_key", LocaleDeDE); got != "Some unknown key" {
+		t.Errorf("unknown key = %q", got)
+	}
+}
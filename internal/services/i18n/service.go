@@ -35,11 +35,34 @@ const (
 	LocaleZhTW = "zh-TW"
 )
 
+// supportedLocales 已内置翻译文件的语言
+var supportedLocales = map[string]bool{
+	LocaleZhCN: true,
+	LocaleEnUS: true,
+	LocaleArSA: true,
+	LocaleBnBD: true,
+	LocaleDeDE: true,
+	LocaleEsES: true,
+	LocaleFrFR: true,
+	LocaleHiIN: true,
+	LocaleItIT: true,
+	LocaleJaJP: true,
+	LocaleKoKR: true,
+	LocalePtBR: true,
+	LocaleSlSI: true,
+	LocaleTrTR: true,
+	LocaleViVN: true,
+	LocaleZhTW: true,
+}
+
 var (
 	bundle    *i18n.Bundle
 	localizer *i18n.Localizer
-	mu        sync.RWMutex
-	appRef    *application.App
+	// fallbackLocalizer 英文 localizer：当前语言缺少某个 key 时按 key 回退到英文，
+	// 而不是 bundle 的默认语言（中文）
+	fallbackLocalizer *i18n.Localizer
+	mu                sync.RWMutex
+	appRef            *application.App
 )
 
 // SetApp stores the application reference for cross-window event broadcasting.
@@ -73,7 +96,8 @@ func init() {
 	bundle.LoadMessageFileFS(localesFS, "locales/zh-TW.json")
 
 	// 默认使用英文（前端/后端都以英文作为不支持语言时的初始兜底）
-	localizer = i18n.NewLocalizer(bundle, LocaleEnUS)
+	localizer = newLocalizer(LocaleEnUS)
+	fallbackLocalizer = localizer
 }
 
 // Service 多语言服务（暴露给前端调用）
@@ -212,31 +236,13 @@ func SetLocale(locale string) {
 		locale = DetectLocale()
 	} else {
 		// 验证语言是否支持
-		supported := map[string]bool{
-			LocaleZhCN: true,
-			LocaleEnUS: true,
-			LocaleArSA: true,
-			LocaleBnBD: true,
-			LocaleDeDE: true,
-			LocaleEsES: true,
-			LocaleFrFR: true,
-			LocaleHiIN: true,
-			LocaleItIT: true,
-			LocaleJaJP: true,
-			LocaleKoKR: true,
-			LocalePtBR: true,
-			LocaleSlSI: true,
-			LocaleTrTR: true,
-			LocaleViVN: true,
-			LocaleZhTW: true,
-		}
-		if !supported[locale] {
+		if !supportedLocales[locale] {
 			// 非支持语言统一退回英文
 			locale = LocaleEnUS
 		}
 	}
 	currentLocale = locale
-	localizer = i18n.NewLocalizer(bundle, locale)
+	localizer = newLocalizer(locale)
 	a := appRef
 	mu.Unlock()
 
@@ -246,16 +252,17 @@ func SetLocale(locale string) {
 	}
 }
 
+// newLocalizer 创建指定语言的 localizer
+func newLocalizer(locale string) *i18n.Localizer {
+	return i18n.NewLocalizer(bundle, locale)
+}
+
 // T 获取翻译文本
 func T(key string) string {
 	mu.RLock()
 	defer mu.RUnlock()
 
-	msg, err := localizer.Localize(&i18n.LocalizeConfig{MessageID: key})
-	if err != nil {
-		return key
-	}
-	return msg
+	return localize(localizer, key, nil)
 }
 
 // Tf 获取翻译文本（带参数）
@@ -263,12 +270,33 @@ func Tf(key string, data map[string]any) string {
 	mu.RLock()
 	defer mu.RUnlock()
 
-	msg, err := localizer.Localize(&i18n.LocalizeConfig{
+	return localize(localizer, key, data)
+}
+
+// localize 翻译 key：当前语言没有该 key 时回退英文，都没有时返回由 key 生成的可读文案
+func localize(l *i18n.Localizer, key string, data map[string]any) string {
+	cfg := &i18n.LocalizeConfig{
 		MessageID:    key,
 		TemplateData: data,
-	})
-	if err != nil {
+	}
+	if msg, err := l.Localize(cfg); err == nil && msg != "" {
+		return msg
+	}
+	if l != fallbackLocalizer {
+		if msg, err := fallbackLocalizer.Localize(cfg); err == nil && msg != "" {
+			return msg
+		}
+	}
+	return humanizeKey(key)
+}
+
+// humanizeKey 把未翻译的 key 转成可读文案，如 "error.chat_image_too_large" -> "Chat image too large"
+func humanizeKey(key string) string {
+	text := strings.TrimPrefix(key, "error.")
+	text = strings.NewReplacer("_", " ", ".", " ", "-", " ").Replace(text)
+	text = strings.Join(strings.Fields(text), " ")
+	if text == "" {
 		return key
 	}
-	return msg
+	return strings.ToUpper(text[:1]) + text[1:]
 }
@@ -0,0 +1,822 @@
+package library
+
+import (
+	"archive/zip"
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"chatclaw/internal/eino/processor"
+	"chatclaw/internal/errs"
+	"chatclaw/internal/fts/tokenizer"
+	"chatclaw/internal/services/document"
+	"chatclaw/internal/sqlite"
+	"chatclaw/internal/taskmanager"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// 知识库归档（zip）结构：
+//
+//	manifest.json  知识库设置、导出时的嵌入模型、文档元数据
+//	nodes.jsonl    每行一个 document_nodes 节点（含 doc_vec 原始向量）
+//	files/...      文档原始文件
+//
+// 文件夹不导出，导入后文档均为未分组。
+const (
+	libraryArchiveVersion  = 1
+	libraryArchiveManifest = "manifest.json"
+	libraryArchiveNodes    = "nodes.jsonl"
+	libraryArchiveFilesDir = "files/"
+
+	libraryArchiveTimeout = 10 * time.Minute
+	// libraryArchiveVectorChunk 单条 IN 查询的节点数（低于 SQLite 变量上限）
+	libraryArchiveVectorChunk = 500
+)
+
+type archiveManifest struct {
+	Version    int               `json:"version"`
+	ExportedAt string            `json:"exported_at"`
+	Library    archiveLibrary    `json:"library"`
+	Embedding  archiveEmbedding  `json:"embedding"`
+	Documents  []archiveDocument `json:"documents"`
+}
+
+type archiveLibrary struct {
+	Name string `json:"name"`
+
+	SemanticSegmentationEnabled bool   `json:"semantic_segmentation_enabled"`
+	RaptorLLMProviderID         string `json:"raptor_llm_provider_id"`
+	RaptorLLMModelID            string `json:"raptor_llm_model_id"`
+
+	ChunkSize    int `json:"chunk_size"`
+	ChunkOverlap int `json:"chunk_overlap"`
+
+	BatchMaxDocuments int `json:"batch_max_documents"`
+	BatchMaxChunks    int `json:"batch_max_chunks"`
+
+	PreserveTables bool `json:"preserve_tables"`
+
+	Language string `json:"language,omitempty"` // 旧版本导出的归档没有该字段，按 auto 处理
+}
+
+// archiveEmbedding 导出时的全局嵌入模型；导入时与当前配置比对，不一致则向量不可用
+type archiveEmbedding struct {
+	ProviderID string `json:"provider_id"`
+	ModelID    string `json:"model_id"`
+	Dimension  int    `json:"dimension"`
+}
+
+type archiveDocument struct {
+	ID           int64  `json:"id"` // 导出库中的文档 ID，仅用于关联 nodes.jsonl
+	OriginalName string `json:"original_name"`
+	ThumbIcon    string `json:"thumb_icon"`
+	FileSize     int64  `json:"file_size"`
+	ContentHash  string `json:"content_hash"`
+
+	Extension  string `json:"extension"`
+	MimeType   string `json:"mime_type"`
+	SourceType string `json:"source_type"`
+	WebURL     string `json:"web_url"`
+	File       string `json:"file"` // 归档内路径；原始文件丢失或为网页时为空
+
+	ParsingStatus   int `json:"parsing_status"`
+	EmbeddingStatus int `json:"embedding_status"`
+	WordTotal       int `json:"word_total"`
+	SplitTotal      int `json:"split_total"`
+}
+
+type archiveNode struct {
+	ID            int64  `json:"id"`
+	DocumentID    int64  `json:"document_id"`
+	ParentID      *int64 `json:"parent_id,omitempty"`
+	Content       string `json:"content"`
+	ContentTokens string `json:"content_tokens"`
+	Level         int    `json:"level"`
+	ChunkOrder    int    `json:"chunk_order"`
+	Vector        []byte `json:"vector,omitempty"` // doc_vec 中的 float32 小端原始数据
+}
+
+func (e archiveEmbedding) matches(config *processor.EmbeddingConfig) bool {
+	return e.ProviderID == config.ProviderID && e.ModelID == config.ModelID && e.Dimension == config.Dimension
+}
+
+func (e archiveEmbedding) String() string {
+	return fmt.Sprintf("%s/%s (%d)", e.ProviderID, e.ModelID, e.Dimension)
+}
+
+// ExportLibrary 将知识库（原始文件、文档元数据、节点和向量）导出为单个归档文件
+func (s *LibraryService) ExportLibrary(libraryID int64, destPath string) error {
+	if libraryID <= 0 {
+		return errs.New("error.library_id_required")
+	}
+	destPath = strings.TrimSpace(destPath)
+	if destPath == "" {
+		return errs.New("error.library_archive_path_required")
+	}
+
+	db, err := s.db()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), libraryArchiveTimeout)
+	defer cancel()
+
+	var lib libraryModel
+	if err := db.NewSelect().Model(&lib).Where("id = ?", libraryID).Limit(1).Scan(ctx); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errs.Newf("error.library_not_found", map[string]any{"ID": libraryID})
+		}
+		return errs.Wrap("error.library_read_failed", err)
+	}
+	embeddingConfig, err := processor.GetEmbeddingConfig(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	// 先写临时文件，成功后再改名，避免失败时留下半个归档
+	tmpPath := destPath + ".tmp"
+	if err := s.writeLibraryArchive(ctx, db, &lib, embeddingConfig, tmpPath); err != nil {
+		os.Remove(tmpPath)
+		return errs.Wrap("error.library_export_failed", err)
+	}
+	if err := os.Rename(tmpPath, destPath); err != nil {
+		os.Remove(tmpPath)
+		return errs.Wrap("error.library_export_failed", err)
+	}
+	s.app.Logger.Info("library exported", "library_id", libraryID, "path", destPath)
+	return nil
+}
+
+func (s *LibraryService) writeLibraryArchive(
+	ctx context.Context,
+	db *bun.DB,
+	lib *libraryModel,
+	embeddingConfig *processor.EmbeddingConfig,
+	path string,
+) error {
+	type docRow struct {
+		ID              int64          `bun:"id"`
+		OriginalName    string         `bun:"original_name"`
+		ThumbIcon       sql.NullString `bun:"thumb_icon"`
+		FileSize        int64          `bun:"file_size"`
+		ContentHash     string         `bun:"content_hash"`
+		Extension       string         `bun:"extension"`
+		MimeType        string         `bun:"mime_type"`
+		SourceType      string         `bun:"source_type"`
+		LocalPath       sql.NullString `bun:"local_path"`
+		WebURL          sql.NullString `bun:"web_url"`
+		ParsingStatus   int            `bun:"parsing_status"`
+		EmbeddingStatus int            `bun:"embedding_status"`
+		WordTotal       int            `bun:"word_total"`
+		SplitTotal      int            `bun:"split_total"`
+	}
+	var docs []docRow
+	if err := db.NewSelect().
+		Table("documents").
+		Column("id", "original_name", "thumb_icon", "file_size", "content_hash", "extension", "mime_type",
+			"source_type", "local_path", "web_url", "parsing_status", "embedding_status", "word_total", "split_total").
+		Where("library_id = ?", lib.ID).
+		OrderExpr("id ASC").
+		Scan(ctx, &docs); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("query documents: %w", err)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	zw := zip.NewWriter(f)
+
+	manifest := archiveManifest{
+		Version:    libraryArchiveVersion,
+		ExportedAt: sqlite.NowUTC(),
+		Library: archiveLibrary{
+			Name:                        lib.Name,
+			SemanticSegmentationEnabled: lib.SemanticSegmentationEnabled,
+			RaptorLLMProviderID:         lib.RaptorLLMProviderID,
+			RaptorLLMModelID:            lib.RaptorLLMModelID,
+			ChunkSize:                   lib.ChunkSize,
+			ChunkOverlap:                lib.ChunkOverlap,
+			BatchMaxDocuments:           lib.BatchMaxDocuments,
+			BatchMaxChunks:              lib.BatchMaxChunks,
+			PreserveTables:              lib.PreserveTables,
+			Language:                    lib.Language,
+		},
+		Embedding: archiveEmbedding{
+			ProviderID: embeddingConfig.ProviderID,
+			ModelID:    embeddingConfig.ModelID,
+			Dimension:  embeddingConfig.Dimension,
+		},
+		Documents: make([]archiveDocument, 0, len(docs)),
+	}
+
+	// 1. 原始文件
+	for _, d := range docs {
+		ad := archiveDocument{
+			ID:              d.ID,
+			OriginalName:    d.OriginalName,
+			ThumbIcon:       d.ThumbIcon.String,
+			FileSize:        d.FileSize,
+			ContentHash:     d.ContentHash,
+			Extension:       d.Extension,
+			MimeType:        d.MimeType,
+			SourceType:      d.SourceType,
+			WebURL:          d.WebURL.String,
+			ParsingStatus:   d.ParsingStatus,
+			EmbeddingStatus: d.EmbeddingStatus,
+			WordTotal:       d.WordTotal,
+			SplitTotal:      d.SplitTotal,
+		}
+		if d.LocalPath.String != "" {
+			name := fmt.Sprintf("%s%d_%s", libraryArchiveFilesDir, d.ID, filepath.Base(d.LocalPath.String))
+			err := addFileToArchive(zw, name, d.LocalPath.String)
+			switch {
+			case err == nil:
+				ad.File = name
+			case os.IsNotExist(err):
+				s.app.Logger.Warn("export library: document file missing", "doc_id", d.ID, "path", d.LocalPath.String)
+			default:
+				return fmt.Errorf("add file %s: %w", d.LocalPath.String, err)
+			}
+		}
+		manifest.Documents = append(manifest.Documents, ad)
+	}
+
+	// 2. 节点与向量（逐文档流式写入）
+	w, err := zw.Create(libraryArchiveNodes)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	for _, d := range docs {
+		if err := writeArchiveNodes(ctx, db, enc, d.ID); err != nil {
+			return err
+		}
+	}
+
+	// 3. manifest
+	w, err = zw.Create(libraryArchiveManifest)
+	if err != nil {
+		return err
+	}
+	if err := json.NewEncoder(w).Encode(&manifest); err != nil {
+		return err
+	}
+
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+func addFileToArchive(zw *zip.Writer, name, srcPath string) error {
+	src, err := os.Open(srcPath)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, src)
+	return err
+}
+
+func writeArchiveNodes(ctx context.Context, db *bun.DB, enc *json.Encoder, docID int64) error {
+	type nodeRow struct {
+		ID            int64         `bun:"id"`
+		ParentID      sql.NullInt64 `bun:"parent_id"`
+		Content       string        `bun:"content"`
+		ContentTokens string        `bun:"content_tokens"`
+		Level         int           `bun:"level"`
+		ChunkOrder    int           `bun:"chunk_order"`
+	}
+	var nodes []nodeRow
+	if err := db.NewSelect().
+		Table("document_nodes").
+		Column("id", "parent_id", "content", "content_tokens", "level", "chunk_order").
+		Where("document_id = ?", docID).
+		OrderExpr("id ASC").
+		Scan(ctx, &nodes); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("query document_nodes: %w", err)
+	}
+
+	vectors := make(map[int64][]byte, len(nodes))
+	for start := 0; start < len(nodes); start += libraryArchiveVectorChunk {
+		end := min(start+libraryArchiveVectorChunk, len(nodes))
+		ids := make([]int64, 0, end-start)
+		for _, n := range nodes[start:end] {
+			ids = append(ids, n.ID)
+		}
+		type vecRow struct {
+			ID      int64  `bun:"id"`
+			Content []byte `bun:"content"`
+		}
+		var rows []vecRow
+		if err := db.NewRaw("SELECT id, content FROM doc_vec WHERE id IN (?)", bun.In(ids)).Scan(ctx, &rows); err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("query doc_vec: %w", err)
+		}
+		for _, r := range rows {
+			vectors[r.ID] = r.Content
+		}
+	}
+
+	for _, n := range nodes {
+		an := archiveNode{
+			ID:            n.ID,
+			DocumentID:    docID,
+			Content:       n.Content,
+			ContentTokens: n.ContentTokens,
+			Level:         n.Level,
+			ChunkOrder:    n.ChunkOrder,
+			Vector:        vectors[n.ID],
+		}
+		if n.ParentID.Valid {
+			parentID := n.ParentID.Int64
+			an.ParentID = &parentID
+		}
+		if err := enc.Encode(&an); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ImportLibrary 从归档文件恢复为一个新的知识库，原始文件复制到文档目录。
+// 归档的嵌入模型/维度与当前全局配置不一致时，reembed 为 false 则返回
+// error.library_import_embedding_mismatch（前端据此询问是否重新向量化）；
+// 为 true 则丢弃归档中的向量，导入后按当前模型重新向量化。
+func (s *LibraryService) ImportLibrary(srcPath string, reembed bool) (*Library, error) {
+	srcPath = strings.TrimSpace(srcPath)
+	if srcPath == "" {
+		return nil, errs.New("error.library_archive_path_required")
+	}
+
+	db, err := s.db()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), libraryArchiveTimeout)
+	defer cancel()
+
+	zr, entries, manifest, err := openLibraryArchive(srcPath)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	embeddingConfig, err := processor.GetEmbeddingConfig(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	keepVectors := manifest.Embedding.matches(embeddingConfig)
+	if !keepVectors && !reembed {
+		return nil, errs.Newf("error.library_import_embedding_mismatch", map[string]any{
+			"Archive": manifest.Embedding.String(),
+			"Current": archiveEmbedding{
+				ProviderID: embeddingConfig.ProviderID,
+				ModelID:    embeddingConfig.ModelID,
+				Dimension:  embeddingConfig.Dimension,
+			}.String(),
+		})
+	}
+
+	docsDir, releaseDir, err := document.AcquireDocumentsDir(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	defer releaseDir()
+
+	var (
+		lib        *libraryModel
+		libraryDir string
+		imported   []importedDocument
+	)
+	err = db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		var err error
+		lib, err = insertArchiveLibrary(ctx, tx, &manifest.Library)
+		if err != nil {
+			return err
+		}
+
+		libraryDir = filepath.Join(docsDir, fmt.Sprintf("%d", lib.ID))
+		if err := os.MkdirAll(libraryDir, 0o755); err != nil {
+			return err
+		}
+
+		docIDs := make(map[int64]*importedDocument, len(manifest.Documents))
+		imported = make([]importedDocument, 0, len(manifest.Documents))
+		for i := range manifest.Documents {
+			d, err := insertArchiveDocument(ctx, tx, entries, lib.ID, lib.Language, libraryDir, &manifest.Documents[i])
+			if err != nil {
+				return err
+			}
+			imported = append(imported, *d)
+		}
+		for i := range imported {
+			docIDs[imported[i].archiveID] = &imported[i]
+		}
+
+		// 已完成的文档按归档的分段参数与嵌入模型记录处理参数；需重新向量化的文档在向量化完成后更新嵌入模型
+		if _, err := tx.NewUpdate().
+			Table("documents").
+			Set("processed_chunk_size = ?", manifest.Library.ChunkSize).
+			Set("processed_chunk_overlap = ?", manifest.Library.ChunkOverlap).
+			Set("processed_embedding_provider_id = ?", manifest.Embedding.ProviderID).
+			Set("processed_embedding_model_id = ?", manifest.Embedding.ModelID).
+			Set("processed_embedding_dimension = ?", manifest.Embedding.Dimension).
+			Where("library_id = ?", lib.ID).
+			Where("parsing_status = ?", document.StatusCompleted).
+			Where("embedding_status = ?", document.StatusCompleted).
+			Exec(ctx); err != nil {
+			return err
+		}
+
+		return insertArchiveNodes(ctx, tx, entries, lib.ID, docIDs, keepVectors)
+	})
+	if err != nil {
+		if libraryDir != "" {
+			os.RemoveAll(libraryDir)
+		}
+		return nil, errs.Wrap("error.library_import_failed", err)
+	}
+
+	s.submitImportedDocuments(ctx, db, lib.ID, imported, keepVectors)
+
+	s.app.Logger.Info("library imported", "library_id", lib.ID, "path", srcPath,
+		"documents", len(imported), "reembed", !keepVectors)
+	dto := lib.toDTO()
+	return &dto, nil
+}
+
+// LibraryArchiveInfo 归档概要，供导入前展示并决定是否需要重新向量化
+type LibraryArchiveInfo struct {
+	Version       int    `json:"version"`
+	ExportedAt    string `json:"exported_at"`
+	Name          string `json:"name"`
+	Documents     int    `json:"documents"`
+	Embedding     string `json:"embedding"`      // 归档的嵌入模型，如 openai/text-embedding-3-small (1536)
+	NeedsReembed  bool   `json:"needs_reembed"`  // 与当前嵌入配置不一致，ImportLibrary 需传 reembed=true
+	CurrentConfig string `json:"current_config"` // 当前全局嵌入配置
+}
+
+// InspectLibraryArchive 读取归档的 manifest，不做任何写入
+func (s *LibraryService) InspectLibraryArchive(srcPath string) (*LibraryArchiveInfo, error) {
+	srcPath = strings.TrimSpace(srcPath)
+	if srcPath == "" {
+		return nil, errs.New("error.library_archive_path_required")
+	}
+
+	db, err := s.db()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	zr, _, manifest, err := openLibraryArchive(srcPath)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	embeddingConfig, err := processor.GetEmbeddingConfig(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	return &LibraryArchiveInfo{
+		Version:      manifest.Version,
+		ExportedAt:   manifest.ExportedAt,
+		Name:         manifest.Library.Name,
+		Documents:    len(manifest.Documents),
+		Embedding:    manifest.Embedding.String(),
+		NeedsReembed: !manifest.Embedding.matches(embeddingConfig),
+		CurrentConfig: archiveEmbedding{
+			ProviderID: embeddingConfig.ProviderID,
+			ModelID:    embeddingConfig.ModelID,
+			Dimension:  embeddingConfig.Dimension,
+		}.String(),
+	}, nil
+}
+
+// openLibraryArchive 打开归档并校验 manifest 版本；调用方负责关闭返回的 reader
+func openLibraryArchive(srcPath string) (*zip.ReadCloser, map[string]*zip.File, *archiveManifest, error) {
+	zr, err := zip.OpenReader(srcPath)
+	if err != nil {
+		return nil, nil, nil, errs.Wrap("error.library_archive_invalid", err)
+	}
+
+	entries := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		entries[f.Name] = f
+	}
+	manifest, err := readArchiveManifest(entries)
+	if err != nil {
+		zr.Close()
+		return nil, nil, nil, errs.Wrap("error.library_archive_invalid", err)
+	}
+	if manifest.Version > libraryArchiveVersion {
+		zr.Close()
+		return nil, nil, nil, errs.Wrap("error.library_archive_invalid", fmt.Errorf("unsupported archive version %d", manifest.Version))
+	}
+	return zr, entries, manifest, nil
+}
+
+// importedDocument 导入后的文档，用于导入完成后补交处理任务
+type importedDocument struct {
+	archiveID int64
+	id        int64
+	runID     string
+	hasNodes  bool
+	completed bool
+	canParse  bool
+}
+
+func readArchiveManifest(entries map[string]*zip.File) (*archiveManifest, error) {
+	f, ok := entries[libraryArchiveManifest]
+	if !ok {
+		return nil, errors.New("manifest.json not found")
+	}
+	r, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	var m archiveManifest
+	if err := json.NewDecoder(r).Decode(&m); err != nil {
+		return nil, fmt.Errorf("decode manifest: %w", err)
+	}
+	return &m, nil
+}
+
+// insertArchiveLibrary 新建知识库；与已有知识库重名时追加序号
+func insertArchiveLibrary(ctx context.Context, tx bun.Tx, in *archiveLibrary) (*libraryModel, error) {
+	base := strings.TrimSpace(in.Name)
+	if base == "" {
+		base = "Imported"
+	}
+	name := base
+	for i := 2; ; i++ {
+		count, err := tx.NewSelect().Table("library").Where("name = ?", name).Count(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if count == 0 {
+			break
+		}
+		suffix := fmt.Sprintf(" (%d)", i)
+		runes := []rune(base)
+		if len(runes)+len([]rune(suffix)) > 30 {
+			runes = runes[:30-len([]rune(suffix))]
+		}
+		name = string(runes) + suffix
+	}
+
+	var maxSort sql.NullInt64
+	if err := tx.NewSelect().Table("library").ColumnExpr("MAX(sort_order)").Scan(ctx, &maxSort); err != nil {
+		return nil, err
+	}
+
+	language, ok := tokenizer.NormalizeLibraryLanguage(in.Language)
+	if !ok {
+		language = tokenizer.LanguageAuto
+	}
+
+	m := &libraryModel{
+		Name:                        name,
+		SemanticSegmentationEnabled: in.SemanticSegmentationEnabled,
+		RaptorLLMProviderID:         in.RaptorLLMProviderID,
+		RaptorLLMModelID:            in.RaptorLLMModelID,
+		ChunkSize:                   in.ChunkSize,
+		ChunkOverlap:                in.ChunkOverlap,
+		BatchMaxDocuments:           in.BatchMaxDocuments,
+		BatchMaxChunks:              in.BatchMaxChunks,
+		PreserveTables:              in.PreserveTables,
+		Language:                    language,
+		SortOrder:                   int(maxSort.Int64) + 1,
+	}
+	if _, err := tx.NewInsert().Model(m).Exec(ctx); err != nil {
+		return nil, fmt.Errorf("insert library: %w", err)
+	}
+	return m, nil
+}
+
+// insertArchiveDocument 解压原始文件到知识库目录并写入 documents（触发器同步文件名 FTS）
+func insertArchiveDocument(
+	ctx context.Context,
+	tx bun.Tx,
+	entries map[string]*zip.File,
+	libraryID int64,
+	language string,
+	libraryDir string,
+	d *archiveDocument,
+) (*importedDocument, error) {
+	localPath := ""
+	if d.File != "" {
+		f, ok := entries[d.File]
+		if !ok {
+			return nil, fmt.Errorf("archive file %s not found", d.File)
+		}
+		prefix := d.ContentHash
+		if len(prefix) > 8 {
+			prefix = prefix[:8]
+		}
+		localPath = filepath.Join(libraryDir, fmt.Sprintf("%s_%s", prefix, filepath.Base(d.OriginalName)))
+		if err := extractArchiveFile(f, localPath); err != nil {
+			return nil, err
+		}
+	}
+
+	runID := uuid.New().String()
+	res, err := tx.NewRaw(`INSERT INTO documents (
+			library_id, original_name, name_tokens, thumb_icon, file_size, content_hash,
+			extension, mime_type, source_type, local_path, web_url, processing_run_id,
+			parsing_status, parsing_progress, embedding_status, embedding_progress,
+			word_total, split_total, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		libraryID, d.OriginalName, tokenizer.TokenizeNameFor(d.OriginalName, language), d.ThumbIcon, d.FileSize, d.ContentHash,
+		d.Extension, d.MimeType, d.SourceType, localPath, d.WebURL, runID,
+		d.ParsingStatus, progressFor(d.ParsingStatus), d.EmbeddingStatus, progressFor(d.EmbeddingStatus),
+		d.WordTotal, d.SplitTotal, sqlite.NowUTC(), sqlite.NowUTC(),
+	).Exec(ctx)
+	if err != nil {
+		if localPath != "" {
+			os.Remove(localPath)
+		}
+		return nil, fmt.Errorf("insert document %s: %w", d.OriginalName, err)
+	}
+	docID, err := res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	return &importedDocument{
+		archiveID: d.ID,
+		id:        docID,
+		runID:     runID,
+		completed: d.ParsingStatus == document.StatusCompleted && d.EmbeddingStatus == document.StatusCompleted,
+		canParse:  localPath != "",
+	}, nil
+}
+
+func extractArchiveFile(f *zip.File, destPath string) error {
+	r, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	dst, err := os.Create(destPath)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, r); err != nil {
+		dst.Close()
+		os.Remove(destPath)
+		return err
+	}
+	return dst.Close()
+}
+
+func progressFor(status int) int {
+	if status == document.StatusCompleted {
+		return 100
+	}
+	return 0
+}
+
+// insertArchiveNodes 逐行读取 nodes.jsonl 写入 document_nodes（触发器同步 doc_fts），
+// keepVectors 时同时写入 doc_vec；最后按新 ID 重建 parent_id。
+func insertArchiveNodes(
+	ctx context.Context,
+	tx bun.Tx,
+	entries map[string]*zip.File,
+	libraryID int64,
+	docs map[int64]*importedDocument,
+	keepVectors bool,
+) error {
+	f, ok := entries[libraryArchiveNodes]
+	if !ok {
+		return nil
+	}
+	r, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	type pendingParent struct {
+		nodeID      int64
+		oldParentID int64
+	}
+	idMap := make(map[int64]int64)
+	var parents []pendingParent
+
+	dec := json.NewDecoder(bufio.NewReader(r))
+	for {
+		var n archiveNode
+		if err := dec.Decode(&n); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return fmt.Errorf("decode node: %w", err)
+		}
+		doc, ok := docs[n.DocumentID]
+		if !ok {
+			continue
+		}
+
+		res, err := tx.NewRaw(
+			"INSERT INTO document_nodes (library_id, document_id, content, content_tokens, level, chunk_order) VALUES (?, ?, ?, ?, ?, ?)",
+			libraryID, doc.id, n.Content, n.ContentTokens, n.Level, n.ChunkOrder,
+		).Exec(ctx)
+		if err != nil {
+			return fmt.Errorf("insert node: %w", err)
+		}
+		nodeID, err := res.LastInsertId()
+		if err != nil {
+			return err
+		}
+		idMap[n.ID] = nodeID
+		doc.hasNodes = true
+		if n.ParentID != nil {
+			parents = append(parents, pendingParent{nodeID: nodeID, oldParentID: *n.ParentID})
+		}
+
+		if keepVectors && len(n.Vector) > 0 {
+			if _, err := tx.NewRaw("INSERT INTO doc_vec (id, content) VALUES (?, ?)", nodeID, n.Vector).Exec(ctx); err != nil {
+				return fmt.Errorf("insert vector: %w", err)
+			}
+		}
+	}
+
+	for _, p := range parents {
+		parentID, ok := idMap[p.oldParentID]
+		if !ok {
+			continue
+		}
+		if _, err := tx.NewRaw("UPDATE document_nodes SET parent_id = ? WHERE id = ?", parentID, p.nodeID).Exec(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// submitImportedDocuments 导入提交后补交任务：
+// 向量不可用的文档只重新向量化；归档中未处理完成的文档完整重新处理。
+func (s *LibraryService) submitImportedDocuments(ctx context.Context, db *bun.DB, libraryID int64, docs []importedDocument, keepVectors bool) {
+	tm := taskmanager.Get()
+	if tm == nil {
+		return
+	}
+	for _, d := range docs {
+		var jobType string
+		switch {
+		case d.completed && d.hasNodes && keepVectors:
+			continue
+		case d.completed && d.hasNodes:
+			jobType = document.JobTypeReembed
+		case d.canParse:
+			jobType = document.JobTypeProcess
+		default:
+			continue
+		}
+
+		q := db.NewUpdate().
+			Table("documents").
+			Set("embedding_status = ?", document.StatusPending).
+			Set("embedding_progress = ?", 0).
+			Set("embedding_error = ?", "").
+			Where("id = ?", d.id)
+		if jobType == document.JobTypeProcess {
+			q = q.Set("parsing_status = ?", document.StatusPending).
+				Set("parsing_progress = ?", 0).
+				Set("parsing_error = ?", "")
+		}
+		if _, err := q.Exec(ctx); err != nil {
+			s.app.Logger.Error("reset imported document failed", "docID", d.id, "error", err)
+			continue
+		}
+
+		jobData, _ := json.Marshal(document.ProcessJobData{
+			DocID:     d.id,
+			LibraryID: libraryID,
+			RunID:     d.runID,
+		})
+		tm.Submit(taskmanager.QueueDocument, jobType, fmt.Sprintf("doc:%d", d.id), d.runID, jobData)
+	}
+}
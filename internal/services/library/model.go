@@ -30,6 +30,12 @@ type Library struct {
 	// BatchMaxChunks: max segments per embedding API call during learning (1~20).
 	BatchMaxChunks int `json:"batch_max_chunks"`
 
+	// PreserveTables: PDF/DOCX tables become separate markdown-table nodes instead of flattened text.
+	PreserveTables bool `json:"preserve_tables"`
+
+	// Language: 全文检索分词语言提示（auto 跟随全局 fts_language；zh / en / multi）
+	Language string `json:"language"`
+
 	SortOrder int `json:"sort_order"`
 }
 
@@ -47,6 +53,10 @@ type CreateLibraryInput struct {
 
 	BatchMaxDocuments *int `json:"batch_max_documents"`
 	BatchMaxChunks    *int `json:"batch_max_chunks"`
+
+	PreserveTables *bool `json:"preserve_tables"`
+
+	Language string `json:"language"` // 空表示 auto
 }
 
 // UpdateLibraryInput 更新知识库的输入参数
@@ -62,6 +72,11 @@ type UpdateLibraryInput struct {
 
 	BatchMaxDocuments *int `json:"batch_max_documents"`
 	BatchMaxChunks    *int `json:"batch_max_chunks"`
+
+	PreserveTables *bool `json:"preserve_tables"` // nil = unchanged
+
+	// Language nil = unchanged；修改后已有文档会按新语言重新分词
+	Language *string `json:"language"`
 }
 
 // libraryModel 数据库模型
@@ -84,6 +99,10 @@ type libraryModel struct {
 	BatchMaxDocuments int `bun:"batch_max_documents,notnull"`
 	BatchMaxChunks    int `bun:"batch_max_chunks,notnull"`
 
+	PreserveTables bool `bun:"preserve_tables,notnull"`
+
+	Language string `bun:"language,notnull"`
+
 	SortOrder int `bun:"sort_order,notnull"`
 }
 
@@ -123,6 +142,9 @@ func (m *libraryModel) toDTO() Library {
 		BatchMaxDocuments: m.BatchMaxDocuments,
 		BatchMaxChunks:    m.BatchMaxChunks,
 
+		PreserveTables: m.PreserveTables,
+		Language:       m.Language,
+
 		SortOrder: m.SortOrder,
 	}
 }
@@ -0,0 +1,273 @@
+package library
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	einoembed "chatclaw/internal/eino/embedding"
+	"chatclaw/internal/eino/processor"
+	"chatclaw/internal/errs"
+	"chatclaw/internal/services/retrieval"
+
+	"github.com/uptrace/bun"
+)
+
+const (
+	retrievalTestDefaultTopK = 10
+	retrievalTestMaxTopK     = 50
+)
+
+// RetrievalTestItem 检索测试的单条结果
+type RetrievalTestItem struct {
+	Rank         int     `json:"rank"`
+	NodeID       int64   `json:"node_id"`
+	DocumentID   int64   `json:"document_id"`
+	DocumentName string  `json:"document_name"`
+	Content      string  `json:"content"`
+	Level        int     `json:"level"`
+	Score        float64 `json:"score"`
+}
+
+// RetrievalTestResult 检索测试结果
+type RetrievalTestResult struct {
+	Query      string              `json:"query"`
+	TopK       int                 `json:"top_k"`
+	Threshold  float64             `json:"threshold"`
+	DurationMs int64               `json:"duration_ms"`
+	Items      []RetrievalTestItem `json:"items"`
+}
+
+// newRetrievalService 使用全局 embedding 配置创建检索服务（与智能体知识库检索工具相同）
+func (s *LibraryService) newRetrievalService(ctx context.Context) (*retrieval.Service, error) {
+	db, err := s.db()
+	if err != nil {
+		return nil, err
+	}
+
+	embeddingConfig, err := processor.GetEmbeddingConfig(ctx, db)
+	if err != nil {
+		return nil, errs.New("error.library_embedding_global_not_set")
+	}
+	embedder, err := einoembed.NewEmbedder(ctx, &einoembed.ProviderConfig{
+		ProviderID:   embeddingConfig.ProviderID,
+		ProviderType: embeddingConfig.ProviderType,
+		APIKey:       embeddingConfig.APIKey,
+		APIEndpoint:  embeddingConfig.APIEndpoint,
+		ModelID:      embeddingConfig.ModelID,
+		Dimension:    embeddingConfig.Dimension,
+		ExtraConfig:  embeddingConfig.ExtraConfig,
+	})
+	if err != nil {
+		return nil, errs.Wrap("error.library_retrieval_failed", err)
+	}
+	return retrieval.NewService(db, embedder), nil
+}
+
+// validateRetrievalInput 校验检索测试参数，返回去除空白的 query 和修正后的 topK
+func validateRetrievalInput(libraryIDs []int64, query string, topK int, threshold float64) (string, int, error) {
+	if len(libraryIDs) == 0 {
+		return "", 0, errs.New("error.library_id_required")
+	}
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return "", 0, errs.New("error.library_retrieval_query_required")
+	}
+	if threshold < 0 || threshold > 1 {
+		return "", 0, errs.New("error.library_match_threshold_invalid")
+	}
+	if topK <= 0 {
+		topK = retrievalTestDefaultTopK
+	}
+	return query, min(topK, retrievalTestMaxTopK), nil
+}
+
+// TestRetrieval 检索测试：使用与智能体知识库检索工具相同的 embedding + 混合检索路径，
+// 返回排序后的片段及分数和来源文档，不调用 LLM。用于调试回答质量并调整 TopK / 匹配阈值。
+func (s *LibraryService) TestRetrieval(libraryIDs []int64, query string, topK int, threshold float64) (*RetrievalTestResult, error) {
+	query, topK, err := validateRetrievalInput(libraryIDs, query, topK, threshold)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	svc, err := s.newRetrievalService(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	results, err := svc.Search(ctx, retrieval.SearchInput{
+		LibraryIDs: libraryIDs,
+		Query:      query,
+		TopK:       topK,
+		MinScore:   threshold,
+	})
+	if err != nil {
+		return nil, errs.Wrap("error.library_retrieval_failed", err)
+	}
+
+	items := make([]RetrievalTestItem, 0, len(results))
+	for i, r := range results {
+		items = append(items, RetrievalTestItem{
+			Rank:         i + 1,
+			NodeID:       r.NodeID,
+			DocumentID:   r.DocumentID,
+			DocumentName: r.DocumentName,
+			Content:      r.Content,
+			Level:        r.Level,
+			Score:        r.Score,
+		})
+	}
+
+	return &RetrievalTestResult{
+		Query:      query,
+		TopK:       topK,
+		Threshold:  threshold,
+		DurationMs: time.Since(start).Milliseconds(),
+		Items:      items,
+	}, nil
+}
+
+// SemanticSearchItem 跨知识库语义搜索的单条结果
+type SemanticSearchItem struct {
+	Rank         int     `json:"rank"`
+	NodeID       int64   `json:"node_id"`
+	DocumentID   int64   `json:"document_id"`
+	DocumentName string  `json:"document_name"`
+	LibraryID    int64   `json:"library_id"`
+	LibraryName  string  `json:"library_name"`
+	Content      string  `json:"content"`
+	Score        float64 `json:"score"`
+}
+
+// SemanticSearch 跨知识库语义搜索：与智能体知识库检索工具相同的全局 embedding + 混合检索，
+// 直接返回排序后的片段及来源文档与知识库，无需发起对话。libraryIDs 为空时搜索全部知识库。
+func (s *LibraryService) SemanticSearch(query string, libraryIDs []int64, topK int) ([]SemanticSearchItem, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	db, err := s.db()
+	if err != nil {
+		return nil, err
+	}
+	if len(libraryIDs) == 0 {
+		if err := db.NewSelect().
+			Model((*libraryModel)(nil)).
+			Column("id").
+			Scan(ctx, &libraryIDs); err != nil {
+			return nil, errs.Wrap("error.library_list_failed", err)
+		}
+		if len(libraryIDs) == 0 {
+			return []SemanticSearchItem{}, nil
+		}
+	}
+	query, topK, err = validateRetrievalInput(libraryIDs, query, topK, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	svc, err := s.newRetrievalService(ctx)
+	if err != nil {
+		return nil, err
+	}
+	results, err := svc.Search(ctx, retrieval.SearchInput{
+		LibraryIDs: libraryIDs,
+		Query:      query,
+		TopK:       topK,
+	})
+	if err != nil {
+		return nil, errs.Wrap("error.library_retrieval_failed", err)
+	}
+	if len(results) == 0 {
+		return []SemanticSearchItem{}, nil
+	}
+
+	// 回表补充文档所属知识库
+	docIDs := make([]int64, 0, len(results))
+	for _, r := range results {
+		docIDs = append(docIDs, r.DocumentID)
+	}
+	type docLibrary struct {
+		DocumentID  int64  `bun:"document_id"`
+		LibraryID   int64  `bun:"library_id"`
+		LibraryName string `bun:"library_name"`
+	}
+	var rows []docLibrary
+	if err := db.NewSelect().
+		TableExpr("documents AS d").
+		ColumnExpr("d.id AS document_id, d.library_id, l.name AS library_name").
+		Join("JOIN library AS l ON l.id = d.library_id").
+		Where("d.id IN (?)", bun.In(docIDs)).
+		Scan(ctx, &rows); err != nil {
+		return nil, errs.Wrap("error.library_retrieval_failed", err)
+	}
+	byDoc := make(map[int64]docLibrary, len(rows))
+	for _, r := range rows {
+		byDoc[r.DocumentID] = r
+	}
+
+	items := make([]SemanticSearchItem, 0, len(results))
+	for i, r := range results {
+		lib := byDoc[r.DocumentID]
+		items = append(items, SemanticSearchItem{
+			Rank:         i + 1,
+			NodeID:       r.NodeID,
+			DocumentID:   r.DocumentID,
+			DocumentName: r.DocumentName,
+			LibraryID:    lib.LibraryID,
+			LibraryName:  lib.LibraryName,
+			Content:      r.Content,
+			Score:        r.Score,
+		})
+	}
+	return items, nil
+}
+
+// RetrievalDebugResult 节点级检索调试结果
+type RetrievalDebugResult struct {
+	Query      string  `json:"query"`
+	TopK       int     `json:"top_k"`
+	Threshold  float64 `json:"threshold"`
+	DurationMs int64   `json:"duration_ms"`
+	retrieval.DebugResult
+}
+
+// DebugRetrieve 节点级检索调试：与 TestRetrieval 走同一检索路径（不走缓存、不调用 LLM），
+// 返回 RRF 融合前后的全部候选节点，包括向量距离 / BM25 分数、各自排名、最终排序，
+// 以及是否落在 TopK 内、是否达到匹配阈值，用于在关联智能体前调整 topK 和 matchThreshold。
+func (s *LibraryService) DebugRetrieve(libraryIDs []int64, query string, topK int, threshold float64) (*RetrievalDebugResult, error) {
+	query, topK, err := validateRetrievalInput(libraryIDs, query, topK, threshold)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	svc, err := s.newRetrievalService(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	result, err := svc.Debug(ctx, retrieval.SearchInput{
+		LibraryIDs: libraryIDs,
+		Query:      query,
+		TopK:       topK,
+		MinScore:   threshold,
+	})
+	if err != nil {
+		return nil, errs.Wrap("error.library_retrieval_failed", err)
+	}
+
+	return &RetrievalDebugResult{
+		Query:       query,
+		TopK:        topK,
+		Threshold:   threshold,
+		DurationMs:  time.Since(start).Milliseconds(),
+		DebugResult: *result,
+	}, nil
+}
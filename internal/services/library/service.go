@@ -11,6 +11,9 @@ import (
 
 	"chatclaw/internal/eino/processor"
 	"chatclaw/internal/errs"
+	"chatclaw/internal/fts/tokenizer"
+	"chatclaw/internal/services/document"
+	"chatclaw/internal/services/retrieval"
 	"chatclaw/internal/sqlite"
 	"chatclaw/internal/taskmanager"
 
@@ -137,6 +140,11 @@ func (s *LibraryService) CreateLibrary(input CreateLibraryInput) (*Library, erro
 		batchMaxChunks = *input.BatchMaxChunks
 	}
 
+	language, ok := tokenizer.NormalizeLibraryLanguage(input.Language)
+	if !ok {
+		return nil, errs.Newf("error.library_language_invalid", map[string]any{"Language": input.Language})
+	}
+
 	// embedding 配置为全局 settings（不落库到 library 表），创建前需确保配置真实可用，
 	// 避免默认 openai/text-embedding-* 在未填写 API Key 时被误判为“已配置”。
 	if _, err := processor.GetEmbeddingConfig(ctx, db); err != nil {
@@ -169,6 +177,10 @@ func (s *LibraryService) CreateLibrary(input CreateLibraryInput) (*Library, erro
 		BatchMaxDocuments: batchMaxDocuments,
 		BatchMaxChunks:    batchMaxChunks,
 
+		PreserveTables: input.PreserveTables != nil && *input.PreserveTables,
+
+		Language: language,
+
 		SortOrder: sortOrder,
 	}
 
@@ -226,6 +238,33 @@ func (s *LibraryService) UpdateLibrary(id int64, input UpdateLibraryInput) (*Lib
 	// 语义分段开关总是更新（bool 类型，前端总是传递）
 	q = q.Set("semantic_segmentation_enabled = ?", input.SemanticSegmentationEnabled)
 
+	if input.PreserveTables != nil {
+		q = q.Set("preserve_tables = ?", *input.PreserveTables)
+	}
+
+	// 分词语言变化时，已有文档需按新语言重新分词，否则检索词与索引对不上
+	languageChanged := false
+	if input.Language != nil {
+		language, ok := tokenizer.NormalizeLibraryLanguage(*input.Language)
+		if !ok {
+			return nil, errs.Newf("error.library_language_invalid", map[string]any{"Language": *input.Language})
+		}
+		var cur string
+		if err := db.NewSelect().
+			Table("library").
+			Column("language").
+			Where("id = ?", id).
+			Limit(1).
+			Scan(ctx, &cur); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, errs.Newf("error.library_not_found", map[string]any{"ID": id})
+			}
+			return nil, errs.Wrap("error.library_read_failed", err)
+		}
+		languageChanged = tokenizer.ResolveLanguage(cur) != tokenizer.ResolveLanguage(language)
+		q = q.Set("language = ?", language)
+	}
+
 	if input.RaptorLLMProviderID != nil || input.RaptorLLMModelID != nil {
 		// 允许"只更新其中一个字段"的局部更新：先读当前值再合并更新
 		type row struct {
@@ -296,6 +335,18 @@ func (s *LibraryService) UpdateLibrary(id int64, input UpdateLibraryInput) (*Lib
 		return nil, errs.Newf("error.library_not_found", map[string]any{"ID": id})
 	}
 
+	if languageChanged {
+		go func() {
+			result, err := document.RetokenizeLibrary(context.Background(), db, id)
+			if err != nil {
+				s.app.Logger.Error("[library] retokenize after language change failed", "library_id", id, "error", err)
+				return
+			}
+			retrieval.InvalidateLibrary(id)
+			s.app.Logger.Info("[library] retokenized after language change", "library_id", id, "documents", result.Documents, "nodes", result.Nodes)
+		}()
+	}
+
 	var m libraryModel
 	if err := db.NewSelect().Model(&m).Where("id = ?", id).Limit(1).Scan(ctx); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -375,6 +426,7 @@ func (s *LibraryService) DeleteLibrary(id int64) error {
 	if affected == 0 {
 		return errs.Newf("error.library_not_found", map[string]any{"ID": id})
 	}
+	retrieval.InvalidateLibrary(id)
 
 	// 5. 删除物理文件（在数据库删除成功后执行，失败不影响整体结果）
 	for _, doc := range docs {
@@ -0,0 +1,24 @@
+package maintenance
+
+import (
+	"context"
+
+	"chatclaw/internal/eino/processor"
+	"chatclaw/internal/errs"
+	"chatclaw/internal/sqlite"
+)
+
+// ClearEmbeddingCache 清空嵌入缓存（embedding_cache），返回删除的条数。
+// 之后重新处理文档会重新调用嵌入模型。
+func (s *MaintenanceService) ClearEmbeddingCache() (int64, error) {
+	db := sqlite.DB()
+	if db == nil {
+		return 0, errs.New("error.sqlite_not_initialized")
+	}
+	deleted, err := processor.ClearEmbeddingCache(context.Background(), db)
+	if err != nil {
+		return 0, errs.Wrap("error.maintenance_clear_embedding_cache_failed", err)
+	}
+	s.app.Logger.Info("embedding cache cleared", "deleted", deleted)
+	return deleted, nil
+}
@@ -0,0 +1,185 @@
+package maintenance
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	einoagent "chatclaw/internal/eino/agent"
+	einoembed "chatclaw/internal/eino/embedding"
+	"chatclaw/internal/eino/processor"
+	"chatclaw/internal/errs"
+	"chatclaw/internal/services/settings"
+	"chatclaw/internal/sqlite"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// 自检项名称
+const (
+	CheckSQLite       = "sqlite"
+	CheckSchema       = "schema"
+	CheckSearchTables = "search_tables"
+	CheckEmbedding    = "embedding"
+	CheckChatModel    = "chat_model"
+
+	// selfTestModelTimeout 单个模型调用的超时时间
+	selfTestModelTimeout = 30 * time.Second
+)
+
+// 自检项状态
+const (
+	CheckStatusPass    = "pass"
+	CheckStatusFail    = "fail"
+	CheckStatusSkipped = "skipped" // 未配置（如未设置默认对话模型），不计为失败
+)
+
+// SelfTestCheck 单个自检项结果
+type SelfTestCheck struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"` // pass / fail / skipped
+	Detail     string `json:"detail,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// SelfTestReport 自检报告；Passed 为 true 表示没有失败项
+type SelfTestReport struct {
+	Passed     bool                 `json:"passed"`
+	Checks     []SelfTestCheck      `json:"checks"`
+	Schema     *sqlite.SchemaStatus `json:"schema,omitempty"`
+	DurationMs int64                `json:"duration_ms"`
+}
+
+// RunSelfTest 检查整条链路：数据库连接与迁移版本、FTS / 向量表、
+// 嵌入模型（一次极小的 embed 调用）以及默认对话模型（一次极小的生成）。
+// 各项互不影响，某项失败时仍会继续执行其余检查。
+func (s *MaintenanceService) RunSelfTest() (*SelfTestReport, error) {
+	db := sqlite.DB()
+	if db == nil {
+		return nil, errs.New("error.sqlite_not_initialized")
+	}
+
+	start := time.Now()
+	report := &SelfTestReport{Checks: make([]SelfTestCheck, 0, 5)}
+	run := func(name string, fn func(ctx context.Context) (status, detail string)) {
+		ctx, cancel := context.WithTimeout(context.Background(), selfTestModelTimeout)
+		defer cancel()
+		t := time.Now()
+		status, detail := fn(ctx)
+		report.Checks = append(report.Checks, SelfTestCheck{
+			Name:       name,
+			Status:     status,
+			Detail:     detail,
+			DurationMs: time.Since(t).Milliseconds(),
+		})
+	}
+
+	run(CheckSQLite, func(ctx context.Context) (string, string) {
+		if err := db.PingContext(ctx); err != nil {
+			return CheckStatusFail, err.Error()
+		}
+		var version string
+		if err := db.QueryRowContext(ctx, "SELECT sqlite_version()").Scan(&version); err != nil {
+			return CheckStatusFail, err.Error()
+		}
+		return CheckStatusPass, "sqlite " + version
+	})
+
+	run(CheckSchema, func(ctx context.Context) (string, string) {
+		status, err := sqlite.CheckSchema(ctx)
+		if err != nil {
+			return CheckStatusFail, err.Error()
+		}
+		report.Schema = status
+		if len(status.PendingMigrations) > 0 {
+			return CheckStatusFail, fmt.Sprintf("pending migrations: %s", strings.Join(status.PendingMigrations, ", "))
+		}
+		if len(status.MissingColumns) > 0 {
+			return CheckStatusFail, fmt.Sprintf("missing columns: %s", strings.Join(status.MissingColumns, ", "))
+		}
+		return CheckStatusPass, "version " + status.AppliedVersion
+	})
+
+	run(CheckSearchTables, func(ctx context.Context) (string, string) {
+		if report.Schema == nil {
+			return CheckStatusFail, "schema status unavailable"
+		}
+		if len(report.Schema.MissingSearchTables) > 0 {
+			return CheckStatusFail, fmt.Sprintf("missing tables: %s", strings.Join(report.Schema.MissingSearchTables, ", "))
+		}
+		return CheckStatusPass, ""
+	})
+
+	run(CheckEmbedding, func(ctx context.Context) (string, string) {
+		cfg, err := processor.GetEmbeddingConfig(ctx, db)
+		if err != nil {
+			return CheckStatusFail, err.Error()
+		}
+		embedder, err := einoembed.NewEmbedder(ctx, &einoembed.ProviderConfig{
+			ProviderID:   cfg.ProviderID,
+			ProviderType: cfg.ProviderType,
+			APIKey:       cfg.APIKey,
+			APIEndpoint:  cfg.APIEndpoint,
+			ModelID:      cfg.ModelID,
+			Dimension:    cfg.Dimension,
+			ExtraConfig:  cfg.ExtraConfig,
+		})
+		if err != nil {
+			return CheckStatusFail, err.Error()
+		}
+		vectors, err := embedder.EmbedStrings(ctx, []string{"ping"})
+		if err != nil {
+			return CheckStatusFail, err.Error()
+		}
+		if len(vectors) != 1 || len(vectors[0]) == 0 {
+			return CheckStatusFail, "empty embedding returned"
+		}
+		if cfg.Dimension > 0 && len(vectors[0]) != cfg.Dimension {
+			return CheckStatusFail, fmt.Sprintf("dimension mismatch: got %d, want %d", len(vectors[0]), cfg.Dimension)
+		}
+		return CheckStatusPass, fmt.Sprintf("%s/%s, dim %d", cfg.ProviderID, cfg.ModelID, len(vectors[0]))
+	})
+
+	run(CheckChatModel, func(ctx context.Context) (string, string) {
+		raw, _ := settings.GetValue("default_chat_model")
+		providerID, modelID, _ := strings.Cut(strings.TrimSpace(raw), "::")
+		if providerID == "" || modelID == "" {
+			return CheckStatusSkipped, "default_chat_model not set"
+		}
+		info, err := processor.GetProviderInfo(ctx, db, providerID)
+		if err != nil {
+			return CheckStatusFail, err.Error()
+		}
+		maxTokens := 8
+		chatModel, err := einoagent.CreateChatModel(ctx, einoagent.Config{
+			ModelID: modelID,
+			Provider: einoagent.ProviderConfig{
+				ProviderID:  providerID,
+				Type:        info.ProviderType,
+				APIKey:      info.APIKey,
+				APIEndpoint: info.APIEndpoint,
+				ExtraConfig: info.ExtraConfig,
+			},
+			MaxTokens:       &maxTokens,
+			EnableMaxTokens: true,
+		})
+		if err != nil {
+			return CheckStatusFail, err.Error()
+		}
+		if _, err := chatModel.Generate(ctx, []*schema.Message{schema.UserMessage("ping")}); err != nil {
+			return CheckStatusFail, err.Error()
+		}
+		return CheckStatusPass, providerID + "/" + modelID
+	})
+
+	report.Passed = true
+	for _, c := range report.Checks {
+		if c.Status == CheckStatusFail {
+			report.Passed = false
+		}
+	}
+	report.DurationMs = time.Since(start).Milliseconds()
+	s.app.Logger.Info("self-test finished", "passed", report.Passed, "duration_ms", report.DurationMs)
+	return report, nil
+}
@@ -0,0 +1,299 @@
+package maintenance
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"chatclaw/internal/errs"
+	"chatclaw/internal/sqlite"
+	"chatclaw/internal/taskmanager"
+
+	sqlite3 "github.com/mattn/go-sqlite3"
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+const (
+	EventProgress  = "maintenance:progress"
+	EventCompleted = "maintenance:completed"
+
+	OperationBackup = "backup"
+	OperationVacuum = "vacuum"
+
+	// backupPagesPerStep 每步复制的页数；步与步之间释放锁，让其他连接可以继续读写
+	backupPagesPerStep = 256
+	backupStepPause    = 5 * time.Millisecond
+
+	maintenanceTimeout = 30 * time.Minute
+)
+
+// ftsTables 需要定期 optimize 的 FTS5 表
+var ftsTables = []string{"doc_fts", "doc_name_fts", "conv_name_fts"}
+
+// ProgressEvent 维护操作进度
+type ProgressEvent struct {
+	Operation string `json:"operation"`
+	Stage     string `json:"stage"`    // backup: copying; vacuum: optimize_fts / vacuum / checkpoint
+	Progress  int    `json:"progress"` // 0-100
+}
+
+// CompletedEvent 维护操作完成
+type CompletedEvent struct {
+	Operation  string `json:"operation"`
+	Success    bool   `json:"success"`
+	Error      string `json:"error,omitempty"`
+	SizeBefore int64  `json:"size_before"`
+	SizeAfter  int64  `json:"size_after"`
+}
+
+// Result 维护操作结果
+type Result struct {
+	Path       string `json:"path"`
+	SizeBefore int64  `json:"size_before"`
+	SizeAfter  int64  `json:"size_after"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// MaintenanceService 数据库维护服务（备份 / 压缩，暴露给前端调用）
+type MaintenanceService struct {
+	app     *application.App
+	running atomic.Bool
+}
+
+func NewMaintenanceService(app *application.App) *MaintenanceService {
+	return &MaintenanceService{app: app}
+}
+
+// BackupDatabase 使用 SQLite online backup API 将数据库复制到 destPath。
+// 备份期间应用可以继续读写；目标文件已存在时会被覆盖。
+func (s *MaintenanceService) BackupDatabase(destPath string) (*Result, error) {
+	destPath = filepath.Clean(destPath)
+	if destPath == "." || !filepath.IsAbs(destPath) {
+		return nil, errs.New("error.maintenance_backup_path_invalid")
+	}
+	if srcPath := sqlite.Path(); srcPath != "" && sameFile(srcPath, destPath) {
+		return nil, errs.New("error.maintenance_backup_path_invalid")
+	}
+
+	db := sqlite.DB()
+	if db == nil {
+		return nil, errs.New("error.sqlite_not_initialized")
+	}
+	end, err := s.begin()
+	if err != nil {
+		return nil, err
+	}
+	defer end()
+
+	ctx, cancel := context.WithTimeout(context.Background(), maintenanceTimeout)
+	defer cancel()
+
+	start := time.Now()
+	sizeBefore := databaseSize()
+
+	if err := os.MkdirAll(filepath.Dir(destPath), 0o755); err != nil {
+		return nil, s.fail(OperationBackup, errs.Wrap("error.maintenance_backup_failed", err))
+	}
+
+	if err := s.backup(ctx, db.DB, destPath); err != nil {
+		_ = os.Remove(destPath)
+		return nil, s.fail(OperationBackup, errs.Wrap("error.maintenance_backup_failed", err))
+	}
+
+	result := &Result{
+		Path:       destPath,
+		SizeBefore: sizeBefore,
+		SizeAfter:  fileSize(destPath),
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+	s.app.Logger.Info("database backup completed", "dest", destPath, "size", result.SizeAfter, "duration_ms", result.DurationMs)
+	s.app.Event.Emit(EventCompleted, CompletedEvent{
+		Operation:  OperationBackup,
+		Success:    true,
+		SizeBefore: result.SizeBefore,
+		SizeAfter:  result.SizeAfter,
+	})
+	return result, nil
+}
+
+// VacuumDatabase 优化 FTS 索引并执行 VACUUM，回收删除知识库/文档后遗留的空间。
+// 有文档处理任务进行中时拒绝执行；执行期间新提交的文档任务暂缓入队，结束后再处理。
+func (s *MaintenanceService) VacuumDatabase() (*Result, error) {
+	db := sqlite.DB()
+	if db == nil {
+		return nil, errs.New("error.sqlite_not_initialized")
+	}
+	end, err := s.begin()
+	if err != nil {
+		return nil, err
+	}
+	defer end()
+
+	ctx, cancel := context.WithTimeout(context.Background(), maintenanceTimeout)
+	defer cancel()
+
+	start := time.Now()
+	sizeBefore := databaseSize()
+
+	for i, table := range ftsTables {
+		s.emitProgress(OperationVacuum, "optimize_fts", i*30/len(ftsTables))
+		stmt := fmt.Sprintf("INSERT INTO %s(%s) VALUES('optimize')", table, table)
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			return nil, s.fail(OperationVacuum, errs.Wrap("error.maintenance_vacuum_failed", err))
+		}
+	}
+
+	s.emitProgress(OperationVacuum, "vacuum", 30)
+	if _, err := db.ExecContext(ctx, "VACUUM"); err != nil {
+		return nil, s.fail(OperationVacuum, errs.Wrap("error.maintenance_vacuum_failed", err))
+	}
+
+	// WAL 模式下 VACUUM 的结果先写入 -wal 文件，checkpoint 后主文件才会真正变小
+	s.emitProgress(OperationVacuum, "checkpoint", 90)
+	if _, err := db.ExecContext(ctx, "PRAGMA wal_checkpoint(TRUNCATE)"); err != nil {
+		s.app.Logger.Warn("wal checkpoint after vacuum failed", "error", err)
+	}
+
+	result := &Result{
+		Path:       sqlite.Path(),
+		SizeBefore: sizeBefore,
+		SizeAfter:  databaseSize(),
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+	s.app.Logger.Info("database vacuum completed", "size_before", result.SizeBefore, "size_after", result.SizeAfter, "duration_ms", result.DurationMs)
+	s.emitProgress(OperationVacuum, "vacuum", 100)
+	s.app.Event.Emit(EventCompleted, CompletedEvent{
+		Operation:  OperationVacuum,
+		Success:    true,
+		SizeBefore: result.SizeBefore,
+		SizeAfter:  result.SizeAfter,
+	})
+	return result, nil
+}
+
+// begin 标记维护操作开始；同一时间只允许一个维护操作，且不能与文档处理任务并行。
+// 成功时暂停文档任务队列直到返回的 end 被调用
+func (s *MaintenanceService) begin() (end func(), err error) {
+	if !s.running.CompareAndSwap(false, true) {
+		return nil, errs.New("error.maintenance_in_progress")
+	}
+	resume, n := taskmanager.Get().Pause("doc:")
+	if n > 0 {
+		s.running.Store(false)
+		return nil, errs.Newf("error.maintenance_jobs_active", map[string]any{"Count": n})
+	}
+	return func() {
+		resume()
+		s.running.Store(false)
+	}, nil
+}
+
+func (s *MaintenanceService) backup(ctx context.Context, srcDB *sql.DB, destPath string) error {
+	destDB, err := sql.Open("sqlite3", destPath)
+	if err != nil {
+		return err
+	}
+	defer destDB.Close()
+
+	destConn, err := destDB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer destConn.Close()
+
+	srcConn, err := srcDB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer srcConn.Close()
+
+	return destConn.Raw(func(destDriverConn any) error {
+		return srcConn.Raw(func(srcDriverConn any) error {
+			dest, ok := destDriverConn.(*sqlite3.SQLiteConn)
+			if !ok {
+				return errors.New("unexpected destination driver connection")
+			}
+			src, ok := srcDriverConn.(*sqlite3.SQLiteConn)
+			if !ok {
+				return errors.New("unexpected source driver connection")
+			}
+
+			bk, err := dest.Backup("main", src, "main")
+			if err != nil {
+				return err
+			}
+			for {
+				done, err := bk.Step(backupPagesPerStep)
+				if err != nil {
+					_ = bk.Finish()
+					return err
+				}
+				if total := bk.PageCount(); total > 0 {
+					s.emitProgress(OperationBackup, "copying", (total-bk.Remaining())*100/total)
+				}
+				if done {
+					break
+				}
+				select {
+				case <-ctx.Done():
+					_ = bk.Finish()
+					return ctx.Err()
+				case <-time.After(backupStepPause):
+				}
+			}
+			return bk.Finish()
+		})
+	})
+}
+
+func (s *MaintenanceService) emitProgress(operation, stage string, progress int) {
+	s.app.Event.Emit(EventProgress, ProgressEvent{
+		Operation: operation,
+		Stage:     stage,
+		Progress:  progress,
+	})
+}
+
+func (s *MaintenanceService) fail(operation string, err error) error {
+	s.app.Logger.Error("database maintenance failed", "operation", operation, "error", err)
+	s.app.Event.Emit(EventCompleted, CompletedEvent{
+		Operation: operation,
+		Success:   false,
+		Error:     err.Error(),
+	})
+	return err
+}
+
+// databaseSize 返回数据库主文件与 WAL 文件的总大小
+func databaseSize() int64 {
+	path := sqlite.Path()
+	if path == "" {
+		return 0
+	}
+	return fileSize(path) + fileSize(path+"-wal")
+}
+
+func fileSize(path string) int64 {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
+
+func sameFile(a, b string) bool {
+	ai, err := os.Stat(a)
+	if err != nil {
+		return false
+	}
+	bi, err := os.Stat(b)
+	if err != nil {
+		return false
+	}
+	return os.SameFile(ai, bi)
+}
@@ -13,6 +13,7 @@ import (
 
 	"chatclaw/internal/define"
 	"chatclaw/internal/errs"
+	"chatclaw/internal/fts/tokenizer"
 	"chatclaw/internal/services/channels"
 	"chatclaw/internal/services/chat"
 	"chatclaw/internal/services/conversations"
@@ -336,7 +337,8 @@ func (s *OpenClawChannelService) syncSessionConversation(
 		allowNameUpdate = shouldUpdateSyncedConversationName(currentName, name, scope, targetID)
 	}
 	if allowNameUpdate {
-		q = q.Set("name = ?", name)
+		q = q.Set("name = ?", name).
+			Set("name_tokens = ?", tokenizer.TokenizeTitle(name))
 	}
 	if lastMessage != "" {
 		q = q.Set("last_message = ?", lastMessage)
@@ -2,10 +2,68 @@ package providers
 
 import (
 	"strings"
+	"time"
 
+	"chatclaw/internal/logger"
 	"chatclaw/internal/services/chatwiki"
 )
 
+// modelListStaleAfter 距上次同步成功超过该时长时，认为模型列表可能已过期
+const modelListStaleAfter = 24 * time.Hour
+
+// ModelRefreshResult 手动刷新模型列表的结果
+type ModelRefreshResult struct {
+	Success      bool       `json:"success"`
+	Error        string     `json:"error,omitempty"` // 同步失败原因（已脱敏）
+	Added        int        `json:"added"`
+	Updated      int        `json:"updated"`
+	Deleted      int        `json:"deleted"`
+	LastSyncedAt *time.Time `json:"last_synced_at,omitempty"` // 最近一次同步成功的时间
+	// Stale 为 true 时前端提示“模型列表可能已过期”：本次同步失败，或从未成功/成功时间过久
+	Stale bool `json:"stale"`
+}
+
+// RefreshChatWikiModels 立即从 ChatWiki 拉取模型列表并同步到本地，不使用缓存兜底。
+// 同步失败（如受限网络）不返回 error，而是在结果中标记 Stale 并附带上次成功同步的时间。
+func (s *ProvidersService) RefreshChatWikiModels() (*ModelRefreshResult, error) {
+	result := &ModelRefreshResult{}
+	stats, err := chatwiki.NewChatWikiService(s.app).SyncModels()
+	if err != nil {
+		s.app.Logger.Warn("[providers] RefreshChatWikiModels failed", "error", err)
+		result.Error = logger.RedactString(err.Error())
+	} else {
+		result.Success = true
+		result.Added, result.Updated, result.Deleted = stats.Added, stats.Updated, stats.Deleted
+	}
+
+	provider, err := s.GetProvider("chatwiki")
+	if err != nil {
+		return nil, err
+	}
+	result.LastSyncedAt = provider.LastSyncedAt
+	result.Stale = !result.Success || modelListStale(provider.LastSyncedAt)
+	return result, nil
+}
+
+// GetChatWikiModelSyncStatus 返回 ChatWiki 模型列表的同步状态（不触发同步），供界面显示过期提示
+func (s *ProvidersService) GetChatWikiModelSyncStatus() (*ModelRefreshResult, error) {
+	provider, err := s.GetProvider("chatwiki")
+	if err != nil {
+		return nil, err
+	}
+	result := &ModelRefreshResult{Success: true, LastSyncedAt: provider.LastSyncedAt}
+	if syncErr := chatwiki.LastModelSyncError(); syncErr != nil {
+		result.Success = false
+		result.Error = logger.RedactString(syncErr.Error())
+	}
+	result.Stale = !result.Success || modelListStale(provider.LastSyncedAt)
+	return result, nil
+}
+
+func modelListStale(lastSyncedAt *time.Time) bool {
+	return lastSyncedAt == nil || time.Since(*lastSyncedAt) > modelListStaleAfter
+}
+
 func (s *ProvidersService) buildChatWikiProviderWithModels(provider *Provider, catalog *chatwiki.ModelCatalog) *ProviderWithModels {
 	if provider == nil {
 		return nil
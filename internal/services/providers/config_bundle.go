@@ -0,0 +1,473 @@
+package providers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"chatclaw/internal/errs"
+
+	"github.com/uptrace/bun"
+)
+
+// ConfigBundleVersion is bumped whenever the bundle layout changes incompatibly.
+const ConfigBundleVersion = 1
+
+// Import modes for ImportConfig.
+const (
+	// ImportModeMerge only adds what is missing locally: new models and agents are created and
+	// empty provider fields are filled in; existing entries are left untouched.
+	ImportModeMerge = "merge"
+	// ImportModeReplace also overwrites matching providers, models and agents with the bundle
+	// values. Nothing is deleted.
+	ImportModeReplace = "replace"
+)
+
+// ConfigBundle is the portable provider / model / agent configuration produced by ExportConfig.
+type ConfigBundle struct {
+	Version    int                    `json:"version"`
+	ExportedAt time.Time              `json:"exported_at"`
+	Providers  []ConfigBundleProvider `json:"providers"`
+	Models     []ConfigBundleModel    `json:"models"`
+	Agents     []ConfigBundleAgent    `json:"agents"`
+}
+
+type ConfigBundleProvider struct {
+	ProviderID  string `json:"provider_id"`
+	Enabled     bool   `json:"enabled"`
+	APIEndpoint string `json:"api_endpoint"`
+	APIKey      string `json:"api_key,omitempty"`
+	ExtraConfig string `json:"extra_config"`
+}
+
+type ConfigBundleModel struct {
+	ProviderID        string   `json:"provider_id"`
+	ModelID           string   `json:"model_id"`
+	Name              string   `json:"name"`
+	Type              string   `json:"type"`
+	Capabilities      []string `json:"capabilities"`
+	SupportsTools     bool     `json:"supports_tools"`
+	SupportsReasoning bool     `json:"supports_reasoning"`
+	Enabled           bool     `json:"enabled"`
+}
+
+// ConfigBundleAgent holds the portable part of an agent. Knowledge libraries, MCP servers and the
+// work directory are machine-specific and are not exported.
+type ConfigBundleAgent struct {
+	Name      string `json:"name"`
+	IsDefault bool   `json:"is_default"` // the built-in agent mapped to OpenClaw "main"
+	Prompt    string `json:"prompt"`
+	Icon      string `json:"icon"`
+
+	DefaultLLMProviderID    string  `json:"default_llm_provider_id"`
+	DefaultLLMModelID       string  `json:"default_llm_model_id"`
+	LLMTemperature          float64 `json:"llm_temperature"`
+	LLMTopP                 float64 `json:"llm_top_p"`
+	LLMMaxContextCount      int     `json:"llm_max_context_count"`
+	LLMMaxTokens            int     `json:"llm_max_tokens"`
+	EnableLLMTemperature    bool    `json:"enable_llm_temperature"`
+	EnableLLMTopP           bool    `json:"enable_llm_top_p"`
+	EnableLLMMaxTokens      bool    `json:"enable_llm_max_tokens"`
+	RetrievalMatchThreshold float64 `json:"retrieval_match_threshold"`
+	RetrievalTopK           int     `json:"retrieval_top_k"`
+
+	SandboxMode       string `json:"sandbox_mode"`
+	SandboxNetwork    bool   `json:"sandbox_network"`
+	EnabledTools      string `json:"enabled_tools"`
+	MaxToolIterations int    `json:"max_tool_iterations"`
+	ThinkingBudget    int    `json:"thinking_budget"`
+	ResponseLanguage  string `json:"response_language"`
+	OnToolError       string `json:"on_tool_error"`
+}
+
+// ImportConfigOptions 导入配置的参数
+type ImportConfigOptions struct {
+	Mode string `json:"mode"` // merge (default) | replace
+}
+
+// ImportConfigResult summarizes what ImportConfig changed.
+type ImportConfigResult struct {
+	ProvidersUpdated int      `json:"providers_updated"`
+	ModelsAdded      int      `json:"models_added"`
+	ModelsUpdated    int      `json:"models_updated"`
+	AgentsAdded      int      `json:"agents_added"`
+	AgentsUpdated    int      `json:"agents_updated"`
+	Skipped          []string `json:"skipped"`
+}
+
+// deviceBoundProviders have API keys tied to this installation (ChatClaw: derived from the device ID;
+// ChatWiki: issued at login) and a model list synced from the server, so neither the key nor the
+// models are exported or imported.
+var deviceBoundProviders = map[string]bool{
+	"chatclaw": true,
+	"chatwiki": true,
+}
+
+// ConfigAgents exports and imports the agent part of a config bundle. It is implemented by the
+// agents package and registered from bootstrap with RegisterConfigAgents, since providers cannot
+// import agents (agents → tools → … → providers).
+type ConfigAgents interface {
+	// NormalizeAgent trims and validates a bundle agent with the same rules as CreateAgent / UpdateAgent.
+	NormalizeAgent(a *ConfigBundleAgent) error
+	// ExportAgents returns the portable settings of all agents.
+	ExportAgents(ctx context.Context, db bun.IDB) ([]ConfigBundleAgent, error)
+	// ImportAgents creates the agents missing locally and, with replace, overwrites matching ones.
+	ImportAgents(ctx context.Context, db bun.IDB, items []ConfigBundleAgent, replace bool) (added, updated int, err error)
+}
+
+// configAgents is set by RegisterConfigAgents; while nil, bundles are exported without agents
+// and imported agents are skipped.
+var configAgents ConfigAgents
+
+// RegisterConfigAgents sets the handler used for the agents of ExportConfig / ImportConfig.
+func RegisterConfigAgents(h ConfigAgents) {
+	configAgents = h
+}
+
+// ExportConfig 导出供应商、模型与助手配置（JSON）。includeAPIKeys=false 时不导出 API Key。
+func (s *ProvidersService) ExportConfig(includeAPIKeys bool) (string, error) {
+	db, err := s.db()
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var providers []providerModel
+	if err := db.NewSelect().Model(&providers).OrderExpr("sort_order ASC, id ASC").Scan(ctx); err != nil {
+		return "", errs.Wrap("error.provider_list_failed", err)
+	}
+	var models []modelModel
+	if err := db.NewSelect().Model(&models).OrderExpr("provider_id ASC, type ASC, sort_order ASC").Scan(ctx); err != nil {
+		return "", errs.Wrap("error.model_list_failed", err)
+	}
+	var bundleAgents []ConfigBundleAgent
+	if configAgents != nil {
+		if bundleAgents, err = configAgents.ExportAgents(ctx, db); err != nil {
+			return "", errs.Wrap("error.agent_list_failed", err)
+		}
+	}
+
+	bundle := ConfigBundle{
+		Version:    ConfigBundleVersion,
+		ExportedAt: time.Now().UTC(),
+		Providers:  make([]ConfigBundleProvider, 0, len(providers)),
+		Models:     make([]ConfigBundleModel, 0, len(models)),
+		Agents:     make([]ConfigBundleAgent, 0, len(bundleAgents)),
+	}
+	for _, p := range providers {
+		bp := ConfigBundleProvider{
+			ProviderID:  p.ProviderID,
+			Enabled:     p.Enabled,
+			APIEndpoint: p.APIEndpoint,
+			ExtraConfig: p.ExtraConfig,
+		}
+		if includeAPIKeys && !deviceBoundProviders[p.ProviderID] {
+			bp.APIKey = p.APIKey
+		}
+		bundle.Providers = append(bundle.Providers, bp)
+	}
+	for i := range models {
+		if deviceBoundProviders[models[i].ProviderID] {
+			continue
+		}
+		dto := models[i].toDTO()
+		bundle.Models = append(bundle.Models, ConfigBundleModel{
+			ProviderID:        dto.ProviderID,
+			ModelID:           dto.ModelID,
+			Name:              dto.Name,
+			Type:              dto.Type,
+			Capabilities:      dto.Capabilities,
+			SupportsTools:     dto.SupportsTools,
+			SupportsReasoning: dto.SupportsReasoning,
+			Enabled:           dto.Enabled,
+		})
+	}
+	bundle.Agents = append(bundle.Agents, bundleAgents...)
+
+	data, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return "", errs.Wrap("error.config_export_failed", err)
+	}
+	return string(data), nil
+}
+
+// ImportConfig 导入 ExportConfig 生成的配置。Only providers that exist locally are updated;
+// providers are never disabled by an import. Agents are matched by name (the default agent by
+// IsDefault); a default model that does not exist locally after the import is cleared.
+func (s *ProvidersService) ImportConfig(bundleJSON string, options ImportConfigOptions) (*ImportConfigResult, error) {
+	var bundle ConfigBundle
+	if err := json.Unmarshal([]byte(bundleJSON), &bundle); err != nil {
+		return nil, errs.Wrap("error.config_import_invalid", err)
+	}
+	if bundle.Version <= 0 || bundle.Version > ConfigBundleVersion {
+		return nil, errs.Newf("error.config_import_version_unsupported", map[string]any{"Version": bundle.Version})
+	}
+
+	mode := strings.TrimSpace(options.Mode)
+	if mode == "" {
+		mode = ImportModeMerge
+	}
+	if mode != ImportModeMerge && mode != ImportModeReplace {
+		return nil, errs.Newf("error.config_import_mode_invalid", map[string]any{"Mode": mode})
+	}
+	replace := mode == ImportModeReplace
+
+	if err := normalizeBundle(&bundle); err != nil {
+		return nil, err
+	}
+
+	db, err := s.db()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	result := &ImportConfigResult{Skipped: []string{}}
+	err = db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		known, err := importProviders(ctx, tx, bundle.Providers, replace, result)
+		if err != nil {
+			return err
+		}
+		if err := importModels(ctx, tx, bundle.Models, known, replace, result); err != nil {
+			return err
+		}
+		return importAgents(ctx, tx, bundle.Agents, replace, result)
+	})
+	if err != nil {
+		return nil, errs.Wrap("error.config_import_failed", err)
+	}
+
+	s.app.Logger.Info("[providers] config imported", "mode", mode,
+		"providers", result.ProvidersUpdated, "models_added", result.ModelsAdded, "models_updated", result.ModelsUpdated,
+		"agents_added", result.AgentsAdded, "agents_updated", result.AgentsUpdated, "skipped", len(result.Skipped))
+
+	// Notify OpenClaw Gateway of provider config change
+	s.app.Event.Emit("providers:config-changed", nil)
+	return result, nil
+}
+
+// normalizeBundle trims and validates the bundle before anything is written, using the same rules
+// as CreateModel / CreateAgent.
+func normalizeBundle(b *ConfigBundle) error {
+	for i := range b.Providers {
+		p := &b.Providers[i]
+		p.ProviderID = strings.TrimSpace(p.ProviderID)
+		if deviceBoundProviders[p.ProviderID] {
+			p.APIKey = ""
+		}
+	}
+
+	for i := range b.Models {
+		m := &b.Models[i]
+		m.ProviderID = strings.TrimSpace(m.ProviderID)
+		m.ModelID = strings.TrimSpace(m.ModelID)
+		m.Name = strings.TrimSpace(m.Name)
+		m.Type = strings.TrimSpace(m.Type)
+		if m.ModelID == "" {
+			return errs.New("error.model_id_required")
+		}
+		if len([]rune(m.ModelID)) > 40 {
+			return errs.New("error.model_id_too_long")
+		}
+		if err := validateModelID(m.ModelID); err != nil {
+			return err
+		}
+		if m.Name == "" {
+			return errs.New("error.model_name_required")
+		}
+		if len([]rune(m.Name)) > 40 {
+			return errs.New("error.model_name_too_long")
+		}
+		if m.Type != "llm" && m.Type != "embedding" && m.Type != "rerank" {
+			return errs.New("error.model_type_invalid")
+		}
+	}
+
+	if configAgents != nil {
+		for i := range b.Agents {
+			if err := configAgents.NormalizeAgent(&b.Agents[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// importProviders updates existing providers and returns the set of local provider IDs.
+func importProviders(ctx context.Context, tx bun.Tx, items []ConfigBundleProvider, replace bool, result *ImportConfigResult) (map[string]bool, error) {
+	var locals []providerModel
+	if err := tx.NewSelect().Model(&locals).Scan(ctx); err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*providerModel, len(locals))
+	known := make(map[string]bool, len(locals))
+	for i := range locals {
+		byID[locals[i].ProviderID] = &locals[i]
+		known[locals[i].ProviderID] = true
+	}
+
+	for _, item := range items {
+		local, ok := byID[item.ProviderID]
+		if !ok {
+			result.Skipped = append(result.Skipped, "provider:"+item.ProviderID)
+			continue
+		}
+
+		q := tx.NewUpdate().Model((*providerModel)(nil)).Where("provider_id = ?", item.ProviderID)
+		changed := false
+		set := func(column string, value any) {
+			q = q.Set(column+" = ?", value)
+			changed = true
+		}
+		if item.Enabled && !local.Enabled {
+			set("enabled", true)
+		}
+		if item.APIKey != "" && (replace || strings.TrimSpace(local.APIKey) == "") && item.APIKey != local.APIKey {
+			set("api_key", item.APIKey)
+		}
+		if item.APIEndpoint != "" && (replace || strings.TrimSpace(local.APIEndpoint) == "") && item.APIEndpoint != local.APIEndpoint {
+			set("api_endpoint", item.APIEndpoint)
+		}
+		if item.ExtraConfig != "" && (replace || isEmptyJSON(local.ExtraConfig)) && item.ExtraConfig != local.ExtraConfig {
+			set("extra_config", item.ExtraConfig)
+		}
+		if !changed {
+			continue
+		}
+		if _, err := q.Exec(ctx); err != nil {
+			return nil, err
+		}
+		result.ProvidersUpdated++
+	}
+	return known, nil
+}
+
+// importModels adds missing models and, in replace mode, overwrites user-created ones. Built-in
+// models only take the enabled flag; models of device-bound providers are skipped.
+func importModels(ctx context.Context, tx bun.Tx, items []ConfigBundleModel, knownProviders map[string]bool, replace bool, result *ImportConfigResult) error {
+	for _, item := range items {
+		key := "model:" + item.ProviderID + "/" + item.ModelID
+		if !knownProviders[item.ProviderID] || deviceBoundProviders[item.ProviderID] {
+			result.Skipped = append(result.Skipped, key)
+			continue
+		}
+
+		capabilities := []string{"text"}
+		if len(item.Capabilities) > 0 {
+			capabilities = item.Capabilities
+		}
+		capabilitiesJSON, _ := json.Marshal(capabilities)
+
+		var existing modelModel
+		err := tx.NewSelect().
+			Model(&existing).
+			Where("provider_id = ?", item.ProviderID).
+			Where("model_id = ?", item.ModelID).
+			Limit(1).
+			Scan(ctx)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+
+		if errors.Is(err, sql.ErrNoRows) {
+			var maxSortOrder int
+			if err := tx.NewSelect().
+				Model((*modelModel)(nil)).
+				Where("provider_id = ?", item.ProviderID).
+				Where("type = ?", item.Type).
+				ColumnExpr("COALESCE(MAX(sort_order), 0)").
+				Scan(ctx, &maxSortOrder); err != nil {
+				return err
+			}
+			m := &modelModel{
+				ProviderID:        item.ProviderID,
+				ModelID:           item.ModelID,
+				Name:              item.Name,
+				Type:              item.Type,
+				Capabilities:      string(capabilitiesJSON),
+				SupportsTools:     item.SupportsTools,
+				SupportsReasoning: item.SupportsReasoning,
+				Enabled:           item.Enabled,
+				SortOrder:         maxSortOrder + 1,
+			}
+			if _, err := tx.NewInsert().Model(m).Exec(ctx); err != nil {
+				return err
+			}
+			result.ModelsAdded++
+			continue
+		}
+
+		if !replace {
+			continue
+		}
+		q := tx.NewUpdate().
+			Model((*modelModel)(nil)).
+			Where("id = ?", existing.ID).
+			Set("enabled = ?", item.Enabled)
+		if !existing.IsBuiltin {
+			if existing.Type != item.Type {
+				result.Skipped = append(result.Skipped, key)
+				continue
+			}
+			q = q.Set("name = ?", item.Name).
+				Set("capabilities = ?", string(capabilitiesJSON)).
+				Set("supports_tools = ?", item.SupportsTools).
+				Set("supports_reasoning = ?", item.SupportsReasoning)
+		}
+		if _, err := q.Exec(ctx); err != nil {
+			return err
+		}
+		result.ModelsUpdated++
+	}
+	return nil
+}
+
+// importAgents clears default models that do not exist locally and hands the agents to
+// configAgents, which creates missing ones and, in replace mode, overwrites matching ones.
+func importAgents(ctx context.Context, tx bun.Tx, items []ConfigBundleAgent, replace bool, result *ImportConfigResult) error {
+	if configAgents == nil {
+		for _, item := range items {
+			result.Skipped = append(result.Skipped, "agent:"+item.Name)
+		}
+		return nil
+	}
+
+	for i := range items {
+		item := &items[i]
+		if item.DefaultLLMProviderID == "" && item.DefaultLLMModelID == "" {
+			continue
+		}
+		count, err := tx.NewSelect().
+			Model((*modelModel)(nil)).
+			Where("provider_id = ?", item.DefaultLLMProviderID).
+			Where("model_id = ?", item.DefaultLLMModelID).
+			Count(ctx)
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			item.DefaultLLMProviderID = ""
+			item.DefaultLLMModelID = ""
+		}
+	}
+
+	added, updated, err := configAgents.ImportAgents(ctx, tx, items, replace)
+	if err != nil {
+		return err
+	}
+	result.AgentsAdded += added
+	result.AgentsUpdated += updated
+	return nil
+}
+
+func isEmptyJSON(s string) bool {
+	s = strings.TrimSpace(s)
+	return s == "" || s == "{}" || s == "null"
+}
@@ -0,0 +1,75 @@
+package providers
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"chatclaw/internal/eino/chatmodel"
+	"chatclaw/internal/errs"
+)
+
+// geminiNativeHost Gemini 原生 API 的域名；其 OpenAI 兼容接口位于 /v1beta/openai 下
+const geminiNativeHost = "generativelanguage.googleapis.com"
+
+var (
+	// fullRequestPathPattern 匹配误填为完整请求地址的路径（SDK 会自行拼接这些后缀）
+	fullRequestPathPattern = regexp.MustCompile(`(?i)(/chat/completions|/completions|/responses|/embeddings|/messages|/models|:(stream)?generatecontent|/api/chat|/api/generate)$`)
+	// versionSuffixPattern 匹配以版本号结尾的路径，如 /v1、/api/v3、/v1beta
+	versionSuffixPattern = regexp.MustCompile(`(?i)/v\d+[a-z0-9]*$`)
+)
+
+// validateAPIEndpoint 按供应商类型检查 API 地址的形态：协议、是否误填完整请求路径、版本后缀等。
+// 返回的错误附带建议的地址。空地址表示使用 SDK 默认值，不做检查。
+func validateAPIEndpoint(providerType, endpoint string) error {
+	endpoint = strings.TrimSpace(endpoint)
+	if endpoint == "" {
+		return nil
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errs.Newf("error.provider_endpoint_invalid", map[string]any{"Endpoint": endpoint})
+	}
+
+	base := u.Scheme + "://" + u.Host
+	path := strings.TrimRight(u.Path, "/")
+	mismatch := func(suggestion string) error {
+		return errs.Newf("error.provider_endpoint_mismatch", map[string]any{
+			"Endpoint":   endpoint,
+			"Type":       providerType,
+			"Suggestion": suggestion,
+		})
+	}
+
+	// 填了完整的请求地址（如 .../v1/chat/completions）：去掉请求路径
+	if loc := fullRequestPathPattern.FindStringIndex(path); loc != nil {
+		return mismatch(base + path[:loc[0]])
+	}
+
+	switch providerType {
+	case "openai", chatmodel.ProviderTypeOpenAIResponses, "qwen":
+		// Gemini 原生地址不兼容 OpenAI 协议，需使用其 OpenAI 兼容路径
+		if strings.EqualFold(u.Hostname(), geminiNativeHost) && !strings.Contains(path, "/openai") {
+			return mismatch(base + "/v1beta/openai")
+		}
+		// OpenAI 兼容接口需带版本前缀，SDK 只拼接 /chat/completions
+		if path == "" {
+			return mismatch(base + "/v1")
+		}
+	case "anthropic":
+		if strings.EqualFold(u.Hostname(), geminiNativeHost) {
+			return mismatch("https://api.anthropic.com/v1")
+		}
+	case "gemini":
+		// OpenAI 兼容路径应配合 openai 类型使用；版本号由 SDK 自行拼接
+		if strings.Contains(path, "/openai") || versionSuffixPattern.MatchString(path) {
+			return mismatch(base + versionSuffixPattern.ReplaceAllString(strings.Split(path, "/openai")[0], ""))
+		}
+	case "ollama":
+		// Ollama 客户端会拼接 /api/...，地址只需填写主机
+		if strings.HasSuffix(path, "/v1") || strings.HasSuffix(path, "/api") {
+			return mismatch(base)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"runtime"
+	"strings"
+	"time"
+
+	"chatclaw/internal/define"
+	"chatclaw/internal/errs"
+	"chatclaw/internal/httpclient"
+	"chatclaw/internal/logger"
+)
+
+// GetRecentLLMCalls 返回最近记录的模型供应商请求（最新在前，最多 httpclient.AuditBufferSize 条）。
+// 仅在设置 debug_llm_requests 开启期间记录；请求/响应内容已截断，API Key 等凭据已脱敏。
+func (s *ProvidersService) GetRecentLLMCalls() []httpclient.Call {
+	return httpclient.RecentCalls()
+}
+
+// ClearRecentLLMCalls 清空已记录的模型供应商请求
+func (s *ProvidersService) ClearRecentLLMCalls() {
+	httpclient.ClearCalls()
+}
+
+// ExportLLMCall 将一条已记录的请求导出为可直接粘贴到 issue 的 Markdown 报告：
+// 应用版本与平台、匹配到的供应商类型/地址、模型、状态与错误、发送的消息（已脱敏）以及响应的第一段。
+func (s *ProvidersService) ExportLLMCall(id int64) (string, error) {
+	call, ok := httpclient.FindCall(id)
+	if !ok {
+		return "", errs.Newf("error.llm_call_not_found", map[string]any{"ID": id})
+	}
+
+	provider := s.matchCallProvider(call)
+
+	var b strings.Builder
+	b.WriteString("## ChatClaw LLM call report\n\n")
+	fmt.Fprintf(&b, "- App version: %s (%s/%s, %s)\n", define.Version, runtime.GOOS, runtime.GOARCH, runtime.Version())
+	fmt.Fprintf(&b, "- Time: %s\n", call.StartedAt.UTC().Format(time.RFC3339))
+	if provider != nil {
+		fmt.Fprintf(&b, "- Provider: %s (type: %s, id: %s)\n", provider.Name, provider.Type, provider.ProviderID)
+		fmt.Fprintf(&b, "- Configured endpoint: %s\n", logger.RedactString(provider.APIEndpoint))
+	} else {
+		fmt.Fprintf(&b, "- Provider: unknown (host: %s)\n", call.Provider)
+	}
+	fmt.Fprintf(&b, "- Request: %s %s\n", call.Method, call.Endpoint)
+	fmt.Fprintf(&b, "- Model: %s\n", call.Model)
+	fmt.Fprintf(&b, "- Status: %d, latency: %d ms, tokens: %d in / %d out\n", call.Status, call.LatencyMs, call.InputTokens, call.OutputTokens)
+	if call.Error != "" {
+		fmt.Fprintf(&b, "- Error: %s\n", call.Error)
+	}
+
+	b.WriteString("\n### Request\n\n```json\n")
+	b.WriteString(prettyJSON(call.Request))
+	b.WriteString("\n```\n\n### Response (first chunk)\n\n```\n")
+	b.WriteString(firstResponseChunk(call.Response))
+	b.WriteString("\n```\n")
+
+	report := b.String()
+	// 兜底：报告中若出现该供应商的 API Key 原文（例如被放在非常规字段里），一律掩码
+	if provider != nil && len(provider.APIKey) >= 8 {
+		report = strings.ReplaceAll(report, provider.APIKey, logger.MaskSecret(provider.APIKey))
+	}
+	return report, nil
+}
+
+// matchCallProvider 按请求的主机名匹配已配置的供应商；多个供应商共用同一地址时优先选择包含该模型的供应商
+func (s *ProvidersService) matchCallProvider(call httpclient.Call) *providerModel {
+	db, err := s.db()
+	if err != nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var all []providerModel
+	if err := db.NewSelect().Model(&all).OrderExpr("sort_order ASC, id ASC").Scan(ctx); err != nil {
+		return nil
+	}
+	var candidates []*providerModel
+	for i := range all {
+		u, err := url.Parse(strings.TrimSpace(all[i].APIEndpoint))
+		if err == nil && u.Host != "" && strings.EqualFold(u.Host, call.Provider) {
+			candidates = append(candidates, &all[i])
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+	if len(candidates) > 1 && call.Model != "" {
+		for _, p := range candidates {
+			n, err := db.NewSelect().
+				Model((*modelModel)(nil)).
+				Where("provider_id = ?", p.ProviderID).
+				Where("model_id = ?", call.Model).
+				Count(ctx)
+			if err == nil && n > 0 {
+				return p
+			}
+		}
+	}
+	return candidates[0]
+}
+
+// prettyJSON 缩进 JSON 请求体；被截断或非 JSON 的内容原样返回
+func prettyJSON(s string) string {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, []byte(s), "", "  "); err != nil {
+		return s
+	}
+	return buf.String()
+}
+
+// firstResponseChunk 返回流式响应（SSE）的第一个 data 块；非流式响应原样返回
+func firstResponseChunk(s string) string {
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		data, ok := strings.CutPrefix(line, "data:")
+		if !ok {
+			continue
+		}
+		if data = strings.TrimSpace(data); data != "" {
+			return prettyJSON(data)
+		}
+	}
+	return prettyJSON(s)
+}
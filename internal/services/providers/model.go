@@ -26,24 +26,29 @@ type Provider struct {
 	ExtraConfig string    `json:"extra_config"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
+
+	LastSyncedAt *time.Time `json:"last_synced_at,omitempty"` // last successful model list sync (ChatClaw / ChatWiki)
 }
 
 // Model 妯″瀷 DTO锛堟毚闇茬粰鍓嶇锛?
 type Model struct {
-	ID              int64     `json:"id"`
-	ProviderID      string    `json:"provider_id"`
-	ModelID         string    `json:"model_id"`
-	Name            string    `json:"name"`
-	ModelSupplier   string    `json:"model_supplier"`
-	UniModelName    string    `json:"uni_model_name"`
-	Type            string    `json:"type"`         // llm, embedding, rerank
-	Capabilities    []string  `json:"capabilities"` // 鏀寔鐨勮緭鍏ョ被鍨? text, image, audio, video, file
-	DefaultUseModel string    `json:"default_use_model"`
-	IsBuiltin       bool      `json:"is_builtin"`
-	Enabled         bool      `json:"enabled"`
-	SortOrder       int       `json:"sort_order"`
-	CreatedAt       time.Time `json:"created_at"`
-	UpdatedAt       time.Time `json:"updated_at"`
+	ID                int64     `json:"id"`
+	ProviderID        string    `json:"provider_id"`
+	ModelID           string    `json:"model_id"`
+	Name              string    `json:"name"`
+	ModelSupplier     string    `json:"model_supplier"`
+	UniModelName      string    `json:"uni_model_name"`
+	Type              string    `json:"type"`         // llm, embedding, rerank
+	Capabilities      []string  `json:"capabilities"` // 鏀寔鐨勮緭鍏ョ被鍨? text, image, audio, video, file
+	SupportsTools     bool      `json:"supports_tools"`
+	SupportsVision    bool      `json:"supports_vision"` // derived from capabilities ("image")
+	SupportsReasoning bool      `json:"supports_reasoning"`
+	DefaultUseModel   string    `json:"default_use_model"`
+	IsBuiltin         bool      `json:"is_builtin"`
+	Enabled           bool      `json:"enabled"`
+	SortOrder         int       `json:"sort_order"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
 }
 
 // ModelGroup 妯″瀷鍒嗙粍锛堟寜绫诲瀷鍒嗙粍锛?
@@ -64,22 +69,30 @@ type UpdateProviderInput struct {
 	APIKey      *string `json:"api_key"`
 	APIEndpoint *string `json:"api_endpoint"`
 	ExtraConfig *string `json:"extra_config"`
+	// Type switches an OpenAI-compatible provider between "openai" (chat/completions) and
+	// "openai-responses" (Responses API); other types cannot be changed.
+	Type *string `json:"type"`
 }
 
 // CreateModelInput 鍒涘缓妯″瀷鐨勮緭鍏ュ弬鏁?
 type CreateModelInput struct {
-	ModelID      string   `json:"model_id"`
-	Name         string   `json:"name"`
-	Type         string   `json:"type"`         // llm, embedding, rerank
-	Capabilities []string `json:"capabilities"` // 鏀寔鐨勮緭鍏ョ被鍨? text, image, audio, video, file
+	ModelID           string   `json:"model_id"`
+	Name              string   `json:"name"`
+	Type              string   `json:"type"`               // llm, embedding, rerank
+	Capabilities      []string `json:"capabilities"`       // 鏀寔鐨勮緭鍏ョ被鍨? text, image, audio, video, file
+	SupportsTools     *bool    `json:"supports_tools"`     // nil = supported for llm models
+	SupportsReasoning *bool    `json:"supports_reasoning"` // nil = supported for llm models
 }
 
 // UpdateModelInput 鏇存柊妯″瀷鐨勮緭鍏ュ弬鏁?
 // 娉ㄦ剰锛歮odel_id 鍜?type 鍒涘缓鍚庝笉鍏佽淇敼
 type UpdateModelInput struct {
-	Name         *string  `json:"name"`
-	Enabled      *bool    `json:"enabled"`
-	Capabilities []string `json:"capabilities"` // 鏀寔鐨勮緭鍏ョ被鍨? text, image, audio, video, file
+	Name              *string  `json:"name"`
+	Enabled           *bool    `json:"enabled"`
+	Capabilities      []string `json:"capabilities"` // 鏀寔鐨勮緭鍏ョ被鍨? text, image, audio, video, file
+	SupportsTools     *bool    `json:"supports_tools"`
+	SupportsVision    *bool    `json:"supports_vision"` // adds/removes "image" in capabilities
+	SupportsReasoning *bool    `json:"supports_reasoning"`
 }
 
 // providerModel 鏁版嵁搴撴ā鍨?
@@ -100,6 +113,8 @@ type providerModel struct {
 	ExtraConfig string    `bun:"extra_config,notnull"`
 	CreatedAt   time.Time `bun:"created_at,notnull"`
 	UpdatedAt   time.Time `bun:"updated_at,notnull"`
+
+	LastSyncedAt *time.Time `bun:"last_synced_at"`
 }
 
 // BeforeInsert 鍦?INSERT 鏃惰嚜鍔ㄨ缃?created_at 鍜?updated_at锛堝瓧绗︿覆鏍煎紡锛?
@@ -136,6 +151,8 @@ func (m *providerModel) toDTO() Provider {
 		ExtraConfig: m.ExtraConfig,
 		CreatedAt:   m.CreatedAt,
 		UpdatedAt:   m.UpdatedAt,
+
+		LastSyncedAt: m.LastSyncedAt,
 	}
 }
 
@@ -143,18 +160,20 @@ func (m *providerModel) toDTO() Provider {
 type modelModel struct {
 	bun.BaseModel `bun:"table:models,alias:m"`
 
-	ID              int64     `bun:"id,pk,autoincrement"`
-	ProviderID      string    `bun:"provider_id,notnull"`
-	ModelID         string    `bun:"model_id,notnull"`
-	Name            string    `bun:"name,notnull"`
-	Type            string    `bun:"type,notnull"`
-	Capabilities    string    `bun:"capabilities,notnull"` // JSON 鏁扮粍鏍煎紡瀛樺偍
-	DefaultUseModel string    `bun:"default_use_model,notnull"`
-	IsBuiltin       bool      `bun:"is_builtin,notnull"`
-	Enabled         bool      `bun:"enabled,notnull"`
-	SortOrder       int       `bun:"sort_order,notnull"`
-	CreatedAt       time.Time `bun:"created_at,notnull"`
-	UpdatedAt       time.Time `bun:"updated_at,notnull"`
+	ID                int64     `bun:"id,pk,autoincrement"`
+	ProviderID        string    `bun:"provider_id,notnull"`
+	ModelID           string    `bun:"model_id,notnull"`
+	Name              string    `bun:"name,notnull"`
+	Type              string    `bun:"type,notnull"`
+	Capabilities      string    `bun:"capabilities,notnull"` // JSON 鏁扮粍鏍煎紡瀛樺偍
+	SupportsTools     bool      `bun:"supports_tools,notnull"`
+	SupportsReasoning bool      `bun:"supports_reasoning,notnull"`
+	DefaultUseModel   string    `bun:"default_use_model,notnull"`
+	IsBuiltin         bool      `bun:"is_builtin,notnull"`
+	Enabled           bool      `bun:"enabled,notnull"`
+	SortOrder         int       `bun:"sort_order,notnull"`
+	CreatedAt         time.Time `bun:"created_at,notnull"`
+	UpdatedAt         time.Time `bun:"updated_at,notnull"`
 }
 
 // BeforeInsert 鍦?INSERT 鏃惰嚜鍔ㄨ缃?created_at 鍜?updated_at锛堝瓧绗︿覆鏍煎紡锛?
@@ -179,19 +198,31 @@ func (m *modelModel) toDTO() Model {
 	var capabilities []string
 	_ = json.Unmarshal([]byte(m.Capabilities), &capabilities)
 	return Model{
-		ID:              m.ID,
-		ProviderID:      m.ProviderID,
-		ModelID:         m.ModelID,
-		Name:            m.Name,
-		ModelSupplier:   "",
-		UniModelName:    "",
-		Type:            m.Type,
-		Capabilities:    capabilities,
-		DefaultUseModel: m.DefaultUseModel,
-		IsBuiltin:       m.IsBuiltin,
-		Enabled:         m.Enabled,
-		SortOrder:       m.SortOrder,
-		CreatedAt:       m.CreatedAt,
-		UpdatedAt:       m.UpdatedAt,
+		ID:                m.ID,
+		ProviderID:        m.ProviderID,
+		ModelID:           m.ModelID,
+		Name:              m.Name,
+		ModelSupplier:     "",
+		UniModelName:      "",
+		Type:              m.Type,
+		Capabilities:      capabilities,
+		SupportsTools:     m.SupportsTools,
+		SupportsVision:    hasImageCapability(capabilities),
+		SupportsReasoning: m.SupportsReasoning,
+		DefaultUseModel:   m.DefaultUseModel,
+		IsBuiltin:         m.IsBuiltin,
+		Enabled:           m.Enabled,
+		SortOrder:         m.SortOrder,
+		CreatedAt:         m.CreatedAt,
+		UpdatedAt:         m.UpdatedAt,
+	}
+}
+
+func hasImageCapability(capabilities []string) bool {
+	for _, c := range capabilities {
+		if c == "image" {
+			return true
+		}
 	}
+	return false
 }
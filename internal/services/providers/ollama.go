@@ -0,0 +1,321 @@
+package providers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"chatclaw/internal/errs"
+	"chatclaw/internal/httpclient"
+)
+
+// EventOllamaPullProgress streams OllamaPullModel progress to the frontend.
+const EventOllamaPullProgress = "ollama:pull-progress"
+
+const ollamaProviderID = "ollama"
+
+// OllamaLocalModel is a model installed in the local Ollama (/api/tags).
+type OllamaLocalModel struct {
+	ModelID       string `json:"model_id"`
+	Size          int64  `json:"size"`
+	ParameterSize string `json:"parameter_size"`
+	Family        string `json:"family"`
+	ModifiedAt    string `json:"modified_at"`
+	Type          string `json:"type"`  // llm or embedding (guessed from name/family)
+	Added         bool   `json:"added"` // newly added to the ollama provider's model list by this call
+}
+
+// OllamaPullProgressEvent is the payload of EventOllamaPullProgress.
+type OllamaPullProgressEvent struct {
+	ModelID   string `json:"model_id"`
+	Status    string `json:"status"`
+	Total     int64  `json:"total"`
+	Completed int64  `json:"completed"`
+	Percent   int    `json:"percent"`
+	Done      bool   `json:"done"`
+	Error     string `json:"error,omitempty"`
+}
+
+// ollamaPulls tracks in-flight pulls by model ID so they can be cancelled.
+var (
+	ollamaPullsMu sync.Mutex
+	ollamaPulls   = map[string]context.CancelFunc{}
+)
+
+// ollamaBaseURL normalizes an Ollama endpoint to its native API root; an empty endpoint
+// falls back to the ollama provider's configured endpoint.
+func (s *ProvidersService) ollamaBaseURL(endpoint string) (string, error) {
+	endpoint = strings.TrimSpace(endpoint)
+	if endpoint == "" {
+		provider, err := s.GetProvider(ollamaProviderID)
+		if err != nil {
+			return "", err
+		}
+		endpoint = strings.TrimSpace(provider.APIEndpoint)
+	}
+	if endpoint == "" {
+		return "", errs.New("error.ollama_endpoint_required")
+	}
+	endpoint = strings.TrimRight(endpoint, "/")
+	// Accept the OpenAI-compatible or native API path as well as the bare host.
+	endpoint = strings.TrimSuffix(endpoint, "/v1")
+	endpoint = strings.TrimSuffix(endpoint, "/api")
+	return endpoint, nil
+}
+
+// ollamaRequestError maps connection failures to error.ollama_not_reachable.
+func ollamaRequestError(baseURL string, err error, fallbackKey string) error {
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return errs.Newf("error.ollama_not_reachable", map[string]any{"Endpoint": baseURL})
+	}
+	return errs.Wrap(fallbackKey, err)
+}
+
+// OllamaListLocalModels lists the models installed in the local Ollama and adds the ones
+// missing from the ollama provider's model list, so they can be selected without the CLI.
+func (s *ProvidersService) OllamaListLocalModels(endpoint string) ([]OllamaLocalModel, error) {
+	baseURL, err := s.ollamaBaseURL(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"/api/tags", nil)
+	if err != nil {
+		return nil, errs.Wrap("error.ollama_model_list_failed", err)
+	}
+	resp, err := httpclient.Shared().Do(req)
+	if err != nil {
+		return nil, ollamaRequestError(baseURL, err, "error.ollama_model_list_failed")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, errs.Wrap("error.ollama_model_list_failed", fmt.Errorf("status %d: %s", resp.StatusCode, strings.TrimSpace(string(b))))
+	}
+
+	var tags struct {
+		Models []struct {
+			Name       string `json:"name"`
+			Model      string `json:"model"`
+			Size       int64  `json:"size"`
+			ModifiedAt string `json:"modified_at"`
+			Details    struct {
+				Family        string   `json:"family"`
+				Families      []string `json:"families"`
+				ParameterSize string   `json:"parameter_size"`
+			} `json:"details"`
+		} `json:"models"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tags); err != nil {
+		return nil, errs.Wrap("error.ollama_model_list_failed", err)
+	}
+
+	out := make([]OllamaLocalModel, 0, len(tags.Models))
+	added := 0
+	for _, m := range tags.Models {
+		modelID := strings.TrimSpace(m.Name)
+		if modelID == "" {
+			modelID = strings.TrimSpace(m.Model)
+		}
+		if modelID == "" {
+			continue
+		}
+		lm := OllamaLocalModel{
+			ModelID:       modelID,
+			Size:          m.Size,
+			ParameterSize: m.Details.ParameterSize,
+			Family:        m.Details.Family,
+			ModifiedAt:    m.ModifiedAt,
+			Type:          ollamaModelType(modelID, append([]string{m.Details.Family}, m.Details.Families...)),
+		}
+		lm.Added = s.addOllamaModel(lm.ModelID, lm.Type)
+		if lm.Added {
+			added++
+		}
+		out = append(out, lm)
+	}
+	s.app.Logger.Info("[providers] ollama local models listed", "endpoint", baseURL, "count", len(out), "added", added)
+	return out, nil
+}
+
+// ollamaModelType guesses whether a local model is an embedding model.
+func ollamaModelType(modelID string, families []string) string {
+	if strings.Contains(strings.ToLower(modelID), "embed") {
+		return "embedding"
+	}
+	for _, f := range families {
+		if strings.Contains(strings.ToLower(f), "bert") {
+			return "embedding"
+		}
+	}
+	return "llm"
+}
+
+// addOllamaModel adds a local model to the ollama provider's model list; it reports whether a
+// model was added. Existing and invalid IDs (e.g. too long) are skipped.
+func (s *ProvidersService) addOllamaModel(modelID, modelType string) bool {
+	if _, err := s.GetModel(ollamaProviderID, modelID); err == nil {
+		return false
+	}
+	if _, err := s.CreateModel(ollamaProviderID, CreateModelInput{
+		ModelID: modelID,
+		Name:    modelID,
+		Type:    modelType,
+	}); err != nil {
+		s.app.Logger.Warn("[providers] ollama add local model skipped", "model_id", modelID, "error", err)
+		return false
+	}
+	return true
+}
+
+// OllamaPullModel starts downloading a model into the local Ollama. It returns once the pull is
+// accepted; progress, completion and failures are reported via EventOllamaPullProgress. On
+// success the model is added to the ollama provider's model list.
+func (s *ProvidersService) OllamaPullModel(endpoint, modelID string) error {
+	modelID = strings.TrimSpace(modelID)
+	if modelID == "" {
+		return errs.New("error.model_id_required")
+	}
+	baseURL, err := s.ollamaBaseURL(endpoint)
+	if err != nil {
+		return err
+	}
+
+	// Fail fast (and synchronously) when Ollama is not running.
+	pingCtx, pingCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer pingCancel()
+	req, err := http.NewRequestWithContext(pingCtx, http.MethodGet, baseURL+"/api/version", nil)
+	if err != nil {
+		return errs.Wrap("error.ollama_pull_failed", err)
+	}
+	resp, err := httpclient.Shared().Do(req)
+	if err != nil {
+		return ollamaRequestError(baseURL, err, "error.ollama_pull_failed")
+	}
+	resp.Body.Close()
+
+	ollamaPullsMu.Lock()
+	if _, ok := ollamaPulls[modelID]; ok {
+		ollamaPullsMu.Unlock()
+		return errs.Newf("error.ollama_pull_in_progress", map[string]any{"Model": modelID})
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	ollamaPulls[modelID] = cancel
+	ollamaPullsMu.Unlock()
+
+	go func() {
+		defer func() {
+			ollamaPullsMu.Lock()
+			delete(ollamaPulls, modelID)
+			ollamaPullsMu.Unlock()
+			cancel()
+		}()
+
+		if err := s.streamOllamaPull(ctx, baseURL, modelID); err != nil {
+			s.app.Logger.Warn("[providers] ollama pull failed", "model_id", modelID, "error", err)
+			s.app.Event.Emit(EventOllamaPullProgress, OllamaPullProgressEvent{
+				ModelID: modelID,
+				Status:  "error",
+				Done:    true,
+				Error:   err.Error(),
+			})
+			return
+		}
+		s.addOllamaModel(modelID, ollamaModelType(modelID, nil))
+		s.app.Logger.Info("[providers] ollama pull completed", "model_id", modelID)
+		s.app.Event.Emit(EventOllamaPullProgress, OllamaPullProgressEvent{
+			ModelID: modelID,
+			Status:  "success",
+			Percent: 100,
+			Done:    true,
+		})
+	}()
+	return nil
+}
+
+// OllamaCancelPull cancels an in-flight OllamaPullModel; a no-op when none is running.
+func (s *ProvidersService) OllamaCancelPull(modelID string) {
+	ollamaPullsMu.Lock()
+	cancel, ok := ollamaPulls[strings.TrimSpace(modelID)]
+	ollamaPullsMu.Unlock()
+	if ok {
+		cancel()
+	}
+}
+
+// streamOllamaPull posts /api/pull and forwards each NDJSON status line as a progress event.
+func (s *ProvidersService) streamOllamaPull(ctx context.Context, baseURL, modelID string) error {
+	body, _ := json.Marshal(map[string]any{"model": modelID, "stream": true})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, baseURL+"/api/pull", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := httpclient.Shared().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("status %d: %s", resp.StatusCode, strings.TrimSpace(string(b)))
+	}
+
+	var lastPercent = -1
+	var lastStatus string
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var line struct {
+			Status    string `json:"status"`
+			Total     int64  `json:"total"`
+			Completed int64  `json:"completed"`
+			Error     string `json:"error"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			continue
+		}
+		if line.Error != "" {
+			return errors.New(line.Error)
+		}
+		percent := 0
+		if line.Total > 0 {
+			percent = int(line.Completed * 100 / line.Total)
+		}
+		// Layer downloads report many times per percent; only forward visible changes.
+		if line.Status == lastStatus && percent == lastPercent {
+			continue
+		}
+		lastStatus, lastPercent = line.Status, percent
+		s.app.Event.Emit(EventOllamaPullProgress, OllamaPullProgressEvent{
+			ModelID:   modelID,
+			Status:    line.Status,
+			Total:     line.Total,
+			Completed: line.Completed,
+			Percent:   percent,
+		})
+		if line.Status == "success" {
+			return nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	if lastStatus != "success" {
+		return errors.New("pull stream ended before success")
+	}
+	return nil
+}
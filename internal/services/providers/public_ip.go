@@ -0,0 +1,72 @@
+package providers
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"net"
+	"strings"
+	"time"
+
+	"chatclaw/internal/sqlite"
+
+	"github.com/uptrace/bun"
+)
+
+// public_ip_mode values
+const (
+	PublicIPModeAuto     = "auto"     // probe the public IP endpoints (honours HTTP_PROXY / HTTPS_PROXY)
+	PublicIPModeManual   = "manual"   // use the public_ip setting
+	PublicIPModeDisabled = "disabled" // never probe; the key is generated with an empty IP
+)
+
+// resolvePublicIP returns the IP to bind into the ChatClaw API key according to public_ip_mode.
+// Settings are read from sqlite directly because this runs at startup before the settings cache
+// is initialized. An empty IP is always acceptable to the caller.
+func resolvePublicIP() (string, error) {
+	mode, manualIP := PublicIPModeAuto, ""
+	if db := sqlite.DB(); db != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		type row struct {
+			Key   string         `bun:"key"`
+			Value sql.NullString `bun:"value"`
+		}
+		rows := make([]row, 0, 2)
+		if err := db.NewSelect().
+			Table("settings").
+			Column("key", "value").
+			Where("key IN (?)", bun.In([]string{"public_ip_mode", "public_ip"})).
+			Scan(ctx, &rows); err != nil {
+			slog.Warn("[providers] read public ip settings failed", "error", err)
+		}
+		for _, r := range rows {
+			switch r.Key {
+			case "public_ip_mode":
+				if v := strings.ToLower(strings.TrimSpace(r.Value.String)); v != "" {
+					mode = v
+				}
+			case "public_ip":
+				manualIP = strings.TrimSpace(r.Value.String)
+			}
+		}
+	}
+
+	switch mode {
+	case PublicIPModeDisabled:
+		return "", nil
+	case PublicIPModeManual:
+		if manualIP == "" {
+			return "", nil
+		}
+		ip := net.ParseIP(manualIP)
+		if ip == nil {
+			slog.Warn("[providers] ignoring invalid public_ip setting", "public_ip", manualIP)
+			return "", nil
+		}
+		return ip.String(), nil
+	default:
+		return fetchPublicIP()
+	}
+}
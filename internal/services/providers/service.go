@@ -17,7 +17,9 @@ import (
 
 	"chatclaw/internal/define"
 	"chatclaw/internal/device"
+	"chatclaw/internal/eino/chatmodel"
 	"chatclaw/internal/errs"
+	"chatclaw/internal/httpclient"
 	"chatclaw/internal/services/chatwiki"
 	"chatclaw/internal/sqlite"
 
@@ -34,7 +36,8 @@ import (
 )
 
 var (
-	// Enable provider HTTP request logs (may include endpoint URLs and response previews).
+	// Enable provider HTTP request logs (endpoint URLs; response previews are only kept when
+	// log_include_content is on, and credentials are always masked by the logger).
 	// DO NOT enable in production by default.
 	debugProviders = os.Getenv("CHATCLAW_DEBUG_PROVIDERS") == "1"
 )
@@ -110,7 +113,7 @@ func generateChatClawAPIKeyInternal() (string, error) {
 		return "", errs.Wrap("error.chatclaw_generate_key_failed", err)
 	}
 
-	userIP, err := fetchPublicIP()
+	userIP, err := resolvePublicIP()
 	if err != nil {
 		// Network may be restricted (e.g., no global internet access). Do not block key generation.
 		// ChatClaw model list endpoint can be public; other endpoints may still work without IP binding.
@@ -183,7 +186,8 @@ func GetChatClawConfig() (*Provider, error) {
 	return (&ProvidersService{}).GetProvider("chatclaw")
 }
 
-// fetchPublicIP fetches the machine's public IP via api.ipify.org
+// fetchPublicIP fetches the machine's public IP via api.ipify.org and fallbacks.
+// Requests go through http.DefaultClient and so honour HTTP_PROXY / HTTPS_PROXY.
 func fetchPublicIP() (string, error) {
 	// Multiple fallbacks for restricted networks (CN-only, captive portals, etc.).
 	// We only need a best-effort IP string for telemetry/binding. If all fail, caller may proceed without IP.
@@ -301,7 +305,7 @@ func (s *ProvidersService) GetProviderWithModels(providerID string) (*ProviderWi
 	}
 	if providerID == "chatwiki" {
 		if s.app != nil {
-			if _, err := chatwiki.NewChatWikiService(s.app).GetModelCatalog(true); err != nil {
+			if _, err := chatwiki.NewChatWikiService(s.app).RefreshModelCatalogIfStale(); err != nil {
 				s.app.Logger.Error("[providers] GetProviderWithModels chatwiki refresh catalog failed", "provider_id", providerID, "error", err)
 				return nil, err
 			}
@@ -461,119 +465,142 @@ func (s *ProvidersService) syncChatClawModelsToDB(providerID string, groups []Mo
 	}
 
 	remote := s.flattenChatClawGroups(groups)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	return db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		return syncChatClawModelsTx(ctx, tx, providerID, remote)
+	})
+}
+
+// syncChatClawModelsTx replaces the cached ChatClaw models of providerID with remote. The cloud
+// models are read-only: name, type, order and the capability flags always follow the server
+// (every LLM supports tools and reasoning, since the server routes to capable models).
+func syncChatClawModelsTx(ctx context.Context, tx bun.IDB, providerID string, remote []chatClawRemoteModel) error {
 	remoteMap := make(map[string]chatClawRemoteModel, len(remote))
 	for _, r := range remote {
 		remoteMap[r.ModelID] = r
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
-	defer cancel()
+	// Load existing cached models for this provider.
+	existing := make([]modelModel, 0)
+	if err := tx.NewSelect().
+		Model(&existing).
+		Where("provider_id = ?", providerID).
+		Scan(ctx); err != nil {
+		return errs.Wrap("error.chatclaw_model_sync_failed", err)
+	}
 
-	return db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
-		// Load existing cached models for this provider.
-		existing := make([]modelModel, 0)
-		if err := tx.NewSelect().
-			Model(&existing).
+	existingMap := make(map[string]modelModel, len(existing))
+	for _, e := range existing {
+		existingMap[e.ModelID] = e
+	}
+
+	// Compute deletes (local but not in remote).
+	toDelete := make([]string, 0)
+	for modelID := range existingMap {
+		if _, ok := remoteMap[modelID]; !ok {
+			toDelete = append(toDelete, modelID)
+		}
+	}
+	for _, part := range chunkStrings(toDelete, 200) {
+		if _, err := tx.NewDelete().
+			Table("models").
 			Where("provider_id = ?", providerID).
-			Scan(ctx); err != nil {
+			Where("model_id IN (?)", bun.In(part)).
+			Exec(ctx); err != nil {
 			return errs.Wrap("error.chatclaw_model_sync_failed", err)
 		}
+	}
 
-		existingMap := make(map[string]modelModel, len(existing))
-		for _, e := range existing {
-			existingMap[e.ModelID] = e
-		}
-
-		// Compute deletes (local but not in remote).
-		toDelete := make([]string, 0)
-		for modelID := range existingMap {
-			if _, ok := remoteMap[modelID]; !ok {
-				toDelete = append(toDelete, modelID)
+	// Inserts and updates.
+	toInsert := make([]modelModel, 0)
+	for _, r := range remote {
+		if e, ok := existingMap[r.ModelID]; ok {
+			// Force readonly/cache-managed attributes.
+			needUpdate := false
+			if strings.TrimSpace(e.Name) != r.Name {
+				needUpdate = true
 			}
-		}
-		for _, part := range chunkStrings(toDelete, 200) {
-			if _, err := tx.NewDelete().
-				Table("models").
-				Where("provider_id = ?", providerID).
-				Where("model_id IN (?)", bun.In(part)).
-				Exec(ctx); err != nil {
-				return errs.Wrap("error.chatclaw_model_sync_failed", err)
+			if strings.TrimSpace(strings.ToLower(e.Type)) != r.Type {
+				needUpdate = true
 			}
-		}
-
-		// Inserts and updates.
-		toInsert := make([]modelModel, 0)
-		for _, r := range remote {
-			if e, ok := existingMap[r.ModelID]; ok {
-				// Force readonly/cache-managed attributes.
-				needUpdate := false
-				if strings.TrimSpace(e.Name) != r.Name {
-					needUpdate = true
-				}
-				if strings.TrimSpace(strings.ToLower(e.Type)) != r.Type {
-					needUpdate = true
-				}
-				if e.SortOrder != r.SortOrder {
-					needUpdate = true
-				}
-				if !e.Enabled {
-					needUpdate = true
-				}
-				if !e.IsBuiltin {
-					needUpdate = true
-				}
-				if needUpdate {
-					if _, err := tx.NewUpdate().
-						Model((*modelModel)(nil)).
-						Where("provider_id = ?", providerID).
-						Where("model_id = ?", r.ModelID).
-						Set("name = ?", r.Name).
-						Set("type = ?", r.Type).
-						Set("sort_order = ?", r.SortOrder).
-						Set("enabled = ?", true).
-						Set("is_builtin = ?", true).
-						Set("updated_at = ?", sqlite.NowUTC()).
-						Exec(ctx); err != nil {
-						return errs.Wrap("error.chatclaw_model_sync_failed", err)
-					}
-				}
-				continue
+			if e.SortOrder != r.SortOrder {
+				needUpdate = true
 			}
-
-			toInsert = append(toInsert, modelModel{
-				ProviderID: providerID,
-				ModelID:    r.ModelID,
-				Name:       r.Name,
-				Type:       r.Type,
-				IsBuiltin:  true,
-				Enabled:    true,
-				SortOrder:  r.SortOrder,
-			})
-		}
-
-		for _, part := range func(ms []modelModel, size int) [][]modelModel {
-			if size <= 0 || len(ms) == 0 {
-				return nil
+			if !e.Enabled {
+				needUpdate = true
+			}
+			if !e.IsBuiltin {
+				needUpdate = true
 			}
-			out := make([][]modelModel, 0, (len(ms)+size-1)/size)
-			for i := 0; i < len(ms); i += size {
-				j := i + size
-				if j > len(ms) {
-					j = len(ms)
+			isLLM := r.Type == "llm"
+			if e.SupportsTools != isLLM || e.SupportsReasoning != isLLM {
+				needUpdate = true
+			}
+			if needUpdate {
+				if _, err := tx.NewUpdate().
+					Model((*modelModel)(nil)).
+					Where("provider_id = ?", providerID).
+					Where("model_id = ?", r.ModelID).
+					Set("name = ?", r.Name).
+					Set("type = ?", r.Type).
+					Set("sort_order = ?", r.SortOrder).
+					Set("enabled = ?", true).
+					Set("is_builtin = ?", true).
+					Set("supports_tools = ?", isLLM).
+					Set("supports_reasoning = ?", isLLM).
+					Set("updated_at = ?", sqlite.NowUTC()).
+					Exec(ctx); err != nil {
+					return errs.Wrap("error.chatclaw_model_sync_failed", err)
 				}
-				out = append(out, ms[i:j])
 			}
-			return out
-		}(toInsert, 200) {
-			if _, err := tx.NewInsert().
-				Model(&part).
-				Exec(ctx); err != nil {
-				return errs.Wrap("error.chatclaw_model_sync_failed", err)
+			continue
+		}
+
+		toInsert = append(toInsert, modelModel{
+			ProviderID:        providerID,
+			ModelID:           r.ModelID,
+			Name:              r.Name,
+			Type:              r.Type,
+			SupportsTools:     r.Type == "llm",
+			SupportsReasoning: r.Type == "llm",
+			IsBuiltin:         true,
+			Enabled:           true,
+			SortOrder:         r.SortOrder,
+		})
+	}
+
+	for _, part := range func(ms []modelModel, size int) [][]modelModel {
+		if size <= 0 || len(ms) == 0 {
+			return nil
+		}
+		out := make([][]modelModel, 0, (len(ms)+size-1)/size)
+		for i := 0; i < len(ms); i += size {
+			j := i + size
+			if j > len(ms) {
+				j = len(ms)
 			}
+			out = append(out, ms[i:j])
+		}
+		return out
+	}(toInsert, 200) {
+		if _, err := tx.NewInsert().
+			Model(&part).
+			Exec(ctx); err != nil {
+			return errs.Wrap("error.chatclaw_model_sync_failed", err)
 		}
+	}
 
-		return nil
-	})
+	if _, err := tx.NewUpdate().
+		Model((*providerModel)(nil)).
+		Where("provider_id = ?", providerID).
+		Set("last_synced_at = ?", sqlite.NowUTC()).
+		Exec(ctx); err != nil {
+		return errs.Wrap("error.chatclaw_model_sync_failed", err)
+	}
+	return nil
 }
 
 // chatClawModelItem /custom-model/list API response item
@@ -625,7 +652,7 @@ func (s *ProvidersService) fetchChatClawModels(provider *Provider) ([]ModelGroup
 		)
 	}
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := httpclient.Shared().Do(req)
 	if err != nil {
 		if debugProviders {
 			s.app.Logger.Warn(
@@ -822,6 +849,33 @@ func (s *ProvidersService) UpdateProvider(providerID string, input UpdateProvide
 		}
 	}
 
+	var newType string
+	if input.Type != nil {
+		newType = strings.TrimSpace(*input.Type)
+		provider, err := s.GetProvider(providerID)
+		if err != nil {
+			return nil, err
+		}
+		if !isOpenAICompatibleType(provider.Type) || !isOpenAICompatibleType(newType) {
+			return nil, errs.Newf("error.provider_type_switch_invalid", map[string]any{"Type": newType})
+		}
+	}
+
+	// 校验 API 地址是否符合供应商类型（ChatClaw / ChatWiki 的地址由系统维护，不校验）
+	if input.APIEndpoint != nil && !deviceBoundProviders[providerID] {
+		endpointType := newType
+		if endpointType == "" {
+			provider, err := s.GetProvider(providerID)
+			if err != nil {
+				return nil, err
+			}
+			endpointType = provider.Type
+		}
+		if err := validateAPIEndpoint(endpointType, *input.APIEndpoint); err != nil {
+			return nil, err
+		}
+	}
+
 	// 构建更新语句
 	q := db.NewUpdate().
 		Model((*providerModel)(nil)).
@@ -840,6 +894,9 @@ func (s *ProvidersService) UpdateProvider(providerID string, input UpdateProvide
 	if input.ExtraConfig != nil {
 		q = q.Set("extra_config = ?", *input.ExtraConfig)
 	}
+	if newType != "" {
+		q = q.Set("type = ?", newType)
+	}
 
 	result, err := q.Exec(ctx)
 	if err != nil {
@@ -882,12 +939,17 @@ type CheckAPIKeyInput struct {
 	APIKey      string `json:"api_key"`
 	APIEndpoint string `json:"api_endpoint"`
 	ExtraConfig string `json:"extra_config"`
+	// EnableThinking 以思考模式构建模型并流式检测，确认思考内容能正常返回
+	EnableThinking bool `json:"enable_thinking"`
 }
 
 // CheckAPIKeyResult 检测 API Key 的结果
 type CheckAPIKeyResult struct {
 	Success bool   `json:"success"`
 	Message string `json:"message"`
+	ModelID string `json:"model_id"` // 实际用于测试的模型 ID
+	// ThinkingObserved 思考模式检测时是否收到了思考内容（reasoning_content）
+	ThinkingObserved bool `json:"thinking_observed"`
 }
 
 // CheckAPIKey 检测供应商的 API Key 是否有效
@@ -909,27 +971,69 @@ func (s *ProvidersService) CheckAPIKey(providerID string, input CheckAPIKeyInput
 		return nil, err
 	}
 
+	return s.checkProviderModel(provider, input, testModelID)
+}
+
+// CheckModel 使用指定模型检测供应商配置，用于定位供应商下哪个模型不可用
+func (s *ProvidersService) CheckModel(providerID string, modelID string, input CheckAPIKeyInput) (*CheckAPIKeyResult, error) {
+	providerID = strings.TrimSpace(providerID)
+	if providerID == "" {
+		return nil, errs.New("error.provider_id_required")
+	}
+	modelID = strings.TrimSpace(modelID)
+	if modelID == "" {
+		return nil, errs.New("error.model_id_required")
+	}
+
+	provider, err := s.GetProvider(providerID)
+	if err != nil {
+		return nil, err
+	}
+	m, err := s.GetModel(providerID, modelID)
+	if err != nil {
+		return nil, err
+	}
+	// 目前仅支持通过对话测试 LLM 模型
+	if m.Type != "llm" {
+		return nil, errs.Newf("error.model_check_not_llm", map[string]any{"ModelID": modelID})
+	}
+
+	return s.checkProviderModel(provider, input, modelID)
+}
+
+// checkProviderModel 根据供应商类型调用对应 SDK，用指定模型发送测试消息
+func (s *ProvidersService) checkProviderModel(provider *Provider, input CheckAPIKeyInput, testModelID string) (*CheckAPIKeyResult, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
+	var (
+		result *CheckAPIKeyResult
+		err    error
+	)
 	// 根据供应商类型调用不同的 SDK
 	switch provider.Type {
 	case "openai":
-		return s.checkOpenAI(ctx, input, testModelID)
+		result, err = s.checkOpenAI(ctx, input, testModelID)
+	case chatmodel.ProviderTypeOpenAIResponses:
+		result, err = s.checkOpenAIResponses(ctx, input, testModelID)
 	case "azure":
-		return s.checkAzure(ctx, input, testModelID)
+		result, err = s.checkAzure(ctx, input, testModelID)
 	case "anthropic":
-		return s.checkClaude(ctx, input, testModelID)
+		result, err = s.checkClaude(ctx, input, testModelID)
 	case "gemini":
-		return s.checkGemini(ctx, input, testModelID)
+		result, err = s.checkGemini(ctx, input, testModelID)
 	case "ollama":
 		// Ollama 本地运行，直接尝试连接检测
-		return s.checkOllama(ctx, input, testModelID)
+		result, err = s.checkOllama(ctx, input, testModelID)
 	case "qwen":
-		return s.checkQwen(ctx, input, testModelID)
+		result, err = s.checkQwen(ctx, input, testModelID)
 	default:
 		return nil, errs.Newf("error.unsupported_provider_type", map[string]any{"Type": provider.Type})
 	}
+	if result != nil {
+		result.ModelID = testModelID
+	}
+	return result, err
 }
 
 // getFirstLLMModel 获取供应商的第一个 LLM 模型
@@ -984,20 +1088,111 @@ func testChatModel(ctx context.Context, chatModel ChatModelGenerator) *CheckAPIK
 	}
 }
 
+// ChatModelStreamer 定义可流式生成消息的聊天模型接口
+type ChatModelStreamer interface {
+	ChatModelGenerator
+	Stream(ctx context.Context, messages []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error)
+}
+
+// thinkingCheckMaxChunks 思考模式检测最多读取的流式分片数
+const thinkingCheckMaxChunks = 64
+
+// checkChatModel 普通检测发送一条测试消息；开启思考模式时改为流式检测
+func checkChatModel(ctx context.Context, chatModel ChatModelStreamer, enableThinking bool) *CheckAPIKeyResult {
+	if enableThinking {
+		return testThinkingChatModel(ctx, chatModel)
+	}
+	return testChatModel(ctx, chatModel)
+}
+
+// testThinkingChatModel 流式读取少量分片：收到思考内容即判定思考模式可用，先收到正文则说明模型未返回思考内容
+func testThinkingChatModel(ctx context.Context, chatModel ChatModelStreamer) *CheckAPIKeyResult {
+	stream, err := chatModel.Stream(ctx, []*schema.Message{
+		{
+			Role:    schema.User,
+			Content: "hi",
+		},
+	})
+	if err != nil {
+		return &CheckAPIKeyResult{
+			Success: false,
+			Message: err.Error(),
+		}
+	}
+	defer stream.Close()
+
+	result := &CheckAPIKeyResult{Success: true}
+	for i := 0; i < thinkingCheckMaxChunks; i++ {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return &CheckAPIKeyResult{
+				Success: false,
+				Message: err.Error(),
+			}
+		}
+		if chunk == nil {
+			continue
+		}
+		if chunk.ReasoningContent != "" {
+			result.ThinkingObserved = true
+			break
+		}
+		if chunk.Content != "" {
+			break
+		}
+	}
+	return result
+}
+
 // checkOpenAI 使用 OpenAI SDK 检测
 func (s *ProvidersService) checkOpenAI(ctx context.Context, input CheckAPIKeyInput, modelID string) (*CheckAPIKeyResult, error) {
-	chatModel, err := openai.NewChatModel(ctx, &openai.ChatModelConfig{
-		APIKey:  input.APIKey,
-		Model:   modelID,
-		BaseURL: input.APIEndpoint,
-	})
+	config := &openai.ChatModelConfig{
+		APIKey:     input.APIKey,
+		Model:      modelID,
+		BaseURL:    input.APIEndpoint,
+		HTTPClient: httpclient.Shared(),
+	}
+	if input.EnableThinking {
+		config.ExtraFields = map[string]any{"enable_thinking": true}
+	}
+	chatModel, err := openai.NewChatModel(ctx, config)
 	if err != nil {
 		return &CheckAPIKeyResult{
 			Success: false,
 			Message: err.Error(),
 		}, nil
 	}
-	return testChatModel(ctx, chatModel), nil
+	return checkChatModel(ctx, chatModel, input.EnableThinking), nil
+}
+
+// checkOpenAIResponses checks the key against the OpenAI Responses API
+func (s *ProvidersService) checkOpenAIResponses(ctx context.Context, input CheckAPIKeyInput, modelID string) (*CheckAPIKeyResult, error) {
+	config := &chatmodel.ResponsesConfig{
+		APIKey:     input.APIKey,
+		Model:      modelID,
+		BaseURL:    input.APIEndpoint,
+		HTTPClient: httpclient.Shared(),
+	}
+	if input.EnableThinking {
+		config.ReasoningEffort = "medium"
+	}
+	chatModel, err := chatmodel.NewResponsesChatModel(ctx, config)
+	if err != nil {
+		return &CheckAPIKeyResult{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+	return checkChatModel(ctx, chatModel, input.EnableThinking), nil
+}
+
+// isOpenAICompatibleType reports whether a provider type is one of the two OpenAI API surfaces
+// a provider may switch between.
+func isOpenAICompatibleType(providerType string) bool {
+	return providerType == "openai" || providerType == chatmodel.ProviderTypeOpenAIResponses
 }
 
 // checkAzure 使用 Azure OpenAI SDK 检测
@@ -1027,20 +1222,25 @@ func (s *ProvidersService) checkAzure(ctx context.Context, input CheckAPIKeyInpu
 		}, nil
 	}
 
-	chatModel, err := openai.NewChatModel(ctx, &openai.ChatModelConfig{
+	config := &openai.ChatModelConfig{
 		APIKey:     input.APIKey,
 		Model:      modelID,
 		BaseURL:    input.APIEndpoint,
 		ByAzure:    true,
 		APIVersion: extraConfig.APIVersion,
-	})
+		HTTPClient: httpclient.Shared(),
+	}
+	if input.EnableThinking {
+		config.ExtraFields = map[string]any{"enable_thinking": true}
+	}
+	chatModel, err := openai.NewChatModel(ctx, config)
 	if err != nil {
 		return &CheckAPIKeyResult{
 			Success: false,
 			Message: err.Error(),
 		}, nil
 	}
-	return testChatModel(ctx, chatModel), nil
+	return checkChatModel(ctx, chatModel, input.EnableThinking), nil
 }
 
 // checkClaude 使用 Claude SDK 检测
@@ -1050,25 +1250,36 @@ func (s *ProvidersService) checkClaude(ctx context.Context, input CheckAPIKeyInp
 		baseURL = &input.APIEndpoint
 	}
 
-	chatModel, err := claude.NewChatModel(ctx, &claude.Config{
-		APIKey:    input.APIKey,
-		Model:     modelID,
-		BaseURL:   baseURL,
-		MaxTokens: 1000,
-	})
+	config := &claude.Config{
+		APIKey:     input.APIKey,
+		Model:      modelID,
+		BaseURL:    baseURL,
+		MaxTokens:  1000,
+		HTTPClient: httpclient.Shared(),
+	}
+	if input.EnableThinking {
+		// budget_tokens 最小为 1024 且必须小于 max_tokens
+		config.MaxTokens = 2048
+		config.Thinking = &claude.Thinking{
+			Enable:       true,
+			BudgetTokens: 1024,
+		}
+	}
+	chatModel, err := claude.NewChatModel(ctx, config)
 	if err != nil {
 		return &CheckAPIKeyResult{
 			Success: false,
 			Message: err.Error(),
 		}, nil
 	}
-	return testChatModel(ctx, chatModel), nil
+	return checkChatModel(ctx, chatModel, input.EnableThinking), nil
 }
 
 // checkGemini 使用 Gemini SDK 检测
 func (s *ProvidersService) checkGemini(ctx context.Context, input CheckAPIKeyInput, modelID string) (*CheckAPIKeyResult, error) {
 	config := &genai.ClientConfig{
-		APIKey: input.APIKey,
+		APIKey:     input.APIKey,
+		HTTPClient: httpclient.Shared(),
 	}
 	if input.APIEndpoint != "" {
 		config.HTTPOptions = genai.HTTPOptions{
@@ -1083,24 +1294,31 @@ func (s *ProvidersService) checkGemini(ctx context.Context, input CheckAPIKeyInp
 		}, nil
 	}
 
-	chatModel, err := einogemini.NewChatModel(ctx, &einogemini.Config{
+	geminiConfig := &einogemini.Config{
 		Client: client,
 		Model:  modelID,
-	})
+	}
+	if input.EnableThinking {
+		geminiConfig.ThinkingConfig = &genai.ThinkingConfig{
+			IncludeThoughts: true,
+		}
+	}
+	chatModel, err := einogemini.NewChatModel(ctx, geminiConfig)
 	if err != nil {
 		return &CheckAPIKeyResult{
 			Success: false,
 			Message: err.Error(),
 		}, nil
 	}
-	return testChatModel(ctx, chatModel), nil
+	return checkChatModel(ctx, chatModel, input.EnableThinking), nil
 }
 
 // checkOllama 使用 Ollama SDK 检测
 func (s *ProvidersService) checkOllama(ctx context.Context, input CheckAPIKeyInput, modelID string) (*CheckAPIKeyResult, error) {
 	chatModel, err := ollama.NewChatModel(ctx, &ollama.ChatModelConfig{
-		BaseURL: input.APIEndpoint,
-		Model:   modelID,
+		BaseURL:    input.APIEndpoint,
+		Model:      modelID,
+		HTTPClient: httpclient.Shared(),
 	})
 	if err != nil {
 		return &CheckAPIKeyResult{
@@ -1108,17 +1326,18 @@ func (s *ProvidersService) checkOllama(ctx context.Context, input CheckAPIKeyInp
 			Message: err.Error(),
 		}, nil
 	}
-	return testChatModel(ctx, chatModel), nil
+	return checkChatModel(ctx, chatModel, input.EnableThinking), nil
 }
 
 // checkQwen 使用 Qwen SDK 检测
 func (s *ProvidersService) checkQwen(ctx context.Context, input CheckAPIKeyInput, modelID string) (*CheckAPIKeyResult, error) {
-	disableThinking := false
+	enableThinking := input.EnableThinking
 	chatModel, err := qwen.NewChatModel(ctx, &qwen.ChatModelConfig{
 		APIKey:         input.APIKey,
 		BaseURL:        input.APIEndpoint,
 		Model:          modelID,
-		EnableThinking: &disableThinking,
+		EnableThinking: &enableThinking,
+		HTTPClient:     httpclient.Shared(),
 	})
 	if err != nil {
 		return &CheckAPIKeyResult{
@@ -1126,7 +1345,7 @@ func (s *ProvidersService) checkQwen(ctx context.Context, input CheckAPIKeyInput
 			Message: err.Error(),
 		}, nil
 	}
-	return testChatModel(ctx, chatModel), nil
+	return checkChatModel(ctx, chatModel, input.EnableThinking), nil
 }
 
 // CreateModel 创建模型
@@ -1216,15 +1435,27 @@ func (s *ProvidersService) CreateModel(providerID string, input CreateModelInput
 		capabilities = string(capabilitiesBytes)
 	}
 
+	// 能力标记：未指定时 llm 模型默认支持，其他类型不适用
+	supportsTools := input.Type == "llm"
+	if input.SupportsTools != nil {
+		supportsTools = *input.SupportsTools
+	}
+	supportsReasoning := input.Type == "llm"
+	if input.SupportsReasoning != nil {
+		supportsReasoning = *input.SupportsReasoning
+	}
+
 	m := &modelModel{
-		ProviderID:   providerID,
-		ModelID:      input.ModelID,
-		Name:         input.Name,
-		Type:         input.Type,
-		Capabilities: capabilities,
-		IsBuiltin:    false,
-		Enabled:      true,
-		SortOrder:    maxSortOrder + 1,
+		ProviderID:        providerID,
+		ModelID:           input.ModelID,
+		Name:              input.Name,
+		Type:              input.Type,
+		Capabilities:      capabilities,
+		SupportsTools:     supportsTools,
+		SupportsReasoning: supportsReasoning,
+		IsBuiltin:         false,
+		Enabled:           true,
+		SortOrder:         maxSortOrder + 1,
 	}
 
 	_, err = db.NewInsert().Model(m).Exec(ctx)
@@ -1288,13 +1519,31 @@ func (s *ProvidersService) UpdateModel(providerID string, modelID string, input
 	if input.Enabled != nil {
 		q = q.Set("enabled = ?", *input.Enabled)
 	}
-	if len(input.Capabilities) > 0 {
-		capabilitiesBytes, err := json.Marshal(input.Capabilities)
+	capabilities := input.Capabilities
+	if input.SupportsVision != nil {
+		// supports_vision 映射到 capabilities 中的 "image"
+		if len(capabilities) == 0 {
+			current, err := s.GetModel(providerID, modelID)
+			if err != nil {
+				return nil, err
+			}
+			capabilities = current.Capabilities
+		}
+		capabilities = setCapability(capabilities, "image", *input.SupportsVision)
+	}
+	if len(capabilities) > 0 {
+		capabilitiesBytes, err := json.Marshal(capabilities)
 		if err != nil {
 			return nil, errs.Wrap("error.capabilities_invalid", err)
 		}
 		q = q.Set("capabilities = ?", string(capabilitiesBytes))
 	}
+	if input.SupportsTools != nil {
+		q = q.Set("supports_tools = ?", *input.SupportsTools)
+	}
+	if input.SupportsReasoning != nil {
+		q = q.Set("supports_reasoning = ?", *input.SupportsReasoning)
+	}
 
 	result, err := q.Exec(ctx)
 	if err != nil {
@@ -1312,6 +1561,23 @@ func (s *ProvidersService) UpdateModel(providerID string, modelID string, input
 	return s.GetModel(providerID, modelID)
 }
 
+// setCapability 在 capabilities 中添加或移除指定能力（始终保留 text）
+func setCapability(capabilities []string, capability string, enabled bool) []string {
+	result := make([]string, 0, len(capabilities)+1)
+	for _, c := range capabilities {
+		if c != capability {
+			result = append(result, c)
+		}
+	}
+	if len(result) == 0 {
+		result = append(result, "text")
+	}
+	if enabled {
+		result = append(result, capability)
+	}
+	return result
+}
+
 // GetModel 获取单个模型
 func (s *ProvidersService) GetModel(providerID string, modelID string) (*Model, error) {
 	providerID = strings.TrimSpace(providerID)
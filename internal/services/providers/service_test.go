@@ -0,0 +1,88 @@
+package providers
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/sqlitedialect"
+)
+
+func newTestBunDB(t *testing.T) *bun.DB {
+	t.Helper()
+	sqlDB, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("sql.Open(): %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() {
+		_ = sqlDB.Close()
+	})
+	db := bun.NewDB(sqlDB, sqlitedialect.New())
+	for _, stmt := range []string{
+		`CREATE TABLE providers (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			provider_id TEXT NOT NULL,
+			last_synced_at DATETIME,
+			updated_at DATETIME
+		)`,
+		`CREATE TABLE models (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			created_at DATETIME NOT NULL,
+			updated_at DATETIME NOT NULL,
+			provider_id TEXT NOT NULL,
+			model_id TEXT NOT NULL,
+			name TEXT NOT NULL,
+			type TEXT NOT NULL,
+			capabilities TEXT NOT NULL DEFAULT '["text"]',
+			supports_tools BOOLEAN,
+			supports_reasoning BOOLEAN,
+			default_use_model TEXT NOT NULL DEFAULT '',
+			is_builtin BOOLEAN NOT NULL DEFAULT 0,
+			enabled BOOLEAN NOT NULL DEFAULT 1,
+			sort_order INTEGER NOT NULL DEFAULT 0
+		)`,
+		`INSERT INTO providers (provider_id) VALUES ('chatclaw')`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("setup: %v", err)
+		}
+	}
+	return db
+}
+
+func TestSyncChatClawModelsSetsLLMCapabilities(t *testing.T) {
+	db := newTestBunDB(t)
+	ctx := context.Background()
+
+	// A model cached by an earlier sync that left the flags off
+	if _, err := db.Exec(`INSERT INTO models (created_at, updated_at, provider_id, model_id, name, type, supports_tools, supports_reasoning, is_builtin, sort_order)
+		VALUES (CURRENT_TIMESTAMP, CURRENT_TIMESTAMP, 'chatclaw', 'old-llm', 'Old', 'llm', 0, 0, 1, 1)`); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+
+	remote := []chatClawRemoteModel{
+		{ModelID: "old-llm", Name: "Old", Type: "llm", SortOrder: 1},
+		{ModelID: "new-llm", Name: "New", Type: "llm", SortOrder: 2},
+		{ModelID: "embed", Name: "Embed", Type: "embedding", SortOrder: 1},
+	}
+	if err := syncChatClawModelsTx(ctx, db, "chatclaw", remote); err != nil {
+		t.Fatalf("syncChatClawModelsTx() error = %v", err)
+	}
+
+	var models []modelModel
+	if err := db.NewSelect().Model(&models).Where("provider_id = ?", "chatclaw").Scan(ctx); err != nil {
+		t.Fatalf("select: %v", err)
+	}
+	if len(models) != len(remote) {
+		t.Fatalf("got %d models, want %d", len(models), len(remote))
+	}
+	for _, m := range models {
+		want := m.Type == "llm"
+		if m.SupportsTools != want || m.SupportsReasoning != want {
+			t.Errorf("%s: supports_tools=%v supports_reasoning=%v, want %v", m.ModelID, m.SupportsTools, m.SupportsReasoning, want)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package retrieval
+
+import (
+	"container/list"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultCacheSize is the number of search results kept when retrieval_cache_size is unset.
+	DefaultCacheSize = 128
+	// cacheTTL keeps cached hits short-lived so follow-up questions benefit
+	// without serving stale chunks for long.
+	cacheTTL = 5 * time.Minute
+)
+
+// searchCache is an LRU cache of search results shared by all Service instances
+// (services are created per request, so the cache lives at package level).
+var searchCache = newResultCache(DefaultCacheSize, cacheTTL)
+
+// SetCacheSize changes the maximum number of cached searches. 0 disables caching.
+func SetCacheSize(size int) {
+	searchCache.resize(size)
+}
+
+// InvalidateLibrary drops every cached search that includes the given library.
+// Call it whenever the documents of a library change.
+func InvalidateLibrary(libraryID int64) {
+	searchCache.invalidateLibrary(libraryID)
+}
+
+// InvalidateAll clears the cache, e.g. after the embedding model changed.
+func InvalidateAll() {
+	searchCache.clear()
+}
+
+type cacheEntry struct {
+	key        string
+	libraryIDs []int64
+	results    []SearchResult
+	expiresAt  time.Time
+}
+
+type resultCache struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	order   *list.List // front = most recently used
+	entries map[string]*list.Element
+}
+
+func newResultCache(size int, ttl time.Duration) *resultCache {
+	return &resultCache{
+		size:    size,
+		ttl:     ttl,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// cacheKey builds the key from the normalized query, sorted library IDs, level, topK
+// and the score threshold.
+func cacheKey(input SearchInput) (string, []int64) {
+	ids := slices.Clone(input.LibraryIDs)
+	slices.Sort(ids)
+	ids = slices.Compact(ids)
+
+	query := strings.Join(strings.Fields(strings.ToLower(input.Query)), " ")
+	level := -1
+	if input.Level != nil {
+		level = *input.Level
+	}
+	return fmt.Sprintf("%v|%d|%d|%g|%s", ids, level, input.TopK, input.MinScore, query), ids
+}
+
+func (c *resultCache) get(key string) ([]SearchResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := el.Value.(*cacheEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.removeElement(el)
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	return slices.Clone(entry.results), true
+}
+
+func (c *resultCache) put(key string, libraryIDs []int64, results []SearchResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.size <= 0 {
+		return
+	}
+	if el, ok := c.entries[key]; ok {
+		c.removeElement(el)
+	}
+	c.entries[key] = c.order.PushFront(&cacheEntry{
+		key:        key,
+		libraryIDs: libraryIDs,
+		results:    slices.Clone(results),
+		expiresAt:  time.Now().Add(c.ttl),
+	})
+	c.evict()
+}
+
+func (c *resultCache) resize(size int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.size = max(size, 0)
+	c.evict()
+}
+
+func (c *resultCache) invalidateLibrary(libraryID int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for el := c.order.Front(); el != nil; {
+		next := el.Next()
+		if slices.Contains(el.Value.(*cacheEntry).libraryIDs, libraryID) {
+			c.removeElement(el)
+		}
+		el = next
+	}
+}
+
+func (c *resultCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.order.Init()
+	clear(c.entries)
+}
+
+// evict removes least recently used entries until the cache fits its size. Caller holds mu.
+func (c *resultCache) evict() {
+	for c.order.Len() > c.size {
+		c.removeElement(c.order.Back())
+	}
+}
+
+func (c *resultCache) removeElement(el *list.Element) {
+	c.order.Remove(el)
+	delete(c.entries, el.Value.(*cacheEntry).key)
+}
@@ -0,0 +1,101 @@
+package retrieval
+
+import (
+	"context"
+
+	"chatclaw/internal/fts/tokenizer"
+)
+
+// DebugNode is a fusion candidate with the per-search detail behind its final score.
+// A zero rank means the node was not returned by that search.
+type DebugNode struct {
+	SearchResult
+	Rank           int      `json:"rank"` // position after RRF fusion, 1-based
+	VectorRank     int      `json:"vector_rank"`
+	VectorDistance *float64 `json:"vector_distance,omitempty"` // sqlite-vec distance, lower is closer
+	FullTextRank   int      `json:"full_text_rank"`
+	BM25           *float64 `json:"bm25,omitempty"` // FTS5 bm25(), lower is better
+	Selected       bool     `json:"selected"`       // within TopK, i.e. returned by Search
+	AboveThreshold bool     `json:"above_threshold"`
+}
+
+// DebugResult is the outcome of Debug.
+type DebugResult struct {
+	MatchQuery    string      `json:"match_query"` // FTS5 MATCH expression built from the query (per library language)
+	FetchK        int         `json:"fetch_k"`     // results fetched per search before fusion
+	VectorCount   int         `json:"vector_count"`
+	FullTextCount int         `json:"full_text_count"`
+	VectorError   string      `json:"vector_error,omitempty"`
+	FullTextError string      `json:"full_text_error,omitempty"`
+	Nodes         []DebugNode `json:"nodes"`
+}
+
+// Debug runs the same searches and fusion as Search but returns every fusion candidate with its
+// vector / full-text ranks and raw scores, marking which ones Search would return. It bypasses
+// the search cache. AboveThreshold compares the fused score with MinScore; note that Search
+// itself does not filter by MinScore.
+func (s *Service) Debug(ctx context.Context, input SearchInput) (*DebugResult, error) {
+	if input.TopK <= 0 {
+		input.TopK = 10
+	}
+	k := fetchK(input.TopK)
+	result := &DebugResult{
+		MatchQuery: tokenizer.BuildMatchQuery(input.Query),
+		FetchK:     k,
+		Nodes:      []DebugNode{},
+	}
+	if len(input.LibraryIDs) == 0 || input.Query == "" {
+		return result, nil
+	}
+	if q, err := s.buildFTSQuery(ctx, input.LibraryIDs, input.Query); err == nil && q != "" {
+		result.MatchQuery = q
+	}
+
+	vecResults, ftsResults, vecErr, ftsErr := s.searchBoth(ctx, input, k)
+	result.VectorCount = len(vecResults)
+	result.FullTextCount = len(ftsResults)
+	if vecErr != nil {
+		result.VectorError = vecErr.Error()
+	}
+	if ftsErr != nil {
+		result.FullTextError = ftsErr.Error()
+	}
+
+	merged := s.rrfMerge(vecResults, ftsResults)
+	details, err := s.fetchNodeDetails(ctx, merged)
+	if err != nil {
+		return nil, err
+	}
+
+	vecByNode := make(map[int64]rankedResult, len(vecResults))
+	for _, r := range vecResults {
+		vecByNode[r.nodeID] = r
+	}
+	ftsByNode := make(map[int64]rankedResult, len(ftsResults))
+	for _, r := range ftsResults {
+		ftsByNode[r.nodeID] = r
+	}
+
+	// fetchNodeDetails keeps the merged order, so the index is the fused rank; Search cuts the
+	// merged list at TopK before fetching details, which a node deleted meanwhile can shift.
+	for i, d := range details {
+		node := DebugNode{
+			SearchResult:   d,
+			Rank:           i + 1,
+			Selected:       i < input.TopK,
+			AboveThreshold: d.Score >= input.MinScore,
+		}
+		if r, ok := vecByNode[d.NodeID]; ok {
+			distance := r.raw
+			node.VectorRank = r.rank
+			node.VectorDistance = &distance
+		}
+		if r, ok := ftsByNode[d.NodeID]; ok {
+			bm25 := r.raw
+			node.FullTextRank = r.rank
+			node.BM25 = &bm25
+		}
+		result.Nodes = append(result.Nodes, node)
+	}
+	return result, nil
+}
@@ -0,0 +1,140 @@
+package retrieval
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/cloudwego/eino/components/embedding"
+)
+
+// MemoryIndex is an ephemeral vector index over ad-hoc documents that are not part of any
+// library (reference files attached to a single message). It lives only in memory and is
+// searched by cosine similarity; nothing is written to doc_vec or the FTS tables.
+//
+// Node and document IDs are negative so they never collide with stored nodes.
+type MemoryIndex struct {
+	embedder embedding.Embedder
+
+	mu     sync.RWMutex
+	chunks []memoryChunk
+	docs   int
+	closed bool
+}
+
+type memoryChunk struct {
+	docID   int64
+	docName string
+	content string
+	vector  []float64
+	norm    float64
+}
+
+// NewMemoryIndex creates an empty index; embedder embeds the search queries and must be the
+// model the added vectors came from.
+func NewMemoryIndex(embedder embedding.Embedder) *MemoryIndex {
+	return &MemoryIndex{embedder: embedder}
+}
+
+// Add adds one document's chunks with their vectors (same order and length).
+func (m *MemoryIndex) Add(name string, chunks []string, vectors [][]float64) error {
+	if len(chunks) != len(vectors) {
+		return fmt.Errorf("memory index: %d chunks but %d vectors", len(chunks), len(vectors))
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return fmt.Errorf("memory index: closed")
+	}
+	m.docs++
+	docID := -int64(m.docs)
+	for i, content := range chunks {
+		if norm := vectorNorm(vectors[i]); norm > 0 {
+			m.chunks = append(m.chunks, memoryChunk{docID: docID, docName: name, content: content, vector: vectors[i], norm: norm})
+		}
+	}
+	return nil
+}
+
+// Len returns the number of indexed chunks.
+func (m *MemoryIndex) Len() int {
+	if m == nil {
+		return 0
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.chunks)
+}
+
+// Close drops the indexed vectors; later searches return no results.
+func (m *MemoryIndex) Close() {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.chunks = nil
+	m.closed = true
+}
+
+// Search returns the TopK chunks most similar to the query. LibraryIDs is ignored; all chunks
+// are level 0, so a Level other than 0 yields no results. Score is the cosine similarity.
+// Like Service.Search, MinScore is not applied.
+func (m *MemoryIndex) Search(ctx context.Context, input SearchInput) ([]SearchResult, error) {
+	if input.Query == "" || m.Len() == 0 {
+		return nil, nil
+	}
+	if input.Level != nil && *input.Level != 0 {
+		return nil, nil
+	}
+	if input.TopK <= 0 {
+		input.TopK = 10
+	}
+
+	vectors, err := m.embedder.EmbedStrings(ctx, []string{input.Query})
+	if err != nil {
+		return nil, fmt.Errorf("embed query: %w", err)
+	}
+	if len(vectors) == 0 || vectorNorm(vectors[0]) == 0 {
+		return nil, fmt.Errorf("embed query: empty vector")
+	}
+	query := vectors[0]
+	queryNorm := vectorNorm(query)
+
+	m.mu.RLock()
+	results := make([]SearchResult, 0, len(m.chunks))
+	for i, c := range m.chunks {
+		if len(c.vector) != len(query) {
+			continue
+		}
+		var dot float64
+		for j, v := range c.vector {
+			dot += v * query[j]
+		}
+		results = append(results, SearchResult{
+			NodeID:       -int64(i + 1),
+			DocumentID:   c.docID,
+			DocumentName: c.docName,
+			Content:      c.content,
+			Score:        dot / (c.norm * queryNorm),
+		})
+	}
+	m.mu.RUnlock()
+
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+	if len(results) > input.TopK {
+		results = results[:input.TopK]
+	}
+	return results, nil
+}
+
+func vectorNorm(v []float64) float64 {
+	var sum float64
+	for _, x := range v {
+		sum += x * x
+	}
+	return math.Sqrt(sum)
+}
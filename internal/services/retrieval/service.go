@@ -44,6 +44,7 @@ type rankedResult struct {
 	nodeID int64
 	rank   int
 	score  float64
+	raw    float64 // vector distance or bm25 score of the source search, kept for Debug
 }
 
 // Service provides document retrieval capabilities
@@ -72,26 +73,64 @@ func (s *Service) Search(ctx context.Context, input SearchInput) ([]SearchResult
 		input.TopK = 10
 	}
 
-	// Fetch more results than needed for better RRF fusion
-	fetchK := max(input.TopK*3, 30)
+	key, cacheLibraryIDs := cacheKey(input)
+	if cached, ok := searchCache.get(key); ok {
+		return cached, nil
+	}
+
+	vecResults, ftsResults, vecErr, ftsErr := s.searchBoth(ctx, input, fetchK(input.TopK))
+
+	// RRF fusion
+	merged := s.rrfMerge(vecResults, ftsResults)
+
+	// Limit to topK
+	if len(merged) > input.TopK {
+		merged = merged[:input.TopK]
+	}
+
+	// Results of a partially failed search are not cached so the next call retries.
+	cacheable := vecErr == nil && ftsErr == nil
 
+	if len(merged) == 0 {
+		if cacheable {
+			searchCache.put(key, cacheLibraryIDs, nil)
+		}
+		return nil, nil
+	}
+
+	// Fetch full node details
+	results, err := s.fetchNodeDetails(ctx, merged)
+	if err != nil {
+		return nil, err
+	}
+	if cacheable {
+		searchCache.put(key, cacheLibraryIDs, results)
+	}
+	return results, nil
+}
+
+// fetchK is how many results each search fetches: more than topK for better RRF fusion.
+func fetchK(topK int) int {
+	return max(topK*3, 30)
+}
+
+// searchBoth runs the vector and full-text searches in parallel. A failed search is logged
+// and contributes no results; its error is returned so callers can tell partial results apart.
+func (s *Service) searchBoth(ctx context.Context, input SearchInput, k int) (vecResults, ftsResults []rankedResult, vecErr, ftsErr error) {
 	var wg sync.WaitGroup
-	var vecResults []rankedResult
-	var ftsResults []rankedResult
-	var vecErr, ftsErr error
 
 	// Parallel: vector search
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		vecResults, vecErr = s.vectorSearch(ctx, input.LibraryIDs, input.Query, input.Level, fetchK)
+		vecResults, vecErr = s.vectorSearch(ctx, input.LibraryIDs, input.Query, input.Level, k)
 	}()
 
 	// Parallel: full-text search
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		ftsResults, ftsErr = s.fullTextSearch(ctx, input.LibraryIDs, input.Query, input.Level, fetchK)
+		ftsResults, ftsErr = s.fullTextSearch(ctx, input.LibraryIDs, input.Query, input.Level, k)
 	}()
 
 	wg.Wait()
@@ -102,21 +141,7 @@ func (s *Service) Search(ctx context.Context, input SearchInput) ([]SearchResult
 	if ftsErr != nil {
 		slog.Warn("[retrieval] full-text search error", "error", ftsErr)
 	}
-
-	// RRF fusion
-	merged := s.rrfMerge(vecResults, ftsResults)
-
-	// Limit to topK
-	if len(merged) > input.TopK {
-		merged = merged[:input.TopK]
-	}
-
-	if len(merged) == 0 {
-		return nil, nil
-	}
-
-	// Fetch full node details
-	return s.fetchNodeDetails(ctx, merged)
+	return vecResults, ftsResults, vecErr, ftsErr
 }
 
 // vectorSearch performs KNN search using sqlite-vec
@@ -176,6 +201,7 @@ func (s *Service) vectorSearch(ctx context.Context, libraryIDs []int64, query st
 		results[i] = rankedResult{
 			nodeID: row.ID,
 			rank:   i + 1,
+			raw:    row.Distance,
 		}
 	}
 
@@ -184,28 +210,13 @@ func (s *Service) vectorSearch(ctx context.Context, libraryIDs []int64, query st
 
 // fullTextSearch performs FTS5 search on doc_fts
 func (s *Service) fullTextSearch(ctx context.Context, libraryIDs []int64, query string, level *int, topK int) ([]rankedResult, error) {
-	// Build FTS5 match query
-	matchQuery := tokenizer.BuildMatchQuery(query)
-	if matchQuery == "" {
-		return nil, nil
-	}
-
-	// Build library_id filter for FTS5
-	// Format: library_id:1 OR library_id:2 OR ...
-	// Validate library IDs to ensure they are positive
-	var libParts []string
-	for _, id := range libraryIDs {
-		if id > 0 {
-			libParts = append(libParts, fmt.Sprintf("library_id:%d", id))
-		}
+	ftsQuery, err := s.buildFTSQuery(ctx, libraryIDs, query)
+	if err != nil {
+		return nil, err
 	}
-	if len(libParts) == 0 {
+	if ftsQuery == "" {
 		return nil, nil
 	}
-	libFilter := strings.Join(libParts, " OR ")
-
-	// Combine with match query
-	ftsQuery := fmt.Sprintf("(%s) AND (%s)", matchQuery, libFilter)
 
 	// Add level filter if specified
 	if level != nil {
@@ -235,12 +246,72 @@ func (s *Service) fullTextSearch(ctx context.Context, libraryIDs []int64, query
 		results[i] = rankedResult{
 			nodeID: row.RowID,
 			rank:   i + 1,
+			raw:    row.Score,
 		}
 	}
 
 	return results, nil
 }
 
+// buildFTSQuery builds the doc_fts MATCH expression for query restricted to libraryIDs. The query
+// is segmented per library language (see tokenizer.ResolveLanguage) so it matches how each
+// library was indexed; libraries sharing a language share one clause:
+//
+//	((match_zh) AND (library_id:1 OR library_id:2)) OR ((match_en) AND (library_id:3))
+func (s *Service) buildFTSQuery(ctx context.Context, libraryIDs []int64, query string) (string, error) {
+	// Validate library IDs to ensure they are positive
+	ids := make([]int64, 0, len(libraryIDs))
+	for _, id := range libraryIDs {
+		if id > 0 {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return "", nil
+	}
+
+	type libraryRow struct {
+		ID       int64  `bun:"id"`
+		Language string `bun:"language"`
+	}
+	var rows []libraryRow
+	if err := s.db.NewSelect().
+		TableExpr("library").
+		Column("id", "language").
+		Where("id IN (?)", bun.In(ids)).
+		Scan(ctx, &rows); err != nil {
+		return "", fmt.Errorf("load library languages: %w", err)
+	}
+	hints := make(map[int64]string, len(rows))
+	for _, r := range rows {
+		hints[r.ID] = r.Language
+	}
+
+	// Group library_id filters by resolved language, keeping the input order
+	var langs []string
+	libParts := make(map[string][]string)
+	for _, id := range ids {
+		lang := tokenizer.ResolveLanguage(hints[id])
+		if _, ok := libParts[lang]; !ok {
+			langs = append(langs, lang)
+		}
+		libParts[lang] = append(libParts[lang], fmt.Sprintf("library_id:%d", id))
+	}
+
+	var clauses []string
+	for _, lang := range langs {
+		matchQuery := tokenizer.BuildMatchQueryFor(query, lang)
+		if matchQuery == "" {
+			continue
+		}
+		clauses = append(clauses, fmt.Sprintf("(%s) AND (%s)", matchQuery, strings.Join(libParts[lang], " OR ")))
+	}
+	if len(clauses) <= 1 {
+		return strings.Join(clauses, ""), nil
+	}
+	return "(" + strings.Join(clauses, ") OR (") + ")", nil
+}
+
 // rrfMerge combines results using Reciprocal Rank Fusion
 func (s *Service) rrfMerge(vecResults, ftsResults []rankedResult) []rankedResult {
 	scores := make(map[int64]float64)
@@ -264,8 +335,12 @@ func (s *Service) rrfMerge(vecResults, ftsResults []rankedResult) []rankedResult
 		})
 	}
 
+	// Ties are broken by node ID so Search and Debug order them the same way
 	sort.Slice(merged, func(i, j int) bool {
-		return merged[i].score > merged[j].score
+		if merged[i].score != merged[j].score {
+			return merged[i].score > merged[j].score
+		}
+		return merged[i].nodeID < merged[j].nodeID
 	})
 
 	return merged
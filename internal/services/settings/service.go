@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
 	"os"
 	"path/filepath"
 	"sort"
@@ -17,7 +18,13 @@ import (
 	"chatclaw/internal/define"
 	"chatclaw/internal/eino/processor"
 	"chatclaw/internal/errs"
+	"chatclaw/internal/fts/tokenizer"
+	"chatclaw/internal/hotkey"
+	"chatclaw/internal/httpclient"
+	"chatclaw/internal/logger"
+	"chatclaw/internal/services/browser"
 	"chatclaw/internal/services/document"
+	"chatclaw/internal/services/retrieval"
 	"chatclaw/internal/sqlite"
 	"chatclaw/internal/taskmanager"
 
@@ -118,11 +125,101 @@ func (s *SettingsService) Get(key string) (*Setting, error) {
 	return out, nil
 }
 
+// floatingBallTuningRanges 悬浮球贴边/回缩参数的取值范围，与 floatingball/tuning.go 一致
+var floatingBallTuningRanges = map[string][2]int{
+	"floating_ball_edge_snap_gap":      {0, 200},
+	"floating_ball_rehide_delay_ms":    {0, 10000},
+	"floating_ball_idle_dock_delay_ms": {1000, 600000},
+}
+
 func (s *SettingsService) SetValue(key string, value string) (*Setting, error) {
 	key = strings.TrimSpace(key)
 	if key == "" {
 		return nil, errs.New("error.setting_key_required")
 	}
+	switch key {
+	case "log_level":
+		if _, ok := logger.ParseLevel(value); !ok {
+			return nil, errs.Newf("error.setting_log_level_invalid", map[string]any{"Level": value})
+		}
+	case "public_ip_mode":
+		switch value = strings.ToLower(strings.TrimSpace(value)); value {
+		case "auto", "manual", "disabled":
+		default:
+			return nil, errs.Newf("error.setting_public_ip_mode_invalid", map[string]any{"Mode": value})
+		}
+	case "public_ip":
+		if value = strings.TrimSpace(value); value != "" && net.ParseIP(value) == nil {
+			return nil, errs.Newf("error.setting_public_ip_invalid", map[string]any{"IP": value})
+		}
+	case sqlite.JournalModeSettingKey, sqlite.SynchronousSettingKey, sqlite.BusyTimeoutSettingKey:
+		// 连接参数在启动打开数据库时读取，修改后重启生效
+		v, ok := sqlite.NormalizePragmaSetting(key, value)
+		if !ok {
+			return nil, errs.Newf("error.setting_sqlite_pragma_invalid", map[string]any{"Key": key, "Value": value})
+		}
+		value = v
+	case tokenizer.LanguageSettingKey:
+		// 只影响之后写入的分词结果；已有文档需调用 RetokenizeDocuments 重新分词
+		v, ok := tokenizer.NormalizeLanguage(value)
+		if !ok {
+			return nil, errs.Newf("error.setting_fts_language_invalid", map[string]any{"Language": value})
+		}
+		value = v
+	case "conversation_keep_recent":
+		// 0 表示不自动归档
+		n, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || n < 0 {
+			return nil, errs.Newf("error.setting_conversation_keep_recent_invalid", map[string]any{"Value": value})
+		}
+		value = strconv.Itoa(n)
+	case "floating_ball_edge_snap_gap", "floating_ball_rehide_delay_ms", "floating_ball_idle_dock_delay_ms":
+		// 悬浮球每次使用时读取，立即生效
+		bounds := floatingBallTuningRanges[key]
+		n, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || n < bounds[0] || n > bounds[1] {
+			return nil, errs.Newf("error.setting_out_of_range", map[string]any{"Key": key, "Value": value, "Min": bounds[0], "Max": bounds[1]})
+		}
+		value = strconv.Itoa(n)
+	case "tray_recent_conversations":
+		// 0 表示托盘菜单不展示最近会话；上限与 tray.MaxRecentItems 一致，修改后由前端调用 TrayService.RefreshMenu
+		n, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || n < 0 || n > 20 {
+			return nil, errs.Newf("error.setting_out_of_range", map[string]any{"Key": key, "Value": value, "Min": 0, "Max": 20})
+		}
+		value = strconv.Itoa(n)
+	case "selection_popup_timeout_ms":
+		// 0 表示不自动隐藏；划词弹窗每次显示时读取，上限与 textselection.MaxPopupTimeoutMs 一致
+		n, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || n < 0 || n > 600000 {
+			return nil, errs.Newf("error.setting_out_of_range", map[string]any{"Key": key, "Value": value, "Min": 0, "Max": 600000})
+		}
+		value = strconv.Itoa(n)
+	case "floating_ball_open_gesture":
+		// 悬浮球点击时读取，立即生效
+		switch value = strings.ToLower(strings.TrimSpace(value)); value {
+		case "single", "double":
+		default:
+			return nil, errs.Newf("error.setting_floating_ball_open_gesture_invalid", map[string]any{"Value": value})
+		}
+	case "floating_ball_pin_corner":
+		// 空值表示不固定；取值与 floatingball/pin.go 一致
+		switch value = strings.ToLower(strings.TrimSpace(value)); value {
+		case "", "top-left", "top-right", "bottom-left", "bottom-right":
+		default:
+			return nil, errs.Newf("error.setting_floating_ball_pin_corner_invalid", map[string]any{"Value": value})
+		}
+	case "floating_ball_hotkey":
+		// 写入前重新注册全局快捷键（空值表示不启用），冲突或注册失败时不保存
+		v, err := updateHotkey(key, value)
+		if err != nil {
+			return nil, err
+		}
+		value = v
+	case document.DocumentsDirSettingKey:
+		// 只改设置会让已有文档的 local_path 失效，必须通过 MoveDocumentsDir 连同文件一起迁移
+		return nil, errs.New("error.setting_documents_dir_move_required")
+	}
 
 	// 写入：先写 DB，再更新缓存
 	db, err := dbForWrite()
@@ -168,9 +265,96 @@ func (s *SettingsService) SetValue(key string, value string) (*Setting, error) {
 	}
 
 	setCachedValue(key, value)
+	switch key {
+	case "retrieval_cache_size":
+		retrieval.SetCacheSize(GetInt(key, retrieval.DefaultCacheSize))
+	case "log_level":
+		logger.SetLevel(value)
+	case "log_to_file":
+		logger.SetFileEnabled(GetBool(key, true))
+	case "log_include_content":
+		logger.SetIncludeContent(GetBool(key, false))
+	case "http_max_idle_conns", "http_max_idle_conns_per_host", "http_idle_conn_timeout":
+		ApplyHTTPPoolSettings()
+	case "debug_llm_requests":
+		httpclient.SetAuditEnabled(GetBool(key, false))
+	case tokenizer.LanguageSettingKey:
+		tokenizer.SetLanguage(value)
+	case tokenizer.StopwordsSettingKey, tokenizer.SynonymsSettingKey:
+		ApplySearchQueryRules()
+	}
 	return s.Get(key)
 }
 
+// ApplyHTTPPoolSettings 将缓存中的连接池设置应用到模型供应商共享的 HTTP 连接池
+func ApplyHTTPPoolSettings() {
+	httpclient.SetPoolConfig(httpclient.PoolConfig{
+		MaxIdleConns:        GetInt("http_max_idle_conns", httpclient.DefaultMaxIdleConns),
+		MaxIdleConnsPerHost: GetInt("http_max_idle_conns_per_host", httpclient.DefaultMaxIdleConnsPerHost),
+		IdleConnTimeout:     time.Duration(GetInt("http_idle_conn_timeout", int(httpclient.DefaultIdleConnTimeout/time.Second))) * time.Second,
+	})
+}
+
+// ApplySearchQueryRules 将缓存中的自定义停用词与同义词应用到全文检索的查询构建
+func ApplySearchQueryRules() {
+	stopwords, _ := GetValue(tokenizer.StopwordsSettingKey)
+	synonyms, _ := GetValue(tokenizer.SynonymsSettingKey)
+	tokenizer.SetQueryRules(stopwords, synonyms)
+}
+
+// updateHotkey 将 key 对应的全局快捷键改绑为 value（动作由使用方通过 hotkey.Set 注册），
+// 返回规范化后的写法。与其他快捷键或划词取词用的复制快捷键冲突、平台不支持、被其他应用占用时返回错误。
+func updateHotkey(key, value string) (string, error) {
+	v, err := hotkey.Check(key, value)
+	if err == nil {
+		err = hotkey.Update(key, v)
+	}
+	var conflict *hotkey.ConflictError
+	switch {
+	case err == nil:
+		return v, nil
+	case errors.Is(err, hotkey.ErrInvalid):
+		return "", errs.Newf("error.setting_hotkey_invalid", map[string]any{"Value": value})
+	case errors.As(err, &conflict) && conflict.Owner == "enable_selection_search": // textselection 以其设置 key 预留复制快捷键
+		return "", errs.Newf("error.setting_hotkey_text_selection_conflict", map[string]any{"Value": conflict.Accelerator})
+	case errors.As(err, &conflict):
+		return "", errs.Newf("error.setting_hotkey_conflict", map[string]any{"Value": conflict.Accelerator, "Key": conflict.Owner})
+	case errors.Is(err, hotkey.ErrUnsupported):
+		return "", errs.New("error.setting_hotkey_unsupported")
+	default:
+		return "", errs.Wrapf("error.setting_hotkey_register_failed", err, map[string]any{"Value": v})
+	}
+}
+
+// GetSearchStopwords 返回全文检索的自定义停用词
+func (s *SettingsService) GetSearchStopwords() []string {
+	v, _ := GetValue(tokenizer.StopwordsSettingKey)
+	return tokenizer.ParseStopwords(v)
+}
+
+// SetSearchStopwords 替换全文检索的自定义停用词（查询时从关键词中去除）
+func (s *SettingsService) SetSearchStopwords(words []string) ([]string, error) {
+	if _, err := s.SetValue(tokenizer.StopwordsSettingKey, tokenizer.FormatStopwords(words)); err != nil {
+		return nil, err
+	}
+	return s.GetSearchStopwords(), nil
+}
+
+// GetSearchSynonyms 返回全文检索的同义词组
+func (s *SettingsService) GetSearchSynonyms() [][]string {
+	v, _ := GetValue(tokenizer.SynonymsSettingKey)
+	return tokenizer.ParseSynonyms(v)
+}
+
+// SetSearchSynonyms 替换全文检索的同义词组：查询命中组内任一词时，按整组扩展为 OR 条件。
+// 少于两个词的组会被忽略。
+func (s *SettingsService) SetSearchSynonyms(groups [][]string) ([][]string, error) {
+	if _, err := s.SetValue(tokenizer.SynonymsSettingKey, tokenizer.FormatSynonyms(groups)); err != nil {
+		return nil, err
+	}
+	return s.GetSearchSynonyms(), nil
+}
+
 // inferCategoryFromKey determines the category based on the key prefix
 func inferCategoryFromKey(key string) Category {
 	if strings.HasPrefix(key, "snap_") {
@@ -296,6 +480,61 @@ func (s *SettingsService) GetMCPDir() (string, error) {
 	return dir, nil
 }
 
+// GetDocumentsDir returns the directory where knowledge base documents are stored.
+func (s *SettingsService) GetDocumentsDir() (string, error) {
+	db, err := s.db()
+	if err != nil {
+		return "", err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	dir, release, err := document.AcquireDocumentsDir(ctx, db)
+	if err != nil {
+		return "", err
+	}
+	release()
+	return dir, nil
+}
+
+// MoveDocumentsDir moves all stored documents to newPath and makes it the documents directory.
+// Files are moved first; local_path and the documents_dir setting are then rewritten in one
+// transaction, and the files are moved back if that transaction fails.
+func (s *SettingsService) MoveDocumentsDir(newPath string) (*Setting, error) {
+	db, err := s.db()
+	if err != nil {
+		return nil, err
+	}
+	// Copying across drives can take a while for large libraries.
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	dir, err := document.MoveDocumentsDir(ctx, db, newPath)
+	if err != nil {
+		return nil, err
+	}
+	setCachedValue(document.DocumentsDirSettingKey, dir)
+	s.app.Logger.Info("documents directory moved", "dir", dir)
+	return s.Get(document.DocumentsDirSettingKey)
+}
+
+// GetLogPath returns the path of the current log file ($HOME/.chatclaw/native/logs/app.log).
+func (s *SettingsService) GetLogPath() (string, error) {
+	path, err := logger.FilePath()
+	if err != nil {
+		return "", errs.Wrap("error.setting_read_failed", err)
+	}
+	return path, nil
+}
+
+// OpenLogFolder opens the log directory in the system file manager so users can attach logs to bug reports.
+func (s *SettingsService) OpenLogFolder() error {
+	dir, err := logger.Dir()
+	if err != nil {
+		return errs.Wrap("error.setting_read_failed", err)
+	}
+	return browser.NewBrowserService(s.app).OpenDirectory(dir)
+}
+
 // toNullString converts a string to sql.NullString
 func toNullString(s string) sql.NullString {
 	if s == "" {
@@ -377,6 +616,9 @@ func (s *SettingsService) UpdateEmbeddingConfig(input UpdateEmbeddingConfigInput
 		return nil
 	}
 
+	// Cached searches were embedded with the previous model.
+	retrieval.InvalidateAll()
+
 	// Fire-and-forget: rebuild vector table and submit re-embedding tasks.
 	go s.triggerReembedAllDocuments(input.Dimension)
 	return nil
@@ -0,0 +1,67 @@
+package textselection
+
+import (
+	"time"
+
+	"chatclaw/internal/safego"
+	"chatclaw/internal/services/settings"
+)
+
+const (
+	// SettingKeyPopupTimeoutMs is the settings key for auto-hiding the popup after this many
+	// milliseconds without interaction. 0 disables auto-hide.
+	SettingKeyPopupTimeoutMs = "selection_popup_timeout_ms"
+	// MaxPopupTimeoutMs is the upper bound accepted for SettingKeyPopupTimeoutMs.
+	MaxPopupTimeoutMs = 600000
+)
+
+// armHideTimerLocked (re)starts the auto-hide timer for the shown popup. It does nothing when
+// auto-hide is disabled or the pointer is over the popup. Caller must hold s.mu.
+func (s *TextSelectionService) armHideTimerLocked() {
+	if s.hideTimer != nil {
+		s.hideTimer.Stop()
+		s.hideTimer = nil
+	}
+	s.hideTimerSeq++
+	if !s.popupActive || s.popupHovered {
+		return
+	}
+	ms := settings.GetInt(SettingKeyPopupTimeoutMs, 0)
+	if ms <= 0 {
+		return
+	}
+	if ms > MaxPopupTimeoutMs {
+		ms = MaxPopupTimeoutMs
+	}
+	seq := s.hideTimerSeq
+	s.hideTimer = time.AfterFunc(time.Duration(ms)*time.Millisecond, safego.Func("textselection.hide_timer", func() {
+		s.autoHide(seq)
+	}, s.resetPopupAfterPanic))
+}
+
+// autoHide hides the popup when the timer armed with seq is still the current one. The popup
+// window is hidden on the main thread through the usual hide event.
+func (s *TextSelectionService) autoHide(seq uint64) {
+	s.mu.Lock()
+	if seq != s.hideTimerSeq || !s.popupActive || s.popupHovered {
+		s.mu.Unlock()
+		return
+	}
+	s.hideTimer = nil
+	app := s.app
+	s.mu.Unlock()
+
+	if app != nil {
+		app.Event.Emit("text-selection:hide", nil)
+	}
+}
+
+// setPopupHovered pauses auto-hide while the pointer is over the popup and restarts the full
+// delay when it leaves.
+func (s *TextSelectionService) setPopupHovered(hovered bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.popupHovered = hovered
+	s.armHideTimerLocked()
+}
@@ -9,6 +9,8 @@ import (
 	"syscall"
 	"time"
 	"unsafe"
+
+	"chatclaw/internal/safego"
 )
 
 // MouseHookWatcher uses global mouse hook to detect text selection.
@@ -264,6 +266,7 @@ func lowLevelMouseProc(nCode int32, wParam uintptr, lParam uintptr) uintptr {
 						w.mu.Unlock()
 						// Delay processing to let system complete selection
 						go func() {
+							defer safego.Recover("textselection.handle_selection", nil)
 							time.Sleep(120 * time.Millisecond)
 							w.handlePossibleSelection(mouseX, mouseY)
 						}()
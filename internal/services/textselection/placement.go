@@ -0,0 +1,71 @@
+package textselection
+
+// WorkArea represents a screen's work area (excluding taskbar)
+type WorkArea struct {
+	X      int
+	Y      int
+	Width  int
+	Height int
+}
+
+// contains reports whether the point lies inside the area.
+func (a WorkArea) contains(x, y int) bool {
+	return x >= a.X && x < a.X+a.Width && y >= a.Y && y < a.Y+a.Height
+}
+
+// popupMonitor describes the monitor the popup is placed on. Bounds and Work are in
+// physical (virtual-screen) pixels; Scale is the monitor's own DPI scale.
+type popupMonitor struct {
+	ID     uintptr // HMONITOR on Windows, 0 when unknown
+	Bounds WorkArea
+	Work   WorkArea
+	Scale  float64
+}
+
+const (
+	// popupOffsetAbove is the gap (DIP) between the cursor and the popup shown above it.
+	popupOffsetAbove = 10
+	// popupOffsetBelow is the gap (DIP) used when there is no room above the cursor.
+	popupOffsetBelow = 20
+)
+
+// placePopupOnMonitor converts the popup size (DIP) to physical pixels with the monitor's
+// DPI scale, centers it above the anchor point and clamps it to the monitor's work area.
+// All returned values are physical pixels.
+func placePopupOnMonitor(anchorX, anchorY, popW, popH int, mon popupMonitor) (x, y, physW, physH int) {
+	scale := mon.Scale
+	if scale <= 0 {
+		scale = 1
+	}
+	physW = int(float64(popW) * scale)
+	physH = int(float64(popH) * scale)
+
+	x = anchorX - physW/2
+	y = anchorY - physH - int(popupOffsetAbove*scale)
+	x, y = clampToArea(x, y, physW, physH, anchorY+int(popupOffsetBelow*scale), mon.Work)
+	return x, y, physW, physH
+}
+
+// clampToArea keeps the popup rect inside wa. When the popup does not fit above the cursor it
+// moves to belowY. The left/top edges win over the right/bottom ones, so a popup larger than
+// the area still starts on this monitor instead of spilling onto its left/upper neighbour.
+func clampToArea(popX, popY, popWidth, popHeight, belowY int, wa WorkArea) (int, int) {
+	if popX+popWidth > wa.X+wa.Width {
+		popX = wa.X + wa.Width - popWidth
+	}
+	if popX < wa.X {
+		popX = wa.X
+	}
+
+	// Above the work area top: show below the cursor instead
+	if popY < wa.Y {
+		popY = belowY
+	}
+	if popY+popHeight > wa.Y+wa.Height {
+		popY = wa.Y + wa.Height - popHeight
+	}
+	if popY < wa.Y {
+		popY = wa.Y
+	}
+	return popX, popY
+}
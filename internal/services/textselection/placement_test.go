@@ -0,0 +1,63 @@
+package textselection
+
+import "testing"
+
+// Mixed-DPI layout: a 100% primary at the origin and a 150% monitor to its left, offset upwards.
+var (
+	testPrimary = popupMonitor{
+		ID:     1,
+		Bounds: WorkArea{X: 0, Y: 0, Width: 1920, Height: 1080},
+		Work:   WorkArea{X: 0, Y: 0, Width: 1920, Height: 1040},
+		Scale:  1,
+	}
+	testLeftHiDPI = popupMonitor{
+		ID:     2,
+		Bounds: WorkArea{X: -2560, Y: -200, Width: 2560, Height: 1440},
+		Work:   WorkArea{X: -2560, Y: -200, Width: 2560, Height: 1400},
+		Scale:  1.5,
+	}
+)
+
+func TestPlacePopupOnMonitor(t *testing.T) {
+	cases := []struct {
+		name         string
+		anchorX      int
+		anchorY      int
+		mon          popupMonitor
+		wantX, wantY int
+		wantW, wantH int
+	}{
+		{"centered above cursor", 960, 500, testPrimary, 890, 440, 140, 50},
+		{"hidpi size and offset", -1280, 500, testLeftHiDPI, -1385, 410, 210, 75},
+		{"right edge of left monitor stays off primary", -10, 300, testLeftHiDPI, -210, 210, 210, 75},
+		{"left edge of primary stays off left monitor", 2, 300, testPrimary, 0, 240, 140, 50},
+		{"top of primary flips below cursor", 500, 5, testPrimary, 430, 25, 140, 50},
+		{"top of offset monitor flips below cursor", -1280, -190, testLeftHiDPI, -1385, -160, 210, 75},
+		{"bottom of work area", 960, 1039, testPrimary, 890, 979, 140, 50},
+	}
+	for _, c := range cases {
+		x, y, w, h := placePopupOnMonitor(c.anchorX, c.anchorY, 140, 50, c.mon)
+		if x != c.wantX || y != c.wantY || w != c.wantW || h != c.wantH {
+			t.Errorf("%s: got (%d,%d %dx%d), want (%d,%d %dx%d)", c.name, x, y, w, h, c.wantX, c.wantY, c.wantW, c.wantH)
+		}
+		if !c.mon.Work.contains(x, y) || !c.mon.Work.contains(x+w-1, y+h-1) {
+			t.Errorf("%s: popup (%d,%d %dx%d) leaves monitor %d", c.name, x, y, w, h, c.mon.ID)
+		}
+	}
+}
+
+func TestClampToAreaLargerThanArea(t *testing.T) {
+	wa := WorkArea{X: -300, Y: -100, Width: 100, Height: 40}
+	x, y := clampToArea(-250, -90, 210, 75, -50, wa)
+	if x != wa.X || y != wa.Y {
+		t.Errorf("got (%d,%d), want top-left of area (%d,%d)", x, y, wa.X, wa.Y)
+	}
+}
+
+func TestPlacePopupOnMonitorZeroScale(t *testing.T) {
+	mon := testPrimary
+	mon.Scale = 0
+	if _, _, w, h := placePopupOnMonitor(960, 500, 140, 50, mon); w != 140 || h != 50 {
+		t.Errorf("size = %dx%d, want 140x50", w, h)
+	}
+}
@@ -33,14 +33,6 @@ type monitorInfo struct {
 	DwFlags   uint32
 }
 
-// WorkArea represents a screen's work area (excluding taskbar)
-type WorkArea struct {
-	X      int
-	Y      int
-	Width  int
-	Height int
-}
-
 // monitorFromPointPacked calls MonitorFromPoint with correctly packed POINT parameter.
 // On x64 Windows, MonitorFromPoint(POINT pt, DWORD dwFlags) expects the POINT struct
 // (8 bytes) to be packed into a single 64-bit register: low 32 bits = x, high 32 bits = y.
@@ -57,8 +49,12 @@ func monitorFromPointPacked(x, y int32, flags uintptr) uintptr {
 // This correctly handles multi-monitor setups where each monitor may have a different DPI.
 // Falls back to the cached system DPI if per-monitor API is unavailable (pre-Windows 8.1).
 func getDPIScaleForPoint(x, y int32) float64 {
-	hMonitor := monitorFromPointPacked(x, y, monitorDefaultToNearest)
+	return dpiScaleForMonitor(monitorFromPointPacked(x, y, monitorDefaultToNearest))
+}
 
+// dpiScaleForMonitor returns the effective DPI scale of hMonitor, or the system DPI scale
+// when the monitor is unknown or GetDpiForMonitor is unavailable.
+func dpiScaleForMonitor(hMonitor uintptr) float64 {
 	if hMonitor == 0 {
 		return getDPIScale() // fallback to system DPI
 	}
@@ -82,64 +78,51 @@ func getDPIScaleForPoint(x, y int32) float64 {
 	return getDPIScale()
 }
 
-// getWorkAreaAtPoint returns the work area of the monitor containing the specified point.
-// This handles multi-monitor setups correctly by finding the monitor at the given coordinates.
-func getWorkAreaAtPoint(x, y int) WorkArea {
+// defaultWorkArea is used when the monitor cannot be queried.
+var defaultWorkArea = WorkArea{X: 0, Y: 0, Width: 1920, Height: 1080}
+
+// monitorAtPoint returns the monitor containing (or nearest to) the physical point, with
+// its bounds, work area and DPI scale.
+func monitorAtPoint(x, y int) popupMonitor {
 	// Use correctly packed MonitorFromPoint call
 	hMonitor := monitorFromPointPacked(int32(x), int32(y), monitorDefaultToNearest)
-
 	if hMonitor == 0 {
-		// Fallback to default values
-		return WorkArea{X: 0, Y: 0, Width: 1920, Height: 1080}
+		return popupMonitor{Bounds: defaultWorkArea, Work: defaultWorkArea, Scale: getDPIScale()}
 	}
 
-	// Get monitor info
 	var mi monitorInfo
 	mi.CbSize = uint32(unsafe.Sizeof(mi))
 	ret, _, _ := procGetMonitorInfoW.Call(hMonitor, uintptr(unsafe.Pointer(&mi)))
 	if ret == 0 {
-		// Fallback to default values
-		return WorkArea{X: 0, Y: 0, Width: 1920, Height: 1080}
+		return popupMonitor{ID: hMonitor, Bounds: defaultWorkArea, Work: defaultWorkArea, Scale: dpiScaleForMonitor(hMonitor)}
 	}
 
-	wa := WorkArea{
-		X:      int(mi.RcWork.Left),
-		Y:      int(mi.RcWork.Top),
-		Width:  int(mi.RcWork.Right - mi.RcWork.Left),
-		Height: int(mi.RcWork.Bottom - mi.RcWork.Top),
+	return popupMonitor{
+		ID:     hMonitor,
+		Bounds: rectToArea(mi.RcMonitor),
+		Work:   rectToArea(mi.RcWork),
+		Scale:  dpiScaleForMonitor(hMonitor),
 	}
-	return wa
 }
 
-// clampToWorkArea ensures the popup position (in physical pixels) is within the
-// work area bounds. All coordinates and dimensions are in physical (virtual-screen)
-// pixels. MonitorFromPoint receives physical coords and returns physical work area,
-// so the clamp is performed entirely in a single coordinate system.
-//
-// Parameters:
-//   - popX, popY: proposed popup position in physical pixels
-//   - popWidth, popHeight: popup dimensions in physical pixels
-//   - mouseX, mouseY: mouse position in physical pixels (for MonitorFromPoint)
-//
-// Returns adjusted x, y in physical pixels.
-func clampToWorkArea(popX, popY, popWidth, popHeight, mouseX, mouseY int) (int, int) {
-	wa := getWorkAreaAtPoint(mouseX, mouseY)
-
-	// Clamp X
-	if popX < wa.X {
-		popX = wa.X
-	}
-	if popX+popWidth > wa.X+wa.Width {
-		popX = wa.X + wa.Width - popWidth
+func rectToArea(r windows.Rect) WorkArea {
+	return WorkArea{
+		X:      int(r.Left),
+		Y:      int(r.Top),
+		Width:  int(r.Right - r.Left),
+		Height: int(r.Bottom - r.Top),
 	}
+}
 
-	// Clamp Y - if above screen top, show below mouse instead
-	if popY < wa.Y {
-		popY = mouseY + 20
+// resolvePopupMonitor picks the monitor under the physical cursor. The hook point stays the
+// anchor when it lies on that monitor; otherwise it was reported in another DPI space (mixed-DPI
+// setups) and the physical cursor position is used instead, so the popup lands on the screen the
+// user is actually looking at.
+func resolvePopupMonitor(hookX, hookY int) (mon popupMonitor, anchorX, anchorY int) {
+	cx, cy := GetPhysicalCursorPos()
+	mon = monitorAtPoint(int(cx), int(cy))
+	if mon.Bounds.contains(hookX, hookY) {
+		return mon, hookX, hookY
 	}
-	if popY+popHeight > wa.Y+wa.Height {
-		popY = wa.Y + wa.Height - popHeight
-	}
-
-	return popX, popY
+	return mon, int(cx), int(cy)
 }
@@ -6,6 +6,8 @@ import (
 	"sync"
 	"time"
 
+	"chatclaw/internal/hotkey"
+	"chatclaw/internal/safego"
 	"chatclaw/internal/services/settings"
 	"chatclaw/internal/services/windows"
 
@@ -21,6 +23,12 @@ const (
 	SettingKeyTextSelectionEnabled = "enable_selection_search"
 )
 
+func init() {
+	// Selection is read by simulating the copy shortcut; a global hotkey on it would
+	// swallow the simulated keystroke and break text selection.
+	hotkey.Reserve("CmdOrCtrl+C", SettingKeyTextSelectionEnabled)
+}
+
 // TextSelectionService provides text selection popup functionality.
 // It uses mouse hook mode: detect selection drag -> copy to clipboard -> show popup.
 type TextSelectionService struct {
@@ -57,8 +65,11 @@ type TextSelectionService struct {
 	// Original app PID (used to wake original app and execute copy on button click)
 	originalAppPid int32
 
-	// Auto-hide timer
-	hideTimer *time.Timer
+	// Auto-hide timer (see autohide.go); hideTimerSeq invalidates timers that already fired
+	hideTimer    *time.Timer
+	hideTimerSeq uint64
+	// Whether the pointer is over the popup (auto-hide paused)
+	popupHovered bool
 
 	// Mouse hook watcher
 	mouseHookWatcher *MouseHookWatcher
@@ -127,6 +138,13 @@ func (s *TextSelectionService) Attach(app *application.App, mainWindow *applicat
 		s.Hide()
 	})
 
+	// Listen for pointer enter/leave on the popup (pauses/restarts the auto-hide timer).
+	// Sent as an event for the same WS_EX_NOACTIVATE reason as disable-selection-search below.
+	app.Event.On("text-selection:hover", func(e *application.CustomEvent) {
+		hovered, _ := e.Data.(bool)
+		s.setPopupHovered(hovered)
+	})
+
 	// Listen for click outside events (triggered by hook thread, executed in main thread)
 	app.Event.On("text-selection:click-outside", func(_ *application.CustomEvent) {
 		s.Hide()
@@ -219,6 +237,7 @@ func (s *TextSelectionService) IsEnabled() bool {
 // startClickOutsideWatcher starts the click outside watcher.
 func (s *TextSelectionService) startClickOutsideWatcher() {
 	s.clickOutsideWatcher = NewClickOutsideWatcher(func(x, y int32) {
+		defer safego.Recover("textselection.click_outside", s.resetPopupAfterPanic)
 		// Hide popup when clicked outside
 		// Note: must execute window operations in main thread, so trigger via event
 		s.app.Event.Emit("text-selection:click-outside", nil)
@@ -240,6 +259,7 @@ func (s *TextSelectionService) startWatcher() {
 	// Callback when drag starts (hide popup if click is not inside popup)
 	// frontAppPid: the PID of the frontmost app at the moment of mouseDown (before our app gets activated)
 	onDragStartWithPid := func(mouseX, mouseY int32, frontAppPid int32) {
+		defer safego.Recover("textselection.drag_start", s.resetPopupAfterPanic)
 		// Check if click is inside popup area
 		s.mu.RLock()
 		popX := s.popX
@@ -293,6 +313,7 @@ func (s *TextSelectionService) startWatcher() {
 	// New mode: show popup only (no clipboard copy), copy on button click.
 	// This avoids polluting the user's clipboard during text selection.
 	showPopupOnly := func(mouseX, mouseY int32, originalAppPid int32) {
+		defer safego.Recover("textselection.show_popup", s.resetPopupAfterPanic)
 		s.mu.Lock()
 		s.selectedText = ""        // Clear text - will be fetched on button click
 		s.originalAppPid = originalAppPid // Record original app PID for later copy
@@ -304,9 +325,49 @@ func (s *TextSelectionService) startWatcher() {
 	s.mu.Lock()
 	// Use showPopupOnly mode: detect drag -> show popup (no copy) -> copy on button click
 	s.mouseHookWatcher = NewMouseHookWatcher(nil, onDragStartWithPid, showPopupOnly)
+	watcher := s.mouseHookWatcher
+	app := s.app
+	s.mu.Unlock()
+
+	safego.Go("textselection.mouse_hook", func() {
+		if err := watcher.Start(); err != nil && app != nil {
+			app.Logger.Error("TextSelectionService: mouse hook watcher failed", "error", err)
+		}
+	}, s.resetWatcherAfterPanic)
+}
+
+// resetPopupAfterPanic clears popup state after a hook callback panicked. The popup window is
+// hidden on the main thread through the usual hide event.
+func (s *TextSelectionService) resetPopupAfterPanic(any) {
+	s.mu.Lock()
+	if s.hideTimer != nil {
+		s.hideTimer.Stop()
+		s.hideTimer = nil
+	}
+	s.popupActive = false
+	s.selectedText = ""
+	s.originalAppPid = 0
+	app := s.app
 	s.mu.Unlock()
 
-	go s.mouseHookWatcher.Start()
+	if app != nil {
+		app.Event.Emit("text-selection:hide", nil)
+	}
+}
+
+// resetWatcherAfterPanic drops a mouse hook watcher whose loop panicked. The service is marked
+// disabled so the next SyncFromSettings starts a fresh watcher.
+func (s *TextSelectionService) resetWatcherAfterPanic(v any) {
+	s.mu.Lock()
+	w := s.mouseHookWatcher
+	s.mouseHookWatcher = nil
+	s.enabled = false
+	s.mu.Unlock()
+
+	s.resetPopupAfterPanic(v)
+	if w != nil {
+		w.Stop()
+	}
 }
 
 // stopWatcher stops the mouse hook watcher.
@@ -472,6 +533,8 @@ func (s *TextSelectionService) showPopupAt(x, y int) {
 	s.popX = x
 	s.popY = y
 	s.popupActive = true
+	s.popupHovered = false
+	s.armHideTimerLocked()
 	popW := s.popWidth
 	popH := s.popHeight
 	s.mu.Unlock()
@@ -505,6 +568,8 @@ func (s *TextSelectionService) showPopupPhysical(physX, physY, physW, physH int)
 	s.popX = physX
 	s.popY = physY
 	s.popupActive = true
+	s.popupHovered = false
+	s.armHideTimerLocked()
 	s.mu.Unlock()
 
 	// Create/validate window (positioned off-screen initially)
@@ -633,6 +698,8 @@ func (s *TextSelectionService) showPopupOnlyAtScreenPos(screenX, screenY int) {
 		s.popX = screenX - popW/2
 		s.popY = screenY + 10
 		s.popupActive = true
+		s.popupHovered = false
+		s.armHideTimerLocked()
 		s.mu.Unlock()
 
 		s.ensurePopWindowDarwinClamped(screenX, screenY, popW, popH)
@@ -647,15 +714,19 @@ func (s *TextSelectionService) showPopupOnlyAtScreenPos(screenX, screenY int) {
 	} else {
 		// Windows: screenX/Y are physical (virtual screen) pixels.
 		// Work entirely in physical pixels and use native SetWindowPos (bypass Wails DIP).
-		scale := getDPIScaleForPoint(int32(screenX), int32(screenY))
-		physW := int(float64(s.popWidth) * scale)
-		physH := int(float64(s.popHeight) * scale)
-		offsetPx := int(10.0 * scale)
-
-		finalX := screenX - physW/2
-		finalY := screenY - physH - offsetPx
+		// Size and clamp with the DPI and work area of the monitor under the physical cursor,
+		// so mixed-DPI setups don't place the popup on (or partly over) the neighbouring screen.
+		mon, anchorX, anchorY := resolvePopupMonitor(screenX, screenY)
+		s.mu.RLock()
+		popW, popH := s.popWidth, s.popHeight
+		s.mu.RUnlock()
+		finalX, finalY, physW, physH := placePopupOnMonitor(anchorX, anchorY, popW, popH, mon)
 
-		finalX, finalY = clampToWorkArea(finalX, finalY, physW, physH, screenX, screenY)
+		app.Logger.Debug("TextSelectionService: popup monitor",
+			"monitor", mon.ID, "scale", mon.Scale,
+			"workX", mon.Work.X, "workY", mon.Work.Y, "workW", mon.Work.Width, "workH", mon.Work.Height,
+			"hookX", screenX, "hookY", screenY, "anchorX", anchorX, "anchorY", anchorY,
+			"x", finalX, "y", finalY)
 
 		s.showPopupPhysical(finalX, finalY, physW, physH)
 	}
@@ -889,10 +960,10 @@ func (s *TextSelectionService) handleButtonClick() map[string]any {
 	})
 
 	// Delay hide popup
-	go func() {
+	safego.Go("textselection.delayed_hide", func() {
 		time.Sleep(150 * time.Millisecond)
 		s.Hide()
-	}()
+	}, s.resetPopupAfterPanic)
 
 	return map[string]any{
 		"id":   actionID,
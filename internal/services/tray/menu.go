@@ -0,0 +1,155 @@
+package tray
+
+import (
+	"strings"
+	"time"
+
+	"chatclaw/internal/services/conversations"
+	"chatclaw/internal/services/i18n"
+	"chatclaw/internal/services/settings"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+const (
+	// EventOpenConversation 通知主窗口前端打开指定会话（payload: conversation_id、agent_id）
+	EventOpenConversation = "tray:open-conversation"
+	// EventNewChat 通知主窗口前端新建对话
+	EventNewChat = "tray:new-chat"
+
+	// RecentSettingKey 托盘菜单展示的最近会话条数，0 表示不展示
+	RecentSettingKey = "tray_recent_conversations"
+	// MaxRecentItems RecentSettingKey 的上限
+	MaxRecentItems = 20
+)
+
+const (
+	// menuLabelRunes 最近会话菜单项标题的最大长度
+	menuLabelRunes = 30
+	// menuRebuildDelay 合并短时间内的多次会话变更（如生成过程中反复更新），只重建一次菜单
+	menuRebuildDelay = 500 * time.Millisecond
+)
+
+// MenuActions 托盘菜单需要的窗口/悬浮球操作，由 bootstrap 注入（tray 不直接依赖窗口和悬浮球服务）
+type MenuActions struct {
+	ShowMainWindow      func()
+	FloatingBallVisible func() bool
+	ToggleFloatingBall  func()
+}
+
+// SetupMenu 设置托盘菜单依赖并立即构建菜单
+func (s *TrayService) SetupMenu(convs *conversations.ConversationsService, actions MenuActions) {
+	s.mu.Lock()
+	s.conversations = convs
+	s.actions = actions
+	s.mu.Unlock()
+
+	s.RefreshMenu()
+}
+
+// ScheduleMenuRebuild 延迟重建托盘菜单（会话变更、语言切换时调用），短时间内多次调用只重建一次
+func (s *TrayService) ScheduleMenuRebuild() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.rebuildTimer != nil {
+		s.rebuildTimer.Stop()
+	}
+	s.rebuildTimer = time.AfterFunc(menuRebuildDelay, s.RefreshMenu)
+}
+
+// RefreshMenu 立即重建托盘菜单（暴露给前端：修改 tray_recent_conversations 后调用）
+func (s *TrayService) RefreshMenu() {
+	s.mu.RLock()
+	convs := s.conversations
+	actions := s.actions
+	s.mu.RUnlock()
+
+	menu := s.app.NewMenu()
+
+	if limit := settings.GetInt(RecentSettingKey, conversations.DefaultRecentLimit); convs != nil && limit > 0 {
+		if limit > MaxRecentItems {
+			limit = MaxRecentItems
+		}
+		recent, err := convs.ListRecentConversations(limit)
+		if err != nil {
+			s.app.Logger.Warn("tray: list recent conversations failed", "error", err)
+		}
+		if len(recent) > 0 {
+			menu.Add(i18n.T("systray.recent_conversations")).SetEnabled(false)
+			for _, c := range recent {
+				id := c.ID
+				menu.Add(menuLabel(c.Name)).OnClick(func(ctx *application.Context) {
+					if err := s.OpenConversation(id); err != nil {
+						s.app.Logger.Warn("tray: open conversation failed", "conversation_id", id, "error", err)
+					}
+				})
+			}
+			menu.AddSeparator()
+		}
+	}
+
+	menu.Add(i18n.T("systray.new_chat")).OnClick(func(ctx *application.Context) {
+		s.NewChat()
+	})
+	if actions.ToggleFloatingBall != nil {
+		visible := actions.FloatingBallVisible != nil && actions.FloatingBallVisible()
+		menu.AddCheckbox(i18n.T("systray.floating_ball"), visible).OnClick(func(ctx *application.Context) {
+			actions.ToggleFloatingBall()
+		})
+	}
+	menu.AddSeparator()
+	menu.Add(i18n.T("systray.show")).OnClick(func(ctx *application.Context) {
+		s.showMainWindow()
+	})
+	menu.Add(i18n.T("systray.quit")).OnClick(func(ctx *application.Context) {
+		s.app.Quit()
+	})
+
+	s.systray.SetMenu(menu)
+}
+
+// OpenConversation 显示主窗口并打开指定会话（暴露给前端，托盘菜单点击最近会话时也会调用）
+func (s *TrayService) OpenConversation(conversationID int64) error {
+	s.mu.RLock()
+	convs := s.conversations
+	s.mu.RUnlock()
+	if convs == nil {
+		return nil
+	}
+
+	conv, err := convs.GetConversation(conversationID)
+	if err != nil {
+		return err
+	}
+	s.showMainWindow()
+	s.app.Event.Emit(EventOpenConversation, map[string]any{
+		"conversation_id": conv.ID,
+		"agent_id":        conv.AgentID,
+	})
+	return nil
+}
+
+// NewChat 显示主窗口并新建对话
+func (s *TrayService) NewChat() {
+	s.showMainWindow()
+	s.app.Event.Emit(EventNewChat, nil)
+}
+
+func (s *TrayService) showMainWindow() {
+	s.mu.RLock()
+	show := s.actions.ShowMainWindow
+	s.mu.RUnlock()
+	if show != nil {
+		show()
+	}
+}
+
+// menuLabel 截断过长的会话标题，换行替换为空格
+func menuLabel(name string) string {
+	name = strings.Join(strings.Fields(name), " ")
+	if runes := []rune(name); len(runes) > menuLabelRunes {
+		name = string(runes[:menuLabelRunes]) + "…"
+	}
+	return name
+}
@@ -2,7 +2,9 @@ package tray
 
 import (
 	"sync"
+	"time"
 
+	"chatclaw/internal/services/conversations"
 	"chatclaw/internal/services/settings"
 
 	"github.com/wailsapp/wails/v3/pkg/application"
@@ -16,6 +18,11 @@ type TrayService struct {
 	mu                    sync.RWMutex
 	trayIconEnabled       bool
 	minimizeToTrayEnabled bool
+
+	// 托盘菜单（见 menu.go）
+	conversations *conversations.ConversationsService
+	actions       MenuActions
+	rebuildTimer  *time.Timer
 }
 
 func NewTrayService(app *application.App, systray *application.SystemTray) *TrayService {
@@ -3,6 +3,7 @@ package windows
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"runtime"
 	"sort"
 	"strconv"
@@ -11,6 +12,7 @@ import (
 	"time"
 
 	"chatclaw/internal/errs"
+	"chatclaw/internal/safego"
 	"chatclaw/internal/services/settings"
 	"chatclaw/pkg/winsnap"
 
@@ -624,14 +626,14 @@ func (s *SnapService) moveToStandalone(w *application.WebviewWindow) {
 func (s *SnapService) loop(ctx context.Context) {
 	// Run step immediately. The window is created lazily inside step() only
 	// when a visible target is found, so we no longer wait for readyCh here.
-	s.step()
+	s.safeStep()
 	pollingInterval := 400 * time.Millisecond
 	for {
 		select {
 		case <-ctx.Done():
 			return
 		case <-time.After(pollingInterval):
-			s.step()
+			s.safeStep()
 			// Adjust polling interval based on low power mode
 			s.mu.Lock()
 			lowPower := s.lowPowerMode
@@ -645,6 +647,17 @@ func (s *SnapService) loop(ctx context.Context) {
 	}
 }
 
+// safeStep runs one step and recovers from a panic inside it, recording it as LastError so the
+// loop carries on with the next tick instead of crashing the app.
+func (s *SnapService) safeStep() {
+	defer safego.Recover("winsnap.step", func(v any) {
+		s.mu.Lock()
+		s.touchLocked(fmt.Sprintf("snap step panic: %v", v))
+		s.mu.Unlock()
+	})
+	s.step()
+}
+
 func (s *SnapService) step() {
 	if s.isDragGuardActive() {
 		return
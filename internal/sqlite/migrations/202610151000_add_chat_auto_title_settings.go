@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(
+		func(ctx context.Context, db *bun.DB) error {
+			sql := `
+INSERT OR IGNORE INTO settings (key, value, type, category, description, created_at, updated_at) VALUES
+('chat_auto_title', 'true', 'boolean', 'general', 'Generate a conversation title after the first exchange', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('chat_title_provider_id', '', 'string', 'general', 'Provider used for title generation (empty = conversation provider)', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('chat_title_model_id', '', 'string', 'general', 'Model used for title generation (empty = conversation model)', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP);
+`
+			if _, err := db.ExecContext(ctx, sql); err != nil {
+				return err
+			}
+			return nil
+		},
+		func(ctx context.Context, db *bun.DB) error {
+			if _, err := db.ExecContext(ctx, `
+DELETE FROM settings WHERE key IN ('chat_auto_title', 'chat_title_provider_id', 'chat_title_model_id');
+`); err != nil {
+				return err
+			}
+			return nil
+		},
+	)
+}
@@ -0,0 +1,53 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+// 202610151100_add_model_capability_flags
+// Add `supports_tools` / `supports_reasoning` to models so the chat service can skip
+// tool binding and thinking mode for models that reject them. Vision support keeps
+// living in `capabilities` ("image"). The columns start out NULL ("not set") so
+// SyncBuiltinProvidersAndModels fills builtin models once and never overwrites a
+// value the user changed; custom LLMs then default to supported so the behaviour
+// of existing setups does not change until the user says otherwise.
+func init() {
+	Migrations.MustRegister(
+		inTx(func(ctx context.Context, tx bun.Tx) error {
+			for _, stmt := range []string{
+				`ALTER TABLE models ADD COLUMN supports_tools boolean`,
+				`ALTER TABLE models ADD COLUMN supports_reasoning boolean`,
+			} {
+				if _, err := tx.ExecContext(ctx, stmt); err != nil {
+					return err
+				}
+			}
+			// Populate the flags for builtin models, then default the remaining rows.
+			if err := SyncBuiltinProvidersAndModels(ctx, tx); err != nil {
+				return err
+			}
+			for _, stmt := range []string{
+				`UPDATE models SET supports_tools = (type = 'llm') WHERE supports_tools IS NULL`,
+				`UPDATE models SET supports_reasoning = (type = 'llm') WHERE supports_reasoning IS NULL`,
+			} {
+				if _, err := tx.ExecContext(ctx, stmt); err != nil {
+					return err
+				}
+			}
+			return nil
+		}),
+		inTx(func(ctx context.Context, tx bun.Tx) error {
+			for _, stmt := range []string{
+				`ALTER TABLE models DROP COLUMN supports_reasoning`,
+				`ALTER TABLE models DROP COLUMN supports_tools`,
+			} {
+				if _, err := tx.ExecContext(ctx, stmt); err != nil {
+					return err
+				}
+			}
+			return nil
+		}),
+	)
+}
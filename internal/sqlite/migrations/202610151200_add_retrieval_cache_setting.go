@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(
+		func(ctx context.Context, db *bun.DB) error {
+			sql := `
+INSERT OR IGNORE INTO settings (key, value, type, category, description, created_at, updated_at) VALUES
+('retrieval_cache_size', '128', 'string', 'general', 'Number of knowledge base search results kept in memory (0 = disabled)', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP);
+`
+			if _, err := db.ExecContext(ctx, sql); err != nil {
+				return err
+			}
+			return nil
+		},
+		func(ctx context.Context, db *bun.DB) error {
+			if _, err := db.ExecContext(ctx, `
+DELETE FROM settings WHERE key = 'retrieval_cache_size';
+`); err != nil {
+				return err
+			}
+			return nil
+		},
+	)
+}
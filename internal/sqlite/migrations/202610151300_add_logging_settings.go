@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(
+		func(ctx context.Context, db *bun.DB) error {
+			sql := `
+INSERT OR IGNORE INTO settings (key, value, type, category, description, created_at, updated_at) VALUES
+('log_level', 'info', 'string', 'general', 'Minimum log level: error / warn / info / debug', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('log_to_file', 'true', 'boolean', 'general', 'Write application logs to a rotating file in the data directory', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP);
+`
+			if _, err := db.ExecContext(ctx, sql); err != nil {
+				return err
+			}
+			return nil
+		},
+		func(ctx context.Context, db *bun.DB) error {
+			if _, err := db.ExecContext(ctx, `
+DELETE FROM settings WHERE key IN ('log_level', 'log_to_file');
+`); err != nil {
+				return err
+			}
+			return nil
+		},
+	)
+}
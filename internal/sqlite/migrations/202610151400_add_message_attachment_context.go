@@ -0,0 +1,26 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(
+		func(ctx context.Context, db *bun.DB) error {
+			sql := `
+-- Text extracted from inline document attachments; injected into the model context for that message only
+ALTER TABLE messages ADD COLUMN attachment_context TEXT NOT NULL DEFAULT '';
+`
+			if _, err := db.ExecContext(ctx, sql); err != nil {
+				return err
+			}
+			return nil
+		},
+		func(ctx context.Context, db *bun.DB) error {
+			// SQLite doesn't support DROP COLUMN directly; the column is left in place.
+			return nil
+		},
+	)
+}
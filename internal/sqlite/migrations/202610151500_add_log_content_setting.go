@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(
+		func(ctx context.Context, db *bun.DB) error {
+			sql := `
+INSERT OR IGNORE INTO settings (key, value, type, category, description, created_at, updated_at) VALUES
+('log_include_content', 'false', 'boolean', 'general', 'Include message content and response previews in logs (API keys are always masked)', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP);
+`
+			if _, err := db.ExecContext(ctx, sql); err != nil {
+				return err
+			}
+			return nil
+		},
+		func(ctx context.Context, db *bun.DB) error {
+			if _, err := db.ExecContext(ctx, `
+DELETE FROM settings WHERE key = 'log_include_content';
+`); err != nil {
+				return err
+			}
+			return nil
+		},
+	)
+}
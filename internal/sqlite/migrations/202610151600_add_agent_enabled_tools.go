@@ -0,0 +1,22 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(
+		func(ctx context.Context, db *bun.DB) error {
+			// JSON array of built-in tool IDs the agent may use; '[]' keeps all tools enabled.
+			if _, err := db.ExecContext(ctx, `ALTER TABLE agents ADD COLUMN enabled_tools TEXT NOT NULL DEFAULT '[]'`); err != nil {
+				return err
+			}
+			return nil
+		},
+		func(ctx context.Context, db *bun.DB) error {
+			return nil
+		},
+	)
+}
@@ -0,0 +1,22 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(
+		func(ctx context.Context, db *bun.DB) error {
+			// Task mode ReAct iteration limit per conversation; 0 keeps the loop unlimited.
+			if _, err := db.ExecContext(ctx, `ALTER TABLE conversations ADD COLUMN max_iterations INTEGER NOT NULL DEFAULT 0`); err != nil {
+				return err
+			}
+			return nil
+		},
+		func(ctx context.Context, db *bun.DB) error {
+			return nil
+		},
+	)
+}
@@ -0,0 +1,33 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(
+		func(ctx context.Context, db *bun.DB) error {
+			sql := `
+-- Untruncated tool output kept for display when content had to be cut to tool_result_max_chars
+ALTER TABLE messages ADD COLUMN full_content TEXT NOT NULL DEFAULT '';
+
+INSERT OR IGNORE INTO settings (key, value, type, category, description, created_at, updated_at) VALUES
+('tool_result_max_chars', '20000', 'string', 'general', 'Maximum characters of a tool result kept in the model context (0 = unlimited)', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP);
+`
+			if _, err := db.ExecContext(ctx, sql); err != nil {
+				return err
+			}
+			return nil
+		},
+		func(ctx context.Context, db *bun.DB) error {
+			if _, err := db.ExecContext(ctx, `
+DELETE FROM settings WHERE key = 'tool_result_max_chars';
+`); err != nil {
+				return err
+			}
+			return nil
+		},
+	)
+}
@@ -0,0 +1,22 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(
+		func(ctx context.Context, db *bun.DB) error {
+			// Emit PDF/DOCX tables as separate markdown-table nodes instead of flattened text.
+			if _, err := db.ExecContext(ctx, `ALTER TABLE library ADD COLUMN preserve_tables BOOLEAN NOT NULL DEFAULT 0`); err != nil {
+				return err
+			}
+			return nil
+		},
+		func(ctx context.Context, db *bun.DB) error {
+			return nil
+		},
+	)
+}
@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(
+		func(ctx context.Context, db *bun.DB) error {
+			sql := `
+INSERT OR IGNORE INTO settings (key, value, type, category, description, created_at, updated_at) VALUES
+('default_agent_id', '', 'string', 'general', 'Agent used for new conversations when none is chosen', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('default_chat_model', '', 'string', 'general', 'Default provider::model for new conversations', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP);
+`
+			if _, err := db.ExecContext(ctx, sql); err != nil {
+				return err
+			}
+			return nil
+		},
+		func(ctx context.Context, db *bun.DB) error {
+			if _, err := db.ExecContext(ctx, `
+DELETE FROM settings WHERE key IN ('default_agent_id', 'default_chat_model');
+`); err != nil {
+				return err
+			}
+			return nil
+		},
+	)
+}
@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(
+		func(ctx context.Context, db *bun.DB) error {
+			// Per-conversation sampling overrides; each value only applies while its enable flag is set.
+			sql := `
+ALTER TABLE conversations ADD COLUMN llm_temperature float NOT NULL DEFAULT 0.5;
+ALTER TABLE conversations ADD COLUMN llm_top_p float NOT NULL DEFAULT 1.0;
+ALTER TABLE conversations ADD COLUMN llm_max_tokens integer NOT NULL DEFAULT 1000;
+ALTER TABLE conversations ADD COLUMN enable_llm_temperature boolean NOT NULL DEFAULT false;
+ALTER TABLE conversations ADD COLUMN enable_llm_top_p boolean NOT NULL DEFAULT false;
+ALTER TABLE conversations ADD COLUMN enable_llm_max_tokens boolean NOT NULL DEFAULT false;
+`
+			if _, err := db.ExecContext(ctx, sql); err != nil {
+				return err
+			}
+			return nil
+		},
+		func(ctx context.Context, db *bun.DB) error {
+			return nil
+		},
+	)
+}
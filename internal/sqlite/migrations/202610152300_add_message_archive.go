@@ -0,0 +1,49 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(
+		func(ctx context.Context, db *bun.DB) error {
+			sql := `
+-- Archived conversations are hidden from the conversation list until restored
+ALTER TABLE conversations ADD COLUMN archived_at datetime;
+
+-- Messages moved out of a conversation instead of being deleted (edit & resend, archived conversation)
+CREATE TABLE IF NOT EXISTS archived_messages (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	archived_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+	conversation_id INTEGER NOT NULL,
+	message_id INTEGER NOT NULL,              -- original messages.id, reused on restore
+	reason VARCHAR(16) NOT NULL DEFAULT '',   -- edit / conversation
+	data TEXT NOT NULL,                       -- JSON of the full messages row
+
+	FOREIGN KEY(conversation_id) REFERENCES conversations(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_archived_messages_conversation_id ON archived_messages(conversation_id, message_id);
+
+INSERT OR IGNORE INTO settings (key, value, type, category, description, created_at, updated_at) VALUES
+('chat_archive_on_edit', 'false', 'boolean', 'general', 'Archive instead of delete the messages replaced by edit & resend', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP);
+`
+			if _, err := db.ExecContext(ctx, sql); err != nil {
+				return err
+			}
+			return nil
+		},
+		func(ctx context.Context, db *bun.DB) error {
+			if _, err := db.ExecContext(ctx, `
+DROP INDEX IF EXISTS idx_archived_messages_conversation_id;
+DROP TABLE IF EXISTS archived_messages;
+DELETE FROM settings WHERE key = 'chat_archive_on_edit';
+`); err != nil {
+				return err
+			}
+			return nil
+		},
+	)
+}
@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(
+		func(ctx context.Context, db *bun.DB) error {
+			sql := `
+INSERT OR IGNORE INTO settings (key, value, type, category, description, created_at, updated_at) VALUES
+('embedding_batch_size', '0', 'string', 'general', 'Texts per embedding request (0 = use the library batch_max_chunks)', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('embedding_concurrency', '1', 'string', 'general', 'Embedding requests sent in parallel per document', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP);
+`
+			if _, err := db.ExecContext(ctx, sql); err != nil {
+				return err
+			}
+			return nil
+		},
+		func(ctx context.Context, db *bun.DB) error {
+			if _, err := db.ExecContext(ctx, `
+DELETE FROM settings WHERE key IN ('embedding_batch_size', 'embedding_concurrency');
+`); err != nil {
+				return err
+			}
+			return nil
+		},
+	)
+}
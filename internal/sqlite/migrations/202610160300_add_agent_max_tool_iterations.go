@@ -0,0 +1,23 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(
+		func(ctx context.Context, db *bun.DB) error {
+			// Agent default for the ReAct tool-iteration limit; 0 = unlimited. A conversation's
+			// max_iterations (when > 0) takes precedence.
+			if _, err := db.ExecContext(ctx, `ALTER TABLE agents ADD COLUMN max_tool_iterations INTEGER NOT NULL DEFAULT 0`); err != nil {
+				return err
+			}
+			return nil
+		},
+		func(ctx context.Context, db *bun.DB) error {
+			return nil
+		},
+	)
+}
@@ -0,0 +1,23 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(
+		func(ctx context.Context, db *bun.DB) error {
+			// Language the agent must reply in (locale code such as "zh-CN" or a language name);
+			// empty = auto, i.e. follow the user.
+			if _, err := db.ExecContext(ctx, `ALTER TABLE agents ADD COLUMN response_language TEXT NOT NULL DEFAULT ''`); err != nil {
+				return err
+			}
+			return nil
+		},
+		func(ctx context.Context, db *bun.DB) error {
+			return nil
+		},
+	)
+}
@@ -0,0 +1,44 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(
+		func(ctx context.Context, db *bun.DB) error {
+			sql := `
+-- Vectors of previously embedded texts, reused when the same text is embedded again with the same model
+CREATE TABLE IF NOT EXISTS embedding_cache (
+	model_key TEXT NOT NULL,                  -- provider_id::model_id::dimension
+	text_hash TEXT NOT NULL,                  -- hex sha256 of the text
+	vector BLOB NOT NULL,                     -- little-endian float64 values
+	created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	last_used_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+	PRIMARY KEY (model_key, text_hash)
+);
+CREATE INDEX IF NOT EXISTS idx_embedding_cache_last_used_at ON embedding_cache(last_used_at);
+
+INSERT OR IGNORE INTO settings (key, value, type, category, description, created_at, updated_at) VALUES
+('embedding_cache_max_entries', '50000', 'string', 'general', 'Maximum cached embedding vectors (0 = disable the cache)', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP);
+`
+			if _, err := db.ExecContext(ctx, sql); err != nil {
+				return err
+			}
+			return nil
+		},
+		func(ctx context.Context, db *bun.DB) error {
+			if _, err := db.ExecContext(ctx, `
+DROP INDEX IF EXISTS idx_embedding_cache_last_used_at;
+DROP TABLE IF EXISTS embedding_cache;
+DELETE FROM settings WHERE key = 'embedding_cache_max_entries';
+`); err != nil {
+				return err
+			}
+			return nil
+		},
+	)
+}
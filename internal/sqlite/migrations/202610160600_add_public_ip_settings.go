@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(
+		func(ctx context.Context, db *bun.DB) error {
+			sql := `
+INSERT OR IGNORE INTO settings (key, value, type, category, description, created_at, updated_at) VALUES
+('public_ip_mode', 'auto', 'string', 'general', 'Public IP for the ChatClaw key: auto (probe), manual (use public_ip) or disabled', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('public_ip', '', 'string', 'general', 'Public IP used when public_ip_mode is manual', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP);
+`
+			if _, err := db.ExecContext(ctx, sql); err != nil {
+				return err
+			}
+			return nil
+		},
+		func(ctx context.Context, db *bun.DB) error {
+			if _, err := db.ExecContext(ctx, `
+DELETE FROM settings WHERE key IN ('public_ip_mode', 'public_ip');
+`); err != nil {
+				return err
+			}
+			return nil
+		},
+	)
+}
@@ -0,0 +1,22 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(
+		func(ctx context.Context, db *bun.DB) error {
+			// Display-only: whether the UI renders captured reasoning; enable_thinking still decides whether it is requested.
+			if _, err := db.ExecContext(ctx, `ALTER TABLE conversations ADD COLUMN show_thinking boolean NOT NULL DEFAULT true`); err != nil {
+				return err
+			}
+			return nil
+		},
+		func(ctx context.Context, db *bun.DB) error {
+			return nil
+		},
+	)
+}
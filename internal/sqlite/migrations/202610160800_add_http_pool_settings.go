@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(
+		func(ctx context.Context, db *bun.DB) error {
+			sql := `
+INSERT OR IGNORE INTO settings (key, value, type, category, description, created_at, updated_at) VALUES
+('http_max_idle_conns', '100', 'string', 'general', 'Total idle HTTP connections kept for model provider requests', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('http_max_idle_conns_per_host', '16', 'string', 'general', 'Idle HTTP connections kept per model provider endpoint', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('http_idle_conn_timeout', '90', 'string', 'general', 'Seconds an idle model provider connection is kept alive', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP);
+`
+			if _, err := db.ExecContext(ctx, sql); err != nil {
+				return err
+			}
+			return nil
+		},
+		func(ctx context.Context, db *bun.DB) error {
+			if _, err := db.ExecContext(ctx, `
+DELETE FROM settings WHERE key IN ('http_max_idle_conns', 'http_max_idle_conns_per_host', 'http_idle_conn_timeout');
+`); err != nil {
+				return err
+			}
+			return nil
+		},
+	)
+}
@@ -0,0 +1,26 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(
+		func(ctx context.Context, db *bun.DB) error {
+			// Thinking token budget for reasoning models; 0 keeps the provider default. A
+			// conversation's thinking_budget (when > 0) takes precedence over the agent's.
+			if _, err := db.ExecContext(ctx, `ALTER TABLE agents ADD COLUMN thinking_budget INTEGER NOT NULL DEFAULT 0`); err != nil {
+				return err
+			}
+			if _, err := db.ExecContext(ctx, `ALTER TABLE conversations ADD COLUMN thinking_budget INTEGER NOT NULL DEFAULT 0`); err != nil {
+				return err
+			}
+			return nil
+		},
+		func(ctx context.Context, db *bun.DB) error {
+			return nil
+		},
+	)
+}
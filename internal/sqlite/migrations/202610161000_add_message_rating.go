@@ -0,0 +1,29 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(
+		func(ctx context.Context, db *bun.DB) error {
+			sql := `
+-- User feedback on assistant replies: rating is NULL (unrated), 1 (thumbs up) or -1 (thumbs down)
+ALTER TABLE messages ADD COLUMN rating INTEGER;
+ALTER TABLE messages ADD COLUMN feedback_note TEXT NOT NULL DEFAULT '';
+ALTER TABLE messages ADD COLUMN rated_at datetime;
+CREATE INDEX IF NOT EXISTS idx_messages_rating ON messages(rating) WHERE rating IS NOT NULL;
+`
+			if _, err := db.ExecContext(ctx, sql); err != nil {
+				return err
+			}
+			return nil
+		},
+		func(ctx context.Context, db *bun.DB) error {
+			// SQLite doesn't support DROP COLUMN directly; the columns are left in place.
+			return nil
+		},
+	)
+}
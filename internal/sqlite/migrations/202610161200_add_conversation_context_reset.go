@@ -0,0 +1,23 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(
+		func(ctx context.Context, db *bun.DB) error {
+			// Messages with id <= context_reset_at_message_id stay visible but are no longer sent
+			// to the model; 0 = the whole history is used.
+			if _, err := db.ExecContext(ctx, `ALTER TABLE conversations ADD COLUMN context_reset_at_message_id INTEGER NOT NULL DEFAULT 0`); err != nil {
+				return err
+			}
+			return nil
+		},
+		func(ctx context.Context, db *bun.DB) error {
+			return nil
+		},
+	)
+}
@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(
+		func(ctx context.Context, db *bun.DB) error {
+			sql := `
+INSERT OR IGNORE INTO settings (key, value, type, category, description, created_at, updated_at) VALUES
+('sqlite_journal_mode', 'WAL', 'string', 'general', 'SQLite journal mode: WAL (concurrent reads and writes), DELETE or TRUNCATE; applies after restart', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('sqlite_synchronous', 'NORMAL', 'string', 'general', 'SQLite synchronous level: NORMAL, FULL (safer on power loss, slower writes) or OFF (fastest, may corrupt on power loss); applies after restart', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('sqlite_busy_timeout', '5000', 'string', 'general', 'Milliseconds to wait for a locked SQLite database before failing (0-600000); applies after restart', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP);
+`
+			if _, err := db.ExecContext(ctx, sql); err != nil {
+				return err
+			}
+			return nil
+		},
+		func(ctx context.Context, db *bun.DB) error {
+			if _, err := db.ExecContext(ctx, `
+DELETE FROM settings WHERE key IN ('sqlite_journal_mode', 'sqlite_synchronous', 'sqlite_busy_timeout');
+`); err != nil {
+				return err
+			}
+			return nil
+		},
+	)
+}
@@ -0,0 +1,23 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(
+		func(ctx context.Context, db *bun.DB) error {
+			// What to do when a tool call fails: "continue" feeds the error back to the model,
+			// "abort" stops the reply.
+			if _, err := db.ExecContext(ctx, `ALTER TABLE agents ADD COLUMN on_tool_error TEXT NOT NULL DEFAULT 'continue'`); err != nil {
+				return err
+			}
+			return nil
+		},
+		func(ctx context.Context, db *bun.DB) error {
+			return nil
+		},
+	)
+}
@@ -0,0 +1,22 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(
+		func(ctx context.Context, db *bun.DB) error {
+			// Deduplicated knowledge citations of an assistant reply (JSON array, numbered for footnotes)
+			if _, err := db.ExecContext(ctx, `ALTER TABLE messages ADD COLUMN citations TEXT NOT NULL DEFAULT ''`); err != nil {
+				return err
+			}
+			return nil
+		},
+		func(ctx context.Context, db *bun.DB) error {
+			return nil
+		},
+	)
+}
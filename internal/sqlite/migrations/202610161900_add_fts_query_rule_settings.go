@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(
+		func(ctx context.Context, db *bun.DB) error {
+			sql := `
+INSERT OR IGNORE INTO settings (key, value, type, category, description, created_at, updated_at) VALUES
+('fts_stopwords', '', 'string', 'general', 'Full-text search stopwords removed from queries, one per line', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('fts_synonyms', '', 'string', 'general', 'Full-text search synonym groups, one comma-separated group per line', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP);
+`
+			if _, err := db.ExecContext(ctx, sql); err != nil {
+				return err
+			}
+			return nil
+		},
+		func(ctx context.Context, db *bun.DB) error {
+			if _, err := db.ExecContext(ctx, `
+DELETE FROM settings WHERE key IN ('fts_stopwords', 'fts_synonyms');
+`); err != nil {
+				return err
+			}
+			return nil
+		},
+	)
+}
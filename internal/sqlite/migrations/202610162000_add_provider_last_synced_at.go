@@ -0,0 +1,26 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(
+		func(ctx context.Context, db *bun.DB) error {
+			sql := `
+-- Time of the last successful model list sync from the provider's server (ChatClaw / ChatWiki); NULL if never synced
+ALTER TABLE providers ADD COLUMN last_synced_at datetime;
+`
+			if _, err := db.ExecContext(ctx, sql); err != nil {
+				return err
+			}
+			return nil
+		},
+		func(ctx context.Context, db *bun.DB) error {
+			// SQLite doesn't support DROP COLUMN directly; the column is left in place.
+			return nil
+		},
+	)
+}
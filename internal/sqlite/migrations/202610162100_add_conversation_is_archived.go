@@ -0,0 +1,37 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(
+		func(ctx context.Context, db *bun.DB) error {
+			sql := `
+-- Lightweight archive: hidden from the sidebar list but messages stay in place (still searchable).
+-- archived_at remains the marker for conversations whose messages were moved to archived_messages.
+ALTER TABLE conversations ADD COLUMN is_archived boolean NOT NULL DEFAULT false;
+CREATE INDEX IF NOT EXISTS idx_conversations_agent_archived ON conversations(agent_id, is_archived);
+
+INSERT OR IGNORE INTO settings (key, value, type, category, description, created_at, updated_at) VALUES
+('conversation_keep_recent', '0', 'string', 'general', 'Keep this many recent unpinned conversations per agent in the sidebar and archive the rest (0 = never auto-archive)', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP);
+`
+			if _, err := db.ExecContext(ctx, sql); err != nil {
+				return err
+			}
+			return nil
+		},
+		func(ctx context.Context, db *bun.DB) error {
+			// SQLite doesn't support DROP COLUMN directly; the column is left in place.
+			if _, err := db.ExecContext(ctx, `
+DROP INDEX IF EXISTS idx_conversations_agent_archived;
+DELETE FROM settings WHERE key = 'conversation_keep_recent';
+`); err != nil {
+				return err
+			}
+			return nil
+		},
+	)
+}
@@ -0,0 +1,82 @@
+package migrations
+
+import (
+	"context"
+
+	"chatclaw/internal/fts/tokenizer"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(
+		func(ctx context.Context, db *bun.DB) error {
+			sql := `
+-- Pre-tokenized conversation name (written by Go, space separated) for conv_name_fts
+ALTER TABLE conversations ADD COLUMN name_tokens text NOT NULL DEFAULT '';
+
+-- Conversation name full-text search (contentless FTS, same layout as doc_name_fts)
+CREATE VIRTUAL TABLE IF NOT EXISTS conv_name_fts USING fts5(
+	name_tokens,
+	agent_id,
+	content='',
+	tokenize='unicode61'
+);
+
+CREATE TRIGGER IF NOT EXISTS conversations_ai AFTER INSERT ON conversations BEGIN
+  INSERT INTO conv_name_fts(rowid, name_tokens, agent_id)
+    VALUES (new.id, new.name_tokens, new.agent_id);
+END;
+
+CREATE TRIGGER IF NOT EXISTS conversations_ad AFTER DELETE ON conversations BEGIN
+  INSERT INTO conv_name_fts(conv_name_fts, rowid, name_tokens, agent_id)
+    VALUES('delete', old.id, old.name_tokens, old.agent_id);
+END;
+
+CREATE TRIGGER IF NOT EXISTS conversations_au AFTER UPDATE OF name_tokens, agent_id ON conversations BEGIN
+  INSERT INTO conv_name_fts(conv_name_fts, rowid, name_tokens, agent_id)
+    VALUES('delete', old.id, old.name_tokens, old.agent_id);
+  INSERT INTO conv_name_fts(rowid, name_tokens, agent_id)
+    VALUES (new.id, new.name_tokens, new.agent_id);
+END;
+`
+			if _, err := db.ExecContext(ctx, sql); err != nil {
+				return err
+			}
+
+			// Backfill existing names; the update trigger indexes them
+			type convRow struct {
+				ID   int64  `bun:"id"`
+				Name string `bun:"name"`
+			}
+			var rows []convRow
+			if err := db.NewSelect().Table("conversations").Column("id", "name").Scan(ctx, &rows); err != nil {
+				return err
+			}
+			return db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+				for _, r := range rows {
+					tokens := tokenizer.TokenizeTitle(r.Name)
+					if tokens == "" {
+						continue
+					}
+					if _, err := tx.ExecContext(ctx, `UPDATE conversations SET name_tokens = ? WHERE id = ?`, tokens, r.ID); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		},
+		func(ctx context.Context, db *bun.DB) error {
+			// SQLite doesn't support DROP COLUMN directly; name_tokens is left in place.
+			if _, err := db.ExecContext(ctx, `
+DROP TRIGGER IF EXISTS conversations_au;
+DROP TRIGGER IF EXISTS conversations_ad;
+DROP TRIGGER IF EXISTS conversations_ai;
+DROP TABLE IF EXISTS conv_name_fts;
+`); err != nil {
+				return err
+			}
+			return nil
+		},
+	)
+}
@@ -0,0 +1,23 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(
+		func(ctx context.Context, db *bun.DB) error {
+			// Per-library segmentation language for full-text search: auto (follow fts_language), zh, en or multi.
+			if _, err := db.ExecContext(ctx, `ALTER TABLE library ADD COLUMN language TEXT NOT NULL DEFAULT 'auto'`); err != nil {
+				return err
+			}
+			return nil
+		},
+		func(ctx context.Context, db *bun.DB) error {
+			// SQLite doesn't support DROP COLUMN directly; the column is left in place.
+			return nil
+		},
+	)
+}
@@ -0,0 +1,40 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(
+		func(ctx context.Context, db *bun.DB) error {
+			// Chunk / embedding parameters a document was last processed with, compared against the
+			// current library and global config to find documents that need reprocessing.
+			sql := `
+ALTER TABLE documents ADD COLUMN processed_chunk_size INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE documents ADD COLUMN processed_chunk_overlap INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE documents ADD COLUMN processed_embedding_provider_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE documents ADD COLUMN processed_embedding_model_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE documents ADD COLUMN processed_embedding_dimension INTEGER NOT NULL DEFAULT 0;
+
+-- Documents learned before this migration are assumed to match the current config
+UPDATE documents SET
+	processed_chunk_size = COALESCE((SELECT chunk_size FROM library WHERE library.id = documents.library_id), 0),
+	processed_chunk_overlap = COALESCE((SELECT chunk_overlap FROM library WHERE library.id = documents.library_id), 0),
+	processed_embedding_provider_id = COALESCE((SELECT value FROM settings WHERE key = 'embedding_provider_id'), ''),
+	processed_embedding_model_id = COALESCE((SELECT value FROM settings WHERE key = 'embedding_model_id'), ''),
+	processed_embedding_dimension = COALESCE((SELECT CAST(value AS INTEGER) FROM settings WHERE key = 'embedding_dimension'), 0)
+WHERE parsing_status = 2 AND embedding_status = 2;
+`
+			if _, err := db.ExecContext(ctx, sql); err != nil {
+				return err
+			}
+			return nil
+		},
+		func(ctx context.Context, db *bun.DB) error {
+			// SQLite doesn't support DROP COLUMN directly; the columns are left in place.
+			return nil
+		},
+	)
+}
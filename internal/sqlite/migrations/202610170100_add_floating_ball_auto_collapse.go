@@ -0,0 +1,28 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(
+		func(ctx context.Context, db *bun.DB) error {
+			sql := `
+INSERT OR IGNORE INTO settings (key, value, type, category, description, created_at, updated_at) VALUES
+('floating_ball_auto_collapse', 'true', 'boolean', 'tools', '悬浮窗：贴边/空闲时是否自动缩小（关闭后保持完整大小）', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP);
+`
+			if _, err := db.ExecContext(ctx, sql); err != nil {
+				return err
+			}
+			return nil
+		},
+		func(ctx context.Context, db *bun.DB) error {
+			if _, err := db.ExecContext(ctx, `DELETE FROM settings WHERE key = 'floating_ball_auto_collapse'`); err != nil {
+				return err
+			}
+			return nil
+		},
+	)
+}
@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(
+		func(ctx context.Context, db *bun.DB) error {
+			sql := `
+INSERT OR IGNORE INTO settings (key, value, type, category, description, created_at, updated_at) VALUES
+('floating_ball_edge_snap_gap', '24', 'string', 'tools', '悬浮窗：距屏幕边缘多少像素内自动贴边（0~200）', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('floating_ball_rehide_delay_ms', '450', 'string', 'tools', '悬浮窗：鼠标移出后回缩延迟，毫秒（0~10000，0 为立即回缩）', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('floating_ball_idle_dock_delay_ms', '5000', 'string', 'tools', '悬浮窗：空闲自动贴边缩小延迟，毫秒（1000~600000）', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP);
+`
+			if _, err := db.ExecContext(ctx, sql); err != nil {
+				return err
+			}
+			return nil
+		},
+		func(ctx context.Context, db *bun.DB) error {
+			if _, err := db.ExecContext(ctx, `
+DELETE FROM settings WHERE key IN ('floating_ball_edge_snap_gap', 'floating_ball_rehide_delay_ms', 'floating_ball_idle_dock_delay_ms');
+`); err != nil {
+				return err
+			}
+			return nil
+		},
+	)
+}
@@ -0,0 +1,28 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(
+		func(ctx context.Context, db *bun.DB) error {
+			sql := `
+INSERT OR IGNORE INTO settings (key, value, type, category, description, created_at, updated_at) VALUES
+('floating_ball_hotkey', '', 'string', 'tools', '悬浮窗：切换显示/隐藏的全局快捷键（如 CmdOrCtrl+Shift+B，留空不启用）', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP);
+`
+			if _, err := db.ExecContext(ctx, sql); err != nil {
+				return err
+			}
+			return nil
+		},
+		func(ctx context.Context, db *bun.DB) error {
+			if _, err := db.ExecContext(ctx, `DELETE FROM settings WHERE key = 'floating_ball_hotkey'`); err != nil {
+				return err
+			}
+			return nil
+		},
+	)
+}
@@ -0,0 +1,28 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(
+		func(ctx context.Context, db *bun.DB) error {
+			sql := `
+INSERT OR IGNORE INTO settings (key, value, type, category, description, created_at, updated_at) VALUES
+('floating_ball_pin_corner', '', 'string', 'tools', '悬浮窗：固定在主屏角落（top-left/top-right/bottom-left/bottom-right，留空不固定）', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP);
+`
+			if _, err := db.ExecContext(ctx, sql); err != nil {
+				return err
+			}
+			return nil
+		},
+		func(ctx context.Context, db *bun.DB) error {
+			if _, err := db.ExecContext(ctx, `DELETE FROM settings WHERE key = 'floating_ball_pin_corner'`); err != nil {
+				return err
+			}
+			return nil
+		},
+	)
+}
@@ -0,0 +1,28 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+func init() {
+	Migrations.MustRegister(
+		func(ctx context.Context, db *bun.DB) error {
+			sql := `
+INSERT OR IGNORE INTO settings (key, value, type, category, description, created_at, updated_at) VALUES
+('floating_ball_clickthrough_when_collapsed', 'false', 'boolean', 'tools', '悬浮窗：贴边缩小时点击穿透（鼠标移上去时恢复交互并展开）', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP);
+`
+			if _, err := db.ExecContext(ctx, sql); err != nil {
+				return err
+			}
+			return nil
+		},
+		func(ctx context.Context, db *bun.DB) error {
+			if _, err := db.ExecContext(ctx, `DELETE FROM settings WHERE key = 'floating_ball_clickthrough_when_collapsed'`); err != nil {
+				return err
+			}
+			return nil
+		},
+	)
+}
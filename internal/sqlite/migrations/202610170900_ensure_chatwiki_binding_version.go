@@ -0,0 +1,33 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+// 202610170900_ensure_chatwiki_binding_version
+// Re-check chatwiki_bindings.chatwiki_version for databases where 202603171200 was
+// recorded as applied without the column (restored backups, interrupted upgrades).
+// hasColumn only selects the column name, so this does not depend on the PRAGMA
+// table_info layout the way the original migration's scan does.
+func init() {
+	Migrations.MustRegister(
+		func(ctx context.Context, db *bun.DB) error {
+			tableExists, err := hasColumn(ctx, db, "chatwiki_bindings", "id")
+			if err != nil || !tableExists {
+				return err
+			}
+			exists, err := hasColumn(ctx, db, "chatwiki_bindings", "chatwiki_version")
+			if err != nil || exists {
+				return err
+			}
+			_, err = db.ExecContext(ctx, `ALTER TABLE chatwiki_bindings ADD COLUMN chatwiki_version TEXT NOT NULL DEFAULT 'dev'`)
+			return err
+		},
+		func(ctx context.Context, db *bun.DB) error {
+			// The column belongs to 202603171200; nothing to undo here.
+			return nil
+		},
+	)
+}
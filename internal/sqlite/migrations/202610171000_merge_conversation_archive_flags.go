@@ -0,0 +1,35 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+// 202610171000_merge_conversation_archive_flags
+// conversations.archived_at is the only archive flag: conversations hidden through the
+// is_archived column of 202610162100 are folded into it and the column is dropped.
+func init() {
+	Migrations.MustRegister(
+		func(ctx context.Context, db *bun.DB) error {
+			exists, err := hasColumn(ctx, db, "conversations", "is_archived")
+			if err != nil || !exists {
+				return err
+			}
+			_, err = db.ExecContext(ctx, `
+UPDATE conversations SET archived_at = CURRENT_TIMESTAMP WHERE is_archived = 1 AND archived_at IS NULL;
+DROP INDEX IF EXISTS idx_conversations_agent_archived;
+ALTER TABLE conversations DROP COLUMN is_archived;
+CREATE INDEX IF NOT EXISTS idx_conversations_agent_archived_at ON conversations(agent_id, archived_at);
+`)
+			return err
+		},
+		func(ctx context.Context, db *bun.DB) error {
+			_, err := db.ExecContext(ctx, `
+DROP INDEX IF EXISTS idx_conversations_agent_archived_at;
+ALTER TABLE conversations ADD COLUMN is_archived boolean NOT NULL DEFAULT false;
+`)
+			return err
+		},
+	)
+}
@@ -1,5 +1,28 @@
 package migrations
 
-import "github.com/uptrace/bun/migrate"
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/migrate"
+)
 
 var Migrations = migrate.NewMigrations()
+
+// TxMigrationFunc is a migration step that runs inside a transaction.
+type TxMigrationFunc func(ctx context.Context, tx bun.Tx) error
+
+// inTx wraps a TxMigrationFunc so it can be passed to Migrations.MustRegister:
+//
+//	Migrations.MustRegister(inTx(up), inTx(down))
+//
+// Either every statement of the step is applied or none is, so a failing migration
+// cannot leave a half-altered table behind. Registration must stay in the migration
+// file itself because bun derives the migration name from the caller's file name.
+func inTx(fn TxMigrationFunc) migrate.MigrationFunc {
+	return func(ctx context.Context, db *bun.DB) error {
+		return db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+			return fn(ctx, tx)
+		})
+	}
+}
@@ -2,7 +2,6 @@ package migrations
 
 import (
 	"context"
-	"database/sql"
 	"encoding/json"
 	"fmt"
 	"strings"
@@ -16,6 +15,9 @@ import (
 type optionalModelColumn struct {
 	Name  string
 	Value func(model define.BuiltinModelConfig) (any, error)
+	// FillOnly columns are user-editable: an existing row is only written while the
+	// column is still NULL, so later syncs never overwrite the user's choice.
+	FillOnly bool
 }
 
 var modelOptionalColumns = []optionalModelColumn{
@@ -29,6 +31,20 @@ var modelOptionalColumns = []optionalModelColumn{
 			return string(capabilities), nil
 		},
 	},
+	{
+		Name: "supports_tools",
+		Value: func(model define.BuiltinModelConfig) (any, error) {
+			return model.SupportsTools(), nil
+		},
+		FillOnly: true,
+	},
+	{
+		Name: "supports_reasoning",
+		Value: func(model define.BuiltinModelConfig) (any, error) {
+			return model.SupportsReasoning, nil
+		},
+		FillOnly: true,
+	},
 }
 
 // SyncBuiltinProvidersAndModels synchronises the providers and models tables
@@ -49,12 +65,13 @@ var modelOptionalColumns = []optionalModelColumn{
 //     model_id) already exists — regardless of whether the user added it
 //     manually or a prior migration inserted it — update name/type/sort_order
 //     and mark is_builtin = true (preserving the user's enabled flag).
+//     FillOnly columns (capability flags) are only set while still NULL.
 //     If no row exists, insert it as enabled.
 //
 //   - Stale models: builtin models present in the DB (is_builtin = true) but
 //     no longer in BuiltinModels are marked is_builtin = false so they stay
 //     visible but won't be re-managed.
-func SyncBuiltinProvidersAndModels(ctx context.Context, db *bun.DB) error {
+func SyncBuiltinProvidersAndModels(ctx context.Context, db bun.IDB) error {
 	now := time.Now().UTC().Format(dateTimeFormat)
 
 	if err := syncProviders(ctx, db, now); err != nil {
@@ -66,7 +83,7 @@ func SyncBuiltinProvidersAndModels(ctx context.Context, db *bun.DB) error {
 	return nil
 }
 
-func syncProviders(ctx context.Context, db *bun.DB, now string) error {
+func syncProviders(ctx context.Context, db bun.IDB, now string) error {
 	for _, p := range define.BuiltinProviders {
 		var exists int
 		if err := db.QueryRowContext(ctx,
@@ -98,7 +115,7 @@ func syncProviders(ctx context.Context, db *bun.DB, now string) error {
 	return nil
 }
 
-func syncModels(ctx context.Context, db *bun.DB, now string) error {
+func syncModels(ctx context.Context, db bun.IDB, now string) error {
 	availableOptionalColumns, err := availableModelOptionalColumns(ctx, db)
 	if err != nil {
 		return fmt.Errorf("resolve optional model columns: %w", err)
@@ -176,7 +193,7 @@ func syncModels(ctx context.Context, db *bun.DB, now string) error {
 	return nil
 }
 
-func availableModelOptionalColumns(ctx context.Context, db *bun.DB) ([]optionalModelColumn, error) {
+func availableModelOptionalColumns(ctx context.Context, db bun.IDB) ([]optionalModelColumn, error) {
 	available := make([]optionalModelColumn, 0, len(modelOptionalColumns))
 	for _, column := range modelOptionalColumns {
 		exists, err := hasColumn(ctx, db, "models", column.Name)
@@ -199,7 +216,11 @@ func buildModelUpdateSQL(model define.BuiltinModelConfig, now string, optionalCo
 		if err != nil {
 			return "", nil, fmt.Errorf("resolve %s: %w", column.Name, err)
 		}
-		assignments = append(assignments, fmt.Sprintf("%s = ?", column.Name))
+		if column.FillOnly {
+			assignments = append(assignments, fmt.Sprintf("%s = COALESCE(%s, ?)", column.Name, column.Name))
+		} else {
+			assignments = append(assignments, fmt.Sprintf("%s = ?", column.Name))
+		}
 		args = append(args, value)
 	}
 
@@ -245,32 +266,14 @@ func questionMarks(n int) []string {
 	return placeholders
 }
 
-func hasColumn(ctx context.Context, db *bun.DB, tableName, columnName string) (bool, error) {
-	rows, err := db.QueryContext(ctx, fmt.Sprintf("PRAGMA table_info(%s)", tableName))
-	if err != nil {
-		return false, err
-	}
-	defer rows.Close()
-
-	for rows.Next() {
-		var (
-			cid       int
-			name      string
-			colType   string
-			notNull   int
-			defaultV  sql.NullString
-			primaryID int
-		)
-		if err := rows.Scan(&cid, &name, &colType, &notNull, &defaultV, &primaryID); err != nil {
-			return false, err
-		}
-		if name == columnName {
-			return true, nil
-		}
-	}
-
-	if err := rows.Err(); err != nil {
+func hasColumn(ctx context.Context, db bun.IDB, tableName, columnName string) (bool, error) {
+	// Select by name only: scanning every PRAGMA table_info column ties the
+	// result to its exact layout (cid, type, notnull, dflt_value, pk).
+	var count int
+	if err := db.QueryRowContext(ctx,
+		`SELECT COUNT(1) FROM pragma_table_info(?) WHERE name = ?`, tableName, columnName,
+	).Scan(&count); err != nil {
 		return false, err
 	}
-	return false, nil
+	return count > 0, nil
 }
@@ -0,0 +1,118 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// 连接级 PRAGMA 配置，可通过 settings 表中的以下项覆盖（启动时读取，修改后重启生效）：
+//
+// sqlite_journal_mode（WAL | DELETE | TRUNCATE，默认 WAL）
+//   - WAL：读不阻塞写、写不阻塞读，聊天写消息与文档向量化写入可以并发进行；
+//     数据库目录下会多出 -wal / -shm 文件，且不适合放在网络文件系统上。
+//   - DELETE / TRUNCATE：传统回滚日志，只有一个主库文件，但写入期间读会被阻塞，更容易出现 "database is locked"。
+//
+// sqlite_synchronous（NORMAL | FULL | OFF，默认 NORMAL）
+//   - NORMAL：WAL 模式下应用崩溃不会丢数据；断电可能丢失最后几次提交，但数据库不会损坏。
+//   - FULL：每次提交都 fsync，断电也不丢已提交数据，写入明显变慢。
+//   - OFF：完全交给操作系统刷盘，最快，但断电或系统崩溃可能损坏数据库。
+//
+// sqlite_busy_timeout（毫秒，默认 5000，范围 0..600000）
+//   - 遇到锁时最长等待多久才返回 SQLITE_BUSY（"database is locked"）。
+//     调大可减少并发写入报错，代价是锁竞争时单次写入的等待时间更长；0 表示不等待立即报错。
+//
+// 这些参数写在 DSN 中，对连接池里的每个连接都生效（直接执行 PRAGMA 只会作用于其中一个连接）。
+const (
+	JournalModeSettingKey = "sqlite_journal_mode"
+	SynchronousSettingKey = "sqlite_synchronous"
+	BusyTimeoutSettingKey = "sqlite_busy_timeout"
+)
+
+const maxBusyTimeoutMs = 600000
+
+// Pragmas 打开数据库时使用的连接参数
+type Pragmas struct {
+	JournalMode   string
+	Synchronous   string
+	BusyTimeoutMs int
+}
+
+// DefaultPragmas 返回默认连接参数
+func DefaultPragmas() Pragmas {
+	return Pragmas{
+		JournalMode:   "WAL",
+		Synchronous:   "NORMAL",
+		BusyTimeoutMs: 5000,
+	}
+}
+
+// NormalizePragmaSetting 校验并规范化 PRAGMA 设置项的值，ok 为 false 表示取值不合法
+func NormalizePragmaSetting(key, value string) (string, bool) {
+	value = strings.TrimSpace(value)
+	switch key {
+	case JournalModeSettingKey:
+		switch v := strings.ToUpper(value); v {
+		case "WAL", "DELETE", "TRUNCATE":
+			return v, true
+		}
+	case SynchronousSettingKey:
+		switch v := strings.ToUpper(value); v {
+		case "NORMAL", "FULL", "OFF":
+			return v, true
+		}
+	case BusyTimeoutSettingKey:
+		if n, err := strconv.Atoi(value); err == nil && n >= 0 && n <= maxBusyTimeoutMs {
+			return strconv.Itoa(n), true
+		}
+	}
+	return "", false
+}
+
+// dsn 生成带连接参数的 DSN（go-sqlite3 会解析并去掉 ? 之后的参数）
+func (p Pragmas) dsn(path string) string {
+	q := url.Values{}
+	q.Set("_journal_mode", p.JournalMode)
+	q.Set("_synchronous", p.Synchronous)
+	q.Set("_busy_timeout", strconv.Itoa(p.BusyTimeoutMs))
+	q.Set("_foreign_keys", "1")
+	return path + "?" + q.Encode()
+}
+
+// readPragmaSettings 读取 settings 表中的覆盖值；表不存在（首次启动）或取值非法时使用默认值
+func readPragmaSettings(ctx context.Context, sqlDB *sql.DB) Pragmas {
+	p := DefaultPragmas()
+	rows, err := sqlDB.QueryContext(ctx,
+		`SELECT key, value FROM settings WHERE key IN (?, ?, ?)`,
+		JournalModeSettingKey, SynchronousSettingKey, BusyTimeoutSettingKey)
+	if err != nil {
+		return p
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key string
+		var value sql.NullString
+		if err := rows.Scan(&key, &value); err != nil {
+			return DefaultPragmas()
+		}
+		v, ok := NormalizePragmaSetting(key, value.String)
+		if !ok {
+			continue
+		}
+		switch key {
+		case JournalModeSettingKey:
+			p.JournalMode = v
+		case SynchronousSettingKey:
+			p.Synchronous = v
+		case BusyTimeoutSettingKey:
+			p.BusyTimeoutMs, _ = strconv.Atoi(v)
+		}
+	}
+	if rows.Err() != nil {
+		return DefaultPragmas()
+	}
+	return p
+}
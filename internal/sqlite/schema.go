@@ -0,0 +1,172 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/uptrace/bun"
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// expectedColumn 描述由迁移追加到已有表上的列
+type expectedColumn struct {
+	Table      string
+	Column     string
+	Definition string // ALTER TABLE ... ADD COLUMN 使用的列定义
+	Migration  string // 负责添加该列的迁移
+}
+
+// expectedColumns lists the columns added by "ADD COLUMN" migrations. A database can
+// have such a migration recorded as applied while the column itself is missing
+// (restored backups, a file copied from another build, an interrupted upgrade); bun
+// then fails much later with an opaque scan error. verifySchema repairs those.
+// TestExpectedColumnsCoverMigrations fails when a migration adds a column that is not listed.
+var expectedColumns = []expectedColumn{
+	{"agents", "library_ids", "TEXT NOT NULL DEFAULT '[]'", "202602051000_create_chat_messages_table"},
+	{"agents", "sandbox_mode", "varchar(16) NOT NULL DEFAULT 'codex'", "202602281500_add_agent_workspace_fields"},
+	{"agents", "sandbox_network", "boolean NOT NULL DEFAULT true", "202602281500_add_agent_workspace_fields"},
+	{"agents", "work_dir", "text NOT NULL DEFAULT ''", "202602281500_add_agent_workspace_fields"},
+	{"agents", "mcp_enabled", "BOOLEAN NOT NULL DEFAULT 1", "202603091000_add_agent_mcp_fields"},
+	{"agents", "mcp_server_ids", "TEXT NOT NULL DEFAULT '[]'", "202603091000_add_agent_mcp_fields"},
+	{"agents", "mcp_server_enabled_ids", "TEXT NOT NULL DEFAULT '[]'", "202603101000_add_agent_mcp_server_enabled_ids"},
+	{"agents", "enabled_tools", "TEXT NOT NULL DEFAULT '[]'", "202610151600_add_agent_enabled_tools"},
+	{"agents", "max_tool_iterations", "INTEGER NOT NULL DEFAULT 0", "202610160300_add_agent_max_tool_iterations"},
+	{"agents", "response_language", "TEXT NOT NULL DEFAULT ''", "202610160400_add_agent_response_language"},
+	{"agents", "thinking_budget", "INTEGER NOT NULL DEFAULT 0", "202610160900_add_thinking_budget"},
+	{"agents", "on_tool_error", "TEXT NOT NULL DEFAULT 'continue'", "202610161600_add_agent_on_tool_error"},
+
+	{"conversations", "llm_provider_id", "VARCHAR(64) NOT NULL DEFAULT ''", "202602051000_create_chat_messages_table"},
+	{"conversations", "llm_model_id", "VARCHAR(128) NOT NULL DEFAULT ''", "202602051000_create_chat_messages_table"},
+	{"conversations", "library_ids", "TEXT NOT NULL DEFAULT '[]'", "202602061000_add_conversation_library_ids"},
+	{"conversations", "enable_thinking", "boolean NOT NULL DEFAULT false", "202602061400_add_conversation_thinking"},
+	{"conversations", "chat_mode", "text NOT NULL DEFAULT 'chat'", "202602271200_add_conversation_chat_mode"},
+	{"conversations", "external_id", "TEXT NOT NULL DEFAULT ''", "202603051300_add_conversation_external_id"},
+	{"conversations", "team_type", "VARCHAR(20) NOT NULL DEFAULT 'person'", "202603061200_add_conversation_team_type_and_dialogue_id"},
+	{"conversations", "dialogue_id", "INTEGER NOT NULL DEFAULT 0", "202603061200_add_conversation_team_type_and_dialogue_id"},
+	{"conversations", "team_library_id", "TEXT NOT NULL DEFAULT ''", "202603121000_add_conversation_team_library_id"},
+	{"conversations", "agent_type", "TEXT NOT NULL DEFAULT 'eino'", "202603241000_add_conversation_agent_type"},
+	{"conversations", "openclaw_session_key", "TEXT NOT NULL DEFAULT ''", "202603251100_add_conversation_openclaw_session_key"},
+	{"conversations", "max_iterations", "INTEGER NOT NULL DEFAULT 0", "202610151700_add_conversation_max_iterations"},
+	{"conversations", "llm_temperature", "float NOT NULL DEFAULT 0.5", "202610152200_add_conversation_sampling_params"},
+	{"conversations", "llm_top_p", "float NOT NULL DEFAULT 1.0", "202610152200_add_conversation_sampling_params"},
+	{"conversations", "llm_max_tokens", "integer NOT NULL DEFAULT 1000", "202610152200_add_conversation_sampling_params"},
+	{"conversations", "enable_llm_temperature", "boolean NOT NULL DEFAULT false", "202610152200_add_conversation_sampling_params"},
+	{"conversations", "enable_llm_top_p", "boolean NOT NULL DEFAULT false", "202610152200_add_conversation_sampling_params"},
+	{"conversations", "enable_llm_max_tokens", "boolean NOT NULL DEFAULT false", "202610152200_add_conversation_sampling_params"},
+	{"conversations", "archived_at", "datetime", "202610152300_add_message_archive"},
+	{"conversations", "show_thinking", "boolean NOT NULL DEFAULT true", "202610160700_add_conversation_show_thinking"},
+	{"conversations", "thinking_budget", "INTEGER NOT NULL DEFAULT 0", "202610160900_add_thinking_budget"},
+	{"conversations", "context_reset_at_message_id", "INTEGER NOT NULL DEFAULT 0", "202610161200_add_conversation_context_reset"},
+	{"conversations", "name_tokens", "text NOT NULL DEFAULT ''", "202610162200_add_conversation_name_fts"},
+
+	{"messages", "images_json", "TEXT NOT NULL DEFAULT '[]'", "202603031200_add_message_images_json"},
+	{"messages", "attachments", "TEXT NOT NULL DEFAULT '[]'", "202610171100_add_message_attachments"},
+	{"messages", "attachment_context", "TEXT NOT NULL DEFAULT ''", "202610151400_add_message_attachment_context"},
+	{"messages", "full_content", "TEXT NOT NULL DEFAULT ''", "202610151800_add_tool_result_limit"},
+	{"messages", "citations", "TEXT NOT NULL DEFAULT ''", "202610161700_add_message_citations"},
+	{"messages", "rating", "INTEGER", "202610161000_add_message_rating"},
+	{"messages", "feedback_note", "TEXT NOT NULL DEFAULT ''", "202610161000_add_message_rating"},
+	{"messages", "rated_at", "datetime", "202610161000_add_message_rating"},
+	{"archived_messages", "operation_id", "VARCHAR(64) NOT NULL DEFAULT ''", "202610160000_add_archived_message_operation"},
+
+	{"providers", "is_free", "boolean NOT NULL DEFAULT 0", "202602091200_add_provider_free_flag"},
+	{"providers", "last_synced_at", "datetime", "202610162000_add_provider_last_synced_at"},
+
+	{"models", "capabilities", `text NOT NULL DEFAULT '["text"]'`, "202603051000_add_model_capabilities"},
+	{"models", "default_use_model", "varchar(16) NOT NULL DEFAULT '0'", "202604201813_add_model_default_use_model"},
+	{"models", "supports_tools", "boolean", "202610151100_add_model_capability_flags"},
+	{"models", "supports_reasoning", "boolean", "202610151100_add_model_capability_flags"},
+
+	{"documents", "folder_id", "integer", "202603021657_add_library_folders"},
+	{"library", "batch_max_documents", "INTEGER NOT NULL DEFAULT 3", "202604071200_add_library_batch_limits"},
+	{"library", "batch_max_chunks", "INTEGER NOT NULL DEFAULT 3", "202604071200_add_library_batch_limits"},
+	{"library", "preserve_tables", "BOOLEAN NOT NULL DEFAULT 0", "202610151900_add_library_preserve_tables"},
+	{"library", "language", "TEXT NOT NULL DEFAULT 'auto'", "202610162300_add_library_language"},
+	{"documents", "processed_chunk_size", "INTEGER NOT NULL DEFAULT 0", "202610170000_add_document_processed_params"},
+	{"documents", "processed_chunk_overlap", "INTEGER NOT NULL DEFAULT 0", "202610170000_add_document_processed_params"},
+	{"documents", "processed_embedding_provider_id", "TEXT NOT NULL DEFAULT ''", "202610170000_add_document_processed_params"},
+	{"documents", "processed_embedding_model_id", "TEXT NOT NULL DEFAULT ''", "202610170000_add_document_processed_params"},
+	{"documents", "processed_embedding_dimension", "INTEGER NOT NULL DEFAULT 0", "202610170000_add_document_processed_params"},
+
+	{"channels", "agent_id", "INTEGER NOT NULL DEFAULT 0", "202603051200_add_agent_id_to_channels"},
+	{"channels", "last_sender_id", "text NOT NULL DEFAULT ''", "202603191500_add_channel_last_sender_id"},
+	{"channels", "openclaw_scope", "INTEGER NOT NULL DEFAULT 0", "202603251200_add_openclaw_scope_to_channels"},
+
+	{"openclaw_agents", "identity_emoji", "text NOT NULL DEFAULT ''", "202603231200_add_openclaw_agent_config_fields"},
+	{"openclaw_agents", "identity_theme", "text NOT NULL DEFAULT ''", "202603231200_add_openclaw_agent_config_fields"},
+	{"openclaw_agents", "group_chat_mention_patterns", "text NOT NULL DEFAULT '[]'", "202603231200_add_openclaw_agent_config_fields"},
+	{"openclaw_agents", "tools_profile", "text NOT NULL DEFAULT ''", "202603231200_add_openclaw_agent_config_fields"},
+	{"openclaw_agents", "tools_allow", "text NOT NULL DEFAULT '[]'", "202603231200_add_openclaw_agent_config_fields"},
+	{"openclaw_agents", "tools_deny", "text NOT NULL DEFAULT '[]'", "202603231200_add_openclaw_agent_config_fields"},
+	{"openclaw_agents", "heartbeat_every", "text NOT NULL DEFAULT ''", "202603231200_add_openclaw_agent_config_fields"},
+	{"openclaw_agents", "params_temperature", "text NOT NULL DEFAULT ''", "202603231200_add_openclaw_agent_config_fields"},
+	{"openclaw_agents", "params_max_tokens", "text NOT NULL DEFAULT ''", "202603231200_add_openclaw_agent_config_fields"},
+
+	{"mcp_servers", "description", "TEXT NOT NULL DEFAULT ''", "202603091100_add_mcp_server_description"},
+	{"chatwiki_bindings", "chatwiki_version", "TEXT NOT NULL DEFAULT 'dev'", "202603171200_add_chatwiki_binding_version"},
+
+	{"scheduled_tasks", "notification_platform", "text NOT NULL DEFAULT ''", "202603191200_add_notification_fields_to_scheduled_tasks"},
+	{"scheduled_tasks", "notification_channel_ids", "text NOT NULL DEFAULT '[]'", "202603191200_add_notification_fields_to_scheduled_tasks"},
+	{"scheduled_tasks", "expires_at", "datetime", "202603191830_add_expires_at_to_scheduled_tasks"},
+
+	{"skill_market_skills", "backend_id", "INTEGER", "202604231000_add_skill_market_backend_id"},
+	{"skill_market_skills", "deleted_at", "TEXT", "202604241000_add_skill_market_deleted_at"},
+}
+
+// verifySchema compares the actual table columns (PRAGMA table_info) with
+// expectedColumns and adds any column that is missing. Tables that do not exist are
+// skipped. If a column cannot be added, the returned error names the table, the
+// column and the migration that should have created it.
+func verifySchema(ctx context.Context, db *bun.DB, app *application.App) error {
+	tables := make(map[string]map[string]bool)
+	for _, c := range expectedColumns {
+		columns, ok := tables[c.Table]
+		if !ok {
+			var err error
+			columns, err = tableColumns(ctx, db, c.Table)
+			if err != nil {
+				return fmt.Errorf("sqlite: read columns of table %q: %w", c.Table, err)
+			}
+			tables[c.Table] = columns
+		}
+		if len(columns) == 0 || columns[c.Column] {
+			continue
+		}
+
+		stmt := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", c.Table, c.Column, c.Definition)
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf(
+				"sqlite: table %q is missing column %q and it could not be added automatically (%v); "+
+					"re-run migration %s or restore the database file %s",
+				c.Table, c.Column, err, c.Migration, dbPath,
+			)
+		}
+		columns[c.Column] = true
+		if app != nil {
+			app.Logger.Warn("sqlite: repaired missing column",
+				"table", c.Table, "column", c.Column, "migration", c.Migration)
+		}
+	}
+	return nil
+}
+
+// tableColumns returns the column names of a table, or an empty set if the table
+// does not exist. Only the name is selected so the result does not depend on the
+// full PRAGMA table_info layout (cid, type, notnull, ...).
+func tableColumns(ctx context.Context, db *bun.DB, table string) (map[string]bool, error) {
+	rows, err := db.QueryContext(ctx, `SELECT name FROM pragma_table_info(?)`, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		columns[name] = true
+	}
+	return columns, rows.Err()
+}
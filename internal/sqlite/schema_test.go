@@ -0,0 +1,54 @@
+package sqlite
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// addColumnPattern matches "ALTER TABLE t ADD COLUMN c" in migration sources.
+var addColumnPattern = regexp.MustCompile(`(?is)ALTER\s+TABLE\s+"?(\w+)"?\s+ADD\s+(?:COLUMN\s+)?"?(\w+)"?`)
+
+// droppedColumns are only re-added by down migrations and must not be repaired.
+var droppedColumns = map[string]bool{
+	"openclaw_agents.prompt":    true, // 202603231100_drop_openclaw_agents_prompt
+	"conversations.is_archived": true, // 202610171000_merge_conversation_archive_flags
+}
+
+func TestExpectedColumnsCoverMigrations(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("migrations", "*.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	listed := make(map[string]bool, len(expectedColumns))
+	for _, c := range expectedColumns {
+		key := c.Table + "." + c.Column
+		if listed[key] {
+			t.Errorf("expectedColumns lists %s twice", key)
+		}
+		listed[key] = true
+	}
+
+	added := make(map[string]bool)
+	for _, f := range files {
+		src, err := os.ReadFile(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, m := range addColumnPattern.FindAllStringSubmatch(string(src), -1) {
+			key := strings.ToLower(m[1]) + "." + strings.ToLower(m[2])
+			added[key] = true
+			if !listed[key] && !droppedColumns[key] {
+				t.Errorf("%s adds column %s, which is missing from expectedColumns", filepath.Base(f), key)
+			}
+		}
+	}
+	for key := range listed {
+		if !added[key] {
+			t.Errorf("expectedColumns lists %s, but no migration adds it", key)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package sqlite
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"os"
 	"path/filepath"
 	"sync"
@@ -56,32 +57,24 @@ func doInit(app *application.App) error {
 	// Enable sqlite-vec extension (CGO version requires calling before Open)
 	sqlite_vec.Auto()
 
-	sqlDB, err := sql.Open("sqlite3", dbPath)
-	if err != nil {
-		return err
-	}
-
-	// SQLite WAL 模式下读写可并发，但写必须串行，设置少量连接即可
-	sqlDB.SetMaxOpenConns(4)
-	sqlDB.SetMaxIdleConns(4)
-	sqlDB.SetConnMaxLifetime(0)
-
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	if err := sqlDB.PingContext(ctx); err != nil {
-		sqlDB.Close()
+	// 先用默认连接参数打开；settings 中配置了不同的 PRAGMA 时按配置重新打开
+	pragmas := DefaultPragmas()
+	sqlDB, err := openDB(ctx, dbPath, pragmas)
+	if err != nil {
 		return err
 	}
-
-	// 连接级 PRAGMA
-	if _, err := sqlDB.ExecContext(ctx, `PRAGMA busy_timeout = 5000;`); err != nil {
+	if p := readPragmaSettings(ctx, sqlDB); p != pragmas {
 		sqlDB.Close()
-		return err
+		pragmas = p
+		if sqlDB, err = openDB(ctx, dbPath, pragmas); err != nil {
+			return err
+		}
 	}
-	if _, err := sqlDB.ExecContext(ctx, `PRAGMA foreign_keys = ON;`); err != nil {
-		sqlDB.Close()
-		return err
+	if app != nil {
+		app.Logger.Info("sqlite pragmas", "journal_mode", pragmas.JournalMode, "synchronous", pragmas.Synchronous, "busy_timeout_ms", pragmas.BusyTimeoutMs)
 	}
 
 	// 验证 sqlite-vec 扩展已加载
@@ -91,15 +84,47 @@ func doInit(app *application.App) error {
 		return err
 	}
 
+	if err := checkFTS5Support(ctx, sqlDB); err != nil {
+		sqlDB.Close()
+		return err
+	}
+
 	bunDB := bun.NewDB(sqlDB, sqlitedialect.New())
 
-	// 运行迁移
-	migrator := migrate.NewMigrator(bunDB, migrations.Migrations)
+	// 运行迁移（已执行的版本记录在 bun_migrations 表中）
+	// 仅在迁移成功后才标记为已执行：默认行为是先标记再执行，失败的迁移会被当作已完成，
+	// 导致缺列等问题在之后的查询中才暴露出来。
+	migrator := migrate.NewMigrator(bunDB, migrations.Migrations, migrate.WithMarkAppliedOnSuccess(true))
 	if err := migrator.Init(ctx); err != nil {
 		bunDB.Close()
 		return err
 	}
-	if _, err := migrator.Migrate(ctx); err != nil {
+	// 数据库由更新版本的程序迁移过时拒绝启动，避免旧代码读到未知的表结构
+	if err := checkDatabaseVersion(ctx, migrator, app); err != nil {
+		bunDB.Close()
+		return err
+	}
+	group, err := migrator.Migrate(ctx)
+	if err != nil {
+		bunDB.Close()
+		if group != nil && len(group.Migrations) > 0 {
+			failed := group.Migrations[len(group.Migrations)-1]
+			return fmt.Errorf("sqlite: migration %s failed: %w", failed.Name, err)
+		}
+		return fmt.Errorf("sqlite: run migrations: %w", err)
+	}
+	if app != nil && group != nil && !group.IsZero() {
+		app.Logger.Info("sqlite migrations applied", "group", group.ID, "count", len(group.Migrations))
+	}
+
+	// 校验迁移追加的列是否存在，缺失时自动补齐
+	if err := verifySchema(ctx, bunDB, app); err != nil {
+		bunDB.Close()
+		return err
+	}
+
+	// 校验 FTS / 向量虚拟表及同步触发器，缺失时重建
+	if err := verifyVirtualTables(ctx, bunDB, app); err != nil {
 		bunDB.Close()
 		return err
 	}
@@ -108,6 +133,25 @@ func doInit(app *application.App) error {
 	return nil
 }
 
+// openDB 打开数据库连接池；PRAGMA 通过 DSN 下发，连接池中的每个新连接都会应用
+func openDB(ctx context.Context, path string, pragmas Pragmas) (*sql.DB, error) {
+	sqlDB, err := sql.Open("sqlite3", pragmas.dsn(path))
+	if err != nil {
+		return nil, err
+	}
+
+	// SQLite WAL 模式下读写可并发，但写必须串行，设置少量连接即可
+	sqlDB.SetMaxOpenConns(4)
+	sqlDB.SetMaxIdleConns(4)
+	sqlDB.SetConnMaxLifetime(0)
+
+	if err := sqlDB.PingContext(ctx); err != nil {
+		sqlDB.Close()
+		return nil, err
+	}
+	return sqlDB, nil
+}
+
 func Close() error {
 	if db == nil {
 		return nil
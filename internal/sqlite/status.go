@@ -0,0 +1,83 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"chatclaw/internal/sqlite/migrations"
+
+	"github.com/uptrace/bun/migrate"
+)
+
+// SchemaStatus summarizes the schema state of the open database, for diagnostics.
+type SchemaStatus struct {
+	AppliedVersion      string   `json:"applied_version"`       // newest migration recorded in bun_migrations
+	LatestVersion       string   `json:"latest_version"`        // newest migration known to this build
+	PendingMigrations   []string `json:"pending_migrations"`    // known but not applied
+	MissingColumns      []string `json:"missing_columns"`       // "table.column" from expectedColumns
+	MissingSearchTables []string `json:"missing_search_tables"` // FTS5 / vec0 tables
+}
+
+// OK reports whether the schema is fully migrated with all columns and search tables present.
+func (s *SchemaStatus) OK() bool {
+	return len(s.PendingMigrations) == 0 && len(s.MissingColumns) == 0 && len(s.MissingSearchTables) == 0
+}
+
+// CheckSchema inspects the open database without changing it (unlike the startup checks,
+// which repair what they can).
+func CheckSchema(ctx context.Context) (*SchemaStatus, error) {
+	if db == nil {
+		return nil, errors.New("sqlite: database not initialized")
+	}
+
+	status := &SchemaStatus{
+		PendingMigrations:   []string{},
+		MissingColumns:      []string{},
+		MissingSearchTables: []string{},
+	}
+
+	ms, err := migrate.NewMigrator(db, migrations.Migrations).MigrationsWithStatus(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("sqlite: read migration status: %w", err)
+	}
+	for _, m := range ms {
+		if status.LatestVersion < m.Name {
+			status.LatestVersion = m.Name
+		}
+		if !m.IsApplied() {
+			status.PendingMigrations = append(status.PendingMigrations, m.Name)
+		} else if status.AppliedVersion < m.Name {
+			status.AppliedVersion = m.Name
+		}
+	}
+
+	tables := make(map[string]map[string]bool)
+	for _, c := range expectedColumns {
+		columns, ok := tables[c.Table]
+		if !ok {
+			columns, err = tableColumns(ctx, db, c.Table)
+			if err != nil {
+				return nil, fmt.Errorf("sqlite: read columns of table %q: %w", c.Table, err)
+			}
+			tables[c.Table] = columns
+		}
+		if len(columns) > 0 && !columns[c.Column] {
+			status.MissingColumns = append(status.MissingColumns, c.Table+"."+c.Column)
+		}
+	}
+
+	existing, err := schemaObjects(ctx, db)
+	if err != nil {
+		return nil, fmt.Errorf("sqlite: read schema: %w", err)
+	}
+	for _, t := range ftsTables {
+		if !existing[t.Name] {
+			status.MissingSearchTables = append(status.MissingSearchTables, t.Name)
+		}
+	}
+	if !existing["doc_vec"] {
+		status.MissingSearchTables = append(status.MissingSearchTables, "doc_vec")
+	}
+	return status, nil
+}
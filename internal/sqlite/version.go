@@ -0,0 +1,50 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"chatclaw/internal/sqlite/migrations"
+
+	"github.com/uptrace/bun/migrate"
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// checkDatabaseVersion refuses to start when the database has migrations applied that this
+// binary does not know about and that are newer than its latest migration, i.e. the file was
+// written by a newer release. Running older code against it would fail later with confusing
+// scan errors on unknown columns. Unknown migrations older than the latest known one are only
+// logged: they come from renamed or removed migrations in past releases.
+func checkDatabaseVersion(ctx context.Context, migrator *migrate.Migrator, app *application.App) error {
+	missing, err := migrator.MissingMigrations(ctx)
+	if err != nil {
+		return fmt.Errorf("sqlite: read applied migrations: %w", err)
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	known := migrations.Migrations.Sorted()
+	latest := ""
+	if len(known) > 0 {
+		latest = known[len(known)-1].Name
+	}
+
+	var newer []string
+	for _, m := range missing {
+		if m.Name > latest {
+			newer = append(newer, m.Name)
+		} else if app != nil {
+			app.Logger.Warn("sqlite: applied migration is not registered in this build", "migration", m.Name)
+		}
+	}
+	if len(newer) > 0 {
+		return fmt.Errorf(
+			"sqlite: database %s was upgraded by a newer version of ChatClaw (unknown migrations: %s; this build knows up to %s); "+
+				"please update ChatClaw or restore a backup of the database",
+			dbPath, strings.Join(newer, ", "), latest,
+		)
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/uptrace/bun"
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// defaultVecDimension matches the doc_vec table created by the initial documents migration.
+const defaultVecDimension = 1536
+
+// ftsTable is an FTS5 index together with the statement that refills it from its source table.
+type ftsTable struct {
+	Name      string
+	CreateSQL string
+	FillSQL   string
+}
+
+var ftsTables = []ftsTable{
+	{
+		Name: "doc_fts",
+		CreateSQL: `CREATE VIRTUAL TABLE doc_fts USING fts5(
+	tokens, library_id, document_id UNINDEXED, level UNINDEXED,
+	content='', tokenize='unicode61'
+)`,
+		FillSQL: `INSERT INTO doc_fts(rowid, tokens, library_id, document_id, level)
+	SELECT id, content_tokens, library_id, document_id, level FROM document_nodes`,
+	},
+	{
+		Name: "doc_name_fts",
+		CreateSQL: `CREATE VIRTUAL TABLE doc_name_fts USING fts5(
+	name_tokens, library_id, document_id UNINDEXED,
+	content='', tokenize='unicode61'
+)`,
+		FillSQL: `INSERT INTO doc_name_fts(rowid, name_tokens, library_id, document_id)
+	SELECT id, name_tokens, library_id, id FROM documents`,
+	},
+	{
+		Name: "conv_name_fts",
+		CreateSQL: `CREATE VIRTUAL TABLE conv_name_fts USING fts5(
+	name_tokens, agent_id,
+	content='', tokenize='unicode61'
+)`,
+		FillSQL: `INSERT INTO conv_name_fts(rowid, name_tokens, agent_id)
+	SELECT id, name_tokens, agent_id FROM conversations`,
+	},
+}
+
+// indexTriggers keep doc_fts / doc_name_fts / conv_name_fts in sync with document_nodes / documents /
+// conversations (same definitions as 202602031052_create_documents_table and
+// 202610162200_add_conversation_name_fts).
+var indexTriggers = []struct {
+	Name string
+	SQL  string
+}{
+	{"doc_nodes_ai", `CREATE TRIGGER doc_nodes_ai AFTER INSERT ON document_nodes BEGIN
+  INSERT INTO doc_fts(rowid, tokens, library_id, document_id, level)
+    VALUES (new.id, new.content_tokens, new.library_id, new.document_id, new.level);
+END`},
+	{"doc_nodes_ad", `CREATE TRIGGER doc_nodes_ad AFTER DELETE ON document_nodes BEGIN
+  INSERT INTO doc_fts(doc_fts, rowid, tokens, library_id, document_id, level)
+    VALUES('delete', old.id, old.content_tokens, old.library_id, old.document_id, old.level);
+END`},
+	{"doc_nodes_au", `CREATE TRIGGER doc_nodes_au AFTER UPDATE OF content_tokens, library_id, document_id, level ON document_nodes BEGIN
+  INSERT INTO doc_fts(doc_fts, rowid, tokens, library_id, document_id, level)
+    VALUES('delete', old.id, old.content_tokens, old.library_id, old.document_id, old.level);
+  INSERT INTO doc_fts(rowid, tokens, library_id, document_id, level)
+    VALUES (new.id, new.content_tokens, new.library_id, new.document_id, new.level);
+END`},
+	{"documents_ai", `CREATE TRIGGER documents_ai AFTER INSERT ON documents BEGIN
+  INSERT INTO doc_name_fts(rowid, name_tokens, library_id, document_id)
+    VALUES (new.id, new.name_tokens, new.library_id, new.id);
+END`},
+	{"documents_ad", `CREATE TRIGGER documents_ad AFTER DELETE ON documents BEGIN
+  INSERT INTO doc_name_fts(doc_name_fts, rowid, name_tokens, library_id, document_id)
+    VALUES('delete', old.id, old.name_tokens, old.library_id, old.id);
+END`},
+	{"documents_au", `CREATE TRIGGER documents_au AFTER UPDATE OF name_tokens, library_id ON documents BEGIN
+  INSERT INTO doc_name_fts(doc_name_fts, rowid, name_tokens, library_id, document_id)
+    VALUES('delete', old.id, old.name_tokens, old.library_id, old.id);
+  INSERT INTO doc_name_fts(rowid, name_tokens, library_id, document_id)
+    VALUES (new.id, new.name_tokens, new.library_id, new.id);
+END`},
+	{"conversations_ai", `CREATE TRIGGER conversations_ai AFTER INSERT ON conversations BEGIN
+  INSERT INTO conv_name_fts(rowid, name_tokens, agent_id)
+    VALUES (new.id, new.name_tokens, new.agent_id);
+END`},
+	{"conversations_ad", `CREATE TRIGGER conversations_ad AFTER DELETE ON conversations BEGIN
+  INSERT INTO conv_name_fts(conv_name_fts, rowid, name_tokens, agent_id)
+    VALUES('delete', old.id, old.name_tokens, old.agent_id);
+END`},
+	{"conversations_au", `CREATE TRIGGER conversations_au AFTER UPDATE OF name_tokens, agent_id ON conversations BEGIN
+  INSERT INTO conv_name_fts(conv_name_fts, rowid, name_tokens, agent_id)
+    VALUES('delete', old.id, old.name_tokens, old.agent_id);
+  INSERT INTO conv_name_fts(rowid, name_tokens, agent_id)
+    VALUES (new.id, new.name_tokens, new.agent_id);
+END`},
+}
+
+// verifyVirtualTables checks the FTS5 / vec0 search tables and their sync triggers, recreating
+// whatever is missing. A missing FTS table is rebuilt from its source rows. A missing doc_vec is
+// only recreated when no embedding model is configured yet; otherwise it is left to
+// settings.RepairEmbeddingIndexIfNeeded, which also re-embeds every document.
+func verifyVirtualTables(ctx context.Context, db *bun.DB, app *application.App) error {
+	existing, err := schemaObjects(ctx, db)
+	if err != nil {
+		return fmt.Errorf("sqlite: read schema: %w", err)
+	}
+
+	for _, t := range ftsTables {
+		if existing[t.Name] {
+			continue
+		}
+		err := db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+			if _, err := tx.ExecContext(ctx, t.CreateSQL); err != nil {
+				return err
+			}
+			_, err := tx.ExecContext(ctx, t.FillSQL)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("sqlite: recreate missing search index %s: %w", t.Name, err)
+		}
+		warn(app, "sqlite: search index was missing and has been rebuilt", "table", t.Name)
+	}
+
+	for _, trg := range indexTriggers {
+		if existing[trg.Name] {
+			continue
+		}
+		if _, err := db.ExecContext(ctx, trg.SQL); err != nil {
+			return fmt.Errorf("sqlite: recreate missing trigger %s: %w", trg.Name, err)
+		}
+		warn(app, "sqlite: index trigger was missing and has been recreated", "trigger", trg.Name)
+	}
+
+	if !existing["doc_vec"] {
+		if embeddingDimension(ctx, db) > 0 {
+			warn(app, "sqlite: vector index doc_vec is missing; it will be rebuilt and documents re-embedded")
+		} else {
+			stmt := fmt.Sprintf(`CREATE VIRTUAL TABLE doc_vec USING vec0(id INTEGER PRIMARY KEY, content FLOAT[%d])`, defaultVecDimension)
+			if _, err := db.ExecContext(ctx, stmt); err != nil {
+				return fmt.Errorf("sqlite: recreate missing vector index doc_vec: %w", err)
+			}
+			warn(app, "sqlite: vector index was missing and has been recreated", "table", "doc_vec")
+		}
+	}
+	return nil
+}
+
+// schemaObjects returns the names of all tables and triggers in the main schema.
+func schemaObjects(ctx context.Context, db *bun.DB) (map[string]bool, error) {
+	rows, err := db.QueryContext(ctx, `SELECT name FROM sqlite_master WHERE type IN ('table', 'trigger')`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	names := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names[name] = true
+	}
+	return names, rows.Err()
+}
+
+// checkFTS5Support fails fast when the binary was built without the fts5 tag; otherwise the
+// first migration or search would fail with "no such module: fts5".
+func checkFTS5Support(ctx context.Context, db *sql.DB) error {
+	var used int
+	if err := db.QueryRowContext(ctx, `SELECT sqlite_compileoption_used('ENABLE_FTS5')`).Scan(&used); err != nil || used != 1 {
+		return fmt.Errorf("sqlite: this build has no FTS5 support; rebuild with -tags fts5 (see build/Taskfile.yml)")
+	}
+	return nil
+}
+
+// embeddingDimension reads the configured embedding dimension straight from the settings table
+// (the settings service is not initialized yet at this point).
+func embeddingDimension(ctx context.Context, db *bun.DB) int {
+	var value string
+	if err := db.QueryRowContext(ctx, `SELECT COALESCE(value, '') FROM settings WHERE key = 'embedding_dimension'`).Scan(&value); err != nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(strings.TrimSpace(value))
+	return n
+}
+
+func warn(app *application.App, msg string, args ...any) {
+	if app != nil {
+		app.Logger.Warn(msg, args...)
+	}
+}
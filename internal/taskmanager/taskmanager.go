@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"strings"
 	"sync"
 	"time"
 
@@ -50,6 +51,19 @@ type TaskManager struct {
 	cancel  context.CancelFunc
 	wg      sync.WaitGroup
 	stopped bool
+	closing bool // StopAccepting 后拒绝新任务，已有任务继续运行
+
+	paused   map[string]chan struct{} // Pause 的 taskKey 前缀 -> resume 时关闭
+	deferred []deferredJob            // 暂停期间提交、resume 后再入队的任务
+}
+
+// deferredJob 暂停期间提交的任务
+type deferredJob struct {
+	queue   *taskQueue
+	jobType string
+	taskKey string
+	info    *TaskInfo
+	payload []byte
 }
 
 // TaskInfo 任务元数据（用于取消）
@@ -154,6 +168,11 @@ func (tm *TaskManager) RegisterHandler(queueName, jobType string, handler func(c
 			return nil // 不重试格式错误的任务
 		}
 
+		// 所属前缀被暂停时（如数据库维护中）等到 resume 再执行
+		if err := tm.waitPaused(ctx, payload.TaskKey); err != nil {
+			return err
+		}
+
 		// 检查任务是否已取消/被替换。
 		// 注意：goqite 的任务是持久化的，应用重启后 tm.tasks 为空；
 		// 此时仍应允许运行队列里的任务，否则会出现“退出后任务不继续”的问题。
@@ -228,9 +247,21 @@ func (tm *TaskManager) Submit(queueName, jobType, taskKey, runID string, data []
 		return false
 	}
 
+	// 创建任务负载
+	payload := JobPayload{
+		TaskKey: taskKey,
+		RunID:   runID,
+		Data:    data,
+	}
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		tm.app.Logger.Error("failed to marshal job payload", "taskKey", taskKey, "error", err)
+		return false
+	}
+
 	// 注册/替换任务记录
 	tm.mu.Lock()
-	if tm.stopped {
+	if tm.stopped || tm.closing {
 		tm.mu.Unlock()
 		return false
 	}
@@ -243,33 +274,108 @@ func (tm *TaskManager) Submit(queueName, jobType, taskKey, runID string, data []
 		Cancelled: false,
 	}
 	tm.tasks[taskKey] = info
+	if tm.pausedPrefix(taskKey) != "" {
+		// 暂停期间不写队列表，resume 时再入队
+		tm.deferred = append(tm.deferred, deferredJob{queue: q, jobType: jobType, taskKey: taskKey, info: info, payload: payloadBytes})
+		tm.mu.Unlock()
+		return true
+	}
 	tm.mu.Unlock()
 
-	// 创建任务负载
-	payload := JobPayload{
-		TaskKey: taskKey,
-		RunID:   runID,
-		Data:    data,
-	}
-	payloadBytes, err := json.Marshal(payload)
-	if err != nil {
-		tm.app.Logger.Error("failed to marshal job payload", "taskKey", taskKey, "error", err)
+	return tm.enqueue(q, jobType, taskKey, info, payloadBytes)
+}
+
+// enqueue 将任务写入 goqite 队列，失败时移除任务记录
+func (tm *TaskManager) enqueue(q *taskQueue, jobType, taskKey string, info *TaskInfo, payload []byte) bool {
+	if err := jobs.Create(tm.ctx, q.queue, jobType, payload); err != nil {
+		tm.app.Logger.Error("failed to create job", "queue", q.name, "jobType", jobType, "taskKey", taskKey, "error", err)
+		tm.removeTask(taskKey, info)
 		return false
 	}
+	return true
+}
 
-	// 提交到 goqite
-	if err := jobs.Create(tm.ctx, q.queue, jobType, payloadBytes); err != nil {
-		tm.app.Logger.Error("failed to create job", "queue", queueName, "jobType", jobType, "taskKey", taskKey, "error", err)
-		// 失败时移除任务记录
-		tm.mu.Lock()
-		if cur, ok := tm.tasks[taskKey]; ok && cur == info {
-			delete(tm.tasks, taskKey)
+// Pause 暂停 taskKey 以 prefix 开头的任务，用于需要独占数据库的维护操作。
+// 已有未完成的此类任务（含排队中）时不暂停，返回其数量；否则之后 Submit 的任务先缓存，
+// 已在队列中的任务（如重启后遗留的）在执行前等待，直到调用返回的 resume。
+func (tm *TaskManager) Pause(prefix string) (resume func(), active int) {
+	if tm == nil {
+		return func() {}, 0
+	}
+
+	tm.mu.Lock()
+	for key, info := range tm.tasks {
+		if strings.HasPrefix(key, prefix) && !info.Cancelled {
+			active++
 		}
+	}
+	if active > 0 {
 		tm.mu.Unlock()
-		return false
+		return nil, active
 	}
+	if tm.paused == nil {
+		tm.paused = make(map[string]chan struct{})
+	}
+	if _, ok := tm.paused[prefix]; ok {
+		// 已被暂停：由先调用 Pause 的一方负责 resume
+		tm.mu.Unlock()
+		return func() {}, 0
+	}
+	ch := make(chan struct{})
+	tm.paused[prefix] = ch
+	tm.mu.Unlock()
 
-	return true
+	var once sync.Once
+	return func() { once.Do(func() { tm.resume(prefix, ch) }) }, 0
+}
+
+// resume 解除 Pause，并把暂停期间提交的任务入队
+func (tm *TaskManager) resume(prefix string, ch chan struct{}) {
+	tm.mu.Lock()
+	delete(tm.paused, prefix)
+	close(ch)
+	var ready []deferredJob
+	rest := tm.deferred[:0]
+	for _, job := range tm.deferred {
+		if tm.pausedPrefix(job.taskKey) == "" {
+			ready = append(ready, job)
+		} else {
+			rest = append(rest, job)
+		}
+	}
+	tm.deferred = rest
+	tm.mu.Unlock()
+
+	for _, job := range ready {
+		tm.enqueue(job.queue, job.jobType, job.taskKey, job.info, job.payload)
+	}
+}
+
+// pausedPrefix 返回覆盖 taskKey 的暂停前缀，调用方需持有 tm.mu
+func (tm *TaskManager) pausedPrefix(taskKey string) string {
+	for prefix := range tm.paused {
+		if strings.HasPrefix(taskKey, prefix) {
+			return prefix
+		}
+	}
+	return ""
+}
+
+// waitPaused 在 taskKey 所属前缀被暂停时阻塞，直到 resume 或 ctx 结束
+func (tm *TaskManager) waitPaused(ctx context.Context, taskKey string) error {
+	for {
+		tm.mu.RLock()
+		ch := tm.paused[tm.pausedPrefix(taskKey)]
+		tm.mu.RUnlock()
+		if ch == nil {
+			return nil
+		}
+		select {
+		case <-ch:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
 }
 
 // Cancel 通过 taskKey 将任务标记为已取消
@@ -291,6 +397,23 @@ func (tm *TaskManager) IsTaskRunning(taskKey string) bool {
 	return ok
 }
 
+// ActiveTaskCount 返回 taskKey 以指定前缀开头、且未取消的任务数量（含排队中的任务）
+func (tm *TaskManager) ActiveTaskCount(prefix string) int {
+	if tm == nil {
+		return 0
+	}
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+
+	count := 0
+	for key, info := range tm.tasks {
+		if strings.HasPrefix(key, prefix) && !info.Cancelled {
+			count++
+		}
+	}
+	return count
+}
+
 // GetTaskInfo 返回指定 taskKey 的任务信息
 func (tm *TaskManager) GetTaskInfo(taskKey string) *TaskInfo {
 	tm.mu.RLock()
@@ -314,6 +437,14 @@ func (tm *TaskManager) removeTask(taskKey string, info *TaskInfo) {
 	}
 }
 
+// StopAccepting 停止接收新任务（Submit 返回 false），正在运行的任务不受影响。
+// 用于退出流程：先拒绝新任务，待其他服务停止后再调用 StopNow
+func (tm *TaskManager) StopAccepting() {
+	tm.mu.Lock()
+	tm.closing = true
+	tm.mu.Unlock()
+}
+
 // Stop 优雅停止所有 job runner 并等待完成
 func (tm *TaskManager) Stop() {
 	tm.mu.Lock()